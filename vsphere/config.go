@@ -7,7 +7,7 @@ package vsphere
 import (
 	"context"
 	"crypto/sha256"
-	"encoding/json"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
@@ -15,6 +15,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -24,6 +26,7 @@ import (
 	"github.com/vmware/govmomi/session/cache"
 	"github.com/vmware/govmomi/session/keepalive"
 	"github.com/vmware/govmomi/ssoadmin"
+	"github.com/vmware/govmomi/sts"
 	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vim25"
@@ -31,10 +34,23 @@ import (
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/cassette"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/proxyhelper"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/sessioncache"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/ssohelper"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/throttle"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/tlshelper"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
+// ssoTokenLifetime is the lifetime of the tokens requested from the Security
+// Token Service. Tokens are only used to create sessions, which outlive them.
+const ssoTokenLifetime = 10 * time.Minute
+
+// restSessionHeader is the header that carries the session ID of REST API
+// requests.
+const restSessionHeader = "vmware-api-session-id"
+
 // Client is the client connection manager for the vSphere provider. It
 // holds the connections to the various API endpoints we need to interface
 // with, such as the VMODL API through govmomi, and the REST SDK through
@@ -58,6 +74,18 @@ type Client struct {
 
 	// client timeout for certain operations
 	timeout time.Duration
+
+	// The provider configuration that the client was created from.
+	config *Config
+
+	// Connections to other vCenter Server instances, keyed by server, user
+	// name, and a fingerprint of the credentials. See VCenterClient.
+	vcenters   map[string]*Client
+	vcentersMu sync.Mutex
+
+	// The client of the provider connection. Only set on clients of other
+	// vCenter Server instances.
+	origin *Client
 }
 
 // TagsManager returns the embedded tags manager used for tags, after determining
@@ -104,6 +132,103 @@ func (c *Client) SSOAdminClient(ctx context.Context) (*ssoadmin.Client, error) {
 	return c.ssoClient.Client(ctx)
 }
 
+// vcenterClientKey returns the key of a client of another vCenter Server
+// instance in the cache of the provider. Besides the server and user name, it
+// contains a fingerprint of the password and the certificate verification
+// options, so that a client is not reused for a connection that would have
+// failed to log in or to verify the certificate of the instance.
+func vcenterClientKey(server, username, password, thumbprint string, insecure bool) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{password, thumbprint, fmt.Sprintf("%t", insecure)}, "\x00")))
+	return fmt.Sprintf("%s#%s#%x", server, username, sum[:16])
+}
+
+// VCenterClient returns a client for another vCenter Server instance, such as
+// the target of a cross vCenter Server migration. The client has SOAP, REST,
+// and policy based management connections, and is cached for the lifetime of
+// the provider. When thumbprint is set, the certificate of the instance must
+// match it, and is trusted even if it is not signed by a trusted certificate
+// authority.
+func (c *Client) VCenterClient(ctx context.Context, server, username, password, thumbprint string) (*Client, error) {
+	origin := c.originClient()
+	origin.vcentersMu.Lock()
+	defer origin.vcentersMu.Unlock()
+
+	key := vcenterClientKey(server, username, password, thumbprint, origin.config.InsecureFlag)
+	if client, ok := origin.vcenters[key]; ok {
+		return client, nil
+	}
+
+	cfg := *origin.config
+	cfg.VSphereServer = server
+	cfg.User = username
+	cfg.Password = password
+	cfg.Thumbprint = thumbprint
+	cfg.SSOToken = ""
+	cfg.SSOOAuthToken = ""
+	cfg.SSOCertificate = ""
+	cfg.SSOPrivateKey = ""
+	u, err := cfg.vimURL()
+	if err != nil {
+		return nil, fmt.Errorf("error generating SOAP endpoint url: %s", err)
+	}
+	log.Printf("[DEBUG] Creating new SOAP API session on endpoint %s", server)
+	vimClient, err := cfg.newClientWithKeepAlive(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to vCenter Server %s: %s", server, err)
+	}
+	if err := viapi.ValidateVirtualCenter(vimClient); err != nil {
+		return nil, fmt.Errorf("%s: %s", server, err)
+	}
+
+	client := &Client{
+		vimClient: vimClient,
+		ssoClient: ssohelper.New(vimClient.Client, u.User),
+		timeout:   origin.timeout,
+		config:    &cfg,
+		origin:    origin,
+	}
+	if isEligibleRestEndpoint(vimClient) {
+		// The REST client shares the transport of the SOAP client, so that the
+		// certificate verification options are honored.
+		client.restClient = rest.NewClient(vimClient.Client)
+		if err := client.restClient.Login(ctx, u.User); err != nil {
+			return nil, fmt.Errorf("error connecting to the REST API of vCenter Server %s: %s", server, err)
+		}
+	}
+	client.pbmClient, err = pbm.NewClient(ctx, vimClient.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.throttleClient(client, u, func(ctx context.Context, rc *rest.Client) error {
+		return rc.Login(ctx, u.User)
+	})
+
+	if origin.vcenters == nil {
+		origin.vcenters = make(map[string]*Client)
+	}
+	origin.vcenters[key] = client
+	return client, nil
+}
+
+// TLSOptions returns the certificate verification and proxy options of the
+// provider, for the connections that are not made through the API clients,
+// such as the download of remote OVF and OVA files.
+func (c *Client) TLSOptions() *tlshelper.Options {
+	if c.config == nil {
+		return nil
+	}
+	return c.config.tlsOptions()
+}
+
+// originClient returns the client of the provider connection.
+func (c *Client) originClient() *Client {
+	if c.origin != nil {
+		return c.origin
+	}
+	return c
+}
+
 // Config holds the provider configuration, and delivers a populated
 // VSphereClient based off the contained settings.
 type Config struct {
@@ -119,6 +244,57 @@ type Config struct {
 	RestSessionPath string
 	KeepAlive       int
 	APITimeout      time.Duration
+	CassettePath    string
+	CassetteMode    string
+
+	// The SHA-1 or SHA-256 thumbprint of the certificate of the server. When
+	// set, the certificate must match it, and is trusted even if it is not
+	// signed by a trusted certificate authority.
+	Thumbprint string
+
+	// Certificate authorities to verify server certificates with instead of
+	// the system certificate authorities. CAFile is a list of paths.
+	CAFile string
+	CAPEM  string
+
+	// Thumbprints of the certificates of ESXi hosts, keyed by host name or
+	// address. Used for the connections made directly to hosts, such as for
+	// disk uploads and downloads, and when vsphere_server is a host.
+	HostThumbprints map[string]string
+
+	// The proxy that connections are made through, and the hosts that are
+	// connected to directly. See the proxyhelper package.
+	ProxyURL string
+	NoProxy  string
+
+	// Limits and retries applied to the API requests of all clients. See the
+	// throttle package.
+	MaxConcurrentRequests int
+	RequestsPerSecond     float64
+	MaxRetries            int
+
+	// The encryption key of the saved sessions, or whether the key is stored
+	// in the OS keyring. See the sessioncache package.
+	SessionEncryptionKey     string
+	SessionEncryptionKeyring bool
+
+	// SSO token authentication. When any of these are set, the provider logs
+	// in with a SAML token instead of User and Password. See ssoSigner.
+	SSOToken       string
+	SSOOAuthToken  string
+	SSOCertificate string
+	SSOPrivateKey  string
+
+	// The recorder for the API cassette, if one is configured. Set up by
+	// EnableDebug.
+	recorder *cassette.Recorder
+
+	// The throttle shared by the clients created from the configuration. Set
+	// up by throttleClient.
+	throttle *throttle.Throttle
+
+	// The cache of the saved sessions. Set up by sessionCache.
+	cache *sessioncache.Cache
 }
 
 // NewConfig returns a new Config from a supplied ResourceData.
@@ -152,11 +328,76 @@ func NewConfig(d *schema.ResourceData) (*Config, error) {
 		RestSessionPath: d.Get("rest_session_path").(string),
 		KeepAlive:       d.Get("vim_keep_alive").(int),
 		APITimeout:      timeout,
+		CassettePath:    d.Get("client_cassette_path").(string),
+		CassetteMode:    d.Get("client_cassette_mode").(string),
+		SSOToken:        d.Get("sso_token").(string),
+		SSOOAuthToken:   d.Get("sso_oauth_token").(string),
+		SSOCertificate:  d.Get("sso_certificate").(string),
+		SSOPrivateKey:   d.Get("sso_private_key").(string),
+		Thumbprint:      d.Get("vcenter_thumbprint").(string),
+		CAFile:          d.Get("ca_file").(string),
+		CAPEM:           d.Get("ca_pem").(string),
+		ProxyURL:        d.Get("proxy_url").(string),
+		NoProxy:         d.Get("no_proxy").(string),
+
+		MaxConcurrentRequests: d.Get("api_max_concurrent_requests").(int),
+		RequestsPerSecond:     d.Get("api_requests_per_second").(float64),
+		MaxRetries:            d.Get("api_max_retries").(int),
+
+		SessionEncryptionKey:     d.Get("session_encryption_key").(string),
+		SessionEncryptionKeyring: d.Get("session_encryption_keyring").(bool),
+	}
+	if v := d.Get("host_thumbprints").(map[string]interface{}); len(v) > 0 {
+		c.HostThumbprints = make(map[string]string)
+		for host, thumbprint := range v {
+			c.HostThumbprints[host] = thumbprint.(string)
+		}
+	}
+
+	if c.CassettePath != "" && c.CassetteMode == "" {
+		return nil, fmt.Errorf("client_cassette_mode must be set when client_cassette_path is set")
+	}
+
+	if err := c.validateAuth(); err != nil {
+		return nil, err
+	}
+
+	if err := c.tlsOptions().Proxy.Validate(); err != nil {
+		return nil, err
+	}
+
+	if c.SessionEncryptionKey != "" {
+		if _, err := sessioncache.ParseKey(c.SessionEncryptionKey); err != nil {
+			return nil, err
+		}
 	}
 
 	return c, nil
 }
 
+// tlsOptions returns the certificate verification and proxy options of the
+// provider connections. The thumbprint of vsphere_server is pinned along with
+// the thumbprints of the ESXi hosts.
+func (c *Config) tlsOptions() *tlshelper.Options {
+	o := &tlshelper.Options{
+		Insecure:    c.InsecureFlag,
+		CAFile:      c.CAFile,
+		CAPEM:       c.CAPEM,
+		Thumbprints: make(map[string]string),
+		Proxy: &proxyhelper.Options{
+			URL:     c.ProxyURL,
+			NoProxy: c.NoProxy,
+		},
+	}
+	for host, thumbprint := range c.HostThumbprints {
+		o.Thumbprints[host] = thumbprint
+	}
+	if c.Thumbprint != "" {
+		o.Thumbprints[c.VSphereServer] = c.Thumbprint
+	}
+	return o
+}
+
 // vimURL returns a URL to pass to the VIM SOAP client.
 func (c *Config) vimURL() (*url.URL, error) {
 	u, err := url.Parse("https://" + c.VSphereServer + "/sdk")
@@ -164,11 +405,90 @@ func (c *Config) vimURL() (*url.URL, error) {
 		return nil, fmt.Errorf("error parse url: %s", err)
 	}
 
-	u.User = url.UserPassword(c.User, c.Password)
+	if c.User != "" || !c.ssoTokenAuth() {
+		u.User = url.UserPassword(c.User, c.Password)
+	}
 
 	return u, nil
 }
 
+// validateAuth checks that the configuration has exactly one way of
+// authenticating to vSphere.
+func (c *Config) validateAuth() error {
+	if c.SSOToken != "" && c.SSOOAuthToken != "" {
+		return fmt.Errorf("only one of sso_token or sso_oauth_token can be set")
+	}
+	if (c.SSOCertificate == "") != (c.SSOPrivateKey == "") {
+		return fmt.Errorf("sso_certificate and sso_private_key must be set together")
+	}
+	if !c.ssoTokenAuth() && (c.User == "" || c.Password == "") {
+		return fmt.Errorf("user and password must be provided unless SSO token authentication is configured")
+	}
+	return nil
+}
+
+// ssoTokenAuth returns true if the provider logs in with a SAML token instead
+// of a user name and password.
+func (c *Config) ssoTokenAuth() bool {
+	return c.SSOToken != "" || c.SSOOAuthToken != "" || c.SSOCertificate != ""
+}
+
+// ssoSessionKey returns a fingerprint of the SSO token and certificate that
+// the provider logs in with, to be added to the keys of the saved sessions.
+// Sessions created from tokens are otherwise keyed off the URL alone, which
+// does not include a user name, so sessions of different identities would
+// share the same file. An empty string is returned when logging in with a user
+// name and password, so that the keys stay compatible with govc.
+func (c *Config) ssoSessionKey() string {
+	if !c.ssoTokenAuth() {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{c.SSOToken, c.SSOOAuthToken, c.SSOCertificate}, "\x00")))
+	return fmt.Sprintf("#sso=%x", sum[:16])
+}
+
+// ssoSigner returns a signer holding the SAML token to log in with. The token
+// is, in order of precedence:
+//
+// * The token in SSOToken.
+// * The SAML token exchanged for the OAuth token in SSOOAuthToken.
+// * A token issued by the Security Token Service of vCenter Server.
+//
+// When SSOCertificate is set, the token is a holder-of-key token for the
+// certificate, and requests are signed with its key. Tokens from the Security
+// Token Service are issued for the solution user of the certificate, or for
+// User if it is also set. Otherwise, the token is a bearer token.
+func (c *Config) ssoSigner(ctx context.Context, vc *vim25.Client) (*sts.Signer, error) {
+	var cert *tls.Certificate
+	if c.SSOCertificate != "" {
+		pair, err := tls.X509KeyPair([]byte(c.SSOCertificate), []byte(c.SSOPrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("error loading sso_certificate: %s", err)
+		}
+		cert = &pair
+	}
+
+	var token string
+	var err error
+	switch {
+	case c.SSOToken != "":
+		token, err = ssohelper.ParseToken(c.SSOToken)
+	case c.SSOOAuthToken != "":
+		token, err = ssohelper.ExchangeOAuthToken(ctx, vc, c.SSOOAuthToken)
+	default:
+		var userinfo *url.Userinfo
+		if c.User != "" {
+			userinfo = url.UserPassword(c.User, c.Password)
+		}
+		log.Printf("[DEBUG] Requesting SSO token for %s", c.VSphereServer)
+		return ssohelper.IssueToken(ctx, vc, userinfo, cert, ssoTokenLifetime)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading SSO token: %s", err)
+	}
+	return &sts.Signer{Token: token, Certificate: cert}, nil
+}
+
 // Client returns a new client for accessing VMWare vSphere.
 func (c *Config) Client() (*Client, error) {
 	client := new(Client)
@@ -183,6 +503,10 @@ func (c *Config) Client() (*Client, error) {
 		return nil, fmt.Errorf("error setting up client debug: %s", err)
 	}
 
+	if err := c.pruneSessions(); err != nil {
+		return nil, err
+	}
+
 	// Set up the VIM/govmomi client connection, or load a previous session
 	client.vimClient, err = c.SavedVimSessionOrNew(u)
 	if err != nil {
@@ -192,7 +516,14 @@ func (c *Config) Client() (*Client, error) {
 	// Prepare the SSO admin client wrapper. This does not authenticate yet; the
 	// handshake is deferred until an SSO resource first needs it, so connections
 	// without SSO permission are not affected.
-	client.ssoClient = ssohelper.New(client.vimClient.Client, u.User)
+	if c.ssoTokenAuth() {
+		vc := client.vimClient.Client
+		client.ssoClient = ssohelper.NewWithToken(vc, func(ctx context.Context) (*sts.Signer, error) {
+			return c.ssoSigner(ctx, vc)
+		})
+	} else {
+		client.ssoClient = ssohelper.New(client.vimClient.Client, u.User)
+	}
 
 	log.Printf("[DEBUG] VMWare vSphere Client configured for URL: %s", c.VSphereServer)
 
@@ -204,6 +535,16 @@ func (c *Config) Client() (*Client, error) {
 		if err != nil {
 			return nil, err
 		}
+		if c.ssoTokenAuth() {
+			vc := client.vimClient.Client
+			s.LoginREST = func(ctx context.Context, rc *rest.Client) error {
+				signer, err := c.ssoSigner(ctx, vc)
+				if err != nil {
+					return err
+				}
+				return ssohelper.LoginRESTByToken(ctx, rc, signer)
+			}
+		}
 		client.restClient, err = c.SavedRestSessionOrNew(s)
 		if err != nil {
 			return nil, err
@@ -238,15 +579,23 @@ func (c *Config) Client() (*Client, error) {
 		log.Printf("[DEBUG] Connected endpoint does not support vSAN service")
 	}
 
+	c.throttleClient(client, u, func(ctx context.Context, rc *rest.Client) error {
+		if s.LoginREST != nil {
+			return s.LoginREST(ctx, rc)
+		}
+		return rc.Login(ctx, s.URL.User)
+	})
+
 	// Done, save sessions if we need to and return
 	if err := c.SaveVimClient(client.vimClient); err != nil {
 		return nil, fmt.Errorf("error persisting SOAP session to disk: %s", err)
 	}
-	if err := c.SaveRestClient(client.restClient, s); err != nil {
+	if err := c.SaveRestClient(client.restClient); err != nil {
 		return nil, fmt.Errorf("error persisting REST session to disk: %s", err)
 	}
 
 	client.timeout = c.APITimeout
+	client.config = c
 
 	return client, nil
 }
@@ -256,7 +605,9 @@ func (c *Config) restURL() (*cache.Session, error) {
 	if err != nil {
 		return nil, err
 	}
-	u.User = url.UserPassword(c.User, c.Password)
+	if c.User != "" || !c.ssoTokenAuth() {
+		u.User = url.UserPassword(c.User, c.Password)
+	}
 	s := &cache.Session{
 		URL:      u,
 		Insecure: c.InsecureFlag,
@@ -269,12 +620,18 @@ func (c *Config) SavedRestSessionOrNew(s *cache.Session) (*rest.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
 	defer cancel()
 
-	s.DirREST = c.RestSessionPath
-	s.Passthrough = !c.Persist
-	restClient := new(rest.Client)
-	err := s.Login(ctx, restClient, nil)
+	// Sessions are loaded and saved in the session cache of the provider, and
+	// not by govmomi, which saves them unencrypted.
+	s.Passthrough = true
+	restClient, err := c.LoadRestClient(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error trying to load vSphere REST session from disk: %s", err)
+	}
+	if restClient == nil {
+		restClient = new(rest.Client)
+		if err := s.Login(ctx, restClient, c.configureSOAP); err != nil {
+			return nil, err
+		}
 	}
 	// Setup keepalive functionality
 	var f func() error
@@ -286,9 +643,68 @@ func (c *Config) SavedRestSessionOrNew(s *cache.Session) (*rest.Client, error) {
 	return restClient, nil
 }
 
-// EnableDebug turns on govmomi API operation logging, if appropriate settings
-// are set on the provider.
+// LoadRestClient loads a saved vSphere REST API session from disk, previously
+// saved by SaveRestClient, checking it for validity before returning it. A nil
+// client means that the session is no longer valid and should be created from
+// scratch.
+func (c *Config) LoadRestClient(ctx context.Context) (*rest.Client, error) {
+	if !c.Persist {
+		return nil, nil
+	}
+
+	sessions, err := c.sessionCache()
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.restSessionFile()
+	if err != nil {
+		return nil, fmt.Errorf("error determining REST session filename: %s", err)
+	}
+	log.Printf("[DEBUG] Attempting to locate REST client session data in %q", p)
+	client := new(rest.Client)
+	ok, err := sessions.Load(p, client)
+	if err != nil {
+		return nil, fmt.Errorf("error loading REST client session: %s", err)
+	}
+	if !ok || !client.Valid() {
+		log.Println("[DEBUG] Cached REST client session data not valid, new session necessary")
+		return nil, nil
+	}
+	if err := c.configureSOAP(client.Client); err != nil {
+		return nil, err
+	}
+
+	session, err := client.Session(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving current REST session: %s", err)
+	}
+	if session == nil {
+		log.Println("[DEBUG] Cached REST client session expired, new session necessary")
+		return nil, sessions.Remove(p)
+	}
+
+	log.Println("[DEBUG] Cached REST client session loaded successfully")
+	return client, nil
+}
+
+// configureSOAP applies the certificate verification and proxy options and
+// the API cassette recorder to a SOAP client.
+func (c *Config) configureSOAP(sc *soap.Client) error {
+	if err := c.tlsOptions().ConfigureSOAP(sc); err != nil {
+		return err
+	}
+	c.registerCassette(sc)
+	return nil
+}
+
+// EnableDebug turns on govmomi API operation logging and the API cassette
+// recorder, if appropriate settings are set on the provider.
 func (c *Config) EnableDebug() error {
+	if err := c.enableCassette(); err != nil {
+		log.Printf("[ERROR] Client cassette setup failed: %v", err)
+		return err
+	}
+
 	if !c.Debug {
 		return nil
 	}
@@ -325,13 +741,40 @@ func (c *Config) EnableDebug() error {
 	return nil
 }
 
+// enableCassette opens a new episode in the API cassette, if one is
+// configured. Saved sessions are not used while a cassette is active, as
+// their validation requests would not be part of the recording.
+func (c *Config) enableCassette() error {
+	if c.CassettePath == "" {
+		return nil
+	}
+
+	r, err := cassette.New(c.CassettePath, cassette.Mode(c.CassetteMode))
+	if err != nil {
+		return err
+	}
+	c.recorder = r
+	c.Persist = false
+	return nil
+}
+
+// registerCassette installs the API cassette recorder on the transport used by
+// a SOAP client and the service clients derived from it.
+func (c *Config) registerCassette(sc *soap.Client) {
+	if c.recorder != nil {
+		c.recorder.Register(sc.DefaultTransport())
+	}
+}
+
 func (c *Config) vimURLWithoutPassword() (*url.URL, error) {
 	u, err := c.vimURL()
 	if err != nil {
 		return nil, err
 	}
 	withoutCredentials := u
-	withoutCredentials.User = url.User(u.User.Username())
+	if u.User != nil {
+		withoutCredentials.User = url.User(u.User.Username())
+	}
 	return withoutCredentials, nil
 }
 
@@ -346,21 +789,35 @@ func (c *Config) sessionFile() (string, error) {
 		return "", err
 	}
 
-	// Key session file off of full URI and insecure setting.
+	// Key session file off of full URI and insecure setting, and the SSO
+	// identity when logging in with a token.
 	// Hash key to get a predictable, canonical format.
-	key := fmt.Sprintf("%s#insecure=%t", u.String(), c.InsecureFlag)
+	key := fmt.Sprintf("%s#insecure=%t%s", u.String(), c.InsecureFlag, c.ssoSessionKey())
 	name := fmt.Sprintf("%064x", sha256.Sum256([]byte(key)))
 	return name, nil
 }
 
-// restSessionFile is takes the session file name generated by sessionFile and
-// then prefixes the REST client session path to it.
+// restSessionFile generates a unique hash of the REST endpoint to use as the
+// session file name, and then prefixes the REST client session path to it.
+//
+// As with sessionFile, this is the same logic used as part of govmomi.
 func (c *Config) restSessionFile() (string, error) {
-	p, err := c.sessionFile()
+	s, err := c.restURL()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(c.RestSessionPath, p), nil
+	u := s.Endpoint()
+	u.Path = rest.Path
+
+	key := fmt.Sprintf("%s#insecure=%t%s", u.String(), c.InsecureFlag, c.ssoSessionKey())
+	name := fmt.Sprintf("%064x", sha256.Sum256([]byte(key)))
+
+	// Default to the REST session path of govmomi.
+	dir := c.RestSessionPath
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".govmomi", "rest_sessions")
+	}
+	return filepath.Join(dir, name), nil
 }
 
 // vimSessionFile is takes the session file name generated by sessionFile and
@@ -383,41 +840,85 @@ func (c *Config) SaveVimClient(client *govmomi.Client) error {
 		return nil
 	}
 
+	sessions, err := c.sessionCache()
+	if err != nil {
+		return err
+	}
 	p, err := c.vimSessionFile()
 	if err != nil {
 		return err
 	}
 
 	log.Printf("[DEBUG] Will persist SOAP client session data to %q", p)
-	err = os.MkdirAll(filepath.Dir(p), 0700)
+	return sessions.Save(p, client.Client)
+}
+
+// SaveRestClient saves a REST client to the REST session path, in the same
+// way as SaveVimClient.
+func (c *Config) SaveRestClient(client *rest.Client) error {
+	if !c.Persist || client == nil {
+		return nil
+	}
+
+	sessions, err := c.sessionCache()
 	if err != nil {
 		return err
 	}
-
-	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY, 0600)
+	p, err := c.restSessionFile()
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err = f.Close(); err != nil {
-			log.Printf("[DEBUG] Error closing SOAP client session file %q: %s", p, err)
-		}
-	}()
+	log.Printf("[DEBUG] Will persist REST client session data to %q", p)
+	return sessions.Save(p, client)
+}
 
-	err = json.NewEncoder(f).Encode(client.Client)
+// sessionCache returns the cache that sessions are saved to, which encrypts
+// them with the session encryption key of the provider, if one is configured.
+func (c *Config) sessionCache() (*sessioncache.Cache, error) {
+	if c.cache != nil {
+		return c.cache, nil
+	}
+
+	var key []byte
+	var err error
+	switch {
+	case c.SessionEncryptionKey != "":
+		key, err = sessioncache.ParseKey(c.SessionEncryptionKey)
+	case c.SessionEncryptionKeyring:
+		key, err = sessioncache.KeyringKey()
+	default:
+		log.Printf("[WARN] No session encryption key is configured, sessions are saved unencrypted")
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	c.cache, err = sessioncache.New(key)
+	return c.cache, err
 }
 
-func (c *Config) SaveRestClient(client *rest.Client, s *cache.Session) error {
+// pruneSessions removes the saved sessions that have not been used for a long
+// time, if session persistence is enabled.
+func (c *Config) pruneSessions() error {
 	if !c.Persist {
 		return nil
 	}
-	return s.Save(client)
+
+	sessions, err := c.sessionCache()
+	if err != nil {
+		return fmt.Errorf("error setting up session cache: %s", err)
+	}
+	for _, file := range []func() (string, error){c.vimSessionFile, c.restSessionFile} {
+		p, err := file()
+		if err != nil {
+			return err
+		}
+		if err := sessions.Prune(filepath.Dir(p)); err != nil {
+			log.Printf("[WARN] Error pruning saved sessions in %q: %s", filepath.Dir(p), err)
+		}
+	}
+	return nil
 }
 
 // restoreVimClient loads the saved session from disk. Note that this is a helper
@@ -427,31 +928,25 @@ func (c *Config) restoreVimClient(client *vim25.Client) (bool, error) {
 		return false, nil
 	}
 
+	sessions, err := c.sessionCache()
+	if err != nil {
+		return false, err
+	}
 	p, err := c.vimSessionFile()
 	if err != nil {
 		return false, fmt.Errorf("error determining SOAP session filename: %s", err)
 	}
 	log.Printf("[DEBUG] Attempting to locate SOAP client session data in %q", p)
-	f, err := os.Open(p)
+	ok, err := sessions.Load(p, client)
 	if err != nil {
-		if os.IsNotExist(err) {
-			log.Printf("[DEBUG] SOAP client session data not found in %q", p)
-			return false, nil
-		}
-
-		return false, fmt.Errorf("error opening SOAP client session: %s", err)
+		return false, fmt.Errorf("error loading SOAP client session: %s", err)
 	}
-
-	defer func() {
-		if err = f.Close(); err != nil {
-			log.Printf("[DEBUG] Error closing SOAP client session file %q: %s", p, err)
-		}
-	}()
-
-	dec := json.NewDecoder(f)
-	err = dec.Decode(client)
-	if err != nil {
-		return false, fmt.Errorf("error decoding SOAP client session: %s", err)
+	if !ok {
+		log.Printf("[DEBUG] SOAP client session data not found in %q", p)
+		return false, nil
+	}
+	if err := c.tlsOptions().ConfigureSOAP(client.Client); err != nil {
+		return false, err
 	}
 
 	return true, nil
@@ -485,7 +980,7 @@ func (c *Config) LoadVimClient() (*govmomi.Client, error) {
 			// If the PropertyCollector is not found, the saved session for this URL is not valid
 			if _, ok := fault.(types.ManagedObjectNotFound); ok {
 				log.Println("[DEBUG] Cached SOAP client session missing property collector, new session necessary")
-				return nil, nil
+				return nil, c.removeVimClient()
 			}
 		}
 
@@ -495,7 +990,7 @@ func (c *Config) LoadVimClient() (*govmomi.Client, error) {
 	// If the session is nil, the client is not authenticated
 	if u == nil {
 		log.Println("[DEBUG] Unauthenticated session, new session necessary")
-		return nil, nil
+		return nil, c.removeVimClient()
 	}
 
 	log.Println("[DEBUG] Cached SOAP client session loaded successfully")
@@ -505,6 +1000,20 @@ func (c *Config) LoadVimClient() (*govmomi.Client, error) {
 	}, nil
 }
 
+// removeVimClient removes the saved SOAP session from disk, once it is no
+// longer valid.
+func (c *Config) removeVimClient() error {
+	sessions, err := c.sessionCache()
+	if err != nil {
+		return err
+	}
+	p, err := c.vimSessionFile()
+	if err != nil {
+		return err
+	}
+	return sessions.Remove(p)
+}
+
 // SavedVimSessionOrNew either loads a saved SOAP session from disk, or creates
 // a new one.
 func (c *Config) SavedVimSessionOrNew(u *url.URL) (*govmomi.Client, error) {
@@ -517,7 +1026,7 @@ func (c *Config) SavedVimSessionOrNew(u *url.URL) (*govmomi.Client, error) {
 	}
 	if client == nil {
 		log.Printf("[DEBUG] Creating new SOAP API session on endpoint %s", c.VSphereServer)
-		client, err = newClientWithKeepAlive(ctx, u, c.InsecureFlag, c.KeepAlive)
+		client, err = c.newClientWithKeepAlive(ctx, u)
 		if err != nil {
 			return nil, fmt.Errorf("error setting up new vSphere SOAP client: %s", err)
 		}
@@ -526,30 +1035,89 @@ func (c *Config) SavedVimSessionOrNew(u *url.URL) (*govmomi.Client, error) {
 	return client, nil
 }
 
-func newClientWithKeepAlive(ctx context.Context, u *url.URL, insecure bool, keepAlive int) (*govmomi.Client, error) {
-	soapClient := soap.NewClient(u, insecure)
+func (c *Config) newClientWithKeepAlive(ctx context.Context, u *url.URL) (*govmomi.Client, error) {
+	soapClient := soap.NewClient(u, c.InsecureFlag)
+	if err := c.tlsOptions().ConfigureSOAP(soapClient); err != nil {
+		return nil, err
+	}
+	c.registerCassette(soapClient)
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return nil, err
 	}
 
-	c := &govmomi.Client{
+	client := &govmomi.Client{
 		Client:         vimClient,
 		SessionManager: session.NewManager(vimClient),
 	}
 
-	k := session.KeepAlive(c.RoundTripper, time.Duration(keepAlive)*time.Minute)
-	c.RoundTripper = k
+	k := session.KeepAlive(client.RoundTripper, time.Duration(c.KeepAlive)*time.Minute)
+	client.RoundTripper = k
 
-	// Only login if the URL contains user information.
-	if u.User != nil {
-		err = c.Login(ctx, u.User)
+	if err := c.login(ctx, client, u); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// login creates a SOAP API session for client. Only login if the URL contains
+// user information, or with an SSO token.
+func (c *Config) login(ctx context.Context, client *govmomi.Client, u *url.URL) error {
+	switch {
+	case c.ssoTokenAuth():
+		signer, err := c.ssoSigner(ctx, client.Client)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		if err := ssohelper.LoginByToken(ctx, client.Client, signer); err != nil {
+			return fmt.Errorf("error logging in with SSO token: %s", err)
+		}
+	case u.User != nil:
+		return client.Login(ctx, u.User)
 	}
+	return nil
+}
 
-	return c, nil
+// throttleClient applies the concurrency and rate limits and the retries of
+// the provider to the SOAP, REST, policy based management, and vSAN clients
+// of client. Requests that fail because the session has expired are sent
+// again after logging in with u, or with restLogin for the REST client.
+func (c *Config) throttleClient(client *Client, u *url.URL, restLogin func(ctx context.Context, rc *rest.Client) error) {
+	if c.throttle == nil {
+		c.throttle = throttle.New(throttle.Options{
+			MaxConcurrent:     c.MaxConcurrentRequests,
+			RequestsPerSecond: c.RequestsPerSecond,
+			MaxRetries:        c.MaxRetries,
+		})
+	}
+
+	// The policy based management and vSAN clients share the session of the
+	// SOAP client.
+	vc := client.vimClient
+	session := throttle.NewSession(func(ctx context.Context) error {
+		return c.login(ctx, vc, u)
+	})
+	vc.Client.RoundTripper = c.throttle.SOAP(vc.Client.RoundTripper, session)
+	if client.pbmClient != nil {
+		client.pbmClient.RoundTripper = c.throttle.SOAP(client.pbmClient.RoundTripper, session)
+	}
+	if client.vsanClient != nil {
+		client.vsanClient.RoundTripper = c.throttle.SOAP(client.vsanClient.RoundTripper, session)
+	}
+
+	if rc := client.restClient; rc != nil {
+		restSession := throttle.NewSession(func(ctx context.Context) error {
+			return restLogin(ctx, rc)
+		})
+		transport := rc.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		rc.Transport = c.throttle.HTTP(transport, restSession, func(req *http.Request) {
+			req.Header.Set(restSessionHeader, rc.SessionID())
+		})
+	}
 }
 
 func restSessionValid(client *rest.Client) bool {
@@ -5,10 +5,14 @@
 package vsphere
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -225,3 +229,99 @@ func TestNewConfig(t *testing.T) {
 		t.Fatalf("expected %#v, got %#v", expected, actual)
 	}
 }
+
+func TestConfigValidateAuth(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *Config
+		err    bool
+	}{
+		{"user and password", &Config{User: "foo", Password: "bar"}, false},
+		{"no credentials", &Config{}, true},
+		{"user without password", &Config{User: "foo"}, true},
+		{"sso token", &Config{SSOToken: "token"}, false},
+		{"sso oauth token", &Config{SSOOAuthToken: "token"}, false},
+		{"sso token and oauth token", &Config{SSOToken: "token", SSOOAuthToken: "token"}, true},
+		{"sso certificate and key", &Config{SSOCertificate: "cert", SSOPrivateKey: "key"}, false},
+		{"sso certificate without key", &Config{SSOCertificate: "cert"}, true},
+		{"sso key without certificate", &Config{User: "foo", Password: "bar", SSOPrivateKey: "key"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validateAuth()
+			if tc.err && err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestConfigSessionFile_ssoIdentity(t *testing.T) {
+	configs := map[string]*Config{
+		"user":        {User: "foo", Password: "bar"},
+		"token":       {SSOToken: "token1"},
+		"other token": {SSOToken: "token2"},
+		"certificate": {SSOCertificate: "cert", SSOPrivateKey: "key"},
+	}
+	seen := make(map[string]string)
+	for name, c := range configs {
+		c.VSphereServer = "vsphere.foo.internal"
+		c.RestSessionPath = t.TempDir()
+		for _, file := range []func() (string, error){c.sessionFile, c.restSessionFile} {
+			p, err := file()
+			if err != nil {
+				t.Fatalf("%s: %s", name, err)
+			}
+			p = filepath.Base(p)
+			if other, ok := seen[p]; ok {
+				t.Fatalf("expected %s and %s to have different session files, got %s", name, other, p)
+			}
+			seen[p] = name
+		}
+	}
+
+	// Sessions of users are keyed as in govc.
+	c := &Config{User: "foo", Password: "bar", VSphereServer: "vsphere.foo.internal"}
+	u, err := c.vimURLWithoutPassword()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("%064x", sha256.Sum256([]byte(fmt.Sprintf("%s#insecure=false", u.String()))))
+	if actual, _ := c.sessionFile(); actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestVCenterClientKey(t *testing.T) {
+	type params struct {
+		server, username, password, thumbprint string
+		insecure                               bool
+	}
+	base := params{"vc2.foo.internal", "user", "pass", "AA:BB", false}
+	cases := map[string]params{
+		"base":       base,
+		"server":     {"vc3.foo.internal", "user", "pass", "AA:BB", false},
+		"username":   {"vc2.foo.internal", "other", "pass", "AA:BB", false},
+		"password":   {"vc2.foo.internal", "user", "other", "AA:BB", false},
+		"thumbprint": {"vc2.foo.internal", "user", "pass", "CC:DD", false},
+		"insecure":   {"vc2.foo.internal", "user", "pass", "AA:BB", true},
+	}
+	seen := make(map[string]string)
+	for name, p := range cases {
+		key := vcenterClientKey(p.server, p.username, p.password, p.thumbprint, p.insecure)
+		if other, ok := seen[key]; ok {
+			t.Fatalf("expected %s and %s to have different keys, got %s", name, other, key)
+		}
+		if strings.Contains(key, p.password) {
+			t.Fatalf("%s: expected key not to contain the password, got %s", name, key)
+		}
+		seen[key] = name
+	}
+	if vcenterClientKey(base.server, base.username, base.password, base.thumbprint, base.insecure) !=
+		vcenterClientKey(base.server, base.username, base.password, base.thumbprint, base.insecure) {
+		t.Fatal("expected the same parameters to have the same key")
+	}
+}
@@ -5,9 +5,10 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 	"reflect"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vim25/types"
 	helper "github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/alarm"
@@ -15,7 +16,7 @@ import (
 
 func dataSourceVSphereAlarm() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereAlarmRead,
+		ReadContext: dataSourceVSphereAlarmRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -285,16 +286,16 @@ func dataSourceVSphereAlarm() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereAlarmRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereAlarmRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	entity, err := helper.FindEntity(client, d.Get("entity_type").(string), d.Get("entity_id").(string))
+	entity, err := helper.FindEntity(ctx, client, d.Get("entity_type").(string), d.Get("entity_id").(string))
 	if err != nil {
-		return fmt.Errorf("alarm entity error: %s", err)
+		return diag.Errorf("alarm entity error: %s", err)
 	}
 
-	al, err := helper.FromName(client, d.Get("name").(string), entity)
+	al, err := helper.FromName(ctx, client, d.Get("name").(string), entity)
 	if err != nil {
-		return fmt.Errorf("cannot locate alarm: %s", err)
+		return diag.Errorf("cannot locate alarm: %s", err)
 	}
 
 	d.SetId(al.Reference().Value)
@@ -307,13 +308,13 @@ func dataSourceVSphereAlarmRead(d *schema.ResourceData, meta interface{}) error
 		_ = d.Set("expression_operator", "or")
 		expressions, err = helper.GetExpressions(exp.Expression)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	case *types.AndAlarmExpression:
 		_ = d.Set("expression_operator", "and")
 		expressions, err = helper.GetExpressions(exp.Expression)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if len(expressions.EventExpressions) > 0 {
@@ -335,10 +336,10 @@ func dataSourceVSphereAlarmRead(d *schema.ResourceData, meta interface{}) error
 		case *types.AlarmAction:
 			actions, err = helper.GetAlarmActions([]types.BaseAlarmAction{alarmAction})
 		default:
-			return fmt.Errorf("unmanaged alarm action type: %s", reflect.TypeOf(alarmAction))
+			return diag.Errorf("unmanaged alarm action type: %s", reflect.TypeOf(alarmAction))
 		}
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if len(actions.EmailAction) > 0 {
 			_ = d.Set("email_action", actions.EmailAction)
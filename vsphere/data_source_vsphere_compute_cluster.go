@@ -5,15 +5,16 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 )
 
 func dataSourceVSphereComputeCluster() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereComputeClusterRead,
+		ReadContext: dataSourceVSphereComputeClusterRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -35,14 +36,14 @@ func dataSourceVSphereComputeCluster() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereComputeClusterRead(d *schema.ResourceData, meta interface{}) error {
-	cluster, err := resourceVSphereComputeClusterGetClusterFromPath(meta, d.Get("name").(string), d.Get("datacenter_id").(string))
+func dataSourceVSphereComputeClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cluster, err := resourceVSphereComputeClusterGetClusterFromPath(ctx, meta, d.Get("name").(string), d.Get("datacenter_id").(string))
 	if err != nil {
-		return fmt.Errorf("error loading cluster: %s", err)
+		return diag.Errorf("error loading cluster: %s", err)
 	}
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
-		return fmt.Errorf("error loading cluster properties: %s", err)
+		return diag.Errorf("error loading cluster properties: %s", err)
 	}
 
 	d.SetId(cluster.Reference().Value)
@@ -5,15 +5,16 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 )
 
 func dataSourceVSphereComputeClusterHostGroup() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereComputeClusterHostGroupRead,
+		ReadContext: dataSourceVSphereComputeClusterHostGroupRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -36,15 +37,15 @@ func dataSourceVSphereComputeClusterHostGroup() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta interface{}) error {
-	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
+func dataSourceVSphereComputeClusterHostGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(ctx, d, meta)
 	if err != nil {
-		return fmt.Errorf("cannot locate resource: %s", err)
+		return diag.Errorf("cannot locate resource: %s", err)
 	}
 
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
-		return fmt.Errorf("cannot read cluster properties: %s", err)
+		return diag.Errorf("cannot read cluster properties: %s", err)
 	}
 
 	hostSystemIDs := make([]string, len(props.Host))
@@ -54,7 +55,7 @@ func dataSourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta i
 
 	d.SetId(name)
 	if err := d.Set("host_system_ids", hostSystemIDs); err != nil {
-		return fmt.Errorf("cannot set host_system_ids: %s", err)
+		return diag.Errorf("cannot set host_system_ids: %s", err)
 	}
 
 	return nil
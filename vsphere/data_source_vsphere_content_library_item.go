@@ -5,6 +5,8 @@
 package vsphere
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
@@ -12,7 +14,7 @@ import (
 
 func dataSourceVSphereContentLibraryItem() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereContentLibraryItemRead,
+		ReadContext: dataSourceVSphereContentLibraryItemRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -36,12 +38,12 @@ func dataSourceVSphereContentLibraryItem() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereContentLibraryItemRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereContentLibraryItemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	rc := meta.(*Client).restClient
-	lib, _ := contentlibrary.FromID(rc, d.Get("library_id").(string))
-	item, err := contentlibrary.ItemFromName(rc, lib, d.Get("name").(string))
+	lib, _ := contentlibrary.FromID(ctx, rc, d.Get("library_id").(string))
+	item, err := contentlibrary.ItemFromName(ctx, rc, lib, d.Get("name").(string))
 	if err != nil {
-		return provider.Error(d.Get("name").(string), "dataSourceVSphereContentLibraryItemRead", err)
+		return diag.FromErr(provider.Error(d.Get("name").(string), "dataSourceVSphereContentLibraryItemRead", err))
 	}
 	_ = d.Set("type", item.Type)
 	d.SetId(item.ID)
@@ -6,9 +6,9 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/view"
@@ -17,7 +17,7 @@ import (
 
 func dataSourceVSphereDatacenter() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDatacenterRead,
+		ReadContext: dataSourceVSphereDatacenterRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -35,13 +35,12 @@ func dataSourceVSphereDatacenter() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) error {
-	ctx := context.Background()
+func dataSourceVSphereDatacenterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	datacenter := d.Get("name").(string)
-	dc, err := getDatacenter(client, datacenter)
+	dc, err := getDatacenter(ctx, client, datacenter)
 	if err != nil {
-		return fmt.Errorf("error fetching datacenter: %s", err)
+		return diag.Errorf("error fetching datacenter: %s", err)
 	}
 	id := dc.Reference().Value
 	d.SetId(id)
@@ -50,7 +49,7 @@ func dataSourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) e
 	viewManager := view.NewManager(client.Client)
 	containerView, err := viewManager.CreateContainerView(ctx, dc.Reference(), []string{"VirtualMachine"}, true)
 	if err != nil {
-		return fmt.Errorf("error fetching datacenter: %s", err)
+		return diag.Errorf("error fetching datacenter: %s", err)
 	}
 	defer func() {
 		if err := containerView.Destroy(ctx); err != nil {
@@ -60,7 +59,7 @@ func dataSourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) e
 	var vms []mo.VirtualMachine
 	err = containerView.Retrieve(ctx, []string{"VirtualMachine"}, []string{"name"}, &vms)
 	if err != nil {
-		return fmt.Errorf("error fetching virtual machines: %s", err)
+		return diag.Errorf("error fetching virtual machines: %s", err)
 	}
 	var vmNames []string
 	for v := range vms {
@@ -68,7 +67,7 @@ func dataSourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) e
 	}
 	err = d.Set("virtual_machines", vmNames)
 	if err != nil {
-		return fmt.Errorf("error setting virtual_machines: %s", err)
+		return diag.Errorf("error setting virtual_machines: %s", err)
 	}
 	return nil
 }
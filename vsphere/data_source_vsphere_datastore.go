@@ -5,8 +5,10 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/datastore"
@@ -14,7 +16,7 @@ import (
 
 func dataSourceVSphereDatastore() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDatastoreRead,
+		ReadContext: dataSourceVSphereDatastoreRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -36,27 +38,27 @@ func dataSourceVSphereDatastore() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDatastoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	name := d.Get("name").(string)
 	var dc *object.Datacenter
 	if dcID, ok := d.GetOk("datacenter_id"); ok {
 		var err error
-		dc, err = datacenterFromID(client, dcID.(string))
+		dc, err = datacenterFromID(ctx, client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.Errorf("cannot locate datacenter: %s", err)
 		}
 	}
-	ds, err := datastore.FromPath(client, name, dc)
+	ds, err := datastore.FromPath(ctx, client, name, dc)
 	if err != nil {
-		return fmt.Errorf("error fetching datastore: %s", err)
+		return diag.Errorf("error fetching datastore: %s", err)
 	}
 
 	d.SetId(ds.Reference().Value)
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ctx, ds)
 	if err != nil {
-		return fmt.Errorf("error getting properties for datastore ID %q: %s", ds.Reference().Value, err)
+		return diag.Errorf("error getting properties for datastore ID %q: %s", ds.Reference().Value, err)
 	}
 	_ = d.Set("stats", map[string]string{"capacity": fmt.Sprintf("%v", props.Summary.Capacity), "free": fmt.Sprintf("%v", props.Summary.FreeSpace)})
 	return nil
@@ -6,15 +6,15 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/datastore"
 )
 
 func dataSourceVSphereDatastoreCluster() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDatastoreClusterRead,
+		ReadContext: dataSourceVSphereDatastoreClusterRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -37,29 +37,28 @@ func dataSourceVSphereDatastoreCluster() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDatastoreClusterRead(d *schema.ResourceData, meta interface{}) error {
-	ctx := context.Background()
+func dataSourceVSphereDatastoreClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	pod, err := resourceVSphereDatastoreClusterGetPodFromPath(meta, d.Get("name").(string), d.Get("datacenter_id").(string))
+	pod, err := resourceVSphereDatastoreClusterGetPodFromPath(ctx, meta, d.Get("name").(string), d.Get("datacenter_id").(string))
 	if err != nil {
-		return fmt.Errorf("error loading datastore cluster: %s", err)
+		return diag.Errorf("error loading datastore cluster: %s", err)
 	}
 	d.SetId(pod.Reference().Value)
 	var dsNames []string
 	childDatastores, err := pod.Children(ctx)
 	if err != nil {
-		return fmt.Errorf("error retrieving datastores in datastore cluster: %s", err)
+		return diag.Errorf("error retrieving datastores in datastore cluster: %s", err)
 	}
 	for d := range childDatastores {
-		ds, err := datastore.FromID(client, childDatastores[d].Reference().Value)
+		ds, err := datastore.FromID(ctx, client, childDatastores[d].Reference().Value)
 		if err != nil {
-			return fmt.Errorf("error retrieving datastore: %s", err)
+			return diag.Errorf("error retrieving datastore: %s", err)
 		}
 		dsNames = append(dsNames, ds.Name())
 	}
 	err = d.Set("datastores", dsNames)
 	if err != nil {
-		return fmt.Errorf("cannot set datastores: %s", err)
+		return diag.Errorf("cannot set datastores: %s", err)
 	}
 	return nil
 }
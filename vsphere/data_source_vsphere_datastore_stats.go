@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/datastore"
@@ -17,7 +18,7 @@ import (
 
 func dataSourceVSphereDatastoreStats() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDatastoreStatsRead,
+		ReadContext: dataSourceVSphereDatastoreStatsRead,
 
 		Schema: map[string]*schema.Schema{
 			"datacenter_id": {
@@ -39,34 +40,33 @@ func dataSourceVSphereDatastoreStats() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDatastoreStatsRead(d *schema.ResourceData, meta interface{}) error {
-	ctx := context.Background()
+func dataSourceVSphereDatastoreStatsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	var dc *object.Datacenter
 	if dcID, ok := d.GetOk("datacenter_id"); ok {
 		var err error
-		dc, err = datacenterFromID(client, dcID.(string))
+		dc, err = datacenterFromID(ctx, client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.Errorf("cannot locate datacenter: %s", err)
 		}
 	}
 
-	dss, err := datastore.List(client)
+	dss, err := datastore.List(ctx, client)
 	if err != nil {
-		return fmt.Errorf("error listing datastores: %s", err)
+		return diag.Errorf("error listing datastores: %s", err)
 	}
 
-	storagePods, err := storagepod.List(client)
+	storagePods, err := storagepod.List(ctx, client)
 	if err != nil {
-		return fmt.Errorf("error retrieving storage pods: %s", err)
+		return diag.Errorf("error retrieving storage pods: %s", err)
 	}
 	for s := range storagePods {
 		childDatastores, err := storagePods[s].Children(ctx)
 		if err != nil {
-			return fmt.Errorf("error retrieving datastores in datastore cluster: %s", err)
+			return diag.Errorf("error retrieving datastores in datastore cluster: %s", err)
 		}
 		for c := range childDatastores {
-			ds, err := datastore.FromID(client, childDatastores[c].Reference().Value)
+			ds, err := datastore.FromID(ctx, client, childDatastores[c].Reference().Value)
 			if err != nil {
 				log.Printf("[WARN] Skipping datastore with ID %s: %s", childDatastores[c].Reference().Value, err)
 				continue
@@ -80,7 +80,7 @@ func dataSourceVSphereDatastoreStatsRead(d *schema.ResourceData, meta interface{
 	processedAny := false
 
 	for i := range dss {
-		ds, err := datastore.FromPath(client, dss[i].Name(), dc)
+		ds, err := datastore.FromPath(ctx, client, dss[i].Name(), dc)
 		if err != nil {
 			log.Printf("[WARN] Skipping inaccessible datastore %q: %s\n", dss[i].Name(), err)
 			continue
@@ -90,7 +90,7 @@ func dataSourceVSphereDatastoreStatsRead(d *schema.ResourceData, meta interface{
 			continue
 		}
 
-		props, err := datastore.Properties(ds)
+		props, err := datastore.Properties(ctx, ds)
 		if err != nil {
 			log.Printf("[WARN] Skipping datastore %q with inaccessible properties: %s\n", ds.Reference().Value, err)
 			continue
@@ -112,7 +112,7 @@ func dataSourceVSphereDatastoreStatsRead(d *schema.ResourceData, meta interface{
 	}
 
 	if !processedAny {
-		return fmt.Errorf("failed to process any datastores, all were inaccessible")
+		return diag.Errorf("failed to process any datastores, all were inaccessible")
 	}
 
 	d.SetId(fmt.Sprintf("%s_stats", dc.Reference().Value))
@@ -5,8 +5,9 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
@@ -15,7 +16,7 @@ import (
 
 func dataSourceVSphereDistributedVirtualSwitch() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDistributedVirtualSwitchRead,
+		ReadContext: dataSourceVSphereDistributedVirtualSwitchRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -38,28 +39,28 @@ func dataSourceVSphereDistributedVirtualSwitch() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDistributedVirtualSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	name := d.Get("name").(string)
 	var dc *object.Datacenter
 	if dcID, ok := d.GetOk("datacenter_id"); ok {
 		var err error
-		dc, err = datacenterFromID(client, dcID.(string))
+		dc, err = datacenterFromID(ctx, client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.Errorf("cannot locate datacenter: %s", err)
 		}
 	}
-	dvs, err := dvsFromPath(client, name, dc)
+	dvs, err := dvsFromPath(ctx, client, name, dc)
 	if err != nil {
-		return fmt.Errorf("error fetching distributed virtual switch: %s", err)
+		return diag.Errorf("error fetching distributed virtual switch: %s", err)
 	}
-	props, err := dvsProperties(dvs)
+	props, err := dvsProperties(ctx, dvs)
 	if err != nil {
-		return fmt.Errorf("error fetching DVS properties: %s", err)
+		return diag.Errorf("error fetching DVS properties: %s", err)
 	}
 
 	d.SetId(props.Uuid)
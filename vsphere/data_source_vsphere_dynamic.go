@@ -10,6 +10,7 @@ import (
 	"log"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vapi/tags"
@@ -17,7 +18,7 @@ import (
 
 func dataSourceVSphereDynamic() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDynamicRead,
+		ReadContext: dataSourceVSphereDynamicRead,
 
 		Schema: map[string]*schema.Schema{
 			"filter": {
@@ -40,34 +41,34 @@ func dataSourceVSphereDynamic() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDynamicRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDynamicRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] dataSourceDynamic: Beginning dynamic data source read.")
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	tagIDs := d.Get("filter").(*schema.Set).List()
-	matches, err := filterObjectsByTag(tm, tagIDs)
+	matches, err := filterObjectsByTag(ctx, tm, tagIDs)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	filtered, err := filterObjectsByName(d, meta, matches)
+	filtered, err := filterObjectsByName(ctx, d, meta, matches)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	switch {
 	case len(filtered) < 1:
-		return fmt.Errorf("no matching resources found")
+		return diag.Errorf("no matching resources found")
 	case len(filtered) > 1:
 		log.Printf("dataSourceVSphereDynamic: Multiple matches found: %v", filtered)
-		return fmt.Errorf("multiple objects match the supplied criteria")
+		return diag.Errorf("multiple objects match the supplied criteria")
 	}
 	d.SetId(filtered[0])
 	log.Printf("[DEBUG] dataSourceDynamic: Read complete. Resource located: %s", filtered[0])
 	return nil
 }
 
-func filterObjectsByName(d *schema.ResourceData, meta interface{}, matches []tags.AttachedObjects) ([]string, error) {
+func filterObjectsByName(ctx context.Context, d *schema.ResourceData, meta interface{}, matches []tags.AttachedObjects) ([]string, error) {
 	log.Printf("[DEBUG] dataSourceDynamic: Filtering objects by name.")
 	var filtered []string
 	re, err := regexp.Compile(d.Get("name_regex").(string))
@@ -81,7 +82,7 @@ func filterObjectsByName(d *schema.ResourceData, meta interface{}, matches []tag
 			continue
 		}
 		attachedObject := object.NewCommon(meta.(*Client).vimClient.Client, match.Reference())
-		name, err := attachedObject.ObjectName(context.TODO())
+		name, err := attachedObject.ObjectName(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -93,13 +94,13 @@ func filterObjectsByName(d *schema.ResourceData, meta interface{}, matches []tag
 	return filtered, nil
 }
 
-func filterObjectsByTag(tm *tags.Manager, t []interface{}) ([]tags.AttachedObjects, error) {
+func filterObjectsByTag(ctx context.Context, tm *tags.Manager, t []interface{}) ([]tags.AttachedObjects, error) {
 	log.Printf("[DEBUG] dataSourceDynamic: Filtering objects by tags.")
 	var tagIDs []string
 	for _, ti := range t {
 		tagIDs = append(tagIDs, ti.(string))
 	}
-	matches, err := tm.GetAttachedObjectsOnTags(context.TODO(), tagIDs)
+	matches, err := tm.GetAttachedObjectsOnTags(ctx, tagIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -5,15 +5,16 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/folder"
 )
 
 func dataSourceVSphereFolder() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereFolderRead,
+		ReadContext: dataSourceVSphereFolderRead,
 		Schema: map[string]*schema.Schema{
 			"path": {
 				Type:        schema.TypeString,
@@ -24,11 +25,11 @@ func dataSourceVSphereFolder() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereFolderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	fo, err := folder.FromAbsolutePath(client, d.Get("path").(string))
+	fo, err := folder.FromAbsolutePath(ctx, client, d.Get("path").(string))
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return diag.Errorf("cannot locate folder: %s", err)
 	}
 
 	d.SetId(fo.Reference().Value)
@@ -5,13 +5,15 @@
 package vsphere
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/guestoscustomizations"
 )
 
 func dataSourceVSphereGuestOSCustomization() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereGuestCustomizationRead,
+		ReadContext: dataSourceVSphereGuestCustomizationRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -229,15 +231,15 @@ func dataSourceVSphereGuestOSCustomization() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereGuestCustomizationRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereGuestCustomizationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	name := d.Get("name").(string)
-	specItem, err := guestoscustomizations.FromName(client, name)
+	specItem, err := guestoscustomizations.FromName(ctx, client, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(name)
 
-	return guestoscustomizations.FlattenGuestOsCustomizationSpec(d, specItem, client)
+	return diag.FromErr(guestoscustomizations.FlattenGuestOsCustomizationSpec(d, specItem, client))
 }
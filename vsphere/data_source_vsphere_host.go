@@ -5,15 +5,16 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 )
 
 func dataSourceVSphereHost() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereHostRead,
+		ReadContext: dataSourceVSphereHostRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -35,25 +36,25 @@ func dataSourceVSphereHost() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereHostRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereHostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	name := d.Get("name").(string)
 	dcID := d.Get("datacenter_id").(string)
-	dc, err := datacenterFromID(client, dcID)
+	dc, err := datacenterFromID(ctx, client, dcID)
 	if err != nil {
-		return fmt.Errorf("error fetching datacenter: %s", err)
+		return diag.Errorf("error fetching datacenter: %s", err)
 	}
-	hs, err := hostsystem.SystemOrDefault(client, name, dc)
+	hs, err := hostsystem.SystemOrDefault(ctx, client, name, dc)
 	if err != nil {
-		return fmt.Errorf("error fetching host: %s", err)
+		return diag.Errorf("error fetching host: %s", err)
 	}
-	rp, err := hostsystem.ResourcePool(hs)
+	rp, err := hostsystem.ResourcePool(ctx, hs)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	err = d.Set("resource_pool_id", rp.Reference().Value)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	id := hs.Reference().Value
 	d.SetId(id)
@@ -5,13 +5,15 @@
 package vsphere
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vapi/esx/settings/depots"
 )
 
 func dataSourceVSphereHostBaseImages() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereHostBaseImagesRead,
+		ReadContext: dataSourceVSphereHostBaseImagesRead,
 		Schema: map[string]*schema.Schema{
 			"version": {
 				Type:        schema.TypeList,
@@ -23,12 +25,12 @@ func dataSourceVSphereHostBaseImages() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereHostBaseImagesRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereHostBaseImagesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).restClient
 
 	images, err := depots.NewManager(client).ListBaseImages()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	versions := make([]string, len(images))
@@ -37,5 +39,5 @@ func dataSourceVSphereHostBaseImagesRead(d *schema.ResourceData, meta interface{
 	}
 
 	d.SetId(versions[0])
-	return d.Set("version", versions)
+	return diag.FromErr(d.Set("version", versions))
 }
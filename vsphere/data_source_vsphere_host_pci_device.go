@@ -5,10 +5,12 @@
 package vsphere
 
 import (
+	"context"
 	"log"
 	"regexp"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
@@ -16,7 +18,7 @@ import (
 
 func dataSourceVSphereHostPciDevice() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereHostPciDeviceRead,
+		ReadContext: dataSourceVSphereHostPciDeviceRead,
 
 		Schema: map[string]*schema.Schema{
 			"host_id": {
@@ -44,24 +46,29 @@ func dataSourceVSphereHostPciDevice() *schema.Resource {
 				Computed:    true,
 				Description: "The name of the PCI device.",
 			},
+			"device_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The hexadecimal value of the PCI device's device ID.",
+			},
 		},
 	}
 }
 
-func dataSourceVSphereHostPciDeviceRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereHostPciDeviceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] DataHostPCIDev: Beginning PCI device lookup on %s", d.Get("host_id").(string))
 	client := meta.(*Client).vimClient
-	host, err := hostsystem.FromID(client, d.Get("host_id").(string))
+	host, err := hostsystem.FromID(ctx, client, d.Get("host_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	hprops, err := hostsystem.Properties(host)
+	hprops, err := hostsystem.Properties(ctx, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	devices, err := matchName(d, hprops.Hardware.PciDevice)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] DataHostPCIDev: Looking for a device with matching class_id and vendor_id")
 	for _, device := range devices {
@@ -69,7 +76,7 @@ func dataSourceVSphereHostPciDeviceRead(d *schema.ResourceData, meta interface{}
 		if class, exists := d.GetOk("class_id"); exists {
 			classInt, err := strconv.ParseInt(class.(string), 16, 16)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if device.ClassId != int16(classInt) {
 				continue
@@ -79,7 +86,7 @@ func dataSourceVSphereHostPciDeviceRead(d *schema.ResourceData, meta interface{}
 		if vendor, exists := d.GetOk("vendor_id"); exists {
 			vendorInt, err := strconv.ParseInt(vendor.(string), 16, 16)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if device.VendorId != int16(vendorInt) {
 				continue
@@ -87,10 +94,12 @@ func dataSourceVSphereHostPciDeviceRead(d *schema.ResourceData, meta interface{}
 		}
 		classHex := strconv.FormatInt(int64(device.ClassId), 16)
 		vendorHex := strconv.FormatInt(int64(device.VendorId), 16)
+		deviceHex := strconv.FormatInt(int64(device.DeviceId), 16)
 		d.SetId(device.Id)
 		_ = d.Set("name", device.DeviceName)
 		_ = d.Set("class_id", classHex)
 		_ = d.Set("vendor_id", vendorHex)
+		_ = d.Set("device_id", deviceHex)
 		log.Printf("[DEBUG] DataHostPCIDev: Matching PCI device found: %s", device.DeviceName)
 		return nil
 	}
@@ -5,11 +5,13 @@
 package vsphere
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"log"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
@@ -17,7 +19,7 @@ import (
 
 func dataSourceVSphereHostVGpuProfile() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereHostVGpuProfileRead,
+		ReadContext: dataSourceVSphereHostVGpuProfileRead,
 		Schema: map[string]*schema.Schema{
 			"host_id": {
 				Type:        schema.TypeString,
@@ -67,25 +69,25 @@ func dataSourceVSphereHostVGpuProfile() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereHostVGpuProfileRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereHostVGpuProfileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] DataHostVGpuProfile: Beginning vGPU Profile lookup on %s", d.Get("host_id").(string))
 
 	client := meta.(*Client).vimClient
 
-	host, err := hostsystem.FromID(client, d.Get("host_id").(string))
+	host, err := hostsystem.FromID(ctx, client, d.Get("host_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	hprops, err := hostsystem.Properties(host)
+	hprops, err := hostsystem.Properties(ctx, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Create unique ID based on the host_id
 	idsum := sha256.New()
 	if _, err := fmt.Fprintf(idsum, "%#v", d.Get("host_id").(string)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%x", idsum.Sum(nil)))
@@ -98,7 +100,7 @@ func dataSourceVSphereHostVGpuProfileRead(d *schema.ResourceData, meta interface
 	// If searching for a specific vGPU profile (by name)
 	name, ok := d.GetOk("name_regex")
 	if (ok) && (name.(string) != "") {
-		return searchVGpuProfileByName(d, vgpusRaw, name.(string))
+		return diag.FromErr(searchVGpuProfileByName(d, vgpusRaw, name.(string)))
 	}
 
 	// Loop over all vGPU profiles on host
@@ -117,7 +119,7 @@ func dataSourceVSphereHostVGpuProfileRead(d *schema.ResourceData, meta interface
 
 	// Set the `vgpu_profile` output to all vGPU profiles
 	if err := d.Set("vgpu_profiles", vgpus); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] DataHostVGpuProfile: Identified %d vGPU profiles available on %s", len(vgpus), d.Get("host_id").(string))
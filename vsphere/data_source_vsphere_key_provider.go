@@ -0,0 +1,72 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/keyprovider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+func dataSourceVSphereKeyProvider() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereKeyProviderRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the key provider. If not specified, the default key provider is returned.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the key provider. One of standard or native.",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the key provider is the default key provider of vCenter Server.",
+			},
+			"has_backup": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the key provider has been backed up.",
+			},
+		},
+	}
+}
+
+func dataSourceVSphereKeyProviderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	defaultID, err := keyprovider.DefaultID(ctx, client)
+	if err != nil {
+		return diag.Errorf("error loading default key provider: %s", err)
+	}
+	name := d.Get("name").(string)
+	if name == "" {
+		if defaultID == "" {
+			return diag.Errorf("no default key provider is set")
+		}
+		name = defaultID
+	}
+	info, err := keyprovider.FromID(ctx, client, name)
+	if err != nil {
+		return diag.Errorf("error loading key provider: %s", err)
+	}
+	if info == nil {
+		return diag.Errorf("key provider %q not found", name)
+	}
+
+	d.SetId(info.ClusterId.Id)
+	_ = d.Set("name", info.ClusterId.Id)
+	_ = d.Set("type", keyprovider.TypeFromInfo(info))
+	_ = d.Set("default", defaultID == info.ClusterId.Id)
+	_ = d.Set("has_backup", structure.DeRef(info.HasBackup))
+	return nil
+}
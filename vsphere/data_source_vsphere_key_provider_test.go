@@ -0,0 +1,56 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceVSphereKeyProvider_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereKeyProviderConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_key_provider.default", "id",
+						"vsphere_key_provider.provider", "id",
+					),
+					resource.TestCheckResourceAttr("data.vsphere_key_provider.default", "type", "native"),
+					resource.TestCheckResourceAttr("data.vsphere_key_provider.default", "default", "true"),
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_key_provider.named", "id",
+						"vsphere_key_provider.provider", "id",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereKeyProviderConfig() string {
+	return `
+resource "vsphere_key_provider" "provider" {
+  name    = "testacc-nkp"
+  type    = "native"
+  default = true
+}
+
+data "vsphere_key_provider" "default" {
+  depends_on = [vsphere_key_provider.provider]
+}
+
+data "vsphere_key_provider" "named" {
+  name = vsphere_key_provider.provider.name
+}
+`
+}
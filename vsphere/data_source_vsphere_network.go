@@ -5,10 +5,12 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -24,7 +26,7 @@ const (
 
 func dataSourceVSphereNetwork() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereNetworkRead,
+		ReadContext: dataSourceVSphereNetworkRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -88,7 +90,7 @@ func dataSourceVSphereNetwork() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereNetworkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	name := d.Get("name").(string)
@@ -98,9 +100,9 @@ func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) erro
 	var dc *object.Datacenter
 	if dcID, ok := d.GetOk("datacenter_id"); ok {
 		var err error
-		dc, err = datacenterFromID(client, dcID.(string))
+		dc, err = datacenterFromID(ctx, client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.Errorf("cannot locate datacenter: %s", err)
 		}
 	}
 
@@ -122,7 +124,7 @@ func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) erro
 		var err error
 		if dvSwitchUUID != "" {
 			// Handle distributed virtual switch port group
-			net, err = network.FromNameAndDVSUuid(client, name, dc, dvSwitchUUID)
+			net, err = network.FromNameAndDVSUuid(ctx, client, name, dc, dvSwitchUUID)
 			if err != nil {
 				var notFoundError *network.NotFoundError
 				if errors.As(err, &notFoundError) {
@@ -134,7 +136,7 @@ func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) erro
 			return net, waitForNetworkCompleted, nil
 		} else if vpcID != "" {
 			// Handle VPC network
-			net, err = network.FromNameAndVPCId(client, name, dc, vpcProjectID, vpcID)
+			net, err = network.FromNameAndVPCId(ctx, client, name, dc, vpcProjectID, vpcID)
 			if err != nil {
 				var notFoundError *network.NotFoundError
 				if errors.As(err, &notFoundError) {
@@ -146,7 +148,7 @@ func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) erro
 			return net, waitForNetworkCompleted, nil
 		}
 		// Handle standard switch port group
-		net, err = network.FromName(vimClient, name, dc, filters) // Pass the *vim25.Client
+		net, err = network.FromName(ctx, vimClient, name, dc, filters) // Pass the *vim25.Client
 		if err != nil {
 			var notFoundError *network.NotFoundError
 			if errors.As(err, &notFoundError) {
@@ -187,7 +189,7 @@ func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	net = netObj.(object.NetworkReference)
 
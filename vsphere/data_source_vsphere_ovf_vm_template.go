@@ -5,9 +5,10 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 	"reflect"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/folder"
@@ -137,8 +138,8 @@ func dataSourceVSphereOvfVMTemplate() *schema.Resource {
 	structure.MergeSchema(s, vmConfigSpecSchema)
 
 	return &schema.Resource{
-		Read:   dataSourceVSphereOvfVMTemplateRead,
-		Schema: s,
+		ReadContext: dataSourceVSphereOvfVMTemplateRead,
+		Schema:      s,
 	}
 }
 
@@ -161,17 +162,18 @@ func NewOvfHelperParamsFromVMDatasource(d *schema.ResourceData) *ovfdeploy.OvfHe
 	return ovfParams
 }
 
-func dataSourceVSphereOvfVMTemplateRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereOvfVMTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	ovfParams := NewOvfHelperParamsFromVMDatasource(d)
-	ovfHelper, err := ovfdeploy.NewOvfHelper(client, ovfParams)
+	ovfParams.TLS = meta.(*Client).TLSOptions()
+	ovfHelper, err := ovfdeploy.NewOvfHelper(ctx, client, ovfParams)
 	if err != nil {
-		return fmt.Errorf("while extracting OVF parameters: %s", err)
+		return diag.Errorf("while extracting OVF parameters: %s", err)
 	}
 
-	is, err := ovfHelper.GetImportSpec(client)
+	is, err := ovfHelper.GetImportSpec(ctx, client)
 	if err != nil {
-		return fmt.Errorf("while retrieving import spec: %s", err)
+		return diag.Errorf("while retrieving import spec: %s", err)
 	}
 
 	vmConfigSpec := is.ImportSpec.(*types.VirtualMachineImportSpec).ConfigSpec
@@ -199,21 +201,21 @@ func dataSourceVSphereOvfVMTemplateRead(d *schema.ResourceData, meta interface{}
 			if scsiType == "" {
 				scsiType = "lsilogic"
 			} else if scsiType != "lsilogic" {
-				return fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic")
+				return diag.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic")
 			}
 			controllers["scsi"]++
 		case reflect.TypeOf(&types.VirtualLsiLogicSASController{}):
 			if scsiType == "" {
 				scsiType = "lsilogic-sas"
 			} else if scsiType != "lsilogic-sas" {
-				return fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic-sas")
+				return diag.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic-sas")
 			}
 			controllers["scsi"]++
 		case reflect.TypeOf(&types.ParaVirtualSCSIController{}):
 			if scsiType == "" {
 				scsiType = "pvscsi"
 			} else if scsiType != "pvscsi" {
-				return fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "pvsci")
+				return diag.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "pvsci")
 			}
 			controllers["scsi"]++
 		case reflect.TypeOf(&types.VirtualSATAController{}):
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -18,7 +19,7 @@ import (
 // dataSourceVSphereResourcePool defines a data source for retrieving information about a vSphere resource pool.
 func dataSourceVSphereResourcePool() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereResourcePoolRead,
+		ReadContext: dataSourceVSphereResourcePoolRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -41,7 +42,7 @@ func dataSourceVSphereResourcePool() *schema.Resource {
 }
 
 // dataSourceVSphereResourcePoolRead reads details of a vSphere resource pool based on its name and parent identifiers.
-func dataSourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereResourcePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	finder := find.NewFinder(client.Client, true)
 
@@ -52,16 +53,16 @@ func dataSourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{})
 	var dc *object.Datacenter
 	if dcOk {
 		var err error
-		dc, err = datacenterFromID(client, dcID.(string))
+		dc, err = datacenterFromID(ctx, client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter %q: %s", dcID.(string), err)
+			return diag.Errorf("cannot locate datacenter %q: %s", dcID.(string), err)
 		}
 		finder.SetDatacenter(dc)
 	} else {
 		if err := viapi.ValidateVirtualCenter(client); err == nil {
-			defaultDc, err := finder.DefaultDatacenter(context.TODO())
+			defaultDc, err := finder.DefaultDatacenter(ctx)
 			if err != nil {
-				return fmt.Errorf("failed to get default datacenter: %w", err)
+				return diag.FromErr(fmt.Errorf("failed to get default datacenter: %w", err))
 			}
 			finder.SetDatacenter(defaultDc)
 		}
@@ -72,23 +73,23 @@ func dataSourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{})
 
 	if parentOk {
 		if !nameOk || name == "" {
-			return fmt.Errorf("argument 'name' is required when 'parent_resource_pool_id' is specified")
+			return diag.Errorf("argument 'name' is required when 'parent_resource_pool_id' is specified")
 		}
 
 		var err error
-		rp, err = resourcepool.FromParentAndName(client, parentID.(string), name)
+		rp, err = resourcepool.FromParentAndName(ctx, client, parentID.(string), name)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 	} else {
 		if !nameOk || name == "" {
-			return fmt.Errorf("argument 'name' is required when 'parent_resource_pool_id' is not specified")
+			return diag.Errorf("argument 'name' is required when 'parent_resource_pool_id' is not specified")
 		}
 
-		rp, err = resourcepool.FromPathOrDefault(client, name, dc)
+		rp, err = resourcepool.FromPathOrDefault(ctx, client, name, dc)
 		if err != nil {
-			return fmt.Errorf("error fetching resource pool by path %q: %s", name, err)
+			return diag.Errorf("error fetching resource pool by path %q: %s", name, err)
 		}
 	}
 
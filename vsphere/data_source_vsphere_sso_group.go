@@ -6,16 +6,16 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/ssohelper"
 )
 
 func dataSourceVSphereSSOGroup() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereSSOGroupRead,
+		ReadContext: dataSourceVSphereSSOGroupRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -75,13 +75,12 @@ func dataSourceVSphereSSOGroup() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereSSOGroupRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereSSOGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Default to the local (system) domain when none is supplied.
@@ -94,10 +93,10 @@ func dataSourceVSphereSSOGroupRead(d *schema.ResourceData, meta interface{}) err
 
 	group, err := client.FindGroup(ctx, lookup)
 	if err != nil {
-		return fmt.Errorf("error reading SSO group %q: %s", lookup, err)
+		return diag.Errorf("error reading SSO group %q: %s", lookup, err)
 	}
 	if group == nil {
-		return fmt.Errorf("SSO group %q not found", lookup)
+		return diag.Errorf("SSO group %q not found", lookup)
 	}
 
 	groupID := ssohelper.ID(group.Id.Name, group.Id.Domain)
@@ -106,13 +105,13 @@ func dataSourceVSphereSSOGroupRead(d *schema.ResourceData, meta interface{}) err
 
 	memberUsers, memberGroups, err := readSSOGroupMembers(ctx, client, groupID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("member_user", memberUsers); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("member_group", memberGroups); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(groupID)
@@ -6,16 +6,16 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/ssohelper"
 )
 
 func dataSourceVSphereSSOUser() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereSSOUserRead,
+		ReadContext: dataSourceVSphereSSOUserRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -52,13 +52,12 @@ func dataSourceVSphereSSOUser() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereSSOUserRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereSSOUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Default to the local (system) domain when none is supplied.
@@ -71,10 +70,10 @@ func dataSourceVSphereSSOUserRead(d *schema.ResourceData, meta interface{}) erro
 
 	user, err := client.FindPersonUser(ctx, lookup)
 	if err != nil {
-		return fmt.Errorf("error reading SSO user %q: %s", lookup, err)
+		return diag.Errorf("error reading SSO user %q: %s", lookup, err)
 	}
 	if user == nil {
-		return fmt.Errorf("SSO user %q not found", lookup)
+		return diag.Errorf("SSO user %q not found", lookup)
 	}
 
 	_ = d.Set("name", user.Id.Name)
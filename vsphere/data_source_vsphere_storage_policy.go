@@ -5,13 +5,15 @@
 package vsphere
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/spbm"
 )
 
 func dataSourceVSphereStoragePolicy() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereStoragePolicyRead,
+		ReadContext: dataSourceVSphereStoragePolicyRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -22,12 +24,12 @@ func dataSourceVSphereStoragePolicy() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereStoragePolicyRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereStoragePolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
-	id, err := spbm.PolicyIDByName(client, d.Get("name").(string))
+	id, err := spbm.PolicyIDByName(ctx, client, d.Get("name").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(id)
@@ -5,14 +5,15 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceVSphereTag() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereTagRead,
+		ReadContext: dataSourceVSphereTagRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -46,33 +47,33 @@ func dataSourceVSphereTag() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereTagRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereTagRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if tagID, ok := d.GetOk("id"); ok {
 		d.SetId(tagID.(string))
-		return resourceVSphereTagRead(d, meta)
+		return resourceVSphereTagRead(ctx, d, meta)
 	}
 
 	name := d.Get("name").(string)
 	categoryID := d.Get("category_id").(string)
 
 	if name == "" {
-		return fmt.Errorf("either id or name must be provided")
+		return diag.Errorf("either id or name must be provided")
 	}
 
 	if categoryID == "" {
-		return fmt.Errorf("category_id must be provided when using name lookup")
+		return diag.Errorf("category_id must be provided when using name lookup")
 	}
 
-	tagID, err := tagByName(tm, name, categoryID)
+	tagID, err := tagByName(ctx, tm, name, categoryID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(tagID)
-	return resourceVSphereTagRead(d, meta)
+	return resourceVSphereTagRead(ctx, d, meta)
 }
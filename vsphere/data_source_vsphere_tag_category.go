@@ -5,14 +5,15 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceVSphereTagCategory() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereTagCategoryRead,
+		ReadContext: dataSourceVSphereTagCategoryRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -52,28 +53,28 @@ func dataSourceVSphereTagCategory() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereTagCategoryRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereTagCategoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if id, ok := d.GetOk("id"); ok {
 		d.SetId(id.(string))
-		return resourceVSphereTagCategoryRead(d, meta)
+		return resourceVSphereTagCategoryRead(ctx, d, meta)
 	}
 
 	name := d.Get("name").(string)
 
 	if name == "" {
-		return fmt.Errorf("either id or name must be provided")
+		return diag.Errorf("either id or name must be provided")
 	}
 
-	id, err := tagCategoryByName(tm, d.Get("name").(string))
+	id, err := tagCategoryByName(ctx, tm, d.Get("name").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(id)
-	return resourceVSphereTagCategoryRead(d, meta)
+	return resourceVSphereTagCategoryRead(ctx, d, meta)
 }
@@ -5,15 +5,16 @@
 package vsphere
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/vappcontainer"
 )
 
 func dataSourceVSphereVAppContainer() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereVAppContainerRead,
+		ReadContext: dataSourceVSphereVAppContainerRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -29,18 +30,18 @@ func dataSourceVSphereVAppContainer() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereVAppContainerRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereVAppContainerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	dc, err := datacenterFromID(client, d.Get("datacenter_id").(string))
+	dc, err := datacenterFromID(ctx, client, d.Get("datacenter_id").(string))
 	if err != nil {
-		return fmt.Errorf("cannot locate datacenter: %s", err)
+		return diag.Errorf("cannot locate datacenter: %s", err)
 	}
-	vc, err := vappcontainer.FromPath(client, d.Get("name").(string), dc)
+	vc, err := vappcontainer.FromPath(ctx, client, d.Get("name").(string), dc)
 	if err != nil {
-		return fmt.Errorf("cannot locate vApp Container: %s", err)
+		return diag.Errorf("cannot locate vApp Container: %s", err)
 	}
 	d.SetId(vc.Reference().Value)
 	return nil
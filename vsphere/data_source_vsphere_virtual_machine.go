@@ -6,10 +6,10 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"path"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/object"
@@ -258,16 +258,15 @@ func dataSourceVSphereVirtualMachine() *schema.Resource {
 	// Now that the schema has been composed and merged, we can attach our reader and
 	// return the resource back to our host process.
 	return &schema.Resource{
-		Read:   dataSourceVSphereVirtualMachineRead,
-		Schema: s,
+		ReadContext: dataSourceVSphereVirtualMachineRead,
+		Schema:      s,
 	}
 }
 
-func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereVirtualMachineRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	providerClient := meta.(*Client)
 	restClient := providerClient.restClient
-	ctx := context.Background()
 	uuid := d.Get("uuid").(string)
 	moid := d.Get("moid").(string)
 	name := d.Get("name").(string)
@@ -277,17 +276,17 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 
 	if uuid != "" {
 		log.Printf("[DEBUG] Looking for VM or template by UUID %q", uuid)
-		vm, err = virtualmachine.FromUUID(client, uuid)
+		vm, err = virtualmachine.FromUUID(ctx, client, uuid)
 	} else if moid != "" {
 		log.Printf("[DEBUG] Looking for VM or template by MOID %q", moid)
-		vm, err = virtualmachine.FromMOID(client, moid)
+		vm, err = virtualmachine.FromMOID(ctx, client, moid)
 	} else {
 		log.Printf("[DEBUG] Looking for VM or template by name/path %q", name)
 		var dc *object.Datacenter
 		if dcID, ok := d.GetOk("datacenter_id"); ok {
-			dc, err = datacenterFromID(client, dcID.(string))
+			dc, err = datacenterFromID(ctx, client, dcID.(string))
 			if err != nil {
-				return fmt.Errorf("cannot locate datacenter: %s", err)
+				return diag.Errorf("cannot locate datacenter: %s", err)
 			}
 			log.Printf("[DEBUG] Datacenter for VM/template search: %s", dc.InventoryPath)
 		}
@@ -296,19 +295,19 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 		if len(folderName) > 0 {
 			searchPath = path.Join(folderName, name)
 		}
-		vm, err = virtualmachine.FromPath(client, searchPath, dc)
+		vm, err = virtualmachine.FromPath(ctx, client, searchPath, dc)
 	}
 
 	if err != nil {
-		return fmt.Errorf("error fetching virtual machine: %s", err)
+		return diag.Errorf("error fetching virtual machine: %s", err)
 	}
 
 	// Set the managed object id.
 	_ = d.Set("moid", vm.Reference().Value)
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return fmt.Errorf("error fetching virtual machine properties: %s", err)
+		return diag.Errorf("error fetching virtual machine properties: %s", err)
 	}
 
 	// Expose the VM inventory folder path as a computed attribute.
@@ -317,16 +316,16 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 	_ = d.Set("folder", path.Dir(vm.InventoryPath))
 
 	if props.Config == nil {
-		return fmt.Errorf("no configuration returned for virtual machine %q", vm.InventoryPath)
+		return diag.Errorf("no configuration returned for virtual machine %q", vm.InventoryPath)
 	}
 
 	if props.Config.Uuid == "" {
-		return fmt.Errorf("virtual machine %q does not have a UUID", vm.InventoryPath)
+		return diag.Errorf("virtual machine %q does not have a UUID", vm.InventoryPath)
 	}
 
 	// Read general VM config info
 	if err := flattenVirtualMachineConfigInfo(d, props.Config, client); err != nil {
-		return fmt.Errorf("error reading virtual machine configuration: %s", err)
+		return diag.Errorf("error reading virtual machine configuration: %s", err)
 	}
 
 	d.SetId(props.Config.Uuid)
@@ -339,35 +338,35 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 	_ = d.Set("evc_mode", props.Runtime.MinRequiredEVCModeKey)
 	disks, err := virtualdevice.ReadDiskAttrsForDataSource(props.Config.Hardware.Device, d)
 	if err != nil {
-		return fmt.Errorf("error reading disk sizes: %s", err)
+		return diag.Errorf("error reading disk sizes: %s", err)
 	}
 	nics, err := virtualdevice.ReadNetworkInterfaceTypes(props.Config.Hardware.Device)
 	if err != nil {
-		return fmt.Errorf("error reading network interface types: %s", err)
+		return diag.Errorf("error reading network interface types: %s", err)
 	}
 	networkInterfaces, err := virtualdevice.ReadNetworkInterfaces(props.Config.Hardware.Device)
 	if err != nil {
-		return fmt.Errorf("error reading network interfaces: %s", err)
+		return diag.Errorf("error reading network interfaces: %s", err)
 	}
 	videoCard, err := virtualdevice.ReadVideoCardForDataSource(props.Config.Hardware.Device)
 	if err != nil {
-		return fmt.Errorf("error reading video card: %s", err)
+		return diag.Errorf("error reading video card: %s", err)
 	}
 	if err := d.Set("disks", disks); err != nil {
-		return fmt.Errorf("error setting disk sizes: %s", err)
+		return diag.Errorf("error setting disk sizes: %s", err)
 	}
 	if err := d.Set("network_interface_types", nics); err != nil {
-		return fmt.Errorf("error setting network interface types: %s", err)
+		return diag.Errorf("error setting network interface types: %s", err)
 	}
 	if err := d.Set("network_interfaces", networkInterfaces); err != nil {
-		return fmt.Errorf("error setting network interfaces: %s", err)
+		return diag.Errorf("error setting network interfaces: %s", err)
 	}
 	if err := d.Set("video_card", videoCard); err != nil {
-		return fmt.Errorf("error setting video card: %s", err)
+		return diag.Errorf("error setting video card: %s", err)
 	}
 	if props.Guest != nil {
 		if err := buildAndSelectGuestIPs(d, *props.Guest); err != nil {
-			return fmt.Errorf("error setting guest IP addresses: %s", err)
+			return diag.Errorf("error setting guest IP addresses: %s", err)
 		}
 	}
 
@@ -389,14 +388,14 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 		[]string{"customValue"},
 		&moVM,
 	); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	customattribute.ReadFromResource(&moVM.ManagedEntity, d)
 
 	tagManager := tags.NewManager(restClient)
-	if err := readTagsForResource(tagManager, vm, d); err != nil {
-		return fmt.Errorf("error reading tags for VM: %s", err)
+	if err := readTagsForResource(ctx, tagManager, vm, d); err != nil {
+		return diag.Errorf("error reading tags for VM: %s", err)
 	}
 
 	log.Printf("[DEBUG] VM search for %q completed successfully (UUID %q)", name, props.Config.Uuid)
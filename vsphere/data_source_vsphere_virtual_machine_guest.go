@@ -0,0 +1,397 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+)
+
+// guestInfoVariablePrefix is the prefix of the VMX keys that can be read and
+// written by the guest through VMware Tools.
+const guestInfoVariablePrefix = "guestinfo."
+
+func dataSourceVSphereVirtualMachineGuest() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereVirtualMachineGuestRead,
+		Schema: map[string]*schema.Schema{
+			"uuid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The UUID of the virtual machine.",
+				ExactlyOneOf: []string{"uuid", "moid"},
+			},
+			"moid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The managed object ID of the virtual machine.",
+				ExactlyOneOf: []string{"uuid", "moid"},
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The hostname reported by the guest.",
+			},
+			"guest_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The guest ID of the running guest operating system.",
+			},
+			"guest_full_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The full name of the running guest operating system.",
+			},
+			"guest_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operation mode of the guest operating system.",
+			},
+			"guest_heartbeat_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The guest heartbeat status. One of gray, green, yellow, or red.",
+			},
+			"guest_operations_ready": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the guest is ready to accept guest operations.",
+			},
+			"tools_running_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The running status of VMware Tools.",
+			},
+			"tools_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of VMware Tools.",
+			},
+			"tools_version_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version status of VMware Tools.",
+			},
+			"default_ip_address": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The IP address selected as the primary address of the virtual machine.",
+			},
+			"guest_ip_addresses": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IP addresses reported by the guest.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"network_interface": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The network interfaces reported by the guest.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"device_key": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The device key of the virtual network adapter. -1 if the interface is not backed by a virtual network adapter.",
+						},
+						"mac_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The MAC address of the interface.",
+						},
+						"network": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the network the interface is connected to.",
+						},
+						"connected": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the interface is connected.",
+						},
+						"ip_address": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The IP addresses of the interface.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"address": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The IP address.",
+									},
+									"prefix_length": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The prefix length of the IP address.",
+									},
+									"origin": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "How the IP address was assigned.",
+									},
+									"state": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The state of the IP address.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"ip_stack": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IP stacks reported by the guest, with their DNS configuration and routes.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dns_host_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The host name of the IP stack.",
+						},
+						"dns_domain_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The domain name of the IP stack.",
+						},
+						"dns_server_addresses": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The DNS servers of the IP stack.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"dns_search_domains": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The DNS search domains of the IP stack.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"dhcp": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the DNS configuration was obtained through DHCP.",
+						},
+						"route": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The routes of the IP stack.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"network": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The destination network of the route.",
+									},
+									"prefix_length": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The prefix length of the destination network.",
+									},
+									"gateway": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The gateway of the route.",
+									},
+									"device": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The index of the interface the route uses.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"disk": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The file systems reported by the guest.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The mount point or drive letter of the file system.",
+						},
+						"capacity": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The capacity of the file system, in bytes.",
+						},
+						"free_space": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The free space of the file system, in bytes.",
+						},
+						"filesystem_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the file system.",
+						},
+					},
+				},
+			},
+			"guestinfo": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The guestinfo variables of the virtual machine, keyed without the guestinfo. prefix.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceVSphereVirtualMachineGuestRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	var vm *object.VirtualMachine
+	var err error
+	if uuid, ok := d.GetOk("uuid"); ok {
+		vm, err = virtualmachine.FromUUID(ctx, client, uuid.(string))
+	} else {
+		vm, err = virtualmachine.FromMOID(ctx, client, d.Get("moid").(string))
+	}
+	if err != nil {
+		return diag.Errorf("error fetching virtual machine: %s", err)
+	}
+	props, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return diag.Errorf("error fetching virtual machine properties: %s", err)
+	}
+	log.Printf("[DEBUG] DataVirtualMachineGuest: Reading guest information for %q", vm.InventoryPath)
+
+	d.SetId(props.Self.Value)
+	_ = d.Set("moid", props.Self.Value)
+	if props.Config != nil {
+		_ = d.Set("uuid", props.Config.Uuid)
+		_ = d.Set("guestinfo", flattenGuestInfoVariables(props.Config.ExtraConfig))
+	}
+	_ = d.Set("guest_heartbeat_status", string(props.GuestHeartbeatStatus))
+
+	guest := props.Guest
+	if guest == nil {
+		guest = &types.GuestInfo{}
+	}
+	primary, addrs := selectGuestIPs(*guest)
+	_ = d.Set("hostname", guest.HostName)
+	_ = d.Set("guest_id", guest.GuestId)
+	_ = d.Set("guest_full_name", guest.GuestFullName)
+	_ = d.Set("guest_state", guest.GuestState)
+	_ = d.Set("guest_operations_ready", structure.DeRef(guest.GuestOperationsReady))
+	_ = d.Set("tools_running_status", guest.ToolsRunningStatus)
+	_ = d.Set("tools_version", guest.ToolsVersion)
+	_ = d.Set("tools_version_status", guest.ToolsVersionStatus2)
+	_ = d.Set("default_ip_address", primary)
+	_ = d.Set("guest_ip_addresses", addrs)
+	if err := d.Set("network_interface", flattenGuestNicInfo(guest.Net)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("ip_stack", flattenGuestStackInfo(guest.IpStack)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("disk", flattenGuestDiskInfo(guest.Disk)); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// flattenGuestInfoVariables returns the guestinfo variables in the supplied
+// extra configuration, keyed without the guestinfo. prefix.
+func flattenGuestInfoVariables(extraConfig []types.BaseOptionValue) map[string]interface{} {
+	vars := make(map[string]interface{})
+	for _, bov := range extraConfig {
+		ov := bov.GetOptionValue()
+		if !strings.HasPrefix(ov.Key, guestInfoVariablePrefix) {
+			continue
+		}
+		if v, ok := ov.Value.(string); ok {
+			vars[strings.TrimPrefix(ov.Key, guestInfoVariablePrefix)] = v
+		}
+	}
+	return vars
+}
+
+func flattenGuestNicInfo(nics []types.GuestNicInfo) []interface{} {
+	result := make([]interface{}, 0, len(nics))
+	for _, nic := range nics {
+		addrs := make([]interface{}, 0)
+		if nic.IpConfig != nil {
+			for _, addr := range nic.IpConfig.IpAddress {
+				addrs = append(addrs, map[string]interface{}{
+					"address":       addr.IpAddress,
+					"prefix_length": int(addr.PrefixLength),
+					"origin":        addr.Origin,
+					"state":         addr.State,
+				})
+			}
+		} else {
+			for _, addr := range nic.IpAddress {
+				addrs = append(addrs, map[string]interface{}{
+					"address": addr,
+				})
+			}
+		}
+		result = append(result, map[string]interface{}{
+			"device_key":  int(nic.DeviceConfigId),
+			"mac_address": nic.MacAddress,
+			"network":     nic.Network,
+			"connected":   nic.Connected,
+			"ip_address":  addrs,
+		})
+	}
+	return result
+}
+
+func flattenGuestStackInfo(stacks []types.GuestStackInfo) []interface{} {
+	result := make([]interface{}, 0, len(stacks))
+	for _, stack := range stacks {
+		m := make(map[string]interface{})
+		if dns := stack.DnsConfig; dns != nil {
+			m["dns_host_name"] = dns.HostName
+			m["dns_domain_name"] = dns.DomainName
+			m["dns_server_addresses"] = dns.IpAddress
+			m["dns_search_domains"] = dns.SearchDomain
+			m["dhcp"] = dns.Dhcp
+		}
+		routes := make([]interface{}, 0)
+		if stack.IpRouteConfig != nil {
+			for _, route := range stack.IpRouteConfig.IpRoute {
+				routes = append(routes, map[string]interface{}{
+					"network":       route.Network,
+					"prefix_length": int(route.PrefixLength),
+					"gateway":       route.Gateway.IpAddress,
+					"device":        route.Gateway.Device,
+				})
+			}
+		}
+		m["route"] = routes
+		result = append(result, m)
+	}
+	return result
+}
+
+func flattenGuestDiskInfo(disks []types.GuestDiskInfo) []interface{} {
+	result := make([]interface{}, 0, len(disks))
+	for _, disk := range disks {
+		result = append(result, map[string]interface{}{
+			"path":            disk.DiskPath,
+			"capacity":        int(disk.Capacity),
+			"free_space":      int(disk.FreeSpace),
+			"filesystem_type": disk.FilesystemType,
+		})
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceVSphereVirtualMachineGuest_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereVirtualMachineGuestConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_virtual_machine_guest.guest", "uuid",
+						"vsphere_virtual_machine.vm", "uuid",
+					),
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machine_guest.guest", "guestinfo.testacc", "value"),
+					resource.TestCheckResourceAttrSet("data.vsphere_virtual_machine_guest.guest", "tools_running_status"),
+					resource.TestCheckResourceAttrSet("data.vsphere_virtual_machine_guest.guest", "guest_heartbeat_status"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereVirtualMachineGuestConfig() string {
+	return fmt.Sprintf(`
+%s
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 1024
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label = "disk0"
+    size  = 1
+  }
+
+  extra_config = {
+    "guestinfo.testacc" = "value"
+  }
+}
+
+data "vsphere_virtual_machine_guest" "guest" {
+  moid = vsphere_virtual_machine.vm.moid
+}
+`,
+		testAccResourceVSphereVirtualMachineConfigBase(),
+	)
+}
@@ -0,0 +1,134 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+)
+
+func dataSourceVSphereVirtualMachineSnapshots() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereVirtualMachineSnapshotsRead,
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_uuid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The UUID of the virtual machine.",
+			},
+			"current_snapshot_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The managed object ID of the current snapshot of the virtual machine.",
+			},
+			"snapshots": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The snapshots of the virtual machine, in depth-first order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The managed object ID of the snapshot.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the snapshot.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The description of the snapshot.",
+						},
+						"parent_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The managed object ID of the parent snapshot, if any.",
+						},
+						"child_ids": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The managed object IDs of the child snapshots.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"create_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time the snapshot was created.",
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The size of the files that make up the snapshot, in bytes.",
+						},
+						"quiesced": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the guest file system was quiesced when the snapshot was taken.",
+						},
+						"power_state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The power state of the virtual machine when the snapshot was taken.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVSphereVirtualMachineSnapshotsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	uuid := d.Get("virtual_machine_uuid").(string)
+	vm, err := virtualmachine.FromUUID(ctx, client, uuid)
+	if err != nil {
+		return diag.Errorf("error fetching virtual machine: %s", err)
+	}
+	props, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return diag.Errorf("error fetching virtual machine properties: %s", err)
+	}
+
+	var currentID string
+	if props.Snapshot != nil && props.Snapshot.CurrentSnapshot != nil {
+		currentID = props.Snapshot.CurrentSnapshot.Value
+	}
+	var snapshots []interface{}
+	for _, node := range virtualmachine.Snapshots(props) {
+		var parentID string
+		if node.Parent != nil {
+			parentID = node.Parent.Snapshot.Value
+		}
+		childIDs := make([]interface{}, 0, len(node.Tree.ChildSnapshotList))
+		for _, child := range node.Tree.ChildSnapshotList {
+			childIDs = append(childIDs, child.Snapshot.Value)
+		}
+		snapshots = append(snapshots, map[string]interface{}{
+			"id":          node.Tree.Snapshot.Value,
+			"name":        node.Tree.Name,
+			"description": node.Tree.Description,
+			"parent_id":   parentID,
+			"child_ids":   childIDs,
+			"create_time": node.Tree.CreateTime.Format(time.RFC3339),
+			"size":        int(virtualmachine.SnapshotSize(props, node)),
+			"quiesced":    node.Tree.Quiesced,
+			"power_state": string(node.Tree.State),
+		})
+	}
+
+	d.SetId(uuid)
+	_ = d.Set("current_snapshot_id", currentID)
+	if err := d.Set("snapshots", snapshots); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceVSphereVirtualMachineSnapshots_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereVirtualMachineSnapshotsConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machine_snapshots.snapshots", "snapshots.#", "2"),
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_virtual_machine_snapshots.snapshots", "current_snapshot_id",
+						"vsphere_virtual_machine_snapshot.child", "id",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_virtual_machine_snapshots.snapshots", "snapshots.0.id",
+						"vsphere_virtual_machine_snapshot.parent", "id",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_virtual_machine_snapshots.snapshots", "snapshots.1.parent_id",
+						"vsphere_virtual_machine_snapshot.parent", "id",
+					),
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machine_snapshots.snapshots", "snapshots.0.child_ids.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereVirtualMachineSnapshotsConfig() string {
+	return fmt.Sprintf(`
+%s
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 1024
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label = "disk0"
+    size  = 1
+  }
+}
+
+resource "vsphere_virtual_machine_snapshot" "parent" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm.uuid
+  snapshot_name        = "terraform-test-parent"
+  description          = "Managed by Terraform"
+  memory               = false
+  quiesce              = false
+}
+
+resource "vsphere_virtual_machine_snapshot" "child" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm.uuid
+  snapshot_name        = "terraform-test-child"
+  description          = "Managed by Terraform"
+  memory               = false
+  quiesce              = false
+
+  depends_on = [vsphere_virtual_machine_snapshot.parent]
+}
+
+data "vsphere_virtual_machine_snapshots" "snapshots" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm.uuid
+
+  depends_on = [vsphere_virtual_machine_snapshot.child]
+}
+`,
+		testAccResourceVSphereVirtualMachineConfigBase(),
+	)
+}
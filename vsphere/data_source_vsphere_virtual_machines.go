@@ -0,0 +1,410 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+// virtualMachinesPowerStates maps the power_state values accepted by the
+// vsphere_virtual_machines data source to virtual machine power states.
+var virtualMachinesPowerStates = map[string]types.VirtualMachinePowerState{
+	"on":        types.VirtualMachinePowerStatePoweredOn,
+	"off":       types.VirtualMachinePowerStatePoweredOff,
+	"suspended": types.VirtualMachinePowerStateSuspended,
+}
+
+// virtualMachinesProperties are the properties retrieved for every virtual
+// machine in the search container.
+var virtualMachinesProperties = []string{
+	"name",
+	"config.uuid",
+	"config.template",
+	"config.guestId",
+	"runtime.powerState",
+	"guest.ipAddress",
+	"guest.net",
+	"guest.ipStack",
+	"customValue",
+}
+
+func dataSourceVSphereVirtualMachines() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereVirtualMachinesRead,
+		Schema: map[string]*schema.Schema{
+			"datacenter_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The managed object ID of the datacenter to search. Searches the whole inventory if not set.",
+			},
+			"folder_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The managed object ID of a virtual machine folder. Only virtual machines in this folder or its subfolders are returned.",
+			},
+			"cluster_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The managed object ID of a cluster. Only virtual machines in this cluster are returned.",
+				ConflictsWith: []string{"resource_pool_id"},
+			},
+			"resource_pool_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The managed object ID of a resource pool or vApp. Only virtual machines in this resource pool or its child resource pools are returned.",
+				ConflictsWith: []string{"cluster_id"},
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "A regular expression that virtual machine names must match.",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "A list of tag IDs. Only virtual machines that have all of these tags are returned.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"custom_attributes": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "A map of custom attribute IDs to values. Only virtual machines with all of these values are returned.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"power_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only return virtual machines in this power state. One of on, off, or suspended.",
+				ValidateFunc: validation.StringInSlice([]string{"on", "off", "suspended"}, false),
+			},
+			"guest_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return virtual machines with this guest ID.",
+			},
+			"include_templates": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Include templates in the results.",
+			},
+			"virtual_machines": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The virtual machines that match the filters, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The managed object ID of the virtual machine.",
+						},
+						"uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The UUID of the virtual machine.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the virtual machine.",
+						},
+						"power_state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The power state of the virtual machine.",
+						},
+						"guest_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The guest ID of the virtual machine.",
+						},
+						"template": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the virtual machine is a template.",
+						},
+						"default_ip_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The IP address selected as the primary address of the virtual machine.",
+						},
+						"guest_ip_addresses": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The IP addresses of the virtual machine.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"tags": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The IDs of the tags attached to the virtual machine.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVSphereVirtualMachinesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	log.Printf("[DEBUG] DataVirtualMachines: Retrieving virtual machines")
+	vms, err := dataSourceVSphereVirtualMachinesRetrieve(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var re *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		re = regexp.MustCompile(v.(string))
+	}
+	powerState := virtualMachinesPowerStates[d.Get("power_state").(string)]
+	guestID := d.Get("guest_id").(string)
+	includeTemplates := d.Get("include_templates").(bool)
+	customAttrs := d.Get("custom_attributes").(map[string]interface{})
+
+	var tagged map[string]bool
+	tagIDs := structure.SliceInterfacesToStrings(d.Get("tags").(*schema.Set).List())
+	if len(tagIDs) > 0 {
+		if tagged, err = virtualMachinesWithTags(ctx, meta, tagIDs); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var matches []mo.VirtualMachine
+	for _, vm := range vms {
+		switch {
+		case vm.Config == nil:
+			// The virtual machine is inaccessible or still being created.
+			continue
+		case vm.Config.Template && !includeTemplates:
+			continue
+		case re != nil && !re.MatchString(vm.Name):
+			continue
+		case powerState != "" && vm.Runtime.PowerState != powerState:
+			continue
+		case guestID != "" && vm.Config.GuestId != guestID:
+			continue
+		case tagged != nil && !tagged[vm.Self.Value]:
+			continue
+		case !virtualMachineHasCustomAttributes(vm, customAttrs):
+			continue
+		}
+		matches = append(matches, vm)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Name < matches[j].Name
+	})
+
+	attached := make(map[string][]string)
+	if len(matches) > 0 {
+		if tm, err := meta.(*Client).TagsManager(); err == nil {
+			refs := make([]mo.Reference, len(matches))
+			for i := range matches {
+				refs[i] = matches[i].Self
+			}
+			tctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+			defer cancel()
+			res, err := tm.ListAttachedTagsOnObjects(tctx, refs)
+			if err != nil {
+				return diag.Errorf("error listing tags for virtual machines: %s", err)
+			}
+			for _, r := range res {
+				attached[r.ObjectID.Reference().Value] = r.TagIDs
+			}
+		} else {
+			log.Printf("[DEBUG] DataVirtualMachines: Tags are not available on %s, skipping", client.URL().Host)
+		}
+	}
+
+	var result []interface{}
+	for _, vm := range matches {
+		var primary string
+		addrs := make([]string, 0)
+		if vm.Guest != nil {
+			primary, addrs = selectGuestIPs(*vm.Guest)
+		}
+		result = append(result, map[string]interface{}{
+			"id":                 vm.Self.Value,
+			"uuid":               vm.Config.Uuid,
+			"name":               vm.Name,
+			"power_state":        virtualMachinePowerStateString(vm.Runtime.PowerState),
+			"guest_id":           vm.Config.GuestId,
+			"template":           vm.Config.Template,
+			"default_ip_address": primary,
+			"guest_ip_addresses": addrs,
+			"tags":               attached[vm.Self.Value],
+		})
+	}
+
+	idsum := sha256.New()
+	for _, k := range []string{"datacenter_id", "folder_id", "cluster_id", "resource_pool_id", "name_regex", "power_state", "guest_id"} {
+		if _, err := fmt.Fprintf(idsum, "%s=%q;", k, d.Get(k).(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if _, err := fmt.Fprintf(idsum, "%v;%v;%t", tagIDs, customAttrs, includeTemplates); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%x", idsum.Sum(nil)))
+
+	if err := d.Set("virtual_machines", result); err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[DEBUG] DataVirtualMachines: Found %d matching virtual machines", len(result))
+	return nil
+}
+
+// dataSourceVSphereVirtualMachinesRetrieve fetches the properties of all
+// virtual machines in the search container with a single property collector
+// retrieve. When both a folder and a cluster or resource pool are set, the
+// virtual machines in the folder are retrieved and limited to those that are
+// also in the cluster or resource pool.
+func dataSourceVSphereVirtualMachinesRetrieve(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]mo.VirtualMachine, error) {
+	client := meta.(*Client).vimClient
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+
+	var compute *types.ManagedObjectReference
+	if v, ok := d.GetOk("cluster_id"); ok {
+		compute = &types.ManagedObjectReference{Type: "ClusterComputeResource", Value: v.(string)}
+	}
+	if v, ok := d.GetOk("resource_pool_id"); ok {
+		compute = &types.ManagedObjectReference{Type: "ResourcePool", Value: v.(string)}
+	}
+	container := client.ServiceContent.RootFolder
+	if v, ok := d.GetOk("datacenter_id"); ok {
+		container = types.ManagedObjectReference{Type: "Datacenter", Value: v.(string)}
+	}
+	if v, ok := d.GetOk("folder_id"); ok {
+		container = types.ManagedObjectReference{Type: "Folder", Value: v.(string)}
+	} else if compute != nil {
+		container = *compute
+		compute = nil
+	}
+
+	m := view.NewManager(client.Client)
+	cv, err := m.CreateContainerView(ctx, container, []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, fmt.Errorf("error creating container view for %s %q: %s", container.Type, container.Value, err)
+	}
+	defer func() {
+		if err := cv.Destroy(ctx); err != nil {
+			log.Printf("[WARN] Error destroying container view during cleanup: %s", err)
+		}
+	}()
+	var vms []mo.VirtualMachine
+	if err := cv.Retrieve(ctx, []string{"VirtualMachine"}, virtualMachinesProperties, &vms); err != nil {
+		return nil, fmt.Errorf("error retrieving virtual machines: %s", err)
+	}
+	if compute == nil {
+		return vms, nil
+	}
+
+	ccv, err := m.CreateContainerView(ctx, *compute, []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, fmt.Errorf("error creating container view for %s %q: %s", compute.Type, compute.Value, err)
+	}
+	defer func() {
+		if err := ccv.Destroy(ctx); err != nil {
+			log.Printf("[WARN] Error destroying container view during cleanup: %s", err)
+		}
+	}()
+	refs, err := ccv.Find(ctx, []string{"VirtualMachine"}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error listing virtual machines in %s %q: %s", compute.Type, compute.Value, err)
+	}
+	in := make(map[string]bool)
+	for _, ref := range refs {
+		in[ref.Value] = true
+	}
+	var filtered []mo.VirtualMachine
+	for _, vm := range vms {
+		if in[vm.Self.Value] {
+			filtered = append(filtered, vm)
+		}
+	}
+	return filtered, nil
+}
+
+// virtualMachinesWithTags returns the managed object IDs of the virtual
+// machines that have all of the supplied tags attached.
+func virtualMachinesWithTags(ctx context.Context, meta interface{}, tagIDs []string) (map[string]bool, error) {
+	tm, err := meta.(*Client).TagsManager()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	res, err := tm.ListAttachedObjectsOnTags(ctx, tagIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error listing objects attached to tags: %s", err)
+	}
+	counts := make(map[string]int)
+	for _, r := range res {
+		seen := make(map[string]bool)
+		for _, obj := range r.ObjectIDs {
+			ref := obj.Reference()
+			if ref.Type != vSphereTagTypeVirtualMachine || seen[ref.Value] {
+				continue
+			}
+			seen[ref.Value] = true
+			counts[ref.Value]++
+		}
+	}
+	tagged := make(map[string]bool)
+	for id, n := range counts {
+		if n == len(tagIDs) {
+			tagged[id] = true
+		}
+	}
+	return tagged, nil
+}
+
+// virtualMachineHasCustomAttributes returns true if the virtual machine has
+// all of the supplied custom attribute values, keyed by custom attribute ID.
+func virtualMachineHasCustomAttributes(vm mo.VirtualMachine, attrs map[string]interface{}) bool {
+	values := make(map[string]string)
+	for _, fv := range vm.CustomValue {
+		if sv, ok := fv.(*types.CustomFieldStringValue); ok {
+			values[strconv.Itoa(int(sv.Key))] = sv.Value
+		}
+	}
+	for k, v := range attrs {
+		if values[k] != v.(string) {
+			return false
+		}
+	}
+	return true
+}
+
+// virtualMachinePowerStateString returns the power state of a virtual machine
+// in the form used by the power_state attribute of vsphere_virtual_machine.
+func virtualMachinePowerStateString(state types.VirtualMachinePowerState) string {
+	for k, v := range virtualMachinesPowerStates {
+		if v == state {
+			return k
+		}
+	}
+	return string(state)
+}
@@ -0,0 +1,103 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceVSphereVirtualMachines_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereVirtualMachinesConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machines.tagged", "virtual_machines.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_virtual_machines.tagged", "virtual_machines.0.uuid",
+						"vsphere_virtual_machine.vm", "uuid",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.vsphere_virtual_machines.tagged", "virtual_machines.0.id",
+						"vsphere_virtual_machine.vm", "moid",
+					),
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machines.tagged", "virtual_machines.0.tags.#", "1"),
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machines.tagged", "virtual_machines.0.power_state", "on"),
+					resource.TestCheckResourceAttr("data.vsphere_virtual_machines.off", "virtual_machines.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereVirtualMachinesConfig() string {
+	return fmt.Sprintf(`
+%s
+
+resource "vsphere_tag_category" "category" {
+  name        = "testacc-vms-category"
+  cardinality = "MULTIPLE"
+
+  associable_types = [
+    "VirtualMachine",
+  ]
+}
+
+resource "vsphere_tag" "tag" {
+  name        = "testacc-vms-tag"
+  category_id = vsphere_tag_category.category.id
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 1024
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label = "disk0"
+    size  = 1
+  }
+
+  tags = [vsphere_tag.tag.id]
+}
+
+data "vsphere_virtual_machines" "tagged" {
+  datacenter_id    = data.vsphere_datacenter.rootdc1.id
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  name_regex       = "^testacc-"
+  guest_id         = "other3xLinuxGuest"
+  tags             = [vsphere_tag.tag.id]
+
+  depends_on = [vsphere_virtual_machine.vm]
+}
+
+data "vsphere_virtual_machines" "off" {
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  power_state      = "off"
+
+  depends_on = [vsphere_virtual_machine.vm]
+}
+`,
+		testAccResourceVSphereVirtualMachineConfigBase(),
+	)
+}
@@ -20,23 +20,23 @@ import (
 //
 // The default datacenter is denoted by using an empty string. When working
 // with ESXi directly, the default datacenter is always selected.
-func getDatacenter(c *govmomi.Client, dc string) (*object.Datacenter, error) {
+func getDatacenter(ctx context.Context, c *govmomi.Client, dc string) (*object.Datacenter, error) {
 	finder := find.NewFinder(c.Client, true)
 	t := c.ServiceContent.About.ApiType
 	switch t {
 	case "HostAgent":
-		return finder.DefaultDatacenter(context.TODO())
+		return finder.DefaultDatacenter(ctx)
 	case "VirtualCenter":
 		if dc != "" {
-			return finder.Datacenter(context.TODO(), dc)
+			return finder.Datacenter(ctx, dc)
 		}
-		return finder.DefaultDatacenter(context.TODO())
+		return finder.DefaultDatacenter(ctx)
 	}
 	return nil, fmt.Errorf("unsupported ApiType: %s", t)
 }
 
 // datacenterFromID locates a Datacenter by its managed object reference ID.
-func datacenterFromID(client *govmomi.Client, id string) (*object.Datacenter, error) {
+func datacenterFromID(ctx context.Context, client *govmomi.Client, id string) (*object.Datacenter, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -44,7 +44,7 @@ func datacenterFromID(client *govmomi.Client, id string) (*object.Datacenter, er
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	ds, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -53,8 +53,8 @@ func datacenterFromID(client *govmomi.Client, id string) (*object.Datacenter, er
 	return ds.(*object.Datacenter), nil
 }
 
-func datacenterCustomAttributes(dc *object.Datacenter) (*mo.Datacenter, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func datacenterCustomAttributes(ctx context.Context, dc *object.Datacenter) (*mo.Datacenter, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	var props mo.Datacenter
 	if err := dc.Properties(ctx, dc.Reference(), []string{"customValue"}, &props); err != nil {
@@ -63,12 +63,11 @@ func datacenterCustomAttributes(dc *object.Datacenter) (*mo.Datacenter, error) {
 	return &props, nil
 }
 
-func listDatacenters(client *govmomi.Client) ([]*object.Datacenter, error) {
-	return dcsByPath(client, "/")
+func listDatacenters(ctx context.Context, client *govmomi.Client) ([]*object.Datacenter, error) {
+	return dcsByPath(ctx, client, "/")
 }
 
-func dcsByPath(client *govmomi.Client, path string) ([]*object.Datacenter, error) {
-	ctx := context.TODO()
+func dcsByPath(ctx context.Context, client *govmomi.Client, path string) ([]*object.Datacenter, error) {
 	var dcs []*object.Datacenter
 	finder := find.NewFinder(client.Client, false)
 	if path != "/" {
@@ -81,13 +80,13 @@ func dcsByPath(client *govmomi.Client, path string) ([]*object.Datacenter, error
 	for _, id := range es {
 		switch {
 		case id.Object.Reference().Type == "Datacenter":
-			dc, err := datacenterFromID(client, id.Object.Reference().Value)
+			dc, err := datacenterFromID(ctx, client, id.Object.Reference().Value)
 			if err != nil {
 				return nil, err
 			}
 			dcs = append(dcs, dc)
 		case id.Object.Reference().Type == "Folder":
-			newDCs, err := dcsByPath(client, id.Path)
+			newDCs, err := dcsByPath(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -83,7 +84,7 @@ func flattenDatastoreSummary(d *schema.ResourceData, obj *types.DatastoreSummary
 // resourceVSphereDatastoreApplyFolderOrStorageClusterPath returns a path to a
 // folder or a datastore cluster, depending on what has been selected in the
 // resource.
-func resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d *schema.ResourceData, meta interface{}) (string, error) {
+func resourceVSphereDatastoreApplyFolderOrStorageClusterPath(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, error) {
 	var path string
 	fvalue, fok := d.GetOk("folder")
 	cvalue, cok := d.GetOk("datastore_cluster_id")
@@ -91,14 +92,14 @@ func resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d *schema.ResourceD
 	case fok:
 		path = fvalue.(string)
 	case cok:
-		return resourceVSphereDatastoreStorageClusterPathNormalized(meta, cvalue.(string))
+		return resourceVSphereDatastoreStorageClusterPathNormalized(ctx, meta, cvalue.(string))
 	}
 	return path, nil
 }
 
-func resourceVSphereDatastoreStorageClusterPathNormalized(meta interface{}, id string) (string, error) {
+func resourceVSphereDatastoreStorageClusterPathNormalized(ctx context.Context, meta interface{}, id string) (string, error) {
 	client := meta.(*Client).vimClient
-	pod, err := storagepod.FromID(client, id)
+	pod, err := storagepod.FromID(ctx, client, id)
 	if err != nil {
 		return "", err
 	}
@@ -108,8 +109,8 @@ func resourceVSphereDatastoreStorageClusterPathNormalized(meta interface{}, id s
 // resourceVSphereDatastoreReadFolderOrStorageClusterPath checks the inventory
 // path of the supplied datastore and checks to see if it is a normal folder or
 // if it's a datastore cluster, and saves the attributes accordingly.
-func resourceVSphereDatastoreReadFolderOrStorageClusterPath(d *schema.ResourceData, ds *object.Datastore) error {
-	props, err := datastore.Properties(ds)
+func resourceVSphereDatastoreReadFolderOrStorageClusterPath(ctx context.Context, d *schema.ResourceData, ds *object.Datastore) error {
+	props, err := datastore.Properties(ctx, ds)
 	if err != nil {
 		return fmt.Errorf("error fetching datastore properties while parsing path: %s", err)
 	}
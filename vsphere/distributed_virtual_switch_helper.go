@@ -15,6 +15,7 @@ import (
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/network"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 var dvsVersions = []string{
@@ -29,22 +30,22 @@ var dvsVersions = []string{
 }
 
 // dvsFromUUID gets a DVS object from its UUID.
-func dvsFromUUID(client *govmomi.Client, uuid string) (*object.VmwareDistributedVirtualSwitch, error) {
+func dvsFromUUID(ctx context.Context, client *govmomi.Client, uuid string) (*object.VmwareDistributedVirtualSwitch, error) {
 	dvsm := types.ManagedObjectReference{Type: "DistributedVirtualSwitchManager", Value: "DVSManager"}
 	req := &types.QueryDvsByUuid{
 		This: dvsm,
 		Uuid: uuid,
 	}
-	resp, err := methods.QueryDvsByUuid(context.TODO(), client, req)
+	resp, err := methods.QueryDvsByUuid(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
 
-	return dvsFromMOID(client, resp.Returnval.Reference().Value)
+	return dvsFromMOID(ctx, client, resp.Returnval.Reference().Value)
 }
 
 // dvsFromMOID locates a DVS by its managed object reference ID.
-func dvsFromMOID(client *govmomi.Client, id string) (*object.VmwareDistributedVirtualSwitch, error) {
+func dvsFromMOID(ctx context.Context, client *govmomi.Client, id string) (*object.VmwareDistributedVirtualSwitch, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -52,7 +53,7 @@ func dvsFromMOID(client *govmomi.Client, id string) (*object.VmwareDistributedVi
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	ds, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -65,21 +66,21 @@ func dvsFromMOID(client *govmomi.Client, id string) (*object.VmwareDistributedVi
 }
 
 // dvsFromPath gets a DVS object from its path.
-func dvsFromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*object.VmwareDistributedVirtualSwitch, error) {
-	net, err := network.FromPath(client, name, dc)
+func dvsFromPath(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.VmwareDistributedVirtualSwitch, error) {
+	net, err := network.FromPath(ctx, client, name, dc)
 	if err != nil {
 		return nil, err
 	}
 	if net.Reference().Type != "VmwareDistributedVirtualSwitch" {
 		return nil, fmt.Errorf("network at path %q is not a VMware distributed virtual switch (type %s)", name, net.Reference().Type)
 	}
-	return dvsFromMOID(client, net.Reference().Value)
+	return dvsFromMOID(ctx, client, net.Reference().Value)
 }
 
 // dvsProperties is a convenience method that wraps fetching the DVS MO from
 // its higher-level object.
-func dvsProperties(dvs *object.VmwareDistributedVirtualSwitch) (*mo.VmwareDistributedVirtualSwitch, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func dvsProperties(ctx context.Context, dvs *object.VmwareDistributedVirtualSwitch) (*mo.VmwareDistributedVirtualSwitch, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	var props mo.VmwareDistributedVirtualSwitch
 	if err := dvs.Properties(ctx, dvs.Reference(), nil, &props); err != nil {
@@ -91,7 +92,7 @@ func dvsProperties(dvs *object.VmwareDistributedVirtualSwitch) (*mo.VmwareDistri
 // upgradeDVS upgrades a DVS to a specific version. Downgrades are not
 // supported and will result in an error. This should be checked before running
 // this function.
-func upgradeDVS(client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, version string) error {
+func upgradeDVS(ctx context.Context, client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, version string) error {
 	req := &types.PerformDvsProductSpecOperation_Task{
 		This:      dvs.Reference(),
 		Operation: "upgrade",
@@ -100,42 +101,42 @@ func upgradeDVS(client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwit
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	resp, err := methods.PerformDvsProductSpecOperation_Task(ctx, client, req)
 	if err != nil {
 		return err
 	}
 	task := object.NewTask(client.Client, resp.Returnval)
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	return task.WaitEx(tctx)
+	return viapi.WaitForTask(tctx, task)
 }
 
 // updateDVSConfiguration contains the atomic update/wait operation for a DVS.
-func updateDVSConfiguration(dvs *object.VmwareDistributedVirtualSwitch, spec *types.VMwareDVSConfigSpec) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func updateDVSConfiguration(ctx context.Context, dvs *object.VmwareDistributedVirtualSwitch, spec *types.VMwareDVSConfigSpec) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := dvs.Reconfigure(ctx, spec)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	return task.WaitEx(tctx)
+	return viapi.WaitForTask(tctx, task)
 }
 
 // enableDVSNetworkResourceManagement exposes the
 // EnableNetworkResourceManagement method of the DistributedVirtualSwitch MO.
 // This local implementation may go away if this is exposed in the higher-level
 // object upstream.
-func enableDVSNetworkResourceManagement(client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, enabled bool) error {
+func enableDVSNetworkResourceManagement(ctx context.Context, client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, enabled bool) error {
 	req := &types.EnableNetworkResourceManagement{
 		This:   dvs.Reference(),
 		Enable: enabled,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	_, err := methods.EnableNetworkResourceManagement(ctx, client, req)
 	if err != nil {
@@ -145,9 +146,9 @@ func enableDVSNetworkResourceManagement(client *govmomi.Client, dvs *object.Vmwa
 	return nil
 }
 
-func updateDVSPvlanMappings(dvs *object.VmwareDistributedVirtualSwitch, pvlanConfig []types.VMwareDVSPvlanConfigSpec) error {
+func updateDVSPvlanMappings(ctx context.Context, dvs *object.VmwareDistributedVirtualSwitch, pvlanConfig []types.VMwareDVSPvlanConfigSpec) error {
 	// Load current properties, required to get the 'config version' to provide back when updating
-	props, err := dvsProperties(dvs)
+	props, err := dvsProperties(ctx, dvs)
 	if err != nil {
 		return fmt.Errorf("cannot read properties of distributed_virtual_switch: %s", err)
 	}
@@ -161,7 +162,7 @@ func updateDVSPvlanMappings(dvs *object.VmwareDistributedVirtualSwitch, pvlanCon
 	}
 
 	// Start ReconfigureDvs_Task
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := dvs.Reconfigure(ctx, &updateSpec)
 	if err != nil {
@@ -169,7 +170,7 @@ func updateDVSPvlanMappings(dvs *object.VmwareDistributedVirtualSwitch, pvlanCon
 	}
 
 	// Wait for ReconfigureDvs_Task to finish
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
 	err = task.Wait(tctx)
 	if err != nil {
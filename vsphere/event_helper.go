@@ -55,12 +55,12 @@ func (w *virtualMachineCustomizationWaiter) Err() error {
 //
 // The timeout value is in minutes - a value of less than 1 disables the waiter
 // and returns immediately without error.
-func newVirtualMachineCustomizationWaiter(client *govmomi.Client, vm *object.VirtualMachine, timeout int) *virtualMachineCustomizationWaiter {
+func newVirtualMachineCustomizationWaiter(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int) *virtualMachineCustomizationWaiter {
 	w := &virtualMachineCustomizationWaiter{
 		done: make(chan struct{}),
 	}
 	go func() {
-		w.err = w.wait(client, vm, timeout)
+		w.err = w.wait(ctx, client, vm, timeout)
 		close(w.done)
 	}()
 	return w
@@ -71,7 +71,7 @@ func newVirtualMachineCustomizationWaiter(client *govmomi.Client, vm *object.Vir
 // CustomizationSucceeded and CustomizationFailed events. If the customization
 // failed due to some sort of error, the full formatted message is returned as
 // an error.
-func (w *virtualMachineCustomizationWaiter) wait(client *govmomi.Client, vm *object.VirtualMachine, timeout int) error {
+func (w *virtualMachineCustomizationWaiter) wait(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int) error {
 	// A timeout of less than 1 minute (zero or negative value) skips the waiter,
 	// so we return immediately.
 	if timeout < 1 {
@@ -97,7 +97,7 @@ func (w *virtualMachineCustomizationWaiter) wait(client *govmomi.Client, vm *obj
 	// Make a proper background context so that we can gracefully cancel the
 	// subscriber when we are done with it. This eventually gets passed down to
 	// the property collector SOAP calls.
-	pctx, pcancel := context.WithCancel(context.Background())
+	pctx, pcancel := context.WithCancel(ctx)
 	defer pcancel()
 	go func() {
 		mgrErr <- mgr.Events(pctx, []types.ManagedObjectReference{vm.Reference()}, 10, true, false, cb)
@@ -107,13 +107,15 @@ func (w *virtualMachineCustomizationWaiter) wait(client *govmomi.Client, vm *obj
 	// callback error channel on success). We also use a different context so
 	// that we can give a better error message on timeout without interfering
 	// with the subscriber's context.
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Minute)
 	defer cancel()
 	var err error
 	select {
 	case <-ctx.Done():
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			err = fmt.Errorf("timeout waiting for customization to complete")
+		} else {
+			err = ctx.Err()
 		}
 	case err = <-mgrErr:
 	case err = <-cbErr:
@@ -127,8 +129,8 @@ func (w *virtualMachineCustomizationWaiter) wait(client *govmomi.Client, vm *obj
 // Event types can be supplied to this function via the eventTypes parameter.
 // This is highly recommended when you expect the list of events to be large,
 // as there is no limit on returned events.
-func selectEventsForReference(client *govmomi.Client, ref types.ManagedObjectReference, eventTypes []string) ([]types.BaseEvent, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func selectEventsForReference(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference, eventTypes []string) ([]types.BaseEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	filter := types.EventFilterSpec{
 		Entity: &types.EventFilterSpecByEntity{
@@ -169,12 +169,12 @@ func testGetPortGroup(s *terraform.State, resourceName string) (*types.HostPortG
 	if err != nil {
 		return nil, err
 	}
-	ns, err := hostNetworkSystemFromHostSystemID(tVars.client, hsID)
+	ns, err := hostNetworkSystemFromHostSystemID(context.Background(), tVars.client, hsID)
 	if err != nil {
 		return nil, fmt.Errorf("error loading host network system: %s", err)
 	}
 
-	return hostPortGroupFromName(tVars.client, ns, name)
+	return hostPortGroupFromName(context.Background(), tVars.client, ns, name)
 }
 
 // testGetVirtualMachine is a convenience method to fetch a virtual machine by
@@ -188,7 +188,7 @@ func testGetVirtualMachine(s *terraform.State, resourceName string) (*object.Vir
 	if !ok {
 		return nil, fmt.Errorf("resource %q has no UUID", resourceName)
 	}
-	return virtualmachine.FromUUID(tVars.client, uuid)
+	return virtualmachine.FromUUID(context.Background(), tVars.client, uuid)
 }
 
 // testGetVirtualMachineProperties is a convenience method that adds an extra
@@ -198,7 +198,7 @@ func testGetVirtualMachineProperties(s *terraform.State, resourceName string) (*
 	if err != nil {
 		return nil, err
 	}
-	return virtualmachine.Properties(vm)
+	return virtualmachine.Properties(context.Background(), vm)
 }
 
 // testGetVirtualMachineHost returns the HostSystem for the host that this
@@ -212,7 +212,7 @@ func testGetVirtualMachineHost(s *terraform.State, resourceName string) (*object
 	if err != nil {
 		return nil, err
 	}
-	return hostsystem.FromID(tVars.client, vprops.Runtime.Host.Value)
+	return hostsystem.FromID(context.Background(), tVars.client, vprops.Runtime.Host.Value)
 }
 
 // testGetVirtualMachineResourcePool returns the ResourcePool object for the
@@ -226,7 +226,7 @@ func testGetVirtualMachineResourcePool(s *terraform.State, resourceName string)
 	if err != nil {
 		return nil, err
 	}
-	return resourcepool.FromID(tVars.client, vprops.ResourcePool.Value)
+	return resourcepool.FromID(context.Background(), tVars.client, vprops.ResourcePool.Value)
 }
 
 // testGetVirtualMachineSCSIBusType reads the SCSI bus type for the supplied
@@ -276,7 +276,7 @@ func testGetDatacenter(s *terraform.State, resourceName string) (*object.Datacen
 	if !ok {
 		return nil, fmt.Errorf("datacenter resource %q has no name", resourceName)
 	}
-	return getDatacenter(tVars.client, dcName)
+	return getDatacenter(context.Background(), tVars.client, dcName)
 }
 
 func testGetResourcePool(s *terraform.State, resourceName string) (*object.ResourcePool, error) {
@@ -284,7 +284,7 @@ func testGetResourcePool(s *terraform.State, resourceName string) (*object.Resou
 	if err != nil {
 		return nil, err
 	}
-	return resourcepool.FromID(vars.client, vars.resourceID)
+	return resourcepool.FromID(context.Background(), vars.client, vars.resourceID)
 }
 
 func testGetResourcePoolProperties(s *terraform.State, resourceName string) (*mo.ResourcePool, error) {
@@ -292,7 +292,7 @@ func testGetResourcePoolProperties(s *terraform.State, resourceName string) (*mo
 	if err != nil {
 		return nil, err
 	}
-	return resourcepool.Properties(rp)
+	return resourcepool.Properties(context.Background(), rp)
 }
 
 func testGetDatacenterCustomAttributes(s *terraform.State, resourceName string) (*mo.Datacenter, error) {
@@ -300,7 +300,7 @@ func testGetDatacenterCustomAttributes(s *terraform.State, resourceName string)
 	if err != nil {
 		return nil, err
 	}
-	return datacenterCustomAttributes(dc)
+	return datacenterCustomAttributes(context.Background(), dc)
 }
 
 func testGetVAppEntity(s *terraform.State, resourceName string) (*types.VAppEntityConfigInfo, error) {
@@ -308,7 +308,7 @@ func testGetVAppEntity(s *terraform.State, resourceName string) (*types.VAppEnti
 	if err != nil {
 		return nil, err
 	}
-	return resourceVSphereVAppEntityFind(vars.client, vars.resourceID)
+	return resourceVSphereVAppEntityFind(context.Background(), vars.client, vars.resourceID)
 }
 
 func testGetVAppContainer(s *terraform.State, resourceName string) (*object.VirtualApp, error) {
@@ -316,7 +316,7 @@ func testGetVAppContainer(s *terraform.State, resourceName string) (*object.Virt
 	if err != nil {
 		return nil, err
 	}
-	return vappcontainer.FromID(vars.client, vars.resourceID)
+	return vappcontainer.FromID(context.Background(), vars.client, vars.resourceID)
 }
 
 func testGetVAppContainerProperties(s *terraform.State, resourceName string) (*mo.VirtualApp, error) {
@@ -324,7 +324,7 @@ func testGetVAppContainerProperties(s *terraform.State, resourceName string) (*m
 	if err != nil {
 		return nil, err
 	}
-	return vappcontainer.Properties(vc)
+	return vappcontainer.Properties(context.Background(), vc)
 }
 
 func testGetContentLibrary(s *terraform.State, resourceName string) (*library.Library, error) {
@@ -332,7 +332,7 @@ func testGetContentLibrary(s *terraform.State, resourceName string) (*library.Li
 	if err != nil {
 		return nil, err
 	}
-	return contentlibrary.FromID(tVars.restClient, tVars.resourceID)
+	return contentlibrary.FromID(context.Background(), tVars.restClient, tVars.resourceID)
 }
 
 func testGetContentLibraryItem(s *terraform.State, resourceName string) (*library.Item, error) {
@@ -340,7 +340,7 @@ func testGetContentLibraryItem(s *terraform.State, resourceName string) (*librar
 	if err != nil {
 		return nil, err
 	}
-	return contentlibrary.ItemFromID(tVars.restClient, tVars.resourceID)
+	return contentlibrary.ItemFromID(context.Background(), tVars.restClient, tVars.resourceID)
 }
 
 // testPowerOffVM does an immediate power-off of the supplied virtual machine
@@ -387,7 +387,7 @@ func testRenameVMFirstDisk(s *terraform.State, resourceName string, newDiskName
 	if err != nil {
 		return err
 	}
-	dc, err := getDatacenter(tVars.client, dcp)
+	dc, err := getDatacenter(context.Background(), tVars.client, dcp)
 	if err != nil {
 		return err
 	}
@@ -395,7 +395,7 @@ func testRenameVMFirstDisk(s *terraform.State, resourceName string, newDiskName
 	var dcSpec []types.BaseVirtualDeviceConfigSpec
 	for _, d := range vprops.Config.Hardware.Device {
 		if oldDisk, ok := d.(*types.VirtualDisk); ok {
-			newFileName, err := virtualdisk.Move(
+			newFileName, err := virtualdisk.Move(context.Background(),
 				tVars.client,
 				oldDisk.Backing.(*types.VirtualDiskFlatVer2BackingInfo).FileName,
 				dc,
@@ -441,7 +441,7 @@ func testRenameVMFirstDisk(s *terraform.State, resourceName string, newDiskName
 	spec := types.VirtualMachineConfigSpec{
 		DeviceChange: dcSpec,
 	}
-	return virtualmachine.Reconfigure(vm, spec, defaultAPITimeout)
+	return virtualmachine.Reconfigure(context.Background(), vm, spec, defaultAPITimeout)
 }
 
 // testDeleteVMDisk deletes a VMDK file from the virtual machine directory. It
@@ -468,7 +468,7 @@ func testDeleteVMDisk(s *terraform.State, name string) error {
 	if err != nil {
 		return err
 	}
-	dc, err := getDatacenter(tVars.client, dcp)
+	dc, err := getDatacenter(context.Background(), tVars.client, dcp)
 	if err != nil {
 		return err
 	}
@@ -476,7 +476,7 @@ func testDeleteVMDisk(s *terraform.State, name string) error {
 		Datastore: vmxPath.Datastore,
 		Path:      path.Join(path.Dir(vmxPath.Path), name),
 	}
-	return virtualdisk.Delete(tVars.client, p.String(), dc)
+	return virtualdisk.Delete(context.Background(), tVars.client, p.String(), dc)
 }
 
 // testDeleteVM deletes the virtual machine. This is used to test resource
@@ -600,7 +600,7 @@ func testGetDatastore(s *terraform.State, resAddr string) (*object.Datastore, er
 	if err != nil {
 		return nil, err
 	}
-	return datastore.FromID(vars.client, vars.resourceID)
+	return datastore.FromID(context.Background(), vars.client, vars.resourceID)
 }
 
 // testGetDatastoreProperties is a convenience method that adds an extra step
@@ -610,7 +610,7 @@ func testGetDatastoreProperties(s *terraform.State, datastoreType string, resour
 	if err != nil {
 		return nil, err
 	}
-	return datastore.Properties(ds)
+	return datastore.Properties(context.Background(), ds)
 }
 
 // testAccResourceVSphereDatastoreCheckTags is a check to ensure that the
@@ -639,7 +639,7 @@ func testGetFolder(s *terraform.State, resourceName string) (*object.Folder, err
 	if err != nil {
 		return nil, err
 	}
-	return folder.FromID(tVars.client, tVars.resourceID)
+	return folder.FromID(context.Background(), tVars.client, tVars.resourceID)
 }
 
 // testGetFolderProperties is a convenience method that adds an extra step to
@@ -649,7 +649,7 @@ func testGetFolderProperties(s *terraform.State, resourceName string) (*mo.Folde
 	if err != nil {
 		return nil, err
 	}
-	return folder.Properties(f)
+	return folder.Properties(context.Background(), f)
 }
 
 // testGetDVS is a convenience method to fetch a DVS by resource name.
@@ -659,7 +659,7 @@ func testGetDVS(s *terraform.State, resourceName string) (*object.VmwareDistribu
 		return nil, err
 	}
 
-	return dvsFromUUID(tVars.client, tVars.resourceID)
+	return dvsFromUUID(context.Background(), tVars.client, tVars.resourceID)
 }
 
 // testGetDVSProperties is a convenience method that adds an extra step to
@@ -669,7 +669,7 @@ func testGetDVSProperties(s *terraform.State, resourceName string) (*mo.VmwareDi
 	if err != nil {
 		return nil, err
 	}
-	return dvsProperties(dvs)
+	return dvsProperties(context.Background(), dvs)
 }
 
 // testGetDVPortgroup is a convenience method to fetch a DV portgroup by resource name.
@@ -679,7 +679,7 @@ func testGetDVPortgroup(s *terraform.State, resourceName string) (*object.Distri
 		return nil, err
 	}
 	dvsID := tVars.resourceAttributes["distributed_virtual_switch_uuid"]
-	return dvportgroup.FromKey(tVars.client, dvsID, tVars.resourceID)
+	return dvportgroup.FromKey(context.Background(), tVars.client, dvsID, tVars.resourceID)
 }
 
 // testGetDVPortgroupProperties is a convenience method that adds an extra step to
@@ -689,7 +689,7 @@ func testGetDVPortgroupProperties(s *terraform.State, resourceName string) (*mo.
 	if err != nil {
 		return nil, err
 	}
-	return dvportgroup.Properties(dvs)
+	return dvportgroup.Properties(context.Background(), dvs)
 }
 
 // testCheckResourceNotAttr is an inverse check of TestCheckResourceAttr. It
@@ -763,18 +763,18 @@ func testResourceHasCustomAttributeValues(s *terraform.State, resourceType strin
 // testDeleteDatastoreFile deletes the specified file from a datastore. If the
 // file does not exist, an error is returned.
 func testDeleteDatastoreFile(client *govmomi.Client, dsID string, path string) error {
-	ds, err := datastore.FromID(client, dsID)
+	ds, err := datastore.FromID(context.Background(), client, dsID)
 	if err != nil {
 		return err
 	}
 	var dc *object.Datacenter
 	if ds.DatacenterPath != "" {
-		dc, err = getDatacenter(client, ds.DatacenterPath)
+		dc, err = getDatacenter(context.Background(), client, ds.DatacenterPath)
 		if err != nil {
 			return err
 		}
 	} else {
-		dc, err = datacenter.FromInventoryPath(client, ds.InventoryPath)
+		dc, err = datacenter.FromInventoryPath(context.Background(), client, ds.InventoryPath)
 		if err != nil {
 			return err
 		}
@@ -797,7 +797,7 @@ func testGetDatastoreCluster(s *terraform.State, resourceName string) (*object.S
 	if err != nil {
 		return nil, err
 	}
-	return storagepod.FromID(vars.client, vars.resourceID)
+	return storagepod.FromID(context.Background(), vars.client, vars.resourceID)
 }
 
 // testGetDatastoreClusterProperties is a convenience method that adds an extra
@@ -807,7 +807,7 @@ func testGetDatastoreClusterProperties(s *terraform.State, resourceName string)
 	if err != nil {
 		return nil, err
 	}
-	return storagepod.Properties(pod)
+	return storagepod.Properties(context.Background(), pod)
 }
 
 // testGetDatastoreClusterSDRSVMConfig is a convenience method to fetch a VM's
@@ -827,17 +827,17 @@ func testGetDatastoreClusterSDRSVMConfig(s *terraform.State, resourceName string
 		return nil, err
 	}
 
-	pod, err := storagepod.FromID(vars.client, podID)
+	pod, err := storagepod.FromID(context.Background(), vars.client, podID)
 	if err != nil {
 		return nil, err
 	}
 
-	vm, err := virtualmachine.FromUUID(vars.client, vmID)
+	vm, err := virtualmachine.FromUUID(context.Background(), vars.client, vmID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereStorageDrsVMOverrideFindEntry(pod, vm)
+	return resourceVSphereStorageDrsVMOverrideFindEntry(context.Background(), pod, vm)
 }
 
 // testGetComputeCluster is a convenience method to fetch a compute cluster by
@@ -847,7 +847,7 @@ func testGetComputeCluster(s *terraform.State, resourceName string, resourceType
 	if err != nil {
 		return nil, err
 	}
-	return clustercomputeresource.FromID(vars.client, vars.resourceID)
+	return clustercomputeresource.FromID(context.Background(), vars.client, vars.resourceID)
 }
 
 // testGetComputeClusterFromDataSource is a convenience method to fetch a
@@ -857,7 +857,7 @@ func testGetComputeClusterFromDataSource(s *terraform.State, resourceName string
 	if err != nil {
 		return nil, err
 	}
-	return clustercomputeresource.FromID(vars.client, vars.resourceID)
+	return clustercomputeresource.FromID(context.Background(), vars.client, vars.resourceID)
 }
 
 // testGetComputeClusterProperties is a convenience method that adds an extra
@@ -868,7 +868,7 @@ func testGetComputeClusterProperties(s *terraform.State, resourceName string) (*
 	if err != nil {
 		return nil, err
 	}
-	return clustercomputeresource.Properties(cluster)
+	return clustercomputeresource.Properties(context.Background(), cluster)
 }
 
 // testGetComputeClusterDRSVMConfig is a convenience method to fetch a VM's DRS
@@ -888,17 +888,17 @@ func testGetComputeClusterDRSVMConfig(s *terraform.State, resourceName string) (
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	vm, err := virtualmachine.FromUUID(vars.client, vmID)
+	vm, err := virtualmachine.FromUUID(context.Background(), vars.client, vmID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereDRSVMOverrideFindEntry(cluster, vm)
+	return resourceVSphereDRSVMOverrideFindEntry(context.Background(), cluster, vm)
 }
 
 // testGetComputeClusterHaVMConfig is a convenience method to fetch a VM's HA
@@ -918,17 +918,17 @@ func testGetComputeClusterHaVMConfig(s *terraform.State, resourceName string) (*
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	vm, err := virtualmachine.FromUUID(vars.client, vmID)
+	vm, err := virtualmachine.FromUUID(context.Background(), vars.client, vmID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereHAVMOverrideFindEntry(cluster, vm)
+	return resourceVSphereHAVMOverrideFindEntry(context.Background(), cluster, vm)
 }
 
 // testGetComputeClusterDPMHostConfig is a convenience method to fetch a host's
@@ -948,17 +948,17 @@ func testGetComputeClusterDPMHostConfig(s *terraform.State, resourceName string)
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	host, err := hostsystem.FromID(vars.client, hostID)
+	host, err := hostsystem.FromID(context.Background(), vars.client, hostID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereDPMHostOverrideFindEntry(cluster, host)
+	return resourceVSphereDPMHostOverrideFindEntry(context.Background(), cluster, host)
 }
 
 // testGetHostFromDataSource is a convenience method to fetch a host via the
@@ -968,7 +968,7 @@ func testGetHostFromDataSource(s *terraform.State, resourceName string) (*object
 	if err != nil {
 		return nil, err
 	}
-	return hostsystem.FromID(vars.client, vars.resourceID)
+	return hostsystem.FromID(context.Background(), vars.client, vars.resourceID)
 }
 
 // testGetComputeClusterVMGroup is a convenience method to fetch a virtual
@@ -988,12 +988,12 @@ func testGetComputeClusterVMGroup(s *terraform.State, resourceName string) (*typ
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereComputeClusterVMGroupFindEntry(cluster, name)
+	return resourceVSphereComputeClusterVMGroupFindEntry(context.Background(), cluster, name)
 }
 
 // testGetComputeClusterHostGroup is a convenience method to fetch a host group
@@ -1013,12 +1013,12 @@ func testGetComputeClusterHostGroup(s *terraform.State, resourceName string) (*t
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereComputeClusterHostGroupFindEntry(cluster, name)
+	return resourceVSphereComputeClusterHostGroupFindEntry(context.Background(), cluster, name)
 }
 
 // testGetComputeClusterVMHostRule is a convenience method to fetch a VM/host
@@ -1038,12 +1038,12 @@ func testGetComputeClusterVMHostRule(s *terraform.State, resourceName string) (*
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereComputeClusterVMHostRuleFindEntry(cluster, name)
+	return resourceVSphereComputeClusterVMHostRuleFindEntry(context.Background(), cluster, name)
 }
 
 // testGetComputeClusterVMDependencyRule is a convenience method to fetch a VM
@@ -1063,12 +1063,12 @@ func testGetComputeClusterVMDependencyRule(s *terraform.State, resourceName stri
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereComputeClusterVMDependencyRuleFindEntry(cluster, name)
+	return resourceVSphereComputeClusterVMDependencyRuleFindEntry(context.Background(), cluster, name)
 }
 
 // testGetComputeClusterVMAffinityRule is a convenience method to fetch a VM
@@ -1088,12 +1088,12 @@ func testGetComputeClusterVMAffinityRule(s *terraform.State, resourceName string
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereComputeClusterVMAffinityRuleFindEntry(cluster, name)
+	return resourceVSphereComputeClusterVMAffinityRuleFindEntry(context.Background(), cluster, name)
 }
 
 // testGetComputeClusterVMAntiAffinityRule is a convenience method to fetch a
@@ -1116,12 +1116,12 @@ func testGetComputeClusterVMAntiAffinityRule(s *terraform.State, resourceName st
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(vars.client, clusterID)
+	cluster, err := clustercomputeresource.FromID(context.Background(), vars.client, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(cluster, name)
+	return resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(context.Background(), cluster, name)
 }
 
 // testGetDatastoreClusterVMAntiAffinityRule is a convenience method to fetch a
@@ -1144,12 +1144,12 @@ func testGetDatastoreClusterVMAntiAffinityRule(s *terraform.State, resourceName
 		return nil, err
 	}
 
-	pod, err := storagepod.FromID(vars.client, podID)
+	pod, err := storagepod.FromID(context.Background(), vars.client, podID)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(pod, key)
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(context.Background(), pod, key)
 }
 
 func testGetVMStoragePolicy(s *terraform.State, resourceName string) (string, error) {
@@ -1162,7 +1162,7 @@ func testGetVMStoragePolicy(s *terraform.State, resourceName string) (string, er
 		return "", fmt.Errorf("resource %q has no id", resourceName)
 	}
 
-	return spbm.PolicyNameByID(tVars.client, policyID)
+	return spbm.PolicyNameByID(context.Background(), tVars.client, policyID)
 }
 
 func testGetVSphereZone(s *terraform.State, resourceName string) (string, error) {
@@ -1223,7 +1223,7 @@ func dcSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	dcs, err := listDatacenters(client.vimClient)
+	dcs, err := listDatacenters(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
@@ -1243,14 +1243,14 @@ func vmSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	vms, err := virtualmachine.List(client.vimClient)
+	vms, err := virtualmachine.List(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
 	for _, vm := range vms {
 		if regexp.MustCompile("testacc").Match([]byte(vm.Name())) {
-			_ = virtualmachine.PowerOff(vm)
-			_ = virtualmachine.Destroy(vm)
+			_ = virtualmachine.PowerOff(context.Background(), vm)
+			_ = virtualmachine.Destroy(context.Background(), vm)
 		}
 	}
 	return nil
@@ -1261,13 +1261,13 @@ func rpSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	rps, err := resourcepool.List(client.vimClient)
+	rps, err := resourcepool.List(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
 	for _, rp := range rps {
 		if regexp.MustCompile("testacc").Match([]byte(rp.Name())) {
-			return resourcepool.Delete(rp)
+			return resourcepool.Delete(context.Background(), rp)
 		}
 	}
 	return nil
@@ -1278,18 +1278,18 @@ func dsSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	dss, err := datastore.List(client.vimClient)
+	dss, err := datastore.List(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
 	for _, ds := range dss {
 		if regexp.MustCompile(testhelper.NfsDsName2).Match([]byte(ds.Name())) {
-			if err := datastore.Unmount(client.vimClient, ds); err != nil {
+			if err := datastore.Unmount(context.Background(), client.vimClient, ds); err != nil {
 				return err
 			}
 		}
 		if regexp.MustCompile("testacc").Match([]byte(ds.Name())) {
-			if err := datastore.Unmount(client.vimClient, ds); err != nil {
+			if err := datastore.Unmount(context.Background(), client.vimClient, ds); err != nil {
 				return err
 			}
 		}
@@ -1302,13 +1302,13 @@ func dspSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	dsps, err := storagepod.List(client.vimClient)
+	dsps, err := storagepod.List(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
 	for _, dsp := range dsps {
 		if regexp.MustCompile("testacc").Match([]byte(dsp.Name())) {
-			return storagepod.Delete(dsp)
+			return storagepod.Delete(context.Background(), dsp)
 		}
 	}
 	return nil
@@ -1319,13 +1319,13 @@ func ccSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	dsps, err := clustercomputeresource.List(client.vimClient)
+	dsps, err := clustercomputeresource.List(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
 	for _, dsp := range dsps {
 		if regexp.MustCompile("testacc").Match([]byte(dsp.Name())) {
-			return clustercomputeresource.Delete(dsp)
+			return clustercomputeresource.Delete(context.Background(), dsp)
 		}
 	}
 	return nil
@@ -1336,7 +1336,7 @@ func netSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	nets, err := network.List(client.vimClient)
+	nets, err := network.List(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
@@ -1356,7 +1356,7 @@ func folderSweep(_ string) error {
 	if err != nil {
 		return err
 	}
-	folders, err := folder.List(client.vimClient)
+	folders, err := folder.List(context.Background(), client.vimClient)
 	if err != nil {
 		return err
 	}
@@ -1446,7 +1446,7 @@ func testGetVsphereAlarm(s *terraform.State, resourceName string) (string, error
 		Type:  entityType,
 		Value: entityID,
 	}
-	al, err := alarm.FromID(tVars.client, id, entityMor)
+	al, err := alarm.FromID(context.Background(), tVars.client, id, entityMor)
 	if err != nil {
 		return "", err
 	}
@@ -18,20 +18,20 @@ import (
 
 // hostDatastoreSystemFromHostSystemID locates a HostDatastoreSystem from a
 // specified HostSystem managed object ID.
-func hostDatastoreSystemFromHostSystemID(client *govmomi.Client, hsID string) (*object.HostDatastoreSystem, error) {
-	hs, err := hostsystem.FromID(client, hsID)
+func hostDatastoreSystemFromHostSystemID(ctx context.Context, client *govmomi.Client, hsID string) (*object.HostDatastoreSystem, error) {
+	hs, err := hostsystem.FromID(ctx, client, hsID)
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	return hs.ConfigManager().DatastoreSystem(ctx)
 }
 
 // availableScsiDisk checks to make sure that a disk is available for use in a
 // VMFS datastore, and returns the ScsiDisk.
-func availableScsiDisk(dss *object.HostDatastoreSystem, name string) (*types.HostScsiDisk, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func availableScsiDisk(ctx context.Context, dss *object.HostDatastoreSystem, name string) (*types.HostScsiDisk, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	disks, err := dss.QueryAvailableDisksForVmfs(ctx)
 	if err != nil {
@@ -54,13 +54,13 @@ func availableScsiDisk(dss *object.HostDatastoreSystem, name string) (*types.Hos
 // diskSpecForCreate checks to make sure that a disk is available to be used to
 // create a VMFS datastore, specifically in its entirety, and returns a
 // respective VmfsDatastoreCreateSpec.
-func diskSpecForCreate(dss *object.HostDatastoreSystem, name string) (*types.VmfsDatastoreCreateSpec, error) {
-	disk, err := availableScsiDisk(dss, name)
+func diskSpecForCreate(ctx context.Context, dss *object.HostDatastoreSystem, name string) (*types.VmfsDatastoreCreateSpec, error) {
+	disk, err := availableScsiDisk(ctx, dss, name)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	options, err := dss.QueryVmfsDatastoreCreateOptions(ctx, disk.DevicePath)
 	if err != nil {
@@ -84,18 +84,18 @@ func diskSpecForCreate(dss *object.HostDatastoreSystem, name string) (*types.Vmf
 // used to extend a VMFS datastore, specifically in its entirety, and returns a
 // respective VmfsDatastoreExtendSpec if it is. An error is returned if it's
 // not.
-func diskSpecForExtend(dss *object.HostDatastoreSystem, ds *object.Datastore, name string) (*types.VmfsDatastoreExtendSpec, error) {
-	disk, err := availableScsiDisk(dss, name)
+func diskSpecForExtend(ctx context.Context, dss *object.HostDatastoreSystem, ds *object.Datastore, name string) (*types.VmfsDatastoreExtendSpec, error) {
+	disk, err := availableScsiDisk(ctx, dss, name)
 	if err != nil {
 		return nil, err
 	}
 
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ctx, ds)
 	if err != nil {
 		return nil, fmt.Errorf("error getting properties for datastore ID %q: %s", ds.Reference().Value, err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	options, err := queryVmfsDatastoreExtendOptions(ctx, dss, ds, disk.DevicePath, true)
 	if err != nil {
@@ -115,8 +115,8 @@ func diskSpecForExtend(dss *object.HostDatastoreSystem, ds *object.Datastore, na
 }
 
 // removeDatastore is a convenience method for removing a referenced datastore.
-func removeDatastore(s *object.HostDatastoreSystem, ds *object.Datastore) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func removeDatastore(ctx context.Context, s *object.HostDatastoreSystem, ds *object.Datastore) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	return s.Remove(ctx, ds)
 }
@@ -17,28 +17,28 @@ import (
 
 // hostNetworkSystemFromHostSystem locates a HostNetworkSystem from a specified
 // HostSystem.
-func hostNetworkSystemFromHostSystem(hs *object.HostSystem) (*object.HostNetworkSystem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func hostNetworkSystemFromHostSystem(ctx context.Context, hs *object.HostSystem) (*object.HostNetworkSystem, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	return hs.ConfigManager().NetworkSystem(ctx)
 }
 
 // hostNetworkSystemFromHostSystemID locates a HostNetworkSystem from a
 // specified HostSystem managed object ID.
-func hostNetworkSystemFromHostSystemID(client *govmomi.Client, hsID string) (*object.HostNetworkSystem, error) {
-	hs, err := hostsystem.FromID(client, hsID)
+func hostNetworkSystemFromHostSystemID(ctx context.Context, client *govmomi.Client, hsID string) (*object.HostNetworkSystem, error) {
+	hs, err := hostsystem.FromID(ctx, client, hsID)
 	if err != nil {
 		return nil, err
 	}
-	return hostNetworkSystemFromHostSystem(hs)
+	return hostNetworkSystemFromHostSystem(ctx, hs)
 }
 
 // hostVSwitchFromName locates a virtual switch on the supplied
 // HostNetworkSystem by name.
-func hostVSwitchFromName(client *govmomi.Client, ns *object.HostNetworkSystem, name string) (*types.HostVirtualSwitch, error) {
+func hostVSwitchFromName(ctx context.Context, client *govmomi.Client, ns *object.HostNetworkSystem, name string) (*types.HostVirtualSwitch, error) {
 	var mns mo.HostNetworkSystem
 	pc := client.PropertyCollector()
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	if err := pc.RetrieveOne(ctx, ns.Reference(), []string{"networkInfo.vswitch"}, &mns); err != nil {
 		return nil, fmt.Errorf("error fetching host network properties: %s", err)
@@ -59,10 +59,10 @@ func hostVSwitchFromName(client *govmomi.Client, ns *object.HostNetworkSystem, n
 
 // hostPortGroupFromName locates a port group on the supplied HostNetworkSystem
 // by name.
-func hostPortGroupFromName(client *govmomi.Client, ns *object.HostNetworkSystem, name string) (*types.HostPortGroup, error) {
+func hostPortGroupFromName(ctx context.Context, client *govmomi.Client, ns *object.HostNetworkSystem, name string) (*types.HostPortGroup, error) {
 	var mns mo.HostNetworkSystem
 	pc := client.PropertyCollector()
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	if err := pc.RetrieveOne(ctx, ns.Reference(), []string{"networkInfo.portgroup"}, &mns); err != nil {
 		return nil, fmt.Errorf("error fetching host network properties: %s", err)
@@ -14,12 +14,12 @@ import (
 
 // hostStorageSystemFromHostSystemID locates a HostStorageSystem from a
 // specified HostSystem managed object ID.
-func hostStorageSystemFromHostSystemID(client *govmomi.Client, hsID string) (*object.HostStorageSystem, error) {
-	hs, err := hostsystem.FromID(client, hsID)
+func hostStorageSystemFromHostSystemID(ctx context.Context, client *govmomi.Client, hsID string) (*object.HostStorageSystem, error) {
+	hs, err := hostsystem.FromID(ctx, client, hsID)
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	return hs.ConfigManager().StorageSystem(ctx)
 }
@@ -32,7 +32,7 @@ const (
 )
 
 // Return the object reference from an object type and ID
-func FindEntity(client *govmomi.Client, objType string, id string) (object.Reference, error) {
+func FindEntity(ctx context.Context, client *govmomi.Client, objType string, id string) (object.Reference, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -40,26 +40,26 @@ func FindEntity(client *govmomi.Client, objType string, id string) (object.Refer
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return finder.ObjectReference(ctx, ref)
 }
 
 // Retrieve alarm on the given object
-func getAlarms(client *govmomi.Client, entity object.Reference) ([]mo.Alarm, error) {
+func getAlarms(ctx context.Context, client *govmomi.Client, entity object.Reference) ([]mo.Alarm, error) {
 	m, err := alarm.GetManager(client.Client)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return m.GetAlarm(ctx, entity)
 }
 
 // FromID locates an alarm on a given entity by its managed object reference ID.
-func FromID(client *govmomi.Client, id string, entity object.Reference) (*mo.Alarm, error) {
-	alarms, err := getAlarms(client, entity)
+func FromID(ctx context.Context, client *govmomi.Client, id string, entity object.Reference) (*mo.Alarm, error) {
+	alarms, err := getAlarms(ctx, client, entity)
 	if err != nil {
 		return nil, err
 	}
@@ -73,8 +73,8 @@ func FromID(client *govmomi.Client, id string, entity object.Reference) (*mo.Ala
 }
 
 // FromName locates and alarm on a given entity from its name
-func FromName(client *govmomi.Client, name string, entity object.Reference) (*mo.Alarm, error) {
-	alarms, err := getAlarms(client, entity)
+func FromName(ctx context.Context, client *govmomi.Client, name string, entity object.Reference) (*mo.Alarm, error) {
+	alarms, err := getAlarms(ctx, client, entity)
 	if err != nil {
 		return nil, err
 	}
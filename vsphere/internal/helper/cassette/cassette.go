@@ -0,0 +1,397 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cassette records the HTTP traffic exchanged with the vSphere API
+// endpoints (vim25 SOAP, REST, PBM, vSAN and SSO) to disk and replays it later
+// without a live server.
+//
+// A cassette is a directory. Every client session started by the provider is
+// stored in its own episode file, so the sequence of provider configurations
+// made by Terraform during a test (plan, apply, refresh, ...) replays in the
+// same order it was recorded.
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mode is the mode a cassette is opened in.
+type Mode string
+
+const (
+	// ModeRecord performs requests against the server and appends each
+	// interaction to the cassette.
+	ModeRecord = Mode("record")
+
+	// ModeReplay answers requests from the cassette without contacting the
+	// server.
+	ModeReplay = Mode("replay")
+)
+
+// Modes lists the valid cassette modes.
+var Modes = []string{
+	string(ModeRecord),
+	string(ModeReplay),
+}
+
+const (
+	// episodeExt is the file extension for episode files.
+	episodeExt = ".jsonl"
+
+	// replayStateFile tracks the next episode to replay.
+	replayStateFile = "replay.state"
+)
+
+// redactPatterns match request body content that must never be written to a
+// cassette, such as credentials and the session cookie that the SOAP clients
+// of other endpoints, like pbm, pass in their headers. Matches are replaced before an interaction is stored and before
+// a request is looked up, so credentials do not need to match on replay.
+var redactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(<password>)[^<]*(</password>)`),
+	regexp.MustCompile(`(<token>)[^<]*(</token>)`),
+	regexp.MustCompile(`(<vcSessionCookie[^>]*>)[^<]*(</vcSessionCookie>)`),
+}
+
+// redactHeaders are the canonical names of the response headers that carry
+// session credentials.
+// Their values are replaced before an interaction is stored. The replayed
+// clients do not need the real values, as requests are matched without their
+// headers.
+var redactHeaders = []string{
+	"Set-Cookie",
+	"Vmware-Api-Session-Id",
+}
+
+// redactCookiePattern matches the value of a cookie in a Set-Cookie header,
+// such as vmware_soap_session, keeping its name and attributes.
+var redactCookiePattern = regexp.MustCompile(`^([^=;]+=)[^;]*`)
+
+// Interaction is a single request and response pair.
+type Interaction struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	RequestBody  string      `json:"request_body,omitempty"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	ResponseBody string      `json:"response_body,omitempty"`
+}
+
+func (i *Interaction) key() string {
+	return i.Method + " " + i.URL + "\n" + i.RequestBody
+}
+
+// Recorder records or replays the traffic of one client session.
+type Recorder struct {
+	mode Mode
+	path string
+
+	mu sync.Mutex
+	f  *os.File
+
+	// The recorded interactions waiting to be replayed, queued by request key,
+	// and the last interaction replayed for each key.
+	queue map[string][]*Interaction
+	last  map[string]*Interaction
+}
+
+// New opens a new episode in the cassette directory dir. In record mode, a
+// new episode file is created. In replay mode, the next recorded episode is
+// loaded; after the last episode, replay starts over with the first one.
+func New(dir string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		mode: mode,
+	}
+	var err error
+	switch mode {
+	case ModeRecord:
+		err = r.openRecord(dir)
+	case ModeReplay:
+		err = r.openReplay(dir)
+	default:
+		err = fmt.Errorf("unknown cassette mode %q", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] API cassette episode %q opened in %s mode", r.path, r.mode)
+	return r, nil
+}
+
+// Mode returns the mode the recorder was opened in.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Path returns the path to the episode file in use.
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+func (r *Recorder) openRecord(dir string) error {
+	if err := os.MkdirAll(filepath.Clean(dir), 0700); err != nil {
+		return fmt.Errorf("error creating cassette directory: %s", err)
+	}
+	episodes, err := listEpisodes(dir)
+	if err != nil {
+		return err
+	}
+	// O_EXCL guards against another provider instance claiming the same
+	// episode number between listing the directory and creating the file.
+	for n := len(episodes); ; n++ {
+		p := filepath.Join(dir, episodeName(n))
+		f, err := os.OpenFile(filepath.Clean(p), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error creating cassette episode: %s", err)
+		}
+		r.f = f
+		r.path = p
+		return nil
+	}
+}
+
+func (r *Recorder) openReplay(dir string) error {
+	episodes, err := listEpisodes(dir)
+	if err != nil {
+		return err
+	}
+	if len(episodes) < 1 {
+		return fmt.Errorf("cassette %q has no recorded episodes", dir)
+	}
+
+	statePath := filepath.Join(dir, replayStateFile)
+	n := 0
+	if b, err := os.ReadFile(filepath.Clean(statePath)); err == nil {
+		n, _ = strconv.Atoi(strings.TrimSpace(string(b)))
+	}
+	if n < 0 || n >= len(episodes) {
+		n = 0
+	}
+	if err := os.WriteFile(filepath.Clean(statePath), []byte(strconv.Itoa(n+1)), 0600); err != nil {
+		return fmt.Errorf("error saving cassette replay state: %s", err)
+	}
+
+	r.path = filepath.Join(dir, episodes[n])
+	r.queue = make(map[string][]*Interaction)
+	r.last = make(map[string]*Interaction)
+	f, err := os.Open(filepath.Clean(r.path))
+	if err != nil {
+		return fmt.Errorf("error opening cassette episode: %s", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Printf("[DEBUG] Error closing cassette episode %q: %s", r.path, err)
+		}
+	}()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for s.Scan() {
+		i := new(Interaction)
+		if err := json.Unmarshal(s.Bytes(), i); err != nil {
+			return fmt.Errorf("error decoding cassette episode %q: %s", r.path, err)
+		}
+		r.queue[i.key()] = append(r.queue[i.key()], i)
+	}
+	return s.Err()
+}
+
+// ResetReplay rewinds replay of the cassette in dir to its first episode.
+func ResetReplay(dir string) error {
+	err := os.Remove(filepath.Join(dir, replayStateFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Register installs the recorder on the supplied transport. All HTTP and
+// HTTPS requests made through the transport, including those made by service
+// clients that share it, are recorded or replayed from then on.
+func (r *Recorder) Register(t *http.Transport) {
+	rt := &roundTripper{
+		recorder: r,
+		next:     t,
+	}
+	t.RegisterProtocol("https", rt)
+	t.RegisterProtocol("http", rt)
+}
+
+// Close closes the episode file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// skipKey marks requests that the recorder has passed back to the transport
+// so that they are not intercepted a second time.
+type skipKey struct{}
+
+type roundTripper struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context().Value(skipKey{}) != nil {
+		return nil, http.ErrSkipAltProtocol
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	i := &Interaction{
+		Method:      req.Method,
+		URL:         req.URL.RequestURI(),
+		RequestBody: redact(body),
+	}
+
+	if rt.recorder.mode == ModeReplay {
+		return rt.recorder.replay(req, i)
+	}
+
+	ctx := context.WithValue(req.Context(), skipKey{}, true)
+	res, err := rt.next.RoundTrip(req.Clone(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := rt.recorder.record(res, i); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *Recorder) record(res *http.Response, i *Interaction) error {
+	b, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(b))
+
+	i.StatusCode = res.StatusCode
+	i.Header = redactHeader(res.Header)
+	i.ResponseBody = string(b)
+
+	line, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return errors.New("cassette episode is closed")
+	}
+	if _, err := r.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing cassette episode: %s", err)
+	}
+	return nil
+}
+
+// replay returns the next recorded response for the request. Once the
+// recorded responses for a request are used up, the last one is repeated, so
+// that pollers which ran more often than during the recording still succeed.
+func (r *Recorder) replay(req *http.Request, i *Interaction) (*http.Response, error) {
+	k := i.key()
+	r.mu.Lock()
+	next := r.last[k]
+	if q := r.queue[k]; len(q) > 0 {
+		next, r.queue[k] = q[0], q[1:]
+		r.last[k] = next
+	}
+	r.mu.Unlock()
+
+	if next == nil {
+		return nil, fmt.Errorf("cassette %q has no recorded interaction for %s %s", r.path, i.Method, i.URL)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", next.StatusCode, http.StatusText(next.StatusCode)),
+		StatusCode:    next.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        next.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(next.ResponseBody)),
+		ContentLength: int64(len(next.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	b, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return string(b), nil
+}
+
+func redact(s string) string {
+	for _, re := range redactPatterns {
+		s = re.ReplaceAllString(s, "${1}REDACTED${2}")
+	}
+	return s
+}
+
+// redactHeader returns a copy of a response header without the session
+// credentials in redactHeaders.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range redactHeaders {
+		for n, v := range h[k] {
+			if k == "Set-Cookie" {
+				h[k][n] = redactCookiePattern.ReplaceAllString(v, "${1}REDACTED")
+				continue
+			}
+			h[k][n] = "REDACTED"
+		}
+	}
+	return h
+}
+
+func episodeName(n int) string {
+	return fmt.Sprintf("%04d%s", n, episodeExt)
+}
+
+// listEpisodes returns the episode file names in dir in recording order.
+func listEpisodes(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette directory: %s", err)
+	}
+	var episodes []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), episodeExt) {
+			episodes = append(episodes, e.Name())
+		}
+	}
+	return episodes, nil
+}
@@ -0,0 +1,151 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package cassette
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func testNewClient(t *testing.T, r *Recorder) *http.Client {
+	t.Helper()
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	r.Register(tr)
+	return &http.Client{Transport: tr}
+}
+
+func testPost(t *testing.T, c *http.Client, u, body string) string {
+	t.Helper()
+	res, err := c.Post(u, "text/xml", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error posting to %s: %s", u, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("error reading response: %s", err)
+	}
+	return string(b)
+}
+
+func TestRecordReplay(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		b, _ := io.ReadAll(req.Body)
+		if strings.Contains(string(b), "<password>") {
+			http.SetCookie(w, &http.Cookie{Name: "vmware_soap_session", Value: "secret-cookie", Path: "/", HttpOnly: true})
+			w.Header().Set("vmware-api-session-id", "secret-session-id")
+		}
+		_, _ = io.WriteString(w, string(b)+"-"+string(rune('0'+n)))
+	}))
+	dir := t.TempDir()
+
+	r, err := New(dir, ModeRecord)
+	if err != nil {
+		t.Fatalf("error opening cassette for recording: %s", err)
+	}
+	c := testNewClient(t, r)
+	recorded := []string{
+		testPost(t, c, server.URL+"/sdk", "a"),
+		testPost(t, c, server.URL+"/sdk", "b"),
+		testPost(t, c, server.URL+"/sdk", "a"),
+		testPost(t, c, server.URL+"/sdk", "<password>secret</password>"),
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("error closing cassette: %s", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, episodeName(0)))
+	if err != nil {
+		t.Fatalf("error reading episode: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var login Interaction
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &login); err != nil {
+		t.Fatalf("error decoding interaction: %s", err)
+	}
+	if login.RequestBody != "<password>REDACTED</password>" {
+		t.Fatalf("expected password to be redacted from the cassette, got %q", login.RequestBody)
+	}
+	if strings.Contains(lines[len(lines)-1], "secret-") {
+		t.Fatalf("expected session credentials to be redacted from the cassette, got %s", lines[len(lines)-1])
+	}
+	if actual := login.Header.Get("Set-Cookie"); actual != "vmware_soap_session=REDACTED; Path=/; HttpOnly" {
+		t.Fatalf("expected session cookie to be redacted, got %q", actual)
+	}
+
+	r, err = New(dir, ModeReplay)
+	if err != nil {
+		t.Fatalf("error opening cassette for replay: %s", err)
+	}
+	c = testNewClient(t, r)
+	replayed := []string{
+		testPost(t, c, server.URL+"/sdk", "a"),
+		testPost(t, c, server.URL+"/sdk", "b"),
+		testPost(t, c, server.URL+"/sdk", "a"),
+		testPost(t, c, server.URL+"/sdk", "<password>other</password>"),
+	}
+	for i := range recorded {
+		if recorded[i] != replayed[i] {
+			t.Fatalf("interaction %d: expected %q, got %q", i, recorded[i], replayed[i])
+		}
+	}
+
+	// Exhausted interactions repeat the last recorded response.
+	if actual := testPost(t, c, server.URL+"/sdk", "a"); actual != recorded[2] {
+		t.Fatalf("expected repeated response %q, got %q", recorded[2], actual)
+	}
+
+	if _, err := c.Post(server.URL+"/sdk", "text/xml", strings.NewReader("c")); err == nil {
+		t.Fatal("expected error for request not in cassette")
+	}
+}
+
+func TestReplayEpisodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, req.URL.Path)
+	}))
+	dir := t.TempDir()
+
+	for _, p := range []string{"/first", "/second"} {
+		r, err := New(dir, ModeRecord)
+		if err != nil {
+			t.Fatalf("error opening cassette for recording: %s", err)
+		}
+		testPost(t, testNewClient(t, r), server.URL+p, "")
+		_ = r.Close()
+	}
+	server.Close()
+
+	for _, p := range []string{"/first", "/second", "/first"} {
+		r, err := New(dir, ModeReplay)
+		if err != nil {
+			t.Fatalf("error opening cassette for replay: %s", err)
+		}
+		if actual := testPost(t, testNewClient(t, r), server.URL+p, ""); actual != p {
+			t.Fatalf("expected %q, got %q", p, actual)
+		}
+	}
+
+	if err := ResetReplay(dir); err != nil {
+		t.Fatalf("error resetting replay: %s", err)
+	}
+	r, err := New(dir, ModeReplay)
+	if err != nil {
+		t.Fatalf("error opening cassette for replay: %s", err)
+	}
+	if !strings.HasSuffix(r.Path(), episodeName(0)) {
+		t.Fatalf("expected replay to restart at the first episode, got %q", r.Path())
+	}
+}
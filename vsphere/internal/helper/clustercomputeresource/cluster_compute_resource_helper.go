@@ -21,10 +21,11 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/folder"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // FromID locates a cluster by its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (*object.ClusterComputeResource, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*object.ClusterComputeResource, error) {
 	log.Printf("[DEBUG] Locating compute cluster with ID %q", id)
 	finder := find.NewFinder(client.Client, false)
 
@@ -33,7 +34,7 @@ func FromID(client *govmomi.Client, id string) (*object.ClusterComputeResource,
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	r, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -44,12 +45,11 @@ func FromID(client *govmomi.Client, id string) (*object.ClusterComputeResource,
 	return cluster, nil
 }
 
-func List(client *govmomi.Client) ([]*object.ClusterComputeResource, error) {
-	return getComputeClusters(client, "/*")
+func List(ctx context.Context, client *govmomi.Client) ([]*object.ClusterComputeResource, error) {
+	return getComputeClusters(ctx, client, "/*")
 }
 
-func getComputeClusters(client *govmomi.Client, path string) ([]*object.ClusterComputeResource, error) {
-	ctx := context.TODO()
+func getComputeClusters(ctx context.Context, client *govmomi.Client, path string) ([]*object.ClusterComputeResource, error) {
 	var dss []*object.ClusterComputeResource
 	finder := find.NewFinder(client.Client, false)
 	es, err := finder.ManagedObjectListChildren(ctx, path+"/*", "folder", "storagepod", "clustercompute")
@@ -59,13 +59,13 @@ func getComputeClusters(client *govmomi.Client, path string) ([]*object.ClusterC
 	for _, id := range es {
 		switch {
 		case id.Object.Reference().Type == "ClusterComputeResource":
-			ds, err := FromID(client, id.Object.Reference().Value)
+			ds, err := FromID(ctx, client, id.Object.Reference().Value)
 			if err != nil {
 				return nil, err
 			}
 			dss = append(dss, ds)
 		case id.Object.Reference().Type == "Folder":
-			newDSs, err := getComputeClusters(client, id.Path)
+			newDSs, err := getComputeClusters(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -79,7 +79,7 @@ func getComputeClusters(client *govmomi.Client, path string) ([]*object.ClusterC
 
 // FromPath loads a ClusterComputeResource from its path. The datacenter is
 // optional if the path is specific enough to not require it.
-func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*object.ClusterComputeResource, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.ClusterComputeResource, error) {
 	finder := find.NewFinder(client.Client, false)
 	if dc != nil {
 		log.Printf("[DEBUG] Attempting to locate compute cluster %q in datacenter %q", name, dc.InventoryPath)
@@ -88,15 +88,15 @@ func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*obje
 		log.Printf("[DEBUG] Attempting to locate compute cluster at absolute path %q", name)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return finder.ClusterComputeResource(ctx, name)
 }
 
 // Properties is a convenience method that wraps fetching the
 // ClusterComputeResource MO from its higher-level object.
-func Properties(cluster *object.ClusterComputeResource) (*mo.ClusterComputeResource, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, cluster *object.ClusterComputeResource) (*mo.ClusterComputeResource, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.ClusterComputeResource
 	if err := cluster.Properties(ctx, cluster.Reference(), nil, &props); err != nil {
@@ -107,9 +107,9 @@ func Properties(cluster *object.ClusterComputeResource) (*mo.ClusterComputeResou
 
 // Create creates a ClusterComputeResource in a supplied folder. The resulting
 // ClusterComputeResource is returned.
-func Create(f *object.Folder, name string, spec types.ClusterConfigSpecEx) (*object.ClusterComputeResource, error) {
+func Create(ctx context.Context, f *object.Folder, name string, spec types.ClusterConfigSpecEx) (*object.ClusterComputeResource, error) {
 	log.Printf("[DEBUG] Creating compute cluster %q", fmt.Sprintf("%s/%s", f.InventoryPath, name))
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	cluster, err := f.CreateCluster(ctx, name, spec)
 	if err != nil {
@@ -119,26 +119,26 @@ func Create(f *object.Folder, name string, spec types.ClusterConfigSpecEx) (*obj
 }
 
 // Rename renames a ClusterComputeResource.
-func Rename(cluster *object.ClusterComputeResource, name string) error {
+func Rename(ctx context.Context, cluster *object.ClusterComputeResource, name string) error {
 	log.Printf("[DEBUG] Renaming compute cluster %q to %s", cluster.InventoryPath, name)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := cluster.Rename(ctx, name)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // MoveToFolder is a complex method that moves a ClusterComputeResource to a given relative
 // compute folder path. "Relative" here means relative to a datacenter, which
 // is discovered from the current ClusterComputeResource path.
-func MoveToFolder(client *govmomi.Client, cluster *object.ClusterComputeResource, relative string) error {
-	f, err := folder.HostFolderFromObject(client, cluster, relative)
+func MoveToFolder(ctx context.Context, client *govmomi.Client, cluster *object.ClusterComputeResource, relative string) error {
+	f, err := folder.HostFolderFromObject(ctx, client, cluster, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(cluster.Reference(), f)
+	return folder.MoveObjectTo(ctx, cluster.Reference(), f)
 }
 
 // HasChildren checks to see if a compute cluster has any child items (hosts
@@ -147,21 +147,22 @@ func MoveToFolder(client *govmomi.Client, cluster *object.ClusterComputeResource
 // compute cluster in vSphere destroys *all* children if at all possible
 // (including removing hosts and virtual machines), so extra verification is
 // necessary to prevent accidental removal.
-func HasChildren(cluster *object.ClusterComputeResource) (bool, error) {
-	return computeresource.HasChildren(cluster)
+func HasChildren(ctx context.Context, cluster *object.ClusterComputeResource) (bool, error) {
+	return computeresource.HasChildren(ctx, cluster)
 }
 
 // Reconfigure reconfigures a cluster. This just gets dispatched to
 // computeresource as both methods are the same.
-func Reconfigure(cluster *object.ClusterComputeResource, spec *types.ClusterConfigSpecEx) error {
-	return computeresource.Reconfigure(cluster, spec)
+func Reconfigure(ctx context.Context, cluster *object.ClusterComputeResource, spec *types.ClusterConfigSpecEx) error {
+	return computeresource.Reconfigure(ctx, cluster, spec)
 }
 
 func ConfigureEvc(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	mode string,
 ) error {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	evcMan, err := methods.EvcManager(ctx, cluster.Client(), &types.EvcManager{This: cluster.Reference()})
@@ -180,13 +181,14 @@ func ConfigureEvc(
 	}
 
 	t := object.NewTask(cluster.Client(), res.Returnval)
-	return t.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, t)
 }
 
 func DisableEvc(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 ) error {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	evcMan, err := methods.EvcManager(ctx, cluster.Client(), &types.EvcManager{This: cluster.Reference()})
@@ -204,30 +206,29 @@ func DisableEvc(
 	}
 
 	t := object.NewTask(cluster.Client(), res.Returnval)
-	return t.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, t)
 }
 
 // Delete destroys a ClusterComputeResource.
-func Delete(cluster *object.ClusterComputeResource) error {
+func Delete(ctx context.Context, cluster *object.ClusterComputeResource) error {
 	log.Printf("[DEBUG] Deleting compute cluster %q", cluster.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := cluster.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
-func Hosts(cluster *object.ClusterComputeResource) ([]*object.HostSystem, error) {
-	ctx := context.TODO()
+func Hosts(ctx context.Context, cluster *object.ClusterComputeResource) ([]*object.HostSystem, error) {
 	return cluster.Hosts(ctx)
 }
 
 // MoveHostsInto moves all of the supplied hosts into the cluster. All virtual
 // machines are moved to the cluster's root resource pool and any resource
 // pools on the host itself are deleted.
-func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResource, hosts []*object.HostSystem) error {
+func MoveHostsInto(ctx context.Context, client *govmomi.Client, cluster *object.ClusterComputeResource, hosts []*object.HostSystem) error {
 	var hsNames []string
 	var hsRefs []types.ManagedObjectReference
 
@@ -236,18 +237,18 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 	for _, hs := range hosts {
 		hsNames = append(hsNames, hs.Name())
 		hsRefs = append(hsRefs, hs.Reference())
-		hsProps, err := hostsystem.Properties(hs)
+		hsProps, err := hostsystem.Properties(ctx, hs)
 		if err != nil {
 			return fmt.Errorf("while fetching properties for host %q: %s", hs.Reference().Value, err)
 		}
 
 		if hsProps.Parent.Type == "ClusterComputeResource" {
 			cRef := hsProps.Parent.Value
-			parentCluster, err := computeresource.BaseFromReference(client, hsProps.Parent.Reference())
+			parentCluster, err := computeresource.BaseFromReference(ctx, client, hsProps.Parent.Reference())
 			if err != nil {
 				return fmt.Errorf("while retrieving parent cluster (%q) object for host %q: %s", cluster.Reference().Value, hs.Reference().Value, err)
 			}
-			c, err := computeresource.BaseProperties(parentCluster)
+			c, err := computeresource.BaseProperties(ctx, parentCluster)
 			if err != nil {
 				return fmt.Errorf("while retrieving parent cluster (%q) properties for host %q: %s", cluster.Reference().Value, hs.Reference().Value, err)
 			}
@@ -267,7 +268,7 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 			}
 
 			totalVMTimeout := provider.DefaultAPITimeout * time.Duration(len(hsProps.Vm)+1)
-			err = hostsystem.EnterMaintenanceMode(hs, totalVMTimeout, evacuate)
+			err = hostsystem.EnterMaintenanceMode(ctx, hs, totalVMTimeout, evacuate)
 			if err != nil {
 				return fmt.Errorf("while putting host %q in maintenance mode: %s", hs.Reference().Value, err)
 			}
@@ -280,7 +281,7 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 		Host: hsRefs,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	resp, err := methods.MoveInto_Task(ctx, cluster.Client(), &req)
 	if err != nil {
@@ -288,7 +289,7 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 	}
 
 	task := object.NewTask(cluster.Client(), resp.Returnval)
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // MoveHostsOutOf moves a supplied list of hosts out of the specified cluster.
@@ -304,34 +305,34 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 //
 // Individual hosts are taken out of maintenance mode after its operation is
 // complete.
-func MoveHostsOutOf(cluster *object.ClusterComputeResource, hosts []*object.HostSystem, timeout int) error {
+func MoveHostsOutOf(ctx context.Context, cluster *object.ClusterComputeResource, hosts []*object.HostSystem, timeout int) error {
 	for _, host := range hosts {
-		if err := moveHostOutOf(cluster, host, timeout); err != nil {
+		if err := moveHostOutOf(ctx, cluster, host, timeout); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func moveHostOutOf(cluster *object.ClusterComputeResource, host *object.HostSystem, timeout int) error {
+func moveHostOutOf(ctx context.Context, cluster *object.ClusterComputeResource, host *object.HostSystem, timeout int) error {
 	// Place the host into maintenance mode. This blocks until the host is ready.
 	timeoutDuration := time.Duration(timeout) * time.Second
-	if err := hostsystem.EnterMaintenanceMode(host, timeoutDuration, true); err != nil {
+	if err := hostsystem.EnterMaintenanceMode(ctx, host, timeoutDuration, true); err != nil {
 		return fmt.Errorf("error putting host %q into maintenance mode: %s", host.Name(), err)
 	}
 
 	// Host should be ready to move out of the cluster now.
-	f, err := folder.HostFolderFromObject(&govmomi.Client{Client: cluster.Client()}, host, "/")
+	f, err := folder.HostFolderFromObject(ctx, &govmomi.Client{Client: cluster.Client()}, host, "/")
 	if err != nil {
 		return err
 	}
 	log.Printf("[DEBUG] Moving host %q out of cluster %q and to folder %q", host.Name(), cluster.Name(), f.InventoryPath)
-	if err := folder.MoveObjectTo(host.Reference(), f); err != nil {
+	if err := folder.MoveObjectTo(ctx, host.Reference(), f); err != nil {
 		return fmt.Errorf("error moving host %q out of cluster %q: %s", host.Name(), cluster.Name(), err)
 	}
 
 	// Move the host out of maintenance mode now that it's out of the cluster.
-	if err := hostsystem.ExitMaintenanceMode(host, timeoutDuration); err != nil {
+	if err := hostsystem.ExitMaintenanceMode(ctx, host, timeoutDuration); err != nil {
 		return fmt.Errorf("error taking host %q out of maintenance mode: %s", host.Name(), err)
 	}
 
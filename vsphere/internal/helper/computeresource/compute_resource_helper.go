@@ -18,6 +18,7 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/envbrowse"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // BaseComputeResource is an interface that ComputeResource and any derivative
@@ -45,7 +46,7 @@ type BaseComputeResource interface {
 // Note this is for base level ComputeResource objects only, and should only be
 // used for standalone hosts. If you are looking for a cluster, use
 // ClusterFromID.
-func StandaloneFromID(client *govmomi.Client, id string) (*object.ComputeResource, error) {
+func StandaloneFromID(ctx context.Context, client *govmomi.Client, id string) (*object.ComputeResource, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -53,7 +54,7 @@ func StandaloneFromID(client *govmomi.Client, id string) (*object.ComputeResourc
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	obj, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -62,10 +63,10 @@ func StandaloneFromID(client *govmomi.Client, id string) (*object.ComputeResourc
 	return obj.(*object.ComputeResource), nil
 }
 
-func HostSystemFromID(client *govmomi.Client, id string) (BaseComputeResource, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func HostSystemFromID(ctx context.Context, client *govmomi.Client, id string) (BaseComputeResource, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
-	host, err := hostsystem.FromID(client, id)
+	host, err := hostsystem.FromID(ctx, client, id)
 	if err != nil {
 		return nil, err
 	}
@@ -93,10 +94,10 @@ func HostSystemFromID(client *govmomi.Client, id string) (BaseComputeResource, e
 }
 
 // BaseFromPath returns a BaseComputeResource for a given path.
-func BaseFromPath(client *govmomi.Client, path string) (BaseComputeResource, error) {
+func BaseFromPath(ctx context.Context, client *govmomi.Client, path string) (BaseComputeResource, error) {
 	finder := find.NewFinder(client.Client, false)
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	list, err := finder.ManagedObjectList(ctx, path, "ComputeResource", "ClusterComputeResource")
 	if err != nil {
@@ -111,19 +112,19 @@ func BaseFromPath(client *govmomi.Client, path string) (BaseComputeResource, err
 	if !strings.HasSuffix(list[0].Path, path) {
 		return nil, fmt.Errorf("returned object path %q does not properly match search path %q", list[0].Path, path)
 	}
-	return BaseFromReference(client, list[0].Object.Reference())
+	return BaseFromReference(ctx, client, list[0].Object.Reference())
 }
 
 // BaseFromReference returns a BaseComputeResource for a given managed object
 // reference.
-func BaseFromReference(client *govmomi.Client, ref types.ManagedObjectReference) (BaseComputeResource, error) {
+func BaseFromReference(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference) (BaseComputeResource, error) {
 	switch ref.Type {
 	case "HostSystem":
-		return HostSystemFromID(client, ref.Value)
+		return HostSystemFromID(ctx, client, ref.Value)
 	case "ComputeResource":
-		return StandaloneFromID(client, ref.Value)
+		return StandaloneFromID(ctx, client, ref.Value)
 	case "ClusterComputeResource":
-		return StandaloneFromID(client, ref.Value)
+		return StandaloneFromID(ctx, client, ref.Value)
 	}
 	return nil, fmt.Errorf("unknown object type %s", ref.Type)
 }
@@ -133,8 +134,8 @@ func BaseFromReference(client *govmomi.Client, ref types.ManagedObjectReference)
 // derivative object implements.
 //
 // Note that this does not return any cluster-level attributes.
-func BaseProperties(obj BaseComputeResource) (*mo.ComputeResource, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func BaseProperties(ctx context.Context, obj BaseComputeResource) (*mo.ComputeResource, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.ComputeResource
 	if err := obj.Properties(ctx, obj.Reference(), nil, &props); err != nil {
@@ -146,23 +147,23 @@ func BaseProperties(obj BaseComputeResource) (*mo.ComputeResource, error) {
 // BasePropertiesFromReference combines BaseFromReference and BaseProperties to
 // get a base-level ComputeResource managed object for a specific managed
 // object reference.
-func BasePropertiesFromReference(client *govmomi.Client, ref types.ManagedObjectReference) (*mo.ComputeResource, error) {
-	obj, err := BaseFromReference(client, ref)
+func BasePropertiesFromReference(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference) (*mo.ComputeResource, error) {
+	obj, err := BaseFromReference(ctx, client, ref)
 	if err != nil {
 		return nil, err
 	}
-	return BaseProperties(obj)
+	return BaseProperties(ctx, obj)
 }
 
 // DefaultDevicesFromReference fetches the default virtual device list for a
 // specific compute resource from a supplied managed object reference.
-func DefaultDevicesFromReference(client *govmomi.Client, ref types.ManagedObjectReference, guest string) (object.VirtualDeviceList, error) {
+func DefaultDevicesFromReference(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference, guest string) (object.VirtualDeviceList, error) {
 	log.Printf("[DEBUG] Fetching default device list for object reference %q for OS type %q", ref.Value, guest)
-	b, err := EnvironmentBrowserFromReference(client, ref)
+	b, err := EnvironmentBrowserFromReference(ctx, client, ref)
 	if err != nil {
 		return nil, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return b.DefaultDevices(ctx, "", nil)
 }
@@ -170,12 +171,12 @@ func DefaultDevicesFromReference(client *govmomi.Client, ref types.ManagedObject
 // OSFamily uses the compute resource's environment browser to get the OS family
 // for a specific guest ID. The list of supported OS is dependent on the hardware version of the vm/template
 // so that is also passed to the environment browser.
-func OSFamily(client *govmomi.Client, ref types.ManagedObjectReference, guest string, hardwareVersion int) (string, error) {
-	b, err := EnvironmentBrowserFromReference(client, ref)
+func OSFamily(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference, guest string, hardwareVersion int) (string, error) {
+	b, err := EnvironmentBrowserFromReference(ctx, client, ref)
 	if err != nil {
 		return "", err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return b.OSFamily(ctx, guest, hardwareVersion)
 }
@@ -188,12 +189,12 @@ func OSFamily(client *govmomi.Client, ref types.ManagedObjectReference, guest st
 // an unset environmentBrowser attribute, this function will return an error.
 // This is to protect against cases where this may come up such as licensing
 // issues or clusters without hosts.
-func EnvironmentBrowserFromReference(client *govmomi.Client, ref types.ManagedObjectReference) (*envbrowse.EnvironmentBrowser, error) {
-	cr, err := BaseFromReference(client, ref)
+func EnvironmentBrowserFromReference(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference) (*envbrowse.EnvironmentBrowser, error) {
+	cr, err := BaseFromReference(ctx, client, ref)
 	if err != nil {
 		return nil, err
 	}
-	props, err := BaseProperties(cr)
+	props, err := BaseProperties(ctx, cr)
 	if err != nil {
 		return nil, err
 	}
@@ -209,7 +210,7 @@ func EnvironmentBrowserFromReference(client *govmomi.Client, ref types.ManagedOb
 // Reconfigure reconfigures any BaseComputeResource that uses a
 // BaseComputeResourceConfigSpec as configuration (example: standalone hosts,
 // or clusters). Modify is always set.
-func Reconfigure(obj BaseComputeResource, spec types.BaseComputeResourceConfigSpec) error {
+func Reconfigure(ctx context.Context, obj BaseComputeResource, spec types.BaseComputeResourceConfigSpec) error {
 	var c *object.ComputeResource
 	switch t := obj.(type) {
 	case *object.ComputeResource:
@@ -222,13 +223,13 @@ func Reconfigure(obj BaseComputeResource, spec types.BaseComputeResourceConfigSp
 		return fmt.Errorf("unsupported type for reconfigure: %T", t)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := c.Reconfigure(ctx, spec, true)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // HasChildren checks to see if a compute resource has any child items (hosts
@@ -237,8 +238,8 @@ func Reconfigure(obj BaseComputeResource, spec types.BaseComputeResourceConfigSp
 // compute resource in vSphere destroys *all* children if at all possible
 // (including removing hosts and virtual machines), so extra verification is
 // necessary to prevent accidental removal.
-func HasChildren(obj BaseComputeResource) (bool, error) {
-	props, err := BaseProperties(obj)
+func HasChildren(ctx context.Context, obj BaseComputeResource) (bool, error) {
+	props, err := BaseProperties(ctx, obj)
 	if err != nil {
 		return false, err
 	}
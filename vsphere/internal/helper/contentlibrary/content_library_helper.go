@@ -28,13 +28,13 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/ovfdeploy"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/tlshelper"
 )
 
 // FromName accepts a Content Library name and returns a Library object.
-func FromName(c *rest.Client, name string) (*library.Library, error) {
+func FromName(ctx context.Context, c *rest.Client, name string) (*library.Library, error) {
 	log.Printf("[DEBUG] contentlibrary.FromName: Retrieving content library %s by name", name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	lib, err := clm.GetLibraryByName(ctx, name)
 	if err != nil {
 		return nil, provider.Error(name, "FromName", err)
@@ -47,10 +47,9 @@ func FromName(c *rest.Client, name string) (*library.Library, error) {
 }
 
 // FromID accepts a Content Library ID and returns a Library object.
-func FromID(c *rest.Client, id string) (*library.Library, error) {
+func FromID(ctx context.Context, c *rest.Client, id string) (*library.Library, error) {
 	log.Printf("[DEBUG] contentlibrary.FromID: Retrieving content library %s by ID", id)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	lib, err := clm.GetLibraryByID(ctx, id)
 	if err != nil {
 		return nil, provider.Error(id, "FromID", err)
@@ -63,11 +62,10 @@ func FromID(c *rest.Client, id string) (*library.Library, error) {
 }
 
 // CreateLibrary creates a Content Library.
-func CreateLibrary(d *schema.ResourceData, restclient *rest.Client, backings []library.StorageBacking) (string, error) {
+func CreateLibrary(ctx context.Context, d *schema.ResourceData, restclient *rest.Client, backings []library.StorageBacking) (string, error) {
 	name := d.Get("name").(string)
 	log.Printf("[DEBUG] contentlibrary.CreateLibrary: Creating content library %s", name)
 	clm := library.NewManager(restclient)
-	ctx := context.TODO()
 	description := d.Get("description").(string)
 	lib := library.Library{
 		Description: &description,
@@ -105,10 +103,9 @@ func CreateLibrary(d *schema.ResourceData, restclient *rest.Client, backings []l
 }
 
 // DeleteLibrary deletes a Content Library.
-func DeleteLibrary(c *rest.Client, lib *library.Library) error {
+func DeleteLibrary(ctx context.Context, c *rest.Client, lib *library.Library) error {
 	log.Printf("[DEBUG] contentlibrary.DeleteLibrary: Deleting library %s", lib.Name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	err := clm.DeleteLibrary(ctx, lib)
 	if err != nil {
 		return provider.Error(lib.ID, "DeleteLibrary", err)
@@ -118,10 +115,9 @@ func DeleteLibrary(c *rest.Client, lib *library.Library) error {
 }
 
 // ItemFromName accepts a Content Library item name along with a Content Library and will return the item object.
-func ItemFromName(c *rest.Client, l *library.Library, name string) (*library.Item, error) {
+func ItemFromName(ctx context.Context, c *rest.Client, l *library.Library, name string) (*library.Item, error) {
 	log.Printf("[DEBUG] contentlibrary.ItemFromName: Retrieving library item %s.", name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	fi := library.FindItem{
 		LibraryID: l.ID,
 		Name:      name,
@@ -142,10 +138,9 @@ func ItemFromName(c *rest.Client, l *library.Library, name string) (*library.Ite
 }
 
 // ItemFromID accepts a Content Library item ID and will return the item object.
-func ItemFromID(c *rest.Client, id string) (*library.Item, error) {
+func ItemFromID(ctx context.Context, c *rest.Client, id string) (*library.Item, error) {
 	log.Printf("[DEBUG] contentlibrary.ItemFromID: Retrieving library item %s", id)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	item, err := clm.GetLibraryItem(ctx, id)
 	if err != nil {
 		return nil, provider.Error(id, "ItemFromID", err)
@@ -155,17 +150,18 @@ func ItemFromID(c *rest.Client, id string) (*library.Item, error) {
 }
 
 // IsContentLibraryItem accepts an ID and determines if that ID is associated with an item in a Content Library.
-func IsContentLibraryItem(c *rest.Client, id string) bool {
+func IsContentLibraryItem(ctx context.Context, c *rest.Client, id string) bool {
 	log.Printf("[DEBUG] contentlibrary.IsContentLibrary: Checking if %s is a content library source", id)
-	item, _ := ItemFromID(c, id)
+	item, _ := ItemFromID(ctx, c, id)
 	return item != nil
 }
 
-// CreateLibraryItem creates an item in a Content Library.
-func CreateLibraryItem(c *rest.Client, l *library.Library, name string, desc string, t string, file string, moid string) (*string, error) {
+// CreateLibraryItem creates an item in a Content Library. Remote files are
+// downloaded with the certificate verification options in tlsOptions, and
+// their certificates are not verified if insecure is set.
+func CreateLibraryItem(ctx context.Context, c *rest.Client, tlsOptions *tlshelper.Options, insecure bool, l *library.Library, name string, desc string, t string, file string, moid string) (*string, error) {
 	log.Printf("[DEBUG] contentlibrary.CreateLibraryItem: Creating content library item %s.", name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	item := library.Item{
 		Description: &desc,
 		LibraryID:   l.ID,
@@ -178,9 +174,15 @@ func CreateLibraryItem(c *rest.Client, l *library.Library, name string, desc str
 		LibraryID:             l.ID,
 	}
 	if moid != "" {
-		return uploadSession.cloneTemplate(moid, name, t)
+		return uploadSession.cloneTemplate(ctx, moid, name, desc, t)
 	}
 
+	httpClient, err := tlsOptions.HTTPClient(insecure)
+	if err != nil {
+		return nil, provider.Error(name, "CreateLibraryItem", err)
+	}
+	uploadSession.HTTPClient = httpClient
+
 	id, err := clm.CreateLibraryItem(ctx, item)
 	if err != nil {
 		return nil, provider.Error(name, "CreateLibraryItem", err)
@@ -211,7 +213,7 @@ func CreateLibraryItem(c *rest.Client, l *library.Library, name string, desc str
 
 	var ovfDescriptor string
 	if !isIso {
-		ovfDescriptor, err = ovfdeploy.GetOvfDescriptor(file, isOva, isLocal, true)
+		ovfDescriptor, err = ovfdeploy.GetOvfDescriptor(file, isOva, isLocal, httpClient)
 		if err != nil {
 			return nil, provider.Error(name, "CreateLibraryItem", err)
 		}
@@ -219,23 +221,22 @@ func CreateLibraryItem(c *rest.Client, l *library.Library, name string, desc str
 
 	switch {
 	case isLocal && isOva:
-		return &id, uploadSession.deployLocalOva(file, ovfDescriptor)
+		return &id, uploadSession.deployLocalOva(ctx, file, ovfDescriptor)
 	case isLocal && !isOva && !isIso:
-		return &id, uploadSession.deployLocalOvf(file, ovfDescriptor)
+		return &id, uploadSession.deployLocalOvf(ctx, file, ovfDescriptor)
 	case isLocal && isIso:
-		return &id, uploadSession.deployLocalIso(file)
+		return &id, uploadSession.deployLocalIso(ctx, file)
 	case !isLocal && isOva:
-		return &id, uploadSession.deployRemoteOva(file, ovfDescriptor)
+		return &id, uploadSession.deployRemoteOva(ctx, file, ovfDescriptor)
 	case !isLocal && !isOva:
-		return &id, uploadSession.deployRemoteOvf(file)
+		return &id, uploadSession.deployRemoteOvf(ctx, file)
 	}
 
 	log.Printf("[DEBUG] contentlibrary.CreateLibraryItem: Successfully created content library item %s.", name)
 	return &id, nil
 }
 
-func (uploadSession *libraryUploadSession) deployRemoteOvf(file string) error {
-	ctx := context.TODO()
+func (uploadSession *libraryUploadSession) deployRemoteOvf(ctx context.Context, file string) error {
 	_, err := uploadSession.ContentLibraryManager.AddLibraryItemFileFromURI(ctx, uploadSession.UploadSession, filepath.Base(file), file)
 	if err != nil {
 		return err
@@ -243,54 +244,54 @@ func (uploadSession *libraryUploadSession) deployRemoteOvf(file string) error {
 	return uploadSession.ContentLibraryManager.WaitOnLibraryItemUpdateSession(ctx, uploadSession.UploadSession, time.Second*10, func() { log.Printf("Waiting...") })
 }
 
-func (uploadSession *libraryUploadSession) deployRemoteOva(file string, ovfDescriptor string) error {
+func (uploadSession *libraryUploadSession) deployRemoteOva(ctx context.Context, file string, ovfDescriptor string) error {
 	e, err := readEnvelope(ovfDescriptor)
 	if err != nil {
 		return fmt.Errorf("failed to parse ovf: %s", err)
 	}
 	name := strings.TrimSuffix(filepath.Base(file), "ova")
-	if err := uploadSession.uploadString(ovfDescriptor, name+"ovf"); err != nil {
+	if err := uploadSession.uploadString(ctx, ovfDescriptor, name+"ovf"); err != nil {
 		return err
 	}
 	for _, disk := range e.References {
-		if err := uploadSession.uploadOvaDisksFromURL(file, disk.Href, int64(disk.Size)); err != nil {
+		if err := uploadSession.uploadOvaDisksFromURL(ctx, file, disk.Href, int64(disk.Size)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (uploadSession *libraryUploadSession) deployLocalOvf(file string, ovfDescriptor string) error {
+func (uploadSession *libraryUploadSession) deployLocalOvf(ctx context.Context, file string, ovfDescriptor string) error {
 	e, err := readEnvelope(ovfDescriptor)
 	if err != nil {
 		return fmt.Errorf("failed to parse ovf: %s", err)
 	}
-	if err := uploadSession.uploadLocalFile(file); err != nil {
+	if err := uploadSession.uploadLocalFile(ctx, file); err != nil {
 		return err
 	}
 	dir := filepath.Dir(file)
 	for i := range e.References {
-		if err := uploadSession.uploadLocalFile(dir + "/" + e.References[i].Href); err != nil {
+		if err := uploadSession.uploadLocalFile(ctx, dir+"/"+e.References[i].Href); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (uploadSession *libraryUploadSession) deployLocalOva(file string, ovfDescriptor string) error {
+func (uploadSession *libraryUploadSession) deployLocalOva(ctx context.Context, file string, ovfDescriptor string) error {
 	e, err := readEnvelope(ovfDescriptor)
 	if err != nil {
 		return fmt.Errorf("failed to parse ovf: %s", err)
 	}
 	name := strings.TrimSuffix(filepath.Base(file), "ova")
-	if err := uploadSession.uploadString(ovfDescriptor, name+"ovf"); err != nil {
+	if err := uploadSession.uploadString(ctx, ovfDescriptor, name+"ovf"); err != nil {
 		return err
 	}
-	return uploadSession.uploadOvaDisksFromLocal(file, e)
+	return uploadSession.uploadOvaDisksFromLocal(ctx, file, e)
 }
 
-func (uploadSession *libraryUploadSession) deployLocalIso(file string) error {
-	if err := uploadSession.uploadLocalFile(file); err != nil {
+func (uploadSession *libraryUploadSession) deployLocalIso(ctx context.Context, file string) error {
+	if err := uploadSession.uploadLocalFile(ctx, file); err != nil {
 		return err
 	}
 	return nil
@@ -301,14 +302,15 @@ type libraryUploadSession struct {
 	RestClient            *rest.Client
 	UploadSession         string
 	LibraryID             string
+	HTTPClient            *http.Client
 }
 
-func (uploadSession libraryUploadSession) cloneTemplate(moid string, name string, templateType string) (*string, error) {
-	ctx := context.TODO()
+func (uploadSession libraryUploadSession) cloneTemplate(ctx context.Context, moid string, name string, desc string, templateType string) (*string, error) {
 	if templateType == "ovf" {
 		ovfItem := vcenter.OVF{
 			Spec: vcenter.CreateSpec{
-				Name: name,
+				Name:        name,
+				Description: desc,
 			},
 			Source: vcenter.ResourceID{
 				Value: moid,
@@ -326,20 +328,20 @@ func (uploadSession libraryUploadSession) cloneTemplate(moid string, name string
 	return nil, fmt.Errorf("unsupported template type. Only ovf can be used when cloning from vCenter")
 }
 
-func (uploadSession libraryUploadSession) uploadString(data string, name string) error {
+func (uploadSession libraryUploadSession) uploadString(ctx context.Context, data string, name string) error {
 	stringReader := strings.NewReader(data)
 	openFile := io.Reader(stringReader)
 	size := int64(len([]byte(data)))
-	return uploadSession.upload(name, &openFile, size)
+	return uploadSession.upload(ctx, name, &openFile, size)
 }
 
-func (uploadSession libraryUploadSession) uploadLocalFile(file string) error {
+func (uploadSession libraryUploadSession) uploadLocalFile(ctx context.Context, file string) error {
 	openFile, size, err := openLocalFile(file)
 	if err != nil {
 		return err
 	}
 
-	return uploadSession.upload(filepath.Base(file), openFile, *size)
+	return uploadSession.upload(ctx, filepath.Base(file), openFile, *size)
 }
 
 func openLocalFile(file string) (*io.Reader, *int64, error) {
@@ -357,7 +359,7 @@ func openLocalFile(file string) (*io.Reader, *int64, error) {
 	return &openFileReader, &size, nil
 }
 
-func (uploadSession libraryUploadSession) uploadOvaDisksFromLocal(ovaFilePath string, envelope *ovf.Envelope) error {
+func (uploadSession libraryUploadSession) uploadOvaDisksFromLocal(ctx context.Context, ovaFilePath string, envelope *ovf.Envelope) error {
 	ovaFile, _, err := openLocalFile(ovaFilePath)
 	if err != nil {
 		return err
@@ -366,15 +368,15 @@ func (uploadSession libraryUploadSession) uploadOvaDisksFromLocal(ovaFilePath st
 	for _, disk := range envelope.References {
 		size := disk.Size
 		fileName := disk.Href
-		if err = uploadSession.findAndUploadDiskFromOva(*ovaFile, fileName, int64(size)); err != nil {
+		if err = uploadSession.findAndUploadDiskFromOva(ctx, *ovaFile, fileName, int64(size)); err != nil {
 			return err
 		}
 	}
 	return err
 }
 
-func (uploadSession libraryUploadSession) uploadOvaDisksFromURL(ovfFilePath string, diskName string, size int64) error {
-	client := &http.Client{}
+func (uploadSession libraryUploadSession) uploadOvaDisksFromURL(ctx context.Context, ovfFilePath string, diskName string, size int64) error {
+	client := uploadSession.HTTPClient
 	req, err := http.NewRequest("GET", ovfFilePath, nil)
 	if err != nil {
 		return fmt.Errorf("error creating request for %s: %w", ovfFilePath, err)
@@ -395,7 +397,7 @@ func (uploadSession libraryUploadSession) uploadOvaDisksFromURL(ovfFilePath stri
 		return fmt.Errorf("got status %d (%s) while getting file from %s", resp.StatusCode, http.StatusText(resp.StatusCode), ovfFilePath)
 	}
 
-	err = uploadSession.findAndUploadDiskFromOva(resp.Body, diskName, size)
+	err = uploadSession.findAndUploadDiskFromOva(ctx, resp.Body, diskName, size)
 	if err != nil {
 		return fmt.Errorf("error processing OVA data for disk %s: %w", diskName, err)
 	}
@@ -403,7 +405,7 @@ func (uploadSession libraryUploadSession) uploadOvaDisksFromURL(ovfFilePath stri
 	return nil
 }
 
-func (uploadSession libraryUploadSession) findAndUploadDiskFromOva(ovaFile io.Reader, diskName string, size int64) error {
+func (uploadSession libraryUploadSession) findAndUploadDiskFromOva(ctx context.Context, ovaFile io.Reader, diskName string, size int64) error {
 	log.Printf("[DEBUG] findAndUploadDiskFromOva: Finding %s", diskName)
 	ovaReader := tar.NewReader(ovaFile)
 	for {
@@ -417,7 +419,7 @@ func (uploadSession libraryUploadSession) findAndUploadDiskFromOva(ovaFile io.Re
 		if fileHdr.Name == diskName {
 			log.Printf("[DEBUG] findAndUploadDiskFromOva: %s found", diskName)
 			ioOvaReader := io.Reader(ovaReader)
-			err = uploadSession.upload(diskName, &ioOvaReader, size)
+			err = uploadSession.upload(ctx, diskName, &ioOvaReader, size)
 			if err != nil {
 				return fmt.Errorf("error while uploading the file %s %s", diskName, err)
 			}
@@ -436,9 +438,7 @@ func readEnvelope(data string) (*ovf.Envelope, error) {
 	return e, nil
 }
 
-func (uploadSession libraryUploadSession) upload(name string, file *io.Reader, size int64) error {
-	ctx := context.TODO()
-
+func (uploadSession libraryUploadSession) upload(ctx context.Context, name string, file *io.Reader, size int64) error {
 	info := library.UpdateFile{
 		Name:       name,
 		SourceType: "PUSH",
@@ -460,10 +460,9 @@ func (uploadSession libraryUploadSession) upload(name string, file *io.Reader, s
 }
 
 // DeleteLibraryItem deletes an item from a Content Library.
-func DeleteLibraryItem(c *rest.Client, item *library.Item) error {
+func DeleteLibraryItem(ctx context.Context, c *rest.Client, item *library.Item) error {
 	log.Printf("[DEBUG] contentlibrary.DeleteLibraryItem: Deleting content library item %s.", item.Name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	err := clm.DeleteLibraryItem(ctx, item)
 	if err != nil {
 		return err
@@ -473,11 +472,11 @@ func DeleteLibraryItem(c *rest.Client, item *library.Item) error {
 }
 
 // ExpandStorageBackings takes ResourceData, and returns a list of StorageBackings.
-func ExpandStorageBackings(c *govmomi.Client, d *schema.ResourceData) ([]library.StorageBacking, error) {
+func ExpandStorageBackings(ctx context.Context, c *govmomi.Client, d *schema.ResourceData) ([]library.StorageBacking, error) {
 	log.Printf("[DEBUG] contentlibrary.ExpandStorageBackings: Expanding OVF storage backing.")
 	var sb []library.StorageBacking
 	for _, dsID := range d.Get("storage_backing").(*schema.Set).List() {
-		ds, err := datastore.FromID(c, dsID.(string))
+		ds, err := datastore.FromID(ctx, c, dsID.(string))
 		if err != nil {
 			return nil, provider.Error(d.Id(), "ExpandStorageBackings", err)
 		}
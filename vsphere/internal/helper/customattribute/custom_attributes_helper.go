@@ -85,7 +85,7 @@ type DiffProcessor struct {
 	newAttributes map[string]interface{}
 }
 
-func (p *DiffProcessor) clearRemovedAttributes(subject object.Reference) error {
+func (p *DiffProcessor) clearRemovedAttributes(ctx context.Context, subject object.Reference) error {
 	for k := range p.oldAttributes {
 		_, ok := p.newAttributes[k]
 		if !ok {
@@ -93,7 +93,7 @@ func (p *DiffProcessor) clearRemovedAttributes(subject object.Reference) error {
 			if err != nil {
 				return err
 			}
-			err = p.fm.Set(context.TODO(), subject.Reference(), int32(key), "")
+			err = p.fm.Set(ctx, subject.Reference(), int32(key), "")
 			if err != nil {
 				return err
 			}
@@ -102,13 +102,13 @@ func (p *DiffProcessor) clearRemovedAttributes(subject object.Reference) error {
 	return nil
 }
 
-func (p *DiffProcessor) setNewAttributes(subject object.Reference) error {
+func (p *DiffProcessor) setNewAttributes(ctx context.Context, subject object.Reference) error {
 	for k, v := range p.newAttributes {
 		key, err := strconv.ParseInt(k, 10, 32)
 		if err != nil {
 			return err
 		}
-		err = p.fm.Set(context.TODO(), subject.Reference(), int32(key), v.(string))
+		err = p.fm.Set(ctx, subject.Reference(), int32(key), v.(string))
 		if err != nil {
 			return err
 		}
@@ -116,11 +116,11 @@ func (p *DiffProcessor) setNewAttributes(subject object.Reference) error {
 	return nil
 }
 
-func (p *DiffProcessor) ProcessDiff(subject object.Reference) error {
-	if err := p.clearRemovedAttributes(subject); err != nil {
+func (p *DiffProcessor) ProcessDiff(ctx context.Context, subject object.Reference) error {
+	if err := p.clearRemovedAttributes(ctx, subject); err != nil {
 		return fmt.Errorf("error clearing removed attributes for object ID %q: %s", subject.Reference().Value, err)
 	}
-	if err := p.setNewAttributes(subject); err != nil {
+	if err := p.setNewAttributes(ctx, subject); err != nil {
 		return fmt.Errorf("error setting attributes for object ID %q: %s", subject.Reference().Value, err)
 	}
 	return nil
@@ -150,8 +150,8 @@ func GetDiffProcessorIfAttributesDefined(client *govmomi.Client, d *schema.Resou
 	}, nil
 }
 
-func ByName(fm *object.CustomFieldsManager, name string) (*types.CustomFieldDef, error) {
-	fields, err := fm.Field(context.TODO())
+func ByName(ctx context.Context, fm *object.CustomFieldsManager, name string) (*types.CustomFieldDef, error) {
+	fields, err := fm.Field(ctx)
 	if err != nil {
 		return nil, err
 	}
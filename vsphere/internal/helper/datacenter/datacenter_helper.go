@@ -15,21 +15,21 @@ import (
 )
 
 // FromPath returns a Datacenter via its supplied path.
-func FromPath(client *govmomi.Client, path string) (*object.Datacenter, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, path string) (*object.Datacenter, error) {
 	finder := find.NewFinder(client.Client, false)
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return finder.Datacenter(ctx, path)
 }
 
 // FromInventoryPath returns the Datacenter object which is part of a given InventoryPath
-func FromInventoryPath(client *govmomi.Client, inventoryPath string) (*object.Datacenter, error) {
+func FromInventoryPath(ctx context.Context, client *govmomi.Client, inventoryPath string) (*object.Datacenter, error) {
 	dcPath, err := folder.RootPathParticleDatastore.SplitDatacenter(inventoryPath)
 	if err != nil {
 		return nil, err
 	}
-	dc, err := FromPath(client, dcPath)
+	dc, err := FromPath(ctx, client, dcPath)
 	if err != nil {
 		return nil, err
 	}
@@ -18,10 +18,11 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/folder"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // FromID locates a Datastore by its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (*object.Datastore, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*object.Datastore, error) {
 	log.Printf("[DEBUG] Locating datastore with ID %q", id)
 	finder := find.NewFinder(client.Client, false)
 
@@ -30,7 +31,7 @@ func FromID(client *govmomi.Client, id string) (*object.Datastore, error) {
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	ds, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -45,23 +46,22 @@ func FromID(client *govmomi.Client, id string) (*object.Datastore, error) {
 
 // FromPath loads a datastore from its path. The datacenter is optional if the
 // path is specific enough to not require it.
-func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*object.Datastore, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.Datastore, error) {
 	finder := find.NewFinder(client.Client, false)
 	if dc != nil {
 		finder.SetDatacenter(dc)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return finder.Datastore(ctx, name)
 }
 
-func List(client *govmomi.Client) ([]*object.Datastore, error) {
-	return getDatastores(client, "/*")
+func List(ctx context.Context, client *govmomi.Client) ([]*object.Datastore, error) {
+	return getDatastores(ctx, client, "/*")
 }
 
-func getDatastores(client *govmomi.Client, path string) ([]*object.Datastore, error) {
-	ctx := context.TODO()
+func getDatastores(ctx context.Context, client *govmomi.Client, path string) ([]*object.Datastore, error) {
 	var dss []*object.Datastore
 	finder := find.NewFinder(client.Client, false)
 	es, err := finder.ManagedObjectListChildren(ctx, path+"/*", "datastore", "folder", "storagepod")
@@ -71,13 +71,13 @@ func getDatastores(client *govmomi.Client, path string) ([]*object.Datastore, er
 	for _, id := range es {
 		switch {
 		case id.Object.Reference().Type == "Datastore":
-			ds, err := FromID(client, id.Object.Reference().Value)
+			ds, err := FromID(ctx, client, id.Object.Reference().Value)
 			if err != nil {
 				return nil, err
 			}
 			dss = append(dss, ds)
 		case id.Object.Reference().Type == "Folder" || id.Object.Reference().Type == "Storagepod":
-			newDSs, err := getDatastores(client, id.Path)
+			newDSs, err := getDatastores(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -91,8 +91,8 @@ func getDatastores(client *govmomi.Client, path string) ([]*object.Datastore, er
 
 // Properties is a convenience method that wraps fetching the
 // Datastore MO from its higher-level object.
-func Properties(ds *object.Datastore) (*mo.Datastore, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, ds *object.Datastore) (*mo.Datastore, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.Datastore
 	if err := ds.Properties(ctx, ds.Reference(), nil, &props); err != nil {
@@ -101,21 +101,21 @@ func Properties(ds *object.Datastore) (*mo.Datastore, error) {
 	return &props, nil
 }
 
-func Unmount(client *govmomi.Client, ds *object.Datastore) error {
-	dsprops, err := Properties(ds)
+func Unmount(ctx context.Context, client *govmomi.Client, ds *object.Datastore) error {
+	dsprops, err := Properties(ctx, ds)
 	if err != nil {
 		return err
 	}
 	for _, h := range dsprops.Host {
-		host, err := hostsystem.FromID(client, h.Key.Value)
+		host, err := hostsystem.FromID(ctx, client, h.Key.Value)
 		if err != nil {
 			return err
 		}
-		hds, err := host.ConfigManager().DatastoreSystem(context.TODO())
+		hds, err := host.ConfigManager().DatastoreSystem(ctx)
 		if err != nil {
 			return err
 		}
-		err = hds.Remove(context.TODO(), ds)
+		err = hds.Remove(ctx, ds)
 		if err != nil {
 			return err
 		}
@@ -126,34 +126,34 @@ func Unmount(client *govmomi.Client, ds *object.Datastore) error {
 // MoveToFolder is a complex method that moves a datastore to a given
 // relative datastore folder path. "Relative" here means relative to a
 // datacenter, which is discovered from the current datastore path.
-func MoveToFolder(client *govmomi.Client, ds *object.Datastore, relative string) error {
-	f, err := folder.DatastoreFolderFromObject(client, ds, relative)
+func MoveToFolder(ctx context.Context, client *govmomi.Client, ds *object.Datastore, relative string) error {
+	f, err := folder.DatastoreFolderFromObject(ctx, client, ds, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(ds.Reference(), f)
+	return folder.MoveObjectTo(ctx, ds.Reference(), f)
 }
 
 // MoveToFolderRelativeHostSystemID is a complex method that moves a
 // datastore to a given datastore path, similar to MoveToFolder,
 // except the path is relative to a HostSystem supplied by ID instead of the
 // datastore.
-func MoveToFolderRelativeHostSystemID(client *govmomi.Client, ds *object.Datastore, hsID, relative string) error {
-	hs, err := hostsystem.FromID(client, hsID)
+func MoveToFolderRelativeHostSystemID(ctx context.Context, client *govmomi.Client, ds *object.Datastore, hsID, relative string) error {
+	hs, err := hostsystem.FromID(ctx, client, hsID)
 	if err != nil {
 		return err
 	}
-	f, err := folder.DatastoreFolderFromObject(client, hs, relative)
+	f, err := folder.DatastoreFolderFromObject(ctx, client, hs, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(ds.Reference(), f)
+	return folder.MoveObjectTo(ctx, ds.Reference(), f)
 }
 
 // Browser returns the HostDatastoreBrowser for a certain datastore. This is a
 // convenience method that exists to abstract the context.
-func Browser(ds *object.Datastore) (*object.HostDatastoreBrowser, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Browser(ctx context.Context, ds *object.Datastore) (*object.HostDatastoreBrowser, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return ds.Browser(ctx)
 }
@@ -162,8 +162,8 @@ func Browser(ds *object.Datastore) (*object.HostDatastoreBrowser, error) {
 // and a supplied path. The current implementation only returns the basic
 // information, so all FileQueryFlags set, but not any flags for specific types
 // of files.
-func SearchDatastore(ds *object.Datastore, name string) ([]*types.FileInfo, error) {
-	result, err := searchDatastore(ds, name)
+func SearchDatastore(ctx context.Context, ds *object.Datastore, name string) ([]*types.FileInfo, error) {
+	result, err := searchDatastore(ctx, ds, name)
 	if err != nil {
 		return nil, err
 	}
@@ -174,8 +174,8 @@ func SearchDatastore(ds *object.Datastore, name string) ([]*types.FileInfo, erro
 	return files, nil
 }
 
-func searchDatastore(ds *object.Datastore, name string) (*types.HostDatastoreBrowserSearchResults, error) {
-	browser, err := Browser(ds)
+func searchDatastore(ctx context.Context, ds *object.Datastore, name string) (*types.HostDatastoreBrowserSearchResults, error) {
+	browser, err := Browser(ctx, ds)
 	if err != nil {
 		return nil, err
 	}
@@ -204,15 +204,15 @@ func searchDatastore(ds *object.Datastore, name string) (*types.HostDatastoreBro
 			Modification: true,
 		},
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := browser.SearchDatastore(ctx, dp.String(), spec)
 	if err != nil {
 		return nil, err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	info, err := task.WaitForResultEx(tctx, nil)
+	info, err := viapi.WaitForTaskResult(tctx, task)
 	if err != nil {
 		return nil, err
 	}
@@ -223,8 +223,8 @@ func searchDatastore(ds *object.Datastore, name string) (*types.HostDatastoreBro
 // FileExists takes a path in the datastore and checks to see if it exists.
 //
 // The path should be a bare path, not a datastore path. Globs are not allowed.
-func FileExists(ds *object.Datastore, name string) (bool, error) {
-	files, err := SearchDatastore(ds, name)
+func FileExists(ctx context.Context, ds *object.Datastore, name string) (bool, error) {
+	files, err := SearchDatastore(ctx, ds, name)
 	if err != nil {
 		return false, err
 	}
@@ -35,14 +35,14 @@ func (e *MissingPortGroupReferenceError) Error() string {
 }
 
 // FromKey gets a portgroup object from its key.
-func FromKey(client *govmomi.Client, dvsUUID, pgKey string) (*object.DistributedVirtualPortgroup, error) {
+func FromKey(ctx context.Context, client *govmomi.Client, dvsUUID, pgKey string) (*object.DistributedVirtualPortgroup, error) {
 	dvsm := types.ManagedObjectReference{Type: "DistributedVirtualSwitchManager", Value: "DVSManager"}
 	req := &types.DVSManagerLookupDvPortGroup{
 		This:         dvsm,
 		SwitchUuid:   dvsUUID,
 		PortgroupKey: pgKey,
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	resp, err := methods.DVSManagerLookupDvPortGroup(ctx, client, req)
 	if err != nil {
@@ -59,11 +59,11 @@ func FromKey(client *govmomi.Client, dvsUUID, pgKey string) (*object.Distributed
 		)
 	}
 
-	return FromMOID(client, resp.Returnval.Reference().Value)
+	return FromMOID(ctx, client, resp.Returnval.Reference().Value)
 }
 
 // FromMOID locates a portgroup by its managed object reference ID.
-func FromMOID(client *govmomi.Client, id string) (*object.DistributedVirtualPortgroup, error) {
+func FromMOID(ctx context.Context, client *govmomi.Client, id string) (*object.DistributedVirtualPortgroup, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -71,7 +71,7 @@ func FromMOID(client *govmomi.Client, id string) (*object.DistributedVirtualPort
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	ds, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -84,13 +84,13 @@ func FromMOID(client *govmomi.Client, id string) (*object.DistributedVirtualPort
 }
 
 // FromPath gets a portgroup object from its path.
-func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*object.DistributedVirtualPortgroup, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.DistributedVirtualPortgroup, error) {
 	finder := find.NewFinder(client.Client, false)
 	if dc != nil {
 		finder.SetDatacenter(dc)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	net, err := finder.Network(ctx, name)
 	if err != nil {
@@ -99,13 +99,13 @@ func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*obje
 	if net.Reference().Type != "DistributedVirtualPortgroup" {
 		return nil, fmt.Errorf("network at path %q is not a portgroup (type %s)", name, net.Reference().Type)
 	}
-	return FromMOID(client, net.Reference().Value)
+	return FromMOID(ctx, client, net.Reference().Value)
 }
 
 // Properties is a convenience method that wraps fetching the
 // portgroup MO from its higher-level object.
-func Properties(pg *object.DistributedVirtualPortgroup) (*mo.DistributedVirtualPortgroup, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, pg *object.DistributedVirtualPortgroup) (*mo.DistributedVirtualPortgroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.DistributedVirtualPortgroup
 	if err := pg.Properties(ctx, pg.Reference(), nil, &props); err != nil {
@@ -117,13 +117,13 @@ func Properties(pg *object.DistributedVirtualPortgroup) (*mo.DistributedVirtualP
 // Create exposes the CreateDVPortgroup_Task method of the
 // DistributedVirtualSwitch MO.  This local implementation may go away if this
 // is exposed in the higher-level object upstream.
-func Create(client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, spec types.DVPortgroupConfigSpec) (*object.Task, error) {
+func Create(ctx context.Context, client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, spec types.DVPortgroupConfigSpec) (*object.Task, error) {
 	req := &types.CreateDVPortgroup_Task{
 		This: dvs.Reference(),
 		Spec: spec,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	resp, err := methods.CreateDVPortgroup_Task(ctx, client, req)
 	if err != nil {
@@ -0,0 +1,238 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package firstclassdisk
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vslm"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+// manager returns the VStorageObjectManager for the supplied client.
+func manager(client *govmomi.Client) *vslm.ObjectManager {
+	return vslm.NewObjectManager(client.Client)
+}
+
+// FromID locates a first class disk by its ID on the supplied datastore.
+func FromID(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id string) (*types.VStorageObject, error) {
+	log.Printf("[DEBUG] Locating first class disk with ID %q on datastore %q", id, ds.Reference().Value)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	obj, err := manager(client).Retrieve(actx, ds, id)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] First class disk with ID %q found (%s)", id, obj.Config.Name)
+	return obj, nil
+}
+
+// FilePath returns the datastore path of the backing file of a first class
+// disk, or an empty string if the disk is not backed by a file.
+func FilePath(obj *types.VStorageObject) string {
+	if backing, ok := obj.Config.Backing.(*types.BaseConfigInfoDiskFileBackingInfo); ok {
+		return backing.FilePath
+	}
+	return ""
+}
+
+// ProvisioningType returns the provisioning type of the backing file of a
+// first class disk, or an empty string if the disk is not backed by a file.
+func ProvisioningType(obj *types.VStorageObject) string {
+	if backing, ok := obj.Config.Backing.(*types.BaseConfigInfoDiskFileBackingInfo); ok {
+		return backing.ProvisioningType
+	}
+	return ""
+}
+
+// Create creates a first class disk using the supplied spec, and returns the
+// new disk.
+func Create(ctx context.Context, client *govmomi.Client, spec types.VslmCreateSpec) (*types.VStorageObject, error) {
+	log.Printf("[DEBUG] Creating first class disk %q", spec.Name)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	task, err := manager(client).CreateDisk(actx, spec)
+	if err != nil {
+		return nil, err
+	}
+	info, err := viapi.WaitForTaskResult(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := info.Result.(types.VStorageObject)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %T when creating first class disk", info.Result)
+	}
+	return &obj, nil
+}
+
+// Rename renames a first class disk.
+func Rename(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id, name string) error {
+	log.Printf("[DEBUG] Renaming first class disk %q to %q", id, name)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return manager(client).Rename(actx, ds, id, name)
+}
+
+// Extend grows a first class disk to the supplied capacity, in MB.
+func Extend(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id string, capacityInMB int64) error {
+	log.Printf("[DEBUG] Extending first class disk %q to %d MB", id, capacityInMB)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	task, err := manager(client).ExtendDisk(actx, ds, id, capacityInMB)
+	if err != nil {
+		return err
+	}
+	return viapi.WaitForTask(ctx, task)
+}
+
+// Relocate moves a first class disk to another datastore. An optional
+// storage policy can be supplied to apply to the disk at its destination.
+func Relocate(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id string, dst *object.Datastore, profile []types.BaseVirtualMachineProfileSpec) error {
+	log.Printf("[DEBUG] Relocating first class disk %q from datastore %q to %q", id, ds.Reference().Value, dst.Reference().Value)
+	m := manager(client)
+	req := types.RelocateVStorageObject_Task{
+		This:      m.Reference(),
+		Id:        types.ID{Id: id},
+		Datastore: ds.Reference(),
+		Spec: types.VslmRelocateSpec{
+			VslmMigrateSpec: types.VslmMigrateSpec{
+				BackingSpec: &types.VslmCreateSpecDiskFileBackingSpec{
+					VslmCreateSpecBackingSpec: types.VslmCreateSpecBackingSpec{
+						Datastore: dst.Reference(),
+					},
+				},
+				Profile: profile,
+			},
+		},
+	}
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	res, err := methods.RelocateVStorageObject_Task(actx, client.Client, &req)
+	if err != nil {
+		return err
+	}
+	return viapi.WaitForTask(ctx, object.NewTask(client.Client, res.Returnval))
+}
+
+// UpdatePolicy applies a storage policy to a first class disk.
+func UpdatePolicy(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id string, profile []types.BaseVirtualMachineProfileSpec) error {
+	log.Printf("[DEBUG] Updating storage policy on first class disk %q", id)
+	m := manager(client)
+	req := types.UpdateVStorageObjectPolicy_Task{
+		This:      m.Reference(),
+		Id:        types.ID{Id: id},
+		Datastore: ds.Reference(),
+		Profile:   profile,
+	}
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	res, err := methods.UpdateVStorageObjectPolicy_Task(actx, client.Client, &req)
+	if err != nil {
+		return err
+	}
+	return viapi.WaitForTask(ctx, object.NewTask(client.Client, res.Returnval))
+}
+
+// Delete deletes a first class disk and its backing file.
+func Delete(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id string) error {
+	log.Printf("[DEBUG] Deleting first class disk %q", id)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	task, err := manager(client).Delete(actx, ds, id)
+	if err != nil {
+		return err
+	}
+	return viapi.WaitForTask(ctx, task)
+}
+
+// CreateSnapshot takes a snapshot of a first class disk and returns the ID of
+// the new snapshot.
+func CreateSnapshot(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id, description string) (string, error) {
+	log.Printf("[DEBUG] Creating snapshot of first class disk %q", id)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	task, err := manager(client).CreateSnapshot(actx, ds, id, description)
+	if err != nil {
+		return "", err
+	}
+	info, err := viapi.WaitForTaskResult(ctx, task)
+	if err != nil {
+		return "", err
+	}
+	sid, ok := info.Result.(types.ID)
+	if !ok {
+		return "", fmt.Errorf("unexpected result type %T when creating first class disk snapshot", info.Result)
+	}
+	return sid.Id, nil
+}
+
+// Snapshots returns the snapshots of a first class disk.
+func Snapshots(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id string) ([]types.VStorageObjectSnapshotInfoVStorageObjectSnapshot, error) {
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	info, err := manager(client).RetrieveSnapshotInfo(actx, ds, id)
+	if err != nil {
+		return nil, err
+	}
+	return info.Snapshots, nil
+}
+
+// SnapshotFromID locates a snapshot of a first class disk by its ID. nil is
+// returned if the snapshot does not exist.
+func SnapshotFromID(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id, sid string) (*types.VStorageObjectSnapshotInfoVStorageObjectSnapshot, error) {
+	snapshots, err := Snapshots(ctx, client, ds, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range snapshots {
+		if snapshots[i].Id != nil && snapshots[i].Id.Id == sid {
+			return &snapshots[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// DeleteSnapshot deletes a snapshot of a first class disk.
+func DeleteSnapshot(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id, sid string) error {
+	log.Printf("[DEBUG] Deleting snapshot %q of first class disk %q", sid, id)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	task, err := manager(client).DeleteSnapshot(actx, ds, id, sid)
+	if err != nil {
+		return err
+	}
+	return viapi.WaitForTask(ctx, task)
+}
+
+// Tags returns the tags attached to a first class disk.
+func Tags(ctx context.Context, client *govmomi.Client, id string) ([]types.VslmTagEntry, error) {
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return manager(client).ListAttachedTags(actx, id)
+}
+
+// AttachTag attaches a tag to a first class disk.
+func AttachTag(ctx context.Context, client *govmomi.Client, id string, tag types.VslmTagEntry) error {
+	log.Printf("[DEBUG] Attaching tag %q (category %q) to first class disk %q", tag.TagName, tag.ParentCategoryName, id)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return manager(client).AttachTag(actx, id, tag)
+}
+
+// DetachTag detaches a tag from a first class disk.
+func DetachTag(ctx context.Context, client *govmomi.Client, id string, tag types.VslmTagEntry) error {
+	log.Printf("[DEBUG] Detaching tag %q (category %q) from first class disk %q", tag.TagName, tag.ParentCategoryName, id)
+	actx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return manager(client).DetachTag(actx, id, tag)
+}
@@ -0,0 +1,55 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package firstclassdisk
+
+import (
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestFilePath(t *testing.T) {
+	cases := []struct {
+		name             string
+		subject          *types.VStorageObject
+		path             string
+		provisioningType string
+	}{
+		{
+			name: "file backed",
+			subject: &types.VStorageObject{
+				Config: types.VStorageObjectConfigInfo{
+					BaseConfigInfo: types.BaseConfigInfo{
+						Backing: &types.BaseConfigInfoDiskFileBackingInfo{
+							BaseConfigInfoFileBackingInfo: types.BaseConfigInfoFileBackingInfo{
+								FilePath: "[datastore1] fcd/disk.vmdk",
+							},
+							ProvisioningType: "thin",
+						},
+					},
+				},
+			},
+			path:             "[datastore1] fcd/disk.vmdk",
+			provisioningType: "thin",
+		},
+		{
+			name: "no backing",
+			subject: &types.VStorageObject{
+				Config: types.VStorageObjectConfigInfo{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := FilePath(tc.subject); actual != tc.path {
+				t.Fatalf("expected path %q, got %q", tc.path, actual)
+			}
+			if actual := ProvisioningType(tc.subject); actual != tc.provisioningType {
+				t.Fatalf("expected provisioning type %q, got %q", tc.provisioningType, actual)
+			}
+		})
+	}
+}
@@ -144,9 +144,9 @@ const (
 
 // FromAbsolutePath returns an *object.Folder from a given absolute path.
 // If no such folder is found, an appropriate error will be returned.
-func FromAbsolutePath(client *govmomi.Client, path string) (*object.Folder, error) {
+func FromAbsolutePath(ctx context.Context, client *govmomi.Client, path string) (*object.Folder, error) {
 	finder := find.NewFinder(client.Client, false)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	folder, err := finder.Folder(ctx, path)
 	if err != nil {
@@ -161,7 +161,7 @@ func FromAbsolutePath(client *govmomi.Client, path string) (*object.Folder, erro
 //
 // The list of supported object types will grow as the provider supports more
 // resources.
-func folderFromObject(client *govmomi.Client, obj interface{}, folderType RootPathParticle, relative string) (*object.Folder, error) {
+func folderFromObject(ctx context.Context, client *govmomi.Client, obj interface{}, folderType RootPathParticle, relative string) (*object.Folder, error) {
 	// If we are using this for anything else other than the root folder on ESXi,
 	// return an error.
 	if err := viapi.ValidateVirtualCenter(client); err != nil && relative != "" {
@@ -192,50 +192,50 @@ func folderFromObject(client *govmomi.Client, obj interface{}, folderType RootPa
 	if err != nil {
 		return nil, err
 	}
-	return FromAbsolutePath(client, p)
+	return FromAbsolutePath(ctx, client, p)
 }
 
 // DatastoreFolderFromObject returns an *object.Folder from a given object,
 // and relative datastore folder path. If no such folder is found, of if it is
 // not a datastore folder, an appropriate error will be returned.
-func DatastoreFolderFromObject(client *govmomi.Client, obj interface{}, relative string) (*object.Folder, error) {
-	folder, err := folderFromObject(client, obj, RootPathParticleDatastore, relative)
+func DatastoreFolderFromObject(ctx context.Context, client *govmomi.Client, obj interface{}, relative string) (*object.Folder, error) {
+	folder, err := folderFromObject(ctx, client, obj, RootPathParticleDatastore, relative)
 	if err != nil {
 		return nil, err
 	}
 
-	return validateDatastoreFolder(folder)
+	return validateDatastoreFolder(ctx, folder)
 }
 
 // HostFolderFromObject returns an *object.Folder from a given object, and
 // relative host folder path. If no such folder is found, or if it is not a
 // host folder, an appropriate error will be returned.
-func HostFolderFromObject(client *govmomi.Client, obj interface{}, relative string) (*object.Folder, error) {
-	folder, err := folderFromObject(client, obj, RootPathParticleHost, relative)
+func HostFolderFromObject(ctx context.Context, client *govmomi.Client, obj interface{}, relative string) (*object.Folder, error) {
+	folder, err := folderFromObject(ctx, client, obj, RootPathParticleHost, relative)
 	if err != nil {
 		return nil, err
 	}
 
-	return validateHostFolder(folder)
+	return validateHostFolder(ctx, folder)
 }
 
 // VirtualMachineFolderFromObject returns an *object.Folder from a given
 // object, and relative datastore folder path. If no such folder is found, or
 // if it is not a VM folder, an appropriate error will be returned.
-func VirtualMachineFolderFromObject(client *govmomi.Client, obj interface{}, relative string) (*object.Folder, error) {
+func VirtualMachineFolderFromObject(ctx context.Context, client *govmomi.Client, obj interface{}, relative string) (*object.Folder, error) {
 	log.Printf("[DEBUG] Locating folder at path %q relative to virtual machine root", relative)
-	folder, err := folderFromObject(client, obj, RootPathParticleVM, relative)
+	folder, err := folderFromObject(ctx, client, obj, RootPathParticleVM, relative)
 	if err != nil {
 		return nil, err
 	}
 
-	return validateVirtualMachineFolder(folder)
+	return validateVirtualMachineFolder(ctx, folder)
 }
 
 // validateDatastoreFolder checks to make sure the folder is a datastore
 // folder, and returns it if it is, or an error if it isn't.
-func validateDatastoreFolder(folder *object.Folder) (*object.Folder, error) {
-	ft, err := FindType(folder)
+func validateDatastoreFolder(ctx context.Context, folder *object.Folder) (*object.Folder, error) {
+	ft, err := FindType(ctx, folder)
 	if err != nil {
 		return nil, err
 	}
@@ -247,8 +247,8 @@ func validateDatastoreFolder(folder *object.Folder) (*object.Folder, error) {
 
 // validateHostFolder checks to make sure the folder is a host
 // folder, and returns it if it is, or an error if it isn't.
-func validateHostFolder(folder *object.Folder) (*object.Folder, error) {
-	ft, err := FindType(folder)
+func validateHostFolder(ctx context.Context, folder *object.Folder) (*object.Folder, error) {
+	ft, err := FindType(ctx, folder)
 	if err != nil {
 		return nil, err
 	}
@@ -260,8 +260,8 @@ func validateHostFolder(folder *object.Folder) (*object.Folder, error) {
 
 // validateVirtualMachineFolder checks to make sure the folder is a VM folder,
 // and returns it if it is, or an error if it isn't.
-func validateVirtualMachineFolder(folder *object.Folder) (*object.Folder, error) {
-	ft, err := FindType(folder)
+func validateVirtualMachineFolder(ctx context.Context, folder *object.Folder) (*object.Folder, error) {
+	ft, err := FindType(ctx, folder)
 	if err != nil {
 		return nil, err
 	}
@@ -288,16 +288,16 @@ func NormalizePath(v interface{}) string {
 }
 
 // MoveObjectTo moves a object by reference into a folder.
-func MoveObjectTo(ref types.ManagedObjectReference, folder *object.Folder) error {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func MoveObjectTo(ctx context.Context, ref types.ManagedObjectReference, folder *object.Folder) error {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := folder.MoveInto(ctx, []types.ManagedObjectReference{ref})
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	return task.WaitEx(tctx)
+	return viapi.WaitForTask(tctx, task)
 }
 
 // FromPath takes a relative folder path, an object type, and an optional
@@ -305,7 +305,7 @@ func MoveObjectTo(ref types.ManagedObjectReference, folder *object.Folder) error
 //
 // The datacenter supplied in dc cannot be nil if the folder type supplied by
 // ft is something else other than VSphereFolderTypeDatacenter.
-func FromPath(c *govmomi.Client, p string, ft VSphereFolderType, dc *object.Datacenter) (*object.Folder, error) {
+func FromPath(ctx context.Context, c *govmomi.Client, p string, ft VSphereFolderType, dc *object.Datacenter) (*object.Folder, error) {
 	var fp string
 	if ft == VSphereFolderTypeDatacenter {
 		fp = "/" + p
@@ -313,7 +313,7 @@ func FromPath(c *govmomi.Client, p string, ft VSphereFolderType, dc *object.Data
 		pt := RootPathParticle(ft)
 		fp = pt.PathFromDatacenter(dc, p)
 	}
-	return FromAbsolutePath(c, fp)
+	return FromAbsolutePath(ctx, c, fp)
 }
 
 // ParentFromPath takes a relative object path (usually a folder), an
@@ -322,12 +322,12 @@ func FromPath(c *govmomi.Client, p string, ft VSphereFolderType, dc *object.Data
 //
 // The datacenter supplied in dc cannot be nil if the folder type supplied by
 // ft is something else other than VSphereFolderTypeDatacenter.
-func ParentFromPath(c *govmomi.Client, p string, ft VSphereFolderType, dc *object.Datacenter) (*object.Folder, error) {
-	return FromPath(c, path.Dir(p), ft, dc)
+func ParentFromPath(ctx context.Context, c *govmomi.Client, p string, ft VSphereFolderType, dc *object.Datacenter) (*object.Folder, error) {
+	return FromPath(ctx, c, path.Dir(p), ft, dc)
 }
 
 // FromID locates a Folder by its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (*object.Folder, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*object.Folder, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -335,7 +335,7 @@ func FromID(client *govmomi.Client, id string) (*object.Folder, error) {
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	folder, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -344,12 +344,11 @@ func FromID(client *govmomi.Client, id string) (*object.Folder, error) {
 	return folder.(*object.Folder), nil
 }
 
-func List(client *govmomi.Client) ([]*object.Folder, error) {
-	return getFolders(client, "/*")
+func List(ctx context.Context, client *govmomi.Client) ([]*object.Folder, error) {
+	return getFolders(ctx, client, "/*")
 }
 
-func getFolders(client *govmomi.Client, path string) ([]*object.Folder, error) {
-	ctx := context.TODO()
+func getFolders(ctx context.Context, client *govmomi.Client, path string) ([]*object.Folder, error) {
 	var folders []*object.Folder
 	finder := find.NewFinder(client.Client, false)
 	es, err := finder.ManagedObjectListChildren(ctx, path+"/*", "folder")
@@ -359,7 +358,7 @@ func getFolders(client *govmomi.Client, path string) ([]*object.Folder, error) {
 	for _, id := range es {
 		switch {
 		case id.Object.Reference().Type == "Folder":
-			newFolders, err := getFolders(client, id.Path)
+			newFolders, err := getFolders(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -373,8 +372,8 @@ func getFolders(client *govmomi.Client, path string) ([]*object.Folder, error) {
 
 // Properties is a convenience method that wraps fetching the
 // Folder MO from its higher-level object.
-func Properties(folder *object.Folder) (*mo.Folder, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, folder *object.Folder) (*mo.Folder, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.Folder
 	if err := folder.Properties(ctx, folder.Reference(), nil, &props); err != nil {
@@ -384,10 +383,10 @@ func Properties(folder *object.Folder) (*mo.Folder, error) {
 }
 
 // FindType returns a proper VSphereFolderType for a folder object by checking its child type.
-func FindType(folder *object.Folder) (VSphereFolderType, error) {
+func FindType(ctx context.Context, folder *object.Folder) (VSphereFolderType, error) {
 	var ft VSphereFolderType
 
-	props, err := Properties(folder)
+	props, err := Properties(ctx, folder)
 	if err != nil {
 		return ft, err
 	}
@@ -426,8 +425,8 @@ func FindType(folder *object.Folder) (VSphereFolderType, error) {
 // safe to delete - destroying a folder in vSphere destroys *all* children if
 // at all possible (including removing virtual machines), so extra verification
 // is necessary to prevent accidental removal.
-func HasChildren(f *object.Folder) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func HasChildren(ctx context.Context, f *object.Folder) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	children, err := f.Children(ctx)
 	if err != nil {
@@ -0,0 +1,154 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package guestoperation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/guest/toolbox"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// NamePasswordAuth returns guest authentication for a user name and password.
+func NamePasswordAuth(username, password string, interactive bool) types.BaseGuestAuthentication {
+	return &types.NamePasswordAuthentication{
+		GuestAuthentication: types.GuestAuthentication{
+			InteractiveSession: interactive,
+		},
+		Username: username,
+		Password: password,
+	}
+}
+
+// SSPIAuth returns guest authentication for a base64 encoded SSPI token.
+// This is only supported by Windows guests.
+func SSPIAuth(token string, interactive bool) types.BaseGuestAuthentication {
+	return &types.SSPIAuthentication{
+		GuestAuthentication: types.GuestAuthentication{
+			InteractiveSession: interactive,
+		},
+		SspiToken: token,
+	}
+}
+
+// NewClient returns a client for the guest operations of a virtual machine.
+// VMware Tools must be running in the guest.
+func NewClient(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, auth types.BaseGuestAuthentication) (*toolbox.Client, error) {
+	c, err := toolbox.NewClient(ctx, client.Client, vm, auth)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to guest operations manager: %s", err)
+	}
+	return c, nil
+}
+
+// File describes a file to copy into the guest.
+type File struct {
+	// Source is the path of a local file. Mutually exclusive with Content.
+	Source string
+	// Content is the content of the file. Mutually exclusive with Source.
+	Content string
+	// Destination is the path of the file in the guest.
+	Destination string
+	// Overwrite replaces an existing file in the guest.
+	Overwrite bool
+}
+
+// Upload copies a file into the guest.
+func Upload(ctx context.Context, c *toolbox.Client, f *File) error {
+	var src io.Reader
+	p := soap.DefaultUpload
+	if f.Source != "" {
+		in, err := os.Open(f.Source)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		stat, err := in.Stat()
+		if err != nil {
+			return err
+		}
+		p.ContentLength = stat.Size()
+		src = in
+	} else {
+		src = strings.NewReader(f.Content)
+		p.ContentLength = int64(len(f.Content))
+	}
+
+	var attr types.BaseGuestFileAttributes = new(types.GuestPosixFileAttributes)
+	if c.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest {
+		attr = new(types.GuestWindowsFileAttributes)
+	}
+	log.Printf("[DEBUG] Uploading %d bytes to %q in guest", p.ContentLength, f.Destination)
+	if err := c.Upload(ctx, src, f.Destination, p, attr, f.Overwrite); err != nil {
+		return fmt.Errorf("error uploading %s: %s", f.Destination, err)
+	}
+	return nil
+}
+
+// Program describes a process to run in the guest.
+type Program struct {
+	// Path is the absolute path of the program to run. On Linux guests, a
+	// program without a path is run through /bin/bash.
+	Path string
+	// Arguments are the arguments passed to the program.
+	Arguments string
+	// WorkingDirectory is the working directory of the program.
+	WorkingDirectory string
+	// Env are the environment variables of the program, in key=value form.
+	Env []string
+	// CaptureOutput redirects stdout and stderr to temporary files in the
+	// guest, which are downloaded once the program exits.
+	CaptureOutput bool
+}
+
+// Result is the result of running a program in the guest.
+type Result struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+// Run starts a program in the guest and waits for it to exit. A non-zero
+// exit code is returned in the result and is not an error.
+func Run(ctx context.Context, c *toolbox.Client, p *Program) (*Result, error) {
+	cmd := &exec.Cmd{
+		Path: p.Path,
+		Env:  p.Env,
+		Dir:  p.WorkingDirectory,
+	}
+	if p.Arguments != "" {
+		cmd.Args = []string{p.Arguments}
+	}
+	var stdout, stderr bytes.Buffer
+	if p.CaptureOutput {
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	}
+
+	log.Printf("[DEBUG] Running %q in guest", p.Path)
+	result := &Result{}
+	if err := c.Run(ctx, cmd); err != nil {
+		var exitErr interface{ ExitCode() int }
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("error running %s: %s", p.Path, err)
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	log.Printf("[DEBUG] %q exited with code %d", p.Path, result.ExitCode)
+	return result, nil
+}
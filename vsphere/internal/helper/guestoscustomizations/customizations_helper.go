@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net"
 	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -19,6 +20,7 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -95,7 +97,7 @@ func SpecSchema(isVM bool) map[string]*schema.Schema {
 			Type:          schema.TypeList,
 			Optional:      true,
 			MaxItems:      1,
-			ConflictsWith: []string{prefix + "windows_options", prefix + "windows_sysprep_text"},
+			ConflictsWith: []string{prefix + "windows_options", prefix + "windows_sysprep_text", prefix + "cloud_config"},
 			Description:   "A list of configuration options specific to Linux virtual machines.",
 			Elem: &schema.Resource{Schema: map[string]*schema.Schema{
 				"domain": {
@@ -137,7 +139,7 @@ func SpecSchema(isVM bool) map[string]*schema.Schema {
 			Type:          schema.TypeList,
 			Optional:      true,
 			MaxItems:      1,
-			ConflictsWith: []string{prefix + "linux_options", prefix + "windows_sysprep_text"},
+			ConflictsWith: []string{prefix + "linux_options", prefix + "windows_sysprep_text", prefix + "cloud_config"},
 			Description:   "A list of configuration options specific to Windows virtual machines.",
 			Elem: &schema.Resource{Schema: map[string]*schema.Schema{
 				// CustomizationGuiRunOnce
@@ -241,10 +243,34 @@ func SpecSchema(isVM bool) map[string]*schema.Schema {
 			Type:          schema.TypeString,
 			Optional:      true,
 			Sensitive:     true,
-			ConflictsWith: []string{prefix + "linux_options", prefix + "windows_options"},
+			ConflictsWith: []string{prefix + "linux_options", prefix + "windows_options", prefix + "cloud_config"},
 			Description:   "Use this option to specify a windows sysprep file directly.",
 		},
 
+		// CustomizationCloudinitPrep
+		"cloud_config": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: []string{prefix + "linux_options", prefix + "windows_options", prefix + "windows_sysprep_text"},
+			Description:   "A cloud-init configuration for Linux virtual machines. Requires vCenter Server 7.0 Update 3 or later.",
+			Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"metadata": {
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  "The cloud-init metadata, including the network configuration, instance ID and host name, in JSON or YAML format.",
+					ValidateFunc: validateCloudConfig,
+				},
+				"userdata": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Sensitive:    true,
+					Description:  "The cloud-init user data. Cloud config documents starting with #cloud-config are validated as YAML.",
+					ValidateFunc: validateCloudConfigUserdata,
+				},
+			}},
+		},
+
 		// CustomizationIPSettings
 		"network_interface": {
 			Type:        schema.TypeList,
@@ -301,8 +327,8 @@ func SpecSchema(isVM bool) map[string]*schema.Schema {
 	}
 }
 
-func FromName(client *govmomi.Client, name string) (*types.CustomizationSpecItem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func FromName(ctx context.Context, client *govmomi.Client, name string) (*types.CustomizationSpecItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	csm := object.NewCustomizationSpecManager(client.Client)
@@ -321,6 +347,10 @@ func FlattenGuestOsCustomizationSpec(d *schema.ResourceData, specItem *types.Cus
 
 	switch specItem.Info.Type {
 	case GuestOsCustomizationTypeLinux:
+		if cloudinitPrep, ok := specItem.Spec.Identity.(*types.CustomizationCloudinitPrep); ok {
+			specData["cloud_config"] = flattenCloudConfig(cloudinitPrep)
+			break
+		}
 		linuxPrep := specItem.Spec.Identity.(*types.CustomizationLinuxPrep)
 		linuxOptions, err := flattenLinuxOptions(linuxPrep)
 		if err != nil {
@@ -400,6 +430,9 @@ func ExpandGuestOsCustomizationSpec(d *schema.ResourceData, client *govmomi.Clie
 	}
 
 	version := viapi.ParseVersionFromClient(client)
+	if err := ValidateCloudConfigVersion(d, false, version); err != nil {
+		return nil, err
+	}
 
 	return &types.CustomizationSpecItem{
 		Info: types.CustomizationSpecInfo{
@@ -417,11 +450,14 @@ func ValidateCustomizationSpec(d *schema.ResourceDiff, family string, isVM bool)
 	prefix := getSchemaPrefix(isVM)
 	// Validate that the proper section exists for OS family suboptions.
 	linuxExists := len(d.Get(prefix+"linux_options").([]interface{})) > 0 || !structure.ValuesAvailable(prefix+"linux_options.", []string{"host_name", "domain"}, d)
+	cloudConfigExists := len(d.Get(prefix+"cloud_config").([]interface{})) > 0 || !structure.ValuesAvailable(prefix+"cloud_config.", []string{"metadata"}, d)
 	windowsExists := len(d.Get(prefix+"windows_options").([]interface{})) > 0 || !structure.ValuesAvailable(prefix+"windows_options.", []string{"computer_name"}, d)
 	sysprepExists := d.Get(prefix+"windows_sysprep_text").(string) != "" || !structure.ValuesAvailable(prefix, []string{"windows_sysprep_text"}, d)
 	switch {
-	case family == string(types.VirtualMachineGuestOsFamilyLinuxGuest) && !linuxExists:
-		return errors.New("linux_options must exist in VM customization options for Linux operating systems")
+	case family == string(types.VirtualMachineGuestOsFamilyLinuxGuest) && !linuxExists && !cloudConfigExists:
+		return errors.New("one of linux_options or cloud_config must exist in VM customization options for Linux operating systems")
+	case family != string(types.VirtualMachineGuestOsFamilyLinuxGuest) && cloudConfigExists:
+		return errors.New("cloud_config can only be used in VM customization options for Linux operating systems")
 	case family == string(types.VirtualMachineGuestOsFamilyWindowsGuest) && !windowsExists && !sysprepExists:
 		return errors.New("one of windows_options or windows_sysprep_text must exist in VM customization options for Windows operating systems")
 	}
@@ -480,6 +516,15 @@ func flattenLinuxOptions(customizationPrep *types.CustomizationLinuxPrep) ([]map
 	return []map[string]interface{}{linuxOptionsData}, nil
 }
 
+func flattenCloudConfig(customizationPrep *types.CustomizationCloudinitPrep) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"metadata": customizationPrep.Metadata,
+			"userdata": customizationPrep.Userdata,
+		},
+	}
+}
+
 func flattenSysprepText(identity types.BaseCustomizationIdentitySettings) string {
 	sysprep, ok := identity.(*types.CustomizationSysprepText)
 	if ok {
@@ -533,14 +578,21 @@ func ExpandCustomizationSpec(d *schema.ResourceData, family string, isVM bool, v
 // expandBaseCustomizationIdentitySettings returns a
 // BaseCustomizationIdentitySettings, depending on what is defined.
 //
-// Only one of the three types of identity settings can be specified: Linux
-// settings (from linux_options), Windows settings (from windows_options), and
-// the raw Windows sysprep file (via windows_sysprep_text).
+// Only one of the four types of identity settings can be specified: Linux
+// settings (from linux_options), cloud-init settings for Linux (from
+// cloud_config), Windows settings (from windows_options), and the raw Windows
+// sysprep file (via windows_sysprep_text).
 func expandBaseCustomizationIdentitySettings(d *schema.ResourceData, family string, prefix string, version viapi.VSphereVersion) types.BaseCustomizationIdentitySettings {
 	var obj types.BaseCustomizationIdentitySettings
 	windowsExists := len(d.Get(prefix+"windows_options").([]interface{})) > 0
 	sysprepExists := len(d.Get(prefix+"windows_sysprep_text").(string)) > 0
+	cloudConfigExists := len(d.Get(prefix+"cloud_config").([]interface{})) > 0
 	switch {
+	case family == string(types.VirtualMachineGuestOsFamilyLinuxGuest) && cloudConfigExists:
+		obj = &types.CustomizationCloudinitPrep{
+			Metadata: d.Get(prefix + "cloud_config.0.metadata").(string),
+			Userdata: d.Get(prefix + "cloud_config.0.userdata").(string),
+		}
 	case family == string(types.VirtualMachineGuestOsFamilyLinuxGuest):
 		linuxKeyPrefix := prefix + "linux_options.0."
 		obj = expandCustomizationLinuxPrep(d, linuxKeyPrefix)
@@ -557,6 +609,49 @@ func expandBaseCustomizationIdentitySettings(d *schema.ResourceData, family stri
 	return obj
 }
 
+// ValidateCloudConfigVersion checks that the vCenter Server supports
+// cloud-init customization when cloud_config is used.
+func ValidateCloudConfigVersion(d *schema.ResourceData, isVM bool, version viapi.VSphereVersion) error {
+	prefix := getSchemaPrefix(isVM)
+	if len(d.Get(prefix+"cloud_config").([]interface{})) < 1 {
+		return nil
+	}
+	if !version.AtLeast(viapi.VSphereVersion{Product: version.Product, Major: 7, Minor: 0, Patch: 3}) {
+		return fmt.Errorf("cloud_config requires vCenter Server 7.0 Update 3 or later, connected to %s", version)
+	}
+	return nil
+}
+
+// cloudConfigMaxSize is the maximum size of the cloud-init metadata and user
+// data accepted by vCenter Server.
+const cloudConfigMaxSize = 524288
+
+// validateCloudConfig checks that cloud-init metadata is a valid JSON or YAML
+// document.
+func validateCloudConfig(v interface{}, k string) ([]string, []error) {
+	if len(v.(string)) > cloudConfigMaxSize {
+		return nil, []error{fmt.Errorf("%s must not be larger than %d bytes", k, cloudConfigMaxSize)}
+	}
+	var out interface{}
+	if err := yaml.Unmarshal([]byte(v.(string)), &out); err != nil {
+		return nil, []error{fmt.Errorf("%s is not valid JSON or YAML: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// validateCloudConfigUserdata checks cloud-init user data. Cloud config
+// documents are validated as YAML, other user data formats are only checked
+// for their size.
+func validateCloudConfigUserdata(v interface{}, k string) ([]string, []error) {
+	if len(v.(string)) > cloudConfigMaxSize {
+		return nil, []error{fmt.Errorf("%s must not be larger than %d bytes", k, cloudConfigMaxSize)}
+	}
+	if !strings.HasPrefix(strings.TrimSpace(v.(string)), "#cloud-config") {
+		return nil, nil
+	}
+	return validateCloudConfig(v, k)
+}
+
 // expandCustomizationLinuxPrep reads certain ResourceData keys and
 // returns a CustomizationLinuxPrep.
 func expandCustomizationLinuxPrep(d *schema.ResourceData, prefix string) *types.CustomizationLinuxPrep {
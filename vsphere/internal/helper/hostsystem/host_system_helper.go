@@ -22,11 +22,11 @@ import (
 // SystemOrDefault returns a HostSystem from a specific host name and
 // datacenter. If the user is connecting over ESXi, the default host system is
 // used.
-func SystemOrDefault(client *govmomi.Client, name string, dc *object.Datacenter) (*object.HostSystem, error) {
+func SystemOrDefault(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.HostSystem, error) {
 	finder := find.NewFinder(client.Client, false)
 	finder.SetDatacenter(dc)
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	t := client.ServiceContent.About.ApiType
 	switch t {
@@ -42,7 +42,7 @@ func SystemOrDefault(client *govmomi.Client, name string, dc *object.Datacenter)
 }
 
 // FromID locates a HostSystem by its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (*object.HostSystem, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*object.HostSystem, error) {
 	log.Printf("[DEBUG] Locating host system ID %s", id)
 	finder := find.NewFinder(client.Client, false)
 
@@ -51,7 +51,7 @@ func FromID(client *govmomi.Client, id string) (*object.HostSystem, error) {
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	hs, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -63,8 +63,8 @@ func FromID(client *govmomi.Client, id string) (*object.HostSystem, error) {
 
 // Properties is a convenience method that wraps fetching the HostSystem MO
 // from its higher-level object.
-func Properties(host *object.HostSystem) (*mo.HostSystem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, host *object.HostSystem) (*mo.HostSystem, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.HostSystem
 	if err := host.Properties(ctx, host.Reference(), nil, &props); err != nil {
@@ -75,16 +75,16 @@ func Properties(host *object.HostSystem) (*mo.HostSystem, error) {
 
 // ResourcePool is a convenience method that wraps fetching the host system's
 // root resource pool
-func ResourcePool(host *object.HostSystem) (*object.ResourcePool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func ResourcePool(ctx context.Context, host *object.HostSystem) (*object.ResourcePool, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return host.ResourcePool(ctx)
 }
 
 // hostSystemNameFromID returns the name of a host via its its managed object
 // reference ID.
-func hostSystemNameFromID(client *govmomi.Client, id string) (string, error) {
-	hs, err := FromID(client, id)
+func hostSystemNameFromID(ctx context.Context, client *govmomi.Client, id string) (string, error) {
+	hs, err := FromID(ctx, client, id)
 	if err != nil {
 		return "", err
 	}
@@ -94,8 +94,8 @@ func hostSystemNameFromID(client *govmomi.Client, id string) (string, error) {
 // NameOrID is a convenience method mainly for helping displaying friendly
 // errors where space is important - it displays either the host name or the ID
 // if there was an error fetching it.
-func NameOrID(client *govmomi.Client, id string) string {
-	name, err := hostSystemNameFromID(client, id)
+func NameOrID(ctx context.Context, client *govmomi.Client, id string) string {
+	name, err := hostSystemNameFromID(ctx, client, id)
 	if err != nil {
 		return id
 	}
@@ -104,8 +104,8 @@ func NameOrID(client *govmomi.Client, id string) string {
 
 // HostInMaintenance checks a HostSystem's maintenance mode and returns true if the
 // the host is in maintenance mode.
-func HostInMaintenance(host *object.HostSystem) (bool, error) {
-	hostObject, err := Properties(host)
+func HostInMaintenance(ctx context.Context, host *object.HostSystem) (bool, error) {
+	hostObject, err := Properties(ctx, host)
 	if err != nil {
 		return false, err
 	}
@@ -117,12 +117,14 @@ func HostInMaintenance(host *object.HostSystem) (bool, error) {
 // to true, all powered off VMs will be removed from the host, or the task will
 // block until this is the case, depending on whether or not DRS is on or off
 // for the host's cluster. This parameter is ignored on direct ESXi.
-func EnterMaintenanceMode(host *object.HostSystem, timeout time.Duration, evacuate bool) error {
+//
+// The task is cancelled if ctx is done before it completes.
+func EnterMaintenanceMode(ctx context.Context, host *object.HostSystem, timeout time.Duration, evacuate bool) error {
 	if err := viapi.VimValidateVirtualCenter(host.Client()); err != nil {
 		evacuate = false
 	}
 
-	maintMode, err := HostInMaintenance(host)
+	maintMode, err := HostInMaintenance(ctx, host)
 	if err != nil {
 		return err
 	}
@@ -133,19 +135,19 @@ func EnterMaintenanceMode(host *object.HostSystem, timeout time.Duration, evacua
 
 	log.Printf("[DEBUG] Host %q is entering maintenance mode (evacuate: %t)", host.Name(), evacuate)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	task, err := host.EnterMaintenanceMode(ctx, int32(timeout.Seconds()), evacuate, nil)
 	if err != nil {
 		return err
 	}
 
-	err = task.WaitEx(ctx)
+	err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		return err
 	}
 	var to mo.Task
-	err = task.Properties(context.TODO(), task.Reference(), nil, &to)
+	err = task.Properties(ctx, task.Reference(), nil, &to)
 	if err != nil {
 		log.Printf("[DEBUG] Failed while getting task results: %s", err)
 		return err
@@ -157,9 +159,10 @@ func EnterMaintenanceMode(host *object.HostSystem, timeout time.Duration, evacua
 	return nil
 }
 
-// ExitMaintenanceMode takes a host out of maintenance mode.
-func ExitMaintenanceMode(host *object.HostSystem, timeout time.Duration) error {
-	maintMode, err := HostInMaintenance(host)
+// ExitMaintenanceMode takes a host out of maintenance mode. The task is
+// cancelled if ctx is done before it completes.
+func ExitMaintenanceMode(ctx context.Context, host *object.HostSystem, timeout time.Duration) error {
+	maintMode, err := HostInMaintenance(ctx, host)
 	if err != nil {
 		return err
 	}
@@ -170,19 +173,19 @@ func ExitMaintenanceMode(host *object.HostSystem, timeout time.Duration) error {
 
 	log.Printf("[DEBUG] Host %q is exiting maintenance mode", host.Name())
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	task, err := host.ExitMaintenanceMode(ctx, int32(timeout.Seconds()))
 	if err != nil {
 		return err
 	}
 
-	err = task.WaitEx(ctx)
+	err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		return err
 	}
 	var to mo.Task
-	err = task.Properties(context.TODO(), task.Reference(), nil, &to)
+	err = task.Properties(ctx, task.Reference(), nil, &to)
 	if err != nil {
 		log.Printf("[DEBUG] Failed while getting task results: %s", err)
 		return err
@@ -195,8 +198,8 @@ func ExitMaintenanceMode(host *object.HostSystem, timeout time.Duration) error {
 }
 
 // GetConnectionState returns the host's connection state (see vim.HostSystem.ConnectionState)
-func GetConnectionState(host *object.HostSystem) (types.HostSystemConnectionState, error) {
-	hostProps, err := Properties(host)
+func GetConnectionState(ctx context.Context, host *object.HostSystem) (types.HostSystemConnectionState, error) {
+	hostProps, err := Properties(ctx, host)
 	if err != nil {
 		return "", err
 	}
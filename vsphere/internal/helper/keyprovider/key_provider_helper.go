@@ -0,0 +1,188 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/crypto"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+)
+
+const (
+	// TypeStandard is the type of a key provider backed by one or more
+	// external KMIP servers.
+	TypeStandard = "standard"
+	// TypeNative is the type of a vSphere native key provider.
+	TypeNative = "native"
+)
+
+// manager returns the CryptoManagerKmip for the supplied client.
+func manager(client *govmomi.Client) (*crypto.ManagerKmip, error) {
+	m, err := crypto.GetManagerKmip(client.Client)
+	if err != nil {
+		return nil, fmt.Errorf("key providers are not supported on this connection: %s", err)
+	}
+	return m, nil
+}
+
+// TypeFromInfo returns the type of the key provider described by info.
+func TypeFromInfo(info *types.KmipClusterInfo) string {
+	if info.ManagementType == string(types.KmipClusterInfoKmsManagementTypeNativeProvider) {
+		return TypeNative
+	}
+	return TypeStandard
+}
+
+// List returns all the key providers registered with vCenter Server.
+func List(ctx context.Context, client *govmomi.Client) ([]types.KmipClusterInfo, error) {
+	m, err := manager(client)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return m.ListKmipServers(ctx, nil)
+}
+
+// FromID locates a key provider by its ID. nil is returned if the key
+// provider does not exist.
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*types.KmipClusterInfo, error) {
+	log.Printf("[DEBUG] Locating key provider %q", id)
+	providers, err := List(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for i := range providers {
+		if providers[i].ClusterId.Id == id {
+			return &providers[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// DefaultID returns the ID of the default key provider of vCenter Server, or
+// an empty string if no default key provider is set.
+func DefaultID(ctx context.Context, client *govmomi.Client) (string, error) {
+	m, err := manager(client)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return m.GetDefaultKmsClusterID(ctx, nil, true)
+}
+
+// Register registers a new key provider of the supplied type.
+func Register(ctx context.Context, client *govmomi.Client, id, providerType string) error {
+	log.Printf("[DEBUG] Registering %s key provider %q", providerType, id)
+	m, err := manager(client)
+	if err != nil {
+		return err
+	}
+	managementType := types.KmipClusterInfoKmsManagementTypeVCenter
+	if providerType == TypeNative {
+		managementType = types.KmipClusterInfoKmsManagementTypeNativeProvider
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return m.RegisterKmsCluster(ctx, id, managementType)
+}
+
+// Unregister removes a key provider from vCenter Server.
+func Unregister(ctx context.Context, client *govmomi.Client, id string) error {
+	log.Printf("[DEBUG] Unregistering key provider %q", id)
+	m, err := manager(client)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return m.UnregisterKmsCluster(ctx, id)
+}
+
+// SetDefault marks a key provider as the default key provider of vCenter
+// Server. Supplying an empty ID clears the default.
+func SetDefault(ctx context.Context, client *govmomi.Client, id string) error {
+	log.Printf("[DEBUG] Setting default key provider to %q", id)
+	m, err := manager(client)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	if id == "" {
+		return m.SetDefaultKmsClusterId(ctx, "", nil)
+	}
+	return m.MarkDefault(ctx, id)
+}
+
+// AddServer adds a KMIP server to a standard key provider.
+func AddServer(ctx context.Context, client *govmomi.Client, spec types.KmipServerSpec) error {
+	log.Printf("[DEBUG] Adding KMIP server %q to key provider %q", spec.Info.Name, spec.ClusterId.Id)
+	m, err := manager(client)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return m.RegisterKmipServer(ctx, spec)
+}
+
+// UpdateServer updates a KMIP server of a standard key provider.
+func UpdateServer(ctx context.Context, client *govmomi.Client, spec types.KmipServerSpec) error {
+	log.Printf("[DEBUG] Updating KMIP server %q of key provider %q", spec.Info.Name, spec.ClusterId.Id)
+	m, err := manager(client)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return m.UpdateKmipServer(ctx, spec)
+}
+
+// RemoveServer removes a KMIP server from a standard key provider.
+func RemoveServer(ctx context.Context, client *govmomi.Client, id, name string) error {
+	log.Printf("[DEBUG] Removing KMIP server %q from key provider %q", name, id)
+	m, err := manager(client)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	return m.RemoveKmipServer(ctx, id, name)
+}
+
+// GenerateKey generates a new key with the supplied key provider. If the ID
+// is empty, the default key provider is used.
+func GenerateKey(ctx context.Context, client *govmomi.Client, id string) (*types.CryptoKeyId, error) {
+	log.Printf("[DEBUG] Generating key with key provider %q", id)
+	m, err := manager(client)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		if id, err = DefaultID(ctx, client); err != nil {
+			return nil, err
+		}
+		if id == "" {
+			return nil, fmt.Errorf("no key provider specified and no default key provider is set")
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	keyID, err := m.GenerateKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &types.CryptoKeyId{
+		KeyId:      keyID,
+		ProviderId: &types.KeyProviderId{Id: id},
+	}, nil
+}
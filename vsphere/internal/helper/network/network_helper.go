@@ -49,24 +49,24 @@ func (e NotFoundError) Error() string {
 //
 // Datacenter is optional here - if not provided, it's expected that the path
 // is sufficient enough for finder to determine the datacenter required.
-func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (object.NetworkReference, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (object.NetworkReference, error) {
 	finder := find.NewFinder(client.Client, false)
 	if dc != nil {
 		finder.SetDatacenter(dc)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return finder.Network(ctx, name)
 }
 
-func FromNameAndDVSUuid(client *govmomi.Client, name string, dc *object.Datacenter, dvsUUID string) (object.NetworkReference, error) {
+func FromNameAndDVSUuid(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter, dvsUUID string) (object.NetworkReference, error) {
 	finder := find.NewFinder(client.Client, false)
 	if dc != nil {
 		finder.SetDatacenter(dc)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	networks, err := finder.NetworkList(ctx, name)
 	if err != nil {
@@ -82,7 +82,7 @@ func FromNameAndDVSUuid(client *govmomi.Client, name string, dc *object.Datacent
 	case len(networks) > 1 && dvsUUID == "":
 		return nil, fmt.Errorf("path '%s' resolves to multiple %ss, Please specify", name, "network")
 	case dvsUUID != "":
-		dvsObj, err := dvsFromUUID(client, dvsUUID)
+		dvsObj, err := dvsFromUUID(ctx, client, dvsUUID)
 		if err != nil {
 			return nil, err
 		}
@@ -109,8 +109,7 @@ func FromNameAndDVSUuid(client *govmomi.Client, name string, dc *object.Datacent
 	return nil, NotFoundError{Name: name}
 }
 
-func FromNameAndVPCId(client *govmomi.Client, name string, dc *object.Datacenter, vpcProjectID, vpcID string) (object.NetworkReference, error) {
-	ctx := context.TODO()
+func FromNameAndVPCId(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter, vpcProjectID, vpcID string) (object.NetworkReference, error) {
 	finder := find.NewFinder(client.Client, true)
 
 	// Set the datacenter
@@ -161,12 +160,11 @@ func FromNameAndVPCId(client *govmomi.Client, name string, dc *object.Datacenter
 	return nil, NotFoundError{Name: name}
 }
 
-func List(client *govmomi.Client) ([]*object.VmwareDistributedVirtualSwitch, error) {
-	return getSwitches(client, "/*")
+func List(ctx context.Context, client *govmomi.Client) ([]*object.VmwareDistributedVirtualSwitch, error) {
+	return getSwitches(ctx, client, "/*")
 }
 
-func getSwitches(client *govmomi.Client, path string) ([]*object.VmwareDistributedVirtualSwitch, error) {
-	ctx := context.TODO()
+func getSwitches(ctx context.Context, client *govmomi.Client, path string) ([]*object.VmwareDistributedVirtualSwitch, error) {
 	var nets []*object.VmwareDistributedVirtualSwitch
 	finder := find.NewFinder(client.Client, false)
 	es, err := finder.ManagedObjectListChildren(ctx, path+"/*", "dvs", "folder")
@@ -176,13 +174,13 @@ func getSwitches(client *govmomi.Client, path string) ([]*object.VmwareDistribut
 	for _, id := range es {
 		switch {
 		case id.Object.Reference().Type == "VmwareDistributedVirtualSwitch":
-			net, err := dvsFromMOID(client, id.Object.Reference().Value)
+			net, err := dvsFromMOID(ctx, client, id.Object.Reference().Value)
 			if err != nil {
 				return nil, err
 			}
 			nets = append(nets, net)
 		case id.Object.Reference().Type == "Folder":
-			newDSs, err := getSwitches(client, id.Path)
+			newDSs, err := getSwitches(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -195,7 +193,7 @@ func getSwitches(client *govmomi.Client, path string) ([]*object.VmwareDistribut
 }
 
 // FromID loads a network via its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (object.NetworkReference, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (object.NetworkReference, error) {
 	// I'm not too sure if a more efficient method to do this exists, but if this
 	// becomes a pain point we might want to change this logic a bit.
 	//
@@ -203,7 +201,7 @@ func FromID(client *govmomi.Client, id string) (object.NetworkReference, error)
 	// github.com/vmware/govmomi/examples/networks/main.go.
 	m := view.NewManager(client.Client)
 
-	vctx, vcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	vctx, vcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer vcancel()
 	v, err := m.CreateContainerView(vctx, client.ServiceContent.RootFolder, []string{"Network"}, true)
 	if err != nil {
@@ -211,7 +209,7 @@ func FromID(client *govmomi.Client, id string) (object.NetworkReference, error)
 	}
 
 	defer func() {
-		dctx, dcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+		dctx, dcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 		defer dcancel()
 		_ = v.Destroy(dctx)
 	}()
@@ -226,7 +224,7 @@ func FromID(client *govmomi.Client, id string) (object.NetworkReference, error)
 		ref := net.Reference()
 		if ref.Value == id {
 			finder := find.NewFinder(client.Client, false)
-			fctx, fcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+			fctx, fcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 
 			nref, err := finder.ObjectReference(fctx, ref)
 			if err != nil {
@@ -245,7 +243,7 @@ func FromID(client *govmomi.Client, id string) (object.NetworkReference, error)
 	return nil, NotFoundError{ID: id}
 }
 
-func dvsFromMOID(client *govmomi.Client, id string) (*object.VmwareDistributedVirtualSwitch, error) {
+func dvsFromMOID(ctx context.Context, client *govmomi.Client, id string) (*object.VmwareDistributedVirtualSwitch, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -253,7 +251,7 @@ func dvsFromMOID(client *govmomi.Client, id string) (*object.VmwareDistributedVi
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	ds, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -264,23 +262,22 @@ func dvsFromMOID(client *govmomi.Client, id string) (*object.VmwareDistributedVi
 	// honest we should be panicking anyway.
 	return ds.(*object.VmwareDistributedVirtualSwitch), nil
 }
-func dvsFromUUID(client *govmomi.Client, uuid string) (*object.VmwareDistributedVirtualSwitch, error) {
+func dvsFromUUID(ctx context.Context, client *govmomi.Client, uuid string) (*object.VmwareDistributedVirtualSwitch, error) {
 	dvsm := types.ManagedObjectReference{Type: "DistributedVirtualSwitchManager", Value: "DVSManager"}
 	req := &types.QueryDvsByUuid{
 		This: dvsm,
 		Uuid: uuid,
 	}
-	resp, err := methods.QueryDvsByUuid(context.TODO(), client, req)
+	resp, err := methods.QueryDvsByUuid(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
 
-	return dvsFromMOID(client, resp.Returnval.Reference().Value)
+	return dvsFromMOID(ctx, client, resp.Returnval.Reference().Value)
 }
 
 // FromName fetches a network by name and applies additional filters.
-func FromName(client *vim25.Client, name string, dc *object.Datacenter, filters map[string]string) (object.NetworkReference, error) {
-	ctx := context.TODO()
+func FromName(ctx context.Context, client *vim25.Client, name string, dc *object.Datacenter, filters map[string]string) (object.NetworkReference, error) {
 	finder := find.NewFinder(client, true)
 
 	// Set the datacenter
@@ -25,12 +25,12 @@ import (
 // vsphere_virtual_machine resource, as there is no direct path from an opaque
 // network backing to the managed object reference that represents the opaque
 // network in vCenter.
-func OpaqueNetworkFromNetworkID(client *govmomi.Client, id string) (*object.OpaqueNetwork, error) {
+func OpaqueNetworkFromNetworkID(ctx context.Context, client *govmomi.Client, id string) (*object.OpaqueNetwork, error) {
 	// We use the same ContainerView logic that we use with networkFromID, but we
 	// go a step further and limit it to opaque networks only.
 	m := view.NewManager(client.Client)
 
-	vctx, vcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	vctx, vcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer vcancel()
 	v, err := m.CreateContainerView(vctx, client.ServiceContent.RootFolder, []string{"OpaqueNetwork"}, true)
 	if err != nil {
@@ -38,7 +38,7 @@ func OpaqueNetworkFromNetworkID(client *govmomi.Client, id string) (*object.Opaq
 	}
 
 	defer func() {
-		dctx, dcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+		dctx, dcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 		defer dcancel()
 		_ = v.Destroy(dctx)
 	}()
@@ -53,7 +53,7 @@ func OpaqueNetworkFromNetworkID(client *govmomi.Client, id string) (*object.Opaq
 		if net.Summary.(*types.OpaqueNetworkSummary).OpaqueNetworkId == id {
 			ref := net.Reference()
 			finder := find.NewFinder(client.Client, false)
-			fctx, fcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+			fctx, fcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 
 			nref, err := finder.ObjectReference(fctx, ref)
 			if err != nil {
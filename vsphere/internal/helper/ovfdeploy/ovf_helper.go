@@ -7,7 +7,6 @@ package ovfdeploy
 import (
 	"archive/tar"
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -29,6 +28,7 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/network"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/tlshelper"
 )
 
 func getTotalBytesRead(totalBytes *int64) int64 {
@@ -50,23 +50,23 @@ func (pr *ProgressReader) Read(p []byte) (n int, err error) {
 	return
 }
 
-func DeployOvfAndGetResult(client *govmomi.Client, ovfCreateImportSpecResult *types.OvfCreateImportSpecResult, resourcePoolObj *object.ResourcePool,
-	folder *object.Folder, host *object.HostSystem, filePath string, deployOva bool, fromLocal bool, allowUnverifiedSSL bool) error {
+func DeployOvfAndGetResult(ctx context.Context, client *govmomi.Client, ovfCreateImportSpecResult *types.OvfCreateImportSpecResult, resourcePoolObj *object.ResourcePool,
+	folder *object.Folder, host *object.HostSystem, filePath string, deployOva bool, fromLocal bool, httpClient *http.Client) error {
 
 	var currBytesRead int64
 	var totalBytes int64
 
-	nfcLease, err := resourcePoolObj.ImportVApp(context.Background(), ovfCreateImportSpecResult.ImportSpec, folder, host)
+	nfcLease, err := resourcePoolObj.ImportVApp(ctx, ovfCreateImportSpecResult.ImportSpec, folder, host)
 	if err != nil {
 		return err
 	}
 
-	leaseInfo, err := nfcLease.Wait(context.Background(), ovfCreateImportSpecResult.FileItem)
+	leaseInfo, err := nfcLease.Wait(ctx, ovfCreateImportSpecResult.FileItem)
 	if err != nil {
 		return err
 	}
 
-	u := nfcLease.StartUpdater(context.Background(), leaseInfo)
+	u := nfcLease.StartUpdater(ctx, leaseInfo)
 	defer u.Done()
 
 	for _, ovfFileItem := range ovfCreateImportSpecResult.FileItem {
@@ -83,12 +83,12 @@ func DeployOvfAndGetResult(client *govmomi.Client, ovfCreateImportSpecResult *ty
 				break
 			default:
 				if totalBytes == 0 {
-					_ = nfcLease.Progress(context.Background(), 100)
+					_ = nfcLease.Progress(ctx, 100)
 					return
 				}
 				log.Printf("Uploaded %v of %v Bytes", getTotalBytesRead(&currBytesRead), totalBytes)
 				progress := (getTotalBytesRead(&currBytesRead) / totalBytes) * 100
-				_ = nfcLease.Progress(context.Background(), int32(progress))
+				_ = nfcLease.Progress(ctx, int32(progress))
 				time.Sleep(10 * time.Second)
 			}
 		}
@@ -101,15 +101,15 @@ func DeployOvfAndGetResult(client *govmomi.Client, ovfCreateImportSpecResult *ty
 			}
 			if !deployOva {
 				if fromLocal {
-					err = uploadDisksFromLocal(client, filePath, ovfFileItem, deviceObj, &currBytesRead)
+					err = uploadDisksFromLocal(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead)
 				} else {
-					err = uploadDisksFromURL(client, filePath, ovfFileItem, deviceObj, &currBytesRead, allowUnverifiedSSL)
+					err = uploadDisksFromURL(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead, httpClient)
 				}
 			} else {
 				if fromLocal {
-					err = uploadOvaDisksFromLocal(client, filePath, ovfFileItem, deviceObj, &currBytesRead)
+					err = uploadOvaDisksFromLocal(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead)
 				} else {
-					err = uploadOvaDisksFromURL(client, filePath, ovfFileItem, deviceObj, &currBytesRead, allowUnverifiedSSL)
+					err = uploadOvaDisksFromURL(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead, httpClient)
 				}
 			}
 			if err != nil {
@@ -119,11 +119,11 @@ func DeployOvfAndGetResult(client *govmomi.Client, ovfCreateImportSpecResult *ty
 		}
 	}
 	statusChannel <- true
-	err = nfcLease.Progress(context.Background(), 100)
+	err = nfcLease.Progress(ctx, 100)
 	if err != nil {
 		return err
 	}
-	return nfcLease.Complete(context.Background())
+	return nfcLease.Complete(ctx)
 }
 
 func upload(ctx context.Context, client *govmomi.Client, item types.OvfFileItem, f io.Reader, rawURL string, size int64, totalBytesRead *int64) error {
@@ -187,7 +187,7 @@ func upload(ctx context.Context, client *govmomi.Client, item types.OvfFileItem,
 	return err
 }
 
-func uploadDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
+func uploadDisksFromLocal(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
 	var absoluteFilePath string
 	if strings.Contains(filePath, string(os.PathSeparator)) {
 		absoluteFilePath = filePath[:strings.LastIndex(filePath, string(os.PathSeparator))+1]
@@ -199,7 +199,7 @@ func uploadDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem t
 	if err != nil {
 		return err
 	}
-	err = upload(context.Background(), client, ovfFileItem, file, deviceObj.Url, ovfFileItem.Size, currBytesRead)
+	err = upload(ctx, client, ovfFileItem, file, deviceObj.Url, ovfFileItem.Size, currBytesRead)
 	if err != nil {
 		return fmt.Errorf("error while uploading the file %s %s", vmdkFilePath, err)
 	}
@@ -210,14 +210,13 @@ func uploadDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem t
 	return nil
 }
 
-func uploadDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
-	allowUnverifiedSSL bool) error {
+func uploadDisksFromURL(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
+	httpClient *http.Client) error {
 	var absoluteFilePath string
 	if strings.Contains(filePath, "/") {
 		absoluteFilePath = filePath[:strings.LastIndex(filePath, "/")+1]
 	}
 	vmdkFilePath := absoluteFilePath + ovfFileItem.Path
-	httpClient := getClient(allowUnverifiedSSL)
 	resp, err := httpClient.Get(vmdkFilePath)
 	log.Print(" [DEBUG] Absolute vmdk path: " + vmdkFilePath)
 	if err != nil {
@@ -226,11 +225,11 @@ func uploadDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem typ
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
-	err = upload(context.Background(), client, ovfFileItem, resp.Body, deviceObj.Url, ovfFileItem.Size, currBytesRead)
+	err = upload(ctx, client, ovfFileItem, resp.Body, deviceObj.Url, ovfFileItem.Size, currBytesRead)
 	return err
 }
 
-func uploadOvaDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
+func uploadOvaDisksFromLocal(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
 	diskName := ovfFileItem.Path
 	ovaFile, err := os.Open(filePath)
 	if err != nil {
@@ -240,14 +239,13 @@ func uploadOvaDisksFromLocal(client *govmomi.Client, filePath string, ovfFileIte
 		_ = ovaFile.Close()
 	}(ovaFile)
 
-	err = findAndUploadDiskFromOva(client, ovaFile, diskName, ovfFileItem, deviceObj, currBytesRead)
+	err = findAndUploadDiskFromOva(ctx, client, ovaFile, diskName, ovfFileItem, deviceObj, currBytesRead)
 	return err
 }
 
-func uploadOvaDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
-	allowUnverifiedSSL bool) error {
+func uploadOvaDisksFromURL(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
+	httpClient *http.Client) error {
 	diskName := ovfFileItem.Path
-	httpClient := getClient(allowUnverifiedSSL)
 	resp, err := httpClient.Get(filePath)
 	if err != nil {
 		return err
@@ -256,7 +254,7 @@ func uploadOvaDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem
 		_ = Body.Close()
 	}(resp.Body)
 	if resp.StatusCode == http.StatusOK {
-		err = findAndUploadDiskFromOva(client, resp.Body, diskName, ovfFileItem, deviceObj, currBytesRead)
+		err = findAndUploadDiskFromOva(ctx, client, resp.Body, diskName, ovfFileItem, deviceObj, currBytesRead)
 		if err != nil {
 			return err
 		}
@@ -266,7 +264,9 @@ func uploadOvaDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem
 	return nil
 }
 
-func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, allowUnverifiedSSL bool) (string, error) {
+// GetOvfDescriptor returns the OVF descriptor of a local or remote OVF or OVA
+// file. Remote files are downloaded with httpClient.
+func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, httpClient *http.Client) (string, error) {
 	ovfDescriptor := ""
 	if !deployOva {
 		if fromLocal {
@@ -277,8 +277,7 @@ func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, allowUnve
 			}
 			ovfDescriptor = string(fileBuffer)
 		} else {
-			client := getClient(allowUnverifiedSSL)
-			resp, err := client.Get(filePath)
+			resp, err := httpClient.Get(filePath)
 			if err != nil {
 				return "", err
 			}
@@ -309,8 +308,7 @@ func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, allowUnve
 				return "", err
 			}
 		} else {
-			client := getClient(allowUnverifiedSSL)
-			resp, err := client.Get(filePath)
+			resp, err := httpClient.Get(filePath)
 			if err != nil {
 				return "", err
 			}
@@ -350,7 +348,7 @@ func getOvfDescriptorFromOva(ovaFile io.Reader) (string, error) {
 	return "", fmt.Errorf("ovf file not found inside the ova")
 }
 
-func findAndUploadDiskFromOva(client *govmomi.Client, ovaFile io.Reader, diskName string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
+func findAndUploadDiskFromOva(ctx context.Context, client *govmomi.Client, ovaFile io.Reader, diskName string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
 	ovaReader := tar.NewReader(ovaFile)
 	for {
 		fileHdr, err := ovaReader.Next()
@@ -361,7 +359,7 @@ func findAndUploadDiskFromOva(client *govmomi.Client, ovaFile io.Reader, diskNam
 			return err
 		}
 		if fileHdr.Name == diskName {
-			err = upload(context.Background(), client, ovfFileItem, ovaReader, deviceObj.Url, ovfFileItem.Size, currBytesRead)
+			err = upload(ctx, client, ovfFileItem, ovaReader, deviceObj.Url, ovfFileItem.Size, currBytesRead)
 			if err != nil {
 				return fmt.Errorf("error while uploading the file %s %s", diskName, err)
 			}
@@ -371,10 +369,10 @@ func findAndUploadDiskFromOva(client *govmomi.Client, ovaFile io.Reader, diskNam
 	return fmt.Errorf("disk %s not found inside ova", diskName)
 }
 
-func GetNetworkMapping(client *govmomi.Client, m map[string]interface{}) ([]types.OvfNetworkMapping, error) {
+func GetNetworkMapping(ctx context.Context, client *govmomi.Client, m map[string]interface{}) ([]types.OvfNetworkMapping, error) {
 	var ovfNetworkMappings []types.OvfNetworkMapping
 	for key, val := range m {
-		networkObj, err := network.FromID(client, fmt.Sprint(val))
+		networkObj, err := network.FromID(ctx, client, fmt.Sprint(val))
 		if err != nil {
 			return nil, err
 		}
@@ -384,21 +382,11 @@ func GetNetworkMapping(client *govmomi.Client, m map[string]interface{}) ([]type
 	return ovfNetworkMappings, nil
 }
 
-func getClient(allowUnverifiedSSL bool) *http.Client {
-	if allowUnverifiedSSL {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint (gosec G402)
-		}
-		return &http.Client{Transport: tr}
-	}
-	return &http.Client{}
-}
-
-func CheckDeploymentOption(client *govmomi.Client, deploymentOption, ovfDescriptor string) error {
+func CheckDeploymentOption(ctx context.Context, client *govmomi.Client, deploymentOption, ovfDescriptor string) error {
 	ovfManager := ovf.NewManager(client.Client)
 
 	ovfParseDescriptorParams := types.OvfParseDescriptorParams{}
-	ovfParsedDescriptor, err := ovfManager.ParseDescriptor(context.Background(), ovfDescriptor, ovfParseDescriptorParams)
+	ovfParsedDescriptor, err := ovfManager.ParseDescriptor(ctx, ovfDescriptor, ovfParseDescriptorParams)
 	if err != nil {
 		return fmt.Errorf("error while parsing the ovf descriptor file %s", err)
 	}
@@ -414,13 +402,13 @@ func CheckDeploymentOption(client *govmomi.Client, deploymentOption, ovfDescript
 }
 
 type OvfHelper struct {
-	AllowUnverifiedSSL bool
 	Datastore          *object.Datastore
 	DeploymentOption   string
 	DeployOva          bool
 	DiskProvisioning   string
 	FilePath           string
 	Folder             *object.Folder
+	HTTPClient         *http.Client
 	IsLocal            bool
 	Name               string
 	HostSystem         *object.HostSystem
@@ -444,12 +432,17 @@ type OvfHelperParams struct {
 	NetworkMappings    map[string]interface{}
 	OvfURL             string
 	PoolID             string
+	TLS                *tlshelper.Options
 }
 
-func NewOvfHelper(client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error) {
+func NewOvfHelper(ctx context.Context, client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error) {
+	httpClient, err := o.TLS.HTTPClient(o.AllowUnverifiedSSL)
+	if err != nil {
+		return nil, err
+	}
 	ovfParams := &OvfHelper{
-		AllowUnverifiedSSL: o.AllowUnverifiedSSL,
 		DeploymentOption:   o.DeploymentOption,
+		HTTPClient:         httpClient,
 		DiskProvisioning:   o.DiskProvisioning,
 		IPAllocationPolicy: o.IPAllocationPolicy,
 		IPProtocol:         o.IPProtocol,
@@ -472,14 +465,14 @@ func NewOvfHelper(client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error
 
 	// Resource pool
 	poolID := o.PoolID
-	poolObj, err := resourcepool.FromID(client, poolID)
+	poolObj, err := resourcepool.FromID(ctx, client, poolID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
 	}
 	ovfParams.ResourcePool = poolObj
 
 	// Folder
-	folderObj, err := folder.VirtualMachineFolderFromObject(client, poolObj, o.Folder)
+	folderObj, err := folder.VirtualMachineFolderFromObject(ctx, client, poolObj, o.Folder)
 	if err != nil {
 		return nil, err
 	}
@@ -488,7 +481,7 @@ func NewOvfHelper(client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error
 	// Host
 	hostID := o.HostID
 	if hostID != "" {
-		hostObj, err := hostsystem.FromID(client, hostID)
+		hostObj, err := hostsystem.FromID(ctx, client, hostID)
 		if err != nil {
 			return nil, fmt.Errorf("could not find host with ID %q: %s", hostID, err)
 		}
@@ -500,14 +493,14 @@ func NewOvfHelper(client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error
 	if dsID == "" {
 		return nil, fmt.Errorf("data store ID is required for ovf deployment")
 	}
-	dsObj, err := datastore.FromID(client, dsID)
+	dsObj, err := datastore.FromID(ctx, client, dsID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find datastore with ID %q: %s", dsID, err)
 	}
 	ovfParams.Datastore = dsObj
 
 	// Network Mapping
-	networkMapping, err := GetNetworkMapping(client, o.NetworkMappings)
+	networkMapping, err := GetNetworkMapping(ctx, client, o.NetworkMappings)
 	if err != nil {
 		return nil, fmt.Errorf("while getting OVF network mapping: %s", err)
 	}
@@ -516,7 +509,7 @@ func NewOvfHelper(client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error
 	return ovfParams, nil
 }
 
-func (o *OvfHelper) GetImportSpec(client *govmomi.Client) (*types.OvfCreateImportSpecResult, error) {
+func (o *OvfHelper) GetImportSpec(ctx context.Context, client *govmomi.Client) (*types.OvfCreateImportSpecResult, error) {
 	var hsRef *types.ManagedObjectReference
 	if o.HostSystem != nil {
 		moref := o.HostSystem.Reference()
@@ -532,7 +525,7 @@ func (o *OvfHelper) GetImportSpec(client *govmomi.Client) (*types.OvfCreateImpor
 		DiskProvisioning:   o.DiskProvisioning,
 	}
 
-	ovfDescriptor, err := GetOvfDescriptor(o.FilePath, o.DeployOva, o.IsLocal, o.AllowUnverifiedSSL)
+	ovfDescriptor, err := GetOvfDescriptor(o.FilePath, o.DeployOva, o.IsLocal, o.HTTPClient)
 	if err != nil {
 		return nil, fmt.Errorf("error while reading the ovf file %s, %s ", o.FilePath, err)
 	}
@@ -544,14 +537,14 @@ func (o *OvfHelper) GetImportSpec(client *govmomi.Client) (*types.OvfCreateImpor
 	ovfManager := ovf.NewManager(client.Client)
 	deploymentOption := o.DeploymentOption
 	if deploymentOption != "" {
-		err := CheckDeploymentOption(client, deploymentOption, ovfDescriptor)
+		err := CheckDeploymentOption(ctx, client, deploymentOption, ovfDescriptor)
 		if err != nil {
 			return nil, fmt.Errorf("while checking deployment option: %s", err)
 		}
 		importSpecParam.DeploymentOption = deploymentOption
 	}
 
-	is, err := ovfManager.CreateImportSpec(context.Background(), ovfDescriptor,
+	is, err := ovfManager.CreateImportSpec(ctx, ovfDescriptor,
 		o.ResourcePool.Reference(), o.Datastore.Reference(), importSpecParam)
 	if err != nil {
 		return nil, fmt.Errorf("while getting ovf import spec: %s", err)
@@ -568,7 +561,7 @@ func (o *OvfHelper) GetImportSpec(client *govmomi.Client) (*types.OvfCreateImpor
 	return is, nil
 }
 
-func (o *OvfHelper) DeployOvf(client *govmomi.Client, spec *types.OvfCreateImportSpecResult) error {
-	return DeployOvfAndGetResult(client, spec, o.ResourcePool, o.Folder, o.HostSystem,
-		o.FilePath, o.DeployOva, o.IsLocal, o.AllowUnverifiedSSL)
+func (o *OvfHelper) DeployOvf(ctx context.Context, client *govmomi.Client, spec *types.OvfCreateImportSpecResult) error {
+	return DeployOvfAndGetResult(ctx, client, spec, o.ResourcePool, o.Folder, o.HostSystem,
+		o.FilePath, o.DeployOva, o.IsLocal, o.HTTPClient)
 }
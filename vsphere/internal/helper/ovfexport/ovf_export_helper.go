@@ -0,0 +1,358 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package ovfexport
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/nfc"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	// FormatOVF exports to a directory containing the OVF descriptor, the
+	// manifest, and the disk files.
+	FormatOVF = "ovf"
+
+	// FormatOVA exports to a single OVA archive.
+	FormatOVA = "ova"
+)
+
+// Formats is the list of supported export formats.
+var Formats = []string{
+	FormatOVF,
+	FormatOVA,
+}
+
+var manifestHashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// ManifestAlgorithms is the list of supported manifest checksum algorithms.
+var ManifestAlgorithms = []string{
+	"sha1",
+	"sha256",
+	"sha512",
+}
+
+// ExportParams describes how a virtual machine is exported.
+type ExportParams struct {
+	// Name is the name of the exported package. The files written are
+	// prefixed with this name.
+	Name string
+	// Directory is the local directory the package is written to.
+	Directory string
+	// Format is one of FormatOVF or FormatOVA.
+	Format string
+	// SnapshotID is the managed object ID of a snapshot to export from. When
+	// empty, the current state of the virtual machine is exported.
+	SnapshotID string
+	// IncludeImageFiles includes attached ISO and floppy images.
+	IncludeImageFiles bool
+	// ManifestAlgorithm is one of ManifestAlgorithms.
+	ManifestAlgorithm string
+	// Overwrite replaces an existing package of the same name.
+	Overwrite bool
+}
+
+// ExportResult describes the files written by Export.
+type ExportResult struct {
+	// Files are the paths of the files written to the export directory.
+	Files []string
+	// Manifest maps the name of each file in the package to its checksum.
+	Manifest map[string]string
+}
+
+// packageFile is a file of the package being exported.
+type packageFile struct {
+	name     string
+	path     string
+	checksum string
+}
+
+// OutputFiles returns the paths of the files that make up an export with the
+// supplied parameters, excluding the disk files, whose names are only known
+// once the export has started.
+func OutputFiles(p *ExportParams) []string {
+	if p.Format == FormatOVA {
+		return []string{filepath.Join(p.Directory, p.Name+".ova")}
+	}
+	return []string{
+		filepath.Join(p.Directory, p.Name+".ovf"),
+		filepath.Join(p.Directory, p.Name+".mf"),
+	}
+}
+
+// Export streams a virtual machine, or one of its snapshots, to the local
+// file system through an HttpNfcLease and writes an OVF descriptor and a
+// manifest for it. For FormatOVA, the files are packaged into a single
+// archive.
+func Export(ctx context.Context, vm *object.VirtualMachine, p *ExportParams) (*ExportResult, error) {
+	newHash, ok := manifestHashes[p.ManifestAlgorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported manifest algorithm %q", p.ManifestAlgorithm)
+	}
+	if !p.Overwrite {
+		for _, f := range OutputFiles(p) {
+			if _, err := os.Stat(f); err == nil {
+				return nil, fmt.Errorf("file already exists: %s", f)
+			}
+		}
+	}
+	if err := os.MkdirAll(p.Directory, 0750); err != nil {
+		return nil, err
+	}
+
+	// Disks are streamed into a staging directory when building an OVA, as the
+	// descriptor must be the first file in the archive and can only be created
+	// once all disks have been downloaded.
+	staging := p.Directory
+	if p.Format == FormatOVA {
+		var err error
+		staging, err = os.MkdirTemp(p.Directory, "."+p.Name+"-")
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = os.RemoveAll(staging) }()
+	}
+
+	var written []string
+	files, err := exportFiles(ctx, vm, p, staging, newHash, &written)
+	if err != nil {
+		removeFiles(written)
+		return nil, err
+	}
+
+	result := &ExportResult{
+		Manifest: make(map[string]string),
+	}
+	for _, f := range files {
+		result.Manifest[f.name] = f.checksum
+	}
+	if p.Format == FormatOVA {
+		ova := filepath.Join(p.Directory, p.Name+".ova")
+		if err := writeOVA(ova, files); err != nil {
+			_ = os.Remove(ova)
+			return nil, err
+		}
+		result.Files = []string{ova}
+		return result, nil
+	}
+	result.Files = written
+	return result, nil
+}
+
+// exportFiles downloads the disks of the virtual machine and writes the
+// descriptor and manifest to dir. The returned files are in package order,
+// starting with the descriptor and the manifest. The export lease is aborted
+// if any of this fails, and only completed once all files have been written.
+func exportFiles(ctx context.Context, vm *object.VirtualMachine, p *ExportParams, dir string, newHash func() hash.Hash, written *[]string) (_ []packageFile, err error) {
+	lease, err := requestExport(ctx, vm, p.SnapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting export lease: %s", err)
+	}
+	defer func() {
+		if err != nil {
+			if aerr := lease.Abort(context.WithoutCancel(ctx), nil); aerr != nil {
+				log.Printf("[WARN] Error aborting export lease: %s", aerr)
+			}
+		}
+	}()
+	info, err := lease.Wait(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for export lease: %s", err)
+	}
+
+	items := exportItems(info.Items, p)
+	if !p.Overwrite {
+		for _, item := range items {
+			path := filepath.Join(dir, item.Path)
+			if _, err := os.Stat(path); err == nil {
+				return nil, fmt.Errorf("file already exists: %s", path)
+			}
+		}
+	}
+
+	u := lease.StartUpdater(ctx, info)
+	defer u.Done()
+
+	var disks []packageFile
+	cdp := types.OvfCreateDescriptorParams{
+		Name: p.Name,
+	}
+	for _, item := range items {
+		path := filepath.Join(dir, item.Path)
+		log.Printf("[DEBUG] Downloading %q from virtual machine %q", item.Path, vm.InventoryPath)
+		h := newHash()
+		*written = append(*written, path)
+		if err := lease.DownloadFile(ctx, path, item, soap.Download{Writer: h}); err != nil {
+			return nil, fmt.Errorf("error downloading %s: %s", item.Path, err)
+		}
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		file := item.File()
+		file.Size = stat.Size()
+		cdp.OvfFiles = append(cdp.OvfFiles, file)
+		disks = append(disks, packageFile{
+			name:     item.Path,
+			path:     path,
+			checksum: hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+
+	desc, err := ovf.NewManager(vm.Client()).CreateDescriptor(ctx, vm, cdp)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OVF descriptor: %s", err)
+	}
+	if len(desc.Error) > 0 {
+		return nil, fmt.Errorf("error creating OVF descriptor: %s", desc.Error[0].LocalizedMessage)
+	}
+	descriptor := packageFile{
+		name: p.Name + ".ovf",
+		path: filepath.Join(dir, p.Name+".ovf"),
+	}
+	*written = append(*written, descriptor.path)
+	if descriptor.checksum, err = writeFile(descriptor.path, desc.OvfDescriptor, newHash()); err != nil {
+		return nil, err
+	}
+
+	var mf strings.Builder
+	algorithm := strings.ToUpper(p.ManifestAlgorithm)
+	for _, f := range append([]packageFile{descriptor}, disks...) {
+		_, _ = fmt.Fprintf(&mf, "%s(%s)= %s\n", algorithm, f.name, f.checksum)
+	}
+	manifest := packageFile{
+		name: p.Name + ".mf",
+		path: filepath.Join(dir, p.Name+".mf"),
+	}
+	*written = append(*written, manifest.path)
+	if manifest.checksum, err = writeFile(manifest.path, mf.String(), newHash()); err != nil {
+		return nil, err
+	}
+
+	if err := lease.Complete(ctx); err != nil {
+		return nil, fmt.Errorf("error completing export lease: %s", err)
+	}
+	return append([]packageFile{descriptor, manifest}, disks...), nil
+}
+
+// exportItems returns the files of an export lease that are part of the
+// package, with their paths prefixed with the name of the package.
+func exportItems(items []nfc.FileItem, p *ExportParams) []nfc.FileItem {
+	var out []nfc.FileItem
+	for _, item := range items {
+		if !p.IncludeImageFiles && filepath.Ext(item.Path) != ".vmdk" {
+			continue
+		}
+		if !strings.HasPrefix(item.Path, p.Name) {
+			item.Path = p.Name + "-" + item.Path
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func requestExport(ctx context.Context, vm *object.VirtualMachine, snapshotID string) (*nfc.Lease, error) {
+	if snapshotID != "" {
+		return vm.ExportSnapshot(ctx, &types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: snapshotID})
+	}
+	return vm.Export(ctx)
+}
+
+// writeFile writes data to path and returns its checksum.
+func writeFile(path, data string, h hash.Hash) (string, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(io.MultiWriter(f, h), data); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeOVA packages files into a USTAR archive at path, in the supplied order.
+func writeOVA(path string, files []packageFile) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(out)
+	for _, f := range files {
+		if err := addToArchive(tw, f); err != nil {
+			_ = out.Close()
+			return fmt.Errorf("error adding %s to %s: %s", f.name, path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func addToArchive(tw *tar.Writer, f packageFile) error {
+	in, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	stat, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:    f.name,
+		Mode:    0644,
+		Size:    stat.Size(),
+		ModTime: time.Now(),
+		Format:  tar.FormatUSTAR,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, in)
+	return err
+}
+
+// Remove deletes the files of an export.
+func Remove(files []string) error {
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeFiles(files []string) {
+	if err := Remove(files); err != nil {
+		log.Printf("[WARN] Error cleaning up partial export: %s", err)
+	}
+}
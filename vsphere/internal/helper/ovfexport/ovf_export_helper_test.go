@@ -0,0 +1,135 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package ovfexport
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/nfc"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestOutputFiles(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   string
+		expected []string
+	}{
+		{
+			name:     "ovf",
+			format:   FormatOVF,
+			expected: []string{filepath.Join("out", "vm.ovf"), filepath.Join("out", "vm.mf")},
+		},
+		{
+			name:     "ova",
+			format:   FormatOVA,
+			expected: []string{filepath.Join("out", "vm.ova")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := OutputFiles(&ExportParams{Name: "vm", Directory: "out", Format: tc.format})
+			if !reflect.DeepEqual(tc.expected, actual) {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExportItems(t *testing.T) {
+	items := []nfc.FileItem{
+		{OvfFileItem: types.OvfFileItem{Path: "disk-0.vmdk"}},
+		{OvfFileItem: types.OvfFileItem{Path: "vm-disk-1.vmdk"}},
+		{OvfFileItem: types.OvfFileItem{Path: "file-2.iso"}},
+	}
+	cases := []struct {
+		name              string
+		includeImageFiles bool
+		expected          []string
+	}{
+		{
+			name:     "disks",
+			expected: []string{"vm-disk-0.vmdk", "vm-disk-1.vmdk"},
+		},
+		{
+			name:              "image files",
+			includeImageFiles: true,
+			expected:          []string{"vm-disk-0.vmdk", "vm-disk-1.vmdk", "vm-file-2.iso"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+			for _, item := range exportItems(items, &ExportParams{Name: "vm", IncludeImageFiles: tc.includeImageFiles}) {
+				actual = append(actual, item.Path)
+			}
+			if !reflect.DeepEqual(tc.expected, actual) {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+	if items[0].Path != "disk-0.vmdk" {
+		t.Fatalf("expected lease items to be left unchanged, got %s", items[0].Path)
+	}
+}
+
+func TestWriteOVA(t *testing.T) {
+	dir := t.TempDir()
+	contents := map[string]string{
+		"vm.ovf":        "<Envelope/>",
+		"vm.mf":         "SHA256(vm.ovf)= 00\n",
+		"vm-disk1.vmdk": "disk",
+	}
+	files := []packageFile{
+		{name: "vm.ovf"},
+		{name: "vm.mf"},
+		{name: "vm-disk1.vmdk"},
+	}
+	for i := range files {
+		files[i].path = filepath.Join(dir, files[i].name)
+		if err := os.WriteFile(files[i].path, []byte(contents[files[i].name]), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ova := filepath.Join(dir, "vm.ova")
+	if err := writeOVA(ova, files); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(ova)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != contents[hdr.Name] {
+			t.Fatalf("unexpected contents for %s: %q", hdr.Name, data)
+		}
+		names = append(names, hdr.Name)
+	}
+	expected := []string{"vm.ovf", "vm.mf", "vm-disk1.vmdk"}
+	if !reflect.DeepEqual(expected, names) {
+		t.Fatalf("expected archive order %v, got %v", expected, names)
+	}
+}
@@ -5,8 +5,11 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 )
 
 // DefaultAPITimeout is a default timeout value that is passed to functions
@@ -16,3 +19,16 @@ const DefaultAPITimeout = time.Minute * 5
 func Error(id string, function string, err error) error {
 	return fmt.Errorf("%s: RESOURCE (%s), ACTION (%s)", err, id, function)
 }
+
+// DiagnosticsError converts the error diagnostics returned by a context-aware
+// CRUD function into an error, for callers such as importers that need to
+// return one.
+func DiagnosticsError(diags diag.Diagnostics) error {
+	var errs []error
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			errs = append(errs, errors.New(d.Summary))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,202 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package proxyhelper
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// Schemes are the supported proxy URL schemes. HTTP proxies are used through
+// CONNECT tunnels, so that certificates are verified end to end.
+var Schemes = []string{
+	"http",
+	"socks5",
+	"socks5h",
+}
+
+// dialer is the dialer for the connections to the proxy, and for the
+// connections made without a proxy.
+var dialer = &net.Dialer{
+	Timeout:   30 * time.Second,
+	KeepAlive: 30 * time.Second,
+}
+
+// Options describe the proxy that the provider connects through.
+type Options struct {
+	// URL is the URL of the proxy. Credentials in the URL are used to
+	// authenticate to the proxy.
+	URL string
+
+	// NoProxy is a comma separated list of host names, domains, addresses, and
+	// CIDR ranges that are connected to directly, in the format of the
+	// NO_PROXY environment variable.
+	NoProxy string
+}
+
+// Enabled returns true if a proxy is configured.
+func (o *Options) Enabled() bool {
+	return o != nil && o.URL != ""
+}
+
+// Validate checks that the proxy URL is valid.
+func (o *Options) Validate() error {
+	if !o.Enabled() {
+		return nil
+	}
+	u, err := url.Parse(o.URL)
+	if err != nil {
+		return fmt.Errorf("error parsing proxy URL: %s", err)
+	}
+	for _, scheme := range Schemes {
+		if u.Scheme == scheme {
+			if u.Host == "" {
+				return fmt.Errorf("proxy URL %q has no host", u.Redacted())
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported proxy URL scheme %q", u.Scheme)
+}
+
+// ProxyURL returns the URL of the proxy to connect to addr through, or nil if
+// addr is connected to directly.
+func (o *Options) ProxyURL(scheme, addr string) (*url.URL, error) {
+	if !o.Enabled() {
+		return nil, nil
+	}
+	config := httpproxy.Config{
+		HTTPProxy:  o.URL,
+		HTTPSProxy: o.URL,
+		NoProxy:    o.NoProxy,
+	}
+	return config.ProxyFunc()(&url.URL{Scheme: scheme, Host: addr})
+}
+
+// Proxy is an http.Transport Proxy function for plain HTTP requests, which
+// are forwarded by HTTP proxies. Other requests are tunneled by DialContext.
+func (o *Options) Proxy(req *http.Request) (*url.URL, error) {
+	if req.URL.Scheme != "http" {
+		return nil, nil
+	}
+	u, err := o.ProxyURL(req.URL.Scheme, req.URL.Host)
+	if err != nil || u == nil || u.Scheme != "http" {
+		return nil, err
+	}
+	return u, nil
+}
+
+// DialContext connects to addr through the proxy, or directly when addr
+// matches NoProxy. Host names are resolved by the proxy, except with the
+// socks5 scheme.
+func (o *Options) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	u, err := o.ProxyURL("https", addr)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil || proxyAddr(u) == addr {
+		// Connections to the proxy itself are made by the transport to
+		// forward plain HTTP requests.
+		return dialer.DialContext(ctx, network, addr)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		return dialSOCKS(ctx, u, network, addr)
+	case "http":
+		return dialCONNECT(ctx, u, network, addr)
+	}
+	return nil, fmt.Errorf("unsupported proxy URL scheme %q", u.Scheme)
+}
+
+// dialSOCKS connects to addr through a SOCKS5 proxy.
+func dialSOCKS(ctx context.Context, u *url.URL, network, addr string) (net.Conn, error) {
+	if u.Scheme == "socks5" {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		addr = net.JoinHostPort(ips[0].IP.String(), port)
+	}
+	var auth *proxy.Auth
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = &proxy.Auth{User: u.User.Username(), Password: password}
+	}
+	d, err := proxy.SOCKS5("tcp", u.Host, auth, dialer)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := d.(proxy.ContextDialer).DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s through proxy %s: %s", addr, u.Redacted(), err)
+	}
+	return conn, nil
+}
+
+// dialCONNECT connects to addr through a CONNECT tunnel of an HTTP proxy.
+func dialCONNECT(ctx context.Context, u *url.URL, network, addr string) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, network, proxyAddr(u))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to proxy %s: %s", u.Redacted(), err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error connecting to %s through proxy %s: %s", addr, u.Redacted(), err)
+	}
+	// The proxy does not send data before the response to CONNECT, so
+	// nothing is buffered past it.
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error connecting to %s through proxy %s: %s", addr, u.Redacted(), err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error connecting to %s through proxy %s: %s", addr, u.Redacted(), res.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// proxyAddr returns the address of a proxy, with the default port of its
+// scheme if the URL has none.
+func proxyAddr(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "http" {
+		return net.JoinHostPort(u.Hostname(), "80")
+	}
+	return net.JoinHostPort(u.Hostname(), "1080")
+}
@@ -18,18 +18,19 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/computeresource"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // List retrieves all resource pools.
-func List(client *govmomi.Client) ([]*object.ResourcePool, error) {
-	return FromPath(client, "/*")
+func List(ctx context.Context, client *govmomi.Client) ([]*object.ResourcePool, error) {
+	return FromPath(ctx, client, "/*")
 }
 
 // FromPathOrDefault retrieves a resource pool using its supplied path.
-func FromPathOrDefault(client *govmomi.Client, name string, dc *object.Datacenter) (*object.ResourcePool, error) {
+func FromPathOrDefault(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.ResourcePool, error) {
 	finder := find.NewFinder(client.Client, false)
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	t := client.ServiceContent.About.ApiType
 	switch t {
@@ -53,7 +54,7 @@ func FromPathOrDefault(client *govmomi.Client, name string, dc *object.Datacente
 }
 
 // FromParentAndName retrieves a resource pool by its name and the ID of its parent resource pool.
-func FromParentAndName(client *govmomi.Client, parentID string, name string) (*object.ResourcePool, error) {
+func FromParentAndName(ctx context.Context, client *govmomi.Client, parentID string, name string) (*object.ResourcePool, error) {
 	if strings.Contains(name, "/") {
 		return nil, fmt.Errorf("argument 'name' cannot be a path when 'parent_resource_pool_id' is specified, use the simple resource pool name")
 	}
@@ -64,8 +65,8 @@ func FromParentAndName(client *govmomi.Client, parentID string, name string) (*o
 		Value: parentID,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	parentObj, err := finder.ObjectReference(ctx, parentRef)
+	octx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	parentObj, err := finder.ObjectReference(octx, parentRef)
 	defer cancel()
 	if err != nil {
 		return nil, fmt.Errorf("could not find parent resource pool with ID %q: %w", parentID, err)
@@ -76,9 +77,9 @@ func FromParentAndName(client *govmomi.Client, parentID string, name string) (*o
 		return nil, fmt.Errorf("object with ID %q is not a ResourcePool", parentID)
 	}
 
-	ctx, cancel = context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	octx, cancel = context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	var parentMo mo.ResourcePool
-	err = parentRP.Properties(ctx, parentRP.Reference(), []string{"resourcePool"}, &parentMo)
+	err = parentRP.Properties(octx, parentRP.Reference(), []string{"resourcePool"}, &parentMo)
 	defer cancel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get properties for parent resource pool %q: %w", parentID, err)
@@ -86,10 +87,10 @@ func FromParentAndName(client *govmomi.Client, parentID string, name string) (*o
 
 	var errorMessages []string
 	for _, childRef := range parentMo.ResourcePool {
-		ctx, cancel = context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+		octx, cancel = context.WithTimeout(ctx, provider.DefaultAPITimeout)
 		var childMo mo.ResourcePool
 		childRP := object.NewResourcePool(client.Client, childRef)
-		err = childRP.Properties(ctx, childRef, []string{"name"}, &childMo)
+		err = childRP.Properties(octx, childRef, []string{"name"}, &childMo)
 
 		if err != nil {
 			cancel()
@@ -135,8 +136,7 @@ func FromParentAndName(client *govmomi.Client, parentID string, name string) (*o
 }
 
 // FromPath retrieves all resource pools recursively from the specified inventory path.
-func FromPath(client *govmomi.Client, path string) ([]*object.ResourcePool, error) {
-	ctx := context.TODO()
+func FromPath(ctx context.Context, client *govmomi.Client, path string) ([]*object.ResourcePool, error) {
 	var rps []*object.ResourcePool
 	finder := find.NewFinder(client.Client, false)
 	es, err := finder.ManagedObjectListChildren(ctx, path+"/*", "pool", "folder")
@@ -145,14 +145,14 @@ func FromPath(client *govmomi.Client, path string) ([]*object.ResourcePool, erro
 	}
 	for _, id := range es {
 		if id.Object.Reference().Type == "ResourcePool" {
-			ds, err := FromID(client, id.Object.Reference().Value)
+			ds, err := FromID(ctx, client, id.Object.Reference().Value)
 			if err != nil {
 				return nil, err
 			}
 			rps = append(rps, ds)
 		}
 		if id.Object.Reference().Type == "Folder" || id.Object.Reference().Type == "ClusterComputeResource" || id.Object.Reference().Type == "ResourcePool" {
-			newRPs, err := FromPath(client, id.Path)
+			newRPs, err := FromPath(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -163,7 +163,7 @@ func FromPath(client *govmomi.Client, path string) ([]*object.ResourcePool, erro
 }
 
 // FromID retrieves a resource pool by its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (*object.ResourcePool, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*object.ResourcePool, error) {
 	log.Printf("[DEBUG] Locating resource pool with ID %s", id)
 	finder := find.NewFinder(client.Client, false)
 
@@ -172,7 +172,7 @@ func FromID(client *govmomi.Client, id string) (*object.ResourcePool, error) {
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	obj, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -183,8 +183,8 @@ func FromID(client *govmomi.Client, id string) (*object.ResourcePool, error) {
 }
 
 // Properties retrieves the resource pool managed object from its higher-level object.
-func Properties(obj *object.ResourcePool) (*mo.ResourcePool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, obj *object.ResourcePool) (*mo.ResourcePool, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.ResourcePool
 	if err := obj.Properties(ctx, obj.Reference(), nil, &props); err != nil {
@@ -194,18 +194,18 @@ func Properties(obj *object.ResourcePool) (*mo.ResourcePool, error) {
 }
 
 // ValidateHost verifies if the specified host is a member of the given resource pool.
-func ValidateHost(client *govmomi.Client, pool *object.ResourcePool, host *object.HostSystem) error {
+func ValidateHost(ctx context.Context, client *govmomi.Client, pool *object.ResourcePool, host *object.HostSystem) error {
 	if host == nil {
 		// Nothing to validate here, move along
 		log.Printf("[DEBUG] ValidateHost: no host supplied, nothing to do")
 		return nil
 	}
 	log.Printf("[DEBUG] Validating that host %q is a member of resource pool %q", host.Reference().Value, pool.Reference().Value)
-	pprops, err := Properties(pool)
+	pprops, err := Properties(ctx, pool)
 	if err != nil {
 		return err
 	}
-	cprops, err := computeresource.BasePropertiesFromReference(client, pprops.Owner)
+	cprops, err := computeresource.BasePropertiesFromReference(ctx, client, pprops.Owner)
 	if err != nil {
 		return err
 	}
@@ -219,29 +219,29 @@ func ValidateHost(client *govmomi.Client, pool *object.ResourcePool, host *objec
 }
 
 // DefaultDevices retrieves the default virtual device list for a given resource pool and guest OS type.
-func DefaultDevices(client *govmomi.Client, pool *object.ResourcePool, guest string) (object.VirtualDeviceList, error) {
+func DefaultDevices(ctx context.Context, client *govmomi.Client, pool *object.ResourcePool, guest string) (object.VirtualDeviceList, error) {
 	log.Printf("[DEBUG] Fetching default device list for resource pool %q for OS type %q", pool.Reference().Value, guest)
-	pprops, err := Properties(pool)
+	pprops, err := Properties(ctx, pool)
 	if err != nil {
 		return nil, err
 	}
-	return computeresource.DefaultDevicesFromReference(client, pprops.Owner, guest)
+	return computeresource.DefaultDevicesFromReference(ctx, client, pprops.Owner, guest)
 }
 
 // OSFamily determines the operating system family for a given guest ID based on the resource pool and hardware version.
-func OSFamily(client *govmomi.Client, pool *object.ResourcePool, guest string, hardwareVersion int) (string, error) {
+func OSFamily(ctx context.Context, client *govmomi.Client, pool *object.ResourcePool, guest string, hardwareVersion int) (string, error) {
 	log.Printf("[DEBUG] Looking for OS family for guest ID %q", guest)
-	pprops, err := Properties(pool)
+	pprops, err := Properties(ctx, pool)
 	if err != nil {
 		return "", err
 	}
-	return computeresource.OSFamily(client, pprops.Owner, guest, hardwareVersion)
+	return computeresource.OSFamily(ctx, client, pprops.Owner, guest, hardwareVersion)
 }
 
 // Create creates a resource pool.
-func Create(rp *object.ResourcePool, name string, spec *types.ResourceConfigSpec) (*object.ResourcePool, error) {
+func Create(ctx context.Context, rp *object.ResourcePool, name string, spec *types.ResourceConfigSpec) (*object.ResourcePool, error) {
 	log.Printf("[DEBUG] Creating resource pool %q", fmt.Sprintf("%s/%s", rp.InventoryPath, name))
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	nrp, err := rp.Create(ctx, name, *spec)
 	if err != nil {
@@ -251,32 +251,32 @@ func Create(rp *object.ResourcePool, name string, spec *types.ResourceConfigSpec
 }
 
 // Update updates a resource pool.
-func Update(rp *object.ResourcePool, name string, spec *types.ResourceConfigSpec) error {
+func Update(ctx context.Context, rp *object.ResourcePool, name string, spec *types.ResourceConfigSpec) error {
 	log.Printf("[DEBUG] Updating resource pool %q", rp.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return rp.UpdateConfig(ctx, name, spec)
 }
 
 // Delete destroys a resource pool.
-func Delete(rp *object.ResourcePool) error {
+func Delete(ctx context.Context, rp *object.ResourcePool) error {
 	log.Printf("[DEBUG] Deleting resource pool %q", rp.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := rp.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // MoveIntoResourcePool moves a virtual machine, resource pool, or vApp into the specified resource pool.
-func MoveIntoResourcePool(p *object.ResourcePool, c types.ManagedObjectReference) error {
+func MoveIntoResourcePool(ctx context.Context, p *object.ResourcePool, c types.ManagedObjectReference) error {
 	req := types.MoveIntoResourcePool{
 		This: p.Reference(),
 		List: []types.ManagedObjectReference{c},
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	_, err := methods.MoveIntoResourcePool(ctx, p.Client(), &req)
 	return err
@@ -285,8 +285,8 @@ func MoveIntoResourcePool(p *object.ResourcePool, c types.ManagedObjectReference
 // HasChildren checks to see if a resource pool has any child items and returns true if that is the case.
 // This is useful when checking to see if a resource pool is safe to delete. Destroying a resource pool destroys all
 // children if at all possible, so extra verification is necessary to prevent accidental removal.
-func HasChildren(rp *object.ResourcePool) (bool, error) {
-	props, err := Properties(rp)
+func HasChildren(ctx context.Context, rp *object.ResourcePool) (bool, error) {
+	props, err := Properties(ctx, rp)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,360 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package sessioncache
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// KeySize is the size of the encryption keys of the cache, in bytes.
+	KeySize = 32
+
+	// DefaultMaxIdle is the time after which entries that have not been used
+	// are pruned.
+	DefaultMaxIdle = 24 * time.Hour
+
+	// keyringService and keyringUser identify the key of the cache in the OS
+	// keyring.
+	keyringService = "terraform-provider-vsphere"
+	keyringUser    = "session-cache"
+
+	// envelopeVersion is the version of the format of encrypted entries.
+	envelopeVersion = 1
+
+	// lockTimeout is the time to wait for the lock of an entry held by
+	// another process.
+	lockTimeout = time.Minute
+
+	// lockRetryDelay is the delay between attempts to take the lock of an
+	// entry.
+	lockRetryDelay = 100 * time.Millisecond
+)
+
+// entryName matches the names of cache entries, which are SHA-256 digests of
+// the endpoint they hold the session of.
+var entryName = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Cache reads and writes the sessions of the API clients. Entries are
+// encrypted with AES-256-GCM when the cache has a key, and are JSON encoded in
+// the same format as govc otherwise.
+//
+// Entries are locked while they are read and written, so that concurrent
+// Terraform processes sharing a cache directory do not read partially written
+// entries. Entries that cannot be read, such as entries encrypted with another
+// key, are removed.
+type Cache struct {
+	key     []byte
+	keyID   string
+	maxIdle time.Duration
+}
+
+// envelope is the format of an encrypted entry.
+type envelope struct {
+	// Version is the version of the format.
+	Version int `json:"version"`
+
+	// KeyID identifies the key the entry is encrypted with, so that entries
+	// encrypted with another key are told apart from corrupted entries.
+	KeyID string `json:"key_id"`
+
+	// Nonce is the nonce of the AES-GCM cipher.
+	Nonce []byte `json:"nonce"`
+
+	// Data is the encrypted session.
+	Data []byte `json:"data"`
+}
+
+// New returns a Cache that encrypts entries with key. Entries are not
+// encrypted when key is nil.
+func New(key []byte) (*Cache, error) {
+	c := &Cache{maxIdle: DefaultMaxIdle}
+	if key == nil {
+		return c, nil
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("session cache key must be %d bytes, got %d", KeySize, len(key))
+	}
+	sum := sha256.Sum256(key)
+	c.key = key
+	c.keyID = hex.EncodeToString(sum[:8])
+	return c, nil
+}
+
+// Encrypted returns true if the entries of the cache are encrypted.
+func (c *Cache) Encrypted() bool {
+	return c.key != nil
+}
+
+// ParseKey decodes a base64 encoded key, such as the output of
+// "openssl rand -base64 32".
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("session cache key is not base64 encoded: %s", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("session cache key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// KeyringKey returns the key stored in the OS keyring, storing a new random
+// key if there is none.
+func KeyringKey() ([]byte, error) {
+	s, err := keyring.Get(keyringService, keyringUser)
+	if err == nil {
+		return ParseKey(s)
+	}
+	if !errors.Is(err, keyring.ErrNotFound) {
+		return nil, fmt.Errorf("error reading session cache key from OS keyring: %s", err)
+	}
+
+	log.Printf("[DEBUG] Storing new session cache key in OS keyring")
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := keyring.Set(keyringService, keyringUser, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("error storing session cache key in OS keyring: %s", err)
+	}
+	return key, nil
+}
+
+// Load decodes the entry at path into v. It returns false if there is no
+// entry, or if the entry cannot be read and has been removed.
+func (c *Cache) Load(path string, v any) (bool, error) {
+	b, err := c.read(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := c.decode(path, b, v); err != nil {
+		log.Printf("[DEBUG] Removing session cache entry %q: %s", path, err)
+		return false, c.Remove(path)
+	}
+
+	// Entries are pruned by the time they were last used.
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		log.Printf("[DEBUG] Error updating session cache entry %q: %s", path, err)
+	}
+	return true, nil
+}
+
+// Save encodes v into the entry at path. The entry is replaced atomically.
+func (c *Cache) Save(path string, v any) error {
+	b, err := c.encode(path, v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	unlock, err := lock(path, false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Remove removes the entry at path, such as an entry holding an expired
+// session.
+func (c *Cache) Remove(path string) error {
+	unlock, err := lock(path, false)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Prune removes the entries in dir that have not been used for longer than
+// DefaultMaxIdle, whether they are encrypted or not. Files that are not named
+// like entries are left in place.
+func (c *Cache) Prune(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !entryName.MatchString(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < c.maxIdle {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		log.Printf("[DEBUG] Removing session cache entry %q unused since %s", path, info.ModTime().Format(time.RFC3339))
+		if err := c.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read returns the content of the entry at path.
+func (c *Cache) read(path string) ([]byte, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	unlock, err := lock(path, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return os.ReadFile(path)
+}
+
+// decode decrypts the entry at path if the cache is encrypted, and decodes
+// it into v.
+func (c *Cache) decode(path string, b []byte, v any) error {
+	e, encrypted := parseEnvelope(b)
+	switch {
+	case encrypted && !c.Encrypted():
+		return errors.New("entry is encrypted and no key is configured")
+	case !encrypted && c.Encrypted():
+		return errors.New("entry is not encrypted")
+	case encrypted:
+		if e.KeyID != c.keyID {
+			return errors.New("entry is encrypted with another key")
+		}
+		gcm, err := c.cipher()
+		if err != nil {
+			return err
+		}
+		b, err = gcm.Open(nil, e.Nonce, e.Data, []byte(filepath.Base(path)))
+		if err != nil {
+			return fmt.Errorf("error decrypting entry: %s", err)
+		}
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("error decoding entry: %s", err)
+	}
+	return nil
+}
+
+// encode encodes v, and encrypts it for the entry at path if the cache is
+// encrypted. The name of the entry is authenticated with the session, so
+// that entries cannot be swapped.
+func (c *Cache) encode(path string, v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Encrypted() {
+		return b, nil
+	}
+
+	gcm, err := c.cipher()
+	if err != nil {
+		return nil, err
+	}
+	e := envelope{
+		Version: envelopeVersion,
+		KeyID:   c.keyID,
+		Nonce:   make([]byte, gcm.NonceSize()),
+	}
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return nil, err
+	}
+	e.Data = gcm.Seal(nil, e.Nonce, b, []byte(filepath.Base(path)))
+	return json.Marshal(e)
+}
+
+func (c *Cache) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// parseEnvelope returns the envelope of an encrypted entry, and false if the
+// entry is not encrypted.
+func parseEnvelope(b []byte) (*envelope, bool) {
+	var e envelope
+	if err := json.Unmarshal(b, &e); err != nil || e.Version != envelopeVersion || len(e.Data) == 0 {
+		return nil, false
+	}
+	return &e, true
+}
+
+// lock takes the lock of the entry at path, shared for reads, and returns a
+// function that releases it. The lock is held on a separate file, as entries
+// are replaced when they are written.
+func lock(path string, shared bool) (func(), error) {
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	l := flock.New(path + ".lock")
+	try := l.TryLockContext
+	if shared {
+		try = l.TryRLockContext
+	}
+	ok, err := try(ctx, lockRetryDelay)
+	if err == nil && !ok {
+		err = errors.New("lock is held by another process")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error locking session cache entry %q: %s", path, err)
+	}
+	return func() {
+		if err := l.Unlock(); err != nil {
+			log.Printf("[DEBUG] Error unlocking session cache entry %q: %s", path, err)
+		}
+	}, nil
+}
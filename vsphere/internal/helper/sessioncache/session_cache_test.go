@@ -0,0 +1,212 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package sessioncache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+const testEntryName = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+type testSession struct {
+	URL       string
+	SessionID string
+}
+
+func testCache(t *testing.T, key string) *Cache {
+	var k []byte
+	if key != "" {
+		k = bytes.Repeat([]byte(key), KeySize/len(key))
+	}
+	c, err := New(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions", testEntryName)
+	c := testCache(t, "key1")
+	expected := testSession{URL: "https://vcenter/sdk", SessionID: "secret-session-id"}
+
+	if ok, err := c.Load(path, new(testSession)); ok || err != nil {
+		t.Fatalf("expected no entry, got %t, %v", ok, err)
+	}
+	if err := c.Save(path, expected); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), expected.SessionID) {
+		t.Fatalf("expected entry to be encrypted, got %s", b)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected entry mode 0600, got %s", info.Mode())
+	}
+
+	var actual testSession
+	if ok, err := c.Load(path, &actual); !ok || err != nil {
+		t.Fatalf("expected entry, got %t, %v", ok, err)
+	}
+	if actual != expected {
+		t.Fatalf("expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestCache_mismatched(t *testing.T) {
+	cases := []struct {
+		name  string
+		save  *Cache
+		load  *Cache
+		moved bool
+	}{
+		{"another key", testCache(t, "key1"), testCache(t, "key2"), false},
+		{"unencrypted entry", testCache(t, ""), testCache(t, "key1"), false},
+		{"encrypted entry without key", testCache(t, "key1"), testCache(t, ""), false},
+		{"entry of another endpoint", testCache(t, "key1"), testCache(t, "key1"), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, testEntryName)
+			if err := tc.save.Save(path, testSession{SessionID: "id"}); err != nil {
+				t.Fatal(err)
+			}
+			if tc.moved {
+				moved := filepath.Join(dir, strings.Repeat("f", 64))
+				if err := os.Rename(path, moved); err != nil {
+					t.Fatal(err)
+				}
+				path = moved
+			}
+			if ok, err := tc.load.Load(path, new(testSession)); ok || err != nil {
+				t.Fatalf("expected entry to be ignored, got %t, %v", ok, err)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Fatalf("expected entry to be removed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCache_prune(t *testing.T) {
+	dir := t.TempDir()
+	c := testCache(t, "key1")
+	old := time.Now().Add(-2 * DefaultMaxIdle)
+
+	entries := map[string]struct {
+		cache   *Cache
+		old     bool
+		removed bool
+	}{
+		strings.Repeat("a", 64):  {c, true, true},
+		strings.Repeat("b", 64):  {c, false, false},
+		strings.Repeat("c", 64):  {testCache(t, ""), true, true},
+		strings.Repeat("d", 64):  {testCache(t, ""), false, false},
+		"a-file-of-another-tool": {c, true, false},
+	}
+	for name, e := range entries {
+		path := filepath.Join(dir, name)
+		if err := e.cache.Save(path, testSession{SessionID: name}); err != nil {
+			t.Fatal(err)
+		}
+		if e.old {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := c.Prune(dir); err != nil {
+		t.Fatal(err)
+	}
+	for name, e := range entries {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if removed := os.IsNotExist(err); removed != e.removed {
+			t.Errorf("%s: expected removed to be %t, got %t", name, e.removed, removed)
+		}
+	}
+
+	if err := c.Prune(filepath.Join(dir, "missing")); err != nil {
+		t.Fatalf("expected missing directory to be ignored, got %s", err)
+	}
+}
+
+func TestCache_concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), testEntryName)
+	c := testCache(t, "key1")
+	if err := c.Save(path, testSession{SessionID: "initial"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- c.Save(path, testSession{SessionID: fmt.Sprintf("session-%d", i)})
+		}(i)
+		go func() {
+			defer wg.Done()
+			if ok, err := c.Load(path, new(testSession)); !ok || err != nil {
+				errs <- fmt.Errorf("expected entry, got %t, %v", ok, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, KeySize)
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString(key)); err != nil {
+		t.Fatalf("expected key to be valid, got %s", err)
+	}
+	if _, err := ParseKey("not base64!"); err == nil {
+		t.Fatal("expected error for key that is not base64 encoded")
+	}
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString(key[:16])); err == nil {
+		t.Fatal("expected error for 128-bit key")
+	}
+}
+
+func TestKeyringKey(t *testing.T) {
+	keyring.MockInit()
+
+	key, err := KeyringKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := KeyringKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != KeySize || !bytes.Equal(key, again) {
+		t.Fatal("expected the key stored in the keyring to be reused")
+	}
+}
@@ -38,8 +38,8 @@ func pbmClientFromGovmomiClient(ctx context.Context, client *govmomi.Client) (*p
 }
 
 // PolicyIDByName finds a SPBM storage policy by name and returns its ID.
-func PolicyIDByName(client *govmomi.Client, name string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func PolicyIDByName(ctx context.Context, client *govmomi.Client, name string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	pc, err := pbmClientFromGovmomiClient(ctx, client)
 	if err != nil {
@@ -50,8 +50,8 @@ func PolicyIDByName(client *govmomi.Client, name string) (string, error) {
 }
 
 // PolicyNameByID returns storage policy name by its ID.
-func PolicyNameByID(client *govmomi.Client, id string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func PolicyNameByID(ctx context.Context, client *govmomi.Client, id string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	pc, err := pbmClientFromGovmomiClient(ctx, client)
 	if err != nil {
@@ -82,8 +82,8 @@ func PolicySpecByID(id string) []types.BaseVirtualMachineProfileSpec {
 }
 
 // PolicyIDByVirtualDisk fetches the storage policy associated with a virtual disk.
-func PolicyIDByVirtualDisk(client *govmomi.Client, vmMOID string, diskKey int) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func PolicyIDByVirtualDisk(ctx context.Context, client *govmomi.Client, vmMOID string, diskKey int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	pc, err := pbmClientFromGovmomiClient(ctx, client)
 	if err != nil {
@@ -109,8 +109,8 @@ func PolicyIDByVirtualDisk(client *govmomi.Client, vmMOID string, diskKey int) (
 }
 
 // PolicyIDByVirtualMachine fetches the storage policy associated with a virtual machine.
-func PolicyIDByVirtualMachine(client *govmomi.Client, vmMOID string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func PolicyIDByVirtualMachine(ctx context.Context, client *govmomi.Client, vmMOID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	pc, err := pbmClientFromGovmomiClient(ctx, client)
 	if err != nil {
@@ -135,6 +135,51 @@ func PolicyIDByVirtualMachine(client *govmomi.Client, vmMOID string) (string, er
 	return policies[0].UniqueId, nil
 }
 
+// PolicyIDByFirstClassDisk fetches the storage policy associated with a first
+// class disk.
+func PolicyIDByFirstClassDisk(ctx context.Context, client *govmomi.Client, id string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	pc, err := pbmClientFromGovmomiClient(ctx, client)
+	if err != nil {
+		return "", provider.Error(id, "PolicyIDByFirstClassDisk", err)
+	}
+
+	pbmSOR := pbmtypes.PbmServerObjectRef{
+		ObjectType: string(pbmtypes.PbmObjectTypeVirtualDiskUUID),
+		Key:        id,
+	}
+
+	policies, err := queryAssociatedProfile(ctx, pc, pbmSOR)
+	if err != nil {
+		return "", provider.Error(id, "PolicyIDByFirstClassDisk", err)
+	}
+
+	// If no policy returned then the disk is not associated with a policy
+	if len(policies) == 0 {
+		return "", nil
+	}
+
+	return policies[0].UniqueId, nil
+}
+
+// PolicySupportsEncryption returns true if the storage policy with the
+// supplied ID includes the VM encryption data service.
+func PolicySupportsEncryption(ctx context.Context, client *govmomi.Client, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	pc, err := pbmClientFromGovmomiClient(ctx, client)
+	if err != nil {
+		return false, provider.Error(id, "PolicySupportsEncryption", err)
+	}
+
+	supported, err := pc.SupportsEncryption(ctx, id)
+	if err != nil {
+		return false, provider.Error(id, "PolicySupportsEncryption", err)
+	}
+	return supported, nil
+}
+
 // queryAssociatedProfile returns the PbmProfileId of the storage policy associated with entity.
 func queryAssociatedProfile(ctx context.Context, pc *pbm.Client, ref pbmtypes.PbmServerObjectRef) ([]pbmtypes.PbmProfileId, error) {
 	log.Printf("[DEBUG] queryAssociatedProfile: Retrieving storage policy of server object of type [%s] and key [%s].", ref.ObjectType, ref.Key)
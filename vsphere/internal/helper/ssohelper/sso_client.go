@@ -19,6 +19,7 @@ import (
 type SsoClient struct {
 	vc       *vim25.Client
 	userinfo *url.Userinfo
+	token    TokenFunc // token, when set, is used instead of userinfo
 
 	mu     sync.Mutex
 	client *ssoadmin.Client // cached logged-in client; nil until first use
@@ -48,14 +49,12 @@ func (s *SsoClient) login(ctx context.Context) (*ssoadmin.Client, error) {
 	}
 
 	// This mirrors govmomi's govc/sso/client.go flow.
-	tokens, err := sts.NewClient(ctx, s.vc)
-	if err != nil {
-		return nil, err
+	var signer *sts.Signer
+	if s.token != nil {
+		signer, err = s.token(ctx)
+	} else {
+		signer, err = IssueToken(ctx, s.vc, s.userinfo, s.vc.Certificate(), 0)
 	}
-	signer, err := tokens.Issue(ctx, sts.TokenRequest{
-		Certificate: s.vc.Certificate(),
-		Userinfo:    s.userinfo,
-	})
 	if err != nil {
 		return nil, err
 	}
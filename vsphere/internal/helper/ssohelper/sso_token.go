@@ -0,0 +1,100 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package ssohelper
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/sts"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// tokenExchangePath is the path of the token exchange service of vCenter
+// Server 8.0 and later.
+const tokenExchangePath = "/api/vcenter/tokenservice/token-exchange"
+
+// TokenFunc returns a signer holding a SAML token to authenticate with.
+type TokenFunc func(ctx context.Context) (*sts.Signer, error)
+
+// NewWithToken returns an SsoClient that logs in to the SSO admin service with
+// the token returned by token, instead of issuing one for a user name and
+// password.
+func NewWithToken(vc *vim25.Client, token TokenFunc) *SsoClient {
+	return &SsoClient{vc: vc, token: token}
+}
+
+// IssueToken requests a SAML token from the Security Token Service of vCenter
+// Server. A holder-of-key token is issued when cert is set, such as for a
+// solution user, and a bearer token for userinfo otherwise. A zero lifetime
+// uses the default of the STS client.
+func IssueToken(ctx context.Context, vc *vim25.Client, userinfo *url.Userinfo, cert *tls.Certificate, lifetime time.Duration) (*sts.Signer, error) {
+	tokens, err := sts.NewClient(ctx, vc)
+	if err != nil {
+		return nil, err
+	}
+	return tokens.Issue(ctx, sts.TokenRequest{
+		Certificate: cert,
+		Userinfo:    userinfo,
+		Lifetime:    lifetime,
+	})
+}
+
+// ParseToken returns the SAML assertion in token, which can be either the XML
+// of the assertion or its base64 encoding.
+func ParseToken(token string) (string, error) {
+	token = strings.TrimSpace(token)
+	if strings.HasPrefix(token, "<") {
+		return token, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		b, err := enc.DecodeString(token)
+		if err == nil && strings.HasPrefix(strings.TrimSpace(string(b)), "<") {
+			return strings.TrimSpace(string(b)), nil
+		}
+	}
+	return "", errors.New("token is not a SAML assertion")
+}
+
+// ExchangeOAuthToken exchanges an OAuth 2.0 access token, issued by an
+// identity provider federated with vCenter Server, for a SAML token through
+// the token exchange service of vCenter Server.
+func ExchangeOAuthToken(ctx context.Context, vc *vim25.Client, token string) (string, error) {
+	rc := rest.NewClient(vc)
+	req := rc.Resource(tokenExchangePath).Request(http.MethodPost, map[string]string{
+		"grant_type":           "urn:ietf:params:oauth:grant-type:token-exchange",
+		"subject_token":        token,
+		"subject_token_type":   "urn:ietf:params:oauth:token-type:access_token",
+		"requested_token_type": "urn:ietf:params:oauth:token-type:saml2",
+	})
+	var res struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := rc.Do(ctx, req, &res); err != nil {
+		return "", fmt.Errorf("error exchanging OAuth token: %s", err)
+	}
+	return ParseToken(res.AccessToken)
+}
+
+// LoginByToken creates a SOAP API session for the token held by signer.
+func LoginByToken(ctx context.Context, vc *vim25.Client, signer *sts.Signer) error {
+	header := soap.Header{Security: signer}
+	return session.NewManager(vc).LoginByToken(vc.WithHeader(ctx, header))
+}
+
+// LoginRESTByToken creates a REST API session for the token held by signer.
+func LoginRESTByToken(ctx context.Context, rc *rest.Client, signer *sts.Signer) error {
+	return rc.LoginByToken(rc.WithSigner(ctx, signer))
+}
@@ -26,7 +26,7 @@ import (
 )
 
 // FromID locates a StoragePod by its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (*object.StoragePod, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*object.StoragePod, error) {
 	log.Printf("[DEBUG] Locating datastore cluster with ID %q", id)
 	finder := find.NewFinder(client.Client, false)
 
@@ -35,7 +35,7 @@ func FromID(client *govmomi.Client, id string) (*object.StoragePod, error) {
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	r, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -46,12 +46,11 @@ func FromID(client *govmomi.Client, id string) (*object.StoragePod, error) {
 	return pod, nil
 }
 
-func List(client *govmomi.Client) ([]*object.StoragePod, error) {
-	return getDatastoreClusters(client, "/*")
+func List(ctx context.Context, client *govmomi.Client) ([]*object.StoragePod, error) {
+	return getDatastoreClusters(ctx, client, "/*")
 }
 
-func getDatastoreClusters(client *govmomi.Client, path string) ([]*object.StoragePod, error) {
-	ctx := context.TODO()
+func getDatastoreClusters(ctx context.Context, client *govmomi.Client, path string) ([]*object.StoragePod, error) {
 	var dss []*object.StoragePod
 	finder := find.NewFinder(client.Client, false)
 	es, err := finder.ManagedObjectListChildren(ctx, path+"/*", "folder", "storagepod")
@@ -61,13 +60,13 @@ func getDatastoreClusters(client *govmomi.Client, path string) ([]*object.Storag
 	for _, id := range es {
 		switch {
 		case id.Object.Reference().Type == "StoragePod":
-			ds, err := FromID(client, id.Object.Reference().Value)
+			ds, err := FromID(ctx, client, id.Object.Reference().Value)
 			if err != nil {
 				return nil, err
 			}
 			dss = append(dss, ds)
 		case id.Object.Reference().Type == "Folder":
-			newDSs, err := getDatastoreClusters(client, id.Path)
+			newDSs, err := getDatastoreClusters(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -81,7 +80,7 @@ func getDatastoreClusters(client *govmomi.Client, path string) ([]*object.Storag
 
 // FromPath loads a StoragePod from its path. The datacenter is optional if the
 // path is specific enough to not require it.
-func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*object.StoragePod, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.StoragePod, error) {
 	finder := find.NewFinder(client.Client, false)
 	if dc != nil {
 		log.Printf("[DEBUG] Attempting to locate datastore cluster %q in datacenter %q", name, dc.InventoryPath)
@@ -90,15 +89,15 @@ func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*obje
 		log.Printf("[DEBUG] Attempting to locate datastore cluster at absolute path %q", name)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return finder.DatastoreCluster(ctx, name)
 }
 
 // Properties is a convenience method that wraps fetching the
 // StoragePod MO from its higher-level object.
-func Properties(pod *object.StoragePod) (*mo.StoragePod, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, pod *object.StoragePod) (*mo.StoragePod, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.StoragePod
 	if err := pod.Properties(ctx, pod.Reference(), nil, &props); err != nil {
@@ -109,9 +108,9 @@ func Properties(pod *object.StoragePod) (*mo.StoragePod, error) {
 
 // Create creates a StoragePod from a supplied folder. The resulting StoragePod
 // is returned.
-func Create(f *object.Folder, name string) (*object.StoragePod, error) {
+func Create(ctx context.Context, f *object.Folder, name string) (*object.StoragePod, error) {
 	log.Printf("[DEBUG] Creating datastore cluster %q", fmt.Sprintf("%s/%s", f.InventoryPath, name))
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	pod, err := f.CreateStoragePod(ctx, name)
 	if err != nil {
@@ -122,39 +121,39 @@ func Create(f *object.Folder, name string) (*object.StoragePod, error) {
 
 // ApplyDRSConfiguration takes a types.StorageDrsConfigSpec and applies it
 // against the specified StoragePod.
-func ApplyDRSConfiguration(client *govmomi.Client, pod *object.StoragePod, spec types.StorageDrsConfigSpec) error {
+func ApplyDRSConfiguration(ctx context.Context, client *govmomi.Client, pod *object.StoragePod, spec types.StorageDrsConfigSpec) error {
 	log.Printf("[DEBUG] Applying storage DRS configuration against datastore cluster %q", pod.InventoryPath)
 	mgr := object.NewStorageResourceManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := mgr.ConfigureStorageDrsForPod(ctx, pod, spec, true)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // Rename renames a StoragePod.
-func Rename(pod *object.StoragePod, name string) error {
+func Rename(ctx context.Context, pod *object.StoragePod, name string) error {
 	log.Printf("[DEBUG] Renaming storage pod %q to %s", pod.InventoryPath, name)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := pod.Rename(ctx, name)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // MoveToFolder is a complex method that moves a StoragePod to a given relative
 // datastore folder path. "Relative" here means relative to a datacenter, which
 // is discovered from the current StoragePod path.
-func MoveToFolder(client *govmomi.Client, pod *object.StoragePod, relative string) error {
-	f, err := folder.DatastoreFolderFromObject(client, pod, relative)
+func MoveToFolder(ctx context.Context, client *govmomi.Client, pod *object.StoragePod, relative string) error {
+	f, err := folder.DatastoreFolderFromObject(ctx, client, pod, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(pod.Reference(), f)
+	return folder.MoveObjectTo(ctx, pod.Reference(), f)
 }
 
 // HasChildren checks to see if a datastore cluster has any child items
@@ -163,25 +162,25 @@ func MoveToFolder(client *govmomi.Client, pod *object.StoragePod, relative strin
 // datastore cluster in vSphere destroys *all* children if at all possible
 // (including removing datastores), so extra verification is necessary to
 // prevent accidental removal.
-func HasChildren(pod *object.StoragePod) (bool, error) {
-	return folder.HasChildren(pod.Folder)
+func HasChildren(ctx context.Context, pod *object.StoragePod) (bool, error) {
+	return folder.HasChildren(ctx, pod.Folder)
 }
 
 // Delete destroys a StoragePod.
-func Delete(pod *object.StoragePod) error {
+func Delete(ctx context.Context, pod *object.StoragePod) error {
 	log.Printf("[DEBUG] Deleting datastore cluster %q", pod.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := pod.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // StorageDRSEnabled checks a StoragePod to see if Storage DRS is enabled.
-func StorageDRSEnabled(pod *object.StoragePod) (bool, error) {
-	props, err := Properties(pod)
+func StorageDRSEnabled(ctx context.Context, pod *object.StoragePod) (bool, error) {
+	props, err := Properties(ctx, pod)
 	if err != nil {
 		return false, err
 	}
@@ -195,6 +194,7 @@ func StorageDRSEnabled(pod *object.StoragePod) (bool, error) {
 // StorageResourceManager API. It mimics our helper in the virtualmachine
 // package in functionality, returning a VM helper object on success.
 func CreateVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	fo *object.Folder,
 	spec types.VirtualMachineConfigSpec,
@@ -204,7 +204,7 @@ func CreateVM(
 	dsPath string,
 	timeout time.Duration,
 ) (*object.VirtualMachine, error) {
-	sdrsEnabled, err := StorageDRSEnabled(pod)
+	sdrsEnabled, err := StorageDRSEnabled(ctx, pod)
 	if err != nil {
 		return nil, err
 	}
@@ -242,30 +242,31 @@ func CreateVM(
 		sps.Host = types.NewReference(host.Reference())
 	}
 
-	placement, err := recommendSDRS(client, sps, timeout)
+	placement, err := recommendSDRS(ctx, client, sps, timeout)
 	if err != nil {
 		return nil, err
 	}
 	// If the parent resource pool is a vApp, we need to create the VM using the
 	// CreateChildVM vApp function instead of directly using SDRS recommendations.
 	if sps.ResourcePool != nil {
-		vc, err := vappcontainer.FromID(client, sps.ResourcePool.Reference().Value)
+		vc, err := vappcontainer.FromID(ctx, client, sps.ResourcePool.Reference().Value)
 		switch {
 		case viapi.IsManagedObjectNotFoundError(err):
 			// This isn't a vApp container, so continue with normal SDRS work flow.
 		case err == nil:
-			return createVAppVMFromSPS(client, placement, spec, sps, vc, dsPath, timeout)
+			return createVAppVMFromSPS(ctx, client, placement, spec, sps, vc, dsPath, timeout)
 		default:
 			return nil, err
 		}
 	}
-	return applySDRS(client, placement, timeout)
+	return applySDRS(ctx, client, placement, timeout)
 }
 
 // CloneVM clones a virtual machine to a datastore cluster via the
 // StorageResourceManager API. It mimics our helper in the virtualmachine
 // package in functionality, returning a VM helper object on success.
 func CloneVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	src *object.VirtualMachine,
 	fo *object.Folder,
@@ -274,7 +275,7 @@ func CloneVM(
 	timeout int,
 	pod *object.StoragePod,
 ) (*object.VirtualMachine, error) {
-	sdrsEnabled, err := StorageDRSEnabled(pod)
+	sdrsEnabled, err := StorageDRSEnabled(ctx, pod)
 	if err != nil {
 		return nil, err
 	}
@@ -299,7 +300,7 @@ func CloneVM(
 		Type: string(types.StoragePlacementSpecPlacementTypeClone),
 	}
 
-	return recommendAndApplySDRS(client, sps, time.Minute*time.Duration(timeout))
+	return recommendAndApplySDRS(ctx, client, sps, time.Minute*time.Duration(timeout))
 }
 
 // ReconfigureVM reconfigures a virtual machine via the StorageResourceManager
@@ -311,12 +312,13 @@ func CloneVM(
 // are necessary, use the regular Reconfigure function in the virtualmachine
 // helper package.
 func ReconfigureVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	vm *object.VirtualMachine,
 	spec types.VirtualMachineConfigSpec,
 	pod *object.StoragePod,
 ) error {
-	sdrsEnabled, err := StorageDRSEnabled(pod)
+	sdrsEnabled, err := StorageDRSEnabled(ctx, pod)
 	if err != nil {
 		return err
 	}
@@ -339,7 +341,7 @@ func ReconfigureVM(
 		ConfigSpec: &spec,
 	}
 
-	_, err = recommendAndApplySDRS(client, sps, provider.DefaultAPITimeout)
+	_, err = recommendAndApplySDRS(ctx, client, sps, provider.DefaultAPITimeout)
 	return err
 }
 
@@ -347,13 +349,14 @@ func ReconfigureVM(
 // StorageResourceManager API. It mimics our helper in the virtualmachine
 // package in functionality.
 func RelocateVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	vm *object.VirtualMachine,
 	spec types.VirtualMachineRelocateSpec,
 	timeout int,
 	pod *object.StoragePod,
 ) error {
-	sdrsEnabled, err := StorageDRSEnabled(pod)
+	sdrsEnabled, err := StorageDRSEnabled(ctx, pod)
 	if err != nil {
 		return err
 	}
@@ -376,26 +379,27 @@ func RelocateVM(
 		Type:         string(types.StoragePlacementSpecPlacementTypeRelocate),
 	}
 
-	_, err = recommendAndApplySDRS(client, sps, time.Minute*time.Duration(timeout))
+	_, err = recommendAndApplySDRS(ctx, client, sps, time.Minute*time.Duration(timeout))
 	return err
 }
 
 func recommendAndApplySDRS(
+	ctx context.Context,
 	client *govmomi.Client,
 	sps types.StoragePlacementSpec,
 	timeout time.Duration,
 ) (*object.VirtualMachine, error) {
-	placement, err := recommendSDRS(client, sps, timeout)
+	placement, err := recommendSDRS(ctx, client, sps, timeout)
 	if err != nil {
 		return nil, err
 	}
-	return applySDRS(client, placement, timeout)
+	return applySDRS(ctx, client, placement, timeout)
 }
 
-func recommendSDRS(client *govmomi.Client, sps types.StoragePlacementSpec, timeout time.Duration) (*types.StoragePlacementResult, error) {
+func recommendSDRS(ctx context.Context, client *govmomi.Client, sps types.StoragePlacementSpec, timeout time.Duration) (*types.StoragePlacementResult, error) {
 	log.Printf("[DEBUG] Acquiring Storage DRS recommendations (type: %q)", sps.Type)
 	srm := object.NewStorageResourceManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	placement, err := srm.RecommendDatastores(ctx, sps)
 	if err != nil {
@@ -408,17 +412,17 @@ func recommendSDRS(client *govmomi.Client, sps types.StoragePlacementSpec, timeo
 	return placement, nil
 }
 
-func applySDRS(client *govmomi.Client, placement *types.StoragePlacementResult, timeout time.Duration) (*object.VirtualMachine, error) {
+func applySDRS(ctx context.Context, client *govmomi.Client, placement *types.StoragePlacementResult, timeout time.Duration) (*object.VirtualMachine, error) {
 	recommendation := getTopRecommendation(placement.Recommendations)
 	log.Printf("[DEBUG] Applying Storage DRS recommendations (type: %q)", recommendation.Type)
 	srm := object.NewStorageResourceManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	task, err := srm.ApplyStorageDrsRecommendation(ctx, []string{recommendation.Key})
 	if err != nil {
 		return nil, err
 	}
-	result, err := task.WaitForResultEx(ctx, nil)
+	result, err := viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		// Provide a friendly error message for timeouts
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
@@ -434,7 +438,7 @@ func applySDRS(client *govmomi.Client, placement *types.StoragePlacementResult,
 	vmRef := result.Result.(types.ApplyStorageRecommendationResult).Vm
 	if vmRef != nil {
 		log.Printf("[DEBUG] Storage DRS operation returned virtual machine reference: %s", vmRef)
-		vm, err = virtualmachine.FromMOID(client, vmRef.Value)
+		vm, err = virtualmachine.FromMOID(ctx, client, vmRef.Value)
 		if err != nil {
 			return nil, err
 		}
@@ -454,6 +458,7 @@ func getTopRecommendation(recommendations []types.ClusterRecommendation) types.C
 }
 
 func createVAppVMFromSPS(
+	ctx context.Context,
 	client *govmomi.Client,
 	placement *types.StoragePlacementResult,
 	spec types.VirtualMachineConfigSpec,
@@ -462,7 +467,7 @@ func createVAppVMFromSPS(
 	dsPath string,
 	timeout time.Duration,
 ) (*object.VirtualMachine, error) {
-	ds, err := datastore.FromID(client, placement.Recommendations[0].Action[0].(*types.StoragePlacementAction).Destination.Reference().Value)
+	ds, err := datastore.FromID(ctx, client, placement.Recommendations[0].Action[0].(*types.StoragePlacementAction).Destination.Reference().Value)
 	if err != nil {
 		return nil, err
 	}
@@ -470,11 +475,11 @@ func createVAppVMFromSPS(
 		VmPathName: BuildVMPathName(ds.Name(), dsPath),
 	}
 	var f *object.Folder
-	f, err = folder.FromID(client, sps.Folder.Reference().Value)
+	f, err = folder.FromID(ctx, client, sps.Folder.Reference().Value)
 	if err != nil {
 		return nil, err
 	}
-	return virtualmachine.Create(client, f, spec, vc.ResourcePool, nil, timeout)
+	return virtualmachine.Create(ctx, client, f, spec, vc.ResourcePool, nil, timeout)
 }
 
 // HasDiskCreationOperations is an exported function that checks a list of
@@ -532,8 +537,8 @@ func expandVMPodConfigForPlacement(dc []types.BaseVirtualDeviceConfigSpec, pod *
 //
 // This is a pretty basic operation that checks that the parent of the
 // datastore is the StoragePod.
-func IsMember(pod *object.StoragePod, ds *object.Datastore) (bool, error) {
-	dprops, err := datastore.Properties(ds)
+func IsMember(ctx context.Context, pod *object.StoragePod, ds *object.Datastore) (bool, error) {
+	dprops, err := datastore.Properties(ctx, ds)
 	if err != nil {
 		return false, fmt.Errorf("error getting properties for datastore %q: %s", ds.Name(), err)
 	}
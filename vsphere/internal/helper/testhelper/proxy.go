@@ -0,0 +1,75 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package testhelper
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+)
+
+// Proxy is an HTTP proxy that tunnels CONNECT requests to a fixed target
+// address, regardless of the requested address, so that tests can connect to
+// a local server through host names that do not resolve.
+type Proxy struct {
+	Server *httptest.Server
+	URL    *url.URL
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// StartProxy starts a proxy that tunnels CONNECT requests to target.
+func StartProxy(target string) *Proxy {
+	p := new(Proxy)
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		p.mu.Lock()
+		p.requests = append(p.requests, r.Host)
+		p.mu.Unlock()
+
+		backend, err := net.Dial("tcp", target)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			_ = backend.Close()
+			return
+		}
+		if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+			_ = backend.Close()
+			_ = conn.Close()
+			return
+		}
+		go func() {
+			_, _ = io.Copy(backend, conn)
+			_ = backend.Close()
+		}()
+		_, _ = io.Copy(conn, backend)
+		_ = conn.Close()
+	}))
+	p.URL, _ = url.Parse(p.Server.URL)
+	return p
+}
+
+// Requests returns the addresses requested through the proxy.
+func (p *Proxy) Requests() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.requests...)
+}
+
+// Close stops the proxy.
+func (p *Proxy) Close() {
+	p.Server.Close()
+}
@@ -0,0 +1,138 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package testhelper
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/vmware/govmomi/simulator"
+
+	// Register the REST (tags, content library), PBM, vSAN, lookup service and
+	// STS endpoints with the simulator so that every client built by the
+	// provider can connect.
+	_ "github.com/vmware/govmomi/lookup/simulator"
+	_ "github.com/vmware/govmomi/pbm/simulator"
+	_ "github.com/vmware/govmomi/sts/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vsan/simulator"
+)
+
+// SimulatorEnvVar is the environment variable that switches the acceptance
+// tests into offline mode, backed by an in-process vcsim simulator instead of
+// a live vCenter.
+const SimulatorEnvVar = "VSPHERE_USE_SIMULATOR"
+
+// Inventory names created by the simulator model returned by SimulatorModel.
+// The simulator derives names from the parent object names, so these are
+// stable for a given model.
+const (
+	SimDatacenter       = "DC0"
+	SimCluster          = "DC0_C0"
+	SimClusterHost1     = "DC0_C0_H0"
+	SimClusterHost2     = "DC0_C0_H1"
+	SimClusterHost3     = "DC0_C0_H2"
+	SimStandaloneHost   = "DC0_H0"
+	SimDatastore        = "LocalDS_0"
+	SimDatastore2       = "LocalDS_1"
+	SimDatastoreCluster = "DC0_POD0"
+	SimPortGroup        = "DC0_DVPG0"
+	SimVMNetwork        = "VM Network"
+	SimResourcePool     = "DC0_C0_RP1"
+	SimVirtualMachine   = "DC0_C0_RP1_VM0"
+	SimTemplate         = "DC0_C0_RP1_VM1"
+)
+
+// SimulatorEnabled returns true if the acceptance tests should run against
+// an in-process simulator.
+func SimulatorEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(SimulatorEnvVar))
+	return enabled
+}
+
+// SimulatorModel returns the vCenter inventory model used for offline
+// acceptance tests. It seeds a single datacenter with a DRS cluster of three
+// hosts, a standalone host, two shared datastores, a datastore cluster, two
+// distributed port groups, a child resource pool and a couple of virtual
+// machines.
+func SimulatorModel() *simulator.Model {
+	model := simulator.VPX()
+	model.Datacenter = 1
+	model.Cluster = 1
+	model.ClusterHost = 3
+	model.Host = 1
+	model.Datastore = 2
+	model.Portgroup = 2
+	model.Pool = 1
+	model.Pod = 1
+	model.Machine = 2
+	return model
+}
+
+// Simulator wraps a running simulator model and its server.
+type Simulator struct {
+	Model  *simulator.Model
+	Server *simulator.Server
+}
+
+// StartSimulator creates the inventory described by SimulatorModel and
+// starts a TLS server for it. Use SetEnv to point the provider and the
+// acceptance test configurations at the simulator.
+func StartSimulator() (*Simulator, error) {
+	model := SimulatorModel()
+	if err := model.Create(); err != nil {
+		return nil, fmt.Errorf("error creating simulator inventory: %s", err)
+	}
+	// The provider always connects over HTTPS.
+	model.Service.TLS = new(tls.Config)
+	model.Service.RegisterEndpoints = true
+	server := model.Service.NewServer()
+
+	log.Printf("[DEBUG] vSphere simulator listening on %s", server.URL.Host)
+	return &Simulator{
+		Model:  model,
+		Server: server,
+	}, nil
+}
+
+// Close stops the simulator server and removes the inventory along with any
+// files it created on the local datastores.
+func (s *Simulator) Close() {
+	s.Server.Close()
+	s.Model.Remove()
+}
+
+// SetEnv exports the connection details and the seeded inventory names in
+// the environment variables read by the provider and the acceptance tests.
+func (s *Simulator) SetEnv() error {
+	password, _ := s.Server.URL.User.Password()
+	env := map[string]string{
+		"VSPHERE_SERVER":               s.Server.URL.Host,
+		"VSPHERE_USER":                 s.Server.URL.User.Username(),
+		"VSPHERE_PASSWORD":             password,
+		"VSPHERE_ALLOW_UNVERIFIED_SSL": "true",
+		"VSPHERE_PERSIST_SESSION":      "false",
+		"TF_VAR_VSPHERE_DATACENTER":    SimDatacenter,
+		"TF_VAR_VSPHERE_CLUSTER":       SimCluster,
+		"TF_VAR_VSPHERE_ESXI1":         SimClusterHost1,
+		"TF_VAR_VSPHERE_ESXI2":         SimClusterHost2,
+		"TF_VAR_VSPHERE_ESXI3":         SimClusterHost3,
+		"TF_VAR_VSPHERE_ESXI4":         SimStandaloneHost,
+		"TF_VAR_VSPHERE_NFS_DS_NAME":   SimDatastore,
+		"TF_VAR_VSPHERE_DS_CLUSTER1":   SimDatastoreCluster,
+		"TF_VAR_VSPHERE_PG_NAME":       SimPortGroup,
+		"TF_VAR_VSPHERE_RESOURCE_POOL": SimResourcePool,
+		"TF_VAR_VSPHERE_TEMPLATE":      SimTemplate,
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("error setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+const (
+	// DefaultMinBackoff is the delay before the first retry of a request.
+	DefaultMinBackoff = time.Second
+
+	// DefaultMaxBackoff is the maximum delay between retries of a request.
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// Options describe the limits and retries applied to API requests.
+type Options struct {
+	// MaxConcurrent is the maximum number of requests in flight. Unlimited
+	// when 0.
+	MaxConcurrent int
+
+	// RequestsPerSecond is the maximum rate at which requests are sent.
+	// Unlimited when 0.
+	RequestsPerSecond float64
+
+	// MaxRetries is the number of times a request is sent again after a
+	// transient fault of the request itself. See viapi.IsRetryableError.
+	// Faults of the tasks started by a request are reported when the task
+	// completes, and are not retried.
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between
+	// retries. DefaultMinBackoff and DefaultMaxBackoff are used when 0.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Throttle limits the concurrency and rate of the API requests of all of the
+// clients that it wraps, and retries requests that fail with a transient
+// fault.
+type Throttle struct {
+	options  Options
+	sem      chan struct{}
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// New returns a Throttle for the options.
+func New(o Options) *Throttle {
+	t := &Throttle{options: o}
+	if o.MaxConcurrent > 0 {
+		t.sem = make(chan struct{}, o.MaxConcurrent)
+	}
+	if o.RequestsPerSecond > 0 {
+		t.interval = time.Duration(float64(time.Second) / o.RequestsPerSecond)
+	}
+	if t.options.MinBackoff == 0 {
+		t.options.MinBackoff = DefaultMinBackoff
+	}
+	if t.options.MaxBackoff == 0 {
+		t.options.MaxBackoff = DefaultMaxBackoff
+	}
+	return t
+}
+
+// acquire waits for a request slot, unless slot is false, and for the rate
+// budget, and returns a function that releases the slot.
+func (t *Throttle) acquire(ctx context.Context, slot bool) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sem := t.sem
+	if !slot {
+		sem = nil
+	}
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if sem != nil {
+			<-sem
+		}
+	}
+	if t.interval > 0 {
+		t.mu.Lock()
+		now := time.Now()
+		if t.next.Before(now) {
+			t.next = now
+		}
+		wait := t.next.Sub(now)
+		t.next = t.next.Add(t.interval)
+		t.mu.Unlock()
+		if err := sleep(ctx, wait); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// backoff returns the delay before retry number attempt, starting at 0. The
+// delay doubles with every attempt, with jitter so that concurrent requests
+// do not retry in lockstep.
+func (t *Throttle) backoff(attempt int) time.Duration {
+	d := t.options.MinBackoff << attempt
+	if d > t.options.MaxBackoff || d <= 0 {
+		d = t.options.MaxBackoff
+	}
+	return d/2 + rand.N(d/2+1) //nolint (gosec G404)
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loginKey marks the context of the requests sent to log in again, which are
+// not re-authenticated themselves.
+type loginKey struct{}
+
+// Session logs in again when a session has expired. Requests that fail
+// concurrently because of the same expired session log in only once.
+type Session struct {
+	login func(ctx context.Context) error
+
+	// mu serializes logins. The generation is read without it, as the
+	// requests sent to log in read it while mu is held.
+	mu         sync.Mutex
+	generation atomic.Uint64
+}
+
+// NewSession returns a Session that logs in with login.
+func NewSession(login func(ctx context.Context) error) *Session {
+	return &Session{login: login}
+}
+
+// current returns the generation of the session, which is incremented on
+// every login.
+func (s *Session) current() uint64 {
+	return s.generation.Load()
+}
+
+// renew logs in again, unless the session has been renewed since generation.
+func (s *Session) renew(ctx context.Context, generation uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation.Load() != generation {
+		return nil
+	}
+	if err := s.login(context.WithValue(ctx, loginKey{}, true)); err != nil {
+		return err
+	}
+	s.generation.Add(1)
+	return nil
+}
+
+// canRenew returns true if requests with ctx can log in again.
+func (s *Session) canRenew(ctx context.Context) bool {
+	return s != nil && ctx.Value(loginKey{}) == nil
+}
+
+// SOAP wraps a SOAP round tripper. When session is set, requests that fail
+// because the session has expired are sent again after logging in.
+func (t *Throttle) SOAP(rt soap.RoundTripper, session *Session) soap.RoundTripper {
+	return &soapRoundTripper{
+		rt:       rt,
+		throttle: t,
+		session:  session,
+	}
+}
+
+type soapRoundTripper struct {
+	rt       soap.RoundTripper
+	throttle *Throttle
+	session  *Session
+}
+
+// RoundTrip implements soap.RoundTripper.
+func (r *soapRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	renewed := false
+	for attempt := 0; ; {
+		generation := uint64(0)
+		if r.session != nil {
+			generation = r.session.current()
+		}
+		release, err := r.throttle.acquire(ctx, !longPoll(req))
+		if err != nil {
+			return err
+		}
+		// Decode every attempt into a new response, as the fault of a failed
+		// attempt is not cleared by a successful one.
+		attemptRes := newResponse(res)
+		err = r.rt.RoundTrip(ctx, req, attemptRes)
+		release()
+		if attemptRes != res {
+			reflect.ValueOf(res).Elem().Set(reflect.ValueOf(attemptRes).Elem())
+		}
+
+		switch {
+		case err == nil:
+			return nil
+		case !renewed && r.session.canRenew(ctx) && viapi.IsNotAuthenticatedError(err):
+			log.Printf("[DEBUG] Session expired, logging in again: %s", err)
+			if lerr := r.session.renew(ctx, generation); lerr != nil {
+				log.Printf("[WARN] Error logging in again: %s", lerr)
+				return err
+			}
+			renewed = true
+		case attempt < r.throttle.options.MaxRetries && viapi.IsRetryableError(err):
+			d := r.throttle.backoff(attempt)
+			attempt++
+			log.Printf("[DEBUG] Retrying request in %s (attempt %d of %d): %s", d, attempt, r.throttle.options.MaxRetries, err)
+			if serr := sleep(ctx, d); serr != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+}
+
+// longPoll returns true if req waits for updates of the property collector.
+// These requests are held by the server until there are updates, which can
+// take as long as the tasks they wait for, so they do not take a request slot.
+// Otherwise tasks waited for by as many resources as there are slots would
+// block every other request.
+func longPoll(req soap.HasFault) bool {
+	switch req.(type) {
+	case *methods.WaitForUpdatesBody, *methods.WaitForUpdatesExBody, *methods.CancelWaitForUpdatesBody:
+		return true
+	}
+	return false
+}
+
+// newResponse returns a new value of the type of res, or res if it is not a
+// pointer to a struct.
+func newResponse(res soap.HasFault) soap.HasFault {
+	v := reflect.ValueOf(res)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return res
+	}
+	if n, ok := reflect.New(v.Elem().Type()).Interface().(soap.HasFault); ok {
+		return n
+	}
+	return res
+}
+
+// HTTP wraps the HTTP round tripper of a REST client. When session is set,
+// requests with a 401 response are sent again after logging in, with the
+// new session set by authenticate.
+func (t *Throttle) HTTP(rt http.RoundTripper, session *Session, authenticate func(req *http.Request)) http.RoundTripper {
+	return &httpRoundTripper{
+		rt:           rt,
+		throttle:     t,
+		session:      session,
+		authenticate: authenticate,
+	}
+}
+
+type httpRoundTripper struct {
+	rt           http.RoundTripper
+	throttle     *Throttle
+	session      *Session
+	authenticate func(req *http.Request)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *httpRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	req = emptyBody(req)
+	renewed := false
+	for attempt := 0; ; {
+		generation := uint64(0)
+		if r.session != nil {
+			generation = r.session.current()
+		}
+		release, err := r.throttle.acquire(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.rt.RoundTrip(req)
+		release()
+		if err != nil {
+			return nil, err
+		}
+
+		var retry bool
+		switch {
+		case res.StatusCode == http.StatusUnauthorized && !renewed && r.session.canRenew(ctx):
+			log.Printf("[DEBUG] Session expired, logging in again: %s %s: %s", req.Method, req.URL.Path, res.Status)
+			if lerr := r.session.renew(ctx, generation); lerr != nil {
+				log.Printf("[WARN] Error logging in again: %s", lerr)
+				return res, nil
+			}
+			renewed = true
+			retry = true
+		case attempt < r.throttle.options.MaxRetries && viapi.IsRetryableStatus(res.StatusCode):
+			d := retryAfter(res)
+			if d == 0 || d > r.throttle.options.MaxBackoff {
+				d = r.throttle.backoff(attempt)
+			}
+			attempt++
+			log.Printf("[DEBUG] Retrying request in %s (attempt %d of %d): %s %s: %s", d, attempt, r.throttle.options.MaxRetries, req.Method, req.URL.Path, res.Status)
+			if serr := sleep(ctx, d); serr != nil {
+				return res, nil
+			}
+			retry = true
+		}
+		if !retry {
+			return res, nil
+		}
+
+		next, err := rewind(req)
+		if err != nil || next == nil {
+			// The request body cannot be sent again.
+			return res, nil
+		}
+		_ = res.Body.Close()
+		if renewed && r.authenticate != nil {
+			r.authenticate(next)
+		}
+		req = next
+	}
+}
+
+// emptyBody returns a copy of req without a body if its body, of unknown
+// length, is empty. The REST client sends an empty reader with requests that
+// have no body, which would otherwise not be sent again.
+func emptyBody(req *http.Request) *http.Request {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil || req.ContentLength != 0 {
+		return req
+	}
+	var b [1]byte
+	n, err := io.ReadFull(req.Body, b[:])
+	next := req.Clone(req.Context())
+	if n == 0 && err == io.EOF {
+		_ = req.Body.Close()
+		next.Body = http.NoBody
+		return next
+	}
+	next.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b[:n]), req.Body), req.Body}
+	return next
+}
+
+// rewind returns a copy of req with a new body, or nil if the body cannot be
+// read again.
+func rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// retryAfter returns the delay requested by the Retry-After header of res, in
+// seconds, or 0.
+func retryAfter(res *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
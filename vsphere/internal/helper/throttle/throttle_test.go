@@ -0,0 +1,300 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package throttle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// testFault returns a SOAP fault error for fault.
+func testFault(fault types.AnyType) error {
+	f := &soap.Fault{String: "fault"}
+	f.Detail.Fault = fault
+	return soap.WrapSoapFault(f)
+}
+
+// testRoundTripper fails the first len(errs) requests with errs, setting the
+// fault on the response, and succeeds afterwards.
+type testRoundTripper struct {
+	mu       sync.Mutex
+	errs     []error
+	requests int
+	inFlight int32
+	maxSeen  int32
+	delay    time.Duration
+}
+
+func (rt *testRoundTripper) RoundTrip(_ context.Context, _, res soap.HasFault) error {
+	n := atomic.AddInt32(&rt.inFlight, 1)
+	defer atomic.AddInt32(&rt.inFlight, -1)
+	for {
+		seen := atomic.LoadInt32(&rt.maxSeen)
+		if n <= seen || atomic.CompareAndSwapInt32(&rt.maxSeen, seen, n) {
+			break
+		}
+	}
+	time.Sleep(rt.delay)
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.requests++
+	if len(rt.errs) == 0 {
+		return nil
+	}
+	err := rt.errs[0]
+	rt.errs = rt.errs[1:]
+	if body, ok := res.(*methods.RetrieveServiceContentBody); ok && soap.IsSoapFault(err) {
+		body.Fault_ = soap.ToSoapFault(err)
+	}
+	return err
+}
+
+func testThrottle(o Options) *Throttle {
+	o.MinBackoff = time.Millisecond
+	o.MaxBackoff = 5 * time.Millisecond
+	return New(o)
+}
+
+func TestSOAPRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		errs     []error
+		retries  int
+		requests int
+		ok       bool
+	}{
+		{"task in progress", []error{testFault(types.TaskInProgress{}), testFault(types.TaskInProgress{})}, 3, 3, true},
+		{"resource in use", []error{testFault(types.ResourceInUse{})}, 3, 2, true},
+		{"service unavailable", []error{&url.Error{Op: "POST", URL: "https://vcenter/sdk", Err: &soapStatusError{"503 Service Unavailable"}}}, 3, 2, true},
+		{"retries exhausted", []error{testFault(types.TaskInProgress{}), testFault(types.TaskInProgress{})}, 1, 2, false},
+		{"not retryable", []error{testFault(types.InvalidArgument{})}, 3, 1, false},
+		{"network error", []error{errors.New("connection reset by peer")}, 3, 1, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := &testRoundTripper{errs: tc.errs}
+			res := new(methods.RetrieveServiceContentBody)
+			err := testThrottle(Options{MaxRetries: tc.retries}).SOAP(rt, nil).RoundTrip(context.Background(), nil, res)
+			if tc.ok && (err != nil || res.Fault() != nil) {
+				t.Fatalf("expected success, got %v, fault %v", err, res.Fault())
+			}
+			if !tc.ok && err == nil {
+				t.Fatal("expected error")
+			}
+			if rt.requests != tc.requests {
+				t.Fatalf("expected %d requests, got %d", tc.requests, rt.requests)
+			}
+		})
+	}
+}
+
+// soapStatusError is an error like the one the SOAP client wraps in a
+// *url.Error for responses other than 200 and 500.
+type soapStatusError struct {
+	status string
+}
+
+func (e *soapStatusError) Error() string {
+	return e.status
+}
+
+func TestSOAPRelogin(t *testing.T) {
+	const concurrent = 5
+	rt := &testRoundTripper{delay: 10 * time.Millisecond}
+	for i := 0; i < concurrent; i++ {
+		rt.errs = append(rt.errs, testFault(types.NotAuthenticated{}))
+	}
+	var logins int32
+	session := NewSession(func(ctx context.Context) error {
+		if ctx.Value(loginKey{}) == nil {
+			t.Error("expected login context")
+		}
+		atomic.AddInt32(&logins, 1)
+		return nil
+	})
+	srt := testThrottle(Options{}).SOAP(rt, session)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrent)
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- srt.RoundTrip(context.Background(), nil, new(methods.RetrieveServiceContentBody))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if logins != 1 {
+		t.Fatalf("expected 1 login, got %d", logins)
+	}
+
+	// A failed login returns the original fault.
+	rt.errs = []error{testFault(types.NotAuthenticated{})}
+	session.login = func(context.Context) error { return errors.New("invalid login") }
+	err := srt.RoundTrip(context.Background(), nil, new(methods.RetrieveServiceContentBody))
+	if err == nil || !strings.Contains(err.Error(), "fault") {
+		t.Fatalf("expected NotAuthenticated fault, got %v", err)
+	}
+}
+
+func TestSOAPLimits(t *testing.T) {
+	rt := &testRoundTripper{delay: 20 * time.Millisecond}
+	srt := testThrottle(Options{MaxConcurrent: 2, RequestsPerSecond: 100}).SOAP(rt, nil)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = srt.RoundTrip(context.Background(), nil, new(methods.RetrieveServiceContentBody))
+		}()
+	}
+	wg.Wait()
+	if rt.maxSeen > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", rt.maxSeen)
+	}
+	// Six requests at 100 per second take at least 50ms.
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected requests to be rate limited, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := srt.RoundTrip(ctx, nil, new(methods.RetrieveServiceContentBody)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+}
+
+// blockingRoundTripper holds WaitForUpdatesEx requests until unblock is closed.
+type blockingRoundTripper struct {
+	unblock chan struct{}
+}
+
+func (rt *blockingRoundTripper) RoundTrip(ctx context.Context, req, _ soap.HasFault) error {
+	if _, ok := req.(*methods.WaitForUpdatesExBody); ok {
+		select {
+		case <-rt.unblock:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func TestSOAPLimits_longPoll(t *testing.T) {
+	rt := &blockingRoundTripper{unblock: make(chan struct{})}
+	srt := testThrottle(Options{MaxConcurrent: 1}).SOAP(rt, nil)
+
+	done := make(chan error)
+	go func() {
+		done <- srt.RoundTrip(context.Background(), new(methods.WaitForUpdatesExBody), new(methods.WaitForUpdatesExBody))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srt.RoundTrip(ctx, new(methods.RetrieveServiceContentBody), new(methods.RetrieveServiceContentBody)); err != nil {
+		t.Fatalf("expected request not to wait for WaitForUpdatesEx, got %s", err)
+	}
+	close(rt.unblock)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	var requests int32
+	var session atomic.Value
+	session.Store("expired")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case string(body) != "payload":
+			w.WriteHeader(http.StatusBadRequest)
+		case n == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("vmware-api-session-id") != "renewed":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	s := NewSession(func(context.Context) error {
+		session.Store("renewed")
+		return nil
+	})
+	client := &http.Client{
+		Transport: testThrottle(Options{MaxRetries: 3}).HTTP(http.DefaultTransport, s, func(req *http.Request) {
+			req.Header.Set("vmware-api-session-id", session.Load().(string))
+		}),
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("vmware-api-session-id", "expired")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %s", res.Status)
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestHTTP_emptyBody(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: testThrottle(Options{}).HTTP(http.DefaultTransport, NewSession(func(context.Context) error { return nil }), nil),
+	}
+	// An empty body of unknown length, as sent by the REST client.
+	req, err := http.NewRequest(http.MethodGet, server.URL, io.MultiReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK || requests != 2 {
+		t.Fatalf("expected 200 OK after 2 requests, got %s after %d", res.Status, requests)
+	}
+}
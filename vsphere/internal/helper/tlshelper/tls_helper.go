@@ -0,0 +1,223 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package tlshelper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/proxyhelper"
+)
+
+// Options describe how the provider verifies the certificates of the servers
+// it connects to.
+type Options struct {
+	// Insecure skips certificate verification, except for pinned thumbprints.
+	Insecure bool
+
+	// CAFile is a list of paths to PEM encoded certificate authority bundles,
+	// separated by the OS path list separator.
+	CAFile string
+
+	// CAPEM is a PEM encoded certificate authority bundle.
+	CAPEM string
+
+	// Thumbprints pins the certificates of hosts to their SHA-1 or SHA-256
+	// thumbprints. Keys are host names or addresses, optionally with a port.
+	Thumbprints map[string]string
+
+	// Proxy is the proxy that connections are made through, if any.
+	Proxy *proxyhelper.Options
+}
+
+// RootCAs returns the certificate authorities in CAFile and CAPEM, or nil if
+// neither is set, in which case the system certificate authorities are used.
+func (o *Options) RootCAs() (*x509.CertPool, error) {
+	if o == nil || (o.CAFile == "" && o.CAPEM == "") {
+		return nil, nil
+	}
+	pool := x509.NewCertPool()
+	for _, name := range filepath.SplitList(o.CAFile) {
+		pem, err := os.ReadFile(filepath.Clean(name))
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %s", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", name)
+		}
+	}
+	if o.CAPEM != "" && !pool.AppendCertsFromPEM([]byte(o.CAPEM)) {
+		return nil, errors.New("no certificates found in CA PEM")
+	}
+	return pool, nil
+}
+
+// ConfigureSOAP applies the options to a SOAP client, and to the clients
+// derived from it through NewServiceClient, such as the REST, PBM, vSAN, and
+// SSO clients. Hosts with a pinned thumbprint are accepted even if their
+// certificate is not trusted, and rejected if it does not match.
+func (o *Options) ConfigureSOAP(sc *soap.Client) error {
+	if o == nil {
+		return nil
+	}
+	pool, err := o.RootCAs()
+	if err != nil {
+		return err
+	}
+	t := sc.DefaultTransport()
+	if pool != nil {
+		t.TLSClientConfig.RootCAs = pool
+	}
+	if o.Proxy.Enabled() {
+		// The thumbprints of the SOAP client include those of the ESXi hosts
+		// of NFC leases, which are added as leases are acquired.
+		t.Proxy = o.Proxy.Proxy
+		t.DialContext = o.Proxy.DialContext
+		t.DialTLSContext = dialTLS(t, o.Proxy.DialContext, sc.Thumbprint)
+	}
+	if len(o.Thumbprints) > 0 {
+		for host, thumbprint := range o.Thumbprints {
+			sc.SetThumbprint(host, thumbprint)
+		}
+		t.DialTLSContext = o.pinDialer(t.DialTLSContext)
+	}
+	return nil
+}
+
+// HTTPClient returns an HTTP client that verifies certificates with the
+// options and connects through the proxy, such as for the download of remote
+// OVF and OVA files. Verification is skipped when insecure is set, except for
+// pinned thumbprints. The Insecure option is not used, as the servers are not
+// vSphere endpoints.
+func (o *Options) HTTPClient(insecure bool) (*http.Client, error) {
+	pool, err := o.RootCAs()
+	if err != nil {
+		return nil, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: insecure, //nolint (gosec G402)
+	}
+	if o == nil {
+		return &http.Client{Transport: t}, nil
+	}
+	if o.Proxy.Enabled() {
+		t.Proxy = o.Proxy.Proxy
+		t.DialContext = o.Proxy.DialContext
+	}
+	if o.Proxy.Enabled() || len(o.Thumbprints) > 0 {
+		t.DialTLSContext = o.pinDialer(dialTLS(t, t.DialContext, o.thumbprint))
+	}
+	return &http.Client{Transport: t}, nil
+}
+
+// dialTLS returns a TLS dialer that connects with dial and verifies
+// certificates with the TLS configuration of t. Like the dialer of the SOAP
+// client, a certificate that is not trusted is accepted if it matches the
+// thumbprint of the host.
+func dialTLS(t *http.Transport, dial func(ctx context.Context, network, addr string) (net.Conn, error), thumbprint func(addr string) string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := handshake(ctx, dial, network, addr, t.TLSClientConfig)
+		if err == nil || !soap.IsCertificateUntrusted(err) {
+			return conn, err
+		}
+		tp := thumbprint(addr)
+		if tp == "" {
+			return nil, err
+		}
+		config := t.TLSClientConfig.Clone()
+		config.InsecureSkipVerify = true //nolint (gosec G402)
+		conn, err = handshake(ctx, dial, network, addr, config)
+		if err != nil {
+			return nil, err
+		}
+		if !ThumbprintMatches(tp, conn.ConnectionState().PeerCertificates[0]) {
+			_ = conn.Close()
+			return nil, fmt.Errorf("host %q thumbprint does not match %q", addr, tp)
+		}
+		return conn, nil
+	}
+}
+
+// handshake connects to addr with dial and performs the TLS handshake.
+func handshake(ctx context.Context, dial func(ctx context.Context, network, addr string) (net.Conn, error), network, addr string, config *tls.Config) (*tls.Conn, error) {
+	raw, err := dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	config = config.Clone()
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		config.ServerName = host
+	}
+	conn := tls.Client(raw, config)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		_ = raw.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// pinDialer wraps a TLS dialer, rejecting connections to hosts with a pinned
+// thumbprint that does not match their certificate.
+func (o *Options) pinDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		thumbprint := o.thumbprint(addr)
+		if thumbprint == "" {
+			return conn, nil
+		}
+		tc, ok := conn.(*tls.Conn)
+		if !ok || len(tc.ConnectionState().PeerCertificates) == 0 {
+			_ = conn.Close()
+			return nil, fmt.Errorf("host %q did not present a certificate", addr)
+		}
+		if !ThumbprintMatches(thumbprint, tc.ConnectionState().PeerCertificates[0]) {
+			_ = conn.Close()
+			return nil, fmt.Errorf("host %q thumbprint does not match %q", addr, thumbprint)
+		}
+		return conn, nil
+	}
+}
+
+// thumbprint returns the pinned thumbprint for addr, looked up by host and
+// port, then by host.
+func (o *Options) thumbprint(addr string) string {
+	if t, ok := o.Thumbprints[addr]; ok {
+		return t
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return o.Thumbprints[addr]
+	}
+	if port == "443" {
+		if t, ok := o.Thumbprints[host]; ok {
+			return t
+		}
+	}
+	return o.Thumbprints[host]
+}
+
+// ThumbprintMatches returns true if thumbprint is the SHA-1 or SHA-256
+// thumbprint of cert. The comparison is not case sensitive.
+func ThumbprintMatches(thumbprint string, cert *x509.Certificate) bool {
+	return strings.EqualFold(thumbprint, soap.ThumbprintSHA1(cert)) || strings.EqualFold(thumbprint, soap.ThumbprintSHA256(cert))
+}
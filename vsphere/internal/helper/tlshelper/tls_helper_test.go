@@ -0,0 +1,82 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package tlshelper
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func TestHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cert := server.Certificate()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caPEM), 0600); err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		options  *Options
+		insecure bool
+		ok       bool
+	}{
+		{name: "default", options: nil, ok: false},
+		{name: "insecure", options: nil, insecure: true, ok: true},
+		{name: "ca file", options: &Options{CAFile: caFile}, ok: true},
+		{name: "ca pem", options: &Options{CAPEM: caPEM}, ok: true},
+		{name: "sha1 thumbprint", options: &Options{Thumbprints: map[string]string{u.Host: soap.ThumbprintSHA1(cert)}}, ok: true},
+		{name: "sha256 thumbprint by host", options: &Options{Thumbprints: map[string]string{u.Hostname(): soap.ThumbprintSHA256(cert)}}, ok: true},
+		{name: "wrong thumbprint", options: &Options{CAPEM: caPEM, Thumbprints: map[string]string{u.Host: "AA:BB"}}, ok: false},
+		{name: "wrong thumbprint insecure", options: &Options{Thumbprints: map[string]string{u.Host: "AA:BB"}}, insecure: true, ok: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := tc.options.HTTPClient(tc.insecure)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := client.Get(server.URL)
+			if err == nil {
+				_ = res.Body.Close()
+			}
+			if tc.ok && err != nil {
+				t.Fatalf("expected request to succeed, got %s", err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatal("expected request to fail")
+			}
+		})
+	}
+}
+
+func TestRootCAs(t *testing.T) {
+	if _, err := (&Options{CAPEM: "not a certificate"}).RootCAs(); err == nil {
+		t.Fatal("expected error for invalid CA PEM")
+	}
+	if _, err := (&Options{CAFile: filepath.Join(t.TempDir(), "missing.pem")}).RootCAs(); err == nil {
+		t.Fatal("expected error for missing CA file")
+	}
+	pool, err := (&Options{}).RootCAs()
+	if err != nil || pool != nil {
+		t.Fatalf("expected system certificate authorities, got %v, %v", pool, err)
+	}
+}
@@ -17,10 +17,10 @@ import (
 const VM = "VirtualMachine"
 const DISTRIBUTEDVIRTUALSWITCH = "VmwareDistributedVirtualSwitch"
 
-func GetMoid(client *govmomi.Client, entityType string, id string) (string, error) {
+func GetMoid(ctx context.Context, client *govmomi.Client, entityType string, id string) (string, error) {
 	switch entityType {
 	case VM:
-		vm, err := virtualmachine.FromUUID(client, id)
+		vm, err := virtualmachine.FromUUID(ctx, client, id)
 		if err != nil {
 			log.Printf("unable to find VM object with uuid:%s, error %s,treating given id as managed object id", id, err)
 			return id, nil
@@ -32,7 +32,7 @@ func GetMoid(client *govmomi.Client, entityType string, id string) (string, erro
 			This: dvsm,
 			Uuid: id,
 		}
-		resp, err := methods.QueryDvsByUuid(context.TODO(), client, req)
+		resp, err := methods.QueryDvsByUuid(ctx, client, req)
 		if err != nil {
 			log.Printf("unable to find DVS object with uuid:%s, error %s, treating given id as managed object id", id, err)
 			return id, nil
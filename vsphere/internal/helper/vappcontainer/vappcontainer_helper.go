@@ -14,13 +14,14 @@ import (
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // FromPath returns a VirtualApp via its supplied path.
-func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*object.VirtualApp, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (*object.VirtualApp, error) {
 	finder := find.NewFinder(client.Client, false)
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	if dc != nil {
 		finder.SetDatacenter(dc)
@@ -29,7 +30,7 @@ func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*obje
 }
 
 // FromID locates a VirtualApp by its managed object reference ID.
-func FromID(client *govmomi.Client, id string) (*object.VirtualApp, error) {
+func FromID(ctx context.Context, client *govmomi.Client, id string) (*object.VirtualApp, error) {
 	log.Printf("[DEBUG] Locating vApp container with ID %s", id)
 	finder := find.NewFinder(client.Client, false)
 
@@ -38,7 +39,7 @@ func FromID(client *govmomi.Client, id string) (*object.VirtualApp, error) {
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	obj, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -50,15 +51,15 @@ func FromID(client *govmomi.Client, id string) (*object.VirtualApp, error) {
 
 // IsVApp checks if a given managed object ID is a vApp. This is useful
 // deciding if a given resource pool is a vApp or a standard resource pool.
-func IsVApp(client *govmomi.Client, rp string) bool {
-	_, err := FromID(client, rp)
+func IsVApp(ctx context.Context, client *govmomi.Client, rp string) bool {
+	_, err := FromID(ctx, client, rp)
 	return err == nil
 }
 
 // Properties returns the VirtualApp managed object from its higher-level
 // object.
-func Properties(obj *object.VirtualApp) (*mo.VirtualApp, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func Properties(ctx context.Context, obj *object.VirtualApp) (*mo.VirtualApp, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.VirtualApp
 	if err := obj.Properties(ctx, obj.Reference(), nil, &props); err != nil {
@@ -68,9 +69,9 @@ func Properties(obj *object.VirtualApp) (*mo.VirtualApp, error) {
 }
 
 // Create creates a VirtualApp.
-func Create(rp *object.ResourcePool, name string, resSpec *types.ResourceConfigSpec, vSpec *types.VAppConfigSpec, folder *object.Folder) (*object.VirtualApp, error) {
+func Create(ctx context.Context, rp *object.ResourcePool, name string, resSpec *types.ResourceConfigSpec, vSpec *types.VAppConfigSpec, folder *object.Folder) (*object.VirtualApp, error) {
 	log.Printf("[DEBUG] Creating vApp container %s/%s", rp.InventoryPath, name)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	nva, err := rp.CreateVApp(ctx, name, *resSpec, *vSpec, folder)
 	if err != nil {
@@ -80,23 +81,23 @@ func Create(rp *object.ResourcePool, name string, resSpec *types.ResourceConfigS
 }
 
 // Update updates a VirtualApp.
-func Update(vc *object.VirtualApp, spec types.VAppConfigSpec) error {
+func Update(ctx context.Context, vc *object.VirtualApp, spec types.VAppConfigSpec) error {
 	log.Printf("[DEBUG] Updating vApp container %q", vc.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return vc.UpdateConfig(ctx, spec)
 }
 
 // Delete destroys a VirtualApp.
-func Delete(vc *object.VirtualApp) error {
+func Delete(ctx context.Context, vc *object.VirtualApp) error {
 	log.Printf("[DEBUG] Deleting vApp container %q", vc.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := vc.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // HasChildren checks to see if a vApp container has any child items (virtual
@@ -104,8 +105,8 @@ func Delete(vc *object.VirtualApp) error {
 // This is useful when checking to see if a vApp container is safe to delete.
 // Destroying a vApp container in vSphere destroys *all* children if at all
 // possible, so extra verification is necessary to prevent accidental removal.
-func HasChildren(vc *object.VirtualApp) (bool, error) {
-	props, err := Properties(vc)
+func HasChildren(ctx context.Context, vc *object.VirtualApp) (bool, error) {
+	props, err := Properties(ctx, vc)
 	if err != nil {
 		return false, err
 	}
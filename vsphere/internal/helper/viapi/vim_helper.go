@@ -8,8 +8,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -95,14 +99,72 @@ func IsResourceInUseError(err error) bool {
 	return false
 }
 
+// IsNotAuthenticatedError checks an error to see if it's of the
+// NotAuthenticated type, which is returned when the session has expired.
+func IsNotAuthenticatedError(err error) bool {
+	if f, ok := vimSoapFault(err); ok {
+		if _, ok := f.(types.NotAuthenticated); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryableError checks an error to see if it is a transient fault, after
+// which the same request can be sent again: a TaskInProgress or ResourceInUse
+// fault, or a response with a status of 429, 502, or 503. The faults of tasks,
+// returned by WaitForTask, are not transient in this sense, as the task may
+// have changed the object before failing.
+func IsRetryableError(err error) bool {
+	if IsResourceInUseError(err) {
+		return true
+	}
+	if f, ok := vimSoapFault(err); ok {
+		if _, ok := f.(types.TaskInProgress); ok {
+			return true
+		}
+		return false
+	}
+	var uerr *url.Error
+	if errors.As(err, &uerr) && uerr.Err != nil {
+		return IsRetryableStatus(statusCode(uerr.Err.Error()))
+	}
+	return false
+}
+
+// IsRetryableStatus checks an HTTP status code to see if the request can be
+// sent again.
+func IsRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// statusCode returns the status code of an HTTP status line, such as the
+// errors of the SOAP client for responses other than 200 and 500, or 0 if
+// status is not a status line.
+func statusCode(status string) int {
+	code, _, _ := strings.Cut(status, " ")
+	if len(code) != 3 {
+		return 0
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
 // RenameObject renames a MO and tracks the task to make sure it completes.
-func RenameObject(client *govmomi.Client, ref types.ManagedObjectReference, newObjectName string) error {
+func RenameObject(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference, newObjectName string) error {
 	req := types.Rename_Task{
 		This:    ref,
 		NewName: newObjectName,
 	}
 
-	rctx, rcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	rctx, rcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer rcancel()
 	res, err := methods.Rename_Task(rctx, client.Client, &req)
 	if err != nil {
@@ -110,9 +172,46 @@ func RenameObject(client *govmomi.Client, ref types.ManagedObjectReference, newO
 	}
 
 	t := object.NewTask(client.Client, res.Returnval)
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	return t.WaitEx(tctx)
+	return WaitForTask(tctx, t)
+}
+
+// cancelTaskTimeout is the timeout for the CancelTask call made when a task
+// is abandoned. The context of the abandoned operation is already done by
+// then, so the call gets its own.
+const cancelTaskTimeout = time.Minute
+
+// WaitForTask waits for a task to complete. If ctx is done first, for example
+// because Terraform was interrupted or the operation timed out, the task is
+// cancelled in vSphere before the error is returned, so that it does not keep
+// running unattended.
+func WaitForTask(ctx context.Context, task *object.Task) error {
+	_, err := WaitForTaskResult(ctx, task)
+	return err
+}
+
+// WaitForTaskResult waits for a task to complete and returns its result. The
+// task is cancelled if ctx is done first; see WaitForTask.
+func WaitForTaskResult(ctx context.Context, task *object.Task) (*types.TaskInfo, error) {
+	info, err := task.WaitForResultEx(ctx, nil)
+	if err != nil && ctx.Err() != nil {
+		CancelTask(task)
+	}
+	return info, err
+}
+
+// CancelTask requests the cancellation of a task. Failures are logged, not
+// returned, as this is only attempted on a best-effort basis after the
+// operation that started the task has already failed. Some tasks cannot be
+// cancelled once they reach a certain stage.
+func CancelTask(task *object.Task) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelTaskTimeout)
+	defer cancel()
+	log.Printf("[DEBUG] Cancelling task %q", task.Reference().Value)
+	if err := task.Cancel(ctx); err != nil {
+		log.Printf("[WARN] Could not cancel task %q: %s", task.Reference().Value, err)
+	}
 }
 
 // ValidateVirtualCenter ensures that the client is connected to vCenter.
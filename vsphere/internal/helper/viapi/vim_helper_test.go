@@ -5,9 +5,19 @@
 package viapi
 
 import (
+	"context"
+	"errors"
+	"net/url"
 	"reflect"
 	"regexp"
 	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 // testMatchError performs regex matching for error cases.
@@ -385,3 +395,88 @@ func TestAtLeast(t *testing.T) {
 	}
 
 }
+
+func TestWaitForTask(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := WaitForTask(ctx, task); err != nil {
+			t.Fatalf("expected task to succeed, got %s", err)
+		}
+	})
+}
+
+func TestWaitForTask_cancel(t *testing.T) {
+	// Hold the power-off task in the running state long enough for the waiter
+	// to give up. LockHandoff releases the inventory lock during the delay so
+	// that the task can still be read and cancelled.
+	simulator.TaskDelay.MethodDelay = map[string]int{
+		"PowerOff":    2000,
+		"LockHandoff": 0,
+	}
+	defer func() { simulator.TaskDelay.MethodDelay = nil }()
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Waiting with a context that is already done gives up immediately, in
+		// the same way as an interrupted or timed out operation.
+		wctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := WaitForTask(wctx, task); err == nil {
+			t.Fatal("expected error waiting for task, got none")
+		}
+
+		var mt mo.Task
+		if err := task.Properties(ctx, task.Reference(), []string{"info"}, &mt); err != nil {
+			t.Fatal(err)
+		}
+		if !mt.Info.Cancelled {
+			t.Fatalf("expected task to be cancelled, got state %q", mt.Info.State)
+		}
+	})
+}
+
+func TestIsRetryableError(t *testing.T) {
+	fault := func(f types.AnyType) error {
+		sf := &soap.Fault{String: "fault"}
+		sf.Detail.Fault = f
+		return soap.WrapSoapFault(sf)
+	}
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"task in progress", fault(types.TaskInProgress{}), true},
+		{"resource in use", fault(types.ResourceInUse{}), true},
+		{"not authenticated", fault(types.NotAuthenticated{}), false},
+		{"invalid argument", fault(types.InvalidArgument{}), false},
+		{"service unavailable", &url.Error{Op: "POST", URL: "https://vcenter/sdk", Err: errors.New("503 Service Unavailable")}, true},
+		{"too many requests", &url.Error{Op: "POST", URL: "https://vcenter/sdk", Err: errors.New("429 Too Many Requests")}, true},
+		{"not found", &url.Error{Op: "POST", URL: "https://vcenter/sdk", Err: errors.New("404 Not Found")}, false},
+		{"connection refused", &url.Error{Op: "POST", URL: "https://vcenter/sdk", Err: errors.New("dial tcp: connection refused")}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := IsRetryableError(tc.err); actual != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+	if !IsNotAuthenticatedError(fault(types.NotAuthenticated{})) {
+		t.Fatal("expected NotAuthenticated fault to be detected")
+	}
+}
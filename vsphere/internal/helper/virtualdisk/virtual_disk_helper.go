@@ -15,6 +15,7 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // DatastorePathFromString is a convenience method that returns a
@@ -52,7 +53,7 @@ func IsVmdkDatastorePath(p string) bool {
 //
 // The new datastore path is returned along with any error, to avoid the need
 // to re-calculate the path separately.
-func Move(client *govmomi.Client, srcPath string, srcDC *object.Datacenter, dstPath string, dstDC *object.Datacenter) (string, error) {
+func Move(ctx context.Context, client *govmomi.Client, srcPath string, srcDC *object.Datacenter, dstPath string, dstDC *object.Datacenter) (string, error) {
 	vdm := object.NewVirtualDiskManager(client.Client)
 	if srcDC == nil {
 		return "", fmt.Errorf("source datacenter cannot be nil")
@@ -75,15 +76,15 @@ func Move(client *govmomi.Client, srcPath string, srcDC *object.Datacenter, dstP
 		dstPath,
 		structure.LogCond(dstDC != nil, fmt.Sprintf("in datacenter %s", dstDC), ""),
 	)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := vdm.MoveVirtualDisk(ctx, srcPath, srcDC, dstPath, dstDC, false)
 	if err != nil {
 		return "", err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	if err := task.WaitEx(tctx); err != nil {
+	if err := viapi.WaitForTask(tctx, task); err != nil {
 		return "", err
 	}
 	log.Printf("[DEBUG] Virtual disk %q in datacenter %s successfully moved to destination %s%s",
@@ -96,8 +97,8 @@ func Move(client *govmomi.Client, srcPath string, srcDC *object.Datacenter, dstP
 }
 
 // QueryDiskType queries the disk type of the specified virtual disk.
-func QueryDiskType(client *govmomi.Client, name string, dc *object.Datacenter) (types.VirtualDiskType, error) {
-	di, err := FromPath(client, name, dc)
+func QueryDiskType(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) (types.VirtualDiskType, error) {
+	di, err := FromPath(ctx, client, name, dc)
 	if err != nil {
 		return "", err
 	}
@@ -124,21 +125,21 @@ func dstDataStorePathFromLocalSrc(src, dst string) string {
 }
 
 // Delete deletes the virtual disk at the specified datastore path.
-func Delete(client *govmomi.Client, name string, dc *object.Datacenter) error {
+func Delete(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) error {
 	if dc == nil {
 		return fmt.Errorf("datacenter cannot be nil")
 	}
 	log.Printf("[DEBUG] Deleting virtual disk %q in datacenter %s", name, dc)
 	vdm := object.NewVirtualDiskManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := vdm.DeleteVirtualDisk(ctx, name, dc)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	if err := task.WaitEx(tctx); err != nil {
+	if err := viapi.WaitForTask(tctx, task); err != nil {
 		return err
 	}
 	log.Printf("[DEBUG] Virtual disk %q in datacenter %s deleted successfully", name, dc)
@@ -146,9 +147,9 @@ func Delete(client *govmomi.Client, name string, dc *object.Datacenter) error {
 }
 
 // FromPath loads a datastore from its path.
-func FromPath(client *govmomi.Client, p string, dc *object.Datacenter) (*object.VirtualDiskInfo, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, p string, dc *object.Datacenter) (*object.VirtualDiskInfo, error) {
 	vdm := object.NewVirtualDiskManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	di, err := vdm.QueryVirtualDiskInfo(ctx, p, dc, false)
 	if err != nil {
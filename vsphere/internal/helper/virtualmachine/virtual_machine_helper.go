@@ -6,10 +6,14 @@ package virtualmachine
 
 import (
 	"context"
+	"crypto/sha1"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -86,12 +90,11 @@ func IsUUIDNotFoundError(err error) bool {
 	return ok
 }
 
-func List(client *govmomi.Client) ([]*object.VirtualMachine, error) {
-	return vmsByPath(client, "/*")
+func List(ctx context.Context, client *govmomi.Client) ([]*object.VirtualMachine, error) {
+	return vmsByPath(ctx, client, "/*")
 }
 
-func vmsByPath(client *govmomi.Client, path string) ([]*object.VirtualMachine, error) {
-	ctx := context.TODO()
+func vmsByPath(ctx context.Context, client *govmomi.Client, path string) ([]*object.VirtualMachine, error) {
 	var vms []*object.VirtualMachine
 	finder := find.NewFinder(client.Client, false)
 	es, err := finder.ManagedObjectListChildren(ctx, path+"/*", "vm", "folder", "pool")
@@ -101,13 +104,13 @@ func vmsByPath(client *govmomi.Client, path string) ([]*object.VirtualMachine, e
 	for _, id := range es {
 		switch {
 		case id.Object.Reference().Type == "VirtualMachine":
-			vm, err := FromMOID(client, id.Object.Reference().Value)
+			vm, err := FromMOID(ctx, client, id.Object.Reference().Value)
 			if err != nil {
 				return nil, err
 			}
 			vms = append(vms, vm)
 		case id.Object.Reference().Type == "Folder" || id.Object.Reference().Type == "ResourcePool":
-			newRPs, err := vmsByPath(client, id.Path)
+			newRPs, err := vmsByPath(ctx, client, id.Path)
 			if err != nil {
 				return nil, err
 			}
@@ -120,10 +123,10 @@ func vmsByPath(client *govmomi.Client, path string) ([]*object.VirtualMachine, e
 }
 
 // FromUUID locates a virtualMachine by its UUID.
-func FromUUID(client *govmomi.Client, uuid string) (*object.VirtualMachine, error) {
+func FromUUID(ctx context.Context, client *govmomi.Client, uuid string) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] Locating virtual machine with UUID %q", uuid)
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	var result object.Reference
@@ -223,7 +226,7 @@ func virtualMachineFromContainerView(ctx context.Context, client *govmomi.Client
 
 // FromMOID locates a virtualMachine by its managed
 // object reference ID.
-func FromMOID(client *govmomi.Client, id string) (*object.VirtualMachine, error) {
+func FromMOID(ctx context.Context, client *govmomi.Client, id string) (*object.VirtualMachine, error) {
 	finder := find.NewFinder(client.Client, false)
 
 	ref := types.ManagedObjectReference{
@@ -231,7 +234,7 @@ func FromMOID(client *govmomi.Client, id string) (*object.VirtualMachine, error)
 		Value: id,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	vm, err := finder.ObjectReference(ctx, ref)
 	if err != nil {
@@ -244,22 +247,22 @@ func FromMOID(client *govmomi.Client, id string) (*object.VirtualMachine, error)
 }
 
 // FromPath returns a VirtualMachine via its supplied path.
-func FromPath(client *govmomi.Client, path string, dc *object.Datacenter) (*object.VirtualMachine, error) {
+func FromPath(ctx context.Context, client *govmomi.Client, path string, dc *object.Datacenter) (*object.VirtualMachine, error) {
 	finder := find.NewFinder(client.Client, false)
 	if dc != nil {
 		finder.SetDatacenter(dc)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	return finder.VirtualMachine(ctx, path)
 }
 
 // Properties is a convenience method that wraps fetching the
 // VirtualMachine MO from its higher-level object.
-func Properties(vm *object.VirtualMachine) (*mo.VirtualMachine, error) {
+func Properties(ctx context.Context, vm *object.VirtualMachine) (*mo.VirtualMachine, error) {
 	log.Printf("[DEBUG] Fetching properties for VM %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.VirtualMachine
 	if err := vm.Properties(ctx, vm.Reference(), nil, &props); err != nil {
@@ -268,17 +271,30 @@ func Properties(vm *object.VirtualMachine) (*mo.VirtualMachine, error) {
 	return &props, nil
 }
 
+// SnapshotProperties is a convenience method that wraps fetching the
+// VirtualMachineSnapshot MO from its reference.
+func SnapshotProperties(ctx context.Context, vm *object.VirtualMachine, ref types.ManagedObjectReference) (*mo.VirtualMachineSnapshot, error) {
+	log.Printf("[DEBUG] Fetching properties for snapshot %q on VM %q", ref.Value, vm.InventoryPath)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	var props mo.VirtualMachineSnapshot
+	if err := vm.Properties(ctx, ref, nil, &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
 // ConfigOptions is a convenience method that wraps fetching the VirtualMachine ConfigOptions
 // as returned by QueryConfigOption.
-func ConfigOptions(vm *object.VirtualMachine) (*types.VirtualMachineConfigOption, error) {
+func ConfigOptions(ctx context.Context, vm *object.VirtualMachine) (*types.VirtualMachineConfigOption, error) {
 	// First grab the properties so that we can sneak the EnvironmentBrowser out of it
-	props, err := Properties(vm)
+	props, err := Properties(ctx, vm)
 	if err != nil {
 		return nil, err
 	}
 
 	// Make a context so we can timeout according to the provider configuration
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	// Build a request for the config option, and then query for configuration options
@@ -297,7 +313,7 @@ func ConfigOptions(vm *object.VirtualMachine) (*types.VirtualMachineConfigOption
 //
 // The timeout is specified in minutes. If zero or a negative value is passed,
 // the waiter returns without error immediately.
-func WaitForGuestIP(client *govmomi.Client, vm *object.VirtualMachine, timeout int, ignoredGuestIPs []interface{}) error {
+func WaitForGuestIP(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int, ignoredGuestIPs []interface{}) error {
 	if timeout < 1 {
 		log.Printf("[DEBUG] Skipping IP waiter for VM %q", vm.InventoryPath)
 		return nil
@@ -309,7 +325,7 @@ func WaitForGuestIP(client *govmomi.Client, vm *object.VirtualMachine, timeout i
 	)
 
 	p := client.PropertyCollector()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 
 	err := property.Wait(ctx, p, vm.Reference(), []string{"guest.ipAddress"}, func(pc []types.PropertyChange) bool {
@@ -352,7 +368,7 @@ func WaitForGuestIP(client *govmomi.Client, vm *object.VirtualMachine, timeout i
 //
 // The timeout is specified in minutes. If zero or a negative value is passed,
 // the waiter returns without error immediately.
-func WaitForGuestNet(client *govmomi.Client, vm *object.VirtualMachine, routable bool, timeout int, ignoredGuestIPs []interface{}) error {
+func WaitForGuestNet(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, routable bool, timeout int, ignoredGuestIPs []interface{}) error {
 	if timeout < 1 {
 		log.Printf("[DEBUG] Skipping network waiter for VM %q", vm.InventoryPath)
 		return nil
@@ -366,7 +382,7 @@ func WaitForGuestNet(client *govmomi.Client, vm *object.VirtualMachine, routable
 	var v4gw, v6gw net.IP
 
 	p := client.PropertyCollector()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 
 	err := property.Wait(ctx, p, vm.Reference(), []string{"guest.net", "guest.ipStack"}, func(pc []types.PropertyChange) bool {
@@ -433,6 +449,169 @@ func WaitForGuestNet(client *govmomi.Client, vm *object.VirtualMachine, routable
 	return nil
 }
 
+// GuestWaitCondition describes a state of a virtual machine that
+// WaitForGuestCondition waits for. Exactly one of GuestInfoKey, ToolsReady, or
+// Property is expected to be set.
+type GuestWaitCondition struct {
+	// GuestInfoKey is the name of a guestinfo variable, with or without the
+	// guestinfo. prefix, that the guest sets when it is ready.
+	GuestInfoKey string
+	// ToolsReady waits for VMware Tools to be running with a green heartbeat.
+	ToolsReady bool
+	// Property is the path of a virtual machine property to watch, for example
+	// summary.guest.toolsStatus.
+	Property string
+	// Value is the value that GuestInfoKey or Property must reach.
+	Value string
+	// Regex is a regular expression that the value of GuestInfoKey or Property
+	// must match.
+	Regex *regexp.Regexp
+}
+
+// String implements fmt.Stringer for GuestWaitCondition.
+func (c *GuestWaitCondition) String() string {
+	switch {
+	case c.ToolsReady:
+		return "VMware Tools to be running"
+	case c.GuestInfoKey != "":
+		return fmt.Sprintf("guestinfo variable %q%s", c.guestInfoKey(), c.matchString())
+	default:
+		return fmt.Sprintf("property %q%s", c.Property, c.matchString())
+	}
+}
+
+func (c *GuestWaitCondition) matchString() string {
+	switch {
+	case c.Regex != nil:
+		return fmt.Sprintf(" to match %q", c.Regex.String())
+	case c.Value != "":
+		return fmt.Sprintf(" to be %q", c.Value)
+	}
+	return ""
+}
+
+func (c *GuestWaitCondition) guestInfoKey() string {
+	if strings.HasPrefix(c.GuestInfoKey, "guestinfo.") {
+		return c.GuestInfoKey
+	}
+	return "guestinfo." + c.GuestInfoKey
+}
+
+// properties returns the virtual machine properties that need to be watched
+// for the condition.
+func (c *GuestWaitCondition) properties() []string {
+	switch {
+	case c.ToolsReady:
+		return []string{"guest.toolsRunningStatus", "guestHeartbeatStatus"}
+	case c.GuestInfoKey != "":
+		return []string{"config.extraConfig"}
+	default:
+		return []string{c.Property}
+	}
+}
+
+// matchValue checks a value against the Value or Regex of the condition. When
+// neither is set, any non-empty value matches.
+func (c *GuestWaitCondition) matchValue(v string) bool {
+	switch {
+	case c.Regex != nil:
+		return c.Regex.MatchString(v)
+	case c.Value != "":
+		return v == c.Value
+	}
+	return v != ""
+}
+
+// guestWaitState tracks the property changes received by
+// WaitForGuestCondition.
+type guestWaitState struct {
+	cond          *GuestWaitCondition
+	started       bool
+	initial       string
+	toolsRunning  bool
+	heartbeatGood bool
+}
+
+// update applies a set of property changes to the state and reports if the
+// condition has been met.
+func (s *guestWaitState) update(pc []types.PropertyChange) bool {
+	// The first set of changes holds the values at the time the waiter
+	// started. An unset property is not included in it at all.
+	first := !s.started
+	s.started = true
+	for _, c := range pc {
+		if c.Op != types.PropertyChangeOpAssign {
+			continue
+		}
+		switch {
+		case s.cond.ToolsReady && c.Name == "guest.toolsRunningStatus":
+			s.toolsRunning = c.Val == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+		case s.cond.ToolsReady && c.Name == "guestHeartbeatStatus":
+			s.heartbeatGood = c.Val == types.ManagedEntityStatusGreen
+		case s.cond.GuestInfoKey != "":
+			if v, ok := c.Val.(types.ArrayOfOptionValue); ok {
+				for _, ov := range v.OptionValue {
+					if o := ov.GetOptionValue(); o.Key == s.cond.guestInfoKey() && s.cond.matchValue(fmt.Sprint(o.Value)) {
+						return true
+					}
+				}
+			}
+		case c.Name == s.cond.Property:
+			v := ""
+			if c.Val != nil {
+				v = fmt.Sprint(c.Val)
+			}
+			if s.cond.Value != "" || s.cond.Regex != nil {
+				if s.cond.matchValue(v) {
+					return true
+				}
+				continue
+			}
+			// Without a value to match, wait for the property to change from the
+			// value it had when the waiter started.
+			if first {
+				s.initial = v
+				continue
+			}
+			if v != s.initial {
+				return true
+			}
+		}
+	}
+	return s.cond.ToolsReady && s.toolsRunning && s.heartbeatGood
+}
+
+// WaitForGuestCondition waits for a virtual machine to reach the state
+// described by cond. Like the other guest waiters, this uses the
+// PropertyCollector to watch the virtual machine for changes.
+//
+// The timeout is specified in minutes. If zero or a negative value is passed,
+// the waiter returns without error immediately.
+func WaitForGuestCondition(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, cond *GuestWaitCondition, timeout int) error {
+	if cond == nil || timeout < 1 {
+		log.Printf("[DEBUG] Skipping guest waiter for VM %q", vm.InventoryPath)
+		return nil
+	}
+	log.Printf("[DEBUG] Waiting for %s on VM %q (timeout = %dm)", cond, vm.InventoryPath, timeout)
+
+	p := client.PropertyCollector()
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
+	defer cancel()
+
+	state := &guestWaitState{cond: cond}
+	err := property.Wait(ctx, p, vm.Reference(), cond.properties(), state.update)
+	if err != nil {
+		// Provide a friendly error message if we timed out waiting for the guest.
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timeout waiting for %s", cond)
+		}
+		return err
+	}
+
+	log.Printf("[DEBUG] Guest wait condition met for VM %q", vm.InventoryPath)
+	return nil
+}
+
 func skipIPAddrForWaiter(ip net.IP, ignoredGuestIPs []interface{}) bool {
 	switch {
 	case ip.IsLinkLocalMulticast():
@@ -464,7 +643,7 @@ func blockUntilReadyForMethod(ctx context.Context, method string, vm *object.Vir
 	log.Printf("[DEBUG] blockUntilReadyForMethod: Going to block until %q is no longer in the Disabled Methods list for vm %s", method, vm.Reference().Value)
 
 	for {
-		vprops, err := Properties(vm)
+		vprops, err := Properties(ctx, vm)
 		if err != nil {
 			return fmt.Errorf("cannot fetch properties of created virtual machine: %s", err)
 		}
@@ -494,14 +673,14 @@ func blockUntilReadyForMethod(ctx context.Context, method string, vm *object.Vir
 
 // Create wraps the creation of a virtual machine and the subsequent waiting of
 // the task. A higher-level virtual machine object is returned.
-func Create(c *govmomi.Client, f *object.Folder, s types.VirtualMachineConfigSpec, p *object.ResourcePool,
+func Create(ctx context.Context, c *govmomi.Client, f *object.Folder, s types.VirtualMachineConfigSpec, p *object.ResourcePool,
 	h *object.HostSystem, timeout time.Duration) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] Creating virtual machine %q", fmt.Sprintf("%s/%s", f.InventoryPath, s.Name))
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	var task *object.Task
 	// Check to see if the resource pool is a vApp
-	vc, err := vappcontainer.FromID(c, p.Reference().Value)
+	vc, err := vappcontainer.FromID(ctx, c, p.Reference().Value)
 	if err != nil {
 		if !viapi.IsManagedObjectNotFoundError(err) {
 			return nil, err
@@ -513,21 +692,21 @@ func Create(c *govmomi.Client, f *object.Folder, s types.VirtualMachineConfigSpe
 	if err != nil {
 		return nil, err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), timeout)
+	tctx, tcancel := context.WithTimeout(ctx, timeout)
 	defer tcancel()
-	result, err := task.WaitForResultEx(tctx, nil)
+	result, err := viapi.WaitForTaskResult(tctx, task)
 	if err != nil {
 		return nil, err
 	}
 	log.Printf("[DEBUG] Virtual machine %q: creation complete (MOID: %q)", fmt.Sprintf("%s/%s", f.InventoryPath, s.Name), result.Result.(types.ManagedObjectReference).Value)
-	return FromMOID(c, result.Result.(types.ManagedObjectReference).Value)
+	return FromMOID(ctx, c, result.Result.(types.ManagedObjectReference).Value)
 }
 
 // Clone wraps the creation of a virtual machine and the subsequent waiting of
 // the task. A higher-level virtual machine object is returned.
-func Clone(c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name string, spec types.VirtualMachineCloneSpec, timeout int) (*object.VirtualMachine, error) {
+func Clone(ctx context.Context, c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name string, spec types.VirtualMachineCloneSpec, timeout int) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] Cloning virtual machine %q", fmt.Sprintf("%s/%s", f.InventoryPath, name))
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 	task, err := src.Clone(ctx, f, name, spec)
 	if err != nil {
@@ -536,7 +715,7 @@ func Clone(c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name
 		}
 		return nil, err
 	}
-	result, err := task.WaitForResultEx(ctx, nil)
+	result, err := viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			err = errors.New("timeout waiting for clone to complete")
@@ -544,25 +723,52 @@ func Clone(c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name
 		return nil, err
 	}
 	log.Printf("[DEBUG] Virtual machine %q: clone complete (MOID: %q)", fmt.Sprintf("%s/%s", f.InventoryPath, name), result.Result.(types.ManagedObjectReference).Value)
-	return FromMOID(c, result.Result.(types.ManagedObjectReference).Value)
+	return FromMOID(ctx, c, result.Result.(types.ManagedObjectReference).Value)
+}
+
+// InstantClone wraps the creation of an instant clone of a running virtual
+// machine, adding a timeout and returning the new virtual machine.
+func InstantClone(ctx context.Context, c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name string, spec types.VirtualMachineInstantCloneSpec, timeout int) (*object.VirtualMachine, error) {
+	log.Printf("[DEBUG] Instant cloning virtual machine %q", fmt.Sprintf("%s/%s", f.InventoryPath, name))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
+	defer cancel()
+	spec.Name = name
+	fRef := f.Reference()
+	spec.Location.Folder = &fRef
+	task, err := src.InstantClone(ctx, spec)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = errors.New("timeout waiting for instant clone to complete")
+		}
+		return nil, err
+	}
+	result, err := viapi.WaitForTaskResult(ctx, task)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = errors.New("timeout waiting for instant clone to complete")
+		}
+		return nil, err
+	}
+	log.Printf("[DEBUG] Virtual machine %q: instant clone complete (MOID: %q)", fmt.Sprintf("%s/%s", f.InventoryPath, name), result.Result.(types.ManagedObjectReference).Value)
+	return FromMOID(ctx, c, result.Result.(types.ManagedObjectReference).Value)
 }
 
 // Deploy clones a virtual machine from a content library item.
-func Deploy(deployData *VCenterDeploy) (*types.ManagedObjectReference, error) {
+func Deploy(ctx context.Context, deployData *VCenterDeploy) (*types.ManagedObjectReference, error) {
 	log.Printf("[DEBUG] virtualmachine.Deploy: Deploying VM from Content Library item.")
 	// Get OVF mappings for NICs
 
 	switch deployData.LibraryItem.Type {
 	case library.ItemTypeOVF:
-		return deployData.deployOvf()
+		return deployData.deployOvf(ctx)
 	case library.ItemTypeVMTX:
-		return deployData.deployVmtx()
+		return deployData.deployVmtx(ctx)
 	default:
 		return nil, fmt.Errorf("unsupported library item type: %s", deployData.LibraryItem.Type)
 	}
 }
 
-func (deployData *VCenterDeploy) deployVmtx() (*types.ManagedObjectReference, error) {
+func (deployData *VCenterDeploy) deployVmtx(ctx context.Context) (*types.ManagedObjectReference, error) {
 	storage := &vcenter.DiskStorage{
 		Datastore: deployData.DatastoreID,
 		StoragePolicy: &vcenter.StoragePolicy{
@@ -585,11 +791,10 @@ func (deployData *VCenterDeploy) deployVmtx() (*types.ManagedObjectReference, er
 			Folder:       deployData.FolderID,
 		},
 	}
-	ctx := context.TODO()
 	return deployData.VCenterManager.DeployTemplateLibraryItem(ctx, deployData.LibraryItem.ID, deploy)
 }
 
-func (deployData *VCenterDeploy) deployOvf() (*types.ManagedObjectReference, error) {
+func (deployData *VCenterDeploy) deployOvf(ctx context.Context) (*types.ManagedObjectReference, error) {
 	deploy := vcenter.Deploy{
 		DeploymentSpec: vcenter.DeploymentSpec{
 			Name:               deployData.VMName,
@@ -617,7 +822,6 @@ func (deployData *VCenterDeploy) deployOvf() (*types.ManagedObjectReference, err
 			FolderID:       deployData.FolderID,
 		},
 	}
-	ctx := context.TODO()
 	return deployData.VCenterManager.DeployLibraryItem(ctx, deployData.LibraryItem.ID, deploy)
 }
 
@@ -654,21 +858,21 @@ func DiskType(d *schema.ResourceData) string {
 
 // Customize wraps the customization of a virtual machine and the subsequent
 // waiting of the task.
-func Customize(vm *object.VirtualMachine, spec types.CustomizationSpec) error {
+func Customize(ctx context.Context, vm *object.VirtualMachine, spec types.CustomizationSpec) error {
 	log.Printf("[DEBUG] Sending customization spec to virtual machine %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := vm.Customize(ctx, spec)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	return task.WaitEx(tctx)
+	return viapi.WaitForTask(tctx, task)
 }
 
 // PowerOn wraps powering on a VM and the waiting for the subsequent task.
-func PowerOn(vm *object.VirtualMachine, pTimeout time.Duration) error {
+func PowerOn(ctx context.Context, vm *object.VirtualMachine, pTimeout time.Duration) error {
 	vmPath := vm.InventoryPath
 	log.Printf("[DEBUG] Powering on virtual machine %q", vmPath)
 	var ctxTimeout time.Duration
@@ -678,7 +882,7 @@ func PowerOn(vm *object.VirtualMachine, pTimeout time.Duration) error {
 		ctxTimeout = provider.DefaultAPITimeout
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, ctxTimeout)
 	defer cancel()
 
 	err := blockUntilReadyForMethod(ctx, "PowerOnVM_Task", vm)
@@ -697,7 +901,7 @@ powerLoop:
 	for {
 		select {
 		case <-time.After(500 * time.Millisecond):
-			vprops, err := Properties(vm)
+			vprops, err := Properties(ctx, vm)
 			if err != nil {
 				return fmt.Errorf("cannot fetch properties of created virtual machine: %s", err)
 			}
@@ -708,7 +912,7 @@ powerLoop:
 					log.Printf("[DEBUG] Failed to submit PowerOn task for vm %q. Error: %s", vmPath, err)
 					return fmt.Errorf("failed to submit poweron task for vm %q: %s", vmPath, err)
 				}
-				err = task.WaitEx(ctx)
+				err = viapi.WaitForTask(ctx, task)
 				if err != nil {
 					if err != nil && err.Error() == "The operation is not allowed in the current state." {
 						log.Printf("[DEBUG] vm %q cannot be powered on in the current state", vmPath)
@@ -727,17 +931,17 @@ powerLoop:
 }
 
 // PowerOff wraps powering off a VM and the waiting for the subsequent task.
-func PowerOff(vm *object.VirtualMachine) error {
+func PowerOff(ctx context.Context, vm *object.VirtualMachine) error {
 	log.Printf("[DEBUG] Forcing power off of virtual machine of %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := vm.PowerOff(ctx)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	return task.WaitEx(tctx)
+	return viapi.WaitForTask(tctx, task)
 }
 
 // ShutdownGuest wraps the graceful shutdown of a guest VM, and then waiting an
@@ -747,9 +951,9 @@ func PowerOff(vm *object.VirtualMachine) error {
 //
 // The minimum value for timeout is 1 minute - setting to a 0 or negative value
 // is not allowed and will just reset the timeout to the minimum.
-func ShutdownGuest(client *govmomi.Client, vm *object.VirtualMachine, timeout int) error {
+func ShutdownGuest(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int) error {
 	log.Printf("[DEBUG] Attempting guest shutdown of virtual machine %q", vm.InventoryPath)
-	sctx, scancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	sctx, scancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer scancel()
 	if err := vm.ShutdownGuest(sctx); err != nil {
 		return err
@@ -760,7 +964,7 @@ func ShutdownGuest(client *govmomi.Client, vm *object.VirtualMachine, timeout in
 	if timeout < 1 {
 		timeout = 1
 	}
-	pctx, pcancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	pctx, pcancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer pcancel()
 
 	err := property.Wait(pctx, p, vm.Reference(), []string{"runtime.powerState"}, func(pc []types.PropertyChange) bool {
@@ -793,8 +997,8 @@ func ShutdownGuest(client *govmomi.Client, vm *object.VirtualMachine, timeout in
 // machines. A graceful shutdown is attempted first if possible (VMware Tools
 // is installed, and the guest state is not suspended), and then, if allowed, a
 // power-off is forced if that fails.
-func GracefulPowerOff(client *govmomi.Client, vm *object.VirtualMachine, timeout int, force bool) error {
-	vprops, err := Properties(vm)
+func GracefulPowerOff(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int, force bool) error {
+	vprops, err := Properties(ctx, vm)
 	if err != nil {
 		return err
 	}
@@ -802,7 +1006,7 @@ func GracefulPowerOff(client *govmomi.Client, vm *object.VirtualMachine, timeout
 	// actually powered on (we don't expect that a graceful shutdown would
 	// complete on a suspended VM, so there's really no point in trying).
 	if vprops.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn && vprops.Guest != nil && vprops.Guest.ToolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
-		if err := ShutdownGuest(client, vm, timeout); err != nil {
+		if err := ShutdownGuest(ctx, client, vm, timeout); err != nil {
 			if errors.Is(err, errGuestShutdownTimeout) && !force {
 				return err
 			}
@@ -813,45 +1017,80 @@ func GracefulPowerOff(client *govmomi.Client, vm *object.VirtualMachine, timeout
 	// If the guest shutdown failed (and we were allowed to proceed), or
 	// conditions did not satisfy the criteria for a graceful shutdown, do a full
 	// power-off of the VM.
-	return PowerOff(vm)
+	return PowerOff(ctx, vm)
 }
 
 // MoveToFolder moves a virtual machine to the specified folder.
-func MoveToFolder(client *govmomi.Client, vm *object.VirtualMachine, relative string) error {
+func MoveToFolder(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, relative string) error {
 	log.Printf("[DEBUG] Moving virtual %q to VM path %q", vm.InventoryPath, relative)
-	f, err := folder.VirtualMachineFolderFromObject(client, vm, relative)
+	f, err := folder.VirtualMachineFolderFromObject(ctx, client, vm, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(vm.Reference(), f)
+	return folder.MoveObjectTo(ctx, vm.Reference(), f)
 }
 
 // Reconfigure wraps the Reconfigure task and the subsequent waiting for
 // the task to complete.
-func Reconfigure(vm *object.VirtualMachine, spec types.VirtualMachineConfigSpec, timeout time.Duration) error {
+func Reconfigure(ctx context.Context, vm *object.VirtualMachine, spec types.VirtualMachineConfigSpec, timeout time.Duration) error {
 	log.Printf("[DEBUG] Reconfiguring virtual machine %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	task, err := vm.Reconfigure(ctx, spec)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), timeout)
+	tctx, tcancel := context.WithTimeout(ctx, timeout)
 	defer tcancel()
-	return task.WaitEx(tctx)
+	return viapi.WaitForTask(tctx, task)
+}
+
+// ServiceLocator returns a service locator for the vCenter Server instance
+// that c is connected to, for use in the relocate spec of a migration or a
+// clone across vCenter Server instances. The credentials are used by the
+// source instance to log in to the destination instance. When thumbprint is
+// empty, the SHA-1 thumbprint of the certificate of the instance is used.
+func ServiceLocator(c *govmomi.Client, username, password, thumbprint string) (*types.ServiceLocator, error) {
+	u := &url.URL{
+		Scheme: "https",
+		Host:   c.URL().Host,
+	}
+	// The service locator takes a SHA-1 thumbprint. It is read from the
+	// certificate when none is set, or when a SHA-256 thumbprint is set.
+	if len(strings.ReplaceAll(thumbprint, ":", "")) != sha1.Size*2 {
+		var info object.HostCertificateInfo
+		// The certificate is only read to compute its thumbprint, the connection
+		// itself has already been verified.
+		if err := info.FromURL(u, &tls.Config{InsecureSkipVerify: true}); err != nil { //nolint (gosec G402)
+			return nil, fmt.Errorf("error fetching certificate thumbprint of %s: %s", u.Host, err)
+		}
+		if thumbprint != "" && !strings.EqualFold(thumbprint, info.ThumbprintSHA256) {
+			return nil, fmt.Errorf("certificate of %s does not match thumbprint %q", u.Host, thumbprint)
+		}
+		thumbprint = info.ThumbprintSHA1
+	}
+	return &types.ServiceLocator{
+		InstanceUuid: c.ServiceContent.About.InstanceUuid,
+		Url:          u.String(),
+		Credential: &types.ServiceLocatorNamePassword{
+			Username: username,
+			Password: password,
+		},
+		SslThumbprint: thumbprint,
+	}, nil
 }
 
 // Relocate wraps the Relocate task and the subsequent waiting for the task to
 // complete.
-func Relocate(vm *object.VirtualMachine, spec types.VirtualMachineRelocateSpec, timeout int) error {
+func Relocate(ctx context.Context, vm *object.VirtualMachine, spec types.VirtualMachineRelocateSpec, timeout int) error {
 	log.Printf("[DEBUG] Beginning migration of virtual machine %q (timeout %d)", vm.InventoryPath, timeout)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 	task, err := vm.Relocate(ctx, spec, "")
 	if err != nil {
 		return err
 	}
-	if err := task.WaitEx(ctx); err != nil {
+	if err := viapi.WaitForTask(ctx, task); err != nil {
 		// Provide a friendly error message if we timed out waiting for the migration.
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return errors.New("timeout waiting for migration to complete")
@@ -864,25 +1103,26 @@ func Relocate(vm *object.VirtualMachine, spec types.VirtualMachineRelocateSpec,
 
 // Destroy wraps the Destroy task and the subsequent waiting for the task to
 // complete.
-func Destroy(vm *object.VirtualMachine) error {
+func Destroy(ctx context.Context, vm *object.VirtualMachine) error {
 	log.Printf("[DEBUG] Deleting virtual machine %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := vm.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	return task.WaitEx(tctx)
+	return viapi.WaitForTask(tctx, task)
 }
 
 // ApplyEvc wraps the call to ApplyEvcModeVM_Task and waits for the task to complete.
 func ApplyEvc(
+	ctx context.Context,
 	vm *object.VirtualMachine,
 	mask []types.HostFeatureMask,
 ) error {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	req := types.ApplyEvcModeVM_Task{
@@ -896,7 +1136,7 @@ func ApplyEvc(
 	}
 
 	t := object.NewTask(vm.Client(), res.Returnval)
-	return t.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, t)
 }
 
 // MOIDForUUIDResult is a struct that holds a virtual machine UUID -> MOID
@@ -918,8 +1158,8 @@ type MOIDForUUIDResults []MOIDForUUIDResult
 // MOIDForUUID returns the managed object reference ID for a specific virtual
 // machine UUID and returns a MOIDForUUIDResult with the appropriate
 // association.
-func MOIDForUUID(client *govmomi.Client, uuid string) (MOIDForUUIDResult, error) {
-	vm, err := FromUUID(client, uuid)
+func MOIDForUUID(ctx context.Context, client *govmomi.Client, uuid string) (MOIDForUUIDResult, error) {
+	vm, err := FromUUID(ctx, client, uuid)
 	if err != nil {
 		return MOIDForUUIDResult{}, err
 	}
@@ -932,12 +1172,12 @@ func MOIDForUUID(client *govmomi.Client, uuid string) (MOIDForUUIDResult, error)
 // UUIDForMOID returns the managed object reference ID for a specific virtual
 // machine MOID and returns a MOIDForUUIDResult with the appropriate
 // association.
-func UUIDForMOID(client *govmomi.Client, moid string) (MOIDForUUIDResult, error) {
-	vm, err := FromMOID(client, moid)
+func UUIDForMOID(ctx context.Context, client *govmomi.Client, moid string) (MOIDForUUIDResult, error) {
+	vm, err := FromMOID(ctx, client, moid)
 	if err != nil {
 		return MOIDForUUIDResult{}, err
 	}
-	props, err := Properties(vm)
+	props, err := Properties(ctx, vm)
 	if err != nil {
 		return MOIDForUUIDResult{}, err
 	}
@@ -950,10 +1190,10 @@ func UUIDForMOID(client *govmomi.Client, moid string) (MOIDForUUIDResult, error)
 // MOIDsForUUIDs returns a MOIDForUUIDResults for a list of UUIDs. If one UUID
 // cannot be found, an error is returned. There are no partial results
 // returned.
-func MOIDsForUUIDs(client *govmomi.Client, uuids []string) (MOIDForUUIDResults, error) {
+func MOIDsForUUIDs(ctx context.Context, client *govmomi.Client, uuids []string) (MOIDForUUIDResults, error) {
 	var results MOIDForUUIDResults
 	for _, uuid := range uuids {
-		result, err := MOIDForUUID(client, uuid)
+		result, err := MOIDForUUID(ctx, client, uuid)
 		if err != nil {
 			return nil, err
 		}
@@ -965,10 +1205,10 @@ func MOIDsForUUIDs(client *govmomi.Client, uuids []string) (MOIDForUUIDResults,
 // UUIDsForMOIDs returns a MOIDForUUIDResults for a list of MOIDs. If one MOID
 // cannot be found, an error is returned. There are no partial results
 // returned.
-func UUIDsForMOIDs(client *govmomi.Client, moids []string) (MOIDForUUIDResults, error) {
+func UUIDsForMOIDs(ctx context.Context, client *govmomi.Client, moids []string) (MOIDForUUIDResults, error) {
 	var results MOIDForUUIDResults
 	for _, uuid := range moids {
-		result, err := UUIDForMOID(client, uuid)
+		result, err := UUIDForMOID(ctx, client, uuid)
 		if err != nil {
 			return nil, err
 		}
@@ -980,12 +1220,12 @@ func UUIDsForMOIDs(client *govmomi.Client, moids []string) (MOIDForUUIDResults,
 // UUIDsForManagedObjectReferences returns a MOIDForUUIDResults for a list of
 // ManagedObjectReferences. If one cannot be found, an error is returned. There
 // are no partial results returned.
-func UUIDsForManagedObjectReferences(client *govmomi.Client, refs []types.ManagedObjectReference) (MOIDForUUIDResults, error) {
+func UUIDsForManagedObjectReferences(ctx context.Context, client *govmomi.Client, refs []types.ManagedObjectReference) (MOIDForUUIDResults, error) {
 	var moids []string
 	for _, ref := range refs {
 		moids = append(moids, ref.Value)
 	}
-	return UUIDsForMOIDs(client, moids)
+	return UUIDsForMOIDs(ctx, client, moids)
 }
 
 // MOIDs returns all MOIDs in a MOIDForUUIDResults.
@@ -1040,9 +1280,9 @@ func GetHardwareVersionNumber(vstring string) int {
 
 // SetHardwareVersion sets the virtual machine's hardware version. The virtual
 // machine must be powered off, and the version can only be increased.
-func SetHardwareVersion(vm *object.VirtualMachine, target int) error {
+func SetHardwareVersion(ctx context.Context, vm *object.VirtualMachine, target int) error {
 	// First query for the configuration options of the vm
-	copts, err := ConfigOptions(vm)
+	copts, err := ConfigOptions(ctx, vm)
 	if err != nil {
 		return err
 	}
@@ -1064,7 +1304,7 @@ func SetHardwareVersion(vm *object.VirtualMachine, target int) error {
 	}
 
 	// We can now proceed to upgrade the hardware version on the vm
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	log.Printf("[DEBUG] Upgrading VM from hw version %d to hw version %d", current, target)
@@ -1072,7 +1312,7 @@ func SetHardwareVersion(vm *object.VirtualMachine, target int) error {
 	if err != nil {
 		return err
 	}
-	_, err = task.WaitForResultEx(ctx, nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	return err
 }
 
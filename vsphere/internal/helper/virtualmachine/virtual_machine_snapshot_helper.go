@@ -0,0 +1,114 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualmachine
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+// SnapshotNode is a snapshot in the snapshot tree of a virtual machine. Parent
+// is nil for root snapshots.
+type SnapshotNode struct {
+	Tree   *types.VirtualMachineSnapshotTree
+	Parent *types.VirtualMachineSnapshotTree
+}
+
+// Snapshots returns the snapshots of a virtual machine, walking the snapshot
+// tree depth-first.
+func Snapshots(props *mo.VirtualMachine) []SnapshotNode {
+	if props.Snapshot == nil {
+		return nil
+	}
+	var nodes []SnapshotNode
+	var walk func(trees []types.VirtualMachineSnapshotTree, parent *types.VirtualMachineSnapshotTree)
+	walk = func(trees []types.VirtualMachineSnapshotTree, parent *types.VirtualMachineSnapshotTree) {
+		for i := range trees {
+			nodes = append(nodes, SnapshotNode{Tree: &trees[i], Parent: parent})
+			walk(trees[i].ChildSnapshotList, &trees[i])
+		}
+	}
+	walk(props.Snapshot.RootSnapshotList, nil)
+	return nodes
+}
+
+// FindSnapshotNode locates a snapshot of a virtual machine by its managed
+// object ID or, failing that, by its name. nil is returned if the snapshot
+// does not exist. An error is returned if more than one snapshot has the
+// supplied name.
+func FindSnapshotNode(props *mo.VirtualMachine, nameOrID string) (*SnapshotNode, error) {
+	var byName []SnapshotNode
+	for _, node := range Snapshots(props) {
+		if node.Tree.Snapshot.Value == nameOrID {
+			return &node, nil
+		}
+		if node.Tree.Name == nameOrID {
+			byName = append(byName, node)
+		}
+	}
+	switch len(byName) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &byName[0], nil
+	}
+	return nil, fmt.Errorf("found %d snapshots named %q, use the snapshot ID instead", len(byName), nameOrID)
+}
+
+// IsCurrentSnapshot returns true if the supplied snapshot is the current
+// snapshot of the virtual machine.
+func IsCurrentSnapshot(props *mo.VirtualMachine, node SnapshotNode) bool {
+	return props.Snapshot != nil && props.Snapshot.CurrentSnapshot != nil && props.Snapshot.CurrentSnapshot.Value == node.Tree.Snapshot.Value
+}
+
+// SnapshotSize returns the size, in bytes, of the files that make up a
+// snapshot. props must include the layoutEx property of the virtual machine.
+func SnapshotSize(props *mo.VirtualMachine, node SnapshotNode) int64 {
+	if props.LayoutEx == nil {
+		return 0
+	}
+	var parent *types.ManagedObjectReference
+	if node.Parent != nil {
+		parent = &node.Parent.Snapshot
+	}
+	return int64(object.SnapshotSize(node.Tree.Snapshot, parent, props.LayoutEx, IsCurrentSnapshot(props, node)))
+}
+
+// RevertToSnapshot reverts a virtual machine to the snapshot with the supplied
+// managed object ID. If suppressPowerOn is true, the virtual machine is not
+// powered on even if it was powered on when the snapshot was taken.
+func RevertToSnapshot(ctx context.Context, vm *object.VirtualMachine, id string, suppressPowerOn bool) error {
+	log.Printf("[DEBUG] Reverting virtual machine %q to snapshot %q", vm.InventoryPath, id)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	task, err := vm.RevertToSnapshot(ctx, id, suppressPowerOn)
+	if err != nil {
+		return err
+	}
+	return viapi.WaitForTask(ctx, task)
+}
+
+// RenameSnapshot changes the name and description of a snapshot.
+func RenameSnapshot(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference, name, description string) error {
+	log.Printf("[DEBUG] Renaming snapshot %q to %q", ref.Value, name)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+	defer cancel()
+	req := types.RenameSnapshot{
+		This:        ref,
+		Name:        name,
+		Description: description,
+	}
+	_, err := methods.RenameSnapshot(ctx, client.Client, &req)
+	return err
+}
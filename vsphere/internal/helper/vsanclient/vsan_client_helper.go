@@ -14,20 +14,20 @@ import (
 	"github.com/vmware/govmomi/vsan/methods"
 	vsantypes "github.com/vmware/govmomi/vsan/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
-func Reconfigure(vsanClient *vsan.Client, cluster vimtypes.ManagedObjectReference, spec vsantypes.VimVsanReconfigSpec) error {
-	ctx := context.TODO()
+func Reconfigure(ctx context.Context, vsanClient *vsan.Client, cluster vimtypes.ManagedObjectReference, spec vsantypes.VimVsanReconfigSpec) error {
 
 	task, err := vsanClient.VsanClusterReconfig(ctx, cluster.Reference(), spec)
 	if err != nil {
 		return err
 	}
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
-func GetVsanConfig(vsanClient *vsan.Client, cluster vimtypes.ManagedObjectReference) (*vsantypes.VsanConfigInfoEx, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func GetVsanConfig(ctx context.Context, vsanClient *vsan.Client, cluster vimtypes.ManagedObjectReference) (*vsantypes.VsanConfigInfoEx, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	vsanConfig, err := vsanClient.VsanClusterGetConfig(ctx, cluster.Reference())
@@ -35,8 +35,8 @@ func GetVsanConfig(vsanClient *vsan.Client, cluster vimtypes.ManagedObjectRefere
 	return vsanConfig, err
 }
 
-func ConvertToStretchedCluster(vsanClient *vsan.Client, client *govmomi.Client, req vsantypes.VSANVcConvertToStretchedCluster) error {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func ConvertToStretchedCluster(ctx context.Context, vsanClient *vsan.Client, client *govmomi.Client, req vsantypes.VSANVcConvertToStretchedCluster) error {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	res, err := methods.VSANVcConvertToStretchedCluster(ctx, vsanClient, &req)
@@ -46,13 +46,13 @@ func ConvertToStretchedCluster(vsanClient *vsan.Client, client *govmomi.Client,
 	}
 
 	task := object.NewTask(client.Client, res.Returnval)
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
 // RemoveWitnessHost removes a witness host for a stretched cluster and results in the cluster being converted
 // to a non-stretched cluster.
-func RemoveWitnessHost(vsanClient *vsan.Client, client *govmomi.Client, req vsantypes.VSANVcRemoveWitnessHost) error {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func RemoveWitnessHost(ctx context.Context, vsanClient *vsan.Client, client *govmomi.Client, req vsantypes.VSANVcRemoveWitnessHost) error {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	res, err := methods.VSANVcRemoveWitnessHost(ctx, vsanClient, &req)
@@ -62,11 +62,11 @@ func RemoveWitnessHost(vsanClient *vsan.Client, client *govmomi.Client, req vsan
 	}
 
 	task := object.NewTask(client.Client, res.Returnval)
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
 
-func GetWitnessHosts(vsanClient *vsan.Client, cluster vimtypes.ManagedObjectReference) (*vsantypes.VSANVcGetWitnessHostsResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func GetWitnessHosts(ctx context.Context, vsanClient *vsan.Client, cluster vimtypes.ManagedObjectReference) (*vsantypes.VSANVcGetWitnessHostsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	req := vsantypes.VSANVcGetWitnessHosts{
@@ -13,11 +13,12 @@ import (
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // Properties Returns the HostVsanSystem ManagedObject for the HostVsanSystem object.
-func Properties(hss *object.HostVsanSystem, apiTimeout time.Duration) (*mo.HostVsanSystem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+func Properties(ctx context.Context, hss *object.HostVsanSystem, apiTimeout time.Duration) (*mo.HostVsanSystem, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
 	defer cancel()
 	var hvsProps mo.HostVsanSystem
 	err := hss.Properties(ctx, hss.Reference(), nil, &hvsProps)
@@ -28,16 +29,16 @@ func Properties(hss *object.HostVsanSystem, apiTimeout time.Duration) (*mo.HostV
 }
 
 // FromHost returns a host's HostVsanSystem object.
-func FromHost(host *object.HostSystem, apiTimeout time.Duration) (*object.HostVsanSystem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+func FromHost(ctx context.Context, host *object.HostSystem, apiTimeout time.Duration) (*object.HostVsanSystem, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
 	defer cancel()
 	return host.ConfigManager().VsanSystem(ctx)
 }
 
 // RemoveDiskMapping removes the disks specified in diskMap from the disk group
 // on host.
-func RemoveDiskMapping(client *govmomi.Client, host *object.HostSystem, hvs *object.HostVsanSystem, diskMap *types.VsanHostDiskMapping, apiTimeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+func RemoveDiskMapping(ctx context.Context, client *govmomi.Client, host *object.HostSystem, hvs *object.HostVsanSystem, diskMap *types.VsanHostDiskMapping, apiTimeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
 	defer cancel()
 
 	// If the SSD name is set, then the whole disk group needs to be removed using
@@ -54,7 +55,7 @@ func RemoveDiskMapping(client *govmomi.Client, host *object.HostSystem, hvs *obj
 			return err
 		}
 		task := object.NewTask(client.Client, resp.Returnval)
-		if err := task.WaitEx(ctx); err != nil {
+		if err := viapi.WaitForTask(ctx, task); err != nil {
 			return err
 		}
 	} else {
@@ -68,7 +69,7 @@ func RemoveDiskMapping(client *govmomi.Client, host *object.HostSystem, hvs *obj
 			return err
 		}
 		task := object.NewTask(client.Client, resp.Returnval)
-		if err := task.WaitEx(ctx); err != nil {
+		if err := viapi.WaitForTask(ctx, task); err != nil {
 			return err
 		}
 	}
@@ -76,8 +77,8 @@ func RemoveDiskMapping(client *govmomi.Client, host *object.HostSystem, hvs *obj
 }
 
 // InitializeDisks initializes and adds disks to the specified host disk group.
-func InitializeDisks(client *govmomi.Client, host *object.HostSystem, hvs *object.HostVsanSystem, diskMap *types.VsanHostDiskMapping, apiTimeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+func InitializeDisks(ctx context.Context, client *govmomi.Client, host *object.HostSystem, hvs *object.HostVsanSystem, diskMap *types.VsanHostDiskMapping, apiTimeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
 	defer cancel()
 
 	ntask := types.InitializeDisks_Task{
@@ -90,5 +91,5 @@ func InitializeDisks(client *govmomi.Client, host *object.HostSystem, hvs *objec
 		return err
 	}
 	task := object.NewTask(client.Client, resp.Returnval)
-	return task.WaitEx(ctx)
+	return viapi.WaitForTask(ctx, task)
 }
@@ -5,6 +5,7 @@
 package virtualdevice
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -76,7 +77,7 @@ func NewCdromSubresource(client *govmomi.Client, rdd resourceDataDiff, d, old ma
 // operation. All disk operations are carried out, with both the complete,
 // updated, VirtualDeviceList, and the complete list of changes returned as a
 // slice of BaseVirtualDeviceConfigSpec.
-func CdromApplyOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func CdromApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] CdromApplyOperation: Beginning apply operation")
 	// While we are currently only restricting CD devices to one device, we have
 	// to actually account for the fact that someone could add multiple CD drives
@@ -129,7 +130,7 @@ nextOld:
 				continue
 			}
 			r := NewCdromSubresource(c, d, nm, om, n)
-			uspec, err := r.Update(l)
+			uspec, err := r.Update(ctx, l)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 			}
@@ -140,7 +141,7 @@ nextOld:
 		}
 		// New device
 		r := NewCdromSubresource(c, d, nm, nil, n)
-		cspec, err := r.Create(l)
+		cspec, err := r.Create(ctx, l)
 		if err != nil {
 			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 		}
@@ -286,7 +287,7 @@ func CdromRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.V
 // This differs from a regular apply operation in that a configuration is
 // already present, but we don't have any existing state, which the standard
 // virtual device operations rely pretty heavily on.
-func CdromPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func CdromPostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] CdromPostCloneOperation: Looking for post-clone device changes")
 	// While we are currently only restricting CD devices to one device, we have
 	// to actually account for the fact that someone could add multiple CD drives
@@ -339,7 +340,7 @@ func CdromPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object
 		if i > len(srcSet)-1 {
 			// New device
 			r := NewCdromSubresource(c, d, cm, nil, i)
-			cspec, err := r.Create(l)
+			cspec, err := r.Create(ctx, l)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 			}
@@ -361,7 +362,7 @@ func CdromPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object
 		r := NewCdromSubresource(c, d, nm, sm, i)
 		if !reflect.DeepEqual(sm, nm) {
 			// Update
-			cspec, err := r.Update(l)
+			cspec, err := r.Update(ctx, l)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 			}
@@ -415,7 +416,7 @@ func (r *CdromSubresource) ValidateDiff() error {
 }
 
 // Create creates a vsphere_virtual_machine cdrom sub-resource.
-func (r *CdromSubresource) Create(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+func (r *CdromSubresource) Create(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] %s: Running create", r)
 	err := r.ValidateDiff()
 	if err != nil {
@@ -434,7 +435,7 @@ func (r *CdromSubresource) Create(l object.VirtualDeviceList) ([]types.BaseVirtu
 		return nil, err
 	}
 	// Map the CDROM to the correct device
-	err = r.mapCdrom(device, l)
+	err = r.mapCdrom(ctx, device, l)
 	if err != nil {
 		return nil, err
 	}
@@ -503,7 +504,7 @@ func (r *CdromSubresource) Read(l object.VirtualDeviceList) error {
 }
 
 // Update updates a vsphere_virtual_machine cdrom sub-resource.
-func (r *CdromSubresource) Update(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+func (r *CdromSubresource) Update(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] %s: Beginning update", r)
 	err := r.ValidateDiff()
 	if err != nil {
@@ -519,7 +520,7 @@ func (r *CdromSubresource) Update(l object.VirtualDeviceList) ([]types.BaseVirtu
 	}
 
 	// Map the CDROM to the correct device
-	err = r.mapCdrom(device, l)
+	err = r.mapCdrom(ctx, device, l)
 	if err != nil {
 		return nil, err
 	}
@@ -553,18 +554,18 @@ func (r *CdromSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtu
 }
 
 // mapCdrom takes a CdromSubresource and attaches either a client device or a datastore ISO.
-func (r *CdromSubresource) mapCdrom(device *types.VirtualCdrom, l object.VirtualDeviceList) error {
+func (r *CdromSubresource) mapCdrom(ctx context.Context, device *types.VirtualCdrom, l object.VirtualDeviceList) error {
 	dsID := r.Get("datastore_id").(string)
 	path := r.Get("path").(string)
 	clientDevice := r.Get("client_device").(bool)
 	switch {
 	case dsID != "" && path != "":
 		// If the datastore ID and path are both set, the CDROM will be mapped to a file on a datastore.
-		ds, err := datastore.FromID(r.client, dsID)
+		ds, err := datastore.FromID(ctx, r.client, dsID)
 		if err != nil {
 			return fmt.Errorf("cannot find datastore: %s", err)
 		}
-		dsProps, err := datastore.Properties(ds)
+		dsProps, err := datastore.Properties(ctx, ds)
 		if err != nil {
 			return fmt.Errorf("could not get properties for datastore: %s", err)
 		}
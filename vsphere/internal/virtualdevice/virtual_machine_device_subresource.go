@@ -16,9 +16,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/computeresource"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
 )
@@ -28,6 +30,12 @@ const (
 	subresourceTypeNetworkInterface = "network_interface"
 	subresourceTypeCdrom            = "cdrom"
 	subresourceTypeVideoCard        = "video_card"
+	subresourceTypeSerialPort       = "serial_port"
+	subresourceTypeParallelPort     = "parallel_port"
+	subresourceTypeUsbController    = "usb_controller"
+	subresourceTypeUsbDevice        = "usb_device"
+	subresourceTypeDynamicPciDevice = "dynamic_pci_device"
+	subresourceTypeVgpuProfile      = "vgpu_profile"
 )
 
 const (
@@ -65,6 +73,18 @@ const (
 	// SubresourceControllerTypeNVME is a string representation of NVMe controller
 	// type.
 	SubresourceControllerTypeNVME = "nvme"
+
+	// SubresourceControllerTypeSIO is a string representation of the super I/O
+	// controller that serial and parallel ports are attached to.
+	SubresourceControllerTypeSIO = "sio"
+
+	// SubresourceControllerTypeEHCI is a string representation of the USB 2.0
+	// (EHCI) controller type.
+	SubresourceControllerTypeEHCI = "ehci"
+
+	// SubresourceControllerTypeXHCI is a string representation of the USB 3.x
+	// (xHCI) controller type.
+	SubresourceControllerTypeXHCI = "xhci"
 )
 
 const (
@@ -83,6 +103,9 @@ var subresourceIDControllerTypeAllowedValues = []string{
 	SubresourceControllerTypePCI,
 	SubresourceControllerTypeSATA,
 	SubresourceControllerTypeNVME,
+	SubresourceControllerTypeSIO,
+	SubresourceControllerTypeEHCI,
+	SubresourceControllerTypeXHCI,
 }
 
 var sharesLevelAllowedValues = []string{
@@ -158,6 +181,12 @@ func controllerTypeToClass(c types.BaseVirtualController) (string, error) {
 		t = SubresourceControllerTypeSCSI
 	case *types.VirtualNVMEController:
 		t = SubresourceControllerTypeNVME
+	case *types.VirtualSIOController:
+		t = SubresourceControllerTypeSIO
+	case *types.VirtualUSBController:
+		t = SubresourceControllerTypeEHCI
+	case *types.VirtualUSBXHCIController:
+		t = SubresourceControllerTypeXHCI
 	default:
 		return subresourceControllerTypeUnknown, fmt.Errorf("unsupported controller type %T", c)
 	}
@@ -428,6 +457,18 @@ func findVirtualDeviceInListControllerSelectFunc(ct string, cb int) func(types.B
 			if _, ok := device.(*types.VirtualNVMEController); !ok {
 				return false
 			}
+		case SubresourceControllerTypeSIO:
+			if _, ok := device.(*types.VirtualSIOController); !ok {
+				return false
+			}
+		case SubresourceControllerTypeEHCI:
+			if _, ok := device.(*types.VirtualUSBController); !ok {
+				return false
+			}
+		case SubresourceControllerTypeXHCI:
+			if _, ok := device.(*types.VirtualUSBXHCIController); !ok {
+				return false
+			}
 		}
 		vc := device.(types.BaseVirtualController).GetVirtualController()
 		if cb <= math.MaxInt32 && vc.BusNumber == int32(cb) {
@@ -802,6 +843,18 @@ func pickController(l object.VirtualDeviceList, bus int, ct string) (types.BaseV
 			if ct == SubresourceControllerTypeNVME {
 				return d.GetVirtualController().BusNumber == int32(bus)
 			}
+		case *types.VirtualSIOController:
+			if ct == SubresourceControllerTypeSIO {
+				return d.GetVirtualController().BusNumber == int32(bus)
+			}
+		case *types.VirtualUSBController:
+			if ct == SubresourceControllerTypeEHCI {
+				return d.GetVirtualController().BusNumber == int32(bus)
+			}
+		case *types.VirtualUSBXHCIController:
+			if ct == SubresourceControllerTypeXHCI {
+				return d.GetVirtualController().BusNumber == int32(bus)
+			}
 		}
 		return false
 	})
@@ -987,12 +1040,12 @@ func AppendDeviceChangeSpec(
 }
 
 // getHostPciDevice returns a HostPciDevice from a host based on the DeviceId.
-func (c *pciApplyConfig) getHostPciDevice(id string) (*types.HostPciDevice, error) {
-	host, err := hostsystem.FromID(c.Client, c.ResourceData.Get("host_system_id").(string))
+func (c *pciApplyConfig) getHostPciDevice(ctx context.Context, id string) (*types.HostPciDevice, error) {
+	host, err := hostsystem.FromID(ctx, c.Client, c.ResourceData.Get("host_system_id").(string))
 	if err != nil {
 		return nil, err
 	}
-	hprops, err := hostsystem.Properties(host)
+	hprops, err := hostsystem.Properties(ctx, host)
 	if err != nil {
 		return nil, err
 	}
@@ -1006,17 +1059,17 @@ func (c *pciApplyConfig) getHostPciDevice(id string) (*types.HostPciDevice, erro
 
 // getPciSysId fetchs the PCI SystemId of a host. The SystemId is required for
 // PCI passthrough devices.
-func (c *pciApplyConfig) getPciSysID() error {
-	host, err := hostsystem.FromID(c.Client, c.ResourceData.Get("host_system_id").(string))
+func (c *pciApplyConfig) getPciSysID(ctx context.Context) error {
+	host, err := hostsystem.FromID(ctx, c.Client, c.ResourceData.Get("host_system_id").(string))
 	if err != nil {
 		return err
 	}
 	hostRef := host.Reference()
-	e, err := computeresource.EnvironmentBrowserFromReference(c.Client, hostRef)
+	e, err := computeresource.EnvironmentBrowserFromReference(ctx, c.Client, hostRef)
 	if err != nil {
 		return err
 	}
-	sysID, err := e.SystemID(context.TODO(), &hostRef)
+	sysID, err := e.SystemID(ctx, &hostRef)
 	if err != nil {
 		return err
 	}
@@ -1026,11 +1079,11 @@ func (c *pciApplyConfig) getPciSysID() error {
 
 // modifyVirtualPciDevices will take a list of devices and an operation and
 // will create the appropriate config spec.
-func (c *pciApplyConfig) modifyVirtualPciDevices(devList *schema.Set, op types.VirtualDeviceConfigSpecOperation) error {
+func (c *pciApplyConfig) modifyVirtualPciDevices(ctx context.Context, devList *schema.Set, op types.VirtualDeviceConfigSpecOperation) error {
 	log.Printf("VirtualMachine: Creating PCI passthrough device specs %v", op)
 	for _, addDev := range devList.List() {
 		log.Printf("[DEBUG] modifyVirtualPciDevices: Appending %v spec for %s", op, addDev.(string))
-		pciDev, err := c.getHostPciDevice(addDev.(string))
+		pciDev, err := c.getHostPciDevice(ctx, addDev.(string))
 		if err != nil {
 			return err
 		}
@@ -1046,18 +1099,18 @@ func (c *pciApplyConfig) modifyVirtualPciDevices(devList *schema.Set, op types.V
 				Key: c.VirtualDevice.NewKey(),
 			},
 		}
-		vm, err := virtualmachine.FromUUID(c.Client, c.ResourceData.Id())
+		vm, err := virtualmachine.FromUUID(ctx, c.Client, c.ResourceData.Id())
 		if err != nil {
 			return err
 		}
-		vprops, err := virtualmachine.Properties(vm)
+		vprops, err := virtualmachine.Properties(ctx, vm)
 		if err != nil {
 			return err
 		}
 		// This will only find a device for delete operations.
 		for _, vmDevP := range vprops.Config.Hardware.Device {
 			if vmDev, ok := vmDevP.(*types.VirtualPCIPassthrough); ok {
-				if vmDev.Backing.(*types.VirtualPCIPassthroughDeviceBackingInfo).Id == pciDev.Id {
+				if backing, ok := vmDev.Backing.(*types.VirtualPCIPassthroughDeviceBackingInfo); ok && backing.Id == pciDev.Id {
 					dev = vmDev
 				}
 			}
@@ -1076,7 +1129,7 @@ func (c *pciApplyConfig) modifyVirtualPciDevices(devList *schema.Set, op types.V
 // PciPassthroughApplyOperation checks for changes in a virtual machine's
 // PCI passthrough devices and creates config specs to apply apply to the
 // virtual machine.
-func PciPassthroughApplyOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func PciPassthroughApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	old, newValue := d.GetChange("pci_device_id")
 	oldDevIDs := old.(*schema.Set)
 	newDevIDs := newValue.(*schema.Set)
@@ -1094,19 +1147,19 @@ func PciPassthroughApplyOperation(d *schema.ResourceData, c *govmomi.Client, l o
 	}
 
 	_ = d.Set("reboot_required", true)
-	err := applyConfig.getPciSysID()
+	err := applyConfig.getPciSysID(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Add new PCI passthrough devices
-	err = applyConfig.modifyVirtualPciDevices(addDevs, types.VirtualDeviceConfigSpecOperationAdd)
+	err = applyConfig.modifyVirtualPciDevices(ctx, addDevs, types.VirtualDeviceConfigSpecOperationAdd)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Remove deleted PCI passthrough devices
-	err = applyConfig.modifyVirtualPciDevices(delDevs, types.VirtualDeviceConfigSpecOperationRemove)
+	err = applyConfig.modifyVirtualPciDevices(ctx, delDevs, types.VirtualDeviceConfigSpecOperationRemove)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1116,7 +1169,7 @@ func PciPassthroughApplyOperation(d *schema.ResourceData, c *govmomi.Client, l o
 // PciPassthroughPostCloneOperation normalizes the PCI passthrough devices
 // on a newly-cloned virtual machine and outputs any necessary device change
 // operations. It also sets the state in advance of the post-create read.
-func PciPassthroughPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func PciPassthroughPostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	old, newValue := d.GetChange("pci_device_id")
 	oldDevIDs := old.(*schema.Set)
 	newDevIDs := newValue.(*schema.Set)
@@ -1133,19 +1186,19 @@ func PciPassthroughPostCloneOperation(d *schema.ResourceData, c *govmomi.Client,
 		return applyConfig.VirtualDevice, applyConfig.Spec, nil
 	}
 
-	err := applyConfig.getPciSysID()
+	err := applyConfig.getPciSysID(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Add new PCI passthrough devices
-	err = applyConfig.modifyVirtualPciDevices(addDevs, types.VirtualDeviceConfigSpecOperationAdd)
+	err = applyConfig.modifyVirtualPciDevices(ctx, addDevs, types.VirtualDeviceConfigSpecOperationAdd)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Remove deleted PCI passthrough devices
-	err = applyConfig.modifyVirtualPciDevices(delDevs, types.VirtualDeviceConfigSpecOperationRemove)
+	err = applyConfig.modifyVirtualPciDevices(ctx, delDevs, types.VirtualDeviceConfigSpecOperationRemove)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1202,3 +1255,51 @@ func VtpmApplyOperation(d *schema.ResourceData, l object.VirtualDeviceList) (obj
 	l = applyDeviceChange(l, specs)
 	return l, specs, nil
 }
+
+// placementHostsKnown returns true if the hosts that a virtual machine can be
+// placed on are known at diff time.
+func placementHostsKnown(d *schema.ResourceDiff) bool {
+	if !d.NewValueKnown("host_system_id") {
+		return false
+	}
+	if d.Get("host_system_id").(string) != "" {
+		return true
+	}
+	return d.NewValueKnown("resource_pool_id") && d.Get("resource_pool_id").(string) != ""
+}
+
+// placementHosts returns the hosts that a virtual machine can be placed on. This is the host in host_system_id if it is set, or all hosts in the
+// cluster or standalone host that owns the resource pool.
+func placementHosts(ctx context.Context, client *govmomi.Client, d resourceDataDiff) ([]*mo.HostSystem, error) {
+	var refs []types.ManagedObjectReference
+	if id := d.Get("host_system_id").(string); id != "" {
+		refs = append(refs, types.ManagedObjectReference{Type: "HostSystem", Value: id})
+	} else {
+		pool, err := resourcepool.FromID(ctx, client, d.Get("resource_pool_id").(string))
+		if err != nil {
+			return nil, fmt.Errorf("could not find resource pool: %s", err)
+		}
+		pprops, err := resourcepool.Properties(ctx, pool)
+		if err != nil {
+			return nil, fmt.Errorf("could not get properties for resource pool: %s", err)
+		}
+		cprops, err := computeresource.BasePropertiesFromReference(ctx, client, pprops.Owner)
+		if err != nil {
+			return nil, fmt.Errorf("could not get properties for compute resource: %s", err)
+		}
+		refs = cprops.Host
+	}
+	var hosts []*mo.HostSystem
+	for _, ref := range refs {
+		host, err := hostsystem.FromID(ctx, client, ref.Value)
+		if err != nil {
+			return nil, err
+		}
+		hprops, err := hostsystem.Properties(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, hprops)
+	}
+	return hosts, nil
+}
@@ -5,12 +5,14 @@
 package virtualdevice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"math"
 	"path"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -18,8 +20,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/datastore"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/firstclassdisk"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/spbm"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/storagepod"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -59,6 +64,15 @@ var diskSubresourceSharingAllowedValues = []string{
 	string(types.VirtualDiskSharingSharingMultiWriter),
 }
 
+var diskSubresourceCompatibilityModeAllowedValues = []string{
+	string(types.VirtualDiskCompatibilityModePhysicalMode),
+	string(types.VirtualDiskCompatibilityModeVirtualMode),
+}
+
+// diskRdmLunRegexp matches the canonical name of a LUN that can be used for a
+// raw device mapping.
+var diskRdmLunRegexp = regexp.MustCompile(`^(naa|eui|t10)\.[0-9A-Za-z_.\-]+$`)
+
 // DiskSubresourceSchema represents the schema for the disk sub-resource.
 func DiskSubresourceSchema() map[string]*schema.Schema {
 	s := map[string]*schema.Schema{
@@ -121,6 +135,21 @@ func DiskSubresourceSchema() map[string]*schema.Schema {
 			Description: "The UUID of the virtual disk.",
 		},
 
+		// VirtualDiskRawDiskMappingVer1BackingInfo
+		"rdm_lun": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The canonical name of a LUN, such as naa.600a0b80001234, to map to this disk as a raw device mapping (RDM). The LUN must be visible to the host of the virtual machine.",
+			ValidateFunc: validation.StringMatch(diskRdmLunRegexp, "must be the canonical name of a LUN, such as naa.600a0b80001234"),
+		},
+		"rdm_compatibility_mode": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			Description:  "The compatibility mode of a raw device mapping. Can be one of physicalMode or virtualMode. Defaults to physicalMode when rdm_lun is set.",
+			ValidateFunc: validation.StringInSlice(diskSubresourceCompatibilityModeAllowedValues, false),
+		},
+
 		// StorageIOAllocationInfo
 		"io_limit": {
 			Type:         schema.TypeInt,
@@ -191,6 +220,11 @@ func DiskSubresourceSchema() map[string]*schema.Schema {
 			ConflictsWith: []string{"datastore_cluster_id"},
 			Description:   "If this is true, the disk is attached instead of created. Implies keep_on_remove.",
 		},
+		"first_class_disk_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The ID of a first class disk to attach. Requires attach to be set to true and datastore_id to be set to the datastore of the first class disk.",
+		},
 		"storage_policy_id": {
 			Type:        schema.TypeString,
 			Optional:    true,
@@ -216,6 +250,20 @@ type DiskSubresource struct {
 	// The set hash for the device as it exists when NewDiskSubresource is
 	// called.
 	ID int
+
+	// The LUNs of the host of the virtual machine, shared between the disks
+	// read in a single operation.
+	luns *hostLunCache
+}
+
+// hostLunCache holds the SCSI LUNs of the host that a virtual machine is
+// running on. The host is looked up the first time a raw device mapping needs
+// to be resolved, and the LUNs are then reused for every other raw device
+// mapping read in the same operation.
+type hostLunCache struct {
+	loaded bool
+	host   string
+	luns   []types.BaseScsiLun
 }
 
 // NewDiskSubresource returns a subresource populated with all the necessary
@@ -243,7 +291,7 @@ func NewDiskSubresource(client *govmomi.Client, resourceDataDiff resourceDataDif
 // operation. All disk operations are carried out, with both the complete,
 // updated, VirtualDeviceList, and the complete list of changes returned as a
 // slice of BaseVirtualDeviceConfigSpec.
-func DiskApplyOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func DiskApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] DiskApplyOperation: Beginning apply operation")
 	o, n := d.GetChange(subresourceTypeDisk)
 	oldDisks := o.([]interface{})
@@ -269,7 +317,7 @@ func DiskApplyOperation(d *schema.ResourceData, c *govmomi.Client, l object.Virt
 	log.Printf("[DEBUG] DiskApplyOperation: Resources not being changed: %s", subresourceListString(updates))
 	for ni, ne := range newDisks {
 		nm := ne.(map[string]interface{})
-		if err := diskApplyOperationCreateUpdate(ni, nm, oldDisks, c, d, &l, &spec, &updates); err != nil {
+		if err := diskApplyOperationCreateUpdate(ctx, ni, nm, oldDisks, c, d, &l, &spec, &updates); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -326,6 +374,7 @@ func diskApplyOperationDelete(
 // diskApplyOperationCreateUpdate is an inner-loop helper for disk creation and
 // update operations.
 func diskApplyOperationCreateUpdate(
+	ctx context.Context,
 	index int,
 	newData map[string]interface{},
 	oldDataSet []interface{},
@@ -373,7 +422,7 @@ func diskApplyOperationCreateUpdate(
 	}
 	// New data was not found - this is a create operation
 	r := NewDiskSubresource(c, d, newData, nil, index)
-	cspec, err := r.Create(*l)
+	cspec, err := r.Create(ctx, *l)
 	if err != nil {
 		return fmt.Errorf("%s: %s", r.Addr(), err)
 	}
@@ -383,12 +432,34 @@ func diskApplyOperationCreateUpdate(
 	return nil
 }
 
+// DiskStoragePolicyIDs returns the storage_policy_id of each disk in the
+// resource that has one set, keyed by the device key of the disk in the
+// supplied device list. Disks that cannot be located in the list are skipped.
+func DiskStoragePolicyIDs(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) map[int32]string {
+	ids := make(map[int32]string)
+	for i, item := range d.Get(subresourceTypeDisk).([]interface{}) {
+		m := item.(map[string]interface{})
+		policyID, _ := m["storage_policy_id"].(string)
+		if policyID == "" {
+			continue
+		}
+		r := NewDiskSubresource(c, d, m, m, i)
+		device, err := r.findVirtualDiskByUUIDOrAddress(l, true)
+		if err != nil {
+			log.Printf("[DEBUG] %s: Cannot locate disk to read its storage policy: %s", r.Addr(), err)
+			continue
+		}
+		ids[device.GetVirtualDevice().Key] = policyID
+	}
+	return ids
+}
+
 // DiskRefreshOperation processes a refresh operation for all the disks in
 // the resource.
 //
 // This functions similar to DiskApplyOperation, but nothing to change is
 // returned, all necessary values are just set and committed to state.
-func DiskRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+func DiskRefreshOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
 	log.Printf("[DEBUG] DiskRefreshOperation: Beginning refresh")
 	devices := SelectDisks(
 		l,
@@ -400,6 +471,7 @@ func DiskRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.Vi
 	log.Printf("[DEBUG] DiskRefreshOperation: Disk devices located: %s", DeviceListString(devices))
 	curSet := d.Get(subresourceTypeDisk).([]interface{})
 	log.Printf("[DEBUG] DiskRefreshOperation: Current resource set from state: %s", subresourceListString(curSet))
+	luns := new(hostLunCache)
 	var newSet []interface{}
 	// First check for negative keys. These are freshly added devices that are
 	// usually coming into read post-create.
@@ -411,7 +483,8 @@ func DiskRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.Vi
 		m := item.(map[string]interface{})
 		if m["key"].(int) < 1 {
 			r := NewDiskSubresource(c, d, m, nil, i)
-			if err := r.Read(l); err != nil {
+			r.luns = luns
+			if err := r.Read(ctx, l); err != nil {
 				return fmt.Errorf("%s: %s", r.Addr(), err)
 			}
 			if r.Get("key").(int) < 1 {
@@ -450,7 +523,8 @@ func DiskRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.Vi
 			}
 			// We should have our device -> resource match, so read now.
 			r := NewDiskSubresource(c, d, m, nil, n)
-			if err := r.Read(l); err != nil {
+			r.luns = luns
+			if err := r.Read(ctx, l); err != nil {
 				return fmt.Errorf("%s: %s", r.Addr(), err)
 			}
 
@@ -491,7 +565,8 @@ func DiskRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.Vi
 			m["keep_on_remove"] = true
 		}
 		r := NewDiskSubresource(c, d, m, nil, len(newSet))
-		if err := r.Read(l); err != nil {
+		r.luns = luns
+		if err := r.Read(ctx, l); err != nil {
 			return fmt.Errorf("%s: %s", r.Addr(), err)
 		}
 		// Add a generic label indicating that this disk is orphaned.
@@ -556,13 +631,14 @@ func DiskDestroyOperation(d *schema.ResourceData, c *govmomi.Client, l object.Vi
 //
 // * Ensuring all names are unique across the set.
 // * Ensuring that at least one element in the set has a unit_number of 0.
-func DiskDiffOperation(d *schema.ResourceDiff, c *govmomi.Client) error {
+func DiskDiffOperation(ctx context.Context, d *schema.ResourceDiff, c *govmomi.Client) error {
 	log.Printf("[DEBUG] DiskDiffOperation: Beginning disk diff customization")
 	o, n := d.GetChange(subresourceTypeDisk)
 	// Some global validation first. We handle individual validation later.
 	log.Printf("[DEBUG] DiskDiffOperation: Beginning collective diff validation (indexes aligned to new config)")
 	names := make(map[string]struct{})
 	attachments := make(map[string]struct{})
+	luns := make(map[string]struct{})
 	scsiUnits := make(map[int]struct{})
 	sataUnits := make(map[int]struct{})
 	ideUnits := make(map[int]struct{})
@@ -582,7 +658,18 @@ func DiskDiffOperation(d *schema.ResourceDiff, c *govmomi.Client) error {
 		// If attach is set, we need to validate that there's no other duplicate paths.
 		curDiskPath := fmt.Sprintf("disk.%d.path", ni)
 		pathKnown := d.NewValueKnown(curDiskPath)
-		if nm["attach"].(bool) {
+		// First class disks are attached by ID, which may not be known yet if the
+		// disk is created in the same plan.
+		fcdID, _ := nm["first_class_disk_id"].(string)
+		fcdKnown := d.NewValueKnown(fmt.Sprintf("disk.%d.first_class_disk_id", ni))
+		if nm["attach"].(bool) && (fcdID != "" || !fcdKnown) {
+			if fcdID != "" {
+				if _, ok := attachments[fcdID]; ok {
+					return fmt.Errorf("disk: multiple entries trying to attach first class disk %s", fcdID)
+				}
+				attachments[fcdID] = struct{}{}
+			}
+		} else if nm["attach"].(bool) {
 			diskPath := getDiskPath(nm)
 			if pathKnown {
 				if diskPath == "" {
@@ -596,6 +683,13 @@ func DiskDiffOperation(d *schema.ResourceDiff, c *govmomi.Client) error {
 				log.Printf("[DEBUG] Disk path for disk %d is not known yet.", ni)
 			}
 		}
+		// A LUN can only be mapped to one disk.
+		if lun, ok := nm["rdm_lun"].(string); ok && lun != "" {
+			if _, ok := luns[lun]; ok {
+				return fmt.Errorf("disk: multiple entries trying to map LUN %s", lun)
+			}
+			luns[lun] = struct{}{}
+		}
 
 		switch nm["controller_type"] {
 		case "scsi":
@@ -634,6 +728,10 @@ func DiskDiffOperation(d *schema.ResourceDiff, c *govmomi.Client) error {
 		return errors.New("at least one disk must have a unit_number of 0 for SATA, SCSI, NVMe or 1 for IDE")
 	}
 
+	if err := diskRdmLunDiffOperation(ctx, d, c, o.([]interface{}), n.([]interface{})); err != nil {
+		return err
+	}
+
 	// Perform the normalization here.
 	log.Printf("[DEBUG] DiskDiffOperation: Beginning diff validation and normalization (indexes aligned to old state)")
 	ods := o.([]interface{})
@@ -656,7 +754,7 @@ nextNew:
 			// We extrapolate using the label as a "primary key" of sorts.
 			if nname == oname {
 				r := NewDiskSubresource(c, d, nm, om, oi)
-				if err := r.DiffExisting(); err != nil {
+				if err := r.DiffExisting(ctx); err != nil {
 					return fmt.Errorf("%s: %s", r.Addr(), err)
 				}
 				normalized[oi] = r.Data()
@@ -719,7 +817,7 @@ nextNew:
 // This function is meant to be called during diff customization. It is a
 // subset of the normal refresh behaviour as we don't worry about checking
 // existing state.
-func DiskCloneValidateOperation(d *schema.ResourceDiff, c *govmomi.Client, l object.VirtualDeviceList, linked bool) error {
+func DiskCloneValidateOperation(ctx context.Context, d *schema.ResourceDiff, c *govmomi.Client, l object.VirtualDeviceList, linked bool) error {
 	log.Printf("[DEBUG] DiskCloneValidateOperation: Checking existing virtual disk configuration")
 	devices := SelectDisks(
 		l,
@@ -765,7 +863,12 @@ func DiskCloneValidateOperation(d *schema.ResourceDiff, c *govmomi.Client, l obj
 			return fmt.Errorf("error computing device address: %s", err)
 		}
 		r := NewDiskSubresource(c, d, m, nil, i)
-		if err := r.Read(l); err != nil {
+		// vSphere copies the data of a raw device mapping into a new virtual
+		// disk when cloning, which would not match the configuration.
+		if _, ok := device.(*types.VirtualDisk).Backing.(*types.VirtualDiskRawDiskMappingVer1BackingInfo); ok {
+			return fmt.Errorf("%s: raw device mapping disks cannot be cloned", r.Addr())
+		}
+		if err := r.Read(ctx, l); err != nil {
 			return fmt.Errorf("%s: validation failed (%s)", r.Addr(), err)
 		}
 		// Load the target resource to do a few comparisons for correctness in config.
@@ -796,11 +899,11 @@ func DiskCloneValidateOperation(d *schema.ResourceDiff, c *govmomi.Client, l obj
 		case linked:
 			switch {
 			case sourceSize != targetSize:
-				return fmt.Errorf("%s: disk name %s must be the exact size of source when using linked_clone (expected: %d GiB)", tr.Addr(), targetName, sourceSize)
+				return fmt.Errorf("%s: disk name %s must be the exact size of source when using linked_clone or instant_clone (expected: %d GiB)", tr.Addr(), targetName, sourceSize)
 			case sourceThin != targetThin:
-				return fmt.Errorf("%s: disk name %s must have same value for thin_provisioned as source when using linked_clone (expected: %t)", tr.Addr(), targetName, sourceThin)
+				return fmt.Errorf("%s: disk name %s must have same value for thin_provisioned as source when using linked_clone or instant_clone (expected: %t)", tr.Addr(), targetName, sourceThin)
 			case sourceEager != targetEager:
-				return fmt.Errorf("%s: disk name %s must have same value for eagerly_scrub as source when using linked_clone (expected: %t)", tr.Addr(), targetName, sourceEager)
+				return fmt.Errorf("%s: disk name %s must have same value for eagerly_scrub as source when using linked_clone or instant_clone (expected: %t)", tr.Addr(), targetName, sourceEager)
 			}
 		default:
 			if sourceSize > targetSize {
@@ -824,7 +927,7 @@ func DiskCloneValidateOperation(d *schema.ResourceDiff, c *govmomi.Client, l obj
 // DiskMigrateRelocateOperation assembles the
 // VirtualMachineRelocateSpecDiskLocator slice for a virtual machine migration
 // operation, otherwise known as storage vMotion.
-func DiskMigrateRelocateOperation(data *schema.ResourceData, client *govmomi.Client, deviceList object.VirtualDeviceList) ([]types.VirtualMachineRelocateSpecDiskLocator, bool, error) {
+func DiskMigrateRelocateOperation(ctx context.Context, data *schema.ResourceData, client *govmomi.Client, deviceList object.VirtualDeviceList) ([]types.VirtualMachineRelocateSpecDiskLocator, bool, error) {
 	log.Printf("[DEBUG] DiskMigrateRelocateOperation: Generating any necessary disk relocate specs")
 	oldDisks, newDisks := data.GetChange(subresourceTypeDisk)
 
@@ -869,7 +972,7 @@ func DiskMigrateRelocateOperation(data *schema.ResourceData, client *govmomi.Cli
 					newDisk["datastore_id"] = data.Get("datastore_id")
 				}
 				diskSubresource := NewDiskSubresource(client, data, newDisk, oldDisk, newDiskIndex)
-				relocator, err := diskSubresource.Relocate(deviceList, false)
+				relocator, err := diskSubresource.Relocate(ctx, deviceList, false)
 				if err != nil {
 					return nil, false, fmt.Errorf("%s: %s", diskSubresource.Addr(), err)
 				}
@@ -903,7 +1006,7 @@ func DiskMigrateRelocateOperation(data *schema.ResourceData, client *govmomi.Cli
 // backing data defined in config, taking on these filenames when cloned. After
 // the clone is complete, natural re-configuration happens to bring the disk
 // configurations fully in sync with what is defined.
-func DiskCloneRelocateOperation(resourceData *schema.ResourceData, client *govmomi.Client, deviceList object.VirtualDeviceList) ([]types.VirtualMachineRelocateSpecDiskLocator, error) {
+func DiskCloneRelocateOperation(ctx context.Context, resourceData *schema.ResourceData, client *govmomi.Client, deviceList object.VirtualDeviceList) ([]types.VirtualMachineRelocateSpecDiskLocator, error) {
 	log.Printf("[DEBUG] DiskCloneRelocateOperation: Generating full disk relocate spec list")
 	devices := SelectDisks(
 		deviceList,
@@ -959,7 +1062,7 @@ func DiskCloneRelocateOperation(resourceData *schema.ResourceData, client *govmo
 
 		r = addDiskDatastore(r, resourceData)
 		// Otherwise, proceed with generating and appending the locator.
-		relocator, err := r.Relocate(deviceList, true)
+		relocator, err := r.Relocate(ctx, deviceList, true)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %s", r.Addr(), err)
 		}
@@ -1050,6 +1153,9 @@ func virtualDiskToSchemaPropsMap(disk *types.VirtualDisk) map[string]interface{}
 		m["datastore_id"] = backing.Datastore.Value
 		m["disk_mode"] = backing.DiskMode
 		m["write_through"] = backing.WriteThrough
+	} else if backing, ok := disk.Backing.(*types.VirtualDiskRawDiskMappingVer1BackingInfo); ok && backing.Datastore != nil {
+		m["datastore_id"] = backing.Datastore.Value
+		m["disk_mode"] = backing.DiskMode
 	}
 
 	return m
@@ -1092,7 +1198,7 @@ func diskDataToSchemaProps(d *schema.ResourceData, deviceIndex int) map[string]i
 // This differs from a regular apply operation in that a configuration is
 // already present, but we don't have any existing state, which the standard
 // virtual device operations rely pretty heavily on.
-func DiskPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList, postOvf bool) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func DiskPostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList, postOvf bool) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] DiskPostCloneOperation: Looking for disk device changes post-clone")
 	devices := SelectDisks(
 		l,
@@ -1116,6 +1222,7 @@ func DiskPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.
 	sort.Sort(virtualDiskSubresourceSorter(curSet))
 	log.Printf("[DEBUG] DiskPostCloneOperation: Resource set order after sort: %s", subresourceListString(curSet))
 
+	luns := new(hostLunCache)
 	var spec []types.BaseVirtualDeviceConfigSpec
 	var updates []interface{}
 
@@ -1144,7 +1251,8 @@ func DiskPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.
 		// product of this set with the source, creating a diff.
 		old := structure.CopyMap(src)
 		rOld := NewDiskSubresource(c, d, old, nil, i)
-		if err := rOld.Read(l); err != nil {
+		rOld.luns = luns
+		if err := rOld.Read(ctx, l); err != nil {
 			return nil, nil, fmt.Errorf("%s: %s", rOld.Addr(), err)
 		}
 		newValue := structure.CopyMap(rOld.Data())
@@ -1182,7 +1290,7 @@ func DiskPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.
 	if len(devices) <= len(curSet) {
 		for _, ni := range curSet[len(devices):] {
 			r := NewDiskSubresource(c, d, ni.(map[string]interface{}), nil, len(updates))
-			cspec, err := r.Create(l)
+			cspec, err := r.Create(ctx, l)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 			}
@@ -1247,12 +1355,14 @@ func DiskImportOperation(d *schema.ResourceData, l object.VirtualDeviceList) err
 			return fmt.Errorf("disk.%d: unsupported controller type %s for disk %s", i, ct, addr)
 		}
 		// As one final validation, as we are no longer reading here, validate that
-		// this is a VMDK-backed virtual disk to make sure we aren't importing RDM
-		// disks or what not. The device should have already been validated as a
-		// virtual disk via SelectDisks.
-		if _, ok := device.(*types.VirtualDisk).Backing.(*types.VirtualDiskFlatVer2BackingInfo); !ok {
+		// this is a VMDK-backed virtual disk or a raw device mapping to make sure
+		// we aren't importing disks with a backing we can't manage. The device
+		// should have already been validated as a virtual disk via SelectDisks.
+		switch device.(*types.VirtualDisk).Backing.(type) {
+		case *types.VirtualDiskFlatVer2BackingInfo, *types.VirtualDiskRawDiskMappingVer1BackingInfo:
+		default:
 			return fmt.Errorf(
-				"disk.%d: unsupported disk type at %s (expected flat VMDK version 2, got %T)",
+				"disk.%d: unsupported disk type at %s (expected flat VMDK version 2 or raw device mapping, got %T)",
 				i,
 				addr,
 				device.(*types.VirtualDisk).Backing,
@@ -1320,17 +1430,21 @@ func ReadDiskAttrsForDataSource(l object.VirtualDeviceList, d *schema.ResourceDa
 	var out []map[string]interface{}
 	for i, device := range devices {
 		disk := device.(*types.VirtualDisk)
-		backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
-		if !ok {
-			return nil, fmt.Errorf("disk number %d has an unsupported backing type (expected flat VMDK version 2, got %T)", i, disk.Backing)
-		}
 		m := make(map[string]interface{})
 		var eager, thin bool
-		if backing.EagerlyScrub != nil {
-			eager = *backing.EagerlyScrub
-		}
-		if backing.ThinProvisioned != nil {
-			thin = *backing.ThinProvisioned
+		switch backing := disk.Backing.(type) {
+		case *types.VirtualDiskFlatVer2BackingInfo:
+			if backing.EagerlyScrub != nil {
+				eager = *backing.EagerlyScrub
+			}
+			if backing.ThinProvisioned != nil {
+				thin = *backing.ThinProvisioned
+			}
+		case *types.VirtualDiskRawDiskMappingVer1BackingInfo:
+			// Raw device mappings are neither thin provisioned nor eagerly
+			// scrubbed.
+		default:
+			return nil, fmt.Errorf("disk number %d has an unsupported backing type (expected flat VMDK version 2 or raw device mapping, got %T)", i, disk.Backing)
 		}
 		if di, ok := disk.DeviceInfo.(*types.Description); ok {
 			m["label"] = di.Label
@@ -1350,11 +1464,11 @@ func ReadDiskAttrsForDataSource(l object.VirtualDeviceList, d *schema.ResourceDa
 }
 
 // Create creates a vsphere_virtual_machine disk sub-resource.
-func (r *DiskSubresource) Create(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+func (r *DiskSubresource) Create(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] %s: Creating disk", r)
 	var spec []types.BaseVirtualDeviceConfigSpec
 
-	disk, err := r.createDisk(l)
+	disk, err := r.createDisk(ctx, l)
 	if err != nil {
 		return nil, fmt.Errorf("error creating disk: %s", err)
 	}
@@ -1398,7 +1512,7 @@ func (r *DiskSubresource) Create(l object.VirtualDeviceList) ([]types.BaseVirtua
 
 // Read reads a vsphere_virtual_machine disk sub-resource and commits the data
 // to the newData layer.
-func (r *DiskSubresource) Read(l object.VirtualDeviceList) error {
+func (r *DiskSubresource) Read(ctx context.Context, l object.VirtualDeviceList) error {
 	log.Printf("[DEBUG] %s: Reading state", r)
 	disk, err := r.findVirtualDisk(l, true)
 	if err != nil {
@@ -1428,6 +1542,9 @@ func (r *DiskSubresource) Read(l object.VirtualDeviceList) error {
 	if r.Get("attach") != nil {
 		attach = r.Get("attach").(bool)
 	}
+	if attach && disk.VDiskId != nil {
+		r.Set("first_class_disk_id", disk.VDiskId.Id)
+	}
 	// Save disk backing settings
 	if b, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo); ok {
 		if err := r.setFlatBackingProperties(b, disk, attach); err != nil {
@@ -1437,6 +1554,10 @@ func (r *DiskSubresource) Read(l object.VirtualDeviceList) error {
 		if err := r.setSparseBackingProperties(b, disk, attach); err != nil {
 			return err
 		}
+	} else if b, ok := disk.Backing.(*types.VirtualDiskRawDiskMappingVer1BackingInfo); ok {
+		if err := r.setRawDiskMappingBackingProperties(ctx, b); err != nil {
+			return err
+		}
 	} else {
 		return fmt.Errorf("disk backing at %s is of an unsupported type (type %T)", r.Get("device_address").(string), disk.Backing)
 	}
@@ -1454,11 +1575,11 @@ func (r *DiskSubresource) Read(l object.VirtualDeviceList) error {
 		// Set storage policy if the VM exists.
 		vmUUID := r.rdd.Id()
 		if vmUUID != "" {
-			result, err := virtualmachine.MOIDForUUID(r.client, vmUUID)
+			result, err := virtualmachine.MOIDForUUID(ctx, r.client, vmUUID)
 			if err != nil {
 				return err
 			}
-			polID, err := spbm.PolicyIDByVirtualDisk(r.client, result.MOID, r.Get("key").(int))
+			polID, err := spbm.PolicyIDByVirtualDisk(ctx, r.client, result.MOID, r.Get("key").(int))
 			if err != nil {
 				return err
 			}
@@ -1524,6 +1645,38 @@ func (r *DiskSubresource) setSparseBackingProperties(b *types.VirtualDiskSparseV
 	return nil
 }
 
+// setRawDiskMappingBackingProperties saves the settings of a raw device
+// mapping. The size, thin_provisioned, and eagerly_scrub settings do not apply
+// to raw device mappings and are not read, in the same way as attached disks.
+func (r *DiskSubresource) setRawDiskMappingBackingProperties(ctx context.Context, b *types.VirtualDiskRawDiskMappingVer1BackingInfo) error {
+	r.Set("uuid", b.Uuid)
+	r.Set("disk_mode", b.DiskMode)
+	r.Set("rdm_compatibility_mode", b.CompatibilityMode)
+
+	version := viapi.ParseVersionFromClient(r.client)
+	if version.Newer(viapi.VSphereVersion{Product: version.Product, Major: 6}) && b.Sharing != "" {
+		r.Set("disk_sharing", b.Sharing)
+	}
+
+	if b.Datastore != nil {
+		r.Set("datastore_id", b.Datastore.Value)
+	}
+	dp := &object.DatastorePath{}
+	if ok := dp.FromString(b.FileName); !ok {
+		return fmt.Errorf("could not parse path from filename: %s", b.FileName)
+	}
+	r.Set("path", dp.Path)
+
+	lun, err := r.rdmLunCanonicalName(ctx, b)
+	if err != nil {
+		return err
+	}
+	if lun != "" {
+		r.Set("rdm_lun", lun)
+	}
+	return nil
+}
+
 // Update updates a vsphere_virtual_machine disk sub-resource.
 func (r *DiskSubresource) Update(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] %s: Beginning update", r)
@@ -1602,7 +1755,7 @@ func (r *DiskSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtua
 // sub-resource.  It handles carrying over existing values, so this should not
 // be used on disks that have not been successfully matched up between current
 // and old diffs.
-func (r *DiskSubresource) DiffExisting() error {
+func (r *DiskSubresource) DiffExisting(ctx context.Context) error {
 	log.Printf("[DEBUG] %s: Beginning normalization of existing disk", r)
 	name, err := getDiskLabel(r.data)
 	if err != nil {
@@ -1642,7 +1795,7 @@ func (r *DiskSubresource) DiffExisting() error {
 				r.Set("datastore_id", dsID)
 			}
 		default:
-			if err = r.normalizeDiskDatastore(); err != nil {
+			if err = r.normalizeDiskDatastore(ctx); err != nil {
 				return err
 			}
 		}
@@ -1667,6 +1820,26 @@ func (r *DiskSubresource) DiffExisting() error {
 		return fmt.Errorf("virtual disk %q: %s", name, err)
 	}
 
+	// The LUN and compatibility mode of a raw device mapping can only be
+	// changed by replacing the disk.
+	if _, err = r.GetWithVeto("rdm_lun"); err != nil {
+		return fmt.Errorf("virtual disk %q: %s", name, err)
+	}
+	if _, err = r.GetWithVeto("rdm_compatibility_mode"); err != nil {
+		return fmt.Errorf("virtual disk %q: %s", name, err)
+	}
+
+	// An attached first class disk can only be swapped by replacing the disk.
+	// Disks attached by path are read back with the ID of the first class disk
+	// if they are one, so carry the ID forward when it is not configured.
+	if nfcd, ok := r.Get("first_class_disk_id").(string); !ok || nfcd == "" {
+		if ofcd, _ := r.GetChange("first_class_disk_id"); ofcd != nil {
+			r.Set("first_class_disk_id", ofcd)
+		}
+	} else if _, err = r.GetWithVeto("first_class_disk_id"); err != nil {
+		return fmt.Errorf("virtual disk %q: %s", name, err)
+	}
+
 	// Validate storage vMotion if the datastore is changing
 	if r.HasChange("datastore_id") {
 		if err = r.validateStorageRelocateDiff(); err != nil {
@@ -1731,6 +1904,21 @@ func (r *DiskSubresource) DiffGeneral() error {
 			return fmt.Errorf("eagerly_scrub for disk %q cannot be defined when attach is set", name)
 		case r.Get("keep_on_remove").(bool):
 			return fmt.Errorf("keep_on_remove for disk %q is implicit when attach is set, please remove this setting", name)
+		case r.Get("rdm_lun") != nil && r.Get("rdm_lun").(string) != "":
+			return fmt.Errorf("rdm_lun for disk %q cannot be defined when attach is set", name)
+		case r.Get("first_class_disk_id") != nil && r.Get("first_class_disk_id").(string) != "" && getDiskPath(r.data) != "":
+			return fmt.Errorf("path for disk %q cannot be defined when first_class_disk_id is set", name)
+		}
+	} else if fcdID, ok := r.Get("first_class_disk_id").(string); ok && fcdID != "" {
+		return fmt.Errorf("first_class_disk_id for disk %q can only be used when attach is set", name)
+	} else if lun, ok := r.Get("rdm_lun").(string); ok && lun != "" {
+		switch {
+		case r.Get("size").(int) > 0:
+			return fmt.Errorf("size for disk %q cannot be defined when rdm_lun is set", name)
+		case r.Get("eagerly_scrub").(bool):
+			return fmt.Errorf("eagerly_scrub for disk %q cannot be defined when rdm_lun is set", name)
+		case r.rdd.Get("datastore_cluster_id").(string) != "":
+			return fmt.Errorf("disk %q: rdm_lun cannot be used with datastore_cluster_id", name)
 		}
 	} else if r.Get("size").(int) < 1 {
 		return fmt.Errorf("size for disk %q: required option not set", name)
@@ -1760,7 +1948,7 @@ func (r *DiskSubresource) DiffGeneral() error {
 // currently defined datastore cluster, and if it is not, it marks the disk as
 // computed so that it can be migrated back to the datastore cluster on the
 // next update.
-func (r *DiskSubresource) normalizeDiskDatastore() error {
+func (r *DiskSubresource) normalizeDiskDatastore(ctx context.Context) error {
 	podID := r.rdd.Get("datastore_cluster_id").(string)
 	dsID, _ := r.GetChange("datastore_id")
 
@@ -1775,17 +1963,17 @@ func (r *DiskSubresource) normalizeDiskDatastore() error {
 
 	log.Printf("[DEBUG] %s: Checking datastore cluster membership of disk", r)
 
-	pod, err := storagepod.FromID(r.client, podID)
+	pod, err := storagepod.FromID(ctx, r.client, podID)
 	if err != nil {
 		return fmt.Errorf("error fetching datastore cluster ID %q: %s", podID, err)
 	}
 
-	ds, err := datastore.FromID(r.client, dsID.(string))
+	ds, err := datastore.FromID(ctx, r.client, dsID.(string))
 	if err != nil {
 		return fmt.Errorf("error fetching datastore ID %q: %s", dsID, err)
 	}
 
-	isMember, err := storagepod.IsMember(pod, ds)
+	isMember, err := storagepod.IsMember(ctx, pod, ds)
 	if err != nil {
 		return fmt.Errorf("error checking storage pod membership: %s", err)
 	}
@@ -1843,7 +2031,7 @@ func (r *DiskSubresource) blockRelocateAttachedDisks() error {
 
 // Relocate produces a VirtualMachineRelocateSpecDiskLocator for this resource
 // and is used for both cloning and storage vMotion.
-func (r *DiskSubresource) Relocate(l object.VirtualDeviceList, clone bool) (types.VirtualMachineRelocateSpecDiskLocator, error) {
+func (r *DiskSubresource) Relocate(ctx context.Context, l object.VirtualDeviceList, clone bool) (types.VirtualMachineRelocateSpecDiskLocator, error) {
 	log.Printf("[DEBUG] %s: Starting relocate generation", r)
 	disk, err := r.findVirtualDisk(l, clone)
 	var relocate types.VirtualMachineRelocateSpecDiskLocator
@@ -1865,7 +2053,7 @@ func (r *DiskSubresource) Relocate(l object.VirtualDeviceList, clone bool) (type
 		// Default to the default datastore
 		dsID = r.rdd.Get("datastore_id").(string)
 	}
-	ds, err := datastore.FromID(r.client, dsID)
+	ds, err := datastore.FromID(ctx, r.client, dsID)
 	if err != nil {
 		return relocate, err
 	}
@@ -1876,10 +2064,13 @@ func (r *DiskSubresource) Relocate(l object.VirtualDeviceList, clone bool) (type
 	if r.rdd.Id() == "" {
 		log.Printf("[DEBUG] %s: Adding additional options to relocator for cloning", r)
 
-		backing := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
-		backing.FileName = ds.Path("")
-		backing.Datastore = &dsref
-		relocate.DiskBackingInfo = backing
+		// Raw device mappings keep their backing, only the mapping file is
+		// placed on the target datastore.
+		if backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo); ok {
+			backing.FileName = ds.Path("")
+			backing.Datastore = &dsref
+			relocate.DiskBackingInfo = backing
+		}
 	}
 
 	// Attach the SPBM storage policy if specified
@@ -1909,6 +2100,28 @@ func (r *DiskSubresource) String() string {
 // configuration.
 func (r *DiskSubresource) expandDiskSettings(disk *types.VirtualDisk) error {
 	// Backing settings
+	if b, ok := disk.Backing.(*types.VirtualDiskRawDiskMappingVer1BackingInfo); ok {
+		r.expandRawDiskMappingSettings(b)
+	} else if err := r.expandFlatDiskSettings(disk); err != nil {
+		return err
+	}
+
+	alloc := &types.StorageIOAllocationInfo{
+		Limit:       structure.Int64Ptr(int64(r.Get("io_limit").(int))),
+		Reservation: structure.Int32Ptr(int32(r.Get("io_reservation").(int))),
+		Shares: &types.SharesInfo{
+			Shares: int32(r.Get("io_share_count").(int)),
+			Level:  types.SharesLevel(r.Get("io_share_level").(string)),
+		},
+	}
+	disk.StorageIOAllocation = alloc
+
+	return nil
+}
+
+// expandFlatDiskSettings sets the backing settings and size of a VMDK-backed
+// disk.
+func (r *DiskSubresource) expandFlatDiskSettings(disk *types.VirtualDisk) error {
 	b := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
 	b.DiskMode = r.GetWithRestart("disk_mode").(string)
 	b.WriteThrough = structure.BoolPtr(r.GetWithRestart("write_through").(bool))
@@ -1942,29 +2155,39 @@ func (r *DiskSubresource) expandDiskSettings(disk *types.VirtualDisk) error {
 		disk.CapacityInBytes = structure.GiBToByte(int64(ns.(int)))
 		disk.CapacityInKB = disk.CapacityInBytes / 1024
 	}
+	return nil
+}
 
-	alloc := &types.StorageIOAllocationInfo{
-		Limit:       structure.Int64Ptr(int64(r.Get("io_limit").(int))),
-		Reservation: structure.Int32Ptr(int32(r.Get("io_reservation").(int))),
-		Shares: &types.SharesInfo{
-			Shares: int32(r.Get("io_share_count").(int)),
-			Level:  types.SharesLevel(r.Get("io_share_level").(string)),
-		},
-	}
-	disk.StorageIOAllocation = alloc
+// expandRawDiskMappingSettings sets the backing settings of a raw device
+// mapping. The size of the disk is the size of the LUN, and is set when the
+// LUN is assigned.
+func (r *DiskSubresource) expandRawDiskMappingSettings(b *types.VirtualDiskRawDiskMappingVer1BackingInfo) {
+	b.DiskMode = r.GetWithRestart("disk_mode").(string)
 
-	return nil
+	version := viapi.ParseVersionFromClient(r.client)
+
+	// Minimum Supported Version: 6.0.0
+	if version.Newer(viapi.VSphereVersion{Product: version.Product, Major: 6}) {
+		b.Sharing = r.GetWithRestart("disk_sharing").(string)
+	}
 }
 
 // createDisk performs all of the logic for a base virtual disk creation.
-func (r *DiskSubresource) createDisk(l object.VirtualDeviceList) (*types.VirtualDisk, error) {
+func (r *DiskSubresource) createDisk(ctx context.Context, l object.VirtualDeviceList) (*types.VirtualDisk, error) {
 	disk := new(types.VirtualDisk)
-	disk.Backing = new(types.VirtualDiskFlatVer2BackingInfo)
+	if lun, ok := r.Get("rdm_lun").(string); ok && lun != "" {
+		disk.Backing = new(types.VirtualDiskRawDiskMappingVer1BackingInfo)
+		if err := r.assignRawDiskMapping(ctx, disk); err != nil {
+			return nil, err
+		}
+	} else {
+		disk.Backing = new(types.VirtualDiskFlatVer2BackingInfo)
+	}
 
 	// Only assign backing info if a datastore cluster is not specified. If one
 	// is, skip this step.
 	if r.rdd.Get("datastore_cluster_id").(string) == "" {
-		if err := r.assignBackingInfo(disk); err != nil {
+		if err := r.assignBackingInfo(ctx, disk); err != nil {
 			return nil, err
 		}
 	}
@@ -1974,19 +2197,19 @@ func (r *DiskSubresource) createDisk(l object.VirtualDeviceList) (*types.Virtual
 	return disk, nil
 }
 
-func (r *DiskSubresource) assignBackingInfo(disk *types.VirtualDisk) error {
+func (r *DiskSubresource) assignBackingInfo(ctx context.Context, disk *types.VirtualDisk) error {
 	dsID := r.Get("datastore_id").(string)
 	if dsID == "" || dsID == diskDatastoreComputedName {
 		// Default to the default datastore
 		dsID = r.rdd.Get("datastore_id").(string)
 
 		if dsID == "" {
-			vmObj, err := virtualmachine.FromUUID(r.client, r.rdd.Id())
+			vmObj, err := virtualmachine.FromUUID(ctx, r.client, r.rdd.Id())
 			if err != nil {
 				return err
 			}
 
-			vmprops, err := virtualmachine.Properties(vmObj)
+			vmprops, err := virtualmachine.Properties(ctx, vmObj)
 			if err != nil {
 				return err
 			}
@@ -1996,7 +2219,7 @@ func (r *DiskSubresource) assignBackingInfo(disk *types.VirtualDisk) error {
 			dsID = vmprops.Datastore[0].Value
 		}
 	}
-	ds, err := datastore.FromID(r.client, dsID)
+	ds, err := datastore.FromID(ctx, r.client, dsID)
 	if err != nil {
 		return err
 	}
@@ -2008,15 +2231,188 @@ func (r *DiskSubresource) assignBackingInfo(disk *types.VirtualDisk) error {
 		// provided path must be the full path to the virtual disk you want to
 		// attach.
 		diskName = getDiskPath(r.data)
+		// First class disks are attached from the backing file of the disk.
+		if fcdID, ok := r.Get("first_class_disk_id").(string); ok && fcdID != "" {
+			if diskName, err = firstClassDiskPath(ctx, r.client, ds, fcdID); err != nil {
+				return err
+			}
+		}
 	}
 
-	backing := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+	backing := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo).GetVirtualDeviceFileBackingInfo()
 	backing.FileName = ds.Path(diskName)
 	backing.Datastore = &dsref
 
 	return nil
 }
 
+// firstClassDiskPath returns the path of the backing file of a first class
+// disk, relative to its datastore.
+func firstClassDiskPath(ctx context.Context, client *govmomi.Client, ds *object.Datastore, id string) (string, error) {
+	obj, err := firstclassdisk.FromID(ctx, client, ds, id)
+	if err != nil {
+		return "", fmt.Errorf("cannot locate first class disk %q: %s", id, err)
+	}
+	dp := &object.DatastorePath{}
+	if ok := dp.FromString(firstclassdisk.FilePath(obj)); !ok {
+		return "", fmt.Errorf("could not parse path of first class disk %q: %q", id, firstclassdisk.FilePath(obj))
+	}
+	return dp.Path, nil
+}
+
+// diskRdmLunDiffOperation checks that the LUNs of raw device mappings that
+// are added to the virtual machine are visible to at least one of the hosts
+// that the virtual machine can be placed on. The check is skipped when the
+// placement of the virtual machine or the LUN is not known until apply, in
+// which case the LUN is checked when the disk is created.
+func diskRdmLunDiffOperation(ctx context.Context, d *schema.ResourceDiff, c *govmomi.Client, ods, nds []interface{}) error {
+	mapped := make(map[string]bool)
+	for _, oe := range ods {
+		if lun, ok := oe.(map[string]interface{})["rdm_lun"].(string); ok && lun != "" {
+			mapped[lun] = true
+		}
+	}
+	var added []string
+	for ni, ne := range nds {
+		lun, ok := ne.(map[string]interface{})["rdm_lun"].(string)
+		if !ok || lun == "" || mapped[lun] || !d.NewValueKnown(fmt.Sprintf("%s.%d.rdm_lun", subresourceTypeDisk, ni)) {
+			continue
+		}
+		added = append(added, lun)
+	}
+	if len(added) < 1 || !placementHostsKnown(d) {
+		return nil
+	}
+	hosts, err := placementHosts(ctx, c, d)
+	if err != nil {
+		return err
+	}
+	for _, name := range added {
+		if lun, _ := findRdmLun(hosts, name); lun == nil {
+			return fmt.Errorf("disk: LUN %s was not found on any host available to the virtual machine", name)
+		}
+	}
+	return nil
+}
+
+// findRdmLun returns the LUN with the supplied canonical name from the first
+// of the hosts that it is visible to, and the name of that host.
+func findRdmLun(hosts []*mo.HostSystem, name string) (*types.HostScsiDisk, string) {
+	for _, host := range hosts {
+		if host.Config == nil || host.Config.StorageDevice == nil {
+			continue
+		}
+		lun := findHostScsiDisk(host.Config.StorageDevice.ScsiLun, func(lun *types.HostScsiDisk) bool {
+			return lun.CanonicalName == name
+		})
+		if lun != nil {
+			return lun, host.Name
+		}
+	}
+	return nil, ""
+}
+
+// assignRawDiskMapping looks up the LUN in rdm_lun on the hosts that the
+// virtual machine can be placed on, and sets the raw device mapping backing
+// and the size of the disk from the first match.
+func (r *DiskSubresource) assignRawDiskMapping(ctx context.Context, disk *types.VirtualDisk) error {
+	name := r.Get("rdm_lun").(string)
+	hosts, err := placementHosts(ctx, r.client, r.rdd)
+	if err != nil {
+		return err
+	}
+	lun, hostName := findRdmLun(hosts, name)
+	if lun == nil {
+		return fmt.Errorf("LUN %s was not found on any host available to the virtual machine", name)
+	}
+	log.Printf("[DEBUG] %s: Found LUN %s on host %s", r, name, hostName)
+	mode, _ := r.Get("rdm_compatibility_mode").(string)
+	if mode == "" {
+		mode = string(types.VirtualDiskCompatibilityModePhysicalMode)
+		r.Set("rdm_compatibility_mode", mode)
+	}
+	backing := disk.Backing.(*types.VirtualDiskRawDiskMappingVer1BackingInfo)
+	backing.DeviceName = lun.DevicePath
+	backing.LunUuid = lun.Uuid
+	backing.CompatibilityMode = mode
+	disk.CapacityInBytes = lun.Capacity.Block * int64(lun.Capacity.BlockSize)
+	disk.CapacityInKB = disk.CapacityInBytes / 1024
+	return nil
+}
+
+// rdmLunCanonicalName returns the canonical name of the LUN that backs a raw
+// device mapping. vSphere usually reports the device name as a vml. path, in
+// which case the LUN is looked up by its UUID on the host that the virtual
+// machine is running on. An empty name is returned if the virtual machine does
+// not exist yet, such as when the disks of a clone source are read.
+func (r *DiskSubresource) rdmLunCanonicalName(ctx context.Context, b *types.VirtualDiskRawDiskMappingVer1BackingInfo) (string, error) {
+	if name := path.Base(b.DeviceName); diskRdmLunRegexp.MatchString(name) {
+		return name, nil
+	}
+	if r.luns == nil {
+		r.luns = new(hostLunCache)
+	}
+	if err := r.luns.load(ctx, r.client, r.rdd.Id()); err != nil {
+		return "", err
+	}
+	if r.luns.host == "" {
+		return "", nil
+	}
+	lun := findHostScsiDisk(r.luns.luns, func(lun *types.HostScsiDisk) bool {
+		return lun.Uuid == b.LunUuid
+	})
+	if lun == nil {
+		return "", fmt.Errorf("could not find LUN with UUID %s on host %s", b.LunUuid, r.luns.host)
+	}
+	return lun.CanonicalName, nil
+}
+
+// load looks up the SCSI LUNs of the host that the virtual machine with the
+// supplied UUID is running on, if they have not been loaded already. No LUNs
+// are loaded if the virtual machine does not exist yet or is not on a host.
+func (c *hostLunCache) load(ctx context.Context, client *govmomi.Client, vmUUID string) error {
+	if c.loaded || vmUUID == "" {
+		return nil
+	}
+	vm, err := virtualmachine.FromUUID(ctx, client, vmUUID)
+	if err != nil {
+		return err
+	}
+	vprops, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return err
+	}
+	c.loaded = true
+	if vprops.Runtime.Host == nil {
+		return nil
+	}
+	host, err := hostsystem.FromID(ctx, client, vprops.Runtime.Host.Value)
+	if err != nil {
+		return err
+	}
+	hprops, err := hostsystem.Properties(ctx, host)
+	if err != nil {
+		return err
+	}
+	c.host = hprops.Name
+	if hprops.Config != nil && hprops.Config.StorageDevice != nil {
+		c.luns = hprops.Config.StorageDevice.ScsiLun
+	}
+	log.Printf("[DEBUG] Loaded %d LUNs of host %s for raw device mappings", len(c.luns), c.host)
+	return nil
+}
+
+// findHostScsiDisk returns the first SCSI disk in a list of host LUNs that
+// matches the supplied function.
+func findHostScsiDisk(luns []types.BaseScsiLun, f func(*types.HostScsiDisk) bool) *types.HostScsiDisk {
+	for _, lun := range luns {
+		if disk, ok := lun.(*types.HostScsiDisk); ok && f(disk) {
+			return disk
+		}
+	}
+	return nil
+}
+
 // assignDisk takes a unit number and assigns it correctly to a controller on
 // the SCSI bus. An error is returned if the assigned unit number is taken.
 func (r *DiskSubresource) assignDisk(l object.VirtualDeviceList, disk *types.VirtualDisk) (types.BaseVirtualController, error) {
@@ -2397,6 +2793,9 @@ func diskUUIDMatch(device types.BaseVirtualDevice, uuid string) bool {
 	if backing, ok := disk.Backing.(*types.VirtualDiskSparseVer2BackingInfo); ok {
 		return backing.Uuid == uuid
 	}
+	if backing, ok := disk.Backing.(*types.VirtualDiskRawDiskMappingVer1BackingInfo); ok {
+		return backing.Uuid == uuid
+	}
 
 	return false
 }
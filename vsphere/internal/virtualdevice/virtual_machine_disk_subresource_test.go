@@ -5,8 +5,10 @@
 package virtualdevice
 
 import (
+	"context"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -56,3 +58,151 @@ func TestDiskCapacityInGiB(t *testing.T) {
 		})
 	}
 }
+
+func TestDiskUUIDMatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		subject  types.BaseVirtualDevice
+		expected bool
+	}{
+		{
+			name: "flat",
+			subject: &types.VirtualDisk{
+				VirtualDevice: types.VirtualDevice{
+					Backing: &types.VirtualDiskFlatVer2BackingInfo{Uuid: "6000C29a-1"},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "raw device mapping",
+			subject: &types.VirtualDisk{
+				VirtualDevice: types.VirtualDevice{
+					Backing: &types.VirtualDiskRawDiskMappingVer1BackingInfo{Uuid: "6000C29a-1"},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "raw device mapping - different UUID",
+			subject: &types.VirtualDisk{
+				VirtualDevice: types.VirtualDevice{
+					Backing: &types.VirtualDiskRawDiskMappingVer1BackingInfo{Uuid: "6000C29a-2"},
+				},
+			},
+			expected: false,
+		},
+		{
+			name:     "not a disk",
+			subject:  &types.VirtualCdrom{},
+			expected: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := diskUUIDMatch(tc.subject, "6000C29a-1"); actual != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFindHostScsiDisk(t *testing.T) {
+	luns := []types.BaseScsiLun{
+		&types.ScsiLun{CanonicalName: "mpx.vmhba0:C0:T0:L0"},
+		&types.HostScsiDisk{ScsiLun: types.ScsiLun{CanonicalName: "naa.600a0b80001234", Uuid: "0200000000600a0b80001234"}},
+		&types.HostScsiDisk{ScsiLun: types.ScsiLun{CanonicalName: "naa.600a0b80005678", Uuid: "0200000000600a0b80005678"}},
+	}
+	cases := []struct {
+		name     string
+		f        func(*types.HostScsiDisk) bool
+		expected string
+	}{
+		{
+			name:     "by canonical name",
+			f:        func(lun *types.HostScsiDisk) bool { return lun.CanonicalName == "naa.600a0b80005678" },
+			expected: "naa.600a0b80005678",
+		},
+		{
+			name:     "by UUID",
+			f:        func(lun *types.HostScsiDisk) bool { return lun.Uuid == "0200000000600a0b80001234" },
+			expected: "naa.600a0b80001234",
+		},
+		{
+			name: "LUN that is not a disk",
+			f:    func(lun *types.HostScsiDisk) bool { return lun.CanonicalName == "mpx.vmhba0:C0:T0:L0" },
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lun := findHostScsiDisk(luns, tc.f)
+			if tc.expected == "" {
+				if lun != nil {
+					t.Fatalf("expected no LUN, got %s", lun.CanonicalName)
+				}
+				return
+			}
+			if lun == nil {
+				t.Fatalf("expected %s, got none", tc.expected)
+			}
+			if lun.CanonicalName != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, lun.CanonicalName)
+			}
+		})
+	}
+}
+
+func TestRdmLunCanonicalName(t *testing.T) {
+	luns := &hostLunCache{
+		loaded: true,
+		host:   "esxi1",
+		luns: []types.BaseScsiLun{
+			&types.HostScsiDisk{ScsiLun: types.ScsiLun{CanonicalName: "naa.600a0b80001234", Uuid: "0200000000600a0b80001234"}},
+		},
+	}
+	cases := []struct {
+		name     string
+		backing  *types.VirtualDiskRawDiskMappingVer1BackingInfo
+		expected string
+		err      bool
+	}{
+		{
+			name:     "canonical device name",
+			backing:  &types.VirtualDiskRawDiskMappingVer1BackingInfo{DeviceName: "/vmfs/devices/disks/naa.600a0b80005678"},
+			expected: "naa.600a0b80005678",
+		},
+		{
+			name:     "vml device name",
+			backing:  &types.VirtualDiskRawDiskMappingVer1BackingInfo{DeviceName: "/vmfs/devices/disks/vml.0200000000600a0b80001234", LunUuid: "0200000000600a0b80001234"},
+			expected: "naa.600a0b80001234",
+		},
+		{
+			name:    "LUN not on host",
+			backing: &types.VirtualDiskRawDiskMappingVer1BackingInfo{DeviceName: "/vmfs/devices/disks/vml.0200000000600a0b80009999", LunUuid: "0200000000600a0b80009999"},
+			err:     true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The LUNs are already loaded, so the virtual machine and its host are
+			// not looked up.
+			d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+			d.SetId("42300000-0000-0000-0000-000000000000")
+			r := NewDiskSubresource(nil, d, map[string]interface{}{}, nil, 0)
+			r.luns = luns
+			actual, err := r.rdmLunCanonicalName(context.Background(), tc.backing)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
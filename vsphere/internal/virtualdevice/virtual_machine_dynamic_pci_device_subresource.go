@@ -0,0 +1,596 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+var pciIDRegexp = regexp.MustCompile("^-?[0-9a-fA-F]{1,4}$")
+
+// DynamicPciDeviceSubresourceSchema represents the schema for the
+// dynamic_pci_device sub-resource.
+func DynamicPciDeviceSubresourceSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"vendor_id": {
+			Type:             schema.TypeString,
+			Required:         true,
+			Description:      "The hexadecimal vendor ID of the PCI device, as returned by the vsphere_host_pci_device data source.",
+			ValidateFunc:     validation.StringMatch(pciIDRegexp, "must be a hexadecimal PCI ID"),
+			DiffSuppressFunc: suppressPciIDDiff,
+		},
+		"device_id": {
+			Type:             schema.TypeString,
+			Required:         true,
+			Description:      "The hexadecimal device ID of the PCI device, as returned by the vsphere_host_pci_device data source.",
+			ValidateFunc:     validation.StringMatch(pciIDRegexp, "must be a hexadecimal PCI ID"),
+			DiffSuppressFunc: suppressPciIDDiff,
+		},
+		"custom_label": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The custom label of the PCI device on the host. When set, only devices with this hardware label are assigned.",
+		},
+		"assigned_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The ID of the host PCI device assigned to the virtual machine while it is powered on.",
+		},
+	}
+	structure.MergeSchema(s, subresourceSchema())
+	return s
+}
+
+// DynamicPciDeviceSubresource represents a vsphere_virtual_machine
+// dynamic_pci_device sub-resource, with a complex device lifecycle.
+type DynamicPciDeviceSubresource struct {
+	*Subresource
+}
+
+// NewDynamicPciDeviceSubresource returns a subresource populated with all of
+// the necessary fields.
+func NewDynamicPciDeviceSubresource(client *govmomi.Client, rdd resourceDataDiff, d, old map[string]interface{}, idx int) *DynamicPciDeviceSubresource {
+	sr := &DynamicPciDeviceSubresource{
+		Subresource: &Subresource{
+			schema:  DynamicPciDeviceSubresourceSchema(),
+			client:  client,
+			srtype:  subresourceTypeDynamicPciDevice,
+			data:    d,
+			olddata: old,
+			rdd:     rdd,
+		},
+	}
+	sr.Index = idx
+	return sr
+}
+
+// DynamicPciDeviceApplyOperation processes an apply operation for all
+// dynamic DirectPath I/O devices in the resource.
+//
+// The function takes the root resource's ResourceData, the provider
+// connection, and the device list as known to vSphere at the start of this
+// operation. All device operations are carried out, with both the complete,
+// updated, VirtualDeviceList, and the complete list of changes returned as a
+// slice of BaseVirtualDeviceConfigSpec.
+//
+// When there are no dynamic PCI devices in state, the existing devices are
+// taken over as in DynamicPciDevicePostCloneOperation.
+func DynamicPciDeviceApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: Beginning apply operation")
+	o, n := d.GetChange(subresourceTypeDynamicPciDevice)
+	ods := o.([]interface{})
+	nds := n.([]interface{})
+	if len(ods) < 1 && len(nds) > 0 {
+		// The dynamic PCI devices of the virtual machine are not managed until
+		// the block is added to configuration. Take them over the same way as
+		// after a clone.
+		log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: No dynamic PCI devices in state, taking over existing devices")
+		return DynamicPciDevicePostCloneOperation(ctx, d, c, l)
+	}
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+
+	// Look for removed devices first.
+	log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: Looking for resources to delete")
+nextOld:
+	for n, oe := range ods {
+		om := oe.(map[string]interface{})
+		for _, ne := range nds {
+			nm := ne.(map[string]interface{})
+			if om["key"] == nm["key"] {
+				continue nextOld
+			}
+		}
+		r := NewDynamicPciDeviceSubresource(c, d, om, nil, n)
+		dspec, err := r.Delete(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, dspec)
+		spec = append(spec, dspec...)
+	}
+
+	// Now check for creates and updates. The results of this operation are
+	// committed to state after the operation completes.
+	var updates []interface{}
+	log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: Looking for resources to create or update")
+	for n, ne := range nds {
+		nm := ne.(map[string]interface{})
+		if n < len(ods) {
+			// This is an update
+			om := ods[n].(map[string]interface{})
+			if nm["key"] != om["key"] {
+				return nil, nil, fmt.Errorf("key mismatch on %s.%d (old: %d, new: %d). This is a bug with the provider, please report it", subresourceTypeDynamicPciDevice, n, nm["key"].(int), om["key"].(int))
+			}
+			if reflect.DeepEqual(nm, om) {
+				// no change is a no-op
+				updates = append(updates, nm)
+				log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: No-op resource: key %d", nm["key"].(int))
+				continue
+			}
+			r := NewDynamicPciDeviceSubresource(c, d, nm, om, n)
+			uspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, uspec)
+			spec = append(spec, uspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		// New device
+		r := NewDynamicPciDeviceSubresource(c, d, nm, nil, n)
+		cspec, err := r.Create(ctx, l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, cspec)
+		spec = append(spec, cspec...)
+		updates = append(updates, r.Data())
+	}
+
+	log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: Post-apply final resource list: %s", subresourceListString(updates))
+	if err := d.Set(subresourceTypeDynamicPciDevice, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: Device config operations from apply: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] DynamicPciDeviceApplyOperation: Apply complete, returning updated spec")
+	return l, spec, nil
+}
+
+// DynamicPciDeviceRefreshOperation processes a refresh operation for all of
+// the dynamic DirectPath I/O devices in the resource.
+//
+// Devices are matched by key. Freshly-created devices do not have an address
+// until vSphere places them on the PCI bus, so they are matched by their
+// backing to a device that has not been claimed by another entry.
+func DynamicPciDeviceRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] DynamicPciDeviceRefreshOperation: Beginning refresh")
+	devices := selectPciPassthroughDevices(l, isDynamicPciBacking)
+	log.Printf("[DEBUG] DynamicPciDeviceRefreshOperation: Dynamic PCI devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeDynamicPciDevice).([]interface{})
+	log.Printf("[DEBUG] DynamicPciDeviceRefreshOperation: Current resource set from state: %s", subresourceListString(curSet))
+
+	claimed := claimedDeviceKeys(curSet, devices)
+	var newSet []interface{}
+	for n, item := range curSet {
+		m := item.(map[string]interface{})
+		r := NewDynamicPciDeviceSubresource(c, d, m, nil, n)
+		if m["key"].(int) > 0 && !claimed[int32(m["key"].(int))] {
+			log.Printf("[DEBUG] DynamicPciDeviceRefreshOperation: %s: device no longer present", r)
+			continue
+		}
+		if err := r.Read(unclaimedDevices(l, claimed, m["key"].(int))); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		claimed[int32(r.Get("key").(int))] = true
+		newSet = append(newSet, r.Data())
+	}
+
+	// Any device that is still unclaimed is orphaned, and is added as a new
+	// device so that it is removed on the next apply, but only when dynamic PCI
+	// devices are managed. Devices of a virtual machine without a
+	// dynamic_pci_device block in configuration are left alone.
+	if len(curSet) < 1 {
+		devices = nil
+	}
+	for _, device := range devices {
+		key := device.GetVirtualDevice().Key
+		if claimed[key] {
+			continue
+		}
+		r := NewDynamicPciDeviceSubresource(c, d, map[string]interface{}{"key": int(key)}, nil, len(newSet))
+		if err := r.Read(l); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		newSet = append(newSet, r.Data())
+	}
+
+	log.Printf("[DEBUG] DynamicPciDeviceRefreshOperation: Refresh operation complete, sending new resource set: %s", subresourceListString(newSet))
+	return d.Set(subresourceTypeDynamicPciDevice, newSet)
+}
+
+// DynamicPciDevicePostCloneOperation normalizes dynamic DirectPath I/O
+// devices on a freshly-cloned virtual machine and outputs any necessary
+// device change operations. It also sets the state in advance of the
+// post-create read.
+func DynamicPciDevicePostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] DynamicPciDevicePostCloneOperation: Looking for post-clone device changes")
+	devices := selectPciPassthroughDevices(l, isDynamicPciBacking)
+	log.Printf("[DEBUG] DynamicPciDevicePostCloneOperation: Dynamic PCI devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeDynamicPciDevice).([]interface{})
+	if len(curSet) < 1 {
+		// Keep the devices of the source when they are not in configuration.
+		log.Printf("[DEBUG] DynamicPciDevicePostCloneOperation: No dynamic PCI devices in configuration, leaving existing devices alone")
+		return l, nil, nil
+	}
+	var srcSet []interface{}
+
+	// Populate the source set as if the devices were orphaned. This give us a
+	// base to diff off of.
+	for n, device := range devices {
+		r := NewDynamicPciDeviceSubresource(c, d, map[string]interface{}{"key": int(device.GetVirtualDevice().Key)}, nil, n)
+		if err := r.Read(l); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		srcSet = append(srcSet, r.Data())
+	}
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+	var updates []interface{}
+	for i, ci := range curSet {
+		cm := ci.(map[string]interface{})
+		if i > len(srcSet)-1 {
+			// New device
+			r := NewDynamicPciDeviceSubresource(c, d, cm, nil, i)
+			cspec, err := r.Create(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		sm := srcSet[i].(map[string]interface{})
+		nm := structure.CopyMap(sm)
+		for _, k := range []string{"vendor_id", "device_id"} {
+			nm[k] = cm[k]
+		}
+		r := NewDynamicPciDeviceSubresource(c, d, nm, sm, i)
+		if !reflect.DeepEqual(sm, nm) {
+			cspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+		}
+		updates = append(updates, r.Data())
+	}
+
+	// Any other device past the end of the devices listed in config needs to
+	// be removed.
+	if len(curSet) < len(srcSet) {
+		for i, si := range srcSet[len(curSet):] {
+			r := NewDynamicPciDeviceSubresource(c, d, si.(map[string]interface{}), nil, i+len(curSet))
+			dspec, err := r.Delete(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, dspec)
+			spec = append(spec, dspec...)
+		}
+	}
+
+	log.Printf("[DEBUG] DynamicPciDevicePostCloneOperation: Post-clone final resource list: %s", subresourceListString(updates))
+	if err := d.Set(subresourceTypeDynamicPciDevice, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] DynamicPciDevicePostCloneOperation: Device config operations from post-clone: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] DynamicPciDevicePostCloneOperation: Operation complete, returning updated spec")
+	return l, spec, nil
+}
+
+// DynamicPciDeviceDiffOperation checks that the hosts the virtual machine
+// can be placed on have a PCI device matching each dynamic_pci_device
+// sub-resource. The check is skipped when the placement of the virtual machine
+// or the IDs of the device are not known until apply, in which case devices
+// are checked when they are created.
+func DynamicPciDeviceDiffOperation(ctx context.Context, d *schema.ResourceDiff, c *govmomi.Client) error {
+	nds := d.Get(subresourceTypeDynamicPciDevice).([]interface{})
+	if len(nds) < 1 || !(d.HasChange(subresourceTypeDynamicPciDevice) || d.HasChange("host_system_id") || d.HasChange("resource_pool_id")) {
+		return nil
+	}
+	if !placementHostsKnown(d) {
+		log.Printf("[DEBUG] DynamicPciDeviceDiffOperation: Placement not known until apply, skipping validation")
+		return nil
+	}
+	log.Printf("[DEBUG] DynamicPciDeviceDiffOperation: Beginning diff validation")
+	hosts, err := placementHosts(ctx, c, d)
+	if err != nil {
+		return err
+	}
+nextDevice:
+	for i, ne := range nds {
+		for _, k := range []string{"vendor_id", "device_id"} {
+			if !d.NewValueKnown(fmt.Sprintf("%s.%d.%s", subresourceTypeDynamicPciDevice, i, k)) {
+				continue nextDevice
+			}
+		}
+		r := NewDynamicPciDeviceSubresource(c, d, ne.(map[string]interface{}), nil, i)
+		if err := r.validateHostDevice(hosts); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+	}
+	log.Printf("[DEBUG] DynamicPciDeviceDiffOperation: Diff validation complete")
+	return nil
+}
+
+// Create creates a vsphere_virtual_machine dynamic_pci_device sub-resource.
+func (r *DynamicPciDeviceSubresource) Create(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Running create", r)
+	hosts, err := placementHosts(ctx, r.client, r.rdd)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateHostDevice(hosts); err != nil {
+		return nil, err
+	}
+	device := &types.VirtualPCIPassthrough{}
+	device.Key = l.NewKey()
+	if err := r.mapBacking(device); err != nil {
+		return nil, err
+	}
+	// PCI passthrough devices cannot be added to a powered on virtual machine.
+	r.SetRestart("<device create>")
+	// The device is placed on the PCI bus by vSphere, so only the key can be
+	// saved here.
+	r.Set("key", device.Key)
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from create: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Create finished", r)
+	return spec, nil
+}
+
+// Read reads a vsphere_virtual_machine dynamic_pci_device sub-resource.
+func (r *DynamicPciDeviceSubresource) Read(l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] %s: Reading state", r)
+	device, err := r.findDevice(l)
+	if err != nil {
+		return fmt.Errorf("cannot find dynamic PCI device: %s", err)
+	}
+	backing := device.Backing.(*types.VirtualPCIPassthroughDynamicBackingInfo)
+	if len(backing.AllowedDevice) > 0 {
+		r.Set("vendor_id", formatPciID(backing.AllowedDevice[0].VendorId))
+		r.Set("device_id", formatPciID(backing.AllowedDevice[0].DeviceId))
+	}
+	r.Set("custom_label", backing.CustomLabel)
+	r.Set("assigned_id", backing.AssignedId)
+	return r.saveDevIDs(l, device)
+}
+
+// Update updates a vsphere_virtual_machine dynamic_pci_device sub-resource.
+func (r *DynamicPciDeviceSubresource) Update(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning update", r)
+	hosts, err := placementHosts(ctx, r.client, r.rdd)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateHostDevice(hosts); err != nil {
+		return nil, err
+	}
+	device, err := r.findDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find dynamic PCI device: %s", err)
+	}
+	if err := r.mapBacking(device); err != nil {
+		return nil, err
+	}
+	r.SetRestart("<device update>")
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationEdit)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from update: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Update complete", r)
+	return spec, nil
+}
+
+// Delete deletes a vsphere_virtual_machine dynamic_pci_device sub-resource.
+func (r *DynamicPciDeviceSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning delete", r)
+	device, err := r.findDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find dynamic PCI device: %s", err)
+	}
+	r.SetRestart("<device delete>")
+	deleteSpec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationRemove)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from delete: %s", r, DeviceChangeString(deleteSpec))
+	log.Printf("[DEBUG] %s: Delete completed", r)
+	return deleteSpec, nil
+}
+
+// mapBacking sets the dynamic backing of a PCI passthrough device from the
+// subresource data.
+func (r *DynamicPciDeviceSubresource) mapBacking(device *types.VirtualPCIPassthrough) error {
+	vendorID, err := parsePciID(r.Get("vendor_id").(string))
+	if err != nil {
+		return fmt.Errorf("invalid vendor_id: %s", err)
+	}
+	deviceID, err := parsePciID(r.Get("device_id").(string))
+	if err != nil {
+		return fmt.Errorf("invalid device_id: %s", err)
+	}
+	device.Backing = &types.VirtualPCIPassthroughDynamicBackingInfo{
+		AllowedDevice: []types.VirtualPCIPassthroughAllowedDevice{
+			{
+				VendorId: vendorID,
+				DeviceId: deviceID,
+			},
+		},
+		CustomLabel: r.Get("custom_label").(string),
+	}
+	return nil
+}
+
+// findDevice locates the device for this subresource by its key. Devices
+// that have not been read since they were created are located by their
+// backing instead.
+func (r *DynamicPciDeviceSubresource) findDevice(l object.VirtualDeviceList) (*types.VirtualPCIPassthrough, error) {
+	if key := r.Get("key").(int); key > 0 {
+		d := l.FindByKey(int32(key))
+		if d == nil {
+			return nil, fmt.Errorf("could not find device with key %d", key)
+		}
+		device, ok := d.(*types.VirtualPCIPassthrough)
+		if !ok || !isDynamicPciBacking(device.Backing) {
+			return nil, fmt.Errorf("device at %q is not a dynamic PCI passthrough device", l.Name(d))
+		}
+		return device, nil
+	}
+	for _, d := range selectPciPassthroughDevices(l, isDynamicPciBacking) {
+		device := d.(*types.VirtualPCIPassthrough)
+		backing := device.Backing.(*types.VirtualPCIPassthroughDynamicBackingInfo)
+		if len(backing.AllowedDevice) < 1 || backing.CustomLabel != r.Get("custom_label").(string) {
+			continue
+		}
+		if pciIDEqual(backing.AllowedDevice[0].VendorId, r.Get("vendor_id").(string)) && pciIDEqual(backing.AllowedDevice[0].DeviceId, r.Get("device_id").(string)) {
+			return device, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find device with vendor ID %s and device ID %s", r.Get("vendor_id").(string), r.Get("device_id").(string))
+}
+
+// validateHostDevice checks that at least one of the hosts that the virtual
+// machine can run on has a PCI device with the configured vendor and device
+// ID.
+func (r *DynamicPciDeviceSubresource) validateHostDevice(hosts []*mo.HostSystem) error {
+	for _, host := range hosts {
+		if host.Hardware == nil {
+			continue
+		}
+		for _, dev := range host.Hardware.PciDevice {
+			if pciIDEqual(int32(uint16(dev.VendorId)), r.Get("vendor_id").(string)) && pciIDEqual(int32(uint16(dev.DeviceId)), r.Get("device_id").(string)) {
+				log.Printf("[DEBUG] %s: Found matching PCI device %s on host %s", r, dev.Id, host.Name)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no host available to the virtual machine has a PCI device with vendor ID %s and device ID %s", r.Get("vendor_id").(string), r.Get("device_id").(string))
+}
+
+// saveDevIDs saves the key and device address of a PCI passthrough device.
+func (r *Subresource) saveDevIDs(l object.VirtualDeviceList, device types.BaseVirtualDevice) error {
+	ctlr, err := findControllerForDevice(l, device)
+	if err != nil {
+		return err
+	}
+	if err := r.SaveDevIDs(device, ctlr); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] %s: Read finished (key and device address may have changed)", r)
+	return nil
+}
+
+// selectPciPassthroughDevices returns the PCI passthrough devices in a device
+// list with a backing that matches the supplied function.
+func selectPciPassthroughDevices(l object.VirtualDeviceList, f func(types.BaseVirtualDeviceBackingInfo) bool) object.VirtualDeviceList {
+	return l.Select(func(device types.BaseVirtualDevice) bool {
+		if pci, ok := device.(*types.VirtualPCIPassthrough); ok {
+			return f(pci.Backing)
+		}
+		return false
+	})
+}
+
+// isDynamicPciBacking returns true if the backing is a dynamic DirectPath
+// I/O backing.
+func isDynamicPciBacking(b types.BaseVirtualDeviceBackingInfo) bool {
+	_, ok := b.(*types.VirtualPCIPassthroughDynamicBackingInfo)
+	return ok
+}
+
+// claimedDeviceKeys returns the keys of the devices that are known in state.
+func claimedDeviceKeys(curSet []interface{}, devices object.VirtualDeviceList) map[int32]bool {
+	claimed := make(map[int32]bool)
+	for _, item := range curSet {
+		key := int32(item.(map[string]interface{})["key"].(int))
+		if key > 0 && devices.FindByKey(key) != nil {
+			claimed[key] = true
+		}
+	}
+	return claimed
+}
+
+// unclaimedDevices returns the device list without the devices that have been
+// claimed by other entries, so that freshly-created devices with the same
+// backing are matched to a single entry each. The device with the supplied
+// key is always kept.
+func unclaimedDevices(l object.VirtualDeviceList, claimed map[int32]bool, key int) object.VirtualDeviceList {
+	return l.Select(func(device types.BaseVirtualDevice) bool {
+		k := device.GetVirtualDevice().Key
+		return !claimed[k] || int(k) == key
+	})
+}
+
+// parsePciID parses a hexadecimal PCI vendor or device ID. The
+// vsphere_host_pci_device data source reports IDs as signed 16-bit values, so
+// negative values are accepted and converted.
+func parsePciID(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 16, 32)
+	if err != nil {
+		return 0, err
+	}
+	if v < math.MinInt16 || v > math.MaxUint16 {
+		return 0, fmt.Errorf("PCI ID %q out of range", s)
+	}
+	return int32(uint16(v)), nil
+}
+
+// formatPciID formats a PCI vendor or device ID as hexadecimal. IDs may be
+// reported as signed 16-bit values, so only the lower 16 bits are used.
+func formatPciID(v int32) string {
+	return fmt.Sprintf("%04x", uint16(v))
+}
+
+// pciIDEqual checks a PCI ID against a hexadecimal string.
+func pciIDEqual(v int32, s string) bool {
+	id, err := parsePciID(s)
+	if err != nil {
+		return false
+	}
+	return uint16(v) == uint16(id)
+}
+
+// suppressPciIDDiff suppresses differences in case and leading zeros between
+// PCI IDs.
+func suppressPciIDDiff(_, o, n string, _ *schema.ResourceData) bool {
+	ov, err := parsePciID(o)
+	if err != nil {
+		return false
+	}
+	return pciIDEqual(ov, n)
+}
@@ -0,0 +1,156 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestParsePciID(t *testing.T) {
+	cases := []struct {
+		name      string
+		id        string
+		expected  int32
+		expectErr bool
+	}{
+		{
+			name:     "lowercase",
+			id:       "10de",
+			expected: 0x10de,
+		},
+		{
+			name:     "uppercase",
+			id:       "10DE",
+			expected: 0x10de,
+		},
+		{
+			name:     "short",
+			id:       "de",
+			expected: 0xde,
+		},
+		{
+			name:     "signed from data source",
+			id:       "-7f7a",
+			expected: 0x8086,
+		},
+		{
+			name:      "not hexadecimal",
+			id:        "nvidia",
+			expectErr: true,
+		},
+		{
+			name:      "out of range",
+			id:        "10000",
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parsePciID(tc.id)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got %#x", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bad: %s", err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("expected %#x, got %#x", tc.expected, actual)
+			}
+			if !pciIDEqual(actual, formatPciID(actual)) {
+				t.Fatalf("%q does not round-trip", formatPciID(actual))
+			}
+		})
+	}
+}
+
+func TestSuppressPciIDDiff(t *testing.T) {
+	cases := []struct {
+		name     string
+		old      string
+		new      string
+		expected bool
+	}{
+		{
+			name:     "case",
+			old:      "10de",
+			new:      "10DE",
+			expected: true,
+		},
+		{
+			name:     "leading zeros",
+			old:      "00de",
+			new:      "de",
+			expected: true,
+		},
+		{
+			name:     "signed",
+			old:      "8086",
+			new:      "-7f7a",
+			expected: true,
+		},
+		{
+			name:     "different",
+			old:      "10de",
+			new:      "1002",
+			expected: false,
+		},
+		{
+			name:     "new value",
+			old:      "",
+			new:      "10de",
+			expected: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := suppressPciIDDiff("", tc.old, tc.new, nil); actual != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDynamicPciDeviceOperations_unmanaged(t *testing.T) {
+	pci := &types.VirtualPCIController{}
+	pci.Key = 100
+	device := &types.VirtualPCIPassthrough{}
+	device.Key = 13000
+	device.ControllerKey = 100
+	device.UnitNumber = types.NewInt32(0)
+	device.Backing = &types.VirtualPCIPassthroughDynamicBackingInfo{
+		AllowedDevice: []types.VirtualPCIPassthroughAllowedDevice{{VendorId: 0x10de, DeviceId: 0x1eb8}},
+	}
+	l := object.VirtualDeviceList{pci, device}
+	s := map[string]*schema.Schema{
+		subresourceTypeDynamicPciDevice: {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Resource{Schema: DynamicPciDeviceSubresourceSchema()},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{})
+	_, spec, err := DynamicPciDevicePostCloneOperation(context.Background(), d, nil, l)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(spec) != 0 {
+		t.Fatalf("expected template PCI device to be kept, got %s", DeviceChangeString(spec))
+	}
+	if err := DynamicPciDeviceRefreshOperation(d, nil, l); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual := d.Get(subresourceTypeDynamicPciDevice).([]interface{}); len(actual) != 0 {
+		t.Fatalf("expected unmanaged PCI device to be left out of state, got %s", subresourceListString(actual))
+	}
+}
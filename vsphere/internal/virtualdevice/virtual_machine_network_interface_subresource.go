@@ -163,7 +163,7 @@ func NewNetworkInterfaceSubresource(client *govmomi.Client, rdd resourceDataDiff
 // operation. All network_interface operations are carried out, with both the
 // complete, updated, VirtualDeviceList, and the complete list of changes
 // returned as a slice of BaseVirtualDeviceConfigSpec.
-func NetworkInterfaceApplyOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func NetworkInterfaceApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] NetworkInterfaceApplyOperation: Beginning apply operation")
 
 	o, n := d.GetChange(subresourceTypeNetworkInterface)
@@ -213,7 +213,7 @@ nextOld:
 				continue
 			}
 			r := NewNetworkInterfaceSubresource(c, d, nm, om, n)
-			uspec, err := r.Update(l)
+			uspec, err := r.Update(ctx, l)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 			}
@@ -224,7 +224,7 @@ nextOld:
 		}
 		// New device
 		r := NewNetworkInterfaceSubresource(c, d, nm, nil, n)
-		cspec, err := r.Create(l)
+		cspec, err := r.Create(ctx, l)
 		if err != nil {
 			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 		}
@@ -251,7 +251,7 @@ nextOld:
 // This functions similar to NetworkInterfaceApplyOperation, but nothing to
 // change is returned, all necessary values are just set and committed to
 // state.
-func NetworkInterfaceRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+func NetworkInterfaceRefreshOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
 	log.Printf("[DEBUG] NetworkInterfaceRefreshOperation: Beginning refresh")
 	devices := l.Select(func(device types.BaseVirtualDevice) bool {
 		if _, ok := device.(types.BaseVirtualEthernetCard); ok {
@@ -297,7 +297,7 @@ func NetworkInterfaceRefreshOperation(d *schema.ResourceData, c *govmomi.Client,
 			}
 			// We should have our device -> resource match, so read now.
 			r := NewNetworkInterfaceSubresource(c, d, m, nil, n)
-			if err := r.Read(l); err != nil {
+			if err := r.Read(ctx, l); err != nil {
 				return fmt.Errorf("%s: %s", r.Addr(), err)
 			}
 			// Done reading, push this onto our new sets and remove the device from
@@ -328,7 +328,7 @@ func NetworkInterfaceRefreshOperation(d *schema.ResourceData, c *govmomi.Client,
 			return fmt.Errorf("error computing device address: %s", err)
 		}
 		r := NewNetworkInterfaceSubresource(c, d, m, nil, n)
-		if err := r.Read(l); err != nil {
+		if err := r.Read(ctx, l); err != nil {
 			return fmt.Errorf("%s: %s", r.Addr(), err)
 		}
 		// Done reading, push this onto our new sets and remove the device from
@@ -345,7 +345,7 @@ func NetworkInterfaceRefreshOperation(d *schema.ResourceData, c *govmomi.Client,
 
 // NetworkInterfaceDiffOperation performs operations relevant to managing the
 // diff on network_interface sub-resources.
-func NetworkInterfaceDiffOperation(d *schema.ResourceDiff, c *govmomi.Client) error {
+func NetworkInterfaceDiffOperation(ctx context.Context, d *schema.ResourceDiff, c *govmomi.Client) error {
 	o, n := d.GetChange(subresourceTypeNetworkInterface)
 	ods := o.([]interface{})
 	nds := n.([]interface{})
@@ -422,11 +422,11 @@ loopInterfaces:
 		}
 
 		// First check that the host system is known
-		host, err := hostsystem.FromID(c, d.Get("host_system_id").(string))
+		host, err := hostsystem.FromID(ctx, c, d.Get("host_system_id").(string))
 		if err != nil {
 			return fmt.Errorf("trying to use an SR-IOV network interface but target host is not known")
 		}
-		hprops, err := hostsystem.Properties(host)
+		hprops, err := hostsystem.Properties(ctx, host)
 		if err != nil {
 			return err
 		}
@@ -511,7 +511,7 @@ loopInterfaces:
 // This differs from a regular apply operation in that a configuration is
 // already present, but we don't have any existing state, which the standard
 // virtual device operations rely pretty heavily on.
-func NetworkInterfacePostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+func NetworkInterfacePostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] NetworkInterfacePostCloneOperation: Looking for post-clone device changes")
 	devices := l.Select(func(device types.BaseVirtualDevice) bool {
 		if _, ok := device.(types.BaseVirtualEthernetCard); ok {
@@ -545,7 +545,7 @@ func NetworkInterfacePostCloneOperation(d *schema.ResourceData, c *govmomi.Clien
 			return nil, nil, fmt.Errorf("error computing device address: %s", err)
 		}
 		r := NewNetworkInterfaceSubresource(c, d, m, nil, n)
-		if err := r.Read(l); err != nil {
+		if err := r.Read(ctx, l); err != nil {
 			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 		}
 
@@ -565,7 +565,7 @@ func NetworkInterfacePostCloneOperation(d *schema.ResourceData, c *govmomi.Clien
 		if i > len(srcSet)-1 || srcSet[i] == nil {
 			// New device
 			r := NewNetworkInterfaceSubresource(c, d, cm, nil, i)
-			cspec, err := r.Create(l)
+			cspec, err := r.Create(ctx, l)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 			}
@@ -588,7 +588,7 @@ func NetworkInterfacePostCloneOperation(d *schema.ResourceData, c *govmomi.Clien
 		r := NewNetworkInterfaceSubresource(c, d, nm, sm, i)
 		if !reflect.DeepEqual(sm, nm) {
 			// Update
-			cspec, err := r.Update(l)
+			cspec, err := r.Update(ctx, l)
 			if err != nil {
 				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
 			}
@@ -627,6 +627,50 @@ func NetworkInterfacePostCloneOperation(d *schema.ResourceData, c *govmomi.Clien
 	return l, spec, nil
 }
 
+// NetworkInterfaceMigrateRelocateOperation assembles the device changes that
+// connect the network interfaces of a virtual machine to the networks in
+// configuration when the virtual machine is migrated across vCenter Server
+// instances. The networks are looked up on c, which must be the connection to
+// the destination instance. Network interfaces that are new in configuration
+// are skipped, as they are added once the migration is complete.
+func NetworkInterfaceMigrateRelocateOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] NetworkInterfaceMigrateRelocateOperation: Generating network interface relocate specs")
+	var spec []types.BaseVirtualDeviceConfigSpec
+	for n, ne := range d.Get(subresourceTypeNetworkInterface).([]interface{}) {
+		nm := ne.(map[string]interface{})
+		if nm["key"].(int) < 1 {
+			continue
+		}
+		r := NewNetworkInterfaceSubresource(c, d, nm, nil, n)
+		vd, err := r.FindVirtualDevice(l)
+		if err != nil {
+			return nil, fmt.Errorf("%s: cannot find network device: %s", r.Addr(), err)
+		}
+		device, err := baseVirtualDeviceToBaseVirtualEthernetCard(vd)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		net, err := network.FromID(ctx, c, nm["network_id"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		bctx, bcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+		backing, err := net.EthernetCardBackingInfo(bctx)
+		bcancel()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		device.GetVirtualEthernetCard().Backing = backing
+		uspec, err := object.VirtualDeviceList{vd}.ConfigSpec(types.VirtualDeviceConfigSpecOperationEdit)
+		if err != nil {
+			return nil, err
+		}
+		spec = append(spec, uspec...)
+	}
+	log.Printf("[DEBUG] NetworkInterfaceMigrateRelocateOperation: Device config operations from relocate: %s", DeviceChangeString(spec))
+	return spec, nil
+}
+
 // ReadNetworkInterfaceTypes returns a list of network interface types. This is used
 // in the VM data source to discover the types of the NIC drivers on the
 // virtual machine. The list is sorted by the order that they would be added in
@@ -777,7 +821,7 @@ func virtualEthernetCardString(d types.BaseVirtualEthernetCard) string {
 }
 
 // Create creates a vsphere_virtual_machine network_interface sub-resource.
-func (r *NetworkInterfaceSubresource) Create(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+func (r *NetworkInterfaceSubresource) Create(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] %s: Running create", r)
 	var spec []types.BaseVirtualDeviceConfigSpec
 	ctlr, err := r.ControllerForCreateUpdate(l, SubresourceControllerTypePCI, 0)
@@ -788,11 +832,11 @@ func (r *NetworkInterfaceSubresource) Create(l object.VirtualDeviceList) ([]type
 	// govmomi has helpers that allow the easy fetching of a network's backing
 	// info, once we actually know what that backing is. Set all of that stuff up
 	// now.
-	net, err := network.FromID(r.client, r.Get("network_id").(string))
+	net, err := network.FromID(ctx, r.client, r.Get("network_id").(string))
 	if err != nil {
 		return nil, err
 	}
-	bctx, bcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	bctx, bcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer bcancel()
 	backing, err := net.EthernetCardBackingInfo(bctx)
 	if err != nil {
@@ -879,7 +923,7 @@ func (r *NetworkInterfaceSubresource) Create(l object.VirtualDeviceList) ([]type
 }
 
 // Read reads a vsphere_virtual_machine network_interface sub-resource.
-func (r *NetworkInterfaceSubresource) Read(l object.VirtualDeviceList) error {
+func (r *NetworkInterfaceSubresource) Read(ctx context.Context, l object.VirtualDeviceList) error {
 	log.Printf("[DEBUG] %s: Reading state", r)
 	vd, err := r.FindVirtualDevice(l)
 	if err != nil {
@@ -911,13 +955,13 @@ func (r *NetworkInterfaceSubresource) Read(l object.VirtualDeviceList) error {
 		}
 		netID = backing.Network.Value
 	case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
-		onet, err := nsx.OpaqueNetworkFromNetworkID(r.client, backing.OpaqueNetworkId)
+		onet, err := nsx.OpaqueNetworkFromNetworkID(ctx, r.client, backing.OpaqueNetworkId)
 		if err != nil {
 			return err
 		}
 		netID = onet.Reference().Value
 	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
-		pg, err := dvportgroup.FromKey(r.client, backing.Port.SwitchUuid, backing.Port.PortgroupKey)
+		pg, err := dvportgroup.FromKey(ctx, r.client, backing.Port.SwitchUuid, backing.Port.PortgroupKey)
 		if err != nil {
 			_, isMissingPortGroupError := err.(*dvportgroup.MissingPortGroupReferenceError)
 			if strings.Contains(err.Error(), "The object or item referred to could not be found") || isMissingPortGroupError {
@@ -978,7 +1022,7 @@ func (r *NetworkInterfaceSubresource) Read(l object.VirtualDeviceList) error {
 }
 
 // Update updates a vsphere_virtual_machine network_interface sub-resource.
-func (r *NetworkInterfaceSubresource) Update(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+func (r *NetworkInterfaceSubresource) Update(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
 	log.Printf("[DEBUG] %s: Beginning update", r)
 	vd, err := r.FindVirtualDevice(l)
 	if err != nil {
@@ -1049,11 +1093,11 @@ func (r *NetworkInterfaceSubresource) Update(l object.VirtualDeviceList) ([]type
 
 	// Has the backing changed?
 	if r.HasChange("network_id") {
-		net, err := network.FromID(r.client, r.Get("network_id").(string))
+		net, err := network.FromID(ctx, r.client, r.Get("network_id").(string))
 		if err != nil {
 			return nil, err
 		}
-		bctx, bcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+		bctx, bcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 		defer bcancel()
 		backing, err := net.EthernetCardBackingInfo(bctx)
 		if err != nil {
@@ -0,0 +1,579 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+// ParallelPortSubresourceSchema represents the schema for the parallel_port
+// sub-resource.
+func ParallelPortSubresourceSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		// VirtualParallelPortFileBackingInfo
+		"datastore_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The datastore ID the output file is located on.",
+		},
+		"path": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The path to the output file on the datastore.",
+		},
+		// VirtualParallelPortDeviceBackingInfo
+		"device_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The name of the host parallel device the parallel port is connected to.",
+		},
+	}
+	structure.MergeSchema(s, subresourceSchema())
+	return s
+}
+
+// ParallelPortSubresource represents a vsphere_virtual_machine parallel_port
+// sub-resource, with a complex device lifecycle.
+type ParallelPortSubresource struct {
+	*Subresource
+}
+
+// NewParallelPortSubresource returns a subresource populated with all of the
+// necessary fields.
+func NewParallelPortSubresource(client *govmomi.Client, rdd resourceDataDiff, d, old map[string]interface{}, idx int) *ParallelPortSubresource {
+	sr := &ParallelPortSubresource{
+		Subresource: &Subresource{
+			schema:  ParallelPortSubresourceSchema(),
+			client:  client,
+			srtype:  subresourceTypeParallelPort,
+			data:    d,
+			olddata: old,
+			rdd:     rdd,
+		},
+	}
+	sr.Index = idx
+	return sr
+}
+
+// ParallelPortApplyOperation processes an apply operation for all parallel ports
+// in the resource.
+//
+// The function takes the root resource's ResourceData, the provider
+// connection, and the device list as known to vSphere at the start of this
+// operation. All parallel port operations are carried out, with both the
+// complete, updated, VirtualDeviceList, and the complete list of changes
+// returned as a slice of BaseVirtualDeviceConfigSpec.
+//
+// When there are no parallel ports in state, the existing devices are taken over
+// as in ParallelPortPostCloneOperation.
+func ParallelPortApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] ParallelPortApplyOperation: Beginning apply operation")
+	o, n := d.GetChange(subresourceTypeParallelPort)
+	ods := o.([]interface{})
+	nds := n.([]interface{})
+	if len(ods) < 1 && len(nds) > 0 {
+		// The ports of the virtual machine are not managed until the block is
+		// added to configuration. Take them over the same way as after a clone.
+		log.Printf("[DEBUG] ParallelPortApplyOperation: No parallel ports in state, taking over existing devices")
+		return ParallelPortPostCloneOperation(ctx, d, c, l)
+	}
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+
+	// Our old and new sets now have an accurate description of devices that may
+	// have been added, removed, or changed. Look for removed devices first.
+	log.Printf("[DEBUG] ParallelPortApplyOperation: Looking for resources to delete")
+nextOld:
+	for n, oe := range ods {
+		om := oe.(map[string]interface{})
+		for _, ne := range nds {
+			nm := ne.(map[string]interface{})
+			if om["key"] == nm["key"] {
+				continue nextOld
+			}
+		}
+		r := NewParallelPortSubresource(c, d, om, nil, n)
+		dspec, err := r.Delete(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, dspec)
+		spec = append(spec, dspec...)
+	}
+
+	// Now check for creates and updates. The results of this operation are
+	// committed to state after the operation completes.
+	var updates []interface{}
+	log.Printf("[DEBUG] ParallelPortApplyOperation: Looking for resources to create or update")
+	for n, ne := range nds {
+		nm := ne.(map[string]interface{})
+		if n < len(ods) {
+			// This is an update
+			oe := ods[n]
+			om := oe.(map[string]interface{})
+			if nm["key"] != om["key"] {
+				return nil, nil, fmt.Errorf("key mismatch on %s.%d (old: %d, new: %d). This is a bug with the provider, please report it", subresourceTypeParallelPort, n, nm["key"].(int), om["key"].(int))
+			}
+			if reflect.DeepEqual(nm, om) {
+				// no change is a no-op
+				updates = append(updates, nm)
+				log.Printf("[DEBUG] ParallelPortApplyOperation: No-op resource: key %d", nm["key"].(int))
+				continue
+			}
+			r := NewParallelPortSubresource(c, d, nm, om, n)
+			uspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, uspec)
+			spec = append(spec, uspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		// New device
+		r := NewParallelPortSubresource(c, d, nm, nil, n)
+		cspec, err := r.Create(ctx, l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, cspec)
+		spec = append(spec, cspec...)
+		updates = append(updates, r.Data())
+	}
+
+	log.Printf("[DEBUG] ParallelPortApplyOperation: Post-apply final resource list: %s", subresourceListString(updates))
+	// We are now done! Return the updated device list and config spec. Save updates as well.
+	if err := d.Set(subresourceTypeParallelPort, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] ParallelPortApplyOperation: Device list at end of operation: %s", DeviceListString(l))
+	log.Printf("[DEBUG] ParallelPortApplyOperation: Device config operations from apply: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] ParallelPortApplyOperation: Apply complete, returning updated spec")
+	return l, spec, nil
+}
+
+// ParallelPortRefreshOperation processes a refresh operation for all of the
+// parallel ports in the resource.
+//
+// This functions similar to ParallelPortApplyOperation, but nothing to change
+// is returned, all necessary values are just set and committed to state.
+func ParallelPortRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Beginning refresh")
+	devices := selectParallelPorts(l)
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Parallel port devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeParallelPort).([]interface{})
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Current resource set from state: %s", subresourceListString(curSet))
+	var newSet []interface{}
+	// First check for negative keys. These are freshly added devices that are
+	// usually coming into read post-create.
+	//
+	// If we find what we are looking for, we remove the device from the working
+	// set so that we don't try and process it in the next few passes.
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Looking for freshly-created resources to read in")
+	for n, item := range curSet {
+		m := item.(map[string]interface{})
+		if m["key"].(int) < 1 {
+			r := NewParallelPortSubresource(c, d, m, nil, n)
+			if err := r.Read(l); err != nil {
+				return fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			if r.Get("key").(int) < 1 {
+				// This should not have happened - if it did, our device
+				// creation/update logic failed somehow that we were not able to track.
+				return fmt.Errorf("device %d with address %s still unaccounted for after update/read", r.Get("key").(int), r.Get("device_address").(string))
+			}
+			newSet = append(newSet, r.Data())
+			for i := 0; i < len(devices); i++ {
+				device := devices[i]
+				if device.GetVirtualDevice().Key == int32(r.Get("key").(int)) {
+					devices = append(devices[:i], devices[i+1:]...)
+					i--
+				}
+			}
+		}
+	}
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Parallel port devices after freshly-created device search: %s", DeviceListString(devices))
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Resource set to write after freshly-created device search: %s", subresourceListString(newSet))
+
+	// Go over the remaining devices, refresh via key, and then remove their
+	// entries as well.
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Looking for devices known in state")
+	for i := 0; i < len(devices); i++ {
+		device := devices[i]
+		for n, item := range curSet {
+			m := item.(map[string]interface{})
+			if m["key"].(int) < 0 {
+				// Skip any of these keys as we won't be matching any of those anyway here
+				continue
+			}
+			if device.GetVirtualDevice().Key != int32(m["key"].(int)) {
+				// Skip any device that doesn't match key as well
+				continue
+			}
+			// We should have our device -> resource match, so read now.
+			r := NewParallelPortSubresource(c, d, m, nil, n)
+			if err := r.Read(l); err != nil {
+				return fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			// Done reading, push this onto our new set and remove the device from
+			// the list
+			newSet = append(newSet, r.Data())
+			devices = append(devices[:i], devices[i+1:]...)
+			i--
+		}
+	}
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Resource set to write after known device search: %s", subresourceListString(newSet))
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Probable orphaned parallel port devices: %s", DeviceListString(devices))
+
+	// Finally, any device that is still here is orphaned. They should be added
+	// as new devices, so that they are removed on the next apply, but only when
+	// parallel ports are managed. Ports of a virtual machine without a parallel_port
+	// block in configuration are left alone.
+	if len(curSet) < 1 {
+		devices = nil
+	}
+	for n, device := range devices {
+		m := make(map[string]interface{})
+		vd := device.GetVirtualDevice()
+		ctlr := l.FindByKey(vd.ControllerKey)
+		if ctlr == nil {
+			return fmt.Errorf("could not find controller with key %d", vd.Key)
+		}
+		m["key"] = int(vd.Key)
+		var err error
+		m["device_address"], err = computeDevAddr(vd, ctlr.(types.BaseVirtualController))
+		if err != nil {
+			return fmt.Errorf("error computing device address: %s", err)
+		}
+		r := NewParallelPortSubresource(c, d, m, nil, n)
+		if err := r.Read(l); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		newSet = append(newSet, r.Data())
+	}
+
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Resource set to write after adding orphaned devices: %s", subresourceListString(newSet))
+	log.Printf("[DEBUG] ParallelPortRefreshOperation: Refresh operation complete, sending new resource set")
+	return d.Set(subresourceTypeParallelPort, newSet)
+}
+
+// ParallelPortPostCloneOperation normalizes parallel ports on a freshly-cloned
+// virtual machine and outputs any necessary device change operations. It also
+// sets the state in advance of the post-create read.
+//
+// This differs from a regular apply operation in that a configuration is
+// already present, but we don't have any existing state, which the standard
+// virtual device operations rely pretty heavily on. The ports of the source
+// are only changed when there are parallel ports in configuration.
+func ParallelPortPostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Looking for post-clone device changes")
+	devices := selectParallelPorts(l)
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Parallel port devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeParallelPort).([]interface{})
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Current resource set from configuration: %s", subresourceListString(curSet))
+	if len(curSet) < 1 {
+		// Keep the ports of the source when they are not in configuration.
+		log.Printf("[DEBUG] ParallelPortPostCloneOperation: No parallel ports in configuration, leaving existing devices alone")
+		return l, nil, nil
+	}
+	var srcSet []interface{}
+
+	// Populate the source set as if the devices were orphaned. This give us a
+	// base to diff off of.
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Reading existing devices")
+	for n, device := range devices {
+		m := make(map[string]interface{})
+		vd := device.GetVirtualDevice()
+		ctlr := l.FindByKey(vd.ControllerKey)
+		if ctlr == nil {
+			return nil, nil, fmt.Errorf("could not find controller with key %d", vd.Key)
+		}
+		m["key"] = int(vd.Key)
+		var err error
+		m["device_address"], err = computeDevAddr(vd, ctlr.(types.BaseVirtualController))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error computing device address: %s", err)
+		}
+		r := NewParallelPortSubresource(c, d, m, nil, n)
+		if err := r.Read(l); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		srcSet = append(srcSet, r.Data())
+	}
+
+	// Now go over our current set, kind of treating it like an apply:
+	//
+	// * Device past the boundaries of existing devices are created
+	// * Devices within the bounds are changed changed
+	// * Data at the source with the same data after patching config data is a
+	// no-op, but we still push the device's state
+	var spec []types.BaseVirtualDeviceConfigSpec
+	var updates []interface{}
+	for i, ci := range curSet {
+		cm := ci.(map[string]interface{})
+		if i > len(srcSet)-1 {
+			// New device
+			r := NewParallelPortSubresource(c, d, cm, nil, i)
+			cspec, err := r.Create(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		sm := srcSet[i].(map[string]interface{})
+		nm := structure.CopyMap(sm)
+		for k, v := range cm {
+			// Skip key and device_address here
+			switch k {
+			case "key", "device_address":
+				continue
+			}
+			nm[k] = v
+		}
+		r := NewParallelPortSubresource(c, d, nm, sm, i)
+		if !reflect.DeepEqual(sm, nm) {
+			// Update
+			cspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+		}
+		updates = append(updates, r.Data())
+	}
+
+	// Any other device past the end of the parallel ports listed in config needs
+	// to be removed.
+	if len(curSet) < len(srcSet) {
+		for i, si := range srcSet[len(curSet):] {
+			sm := si.(map[string]interface{})
+			r := NewParallelPortSubresource(c, d, sm, nil, i+len(curSet))
+			dspec, err := r.Delete(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, dspec)
+			spec = append(spec, dspec...)
+		}
+	}
+
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Post-clone final resource list: %s", subresourceListString(updates))
+	// We are now done! Return the updated device list and config spec. Save updates as well.
+	if err := d.Set(subresourceTypeParallelPort, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Device list at end of operation: %s", DeviceListString(l))
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Device config operations from post-clone: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] ParallelPortPostCloneOperation: Operation complete, returning updated spec")
+	return l, spec, nil
+}
+
+// ParallelPortDiffOperation performs validation on the parallel_port sub-resources
+// that can't be done in schema alone. Ports with backing attributes that are
+// not known until apply are validated when they are created.
+func ParallelPortDiffOperation(d *schema.ResourceDiff, c *govmomi.Client) error {
+	log.Printf("[DEBUG] ParallelPortDiffOperation: Beginning diff validation")
+nextPort:
+	for i, ne := range d.Get(subresourceTypeParallelPort).([]interface{}) {
+		for _, k := range []string{"datastore_id", "path", "device_name"} {
+			if !d.NewValueKnown(fmt.Sprintf("%s.%d.%s", subresourceTypeParallelPort, i, k)) {
+				continue nextPort
+			}
+		}
+		r := NewParallelPortSubresource(c, d, ne.(map[string]interface{}), nil, i)
+		if err := r.ValidateDiff(); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+	}
+	log.Printf("[DEBUG] ParallelPortDiffOperation: Diff validation complete")
+	return nil
+}
+
+// ValidateDiff performs any complex validation of an individual
+// parallel_port sub-resource that can't be done in schema alone.
+func (r *ParallelPortSubresource) ValidateDiff() error {
+	log.Printf("[DEBUG] %s: Beginning parallel port configuration validation", r)
+	dsID := r.Get("datastore_id").(string)
+	path := r.Get("path").(string)
+	deviceName := r.Get("device_name").(string)
+	switch {
+	case deviceName != "" && (dsID != "" || path != ""):
+		return fmt.Errorf("cannot have both device_name and file parameters (datastore_id, path) set")
+	case deviceName == "" && (dsID == "" || path == ""):
+		return fmt.Errorf("either device_name or datastore_id and path must be set")
+	}
+	log.Printf("[DEBUG] %s: Config validation complete", r)
+	return nil
+}
+
+// Create creates a vsphere_virtual_machine parallel_port sub-resource.
+func (r *ParallelPortSubresource) Create(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Running create", r)
+	if err := r.ValidateDiff(); err != nil {
+		return nil, err
+	}
+	ctlr, err := r.ControllerForCreateUpdate(l, SubresourceControllerTypeSIO, 0)
+	if err != nil {
+		return nil, err
+	}
+	device := &types.VirtualParallelPort{}
+	l.AssignController(device, ctlr)
+	device.Connectable = &types.VirtualDeviceConnectInfo{
+		StartConnected:    true,
+		AllowGuestControl: true,
+		Connected:         true,
+	}
+	if err := r.mapParallelPort(ctx, device); err != nil {
+		return nil, err
+	}
+	// Parallel ports cannot be added to a powered on virtual machine.
+	r.SetRestart("<device create>")
+	// Done here. Save IDs, push the device to the new device list and return.
+	if err := r.SaveDevIDs(device, ctlr); err != nil {
+		return nil, err
+	}
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from create: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Create finished", r)
+	return spec, nil
+}
+
+// Read reads a vsphere_virtual_machine parallel_port sub-resource.
+func (r *ParallelPortSubresource) Read(l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] %s: Reading state", r)
+	d, err := r.FindVirtualDevice(l)
+	if err != nil {
+		return fmt.Errorf("cannot find parallel port device: %s", err)
+	}
+	device, ok := d.(*types.VirtualParallelPort)
+	if !ok {
+		return fmt.Errorf("device at %q is not a virtual parallel port device", l.Name(d))
+	}
+	r.Set("datastore_id", "")
+	r.Set("path", "")
+	r.Set("device_name", "")
+	switch backing := device.Backing.(type) {
+	case *types.VirtualParallelPortFileBackingInfo:
+		dp := &object.DatastorePath{}
+		if ok := dp.FromString(backing.FileName); !ok {
+			return fmt.Errorf("could not read datastore path in backing %q", backing.FileName)
+		}
+		if backing.Datastore != nil {
+			r.Set("datastore_id", backing.Datastore.Value)
+		}
+		r.Set("path", dp.Path)
+	case *types.VirtualParallelPortDeviceBackingInfo:
+		r.Set("device_name", backing.DeviceName)
+	default:
+		log.Printf("[DEBUG] %s: Unknown parallel port backing type %T, clearing all attributes", r, backing)
+	}
+	// Save the device key and address data
+	ctlr, err := findControllerForDevice(l, d)
+	if err != nil {
+		return err
+	}
+	if err := r.SaveDevIDs(d, ctlr); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] %s: Read finished (key and device address may have changed)", r)
+	return nil
+}
+
+// Update updates a vsphere_virtual_machine parallel_port sub-resource.
+func (r *ParallelPortSubresource) Update(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning update", r)
+	if err := r.ValidateDiff(); err != nil {
+		return nil, err
+	}
+	d, err := r.FindVirtualDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find parallel port device: %s", err)
+	}
+	device, ok := d.(*types.VirtualParallelPort)
+	if !ok {
+		return nil, fmt.Errorf("device at %q is not a virtual parallel port device", l.Name(d))
+	}
+	if err := r.mapParallelPort(ctx, device); err != nil {
+		return nil, err
+	}
+	r.SetRestart("<device update>")
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationEdit)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from update: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Update complete", r)
+	return spec, nil
+}
+
+// Delete deletes a vsphere_virtual_machine parallel_port sub-resource.
+func (r *ParallelPortSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning delete", r)
+	d, err := r.FindVirtualDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find parallel port device: %s", err)
+	}
+	device, ok := d.(*types.VirtualParallelPort)
+	if !ok {
+		return nil, fmt.Errorf("device at %q is not a virtual parallel port device", l.Name(d))
+	}
+	r.SetRestart("<device delete>")
+	deleteSpec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationRemove)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from delete: %s", r, DeviceChangeString(deleteSpec))
+	log.Printf("[DEBUG] %s: Delete completed", r)
+	return deleteSpec, nil
+}
+
+// mapParallelPort sets the backing of a parallel port device from the
+// subresource data.
+func (r *ParallelPortSubresource) mapParallelPort(ctx context.Context, device *types.VirtualParallelPort) error {
+	if deviceName := r.Get("device_name").(string); deviceName != "" {
+		device.Backing = &types.VirtualParallelPortDeviceBackingInfo{
+			VirtualDeviceDeviceBackingInfo: types.VirtualDeviceDeviceBackingInfo{
+				DeviceName: deviceName,
+			},
+		}
+		return nil
+	}
+	fileName, err := portDatastorePath(ctx, r.client, r.Get("datastore_id").(string), r.Get("path").(string))
+	if err != nil {
+		return err
+	}
+	device.Backing = &types.VirtualParallelPortFileBackingInfo{
+		VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+			FileName: fileName,
+		},
+	}
+	return nil
+}
+
+// selectParallelPorts returns the parallel port devices in a device list.
+func selectParallelPorts(l object.VirtualDeviceList) object.VirtualDeviceList {
+	return l.Select(func(device types.BaseVirtualDevice) bool {
+		if _, ok := device.(*types.VirtualParallelPort); ok {
+			return true
+		}
+		return false
+	})
+}
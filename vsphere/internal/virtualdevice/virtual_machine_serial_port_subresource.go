@@ -0,0 +1,707 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/datastore"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+var serialPortPipeEndpointAllowedValues = []string{
+	string(types.VirtualSerialPortEndPointClient),
+	string(types.VirtualSerialPortEndPointServer),
+}
+
+var serialPortDirectionAllowedValues = []string{
+	string(types.VirtualDeviceURIBackingOptionDirectionClient),
+	string(types.VirtualDeviceURIBackingOptionDirectionServer),
+}
+
+// SerialPortSubresourceSchema represents the schema for the serial_port
+// sub-resource.
+func SerialPortSubresourceSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"yield_on_poll": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Allow the virtual machine to relinquish the CPU when its only task is polling the serial port.",
+		},
+		// VirtualSerialPortPipeBackingInfo
+		"pipe_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The name of the named pipe the serial port is connected to.",
+		},
+		"pipe_endpoint": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The role of the virtual machine on the named pipe. One of client or server.",
+			ValidateFunc: validation.StringInSlice(serialPortPipeEndpointAllowedValues, false),
+		},
+		"no_rx_loss": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Enable optimized data transfer over the named pipe.",
+		},
+		// VirtualSerialPortFileBackingInfo
+		"datastore_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The datastore ID the output file is located on.",
+		},
+		"path": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The path to the output file on the datastore.",
+		},
+		// VirtualSerialPortDeviceBackingInfo
+		"device_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The name of the host serial device the serial port is connected to.",
+		},
+		// VirtualSerialPortURIBackingInfo
+		"service_uri": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The network URI the serial port is connected to, such as telnet://:5000 or tcp://host:port.",
+		},
+		"direction": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "Whether the virtual machine listens on or connects to service_uri. One of client or server.",
+			ValidateFunc: validation.StringInSlice(serialPortDirectionAllowedValues, false),
+		},
+		"proxy_uri": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The URI of a virtual serial port concentrator that the connection is proxied through.",
+		},
+	}
+	structure.MergeSchema(s, subresourceSchema())
+	return s
+}
+
+// SerialPortSubresource represents a vsphere_virtual_machine serial_port
+// sub-resource, with a complex device lifecycle.
+type SerialPortSubresource struct {
+	*Subresource
+}
+
+// NewSerialPortSubresource returns a subresource populated with all of the
+// necessary fields.
+func NewSerialPortSubresource(client *govmomi.Client, rdd resourceDataDiff, d, old map[string]interface{}, idx int) *SerialPortSubresource {
+	sr := &SerialPortSubresource{
+		Subresource: &Subresource{
+			schema:  SerialPortSubresourceSchema(),
+			client:  client,
+			srtype:  subresourceTypeSerialPort,
+			data:    d,
+			olddata: old,
+			rdd:     rdd,
+		},
+	}
+	sr.Index = idx
+	return sr
+}
+
+// SerialPortApplyOperation processes an apply operation for all serial ports
+// in the resource.
+//
+// The function takes the root resource's ResourceData, the provider
+// connection, and the device list as known to vSphere at the start of this
+// operation. All serial port operations are carried out, with both the
+// complete, updated, VirtualDeviceList, and the complete list of changes
+// returned as a slice of BaseVirtualDeviceConfigSpec.
+//
+// When there are no serial ports in state, the existing devices are taken over
+// as in SerialPortPostCloneOperation.
+func SerialPortApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] SerialPortApplyOperation: Beginning apply operation")
+	o, n := d.GetChange(subresourceTypeSerialPort)
+	ods := o.([]interface{})
+	nds := n.([]interface{})
+	if len(ods) < 1 && len(nds) > 0 {
+		// The ports of the virtual machine are not managed until the block is
+		// added to configuration. Take them over the same way as after a clone.
+		log.Printf("[DEBUG] SerialPortApplyOperation: No serial ports in state, taking over existing devices")
+		return SerialPortPostCloneOperation(ctx, d, c, l)
+	}
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+
+	// Our old and new sets now have an accurate description of devices that may
+	// have been added, removed, or changed. Look for removed devices first.
+	log.Printf("[DEBUG] SerialPortApplyOperation: Looking for resources to delete")
+nextOld:
+	for n, oe := range ods {
+		om := oe.(map[string]interface{})
+		for _, ne := range nds {
+			nm := ne.(map[string]interface{})
+			if om["key"] == nm["key"] {
+				continue nextOld
+			}
+		}
+		r := NewSerialPortSubresource(c, d, om, nil, n)
+		dspec, err := r.Delete(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, dspec)
+		spec = append(spec, dspec...)
+	}
+
+	// Now check for creates and updates. The results of this operation are
+	// committed to state after the operation completes.
+	var updates []interface{}
+	log.Printf("[DEBUG] SerialPortApplyOperation: Looking for resources to create or update")
+	for n, ne := range nds {
+		nm := ne.(map[string]interface{})
+		if n < len(ods) {
+			// This is an update
+			oe := ods[n]
+			om := oe.(map[string]interface{})
+			if nm["key"] != om["key"] {
+				return nil, nil, fmt.Errorf("key mismatch on %s.%d (old: %d, new: %d). This is a bug with the provider, please report it", subresourceTypeSerialPort, n, nm["key"].(int), om["key"].(int))
+			}
+			if reflect.DeepEqual(nm, om) {
+				// no change is a no-op
+				updates = append(updates, nm)
+				log.Printf("[DEBUG] SerialPortApplyOperation: No-op resource: key %d", nm["key"].(int))
+				continue
+			}
+			r := NewSerialPortSubresource(c, d, nm, om, n)
+			uspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, uspec)
+			spec = append(spec, uspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		// New device
+		r := NewSerialPortSubresource(c, d, nm, nil, n)
+		cspec, err := r.Create(ctx, l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, cspec)
+		spec = append(spec, cspec...)
+		updates = append(updates, r.Data())
+	}
+
+	log.Printf("[DEBUG] SerialPortApplyOperation: Post-apply final resource list: %s", subresourceListString(updates))
+	// We are now done! Return the updated device list and config spec. Save updates as well.
+	if err := d.Set(subresourceTypeSerialPort, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] SerialPortApplyOperation: Device list at end of operation: %s", DeviceListString(l))
+	log.Printf("[DEBUG] SerialPortApplyOperation: Device config operations from apply: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] SerialPortApplyOperation: Apply complete, returning updated spec")
+	return l, spec, nil
+}
+
+// SerialPortRefreshOperation processes a refresh operation for all of the
+// serial ports in the resource.
+//
+// This functions similar to SerialPortApplyOperation, but nothing to change
+// is returned, all necessary values are just set and committed to state.
+func SerialPortRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Beginning refresh")
+	devices := selectSerialPorts(l)
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Serial port devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeSerialPort).([]interface{})
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Current resource set from state: %s", subresourceListString(curSet))
+	var newSet []interface{}
+	// First check for negative keys. These are freshly added devices that are
+	// usually coming into read post-create.
+	//
+	// If we find what we are looking for, we remove the device from the working
+	// set so that we don't try and process it in the next few passes.
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Looking for freshly-created resources to read in")
+	for n, item := range curSet {
+		m := item.(map[string]interface{})
+		if m["key"].(int) < 1 {
+			r := NewSerialPortSubresource(c, d, m, nil, n)
+			if err := r.Read(l); err != nil {
+				return fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			if r.Get("key").(int) < 1 {
+				// This should not have happened - if it did, our device
+				// creation/update logic failed somehow that we were not able to track.
+				return fmt.Errorf("device %d with address %s still unaccounted for after update/read", r.Get("key").(int), r.Get("device_address").(string))
+			}
+			newSet = append(newSet, r.Data())
+			for i := 0; i < len(devices); i++ {
+				device := devices[i]
+				if device.GetVirtualDevice().Key == int32(r.Get("key").(int)) {
+					devices = append(devices[:i], devices[i+1:]...)
+					i--
+				}
+			}
+		}
+	}
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Serial port devices after freshly-created device search: %s", DeviceListString(devices))
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Resource set to write after freshly-created device search: %s", subresourceListString(newSet))
+
+	// Go over the remaining devices, refresh via key, and then remove their
+	// entries as well.
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Looking for devices known in state")
+	for i := 0; i < len(devices); i++ {
+		device := devices[i]
+		for n, item := range curSet {
+			m := item.(map[string]interface{})
+			if m["key"].(int) < 0 {
+				// Skip any of these keys as we won't be matching any of those anyway here
+				continue
+			}
+			if device.GetVirtualDevice().Key != int32(m["key"].(int)) {
+				// Skip any device that doesn't match key as well
+				continue
+			}
+			// We should have our device -> resource match, so read now.
+			r := NewSerialPortSubresource(c, d, m, nil, n)
+			if err := r.Read(l); err != nil {
+				return fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			// Done reading, push this onto our new set and remove the device from
+			// the list
+			newSet = append(newSet, r.Data())
+			devices = append(devices[:i], devices[i+1:]...)
+			i--
+		}
+	}
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Resource set to write after known device search: %s", subresourceListString(newSet))
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Probable orphaned serial port devices: %s", DeviceListString(devices))
+
+	// Finally, any device that is still here is orphaned. They should be added
+	// as new devices, so that they are removed on the next apply, but only when
+	// serial ports are managed. Ports of a virtual machine without a serial_port
+	// block in configuration are left alone.
+	if len(curSet) < 1 {
+		devices = nil
+	}
+	for n, device := range devices {
+		m := make(map[string]interface{})
+		vd := device.GetVirtualDevice()
+		ctlr := l.FindByKey(vd.ControllerKey)
+		if ctlr == nil {
+			return fmt.Errorf("could not find controller with key %d", vd.Key)
+		}
+		m["key"] = int(vd.Key)
+		var err error
+		m["device_address"], err = computeDevAddr(vd, ctlr.(types.BaseVirtualController))
+		if err != nil {
+			return fmt.Errorf("error computing device address: %s", err)
+		}
+		r := NewSerialPortSubresource(c, d, m, nil, n)
+		if err := r.Read(l); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		newSet = append(newSet, r.Data())
+	}
+
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Resource set to write after adding orphaned devices: %s", subresourceListString(newSet))
+	log.Printf("[DEBUG] SerialPortRefreshOperation: Refresh operation complete, sending new resource set")
+	return d.Set(subresourceTypeSerialPort, newSet)
+}
+
+// SerialPortPostCloneOperation normalizes serial ports on a freshly-cloned
+// virtual machine and outputs any necessary device change operations. It also
+// sets the state in advance of the post-create read.
+//
+// This differs from a regular apply operation in that a configuration is
+// already present, but we don't have any existing state, which the standard
+// virtual device operations rely pretty heavily on. The ports of the source
+// are only changed when there are serial ports in configuration.
+func SerialPortPostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Looking for post-clone device changes")
+	devices := selectSerialPorts(l)
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Serial port devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeSerialPort).([]interface{})
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Current resource set from configuration: %s", subresourceListString(curSet))
+	if len(curSet) < 1 {
+		// Keep the ports of the source when they are not in configuration.
+		log.Printf("[DEBUG] SerialPortPostCloneOperation: No serial ports in configuration, leaving existing devices alone")
+		return l, nil, nil
+	}
+	var srcSet []interface{}
+
+	// Populate the source set as if the devices were orphaned. This give us a
+	// base to diff off of.
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Reading existing devices")
+	for n, device := range devices {
+		m := make(map[string]interface{})
+		vd := device.GetVirtualDevice()
+		ctlr := l.FindByKey(vd.ControllerKey)
+		if ctlr == nil {
+			return nil, nil, fmt.Errorf("could not find controller with key %d", vd.Key)
+		}
+		m["key"] = int(vd.Key)
+		var err error
+		m["device_address"], err = computeDevAddr(vd, ctlr.(types.BaseVirtualController))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error computing device address: %s", err)
+		}
+		r := NewSerialPortSubresource(c, d, m, nil, n)
+		if err := r.Read(l); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		srcSet = append(srcSet, r.Data())
+	}
+
+	// Now go over our current set, kind of treating it like an apply:
+	//
+	// * Device past the boundaries of existing devices are created
+	// * Devices within the bounds are changed changed
+	// * Data at the source with the same data after patching config data is a
+	// no-op, but we still push the device's state
+	var spec []types.BaseVirtualDeviceConfigSpec
+	var updates []interface{}
+	for i, ci := range curSet {
+		cm := ci.(map[string]interface{})
+		if i > len(srcSet)-1 {
+			// New device
+			r := NewSerialPortSubresource(c, d, cm, nil, i)
+			cspec, err := r.Create(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		sm := srcSet[i].(map[string]interface{})
+		nm := structure.CopyMap(sm)
+		for k, v := range cm {
+			// Skip key and device_address here
+			switch k {
+			case "key", "device_address":
+				continue
+			}
+			nm[k] = v
+		}
+		r := NewSerialPortSubresource(c, d, nm, sm, i)
+		if !reflect.DeepEqual(sm, nm) {
+			// Update
+			cspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+		}
+		updates = append(updates, r.Data())
+	}
+
+	// Any other device past the end of the serial ports listed in config needs
+	// to be removed.
+	if len(curSet) < len(srcSet) {
+		for i, si := range srcSet[len(curSet):] {
+			sm := si.(map[string]interface{})
+			r := NewSerialPortSubresource(c, d, sm, nil, i+len(curSet))
+			dspec, err := r.Delete(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, dspec)
+			spec = append(spec, dspec...)
+		}
+	}
+
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Post-clone final resource list: %s", subresourceListString(updates))
+	// We are now done! Return the updated device list and config spec. Save updates as well.
+	if err := d.Set(subresourceTypeSerialPort, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Device list at end of operation: %s", DeviceListString(l))
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Device config operations from post-clone: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] SerialPortPostCloneOperation: Operation complete, returning updated spec")
+	return l, spec, nil
+}
+
+// SerialPortDiffOperation performs validation on the serial_port sub-resources
+// that can't be done in schema alone. Ports with backing attributes that are
+// not known until apply are validated when they are created.
+func SerialPortDiffOperation(d *schema.ResourceDiff, c *govmomi.Client) error {
+	log.Printf("[DEBUG] SerialPortDiffOperation: Beginning diff validation")
+nextPort:
+	for i, ne := range d.Get(subresourceTypeSerialPort).([]interface{}) {
+		for _, k := range []string{"pipe_name", "pipe_endpoint", "datastore_id", "path", "device_name", "service_uri", "direction"} {
+			if !d.NewValueKnown(fmt.Sprintf("%s.%d.%s", subresourceTypeSerialPort, i, k)) {
+				continue nextPort
+			}
+		}
+		r := NewSerialPortSubresource(c, d, ne.(map[string]interface{}), nil, i)
+		if err := r.ValidateDiff(); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+	}
+	log.Printf("[DEBUG] SerialPortDiffOperation: Diff validation complete")
+	return nil
+}
+
+// ValidateDiff performs any complex validation of an individual serial_port
+// sub-resource that can't be done in schema alone.
+func (r *SerialPortSubresource) ValidateDiff() error {
+	log.Printf("[DEBUG] %s: Beginning serial port configuration validation", r)
+	pipeName := r.Get("pipe_name").(string)
+	dsID := r.Get("datastore_id").(string)
+	path := r.Get("path").(string)
+	deviceName := r.Get("device_name").(string)
+	serviceURI := r.Get("service_uri").(string)
+
+	var backings int
+	for _, set := range []bool{pipeName != "", dsID != "" || path != "", deviceName != "", serviceURI != ""} {
+		if set {
+			backings++
+		}
+	}
+	if backings != 1 {
+		return fmt.Errorf("exactly one of pipe_name, datastore_id and path, device_name, or service_uri must be set")
+	}
+	switch {
+	case (dsID != "" || path != "") && (dsID == "" || path == ""):
+		return fmt.Errorf("datastore_id and path must both be set for a file backed serial port")
+	case pipeName != "" && r.Get("pipe_endpoint").(string) == "":
+		return fmt.Errorf("pipe_endpoint must be set when pipe_name is set")
+	case pipeName == "" && (r.Get("pipe_endpoint").(string) != "" || r.Get("no_rx_loss").(bool)):
+		return fmt.Errorf("pipe_endpoint and no_rx_loss can only be set when pipe_name is set")
+	case serviceURI != "" && r.Get("direction").(string) == "":
+		return fmt.Errorf("direction must be set when service_uri is set")
+	case serviceURI == "" && (r.Get("direction").(string) != "" || r.Get("proxy_uri").(string) != ""):
+		return fmt.Errorf("direction and proxy_uri can only be set when service_uri is set")
+	}
+	log.Printf("[DEBUG] %s: Config validation complete", r)
+	return nil
+}
+
+// Create creates a vsphere_virtual_machine serial_port sub-resource.
+func (r *SerialPortSubresource) Create(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Running create", r)
+	if err := r.ValidateDiff(); err != nil {
+		return nil, err
+	}
+	ctlr, err := r.ControllerForCreateUpdate(l, SubresourceControllerTypeSIO, 0)
+	if err != nil {
+		return nil, err
+	}
+	// CreateSerialPort picks the SIO controller and assigns the next free unit
+	// number on it.
+	device, err := l.CreateSerialPort()
+	if err != nil {
+		return nil, err
+	}
+	device.Connectable = &types.VirtualDeviceConnectInfo{
+		StartConnected:    true,
+		AllowGuestControl: true,
+		Connected:         true,
+	}
+	if err := r.mapSerialPort(ctx, device); err != nil {
+		return nil, err
+	}
+	// Serial ports cannot be added to a powered on virtual machine.
+	r.SetRestart("<device create>")
+	// Done here. Save IDs, push the device to the new device list and return.
+	if err := r.SaveDevIDs(device, ctlr); err != nil {
+		return nil, err
+	}
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from create: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Create finished", r)
+	return spec, nil
+}
+
+// Read reads a vsphere_virtual_machine serial_port sub-resource.
+func (r *SerialPortSubresource) Read(l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] %s: Reading state", r)
+	d, err := r.FindVirtualDevice(l)
+	if err != nil {
+		return fmt.Errorf("cannot find serial port device: %s", err)
+	}
+	device, ok := d.(*types.VirtualSerialPort)
+	if !ok {
+		return fmt.Errorf("device at %q is not a virtual serial port device", l.Name(d))
+	}
+	r.Set("yield_on_poll", device.YieldOnPoll)
+	// Clear all backing attributes first, so that only the ones for the current
+	// backing are populated.
+	for _, k := range []string{"pipe_name", "pipe_endpoint", "datastore_id", "path", "device_name", "service_uri", "direction", "proxy_uri"} {
+		r.Set(k, "")
+	}
+	r.Set("no_rx_loss", false)
+	switch backing := device.Backing.(type) {
+	case *types.VirtualSerialPortPipeBackingInfo:
+		r.Set("pipe_name", backing.PipeName)
+		r.Set("pipe_endpoint", backing.Endpoint)
+		if backing.NoRxLoss != nil {
+			r.Set("no_rx_loss", *backing.NoRxLoss)
+		}
+	case *types.VirtualSerialPortFileBackingInfo:
+		dp := &object.DatastorePath{}
+		if ok := dp.FromString(backing.FileName); !ok {
+			return fmt.Errorf("could not read datastore path in backing %q", backing.FileName)
+		}
+		if backing.Datastore != nil {
+			r.Set("datastore_id", backing.Datastore.Value)
+		}
+		r.Set("path", dp.Path)
+	case *types.VirtualSerialPortDeviceBackingInfo:
+		r.Set("device_name", backing.DeviceName)
+	case *types.VirtualSerialPortURIBackingInfo:
+		r.Set("service_uri", backing.ServiceURI)
+		r.Set("direction", backing.Direction)
+		r.Set("proxy_uri", backing.ProxyURI)
+	default:
+		log.Printf("[DEBUG] %s: Unknown serial port backing type %T, clearing all attributes", r, backing)
+	}
+	// Save the device key and address data
+	ctlr, err := findControllerForDevice(l, d)
+	if err != nil {
+		return err
+	}
+	if err := r.SaveDevIDs(d, ctlr); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] %s: Read finished (key and device address may have changed)", r)
+	return nil
+}
+
+// Update updates a vsphere_virtual_machine serial_port sub-resource.
+func (r *SerialPortSubresource) Update(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning update", r)
+	if err := r.ValidateDiff(); err != nil {
+		return nil, err
+	}
+	d, err := r.FindVirtualDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find serial port device: %s", err)
+	}
+	device, ok := d.(*types.VirtualSerialPort)
+	if !ok {
+		return nil, fmt.Errorf("device at %q is not a virtual serial port device", l.Name(d))
+	}
+	if err := r.mapSerialPort(ctx, device); err != nil {
+		return nil, err
+	}
+	// Changing the backing of a connected serial port requires the virtual
+	// machine to be powered off.
+	r.SetRestart("<device update>")
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationEdit)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from update: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Update complete", r)
+	return spec, nil
+}
+
+// Delete deletes a vsphere_virtual_machine serial_port sub-resource.
+func (r *SerialPortSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning delete", r)
+	d, err := r.FindVirtualDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find serial port device: %s", err)
+	}
+	device, ok := d.(*types.VirtualSerialPort)
+	if !ok {
+		return nil, fmt.Errorf("device at %q is not a virtual serial port device", l.Name(d))
+	}
+	r.SetRestart("<device delete>")
+	deleteSpec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationRemove)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from delete: %s", r, DeviceChangeString(deleteSpec))
+	log.Printf("[DEBUG] %s: Delete completed", r)
+	return deleteSpec, nil
+}
+
+// mapSerialPort sets the backing of a serial port device from the
+// subresource data.
+func (r *SerialPortSubresource) mapSerialPort(ctx context.Context, device *types.VirtualSerialPort) error {
+	device.YieldOnPoll = r.Get("yield_on_poll").(bool)
+	switch {
+	case r.Get("pipe_name").(string) != "":
+		device.Backing = &types.VirtualSerialPortPipeBackingInfo{
+			VirtualDevicePipeBackingInfo: types.VirtualDevicePipeBackingInfo{
+				PipeName: r.Get("pipe_name").(string),
+			},
+			Endpoint: r.Get("pipe_endpoint").(string),
+			NoRxLoss: structure.BoolPtr(r.Get("no_rx_loss").(bool)),
+		}
+	case r.Get("path").(string) != "":
+		fileName, err := portDatastorePath(ctx, r.client, r.Get("datastore_id").(string), r.Get("path").(string))
+		if err != nil {
+			return err
+		}
+		device.Backing = &types.VirtualSerialPortFileBackingInfo{
+			VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+				FileName: fileName,
+			},
+		}
+	case r.Get("device_name").(string) != "":
+		device.Backing = &types.VirtualSerialPortDeviceBackingInfo{
+			VirtualDeviceDeviceBackingInfo: types.VirtualDeviceDeviceBackingInfo{
+				DeviceName: r.Get("device_name").(string),
+			},
+		}
+	case r.Get("service_uri").(string) != "":
+		device.Backing = &types.VirtualSerialPortURIBackingInfo{
+			VirtualDeviceURIBackingInfo: types.VirtualDeviceURIBackingInfo{
+				ServiceURI: r.Get("service_uri").(string),
+				Direction:  r.Get("direction").(string),
+				ProxyURI:   r.Get("proxy_uri").(string),
+			},
+		}
+	default:
+		return fmt.Errorf("%s: no serial port backing specified", r)
+	}
+	return nil
+}
+
+// selectSerialPorts returns the serial port devices in a device list.
+func selectSerialPorts(l object.VirtualDeviceList) object.VirtualDeviceList {
+	return l.Select(func(device types.BaseVirtualDevice) bool {
+		if _, ok := device.(*types.VirtualSerialPort); ok {
+			return true
+		}
+		return false
+	})
+}
+
+// portDatastorePath returns the full datastore path for a file backed serial
+// or parallel port.
+func portDatastorePath(ctx context.Context, client *govmomi.Client, dsID, path string) (string, error) {
+	ds, err := datastore.FromID(ctx, client, dsID)
+	if err != nil {
+		return "", fmt.Errorf("cannot find datastore: %s", err)
+	}
+	dsProps, err := datastore.Properties(ctx, ds)
+	if err != nil {
+		return "", fmt.Errorf("could not get properties for datastore: %s", err)
+	}
+	dsPath := &object.DatastorePath{
+		Datastore: dsProps.Name,
+		Path:      path,
+	}
+	return dsPath.String(), nil
+}
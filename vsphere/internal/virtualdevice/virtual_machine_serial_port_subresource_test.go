@@ -0,0 +1,138 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestSerialPortValidateDiff(t *testing.T) {
+	cases := []struct {
+		name      string
+		data      map[string]interface{}
+		expectErr bool
+	}{
+		{
+			name: "pipe",
+			data: map[string]interface{}{
+				"pipe_name":     `\\.\pipe\com1`,
+				"pipe_endpoint": "server",
+			},
+		},
+		{
+			name: "pipe without endpoint",
+			data: map[string]interface{}{
+				"pipe_name": `\\.\pipe\com1`,
+			},
+			expectErr: true,
+		},
+		{
+			name: "file",
+			data: map[string]interface{}{
+				"datastore_id": "datastore-1",
+				"path":         "vm/serial.log",
+			},
+		},
+		{
+			name: "file without datastore",
+			data: map[string]interface{}{
+				"path": "vm/serial.log",
+			},
+			expectErr: true,
+		},
+		{
+			name: "device",
+			data: map[string]interface{}{
+				"device_name": "/dev/char/serial/uart0",
+			},
+		},
+		{
+			name: "network",
+			data: map[string]interface{}{
+				"service_uri": "telnet://:5000",
+				"direction":   "server",
+			},
+		},
+		{
+			name: "network with direction on device",
+			data: map[string]interface{}{
+				"device_name": "/dev/char/serial/uart0",
+				"direction":   "server",
+			},
+			expectErr: true,
+		},
+		{
+			name: "multiple backings",
+			data: map[string]interface{}{
+				"device_name": "/dev/char/serial/uart0",
+				"service_uri": "telnet://:5000",
+				"direction":   "server",
+			},
+			expectErr: true,
+		},
+		{
+			name:      "no backing",
+			data:      map[string]interface{}{},
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, SerialPortSubresourceSchema(), tc.data)
+			m := make(map[string]interface{})
+			for k := range SerialPortSubresourceSchema() {
+				m[k] = d.Get(k)
+			}
+			err := NewSerialPortSubresource(nil, d, m, nil, 0).ValidateDiff()
+			if tc.expectErr && err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestSerialPortOperations_unmanaged(t *testing.T) {
+	sio := &types.VirtualSIOController{}
+	sio.Key = 400
+	port := &types.VirtualSerialPort{}
+	port.Key = 9000
+	port.ControllerKey = 400
+	port.UnitNumber = types.NewInt32(0)
+	port.Backing = &types.VirtualSerialPortPipeBackingInfo{
+		VirtualDevicePipeBackingInfo: types.VirtualDevicePipeBackingInfo{PipeName: "template"},
+		Endpoint:                     string(types.VirtualSerialPortEndPointClient),
+	}
+	l := object.VirtualDeviceList{sio, port}
+	s := map[string]*schema.Schema{
+		subresourceTypeSerialPort: {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Resource{Schema: SerialPortSubresourceSchema()},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{})
+	_, spec, err := SerialPortPostCloneOperation(context.Background(), d, nil, l)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(spec) != 0 {
+		t.Fatalf("expected template serial port to be kept, got %s", DeviceChangeString(spec))
+	}
+	if err := SerialPortRefreshOperation(d, nil, l); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual := d.Get(subresourceTypeSerialPort).([]interface{}); len(actual) != 0 {
+		t.Fatalf("expected unmanaged serial port to be left out of state, got %s", subresourceListString(actual))
+	}
+}
@@ -0,0 +1,421 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+var usbControllerTypeAllowedValues = []string{
+	SubresourceControllerTypeEHCI,
+	SubresourceControllerTypeXHCI,
+}
+
+// UsbControllerSubresourceSchema represents the schema for the
+// usb_controller sub-resource.
+func UsbControllerSubresourceSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "The type of USB controller. One of ehci (USB 2.0) or xhci (USB 3.x).",
+			ValidateFunc: validation.StringInSlice(usbControllerTypeAllowedValues, false),
+		},
+		"auto_connect_devices": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Automatically connect USB devices that are plugged into the client to the virtual machine.",
+		},
+	}
+	structure.MergeSchema(s, subresourceSchema())
+	return s
+}
+
+// UsbControllerSubresource represents a vsphere_virtual_machine
+// usb_controller sub-resource.
+//
+// A virtual machine can only have one USB controller of each type, so
+// controllers are matched to devices by their type rather than their device
+// address.
+type UsbControllerSubresource struct {
+	*Subresource
+}
+
+// NewUsbControllerSubresource returns a subresource populated with all of the
+// necessary fields.
+func NewUsbControllerSubresource(client *govmomi.Client, rdd resourceDataDiff, d, old map[string]interface{}, idx int) *UsbControllerSubresource {
+	sr := &UsbControllerSubresource{
+		Subresource: &Subresource{
+			schema:  UsbControllerSubresourceSchema(),
+			client:  client,
+			srtype:  subresourceTypeUsbController,
+			data:    d,
+			olddata: old,
+			rdd:     rdd,
+		},
+	}
+	sr.Index = idx
+	return sr
+}
+
+// UsbControllerDiffOperation performs validation on the usb_controller
+// sub-resources that can't be done in schema alone.
+func UsbControllerDiffOperation(d *schema.ResourceDiff) error {
+	log.Printf("[DEBUG] UsbControllerDiffOperation: Beginning diff validation")
+	seen := make(map[string]bool)
+	for _, ne := range d.Get(subresourceTypeUsbController).([]interface{}) {
+		ct := ne.(map[string]interface{})["type"].(string)
+		if seen[ct] {
+			return fmt.Errorf("only one usb_controller of type %q can be defined", ct)
+		}
+		seen[ct] = true
+	}
+	for i, ne := range d.Get(subresourceTypeUsbDevice).([]interface{}) {
+		ct := ne.(map[string]interface{})["controller_type"].(string)
+		switch {
+		case ct == "" && len(seen) == 0:
+			return fmt.Errorf("%s.%d: a usb_controller is required to attach a USB device", subresourceTypeUsbDevice, i)
+		case ct != "" && !seen[ct]:
+			return fmt.Errorf("%s.%d: no usb_controller of type %q is defined", subresourceTypeUsbDevice, i, ct)
+		}
+	}
+	log.Printf("[DEBUG] UsbControllerDiffOperation: Diff validation complete")
+	return nil
+}
+
+// UsbControllerApplyOperation processes an apply operation for all USB
+// controllers in the resource.
+//
+// Controllers are matched between the old and new configuration by their
+// type. Controllers whose type is no longer present are removed, new types are
+// created, and existing controllers are updated in place. Controllers that
+// were never in configuration are left alone.
+func UsbControllerApplyOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] UsbControllerApplyOperation: Beginning apply operation")
+	o, n := d.GetChange(subresourceTypeUsbController)
+	ods := usbControllersByType(o.([]interface{}))
+	nds := usbControllersByType(n.([]interface{}))
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+
+	log.Printf("[DEBUG] UsbControllerApplyOperation: Looking for resources to delete")
+	for ct, om := range ods {
+		if _, ok := nds[ct]; ok {
+			continue
+		}
+		r := NewUsbControllerSubresource(c, d, om, nil, 0)
+		dspec, err := r.Delete(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, dspec)
+		spec = append(spec, dspec...)
+	}
+
+	var updates []interface{}
+	log.Printf("[DEBUG] UsbControllerApplyOperation: Looking for resources to create or update")
+	for i, ne := range n.([]interface{}) {
+		nm := ne.(map[string]interface{})
+		om, ok := ods[nm["type"].(string)]
+		if !ok && findUsbController(l, nm["type"].(string)) != nil {
+			// A controller of this type exists but was not managed until now,
+			// such as one inherited from a template. Take it over rather than
+			// adding a second controller of the same type.
+			var uspec []types.BaseVirtualDeviceConfigSpec
+			var m map[string]interface{}
+			var err error
+			l, uspec, m, err = adoptUsbController(d, c, l, nm, i)
+			if err != nil {
+				return nil, nil, err
+			}
+			spec = append(spec, uspec...)
+			updates = append(updates, m)
+			continue
+		}
+		if !ok {
+			// New device
+			r := NewUsbControllerSubresource(c, d, nm, nil, i)
+			cspec, err := r.Create(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		// Carry the identifiers of the existing controller, as the list may
+		// have been re-ordered.
+		nm["key"] = om["key"]
+		nm["device_address"] = om["device_address"]
+		r := NewUsbControllerSubresource(c, d, nm, om, i)
+		if r.HasChange("auto_connect_devices") {
+			uspec, err := r.Update(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, uspec)
+			spec = append(spec, uspec...)
+		}
+		updates = append(updates, r.Data())
+	}
+
+	log.Printf("[DEBUG] UsbControllerApplyOperation: Post-apply final resource list: %s", subresourceListString(updates))
+	if err := d.Set(subresourceTypeUsbController, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] UsbControllerApplyOperation: Device config operations from apply: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] UsbControllerApplyOperation: Apply complete, returning updated spec")
+	return l, spec, nil
+}
+
+// UsbControllerRefreshOperation processes a refresh operation for all of the
+// USB controllers in the resource.
+//
+// Only controllers known in state are read. Controllers that were added
+// outside of Terraform, or inherited from a template, are not managed unless
+// they are added to configuration.
+func UsbControllerRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] UsbControllerRefreshOperation: Beginning refresh")
+	devices := selectUsbControllers(l)
+	log.Printf("[DEBUG] UsbControllerRefreshOperation: USB controllers located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeUsbController).([]interface{})
+	var newSet []interface{}
+	for i, item := range curSet {
+		m := item.(map[string]interface{})
+		ct := m["type"].(string)
+		if findUsbController(devices, ct) == nil {
+			log.Printf("[DEBUG] UsbControllerRefreshOperation: %s controller no longer present", ct)
+			continue
+		}
+		r := NewUsbControllerSubresource(c, d, m, nil, i)
+		if err := r.Read(l); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		newSet = append(newSet, r.Data())
+	}
+	log.Printf("[DEBUG] UsbControllerRefreshOperation: Refresh operation complete, sending new resource set: %s", subresourceListString(newSet))
+	return d.Set(subresourceTypeUsbController, newSet)
+}
+
+// UsbControllerPostCloneOperation normalizes the USB controllers on a
+// freshly-cloned virtual machine and outputs any necessary device change
+// operations. It also sets the state in advance of the post-create read.
+//
+// Controllers in configuration are created, or taken over from the source if
+// it has a controller of the same type. Other controllers of the source are
+// kept and are not added to state.
+func UsbControllerPostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] UsbControllerPostCloneOperation: Looking for post-clone device changes")
+	devices := selectUsbControllers(l)
+	log.Printf("[DEBUG] UsbControllerPostCloneOperation: USB controllers located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeUsbController).([]interface{})
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+	var updates []interface{}
+	for i, ci := range curSet {
+		cm := ci.(map[string]interface{})
+		if findUsbController(devices, cm["type"].(string)) == nil {
+			r := NewUsbControllerSubresource(c, d, cm, nil, i)
+			cspec, err := r.Create(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		var uspec []types.BaseVirtualDeviceConfigSpec
+		var m map[string]interface{}
+		var err error
+		l, uspec, m, err = adoptUsbController(d, c, l, cm, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		spec = append(spec, uspec...)
+		updates = append(updates, m)
+	}
+
+	log.Printf("[DEBUG] UsbControllerPostCloneOperation: Post-clone final resource list: %s", subresourceListString(updates))
+	if err := d.Set(subresourceTypeUsbController, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] UsbControllerPostCloneOperation: Device config operations from post-clone: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] UsbControllerPostCloneOperation: Operation complete, returning updated spec")
+	return l, spec, nil
+}
+
+// adoptUsbController brings an existing USB controller of the type in cm
+// under management, updating it to match the configuration in cm. It returns
+// the updated device list, the device change operations, and the data to save
+// to state.
+func adoptUsbController(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList, cm map[string]interface{}, i int) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, map[string]interface{}, error) {
+	sr := NewUsbControllerSubresource(c, d, map[string]interface{}{"type": cm["type"]}, nil, i)
+	if err := sr.Read(l); err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %s", sr.Addr(), err)
+	}
+	sm := sr.Data()
+	nm := structure.CopyMap(sm)
+	nm["auto_connect_devices"] = cm["auto_connect_devices"]
+	r := NewUsbControllerSubresource(c, d, nm, sm, i)
+	var spec []types.BaseVirtualDeviceConfigSpec
+	if r.HasChange("auto_connect_devices") {
+		var err error
+		spec, err = r.Update(l)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, spec)
+	}
+	return l, spec, r.Data(), nil
+}
+
+// Create creates a vsphere_virtual_machine usb_controller sub-resource.
+func (r *UsbControllerSubresource) Create(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Running create", r)
+	pci, err := r.ControllerForCreateUpdate(l, SubresourceControllerTypePCI, 0)
+	if err != nil {
+		return nil, err
+	}
+	var device types.BaseVirtualDevice
+	autoConnect := structure.BoolPtr(r.Get("auto_connect_devices").(bool))
+	switch r.Get("type").(string) {
+	case SubresourceControllerTypeXHCI:
+		device = &types.VirtualUSBXHCIController{AutoConnectDevices: autoConnect}
+	default:
+		device = &types.VirtualUSBController{
+			AutoConnectDevices: autoConnect,
+			EhciEnabled:        structure.BoolPtr(true),
+		}
+	}
+	vd := device.GetVirtualDevice()
+	vd.Key = l.NewKey()
+	vd.ControllerKey = pci.GetVirtualController().Key
+	// USB controllers cannot be added to a powered on virtual machine.
+	r.SetRestart("<device create>")
+	if err := r.SaveDevIDs(device, pci); err != nil {
+		return nil, err
+	}
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from create: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Create finished", r)
+	return spec, nil
+}
+
+// Read reads a vsphere_virtual_machine usb_controller sub-resource.
+func (r *UsbControllerSubresource) Read(l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] %s: Reading state", r)
+	device := findUsbController(l, r.Get("type").(string))
+	if device == nil {
+		return fmt.Errorf("cannot find %s USB controller", r.Get("type").(string))
+	}
+	switch ctlr := device.(type) {
+	case *types.VirtualUSBController:
+		r.Set("auto_connect_devices", ctlr.AutoConnectDevices != nil && *ctlr.AutoConnectDevices)
+	case *types.VirtualUSBXHCIController:
+		r.Set("auto_connect_devices", ctlr.AutoConnectDevices != nil && *ctlr.AutoConnectDevices)
+	}
+	ctlr, err := findControllerForDevice(l, device)
+	if err != nil {
+		return err
+	}
+	if err := r.SaveDevIDs(device, ctlr); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] %s: Read finished", r)
+	return nil
+}
+
+// Update updates a vsphere_virtual_machine usb_controller sub-resource.
+func (r *UsbControllerSubresource) Update(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning update", r)
+	device := findUsbController(l, r.Get("type").(string))
+	if device == nil {
+		return nil, fmt.Errorf("cannot find %s USB controller", r.Get("type").(string))
+	}
+	autoConnect := structure.BoolPtr(r.Get("auto_connect_devices").(bool))
+	switch ctlr := device.(type) {
+	case *types.VirtualUSBController:
+		ctlr.AutoConnectDevices = autoConnect
+	case *types.VirtualUSBXHCIController:
+		ctlr.AutoConnectDevices = autoConnect
+	}
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationEdit)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from update: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Update complete", r)
+	return spec, nil
+}
+
+// Delete deletes a vsphere_virtual_machine usb_controller sub-resource.
+func (r *UsbControllerSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning delete", r)
+	device := findUsbController(l, r.Get("type").(string))
+	if device == nil {
+		return nil, fmt.Errorf("cannot find %s USB controller", r.Get("type").(string))
+	}
+	r.SetRestart("<device delete>")
+	deleteSpec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationRemove)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from delete: %s", r, DeviceChangeString(deleteSpec))
+	log.Printf("[DEBUG] %s: Delete completed", r)
+	return deleteSpec, nil
+}
+
+// usbControllersByType indexes a usb_controller list by controller type.
+func usbControllersByType(l []interface{}) map[string]map[string]interface{} {
+	m := make(map[string]map[string]interface{})
+	for _, e := range l {
+		em := e.(map[string]interface{})
+		m[em["type"].(string)] = em
+	}
+	return m
+}
+
+// selectUsbControllers returns the USB controllers in a device list.
+func selectUsbControllers(l object.VirtualDeviceList) object.VirtualDeviceList {
+	return l.Select(func(device types.BaseVirtualDevice) bool {
+		switch device.(type) {
+		case *types.VirtualUSBController, *types.VirtualUSBXHCIController:
+			return true
+		}
+		return false
+	})
+}
+
+// findUsbController returns the USB controller of the supplied type in a
+// device list, or nil if there is none.
+func findUsbController(l object.VirtualDeviceList, ct string) types.BaseVirtualDevice {
+	for _, device := range l {
+		switch device.(type) {
+		case *types.VirtualUSBController:
+			if ct == SubresourceControllerTypeEHCI {
+				return device
+			}
+		case *types.VirtualUSBXHCIController:
+			if ct == SubresourceControllerTypeXHCI {
+				return device
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestFindUsbController(t *testing.T) {
+	ehci := &types.VirtualUSBController{}
+	ehci.Key = 7000
+	xhci := &types.VirtualUSBXHCIController{}
+	xhci.Key = 14000
+	l := object.VirtualDeviceList{&types.VirtualPCIController{}, ehci, xhci}
+
+	cases := []struct {
+		name     string
+		list     object.VirtualDeviceList
+		ct       string
+		expected int32
+	}{
+		{
+			name:     "ehci",
+			list:     l,
+			ct:       SubresourceControllerTypeEHCI,
+			expected: 7000,
+		},
+		{
+			name:     "xhci",
+			list:     l,
+			ct:       SubresourceControllerTypeXHCI,
+			expected: 14000,
+		},
+		{
+			name: "missing",
+			list: object.VirtualDeviceList{ehci},
+			ct:   SubresourceControllerTypeXHCI,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := findUsbController(tc.list, tc.ct)
+			if tc.expected == 0 {
+				if device != nil {
+					t.Fatalf("expected no controller, got key %d", device.GetVirtualDevice().Key)
+				}
+				return
+			}
+			if device == nil {
+				t.Fatalf("expected controller with key %d, got none", tc.expected)
+			}
+			if actual := device.GetVirtualDevice().Key; actual != tc.expected {
+				t.Fatalf("expected controller with key %d, got %d", tc.expected, actual)
+			}
+			ct, err := controllerTypeToClass(device.(types.BaseVirtualController))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if ct != tc.ct {
+				t.Fatalf("expected controller class %q, got %q", tc.ct, ct)
+			}
+		})
+	}
+}
+
+func testUsbControllerResourceData(t *testing.T, controllers []interface{}) *schema.ResourceData {
+	s := map[string]*schema.Schema{
+		subresourceTypeUsbController: {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Resource{Schema: UsbControllerSubresourceSchema()},
+		},
+	}
+	raw := map[string]interface{}{}
+	if controllers != nil {
+		raw[subresourceTypeUsbController] = controllers
+	}
+	return schema.TestResourceDataRaw(t, s, raw)
+}
+
+func testUsbControllerDeviceList() object.VirtualDeviceList {
+	pci := &types.VirtualPCIController{}
+	pci.Key = 100
+	ehci := &types.VirtualUSBController{}
+	ehci.Key = 7000
+	ehci.ControllerKey = 100
+	return object.VirtualDeviceList{pci, ehci}
+}
+
+func TestUsbControllerPostCloneOperation_unmanaged(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   []interface{}
+		expected []string
+		add      bool
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:     "existing type",
+			config:   []interface{}{map[string]interface{}{"type": SubresourceControllerTypeEHCI}},
+			expected: []string{SubresourceControllerTypeEHCI},
+		},
+		{
+			name:     "new type",
+			config:   []interface{}{map[string]interface{}{"type": SubresourceControllerTypeXHCI}},
+			expected: []string{SubresourceControllerTypeXHCI},
+			add:      true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := testUsbControllerResourceData(t, tc.config)
+			l, spec, err := UsbControllerPostCloneOperation(d, nil, testUsbControllerDeviceList())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for _, s := range spec {
+				if op := s.GetVirtualDeviceConfigSpec().Operation; op == types.VirtualDeviceConfigSpecOperationRemove {
+					t.Fatalf("expected template controller to be kept, got %s", DeviceChangeString(spec))
+				}
+			}
+			if tc.add != (len(spec) == 1) {
+				t.Fatalf("expected add to be %t, got %s", tc.add, DeviceChangeString(spec))
+			}
+			if findUsbController(l, SubresourceControllerTypeEHCI) == nil {
+				t.Fatal("expected ehci controller to remain in device list")
+			}
+			actual := d.Get(subresourceTypeUsbController).([]interface{})
+			if len(actual) != len(tc.expected) {
+				t.Fatalf("expected %d controllers in state, got %s", len(tc.expected), subresourceListString(actual))
+			}
+			for i, ct := range tc.expected {
+				if actual[i].(map[string]interface{})["type"] != ct {
+					t.Fatalf("expected %s controller in state, got %s", ct, subresourceListString(actual))
+				}
+			}
+		})
+	}
+}
+
+func TestUsbControllerRefreshOperation_unmanaged(t *testing.T) {
+	d := testUsbControllerResourceData(t, nil)
+	if err := UsbControllerRefreshOperation(d, nil, testUsbControllerDeviceList()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual := d.Get(subresourceTypeUsbController).([]interface{}); len(actual) != 0 {
+		t.Fatalf("expected unmanaged controller to be left out of state, got %s", subresourceListString(actual))
+	}
+}
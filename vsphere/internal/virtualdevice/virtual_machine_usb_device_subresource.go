@@ -0,0 +1,524 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+// UsbDeviceSubresourceSchema represents the schema for the usb_device
+// sub-resource.
+func UsbDeviceSubresourceSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"device_name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "The name of the USB device on the host, such as \"path:1/0/1 version:2\".",
+			ValidateFunc: validation.NoZeroValues,
+		},
+		"controller_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			Description:  "The type of USB controller the device is attached to. One of ehci or xhci. Defaults to the xhci controller when one is defined.",
+			ValidateFunc: validation.StringInSlice(usbControllerTypeAllowedValues, false),
+		},
+	}
+	structure.MergeSchema(s, subresourceSchema())
+	return s
+}
+
+// UsbDeviceSubresource represents a vsphere_virtual_machine usb_device
+// sub-resource, with a complex device lifecycle.
+type UsbDeviceSubresource struct {
+	*Subresource
+}
+
+// NewUsbDeviceSubresource returns a subresource populated with all of the
+// necessary fields.
+func NewUsbDeviceSubresource(client *govmomi.Client, rdd resourceDataDiff, d, old map[string]interface{}, idx int) *UsbDeviceSubresource {
+	sr := &UsbDeviceSubresource{
+		Subresource: &Subresource{
+			schema:  UsbDeviceSubresourceSchema(),
+			client:  client,
+			srtype:  subresourceTypeUsbDevice,
+			data:    d,
+			olddata: old,
+			rdd:     rdd,
+		},
+	}
+	sr.Index = idx
+	return sr
+}
+
+// UsbDeviceApplyOperation processes an apply operation for all USB devices
+// in the resource.
+//
+// The function takes the root resource's ResourceData, the provider
+// connection, and the device list as known to vSphere at the start of this
+// operation. All USB device operations are carried out, with both the
+// complete, updated, VirtualDeviceList, and the complete list of changes
+// returned as a slice of BaseVirtualDeviceConfigSpec.
+func UsbDeviceApplyOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] UsbDeviceApplyOperation: Beginning apply operation")
+	o, n := d.GetChange(subresourceTypeUsbDevice)
+	ods := o.([]interface{})
+	nds := n.([]interface{})
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+
+	// Our old and new sets now have an accurate description of devices that may
+	// have been added, removed, or changed. Look for removed devices first.
+	log.Printf("[DEBUG] UsbDeviceApplyOperation: Looking for resources to delete")
+nextOld:
+	for n, oe := range ods {
+		om := oe.(map[string]interface{})
+		for _, ne := range nds {
+			nm := ne.(map[string]interface{})
+			if om["key"] == nm["key"] {
+				continue nextOld
+			}
+		}
+		r := NewUsbDeviceSubresource(c, d, om, nil, n)
+		dspec, err := r.Delete(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, dspec)
+		spec = append(spec, dspec...)
+	}
+
+	// Now check for creates and updates. The results of this operation are
+	// committed to state after the operation completes.
+	var updates []interface{}
+	log.Printf("[DEBUG] UsbDeviceApplyOperation: Looking for resources to create or update")
+	for n, ne := range nds {
+		nm := ne.(map[string]interface{})
+		if n < len(ods) {
+			// This is an update
+			oe := ods[n]
+			om := oe.(map[string]interface{})
+			if nm["key"] != om["key"] {
+				return nil, nil, fmt.Errorf("key mismatch on %s.%d (old: %d, new: %d). This is a bug with the provider, please report it", subresourceTypeUsbDevice, n, nm["key"].(int), om["key"].(int))
+			}
+			if reflect.DeepEqual(nm, om) {
+				// no change is a no-op
+				updates = append(updates, nm)
+				log.Printf("[DEBUG] UsbDeviceApplyOperation: No-op resource: key %d", nm["key"].(int))
+				continue
+			}
+			r := NewUsbDeviceSubresource(c, d, nm, om, n)
+			uspec, err := r.Update(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, uspec)
+			spec = append(spec, uspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		// New device
+		r := NewUsbDeviceSubresource(c, d, nm, nil, n)
+		cspec, err := r.Create(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, cspec)
+		spec = append(spec, cspec...)
+		updates = append(updates, r.Data())
+	}
+
+	log.Printf("[DEBUG] UsbDeviceApplyOperation: Post-apply final resource list: %s", subresourceListString(updates))
+	// We are now done! Return the updated device list and config spec. Save updates as well.
+	if err := d.Set(subresourceTypeUsbDevice, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] UsbDeviceApplyOperation: Device list at end of operation: %s", DeviceListString(l))
+	log.Printf("[DEBUG] UsbDeviceApplyOperation: Device config operations from apply: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] UsbDeviceApplyOperation: Apply complete, returning updated spec")
+	return l, spec, nil
+}
+
+// UsbDeviceRefreshOperation processes a refresh operation for all of the
+// USB devices in the resource.
+//
+// This functions similar to UsbDeviceApplyOperation, but nothing to change
+// is returned, all necessary values are just set and committed to state.
+func UsbDeviceRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Beginning refresh")
+	devices := selectUsbDevices(l)
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: USB devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeUsbDevice).([]interface{})
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Current resource set from state: %s", subresourceListString(curSet))
+	var newSet []interface{}
+	// First check for negative keys. These are freshly added devices that are
+	// usually coming into read post-create.
+	//
+	// If we find what we are looking for, we remove the device from the working
+	// set so that we don't try and process it in the next few passes.
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Looking for freshly-created resources to read in")
+	for n, item := range curSet {
+		m := item.(map[string]interface{})
+		if m["key"].(int) < 1 {
+			r := NewUsbDeviceSubresource(c, d, m, nil, n)
+			if err := r.Read(l); err != nil {
+				return fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			if r.Get("key").(int) < 1 {
+				// This should not have happened - if it did, our device
+				// creation/update logic failed somehow that we were not able to track.
+				return fmt.Errorf("device %d with address %s still unaccounted for after update/read", r.Get("key").(int), r.Get("device_address").(string))
+			}
+			newSet = append(newSet, r.Data())
+			for i := 0; i < len(devices); i++ {
+				device := devices[i]
+				if device.GetVirtualDevice().Key == int32(r.Get("key").(int)) {
+					devices = append(devices[:i], devices[i+1:]...)
+					i--
+				}
+			}
+		}
+	}
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: USB devices after freshly-created device search: %s", DeviceListString(devices))
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Resource set to write after freshly-created device search: %s", subresourceListString(newSet))
+
+	// Go over the remaining devices, refresh via key, and then remove their
+	// entries as well.
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Looking for devices known in state")
+	for i := 0; i < len(devices); i++ {
+		device := devices[i]
+		for n, item := range curSet {
+			m := item.(map[string]interface{})
+			if m["key"].(int) < 0 {
+				// Skip any of these keys as we won't be matching any of those anyway here
+				continue
+			}
+			if device.GetVirtualDevice().Key != int32(m["key"].(int)) {
+				// Skip any device that doesn't match key as well
+				continue
+			}
+			// We should have our device -> resource match, so read now.
+			r := NewUsbDeviceSubresource(c, d, m, nil, n)
+			if err := r.Read(l); err != nil {
+				return fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			// Done reading, push this onto our new set and remove the device from
+			// the list
+			newSet = append(newSet, r.Data())
+			devices = append(devices[:i], devices[i+1:]...)
+			i--
+		}
+	}
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Resource set to write after known device search: %s", subresourceListString(newSet))
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Probable orphaned USB devices: %s", DeviceListString(devices))
+
+	// Finally, any device that is still here is orphaned. They should be added
+	// as new devices.
+	for n, device := range devices {
+		m := make(map[string]interface{})
+		vd := device.GetVirtualDevice()
+		ctlr := l.FindByKey(vd.ControllerKey)
+		if ctlr == nil {
+			return fmt.Errorf("could not find controller with key %d", vd.Key)
+		}
+		m["key"] = int(vd.Key)
+		var err error
+		m["device_address"], err = computeDevAddr(vd, ctlr.(types.BaseVirtualController))
+		if err != nil {
+			return fmt.Errorf("error computing device address: %s", err)
+		}
+		r := NewUsbDeviceSubresource(c, d, m, nil, n)
+		if err := r.Read(l); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		newSet = append(newSet, r.Data())
+	}
+
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Resource set to write after adding orphaned devices: %s", subresourceListString(newSet))
+	log.Printf("[DEBUG] UsbDeviceRefreshOperation: Refresh operation complete, sending new resource set")
+	return d.Set(subresourceTypeUsbDevice, newSet)
+}
+
+// UsbDevicePostCloneOperation normalizes USB devices on a freshly-cloned
+// virtual machine and outputs any necessary device change operations. It also
+// sets the state in advance of the post-create read.
+//
+// This differs from a regular apply operation in that a configuration is
+// already present, but we don't have any existing state, which the standard
+// virtual device operations rely pretty heavily on.
+func UsbDevicePostCloneOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: Looking for post-clone device changes")
+	devices := selectUsbDevices(l)
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: USB devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeUsbDevice).([]interface{})
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: Current resource set from configuration: %s", subresourceListString(curSet))
+	var srcSet []interface{}
+
+	// Populate the source set as if the devices were orphaned. This give us a
+	// base to diff off of.
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: Reading existing devices")
+	for n, device := range devices {
+		m := make(map[string]interface{})
+		vd := device.GetVirtualDevice()
+		ctlr := l.FindByKey(vd.ControllerKey)
+		if ctlr == nil {
+			return nil, nil, fmt.Errorf("could not find controller with key %d", vd.Key)
+		}
+		m["key"] = int(vd.Key)
+		var err error
+		m["device_address"], err = computeDevAddr(vd, ctlr.(types.BaseVirtualController))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error computing device address: %s", err)
+		}
+		r := NewUsbDeviceSubresource(c, d, m, nil, n)
+		if err := r.Read(l); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		srcSet = append(srcSet, r.Data())
+	}
+
+	// Now go over our current set, kind of treating it like an apply:
+	//
+	// * Device past the boundaries of existing devices are created
+	// * Devices within the bounds are changed changed
+	// * Data at the source with the same data after patching config data is a
+	// no-op, but we still push the device's state
+	var spec []types.BaseVirtualDeviceConfigSpec
+	var updates []interface{}
+	for i, ci := range curSet {
+		cm := ci.(map[string]interface{})
+		if i > len(srcSet)-1 {
+			// New device
+			r := NewUsbDeviceSubresource(c, d, cm, nil, i)
+			cspec, err := r.Create(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		sm := srcSet[i].(map[string]interface{})
+		nm := structure.CopyMap(sm)
+		for k, v := range cm {
+			// Skip key and device_address here
+			switch k {
+			case "key", "device_address":
+				continue
+			}
+			nm[k] = v
+		}
+		r := NewUsbDeviceSubresource(c, d, nm, sm, i)
+		if !reflect.DeepEqual(sm, nm) {
+			// Update
+			cspec, err := r.Update(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+		}
+		updates = append(updates, r.Data())
+	}
+
+	// Any other device past the end of the USB devices listed in config needs
+	// to be removed.
+	if len(curSet) < len(srcSet) {
+		for i, si := range srcSet[len(curSet):] {
+			sm := si.(map[string]interface{})
+			r := NewUsbDeviceSubresource(c, d, sm, nil, i+len(curSet))
+			dspec, err := r.Delete(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, dspec)
+			spec = append(spec, dspec...)
+		}
+	}
+
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: Post-clone final resource list: %s", subresourceListString(updates))
+	// We are now done! Return the updated device list and config spec. Save updates as well.
+	if err := d.Set(subresourceTypeUsbDevice, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: Device list at end of operation: %s", DeviceListString(l))
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: Device config operations from post-clone: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] UsbDevicePostCloneOperation: Operation complete, returning updated spec")
+	return l, spec, nil
+}
+
+// Create creates a vsphere_virtual_machine usb_device sub-resource.
+func (r *UsbDeviceSubresource) Create(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Running create", r)
+	ct := r.Get("controller_type").(string)
+	if ct == "" {
+		ct = SubresourceControllerTypeXHCI
+		if findUsbController(l, ct) == nil {
+			ct = SubresourceControllerTypeEHCI
+		}
+	}
+	c := findUsbController(l, ct)
+	if c == nil {
+		return nil, fmt.Errorf("could not find an available %s USB controller", ct)
+	}
+	ctlr := c.(types.BaseVirtualController)
+	device := &types.VirtualUSB{}
+	l.AssignController(device, ctlr)
+	r.mapUsbDevice(device)
+	r.Set("controller_type", ct)
+	// Done here. Save IDs, push the device to the new device list and return.
+	if err := r.SaveDevIDs(device, ctlr); err != nil {
+		return nil, err
+	}
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from create: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Create finished", r)
+	return spec, nil
+}
+
+// Read reads a vsphere_virtual_machine usb_device sub-resource.
+func (r *UsbDeviceSubresource) Read(l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] %s: Reading state", r)
+	device, err := r.findUsbDevice(l)
+	if err != nil {
+		return fmt.Errorf("cannot find USB device: %s", err)
+	}
+	switch backing := device.Backing.(type) {
+	case *types.VirtualUSBUSBBackingInfo:
+		r.Set("device_name", backing.DeviceName)
+	case *types.VirtualUSBRemoteHostBackingInfo:
+		r.Set("device_name", backing.DeviceName)
+	default:
+		log.Printf("[DEBUG] %s: Unknown USB device backing type %T, clearing device name", r, backing)
+		r.Set("device_name", "")
+	}
+	ctlr, err := findControllerForDevice(l, device)
+	if err != nil {
+		return err
+	}
+	ct, err := controllerTypeToClass(ctlr)
+	if err != nil {
+		return err
+	}
+	r.Set("controller_type", ct)
+	if err := r.SaveDevIDs(device, ctlr); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] %s: Read finished (key and device address may have changed)", r)
+	return nil
+}
+
+// Update updates a vsphere_virtual_machine usb_device sub-resource.
+//
+// A USB device cannot be moved between controllers, so a change to
+// controller_type removes the device and attaches it to the new controller.
+func (r *UsbDeviceSubresource) Update(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning update", r)
+	if r.HasChange("controller_type") {
+		spec, err := r.Delete(l)
+		if err != nil {
+			return nil, err
+		}
+		l = applyDeviceChange(l, spec)
+		cspec, err := r.Create(l)
+		if err != nil {
+			return nil, err
+		}
+		spec = append(spec, cspec...)
+		log.Printf("[DEBUG] %s: Device config operations from update: %s", r, DeviceChangeString(spec))
+		log.Printf("[DEBUG] %s: Update complete", r)
+		return spec, nil
+	}
+	device, err := r.findUsbDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find USB device: %s", err)
+	}
+	r.mapUsbDevice(device)
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationEdit)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from update: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Update complete", r)
+	return spec, nil
+}
+
+// Delete deletes a vsphere_virtual_machine usb_device sub-resource.
+func (r *UsbDeviceSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning delete", r)
+	device, err := r.findUsbDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find USB device: %s", err)
+	}
+	deleteSpec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationRemove)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from delete: %s", r, DeviceChangeString(deleteSpec))
+	log.Printf("[DEBUG] %s: Delete completed", r)
+	return deleteSpec, nil
+}
+
+// findUsbDevice locates the USB device for this subresource. Known devices
+// are located by their key. USB devices are assigned a port by vSphere when
+// they are connected, so freshly-created devices are located by their device
+// name instead of their device address.
+func (r *UsbDeviceSubresource) findUsbDevice(l object.VirtualDeviceList) (*types.VirtualUSB, error) {
+	if key := r.Get("key").(int); key > 0 {
+		d, err := r.FindVirtualDevice(l)
+		if err != nil {
+			return nil, err
+		}
+		device, ok := d.(*types.VirtualUSB)
+		if !ok {
+			return nil, fmt.Errorf("device at %q is not a virtual USB device", l.Name(d))
+		}
+		return device, nil
+	}
+	name := r.Get("device_name").(string)
+	for _, d := range selectUsbDevices(l) {
+		device := d.(*types.VirtualUSB)
+		if b, ok := device.Backing.(types.BaseVirtualDeviceDeviceBackingInfo); ok && b.GetVirtualDeviceDeviceBackingInfo().DeviceName == name {
+			return device, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find USB device with name %q", name)
+}
+
+// mapUsbDevice sets the backing of a USB device from the subresource data.
+func (r *UsbDeviceSubresource) mapUsbDevice(device *types.VirtualUSB) {
+	device.Backing = &types.VirtualUSBUSBBackingInfo{
+		VirtualDeviceDeviceBackingInfo: types.VirtualDeviceDeviceBackingInfo{
+			DeviceName: r.Get("device_name").(string),
+		},
+	}
+	device.Connectable = &types.VirtualDeviceConnectInfo{
+		StartConnected: true,
+		Connected:      true,
+	}
+}
+
+// selectUsbDevices returns the USB devices in a device list.
+func selectUsbDevices(l object.VirtualDeviceList) object.VirtualDeviceList {
+	return l.Select(func(device types.BaseVirtualDevice) bool {
+		if _, ok := device.(*types.VirtualUSB); ok {
+			return true
+		}
+		return false
+	})
+}
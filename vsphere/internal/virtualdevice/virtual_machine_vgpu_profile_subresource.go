@@ -0,0 +1,455 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package virtualdevice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+// VgpuProfileSubresourceSchema represents the schema for the
+// vgpu_profile sub-resource.
+func VgpuProfileSubresourceSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"profile": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The name of the vGPU profile, as returned by the vsphere_host_vgpu_profile data source.",
+		},
+	}
+	structure.MergeSchema(s, subresourceSchema())
+	return s
+}
+
+// VgpuProfileSubresource represents a vsphere_virtual_machine
+// vgpu_profile sub-resource, with a complex device lifecycle.
+type VgpuProfileSubresource struct {
+	*Subresource
+}
+
+// NewVgpuProfileSubresource returns a subresource populated with all of
+// the necessary fields.
+func NewVgpuProfileSubresource(client *govmomi.Client, rdd resourceDataDiff, d, old map[string]interface{}, idx int) *VgpuProfileSubresource {
+	sr := &VgpuProfileSubresource{
+		Subresource: &Subresource{
+			schema:  VgpuProfileSubresourceSchema(),
+			client:  client,
+			srtype:  subresourceTypeVgpuProfile,
+			data:    d,
+			olddata: old,
+			rdd:     rdd,
+		},
+	}
+	sr.Index = idx
+	return sr
+}
+
+// VgpuProfileApplyOperation processes an apply operation for all
+// vGPU devices in the resource.
+//
+// The function takes the root resource's ResourceData, the provider
+// connection, and the device list as known to vSphere at the start of this
+// operation. All device operations are carried out, with both the complete,
+// updated, VirtualDeviceList, and the complete list of changes returned as a
+// slice of BaseVirtualDeviceConfigSpec.
+//
+// When there are no vGPU devices in state, the existing devices are taken
+// over as in VgpuProfilePostCloneOperation.
+func VgpuProfileApplyOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] VgpuProfileApplyOperation: Beginning apply operation")
+	o, n := d.GetChange(subresourceTypeVgpuProfile)
+	ods := o.([]interface{})
+	nds := n.([]interface{})
+	if len(ods) < 1 && len(nds) > 0 {
+		// The vGPU devices of the virtual machine are not managed until the
+		// block is added to configuration. Take them over the same way as after
+		// a clone.
+		log.Printf("[DEBUG] VgpuProfileApplyOperation: No vGPU devices in state, taking over existing devices")
+		return VgpuProfilePostCloneOperation(ctx, d, c, l)
+	}
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+
+	// Look for removed devices first.
+	log.Printf("[DEBUG] VgpuProfileApplyOperation: Looking for resources to delete")
+nextOld:
+	for n, oe := range ods {
+		om := oe.(map[string]interface{})
+		for _, ne := range nds {
+			nm := ne.(map[string]interface{})
+			if om["key"] == nm["key"] {
+				continue nextOld
+			}
+		}
+		r := NewVgpuProfileSubresource(c, d, om, nil, n)
+		dspec, err := r.Delete(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, dspec)
+		spec = append(spec, dspec...)
+	}
+
+	// Now check for creates and updates. The results of this operation are
+	// committed to state after the operation completes.
+	var updates []interface{}
+	log.Printf("[DEBUG] VgpuProfileApplyOperation: Looking for resources to create or update")
+	for n, ne := range nds {
+		nm := ne.(map[string]interface{})
+		if n < len(ods) {
+			// This is an update
+			om := ods[n].(map[string]interface{})
+			if nm["key"] != om["key"] {
+				return nil, nil, fmt.Errorf("key mismatch on %s.%d (old: %d, new: %d). This is a bug with the provider, please report it", subresourceTypeVgpuProfile, n, nm["key"].(int), om["key"].(int))
+			}
+			if reflect.DeepEqual(nm, om) {
+				// no change is a no-op
+				updates = append(updates, nm)
+				log.Printf("[DEBUG] VgpuProfileApplyOperation: No-op resource: key %d", nm["key"].(int))
+				continue
+			}
+			r := NewVgpuProfileSubresource(c, d, nm, om, n)
+			uspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, uspec)
+			spec = append(spec, uspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		// New device
+		r := NewVgpuProfileSubresource(c, d, nm, nil, n)
+		cspec, err := r.Create(ctx, l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		l = applyDeviceChange(l, cspec)
+		spec = append(spec, cspec...)
+		updates = append(updates, r.Data())
+	}
+
+	log.Printf("[DEBUG] VgpuProfileApplyOperation: Post-apply final resource list: %s", subresourceListString(updates))
+	if err := d.Set(subresourceTypeVgpuProfile, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] VgpuProfileApplyOperation: Device config operations from apply: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] VgpuProfileApplyOperation: Apply complete, returning updated spec")
+	return l, spec, nil
+}
+
+// VgpuProfileRefreshOperation processes a refresh operation for all of
+// the vGPU devices in the resource.
+//
+// Devices are matched by key. Freshly-created devices do not have an address
+// until vSphere places them on the PCI bus, so they are matched by their
+// backing to a device that has not been claimed by another entry.
+func VgpuProfileRefreshOperation(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] VgpuProfileRefreshOperation: Beginning refresh")
+	devices := selectPciPassthroughDevices(l, isVgpuBacking)
+	log.Printf("[DEBUG] VgpuProfileRefreshOperation: vGPU devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeVgpuProfile).([]interface{})
+	log.Printf("[DEBUG] VgpuProfileRefreshOperation: Current resource set from state: %s", subresourceListString(curSet))
+
+	claimed := claimedDeviceKeys(curSet, devices)
+	var newSet []interface{}
+	for n, item := range curSet {
+		m := item.(map[string]interface{})
+		r := NewVgpuProfileSubresource(c, d, m, nil, n)
+		if m["key"].(int) > 0 && !claimed[int32(m["key"].(int))] {
+			log.Printf("[DEBUG] VgpuProfileRefreshOperation: %s: device no longer present", r)
+			continue
+		}
+		if err := r.Read(unclaimedDevices(l, claimed, m["key"].(int))); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		claimed[int32(r.Get("key").(int))] = true
+		newSet = append(newSet, r.Data())
+	}
+
+	// Any device that is still unclaimed is orphaned, and is added as a new
+	// device so that it is removed on the next apply, but only when vGPU
+	// devices are managed. Devices of a virtual machine without a vgpu_profile
+	// block in configuration are left alone.
+	if len(curSet) < 1 {
+		devices = nil
+	}
+	for _, device := range devices {
+		key := device.GetVirtualDevice().Key
+		if claimed[key] {
+			continue
+		}
+		r := NewVgpuProfileSubresource(c, d, map[string]interface{}{"key": int(key)}, nil, len(newSet))
+		if err := r.Read(l); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		newSet = append(newSet, r.Data())
+	}
+
+	log.Printf("[DEBUG] VgpuProfileRefreshOperation: Refresh operation complete, sending new resource set: %s", subresourceListString(newSet))
+	return d.Set(subresourceTypeVgpuProfile, newSet)
+}
+
+// VgpuProfilePostCloneOperation normalizes vGPU devices on a freshly-cloned virtual machine and outputs any necessary
+// device change operations. It also sets the state in advance of the
+// post-create read.
+func VgpuProfilePostCloneOperation(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) (object.VirtualDeviceList, []types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] VgpuProfilePostCloneOperation: Looking for post-clone device changes")
+	devices := selectPciPassthroughDevices(l, isVgpuBacking)
+	log.Printf("[DEBUG] VgpuProfilePostCloneOperation: vGPU devices located: %s", DeviceListString(devices))
+	curSet := d.Get(subresourceTypeVgpuProfile).([]interface{})
+	if len(curSet) < 1 {
+		// Keep the devices of the source when they are not in configuration.
+		log.Printf("[DEBUG] VgpuProfilePostCloneOperation: No vGPU devices in configuration, leaving existing devices alone")
+		return l, nil, nil
+	}
+	var srcSet []interface{}
+
+	// Populate the source set as if the devices were orphaned. This give us a
+	// base to diff off of.
+	for n, device := range devices {
+		r := NewVgpuProfileSubresource(c, d, map[string]interface{}{"key": int(device.GetVirtualDevice().Key)}, nil, n)
+		if err := r.Read(l); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+		srcSet = append(srcSet, r.Data())
+	}
+
+	var spec []types.BaseVirtualDeviceConfigSpec
+	var updates []interface{}
+	for i, ci := range curSet {
+		cm := ci.(map[string]interface{})
+		if i > len(srcSet)-1 {
+			// New device
+			r := NewVgpuProfileSubresource(c, d, cm, nil, i)
+			cspec, err := r.Create(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+			updates = append(updates, r.Data())
+			continue
+		}
+		sm := srcSet[i].(map[string]interface{})
+		nm := structure.CopyMap(sm)
+		nm["profile"] = cm["profile"]
+		r := NewVgpuProfileSubresource(c, d, nm, sm, i)
+		if !reflect.DeepEqual(sm, nm) {
+			cspec, err := r.Update(ctx, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, cspec)
+			spec = append(spec, cspec...)
+		}
+		updates = append(updates, r.Data())
+	}
+
+	// Any other device past the end of the devices listed in config needs to
+	// be removed.
+	if len(curSet) < len(srcSet) {
+		for i, si := range srcSet[len(curSet):] {
+			r := NewVgpuProfileSubresource(c, d, si.(map[string]interface{}), nil, i+len(curSet))
+			dspec, err := r.Delete(l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", r.Addr(), err)
+			}
+			l = applyDeviceChange(l, dspec)
+			spec = append(spec, dspec...)
+		}
+	}
+
+	log.Printf("[DEBUG] VgpuProfilePostCloneOperation: Post-clone final resource list: %s", subresourceListString(updates))
+	if err := d.Set(subresourceTypeVgpuProfile, updates); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[DEBUG] VgpuProfilePostCloneOperation: Device config operations from post-clone: %s", DeviceChangeString(spec))
+	log.Printf("[DEBUG] VgpuProfilePostCloneOperation: Operation complete, returning updated spec")
+	return l, spec, nil
+}
+
+// VgpuProfileDiffOperation checks that the hosts the virtual machine can be
+// placed on support the profile of each vgpu_profile sub-resource. The check
+// is skipped when the placement of the virtual machine or the attributes of the
+// device are not known until apply, in which case devices are checked when
+// they are created.
+func VgpuProfileDiffOperation(ctx context.Context, d *schema.ResourceDiff, c *govmomi.Client) error {
+	nds := d.Get(subresourceTypeVgpuProfile).([]interface{})
+	if len(nds) < 1 || !(d.HasChange(subresourceTypeVgpuProfile) || d.HasChange("host_system_id") || d.HasChange("resource_pool_id")) {
+		return nil
+	}
+	if !placementHostsKnown(d) {
+		log.Printf("[DEBUG] VgpuProfileDiffOperation: Placement not known until apply, skipping validation")
+		return nil
+	}
+	log.Printf("[DEBUG] VgpuProfileDiffOperation: Beginning diff validation")
+	hosts, err := placementHosts(ctx, c, d)
+	if err != nil {
+		return err
+	}
+	for i, ne := range nds {
+		if !d.NewValueKnown(fmt.Sprintf("%s.%d.profile", subresourceTypeVgpuProfile, i)) {
+			continue
+		}
+		r := NewVgpuProfileSubresource(c, d, ne.(map[string]interface{}), nil, i)
+		if err := r.validateHostDevice(hosts); err != nil {
+			return fmt.Errorf("%s: %s", r.Addr(), err)
+		}
+	}
+	log.Printf("[DEBUG] VgpuProfileDiffOperation: Diff validation complete")
+	return nil
+}
+
+// Create creates a vsphere_virtual_machine vgpu_profile sub-resource.
+func (r *VgpuProfileSubresource) Create(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Running create", r)
+	hosts, err := placementHosts(ctx, r.client, r.rdd)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateHostDevice(hosts); err != nil {
+		return nil, err
+	}
+	device := &types.VirtualPCIPassthrough{}
+	device.Key = l.NewKey()
+	if err := r.mapBacking(device); err != nil {
+		return nil, err
+	}
+	// PCI passthrough devices cannot be added to a powered on virtual machine.
+	r.SetRestart("<device create>")
+	// The device is placed on the PCI bus by vSphere, so only the key can be
+	// saved here.
+	r.Set("key", device.Key)
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from create: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Create finished", r)
+	return spec, nil
+}
+
+// Read reads a vsphere_virtual_machine vgpu_profile sub-resource.
+func (r *VgpuProfileSubresource) Read(l object.VirtualDeviceList) error {
+	log.Printf("[DEBUG] %s: Reading state", r)
+	device, err := r.findDevice(l)
+	if err != nil {
+		return fmt.Errorf("cannot find vGPU device: %s", err)
+	}
+	backing := device.Backing.(*types.VirtualPCIPassthroughVmiopBackingInfo)
+	r.Set("profile", backing.Vgpu)
+	return r.saveDevIDs(l, device)
+}
+
+// Update updates a vsphere_virtual_machine vgpu_profile sub-resource.
+func (r *VgpuProfileSubresource) Update(ctx context.Context, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning update", r)
+	hosts, err := placementHosts(ctx, r.client, r.rdd)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validateHostDevice(hosts); err != nil {
+		return nil, err
+	}
+	device, err := r.findDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find vGPU device: %s", err)
+	}
+	if err := r.mapBacking(device); err != nil {
+		return nil, err
+	}
+	r.SetRestart("<device update>")
+	spec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationEdit)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from update: %s", r, DeviceChangeString(spec))
+	log.Printf("[DEBUG] %s: Update complete", r)
+	return spec, nil
+}
+
+// Delete deletes a vsphere_virtual_machine vgpu_profile sub-resource.
+func (r *VgpuProfileSubresource) Delete(l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	log.Printf("[DEBUG] %s: Beginning delete", r)
+	device, err := r.findDevice(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find vGPU device: %s", err)
+	}
+	r.SetRestart("<device delete>")
+	deleteSpec, err := object.VirtualDeviceList{device}.ConfigSpec(types.VirtualDeviceConfigSpecOperationRemove)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] %s: Device config operations from delete: %s", r, DeviceChangeString(deleteSpec))
+	log.Printf("[DEBUG] %s: Delete completed", r)
+	return deleteSpec, nil
+}
+
+// mapBacking sets the vGPU backing of a PCI passthrough device from the
+// subresource data.
+func (r *VgpuProfileSubresource) mapBacking(device *types.VirtualPCIPassthrough) error {
+	device.Backing = &types.VirtualPCIPassthroughVmiopBackingInfo{
+		Vgpu: r.Get("profile").(string),
+	}
+	return nil
+}
+
+// findDevice locates the device for this subresource by its key. Devices
+// that have not been read since they were created are located by their
+// backing instead.
+func (r *VgpuProfileSubresource) findDevice(l object.VirtualDeviceList) (*types.VirtualPCIPassthrough, error) {
+	if key := r.Get("key").(int); key > 0 {
+		d := l.FindByKey(int32(key))
+		if d == nil {
+			return nil, fmt.Errorf("could not find device with key %d", key)
+		}
+		device, ok := d.(*types.VirtualPCIPassthrough)
+		if !ok || !isVgpuBacking(device.Backing) {
+			return nil, fmt.Errorf("device at %q is not a vGPU device", l.Name(d))
+		}
+		return device, nil
+	}
+	for _, d := range selectPciPassthroughDevices(l, isVgpuBacking) {
+		device := d.(*types.VirtualPCIPassthrough)
+		if device.Backing.(*types.VirtualPCIPassthroughVmiopBackingInfo).Vgpu == r.Get("profile").(string) {
+			return device, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find device with profile %q", r.Get("profile").(string))
+}
+
+// validateHostDevice checks that at least one of the hosts that the virtual
+// machine can run on supports the configured vGPU profile.
+func (r *VgpuProfileSubresource) validateHostDevice(hosts []*mo.HostSystem) error {
+	profile := r.Get("profile").(string)
+	for _, host := range hosts {
+		if host.Config == nil {
+			continue
+		}
+		for _, gpu := range host.Config.SharedGpuCapabilities {
+			if gpu.Vgpu == profile {
+				log.Printf("[DEBUG] %s: Found vGPU profile %q on host %s", r, profile, host.Name)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no host available to the virtual machine supports vGPU profile %q", profile)
+}
+
+// isVgpuBacking returns true if the backing is a vGPU backing.
+func isVgpuBacking(b types.BaseVirtualDeviceBackingInfo) bool {
+	_, ok := b.(*types.VirtualPCIPassthroughVmiopBackingInfo)
+	return ok
+}
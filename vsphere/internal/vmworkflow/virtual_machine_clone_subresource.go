@@ -5,8 +5,10 @@
 package vmworkflow
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -23,6 +25,10 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/virtualdevice"
 )
 
+// instantCloneGuestinfoPrefix is the prefix for the extra configuration keys
+// that are visible to the guest through VMware Tools.
+const instantCloneGuestinfoPrefix = "guestinfo."
+
 // VirtualMachineCloneSchema represents the schema for the VM clone sub-resource.
 //
 // This is a workflow for vsphere_virtual_machine that facilitates the creation
@@ -48,6 +54,36 @@ func VirtualMachineCloneSchema() map[string]*schema.Schema {
 			Optional:    true,
 			Description: "Whether or not to create a linked clone when cloning. When this option is used, the source VM must have a single snapshot associated with it.",
 		},
+		"snapshot_name": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{"clone.0.snapshot_id", "clone.0.instant_clone"},
+			Description:   "The name of the snapshot of the source virtual machine to base a linked clone on. Defaults to the current snapshot.",
+		},
+		"snapshot_id": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{"clone.0.snapshot_name", "clone.0.instant_clone"},
+			Description:   "The managed object ID of the snapshot of the source virtual machine to base a linked clone on. Defaults to the current snapshot.",
+		},
+		"instant_clone": {
+			Type:          schema.TypeBool,
+			Optional:      true,
+			ConflictsWith: []string{"clone.0.linked_clone", "clone.0.customize", "clone.0.customization_spec"},
+			Description:   "Whether or not to create an instant clone from the running state of the source virtual machine. When this option is used, the source must be a powered on or frozen virtual machine.",
+		},
+		"instant_clone_config": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "Extra configuration options to set on the instant clone.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"instant_clone_guestinfo": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "Guestinfo variables to set on the instant clone, used by the guest to assume a new identity. Keys are prefixed with \"guestinfo.\" if necessary.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
 		"timeout": {
 			Type:         schema.TypeInt,
 			Optional:     true,
@@ -106,15 +142,18 @@ func VirtualMachineCloneSchema() map[string]*schema.Schema {
 // the new VM configuration line up with the configuration in the existing
 // template, and checking to make sure that the VM has a single snapshot we can
 // use in the even that linked clones are enabled.
-func ValidateVirtualMachineClone(d *schema.ResourceDiff, c *govmomi.Client) error {
+//
+// The source VM/template is looked up on src, which differs from c when
+// cloning across vCenter Server instances.
+func ValidateVirtualMachineClone(ctx context.Context, d *schema.ResourceDiff, c, src *govmomi.Client) error {
 	tUUID := d.Get("clone.0.template_uuid").(string)
 	if d.NewValueKnown("clone.0.template_uuid") {
 		log.Printf("[DEBUG] ValidateVirtualMachineClone: Validating fitness of source VM/template %s", tUUID)
-		vm, err := virtualmachine.FromUUID(c, tUUID)
+		vm, err := virtualmachine.FromUUID(ctx, src, tUUID)
 		if err != nil {
 			return fmt.Errorf("cannot locate virtual machine or template with UUID %q: %s", tUUID, err)
 		}
-		vprops, err := virtualmachine.Properties(vm)
+		vprops, err := virtualmachine.Properties(ctx, vm)
 		if err != nil {
 			return fmt.Errorf("error fetching virtual machine or template properties: %s", err)
 		}
@@ -124,10 +163,34 @@ func ValidateVirtualMachineClone(d *schema.ResourceDiff, c *govmomi.Client) erro
 		if eGuestID != aGuestID {
 			return fmt.Errorf("invalid guest ID %q for clone. Please set it to %q", aGuestID, eGuestID)
 		}
-		// If linked clone is enabled, check to see if we have a snapshot. There need
-		// to be a single snapshot on the template for it to be eligible.
+		// If linked clone is enabled, check to see if we have a snapshot. Unless a
+		// snapshot is selected by name or ID, there need to be a single snapshot on
+		// the template for it to be eligible.
 		linked := d.Get("clone.0.linked_clone").(bool)
-		if linked {
+		snapName := d.Get("clone.0.snapshot_name").(string)
+		snapID := d.Get("clone.0.snapshot_id").(string)
+		snapKnown := d.NewValueKnown("clone.0.snapshot_name") && d.NewValueKnown("clone.0.snapshot_id")
+		if err := validateCloneSnapshotSelection(linked, snapName, snapID); err != nil {
+			return err
+		}
+		l := object.VirtualDeviceList(vprops.Config.Hardware.Device)
+		switch {
+		case linked && (snapName != "" || snapID != ""):
+			log.Printf("[DEBUG] ValidateVirtualMachineClone: Checking snapshot tree on %s for linked clone eligibility", tUUID)
+			tree, err := findCloneSnapshot(vprops, snapName, snapID)
+			if err != nil {
+				return err
+			}
+			// The disks of the clone are based on the disks as they were when the
+			// snapshot was taken, so validate against those.
+			sprops, err := virtualmachine.SnapshotProperties(ctx, vm, tree.Snapshot)
+			if err != nil {
+				return fmt.Errorf("error fetching properties of snapshot %q: %s", tree.Name, err)
+			}
+			l = object.VirtualDeviceList(sprops.Config.Hardware.Device)
+		case linked && !snapKnown:
+			log.Printf("[DEBUG] ValidateVirtualMachineClone: Snapshot for linked clone of %s is not available. Skipping snapshot validation.", tUUID)
+		case linked:
 			if vprops.Config.Template {
 				log.Printf("[DEBUG] ValidateVirtualMachineClone: Virtual machine %s is marked as a template and satisfies linked clone eligibility", tUUID)
 			} else {
@@ -137,11 +200,20 @@ func ValidateVirtualMachineClone(d *schema.ResourceDiff, c *govmomi.Client) erro
 				}
 			}
 		}
+		// Instant clones share the running state of the source, so the source
+		// must be running and the virtual hardware must be left as it is.
+		instant := d.Get("clone.0.instant_clone").(bool)
+		if instant {
+			log.Printf("[DEBUG] ValidateVirtualMachineClone: Checking %s for instant clone eligibility", tUUID)
+			if err := validateInstantCloneSource(d, vprops); err != nil {
+				return err
+			}
+		}
 		// Check to make sure the disks for this VM/template line up with the disks
 		// in the configuration. This is in the virtual device package, so pass off
-		// to that now.
-		l := object.VirtualDeviceList(vprops.Config.Hardware.Device)
-		if err := virtualdevice.DiskCloneValidateOperation(d, c, l, linked); err != nil {
+		// to that now. Instant clones share disks with the source the same way
+		// linked clones do.
+		if err := virtualdevice.DiskCloneValidateOperation(ctx, d, c, l, linked || instant); err != nil {
 			return err
 		}
 		vconfig := vprops.Config.VAppConfig
@@ -158,18 +230,18 @@ func ValidateVirtualMachineClone(d *schema.ResourceDiff, c *govmomi.Client) erro
 	// If a customization spec was defined, we need to check some items in it as well.
 	if len(d.Get("clone.0.customize").([]interface{})) > 0 {
 		if poolID, ok := d.GetOk("resource_pool_id"); ok {
-			pool, err := resourcepool.FromID(c, poolID.(string))
+			pool, err := resourcepool.FromID(ctx, c, poolID.(string))
 			if err != nil {
 				return fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
 			}
 
 			// Retrieving the vm/template data to extract the hardware version.
 			// If there's a higher hardware version specified in the spec that value is used instead.
-			vm, err := virtualmachine.FromUUID(c, tUUID)
+			vm, err := virtualmachine.FromUUID(ctx, src, tUUID)
 			if err != nil {
 				return fmt.Errorf("cannot locate virtual machine or template with UUID %q: %s", tUUID, err)
 			}
-			vprops, err := virtualmachine.Properties(vm)
+			vprops, err := virtualmachine.Properties(ctx, vm)
 			if err != nil {
 				return fmt.Errorf("error fetching virtual machine or template properties: %s", err)
 			}
@@ -180,7 +252,7 @@ func ValidateVirtualMachineClone(d *schema.ResourceDiff, c *govmomi.Client) erro
 			}
 
 			// Retrieving the guest OS family of the vm/template.
-			family, err := resourcepool.OSFamily(c, pool, d.Get("guest_id").(string), vmHardwareVersion)
+			family, err := resourcepool.OSFamily(ctx, c, pool, d.Get("guest_id").(string), vmHardwareVersion)
 			if err != nil {
 				return fmt.Errorf("cannot find OS family for guest ID %q: %s", d.Get("guest_id").(string), err)
 			}
@@ -219,20 +291,88 @@ func validateCloneSnapshots(props *mo.VirtualMachine) error { // Ensure that the
 	return nil
 }
 
+// validateCloneSnapshotSelection checks that a snapshot is only selected by
+// name or ID for linked clones.
+func validateCloneSnapshotSelection(linked bool, name, id string) error {
+	if (name != "" || id != "") && !linked {
+		return fmt.Errorf("snapshot_name and snapshot_id can only be used with linked_clone")
+	}
+	return nil
+}
+
+// findCloneSnapshot searches the snapshot tree of a VM for the snapshot to
+// base a linked clone on, either by name or by managed object ID. Names must be
+// unique in the tree.
+func findCloneSnapshot(props *mo.VirtualMachine, name, id string) (*types.VirtualMachineSnapshotTree, error) {
+	if props.Snapshot == nil {
+		return nil, fmt.Errorf("virtual machine %s must have a snapshot to be used as a linked clone", props.Config.Uuid)
+	}
+	var found []types.VirtualMachineSnapshotTree
+	var walk func([]types.VirtualMachineSnapshotTree)
+	walk = func(trees []types.VirtualMachineSnapshotTree) {
+		for _, tree := range trees {
+			if (id != "" && tree.Snapshot.Value == id) || (name != "" && tree.Name == name) {
+				found = append(found, tree)
+			}
+			walk(tree.ChildSnapshotList)
+		}
+	}
+	walk(props.Snapshot.RootSnapshotList)
+
+	desc := fmt.Sprintf("snapshot_id %q", id)
+	if name != "" {
+		desc = fmt.Sprintf("snapshot_name %q", name)
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("virtual machine %s has no snapshot matching %s", props.Config.Uuid, desc)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("virtual machine %s has %d snapshots matching %s, use snapshot_id to select one", props.Config.Uuid, len(found), desc)
+	}
+}
+
+// validateInstantCloneSource checks that a VM can be used as the source of an
+// instant clone, and that the configuration does not ask for hardware changes
+// that InstantClone_Task cannot make.
+func validateInstantCloneSource(d *schema.ResourceDiff, props *mo.VirtualMachine) error {
+	if props.Config.Template {
+		return fmt.Errorf("virtual machine %s is a template and cannot be used as the source of an instant clone", props.Config.Uuid)
+	}
+	if props.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+		return fmt.Errorf("virtual machine %s must be powered on or frozen to be used as the source of an instant clone (current state: %s)", props.Config.Uuid, props.Runtime.PowerState)
+	}
+	if _, ok := d.GetOk("datastore_cluster_id"); ok {
+		return fmt.Errorf("datastore_cluster_id cannot be used with instant_clone")
+	}
+	if d.NewValueKnown("num_cpus") && int32(d.Get("num_cpus").(int)) != props.Config.Hardware.NumCPU {
+		return fmt.Errorf("num_cpus must match the source virtual machine when using instant_clone. Please set it to %d", props.Config.Hardware.NumCPU)
+	}
+	if d.NewValueKnown("memory") && int32(d.Get("memory").(int)) != props.Config.Hardware.MemoryMB {
+		return fmt.Errorf("memory must match the source virtual machine when using instant_clone. Please set it to %d", props.Config.Hardware.MemoryMB)
+	}
+	return nil
+}
+
 // ExpandVirtualMachineCloneSpec creates a clone spec for an existing virtual machine.
 //
 // The clone spec built by this function for the clone contains the target
 // datastore, the source snapshot in the event of linked clones, and a relocate
 // spec that contains the new locations and configuration details of the new
 // virtual disks.
-func ExpandVirtualMachineCloneSpec(d *schema.ResourceData, c *govmomi.Client) (types.VirtualMachineCloneSpec, *object.VirtualMachine, error) {
+//
+// The source VM/template is looked up on src, which differs from c when
+// cloning across vCenter Server instances. All other objects are looked up on
+// c.
+func ExpandVirtualMachineCloneSpec(ctx context.Context, d *schema.ResourceData, c, src *govmomi.Client) (types.VirtualMachineCloneSpec, *object.VirtualMachine, error) {
 	var spec types.VirtualMachineCloneSpec
 	log.Printf("[DEBUG] ExpandVirtualMachineCloneSpec: Preparing clone spec for VM")
 
 	// Populate the datastore only if we have a datastore ID. The ID may not be
 	// specified in the event a datastore cluster is specified instead.
 	if dsID, ok := d.GetOk("datastore_id"); ok {
-		ds, err := datastore.FromID(c, dsID.(string))
+		ds, err := datastore.FromID(ctx, c, dsID.(string))
 		if err != nil {
 			return spec, nil, fmt.Errorf("error locating datastore for VM: %s", err)
 		}
@@ -259,24 +399,41 @@ func ExpandVirtualMachineCloneSpec(d *schema.ResourceData, c *govmomi.Client) (t
 
 	tUUID := d.Get("clone.0.template_uuid").(string)
 	log.Printf("[DEBUG] ExpandVirtualMachineCloneSpec: Cloning from UUID: %s", tUUID)
-	vm, err := virtualmachine.FromUUID(c, tUUID)
+	vm, err := virtualmachine.FromUUID(ctx, src, tUUID)
 	if err != nil {
 		return spec, nil, fmt.Errorf("cannot locate virtual machine or template with UUID %q: %s", tUUID, err)
 	}
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return spec, nil, fmt.Errorf("error fetching virtual machine or template properties: %s", err)
 	}
-	// If we are creating a linked clone, grab the current snapshot of the
-	// source, and populate the appropriate field. This should have already been
-	// validated, but just in case, validate it again here.
+	// If we are creating a linked clone, grab the selected or current snapshot
+	// of the source, and populate the appropriate field. This should have
+	// already been validated, but just in case, validate it again here.
+	l := object.VirtualDeviceList(vprops.Config.Hardware.Device)
+	snapName := d.Get("clone.0.snapshot_name").(string)
+	snapID := d.Get("clone.0.snapshot_id").(string)
 	if d.Get("clone.0.linked_clone").(bool) {
 		log.Printf("[DEBUG] ExpandVirtualMachineCloneSpec: Clone type is a linked clone")
 		log.Printf("[DEBUG] ExpandVirtualMachineCloneSpec: Fetching snapshot for VM/template UUID %s", tUUID)
 
-		// If our properties tell us that the Template flag is set, then we need to use a
-		// different option to clone the disk so that way vSphere knows the disk is shared.
-		if vprops.Config.Template {
+		if snapName != "" || snapID != "" {
+			tree, err := findCloneSnapshot(vprops, snapName, snapID)
+			if err != nil {
+				return spec, nil, err
+			}
+			sprops, err := virtualmachine.SnapshotProperties(ctx, vm, tree.Snapshot)
+			if err != nil {
+				return spec, nil, fmt.Errorf("error fetching properties of snapshot %q: %s", tree.Name, err)
+			}
+			l = object.VirtualDeviceList(sprops.Config.Hardware.Device)
+			spec.Snapshot = &tree.Snapshot
+			log.Printf("[DEBUG] ExpandVirtualMachineCloneSpec: Using snapshot %q for clone: %s", tree.Name, tree.Snapshot.Value)
+
+			spec.Location.DiskMoveType = string(types.VirtualMachineRelocateDiskMoveOptionsCreateNewChildDiskBacking)
+		} else if vprops.Config.Template {
+			// If our properties tell us that the Template flag is set, then we need to use a
+			// different option to clone the disk so that way vSphere knows the disk is shared.
 			log.Printf("[DEBUG] Virtual machine %s was marked as a template", tUUID)
 			spec.Location.DiskMoveType = string(types.VirtualMachineRelocateDiskMoveOptionsMoveAllDiskBackingsAndAllowSharing)
 		} else {
@@ -296,7 +453,7 @@ func ExpandVirtualMachineCloneSpec(d *schema.ResourceData, c *govmomi.Client) (t
 
 	// Set the target host system and resource pool.
 	poolID := d.Get("resource_pool_id").(string)
-	pool, err := resourcepool.FromID(c, poolID)
+	pool, err := resourcepool.FromID(ctx, c, poolID)
 	if err != nil {
 		return spec, nil, fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
 	}
@@ -304,12 +461,12 @@ func ExpandVirtualMachineCloneSpec(d *schema.ResourceData, c *govmomi.Client) (t
 	if v, ok := d.GetOk("host_system_id"); ok {
 		hsID := v.(string)
 		var err error
-		if hs, err = hostsystem.FromID(c, hsID); err != nil {
+		if hs, err = hostsystem.FromID(ctx, c, hsID); err != nil {
 			return spec, nil, fmt.Errorf("error locating host system at ID %q: %s", hsID, err)
 		}
 	}
 	// Validate that the host is part of the resource pool before proceeding
-	if err := resourcepool.ValidateHost(c, pool, hs); err != nil {
+	if err := resourcepool.ValidateHost(ctx, c, pool, hs); err != nil {
 		return spec, nil, err
 	}
 	poolRef := pool.Reference()
@@ -320,8 +477,7 @@ func ExpandVirtualMachineCloneSpec(d *schema.ResourceData, c *govmomi.Client) (t
 	}
 
 	// Grab the relocate spec for the disks.
-	l := object.VirtualDeviceList(vprops.Config.Hardware.Device)
-	relocators, err := virtualdevice.DiskCloneRelocateOperation(d, c, l)
+	relocators, err := virtualdevice.DiskCloneRelocateOperation(ctx, d, c, l)
 	if err != nil {
 		return spec, nil, err
 	}
@@ -329,3 +485,74 @@ func ExpandVirtualMachineCloneSpec(d *schema.ResourceData, c *govmomi.Client) (t
 	log.Printf("[DEBUG] ExpandVirtualMachineCloneSpec: Clone spec prep complete")
 	return spec, vm, nil
 }
+
+// ExpandVirtualMachineInstantCloneSpec creates an instant clone spec for an
+// existing, running virtual machine.
+//
+// The location of an instant clone is limited to the target datastore and
+// resource pool. The extra configuration options and guestinfo variables from
+// the clone block are passed to the new virtual machine as configuration
+// overrides.
+func ExpandVirtualMachineInstantCloneSpec(ctx context.Context, d *schema.ResourceData, c *govmomi.Client) (types.VirtualMachineInstantCloneSpec, *object.VirtualMachine, error) {
+	var spec types.VirtualMachineInstantCloneSpec
+	log.Printf("[DEBUG] ExpandVirtualMachineInstantCloneSpec: Preparing instant clone spec for VM")
+
+	if dsID, ok := d.GetOk("datastore_id"); ok {
+		ds, err := datastore.FromID(ctx, c, dsID.(string))
+		if err != nil {
+			return spec, nil, fmt.Errorf("error locating datastore for VM: %s", err)
+		}
+		spec.Location.Datastore = types.NewReference(ds.Reference())
+	}
+
+	tUUID := d.Get("clone.0.template_uuid").(string)
+	log.Printf("[DEBUG] ExpandVirtualMachineInstantCloneSpec: Instant cloning from UUID: %s", tUUID)
+	vm, err := virtualmachine.FromUUID(ctx, c, tUUID)
+	if err != nil {
+		return spec, nil, fmt.Errorf("cannot locate virtual machine with UUID %q: %s", tUUID, err)
+	}
+
+	poolID := d.Get("resource_pool_id").(string)
+	pool, err := resourcepool.FromID(ctx, c, poolID)
+	if err != nil {
+		return spec, nil, fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
+	}
+	poolRef := pool.Reference()
+	spec.Location.Pool = &poolRef
+
+	spec.Config = expandInstantCloneConfig(
+		d.Get("clone.0.instant_clone_config").(map[string]interface{}),
+		d.Get("clone.0.instant_clone_guestinfo").(map[string]interface{}),
+	)
+	log.Printf("[DEBUG] ExpandVirtualMachineInstantCloneSpec: Instant clone spec prep complete")
+	return spec, vm, nil
+}
+
+// expandInstantCloneConfig merges the extra configuration options and the
+// guestinfo variables for an instant clone into a list of option values,
+// sorted by key.
+func expandInstantCloneConfig(config, guestinfo map[string]interface{}) []types.BaseOptionValue {
+	opts := make(map[string]string)
+	for k, v := range config {
+		opts[k] = v.(string)
+	}
+	for k, v := range guestinfo {
+		if !strings.HasPrefix(k, instantCloneGuestinfoPrefix) {
+			k = instantCloneGuestinfoPrefix + k
+		}
+		opts[k] = v.(string)
+	}
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var ov []types.BaseOptionValue
+	for _, k := range keys {
+		ov = append(ov, &types.OptionValue{
+			Key:   k,
+			Value: opts[k],
+		})
+	}
+	return ov
+}
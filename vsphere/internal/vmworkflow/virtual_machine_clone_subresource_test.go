@@ -0,0 +1,283 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vmworkflow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestExpandInstantCloneConfig(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    map[string]interface{}
+		guestinfo map[string]interface{}
+		expected  []types.BaseOptionValue
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "config and guestinfo",
+			config: map[string]interface{}{
+				"vmx.foo": "bar",
+			},
+			guestinfo: map[string]interface{}{
+				"hostname":         "clone01",
+				"guestinfo.ipaddr": "10.0.0.10",
+			},
+			expected: []types.BaseOptionValue{
+				&types.OptionValue{Key: "guestinfo.hostname", Value: "clone01"},
+				&types.OptionValue{Key: "guestinfo.ipaddr", Value: "10.0.0.10"},
+				&types.OptionValue{Key: "vmx.foo", Value: "bar"},
+			},
+		},
+		{
+			name: "guestinfo overrides config",
+			config: map[string]interface{}{
+				"guestinfo.hostname": "source",
+			},
+			guestinfo: map[string]interface{}{
+				"hostname": "clone01",
+			},
+			expected: []types.BaseOptionValue{
+				&types.OptionValue{Key: "guestinfo.hostname", Value: "clone01"},
+			},
+		},
+		{
+			name: "sorted by key",
+			config: map[string]interface{}{
+				"c": "3",
+				"a": "1",
+				"b": "2",
+			},
+			expected: []types.BaseOptionValue{
+				&types.OptionValue{Key: "a", Value: "1"},
+				&types.OptionValue{Key: "b", Value: "2"},
+				&types.OptionValue{Key: "c", Value: "3"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Map iteration order is random, so run a few times to make sure
+			// that the order of the options is stable.
+			for i := 0; i < 10; i++ {
+				actual := expandInstantCloneConfig(tc.config, tc.guestinfo)
+				if !reflect.DeepEqual(tc.expected, actual) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateInstantCloneSource(t *testing.T) {
+	cases := []struct {
+		name     string
+		template bool
+		state    types.VirtualMachinePowerState
+	}{
+		{
+			name:  "powered off",
+			state: types.VirtualMachinePowerStatePoweredOff,
+		},
+		{
+			name:  "suspended",
+			state: types.VirtualMachinePowerStateSuspended,
+		},
+		{
+			name:     "template",
+			template: true,
+			state:    types.VirtualMachinePowerStatePoweredOff,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			props := &mo.VirtualMachine{
+				Config: &types.VirtualMachineConfigInfo{
+					Uuid:     "42010000-0000-0000-0000-000000000000",
+					Template: tc.template,
+				},
+				Runtime: types.VirtualMachineRuntimeInfo{
+					PowerState: tc.state,
+				},
+			}
+			// The source is rejected before the configuration is looked at.
+			if err := validateInstantCloneSource(nil, props); err == nil {
+				t.Fatal("expected error, got none")
+			}
+		})
+	}
+}
+
+// testCloneSnapshotTree returns a snapshot tree node with the given name,
+// managed object ID, and children.
+func testCloneSnapshotTree(name, id string, children ...types.VirtualMachineSnapshotTree) types.VirtualMachineSnapshotTree {
+	return types.VirtualMachineSnapshotTree{
+		Name:              name,
+		Snapshot:          types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: id},
+		ChildSnapshotList: children,
+	}
+}
+
+func TestFindCloneSnapshot(t *testing.T) {
+	// base (snapshot-1)
+	// ├── patched (snapshot-2)
+	// │   └── release (snapshot-3)
+	// └── dup (snapshot-4)
+	//     └── dup (snapshot-5)
+	tree := &types.VirtualMachineSnapshotInfo{
+		RootSnapshotList: []types.VirtualMachineSnapshotTree{
+			testCloneSnapshotTree("base", "snapshot-1",
+				testCloneSnapshotTree("patched", "snapshot-2",
+					testCloneSnapshotTree("release", "snapshot-3"),
+				),
+				testCloneSnapshotTree("dup", "snapshot-4",
+					testCloneSnapshotTree("dup", "snapshot-5"),
+				),
+			),
+		},
+	}
+	cases := []struct {
+		name      string
+		snapshots *types.VirtualMachineSnapshotInfo
+		snapName  string
+		snapID    string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "root by name",
+			snapName: "base",
+			expected: "snapshot-1",
+		},
+		{
+			name:     "nested by name",
+			snapName: "release",
+			expected: "snapshot-3",
+		},
+		{
+			name:     "nested by ID",
+			snapID:   "snapshot-3",
+			expected: "snapshot-3",
+		},
+		{
+			name:     "duplicate name by ID",
+			snapID:   "snapshot-5",
+			expected: "snapshot-5",
+		},
+		{
+			name:      "duplicate name",
+			snapName:  "dup",
+			expectErr: true,
+		},
+		{
+			name:      "missing name",
+			snapName:  "nonexistent",
+			expectErr: true,
+		},
+		{
+			name:      "missing ID",
+			snapID:    "snapshot-99",
+			expectErr: true,
+		},
+		{
+			name:      "no snapshots",
+			snapName:  "base",
+			snapshots: &types.VirtualMachineSnapshotInfo{},
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			props := &mo.VirtualMachine{
+				Config: &types.VirtualMachineConfigInfo{
+					Uuid: "42010000-0000-0000-0000-000000000000",
+				},
+				Snapshot: tree,
+			}
+			if tc.snapshots != nil {
+				props.Snapshot = tc.snapshots
+			}
+			actual, err := findCloneSnapshot(props, tc.snapName, tc.snapID)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", actual.Snapshot.Value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual.Snapshot.Value != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, actual.Snapshot.Value)
+			}
+		})
+	}
+}
+
+func TestFindCloneSnapshotWithoutSnapshot(t *testing.T) {
+	props := &mo.VirtualMachine{
+		Config: &types.VirtualMachineConfigInfo{
+			Uuid: "42010000-0000-0000-0000-000000000000",
+		},
+	}
+	if _, err := findCloneSnapshot(props, "base", ""); err == nil {
+		t.Fatal("expected error, got none")
+	}
+}
+
+func TestValidateCloneSnapshotSelection(t *testing.T) {
+	cases := []struct {
+		name      string
+		linked    bool
+		snapName  string
+		snapID    string
+		expectErr bool
+	}{
+		{
+			name: "full clone",
+		},
+		{
+			name:   "linked clone of current snapshot",
+			linked: true,
+		},
+		{
+			name:     "snapshot_name with linked_clone",
+			linked:   true,
+			snapName: "base",
+		},
+		{
+			name:   "snapshot_id with linked_clone",
+			linked: true,
+			snapID: "snapshot-1",
+		},
+		{
+			name:      "snapshot_name without linked_clone",
+			snapName:  "base",
+			expectErr: true,
+		},
+		{
+			name:      "snapshot_id without linked_clone",
+			snapID:    "snapshot-1",
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCloneSnapshotSelection(tc.linked, tc.snapName, tc.snapID)
+			if tc.expectErr && err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !tc.expectErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
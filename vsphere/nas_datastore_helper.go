@@ -70,7 +70,7 @@ func (p *nasDatastoreMountProcessor) diff(a, b []string) []string {
 // processMountOperations processes all pending mount operations by diffing old
 // and new and adding any hosts that were not found in old. The datastore is
 // returned, along with any error.
-func (p *nasDatastoreMountProcessor) processMountOperations() (*object.Datastore, error) {
+func (p *nasDatastoreMountProcessor) processMountOperations(ctx context.Context) (*object.Datastore, error) {
 	hosts := p.diffNewOld()
 	if len(hosts) < 1 {
 		// Nothing to do
@@ -83,23 +83,23 @@ func (p *nasDatastoreMountProcessor) processMountOperations() (*object.Datastore
 		}
 	}
 	for _, hsID := range hosts {
-		dss, err := hostDatastoreSystemFromHostSystemID(p.client, hsID)
+		dss, err := hostDatastoreSystemFromHostSystemID(ctx, p.client, hsID)
 		if err != nil {
-			return p.ds, fmt.Errorf("host %q: %s", hostsystem.NameOrID(p.client, hsID), err)
+			return p.ds, fmt.Errorf("host %q: %s", hostsystem.NameOrID(ctx, p.client, hsID), err)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 
 		ds, err := dss.CreateNasDatastore(ctx, *p.volSpec)
 		if err != nil {
 			cancel()
-			return p.ds, fmt.Errorf("host %q: %s", hostsystem.NameOrID(p.client, hsID), err)
+			return p.ds, fmt.Errorf("host %q: %s", hostsystem.NameOrID(ctx, p.client, hsID), err)
 		}
 
 		cancel()
 
 		if err := p.validateDatastore(ds); err != nil {
-			return p.ds, fmt.Errorf("datastore validation error on host %q: %s", hostsystem.NameOrID(p.client, hsID), err)
+			return p.ds, fmt.Errorf("datastore validation error on host %q: %s", hostsystem.NameOrID(ctx, p.client, hsID), err)
 		}
 	}
 
@@ -109,19 +109,19 @@ func (p *nasDatastoreMountProcessor) processMountOperations() (*object.Datastore
 // processUnmountOperations processes all pending unmount operations by diffing old
 // and new and removing any hosts that were not found in new. This operation
 // only proceeds if the datastore field in the processor is populated.
-func (p *nasDatastoreMountProcessor) processUnmountOperations() error {
+func (p *nasDatastoreMountProcessor) processUnmountOperations(ctx context.Context) error {
 	hosts := p.diffOldNew()
 	if len(hosts) < 1 || p.ds == nil {
 		// Nothing to do
 		return nil
 	}
 	for _, hsID := range hosts {
-		dss, err := hostDatastoreSystemFromHostSystemID(p.client, hsID)
+		dss, err := hostDatastoreSystemFromHostSystemID(ctx, p.client, hsID)
 		if err != nil {
-			return fmt.Errorf("host %q: %s", hostsystem.NameOrID(p.client, hsID), err)
+			return fmt.Errorf("host %q: %s", hostsystem.NameOrID(ctx, p.client, hsID), err)
 		}
-		if err := removeDatastore(dss, p.ds); err != nil {
-			return fmt.Errorf("host %q: %s", hostsystem.NameOrID(p.client, hsID), err)
+		if err := removeDatastore(ctx, dss, p.ds); err != nil {
+			return fmt.Errorf("host %q: %s", hostsystem.NameOrID(ctx, p.client, hsID), err)
 		}
 	}
 	return nil
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/cassette"
 )
 
 // defaultAPITimeout is a default timeout value that is passed to functions
@@ -22,16 +24,47 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"user": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_USER", nil),
-				Description: "The user name for vSphere API operations.",
+				Description: "The user name for vSphere API operations. Required unless SSO token authentication is used.",
 			},
 
 			"password": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_PASSWORD", nil),
-				Description: "The user password for vSphere API operations.",
+				Description: "The user password for vSphere API operations. Required unless SSO token authentication is used.",
+			},
+
+			"sso_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_SSO_TOKEN", ""),
+				Description: "An externally issued SAML token, as XML or base64 encoded, to authenticate to vSphere with instead of a user name and password.",
+			},
+
+			"sso_oauth_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_SSO_OAUTH_TOKEN", ""),
+				Description: "An OAuth 2.0 access token from an identity provider federated with vCenter Server, exchanged for a SAML token to authenticate to vSphere with. Requires vCenter Server 8.0 or later.",
+			},
+
+			"sso_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_SSO_CERTIFICATE", ""),
+				Description: "The PEM encoded certificate of a solution user, to request a holder-of-key token from the Security Token Service of vCenter Server with.",
+			},
+
+			"sso_private_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_SSO_PRIVATE_KEY", ""),
+				Description: "The PEM encoded private key of sso_certificate.",
 			},
 
 			"vsphere_server": {
@@ -46,6 +79,43 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_ALLOW_UNVERIFIED_SSL", false),
 				Description: "If set, VMware vSphere client will permit unverifiable SSL certificates.",
 			},
+			"ca_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CA_FILE", ""),
+				Description: "The path to a PEM encoded certificate authority bundle to verify server certificates with, instead of the system certificate authorities. Multiple paths are separated by the OS path list separator.",
+			},
+			"ca_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CA_PEM", ""),
+				Description: "A PEM encoded certificate authority bundle to verify server certificates with, instead of the system certificate authorities.",
+			},
+			"vcenter_thumbprint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_VCENTER_THUMBPRINT", ""),
+				Description: "The SHA-1 or SHA-256 thumbprint of the certificate of vsphere_server. The certificate must match it, and is trusted even if it is not signed by a trusted certificate authority.",
+			},
+			"host_thumbprints": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "The SHA-1 or SHA-256 thumbprints of the certificates of ESXi hosts, keyed by host name or address, for the connections made directly to hosts.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_PROXY_URL", ""),
+				Description: "The URL of an HTTP or SOCKS5 proxy to connect to vSphere and the ESXi hosts through.",
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_NO_PROXY", ""),
+				Description: "A comma separated list of host names, domains, addresses, and CIDR ranges to connect to directly instead of through proxy_url.",
+			},
 			"vcenter_server": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -70,6 +140,19 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CLIENT_DEBUG_PATH", ""),
 				Description: "govmomi debug path for debug",
 			},
+			"client_cassette_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CLIENT_CASSETTE_PATH", ""),
+				Description: "Directory of the API cassette to record vSphere API traffic to, or to replay it from.",
+			},
+			"client_cassette_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_CLIENT_CASSETTE_MODE", ""),
+				Description:  "The API cassette mode. Can be one of record or replay.",
+				ValidateFunc: validation.StringInSlice(append([]string{""}, cassette.Modes...), false),
+			},
 			"persist_session": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -88,6 +171,21 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_REST_SESSION_PATH", filepath.Join(os.Getenv("HOME"), ".govmomi", "rest_sessions")),
 				Description: "The directory to save vSphere REST API sessions to",
 			},
+			"session_encryption_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("VSPHERE_SESSION_ENCRYPTION_KEY", ""),
+				Description:   "A base64 encoded 256-bit key to encrypt persisted sessions with.",
+				ConflictsWith: []string{"session_encryption_keyring"},
+			},
+			"session_encryption_keyring": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("VSPHERE_SESSION_ENCRYPTION_KEYRING", false),
+				Description:   "Encrypt persisted sessions with a key stored in the OS keyring, which is created on first use.",
+				ConflictsWith: []string{"session_encryption_key"},
+			},
 			"vim_keep_alive": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -100,6 +198,27 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_API_TIMEOUT", 5),
 				Description: "API timeout in minutes (Default: 5)",
 			},
+			"api_max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_API_MAX_CONCURRENT_REQUESTS", 0),
+				Description:  "The maximum number of concurrent vSphere API requests. Unlimited when 0 (Default: 0)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"api_requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_API_REQUESTS_PER_SECOND", 0.0),
+				Description:  "The maximum rate of vSphere API requests per second. Unlimited when 0 (Default: 0)",
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"api_max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_API_MAX_RETRIES", 3),
+				Description:  "The number of times a vSphere API request is retried after a transient fault of the request, such as TaskInProgress, ResourceInUse, or HTTP 503. Faults of tasks started by a request are not retried (Default: 3)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -125,12 +244,15 @@ func Provider() *schema.Provider {
 			"vsphere_drs_vm_override":                          resourceVSphereDRSVMOverride(),
 			"vsphere_entity_permissions":                       resourceVsphereEntityPermissions(),
 			"vsphere_file":                                     resourceVSphereFile(),
+			"vsphere_first_class_disk":                         resourceVSphereFirstClassDisk(),
+			"vsphere_first_class_disk_snapshot":                resourceVSphereFirstClassDiskSnapshot(),
 			"vsphere_folder":                                   resourceVSphereFolder(),
 			"vsphere_guest_os_customization":                   resourceVSphereGuestOsCustomization(),
 			"vsphere_ha_vm_override":                           resourceVSphereHAVMOverride(),
 			"vsphere_host":                                     resourceVsphereHost(),
 			"vsphere_host_port_group":                          resourceVSphereHostPortGroup(),
 			"vsphere_host_virtual_switch":                      resourceVSphereHostVirtualSwitch(),
+			"vsphere_key_provider":                             resourceVSphereKeyProvider(),
 			"vsphere_license":                                  resourceVSphereLicense(),
 			"vsphere_namespace":                                resourceVSphereNamespace(),
 			"vsphere_nas_datastore":                            resourceVSphereNasDatastore(),
@@ -149,6 +271,8 @@ func Provider() *schema.Provider {
 			"vsphere_virtual_disk":                             resourceVSphereVirtualDisk(),
 			"vsphere_virtual_machine":                          resourceVSphereVirtualMachine(),
 			"vsphere_virtual_machine_class":                    resourceVsphereVMClass(),
+			"vsphere_virtual_machine_export":                   resourceVSphereVirtualMachineExport(),
+			"vsphere_virtual_machine_guest_operation":          resourceVSphereVirtualMachineGuestOperation(),
 			"vsphere_virtual_machine_snapshot":                 resourceVSphereVirtualMachineSnapshot(),
 			"vsphere_vm_storage_policy":                        resourceVMStoragePolicy(),
 			"vsphere_vmfs_datastore":                           resourceVSphereVmfsDatastore(),
@@ -177,6 +301,7 @@ func Provider() *schema.Provider {
 			"vsphere_host_pci_device":            dataSourceVSphereHostPciDevice(),
 			"vsphere_host_thumbprint":            dataSourceVSphereHostThumbprint(),
 			"vsphere_host_vgpu_profile":          dataSourceVSphereHostVGpuProfile(),
+			"vsphere_key_provider":               dataSourceVSphereKeyProvider(),
 			"vsphere_license":                    dataSourceVSphereLicense(),
 			"vsphere_namespace":                  dataSourceVSphereNamespace(),
 			"vsphere_network":                    dataSourceVSphereNetwork(),
@@ -190,6 +315,9 @@ func Provider() *schema.Provider {
 			"vsphere_tag_category":               dataSourceVSphereTagCategory(),
 			"vsphere_vapp_container":             dataSourceVSphereVAppContainer(),
 			"vsphere_virtual_machine":            dataSourceVSphereVirtualMachine(),
+			"vsphere_virtual_machine_guest":      dataSourceVSphereVirtualMachineGuest(),
+			"vsphere_virtual_machine_snapshots":  dataSourceVSphereVirtualMachineSnapshots(),
+			"vsphere_virtual_machines":           dataSourceVSphereVirtualMachines(),
 			"vsphere_vmfs_disks":                 dataSourceVSphereVmfsDisks(),
 			"vsphere_zone":                       dataSourceVSphereZone(),
 		},
@@ -6,10 +6,12 @@ package vsphere
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/cassette"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -29,6 +31,8 @@ func TestProvider(t *testing.T) {
 }
 
 func testAccPreCheck(t *testing.T) {
+	testAccCassette(t)
+
 	if v := os.Getenv("VSPHERE_USER"); v == "" {
 		t.Fatal("VSPHERE_USER must be set for acceptance tests")
 	}
@@ -48,6 +52,30 @@ func testAccSkipUnstable(t *testing.T) {
 	}
 }
 
+// testAccCassette points the provider at the API cassette for the running
+// test when VSPHERE_CLIENT_CASSETTE_MODE is set. Cassettes are kept in
+// testdata/cassettes, one directory per test. In replay mode the connection
+// variables are not needed, so placeholders are used for any that are unset.
+func testAccCassette(t *testing.T) {
+	mode := os.Getenv("VSPHERE_CLIENT_CASSETTE_MODE")
+	if mode == "" {
+		return
+	}
+	dir := filepath.Join("testdata", "cassettes", t.Name())
+	t.Setenv("VSPHERE_CLIENT_CASSETTE_PATH", dir)
+	if mode != string(cassette.ModeReplay) {
+		return
+	}
+	if err := cassette.ResetReplay(dir); err != nil {
+		t.Fatalf("error resetting cassette %q: %s", dir, err)
+	}
+	for _, name := range []string{"VSPHERE_USER", "VSPHERE_PASSWORD", "VSPHERE_SERVER"} {
+		if os.Getenv(name) == "" {
+			t.Setenv(name, "replay")
+		}
+	}
+}
+
 func testAccCheckEnvVariables(t *testing.T, variableNames []string) {
 	for _, name := range variableNames {
 		if v := os.Getenv(name); v == "" {
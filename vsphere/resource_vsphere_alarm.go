@@ -6,9 +6,10 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"reflect"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/alarm"
@@ -54,10 +55,16 @@ var allowedEventExpressionComparisonOperators = []string{
 
 func resourceVSphereAlarm() *schema.Resource {
 	return &schema.Resource{
-		Create:        resourceVSphereAlarmCreate,
-		Read:          resourceVSphereAlarmRead,
-		Update:        resourceVSphereAlarmUpdate,
-		Delete:        resourceVSphereAlarmDelete,
+		CreateContext: resourceVSphereAlarmCreate,
+		ReadContext:   resourceVSphereAlarmRead,
+		UpdateContext: resourceVSphereAlarmUpdate,
+		DeleteContext: resourceVSphereAlarmDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -361,45 +368,45 @@ func resourceVSphereAlarm() *schema.Resource {
 	}
 }
 
-func resourceVSphereAlarmCreate(d *schema.ResourceData, meta any) error {
+func resourceVSphereAlarmCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	m, err := alarm.GetManager(client.Client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Getting the object the alarm will be created on
-	entity, err := helper.FindEntity(client, d.Get("entity_type").(string), d.Get("entity_id").(string))
+	entity, err := helper.FindEntity(ctx, client, d.Get("entity_type").(string), d.Get("entity_id").(string))
 	if err != nil {
-		return fmt.Errorf("alarm entity error: %s", err)
+		return diag.Errorf("alarm entity error: %s", err)
 	}
 
 	alarmSpec, err := helper.GetAlarmSpec(d)
 	if err != nil {
-		return fmt.Errorf("failed to generate alarm spec: %s", err)
+		return diag.Errorf("failed to generate alarm spec: %s", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	ref, err := m.CreateAlarm(ctx, entity, alarmSpec)
 	if err != nil {
-		return fmt.Errorf("failed to create new alarm: %s", err)
+		return diag.Errorf("failed to create new alarm: %s", err)
 	}
 	d.SetId(ref.Reference().Value)
-	return resourceVSphereAlarmRead(d, meta)
+	return resourceVSphereAlarmRead(ctx, d, meta)
 }
 
-func resourceVSphereAlarmRead(d *schema.ResourceData, meta any) error {
+func resourceVSphereAlarmRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
-	entity, err := helper.FindEntity(client, d.Get("entity_type").(string), d.Get("entity_id").(string))
+	entity, err := helper.FindEntity(ctx, client, d.Get("entity_type").(string), d.Get("entity_id").(string))
 	if err != nil {
-		return fmt.Errorf("alarm entity error: %s", err)
+		return diag.Errorf("alarm entity error: %s", err)
 	}
 
-	al, err := helper.FromID(client, d.Id(), entity)
+	al, err := helper.FromID(ctx, client, d.Id(), entity)
 	if err != nil {
-		return fmt.Errorf("cannot locate alarm: %s", err)
+		return diag.Errorf("cannot locate alarm: %s", err)
 	}
 
 	_ = d.Set("name", al.Info.Name)
@@ -414,13 +421,13 @@ func resourceVSphereAlarmRead(d *schema.ResourceData, meta any) error {
 		_ = d.Set("expression_operator", "or")
 		expressions, err = helper.GetExpressions(exp.Expression)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	case *types.AndAlarmExpression:
 		_ = d.Set("expression_operator", "and")
 		expressions, err = helper.GetExpressions(exp.Expression)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if len(expressions.EventExpressions) > 0 {
@@ -442,10 +449,10 @@ func resourceVSphereAlarmRead(d *schema.ResourceData, meta any) error {
 		case *types.AlarmAction:
 			actions, err = helper.GetAlarmActions([]types.BaseAlarmAction{alarmAction})
 		default:
-			return fmt.Errorf("unmanaged alarm action type: %s", reflect.TypeOf(alarmAction))
+			return diag.Errorf("unmanaged alarm action type: %s", reflect.TypeOf(alarmAction))
 		}
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if len(actions.EmailAction) > 0 {
 			_ = d.Set("email_action", actions.EmailAction)
@@ -461,37 +468,37 @@ func resourceVSphereAlarmRead(d *schema.ResourceData, meta any) error {
 	return nil
 }
 
-func resourceVSphereAlarmUpdate(d *schema.ResourceData, meta any) error {
+func resourceVSphereAlarmUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
-	entity, err := helper.FindEntity(client, d.Get("entity_type").(string), d.Get("entity_id").(string))
+	entity, err := helper.FindEntity(ctx, client, d.Get("entity_type").(string), d.Get("entity_id").(string))
 	if err != nil {
-		return fmt.Errorf("alarm entity error: %s", err)
+		return diag.Errorf("alarm entity error: %s", err)
 	}
 
-	al, err := helper.FromID(client, d.Id(), entity)
+	al, err := helper.FromID(ctx, client, d.Id(), entity)
 	if err != nil {
-		return fmt.Errorf("cannot locate alarm: %s", err)
+		return diag.Errorf("cannot locate alarm: %s", err)
 	}
 
 	alarmSpec, err := helper.GetAlarmSpec(d)
 	if err != nil {
-		return fmt.Errorf("failed to generate alarm spec: %s", err)
+		return diag.Errorf("failed to generate alarm spec: %s", err)
 	}
 
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
 	_, err = methods.ReconfigureAlarm(tctx, client.RoundTripper, &types.ReconfigureAlarm{
 		This: al.Self,
 		Spec: alarmSpec,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to reconfigure alarm: %s", err)
+		return diag.Errorf("failed to reconfigure alarm: %s", err)
 	}
-	return resourceVSphereAlarmRead(d, meta)
+	return resourceVSphereAlarmRead(ctx, d, meta)
 }
 
-func resourceVSphereAlarmDelete(d *schema.ResourceData, meta any) error {
+func resourceVSphereAlarmDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	var ref types.ManagedObjectReference
@@ -500,13 +507,13 @@ func resourceVSphereAlarmDelete(d *schema.ResourceData, meta any) error {
 		ref.Type = "Alarm"
 		ref.Value = d.Id()
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	_, err := methods.RemoveAlarm(ctx, client.Client, &types.RemoveAlarm{
 		This: ref,
 	})
 	if err != nil {
-		return fmt.Errorf("cannot delete alarm: %s", err)
+		return diag.Errorf("cannot delete alarm: %s", err)
 	}
 	d.SetId("")
 	return nil
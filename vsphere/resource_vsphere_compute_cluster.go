@@ -10,7 +10,9 @@ import (
 	"log"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -134,12 +136,18 @@ var clusterInfraUpdateHaConfigInfoRemediationTypeAllowedValues = []string{
 
 func resourceVSphereComputeCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterCreate,
-		Read:   resourceVSphereComputeClusterRead,
-		Update: resourceVSphereComputeClusterUpdate,
-		Delete: resourceVSphereComputeClusterDelete,
+		CreateContext: resourceVSphereComputeClusterCreate,
+		ReadContext:   resourceVSphereComputeClusterRead,
+		UpdateContext: resourceVSphereComputeClusterUpdate,
+		DeleteContext: resourceVSphereComputeClusterDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterImport,
+			StateContext: resourceVSphereComputeClusterImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -707,154 +715,154 @@ func resourceVSphereComputeCluster() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterIDString(d))
 
 	// We create the cluster here. This function creates a cluster with no
 	// configuration, as we want to add the hosts before applying the full
 	// configuration.
-	cluster, err := resourceVSphereComputeClusterApplyCreate(d, meta)
+	cluster, err := resourceVSphereComputeClusterApplyCreate(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// The cluster can be tagged here now.
-	if err := resourceVSphereComputeClusterApplyTags(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyTags(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
-	if err := resourceVSphereComputeClusterApplyCustomAttributes(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyCustomAttributes(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Move the hosts in now.
-	if err := resourceVSphereComputeClusterProcessHostUpdate(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterProcessHostUpdate(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Now that all the hosts that will be in the cluster have been added, apply
 	// the cluster configuration.
-	if err := resourceVSphereComputeClusterApplyClusterConfiguration(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyClusterConfiguration(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Apply vLCM settings
-	if err := resourceVSphereComputeClusterApplyHostImage(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyHostImage(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Apply EVC mode
-	if err := resourceVSphereComputeClusterApplyEvc(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyEvc(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// All done!
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterIDString(d))
-	return resourceVSphereComputeClusterRead(d, meta)
+	return resourceVSphereComputeClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterIDString(d))
 
-	cluster, err := resourceVSphereComputeClusterGetCluster(d, meta)
+	cluster, err := resourceVSphereComputeClusterGetCluster(ctx, d, meta)
 	if err != nil {
 		if viapi.IsManagedObjectNotFoundError(err) {
 			log.Printf("[DEBUG] %s: Resource has been deleted", resourceVSphereComputeClusterIDString(d))
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterSaveDatacenter(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterSaveDatacenter(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	if err := resourceVSphereComputeClusterSaveNameAndPath(d, cluster); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterFlattenData(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterFlattenData(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterReadTags(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterReadTags(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterReadCustomAttributes(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterReadCustomAttributes(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterIDString(d))
 
-	cluster, err := resourceVSphereComputeClusterGetCluster(d, meta)
+	cluster, err := resourceVSphereComputeClusterGetCluster(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	cluster, err = resourceVSphereComputeClusterApplyNameChange(d, meta, cluster)
+	cluster, err = resourceVSphereComputeClusterApplyNameChange(ctx, d, meta, cluster)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	cluster, err = resourceVSphereComputeClusterApplyFolderChange(d, meta, cluster)
+	cluster, err = resourceVSphereComputeClusterApplyFolderChange(ctx, d, meta, cluster)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterProcessHostUpdate(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterProcessHostUpdate(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterApplyClusterConfiguration(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyClusterConfiguration(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterApplyTags(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyTags(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterApplyCustomAttributes(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyCustomAttributes(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterApplyHostImage(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyHostImage(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterApplyEvc(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyEvc(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterIDString(d))
-	return resourceVSphereComputeClusterRead(d, meta)
+	return resourceVSphereComputeClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterIDString(d))
-	cluster, err := resourceVSphereComputeClusterGetCluster(d, meta)
+	cluster, err := resourceVSphereComputeClusterGetCluster(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	client, err := resourceVSphereComputeClusterClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	version := viapi.ParseVersionFromClient(client)
 
-	spec, err := expandClusterConfigSpecEx(d, version, cluster)
+	spec, err := expandClusterConfigSpecEx(ctx, d, version, cluster)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveFaultDomain(d, cluster, spec); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveFaultDomain(ctx, d, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	if *spec.DasConfig.Enabled && *spec.DasConfig.AdmissionControlEnabled {
@@ -862,35 +870,35 @@ func resourceVSphereComputeClusterDelete(d *schema.ResourceData, meta interface{
 			_ = v
 			log.Printf("[DEBUG] if Admission Control Policy set to Failover Host than turn HA OFF before removing hosts")
 			spec.DasConfig.Enabled = structure.BoolPtr(false)
-			if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-				return err
+			if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(ctx, d, meta, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterValidateEmptyCluster(d, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterValidateEmptyCluster(ctx, d, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereComputeClusterApplyDelete(d, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyDelete(ctx, d, cluster); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	p := d.Id()
-	cluster, err := resourceVSphereComputeClusterGetClusterFromPath(meta, p, "")
+	cluster, err := resourceVSphereComputeClusterGetClusterFromPath(ctx, meta, p, "")
 	if err != nil {
 		return nil, fmt.Errorf("error loading cluster: %s", err)
 	}
@@ -900,7 +908,7 @@ func resourceVSphereComputeClusterImport(d *schema.ResourceData, meta interface{
 		return nil, err
 	}
 
-	err = resourceVSphereComputeClusterRead(d, meta)
+	err = provider.DiagnosticsError(resourceVSphereComputeClusterRead(ctx, d, meta))
 	if err != nil {
 		return nil, err
 	}
@@ -926,21 +934,21 @@ func resourceVSphereComputeClusterImportSetDefaults(d *schema.ResourceData) erro
 
 // resourceVSphereComputeClusterApplyCreate processes the creation part of
 // resourceVSphereComputeClusterCreate.
-func resourceVSphereComputeClusterApplyCreate(d *schema.ResourceData, meta interface{}) (*object.ClusterComputeResource, error) {
+func resourceVSphereComputeClusterApplyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.ClusterComputeResource, error) {
 	log.Printf("[DEBUG] %s: Processing compute cluster creation", resourceVSphereComputeClusterIDString(d))
 	client, err := resourceVSphereComputeClusterClient(meta)
 	if err != nil {
 		return nil, err
 	}
 
-	dc, err := datacenterFromID(client, d.Get("datacenter_id").(string))
+	dc, err := datacenterFromID(ctx, client, d.Get("datacenter_id").(string))
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate datacenter: %s", err)
 	}
 
 	// Find the folder based off the path to the datacenter. This is where we
 	// create the datastore cluster.
-	f, err := folder.FromPath(client, d.Get("folder").(string), folder.VSphereFolderTypeHost, dc)
+	f, err := folder.FromPath(ctx, client, d.Get("folder").(string), folder.VSphereFolderTypeHost, dc)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate folder: %s", err)
 	}
@@ -948,7 +956,7 @@ func resourceVSphereComputeClusterApplyCreate(d *schema.ResourceData, meta inter
 	// Create the cluster. We use an empty config spec so that we can move the
 	// necessary hosts into the cluster *before* we send the full configuration,
 	// ensuring that any host-dependent configuration does not break.
-	cluster, err := clustercomputeresource.Create(f, d.Get("name").(string), types.ClusterConfigSpecEx{})
+	cluster, err := clustercomputeresource.Create(ctx, f, d.Get("name").(string), types.ClusterConfigSpecEx{})
 	if err != nil {
 		return nil, fmt.Errorf("error creating cluster: %s", err)
 	}
@@ -993,6 +1001,7 @@ func resourceVSphereComputeClusterApplyCreate(d *schema.ResourceData, meta inter
 // the cluster. The host will be taken out of maintenance mode after being
 // removed.
 func resourceVSphereComputeClusterProcessHostUpdate(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1006,6 +1015,7 @@ func resourceVSphereComputeClusterProcessHostUpdate(
 	o, n := d.GetChange("host_system_ids")
 
 	newHosts, err := resourceVSphereComputeClusterGetHostSystemObjects(
+		ctx,
 		client,
 		structure.SliceInterfacesToStrings(n.(*schema.Set).Difference(o.(*schema.Set)).List()),
 	)
@@ -1014,6 +1024,7 @@ func resourceVSphereComputeClusterProcessHostUpdate(
 	}
 
 	oldHosts, err := resourceVSphereComputeClusterGetHostSystemObjects(
+		ctx,
 		client,
 		structure.SliceInterfacesToStrings(o.(*schema.Set).Difference(n.(*schema.Set)).List()),
 	)
@@ -1023,17 +1034,17 @@ func resourceVSphereComputeClusterProcessHostUpdate(
 
 	// Add new hosts first
 	if len(newHosts) > 0 {
-		if err := clustercomputeresource.MoveHostsInto(client, cluster, newHosts); err != nil {
+		if err := clustercomputeresource.MoveHostsInto(ctx, client, cluster, newHosts); err != nil {
 			return fmt.Errorf("error moving new hosts into cluster: %s", err)
 		}
 
 		for _, hs := range newHosts {
-			hsProps, err := hostsystem.Properties(hs)
+			hsProps, err := hostsystem.Properties(ctx, hs)
 			if err != nil {
 				return fmt.Errorf("while fetching properties for host %q: %s", hs.Reference().Value, err)
 			}
 			if hsProps.Runtime.InMaintenanceMode {
-				err := hostsystem.ExitMaintenanceMode(hs, provider.DefaultAPITimeout)
+				err := hostsystem.ExitMaintenanceMode(ctx, hs, provider.DefaultAPITimeout)
 				if err != nil {
 					return fmt.Errorf("while getting host %q out of maintenance mode: %s", hs.Reference().Value, err)
 				}
@@ -1042,18 +1053,18 @@ func resourceVSphereComputeClusterProcessHostUpdate(
 	}
 
 	// Remove hosts next
-	if err := clustercomputeresource.MoveHostsOutOf(cluster, oldHosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
+	if err := clustercomputeresource.MoveHostsOutOf(ctx, cluster, oldHosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
 		return fmt.Errorf("error moving old hosts out of cluster: %s", err)
 	}
 
 	return nil
 }
 
-func resourceVSphereComputeClusterGetHostSystemObjects(client *govmomi.Client, hsIDs []string) ([]*object.HostSystem, error) {
+func resourceVSphereComputeClusterGetHostSystemObjects(ctx context.Context, client *govmomi.Client, hsIDs []string) ([]*object.HostSystem, error) {
 	var hosts []*object.HostSystem
 
 	for _, hsID := range hsIDs {
-		hs, err := hostsystem.FromID(client, hsID)
+		hs, err := hostsystem.FromID(ctx, client, hsID)
 		if err != nil {
 			return nil, fmt.Errorf("error locating host system ID %q: %s", hsID, err)
 		}
@@ -1064,6 +1075,7 @@ func resourceVSphereComputeClusterGetHostSystemObjects(client *govmomi.Client, h
 }
 
 func resourceVSphereComputeClusterApplyClusterConfiguration(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1077,7 +1089,7 @@ func resourceVSphereComputeClusterApplyClusterConfiguration(
 	log.Printf("[DEBUG] %s: Applying cluster configuration", resourceVSphereComputeClusterIDString(d))
 
 	// handle VSAN first to avoid race condition
-	if err := resourceVSphereComputeClusterApplyVsanConfig(d, meta, cluster); err != nil {
+	if err := resourceVSphereComputeClusterApplyVsanConfig(ctx, d, meta, cluster); err != nil {
 		return err
 	}
 
@@ -1091,19 +1103,19 @@ func resourceVSphereComputeClusterApplyClusterConfiguration(
 	version := viapi.ParseVersionFromClient(client)
 
 	// Expand the cluster configuration.
-	spec, err := expandClusterConfigSpecEx(d, version, cluster)
+	spec, err := expandClusterConfigSpecEx(ctx, d, version, cluster)
 	if err != nil {
 		return err
 	}
 
 	// Note that the reconfigure for a cluster is the same as a standalone host,
 	// hence we send this to the computeresource helper's Reconfigure function.
-	return clustercomputeresource.Reconfigure(cluster, spec)
+	return clustercomputeresource.Reconfigure(ctx, cluster, spec)
 }
 
 // resourceVSphereComputeClusterApplyTags processes the tags step for both
 // create and update for vsphere_compute_cluster.
-func resourceVSphereComputeClusterApplyTags(d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
+func resourceVSphereComputeClusterApplyTags(ctx context.Context, d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
 		return err
@@ -1116,15 +1128,15 @@ func resourceVSphereComputeClusterApplyTags(d *schema.ResourceData, meta interfa
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereComputeClusterIDString(d))
-	return processTagDiff(tagsClient, d, cluster)
+	return processTagDiff(ctx, tagsClient, d, cluster)
 }
 
 // resourceVSphereComputeClusterReadTags reads the tags for
 // vsphere_compute_cluster.
-func resourceVSphereComputeClusterReadTags(d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
+func resourceVSphereComputeClusterReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereComputeClusterIDString(d))
-		if err := readTagsForResource(tagsClient, cluster, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, cluster, d); err != nil {
 			return err
 		}
 	} else {
@@ -1136,6 +1148,7 @@ func resourceVSphereComputeClusterReadTags(d *schema.ResourceData, meta interfac
 // resourceVSphereComputeClusterApplyCustomAttributes processes the custom
 // attributes step for both create and update for vsphere_compute_cluster.
 func resourceVSphereComputeClusterApplyCustomAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1153,10 +1166,11 @@ func resourceVSphereComputeClusterApplyCustomAttributes(
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending custom attributes", resourceVSphereComputeClusterIDString(d))
-	return attrsProcessor.ProcessDiff(cluster)
+	return attrsProcessor.ProcessDiff(ctx, cluster)
 }
 
 func resourceVSphereComputeClusterApplyHostImage(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	_ *object.ClusterComputeResource,
@@ -1175,7 +1189,7 @@ func resourceVSphereComputeClusterApplyHostImage(
 	if vlcmEnabled, err := m.GetSoftwareManagement(d.Id()); err != nil {
 		return err
 	} else if !vlcmEnabled.Enabled {
-		if err := resourceVsphereComputeClusterEnableSoftwareManagement(d, client); err != nil {
+		if err := resourceVsphereComputeClusterEnableSoftwareManagement(ctx, d, client); err != nil {
 			return err
 		}
 	}
@@ -1214,11 +1228,12 @@ func resourceVSphereComputeClusterApplyHostImage(
 		return err
 	}
 
-	_, err = tasks.NewManager(client).WaitForCompletion(context.Background(), taskID)
+	_, err = tasks.NewManager(client).WaitForCompletion(ctx, taskID)
 	return err
 }
 
 func resourceVSphereComputeClusterApplyEvc(
+	ctx context.Context,
 	d *schema.ResourceData,
 	_ interface{},
 	cluster *object.ClusterComputeResource,
@@ -1232,15 +1247,15 @@ func resourceVSphereComputeClusterApplyEvc(
 	}
 
 	if ns != "" {
-		return clustercomputeresource.ConfigureEvc(cluster, ns)
+		return clustercomputeresource.ConfigureEvc(ctx, cluster, ns)
 	} else if os != "" {
-		return clustercomputeresource.DisableEvc(cluster)
+		return clustercomputeresource.DisableEvc(ctx, cluster)
 	}
 
 	return nil
 }
 
-func resourceVsphereComputeClusterEnableSoftwareManagement(d *schema.ResourceData, client *rest.Client) error {
+func resourceVsphereComputeClusterEnableSoftwareManagement(ctx context.Context, d *schema.ResourceData, client *rest.Client) error {
 	m := clusters.NewManager(client)
 
 	if draftID, err := m.CreateSoftwareDraft(d.Id()); err != nil {
@@ -1249,11 +1264,11 @@ func resourceVsphereComputeClusterEnableSoftwareManagement(d *schema.ResourceDat
 		return err
 	} else if taskID, err := m.CommitSoftwareDraft(d.Id(), draftID, clusters.SettingsClustersSoftwareDraftsCommitSpec{}); err != nil {
 		return err
-	} else if _, err := tasks.NewManager(client).WaitForCompletion(context.Background(), taskID); err != nil {
+	} else if _, err := tasks.NewManager(client).WaitForCompletion(ctx, taskID); err != nil {
 		return err
 	} else if taskID, err := m.EnableSoftwareManagement(d.Id(), false); err != nil {
 		return err
-	} else if _, err := tasks.NewManager(client).WaitForCompletion(context.Background(), taskID); err != nil {
+	} else if _, err := tasks.NewManager(client).WaitForCompletion(ctx, taskID); err != nil {
 		return err
 	}
 	return nil
@@ -1297,6 +1312,7 @@ func getComponentsMap(components []interface{}) map[string]interface{} {
 // resourceVSphereComputeClusterReadCustomAttributes reads the custom
 // attributes for vsphere_compute_cluster.
 func resourceVSphereComputeClusterReadCustomAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1305,7 +1321,7 @@ func resourceVSphereComputeClusterReadCustomAttributes(
 	// Read custom attributes
 	if customattribute.IsSupported(client) {
 		log.Printf("[DEBUG] %s: Reading custom attributes", resourceVSphereComputeClusterIDString(d))
-		props, err := clustercomputeresource.Properties(cluster)
+		props, err := clustercomputeresource.Properties(ctx, cluster)
 		if err != nil {
 			return err
 		}
@@ -1320,6 +1336,7 @@ func resourceVSphereComputeClusterReadCustomAttributes(
 // resourceVSphereComputeClusterGetCluster gets the ComputeClusterResource from the ID
 // in the supplied ResourceData.
 func resourceVSphereComputeClusterGetCluster(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, error) {
@@ -1329,12 +1346,13 @@ func resourceVSphereComputeClusterGetCluster(
 		return nil, err
 	}
 
-	return clustercomputeresource.FromID(client, d.Id())
+	return clustercomputeresource.FromID(ctx, client, d.Id())
 }
 
 // resourceVSphereComputeClusterGetClusterFromPath gets the ComputeClusterResource from a
 // supplied path. If no datacenter is supplied, the path must be a full path.
 func resourceVSphereComputeClusterGetClusterFromPath(
+	ctx context.Context,
 	meta interface{},
 	path string,
 	dcID string,
@@ -1346,7 +1364,7 @@ func resourceVSphereComputeClusterGetClusterFromPath(
 	var dc *object.Datacenter
 	if dcID != "" {
 		var err error
-		dc, err = datacenterFromID(client, dcID)
+		dc, err = datacenterFromID(ctx, client, dcID)
 		if err != nil {
 			return nil, fmt.Errorf("cannot locate datacenter: %s", err)
 		}
@@ -1355,12 +1373,13 @@ func resourceVSphereComputeClusterGetClusterFromPath(
 		log.Printf("[DEBUG] Fetching cluster at path %q", path)
 	}
 
-	return clustercomputeresource.FromPath(client, path, dc)
+	return clustercomputeresource.FromPath(ctx, client, path, dc)
 }
 
 // resourceVSphereComputeClusterSaveDatacenter saves the datacenter that the
 // cluster is a member of for correctness on imports.
 func resourceVSphereComputeClusterSaveDatacenter(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1376,7 +1395,7 @@ func resourceVSphereComputeClusterSaveDatacenter(
 		return fmt.Errorf("error parsing datacenter path from cluster: %s", err)
 	}
 
-	dc, err := getDatacenter(client, p)
+	dc, err := getDatacenter(ctx, client, p)
 	if err != nil {
 		return fmt.Errorf("error fetching datacenter for cluster: %s", err)
 	}
@@ -1410,6 +1429,7 @@ func resourceVSphereComputeClusterSaveNameAndPath(d *schema.ResourceData, cluste
 // resourceVSphereComputeClusterApplyNameChange applies any changes to a
 // ClusterComputeResource's name.
 func resourceVSphereComputeClusterApplyNameChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1424,7 +1444,7 @@ func resourceVSphereComputeClusterApplyNameChange(
 	var err error
 
 	if d.HasChange("name") {
-		if err = clustercomputeresource.Rename(cluster, d.Get("name").(string)); err != nil {
+		if err = clustercomputeresource.Rename(ctx, cluster, d.Get("name").(string)); err != nil {
 			return nil, fmt.Errorf("error renaming cluster: %s", err)
 		}
 		changed = true
@@ -1433,7 +1453,7 @@ func resourceVSphereComputeClusterApplyNameChange(
 	if changed {
 		// Update the cluster so that we have the new inventory path for logging and
 		// other things
-		cluster, err = resourceVSphereComputeClusterGetCluster(d, meta)
+		cluster, err = resourceVSphereComputeClusterGetCluster(ctx, d, meta)
 		if err != nil {
 			return nil, fmt.Errorf("error refreshing cluster after name change: %s", err)
 		}
@@ -1450,6 +1470,7 @@ func resourceVSphereComputeClusterApplyNameChange(
 // resourceVSphereComputeClusterApplyFolderChange applies any changes to a
 // ClusterComputeResource's folder location.
 func resourceVSphereComputeClusterApplyFolderChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1466,7 +1487,7 @@ func resourceVSphereComputeClusterApplyFolderChange(
 	if d.HasChange("folder") {
 		f := d.Get("folder").(string)
 		client := meta.(*Client).vimClient
-		if err = clustercomputeresource.MoveToFolder(client, cluster, f); err != nil {
+		if err = clustercomputeresource.MoveToFolder(ctx, client, cluster, f); err != nil {
 			return nil, fmt.Errorf("could not move cluster to folder %q: %s", f, err)
 		}
 		changed = true
@@ -1475,7 +1496,7 @@ func resourceVSphereComputeClusterApplyFolderChange(
 	if changed {
 		// Update the cluster so that we have the new inventory path for logging and
 		// other things
-		cluster, err = resourceVSphereComputeClusterGetCluster(d, meta)
+		cluster, err = resourceVSphereComputeClusterGetCluster(ctx, d, meta)
 		if err != nil {
 			return nil, fmt.Errorf("error refreshing cluster after folder change: %s", err)
 		}
@@ -1493,11 +1514,12 @@ func resourceVSphereComputeClusterApplyFolderChange(
 // is empty. This is used to ensure a safe deletion of the cluster - we do not
 // allow deletion of clusters that still virtual machines or hosts in them.
 func resourceVSphereComputeClusterValidateEmptyCluster(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	cluster *object.ClusterComputeResource,
 ) error {
 	log.Printf("[DEBUG] %s: Checking to ensure that cluster is empty", resourceVSphereComputeClusterIDString(d))
-	ne, err := clustercomputeresource.HasChildren(cluster)
+	ne, err := clustercomputeresource.HasChildren(ctx, cluster)
 	if err != nil {
 		return fmt.Errorf("error checking for cluster contents: %s", err)
 	}
@@ -1518,6 +1540,7 @@ func resourceVSphereComputeClusterValidateEmptyCluster(
 // actually succeed depending on the resources actually in use in the
 // cluster, and specific constraints that exist in the cluster.
 func resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1533,6 +1556,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(
 
 	log.Printf("[DEBUG] %s: Force-evacuating hosts in cluster before removal", resourceVSphereComputeClusterIDString(d))
 	hosts, err := resourceVSphereComputeClusterGetHostSystemObjects(
+		ctx,
 		client,
 		structure.SliceInterfacesToStrings(d.Get("host_system_ids").(*schema.Set).List()),
 	)
@@ -1540,7 +1564,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(
 		return err
 	}
 
-	if err := clustercomputeresource.MoveHostsOutOf(cluster, hosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
+	if err := clustercomputeresource.MoveHostsOutOf(ctx, cluster, hosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
 		return fmt.Errorf("error force-removing old hosts out of cluster: %s", err)
 	}
 
@@ -1548,6 +1572,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(
 }
 
 func resourceVSphereComputeClusterDeleteProcessForceRemoveFaultDomain(
+	ctx context.Context,
 	d *schema.ResourceData,
 	cluster *object.ClusterComputeResource,
 	spec *types.ClusterConfigSpecEx,
@@ -1564,7 +1589,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveFaultDomain(
 	for _, fd := range spec.VsanHostConfigSpec {
 		fd.FaultDomainInfo.Name = ""
 	}
-	return clustercomputeresource.Reconfigure(cluster, spec)
+	return clustercomputeresource.Reconfigure(ctx, cluster, spec)
 }
 
 // resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore process
@@ -1575,6 +1600,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveFaultDomain(
 // actually succeed depending on the resources actually in use in the
 // cluster, and specific constraints that exist in the cluster.
 func resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1593,7 +1619,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(
 	conf := vsantypes.VimVsanReconfigSpec{
 		DatastoreConfig: &vsantypes.VsanAdvancedDatastoreConfig{},
 	}
-	if err := vsanclient.Reconfigure(meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
+	if err := vsanclient.Reconfigure(ctx, meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
 		return fmt.Errorf("cannot force-evacuate remote datastores on cluster: %s, err: %s", d.Get("name").(string), err)
 	}
 
@@ -1602,9 +1628,9 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(
 
 // resourceVSphereComputeClusterApplyDelete process the removal of a
 // cluster.
-func resourceVSphereComputeClusterApplyDelete(d structure.ResourceIDStringer, cluster *object.ClusterComputeResource) error {
+func resourceVSphereComputeClusterApplyDelete(ctx context.Context, d structure.ResourceIDStringer, cluster *object.ClusterComputeResource) error {
 	log.Printf("[DEBUG] %s: Proceeding with cluster deletion", resourceVSphereComputeClusterIDString(d))
-	return clustercomputeresource.Delete(cluster)
+	return clustercomputeresource.Delete(ctx, cluster)
 }
 
 // resourceVSphereComputeClusterFlattenData saves the configuration attributes
@@ -1614,6 +1640,7 @@ func resourceVSphereComputeClusterApplyDelete(d structure.ResourceIDStringer, cl
 // Note that other functions handle other non-configuration related items, such
 // as path, name, tags, and custom attributes.
 func resourceVSphereComputeClusterFlattenData(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1628,7 +1655,7 @@ func resourceVSphereComputeClusterFlattenData(
 	// attributes we need to set
 	version := viapi.ParseVersionFromClient(client)
 
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return err
 	}
@@ -1653,12 +1680,12 @@ func resourceVSphereComputeClusterFlattenData(
 	}
 
 	// VSAN
-	err = flattenVsanDisks(d, cluster)
+	err = flattenVsanDisks(ctx, d, cluster)
 	if err != nil {
 		return err
 	}
 
-	vsanConfig, err := vsanclient.GetVsanConfig(meta.(*Client).vsanClient, cluster.Reference())
+	vsanConfig, err := vsanclient.GetVsanConfig(ctx, meta.(*Client).vsanClient, cluster.Reference())
 	if err != nil {
 		return err
 	}
@@ -1712,7 +1739,7 @@ func resourceVSphereComputeClusterFlattenData(
 		return err
 	}
 
-	if err := flattenVsanStretchedCluster(meta.(*Client).vsanClient, d, cluster, props.ConfigurationEx.(*types.ClusterConfigInfoEx)); err != nil {
+	if err := flattenVsanStretchedCluster(ctx, meta.(*Client).vsanClient, d, cluster, props.ConfigurationEx.(*types.ClusterConfigInfoEx)); err != nil {
 		return err
 	}
 
@@ -1721,8 +1748,8 @@ func resourceVSphereComputeClusterFlattenData(
 
 // expandClusterConfigSpecEx reads certain ResourceData keys and returns a
 // ClusterConfigSpecEx.
-func expandClusterConfigSpecEx(d *schema.ResourceData, version viapi.VSphereVersion, cluster *object.ClusterComputeResource) (*types.ClusterConfigSpecEx, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+func expandClusterConfigSpecEx(ctx context.Context, d *schema.ResourceData, version viapi.VSphereVersion, cluster *object.ClusterComputeResource) (*types.ClusterConfigSpecEx, error) {
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -1841,7 +1868,7 @@ func expandVsanPerfConfig(d *schema.ResourceData) (*vsantypes.VsanPerfsvcConfig,
 	}, nil
 }
 
-func expandVsanDatastoreConfig(d *schema.ResourceData, meta interface{}) (*vsantypes.VsanAdvancedDatastoreConfig, error) {
+func expandVsanDatastoreConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) (*vsantypes.VsanAdvancedDatastoreConfig, error) {
 	vimClient := meta.(*Client).vimClient
 	conf := &vsantypes.VsanAdvancedDatastoreConfig{}
 
@@ -1852,7 +1879,7 @@ func expandVsanDatastoreConfig(d *schema.ResourceData, meta interface{}) (*vsant
 	}
 
 	for _, dsID := range dsIDs {
-		ds, err := datastore.FromID(vimClient, dsID)
+		ds, err := datastore.FromID(ctx, vimClient, dsID)
 		if err != nil {
 			return nil, fmt.Errorf("error locating datastore ID %q: %s", dsID, err)
 		}
@@ -1900,10 +1927,10 @@ func buildVsanStretchedClusterReq(d *schema.ResourceData, cluster types.ManagedO
 	}, nil
 }
 
-func buildVsanRemoveWitnessHostReq(_ *schema.ResourceData, cluster types.ManagedObjectReference, client *vsan.Client) (*vsantypes.VSANVcRemoveWitnessHost, error) {
+func buildVsanRemoveWitnessHostReq(ctx context.Context, _ *schema.ResourceData, cluster types.ManagedObjectReference, client *vsan.Client) (*vsantypes.VSANVcRemoveWitnessHost, error) {
 	log.Printf("[DEBUG] building vsan remove witness request...")
 
-	res, err := vsanclient.GetWitnessHosts(client, cluster.Reference())
+	res, err := vsanclient.GetWitnessHosts(ctx, client, cluster.Reference())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get witness node when removing witness")
 	}
@@ -1916,7 +1943,7 @@ func buildVsanRemoveWitnessHostReq(_ *schema.ResourceData, cluster types.Managed
 	}, nil
 }
 
-func resourceVSphereComputeClusterApplyVsanConfig(d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
+func resourceVSphereComputeClusterApplyVsanConfig(ctx context.Context, d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
 	client, err := resourceVSphereComputeClusterClient(meta)
 	if err != nil {
 		return err
@@ -1976,21 +2003,21 @@ func resourceVSphereComputeClusterApplyVsanConfig(d *schema.ResourceData, meta i
 	}
 	conf.PerfsvcConfig = perfConfig
 
-	if err := vsanclient.Reconfigure(meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
+	if err := vsanclient.Reconfigure(ctx, meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
 		return fmt.Errorf("cannot apply vsan service on cluster '%s': %s", d.Get("name").(string), err)
 	}
 
 	// handle disk groups
-	if err = updateVsanDisks(d, cluster, meta); err != nil {
+	if err = updateVsanDisks(ctx, d, cluster, meta); err != nil {
 		return err
 	}
 
 	// handle remote datastore/HCI Mesh in a separate call
-	datastoreConfig, err := expandVsanDatastoreConfig(d, meta)
+	datastoreConfig, err := expandVsanDatastoreConfig(ctx, d, meta)
 	if err != nil {
 		return err
 	}
-	if err := vsanclient.Reconfigure(meta.(*Client).vsanClient, cluster.Reference(), vsantypes.VimVsanReconfigSpec{
+	if err := vsanclient.Reconfigure(ctx, meta.(*Client).vsanClient, cluster.Reference(), vsantypes.VimVsanReconfigSpec{
 		Modify:          true,
 		DatastoreConfig: datastoreConfig,
 	}); err != nil {
@@ -2007,19 +2034,19 @@ func resourceVSphereComputeClusterApplyVsanConfig(d *schema.ResourceData, meta i
 				return err
 			}
 
-			if err := vsanclient.ConvertToStretchedCluster(meta.(*Client).vsanClient, meta.(*Client).vimClient, *req); err != nil {
+			if err := vsanclient.ConvertToStretchedCluster(ctx, meta.(*Client).vsanClient, meta.(*Client).vimClient, *req); err != nil {
 				return fmt.Errorf("cannot stretch cluster %s with spec: %#v\n, err: %#v", d.Get("name").(string), *req, err)
 			}
 		}
 
 		// disable stretched cluster
 		if len(n.([]interface{})) == 0 || n.([]interface{})[0].(map[string]interface{})["witness_node"].(string) == "" {
-			req, err := buildVsanRemoveWitnessHostReq(d, cluster.Reference(), meta.(*Client).vsanClient)
+			req, err := buildVsanRemoveWitnessHostReq(ctx, d, cluster.Reference(), meta.(*Client).vsanClient)
 			if err != nil {
 				return err
 			}
 
-			if err := vsanclient.RemoveWitnessHost(meta.(*Client).vsanClient, meta.(*Client).vimClient, *req); err != nil {
+			if err := vsanclient.RemoveWitnessHost(ctx, meta.(*Client).vsanClient, meta.(*Client).vimClient, *req); err != nil {
 				return fmt.Errorf("cannot disable stretched cluster %s with spec: %#v", d.Get("name").(string), *req)
 			}
 		}
@@ -2037,7 +2064,7 @@ func vsanDiskMapKey(d interface{}) string {
 	return strings.Join(append(storage, cache), " ")
 }
 
-func updateVsanDisks(d *schema.ResourceData, cluster *object.ClusterComputeResource, meta interface{}) error {
+func updateVsanDisks(ctx context.Context, d *schema.ResourceData, cluster *object.ClusterComputeResource, meta interface{}) error {
 	client := meta.(*Client).vimClient
 	o, n := d.GetChange("vsan_disk_group")
 	oldDisks := o.([]interface{})
@@ -2069,26 +2096,26 @@ func updateVsanDisks(d *schema.ResourceData, cluster *object.ClusterComputeResou
 		}
 	}
 
-	hosts, err := clustercomputeresource.Hosts(cluster)
+	hosts, err := clustercomputeresource.Hosts(ctx, cluster)
 	if err != nil {
 		return err
 	}
 
 	for _, host := range hosts {
-		if err = deleteVsanDisks(host, delSet, client); err != nil {
+		if err = deleteVsanDisks(ctx, host, delSet, client); err != nil {
 			return err
 		}
-		if err = addVsanDisks(host, addSet, client); err != nil {
+		if err = addVsanDisks(ctx, host, addSet, client); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func hostStorageSystemPropertiesFromHostSystemID(client *govmomi.Client, hostID string) (*mo.HostStorageSystem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func hostStorageSystemPropertiesFromHostSystemID(ctx context.Context, client *govmomi.Client, hostID string) (*mo.HostStorageSystem, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
-	hss, err := hostStorageSystemFromHostSystemID(client, hostID)
+	hss, err := hostStorageSystemFromHostSystemID(ctx, client, hostID)
 	if err != nil {
 		return nil, err
 	}
@@ -2097,11 +2124,11 @@ func hostStorageSystemPropertiesFromHostSystemID(client *govmomi.Client, hostID
 	return &hssProps, err
 }
 
-func generateDiskMap(client *govmomi.Client, host *object.HostSystem, list []interface{}) (*types.VsanHostDiskMapping, error) {
+func generateDiskMap(ctx context.Context, client *govmomi.Client, host *object.HostSystem, list []interface{}) (*types.VsanHostDiskMapping, error) {
 	diskMap := types.VsanHostDiskMapping{
 		NonSsd: []types.HostScsiDisk{},
 	}
-	hssProps, err := hostStorageSystemPropertiesFromHostSystemID(client, host.Reference().Value)
+	hssProps, err := hostStorageSystemPropertiesFromHostSystemID(ctx, client, host.Reference().Value)
 	if err != nil {
 		return nil, err
 	}
@@ -2125,22 +2152,22 @@ func generateDiskMap(client *govmomi.Client, host *object.HostSystem, list []int
 	return &diskMap, nil
 }
 
-func deleteVsanDisks(host *object.HostSystem, list []interface{}, client *govmomi.Client) error {
+func deleteVsanDisks(ctx context.Context, host *object.HostSystem, list []interface{}, client *govmomi.Client) error {
 	if len(list) == 0 {
 		return nil
 	}
 	log.Printf("deleteVsanDisks: Starting removal of vSAN disks on %s.", host.Name())
-	hvs, err := vsansystem.FromHost(host, defaultAPITimeout)
+	hvs, err := vsansystem.FromHost(ctx, host, defaultAPITimeout)
 	if err != nil {
 		return nil
 	}
-	diskMap, err := generateDiskMap(client, host, list)
+	diskMap, err := generateDiskMap(ctx, client, host, list)
 	if err != nil {
 		return err
 	}
 	if diskMap.Ssd.CanonicalName != "" || len(diskMap.NonSsd) > 0 {
 		log.Printf("deleteVsanDisks: Scheduled disks are being removed.")
-		if err = vsansystem.RemoveDiskMapping(client, host, hvs, diskMap, defaultAPITimeout); err != nil {
+		if err = vsansystem.RemoveDiskMapping(ctx, client, host, hvs, diskMap, defaultAPITimeout); err != nil {
 			return err
 		}
 		log.Printf("deleteVsanDisks: vSAN disks successfully removed.")
@@ -2150,22 +2177,22 @@ func deleteVsanDisks(host *object.HostSystem, list []interface{}, client *govmom
 	return nil
 }
 
-func addVsanDisks(host *object.HostSystem, list []interface{}, client *govmomi.Client) error {
+func addVsanDisks(ctx context.Context, host *object.HostSystem, list []interface{}, client *govmomi.Client) error {
 	if len(list) == 0 {
 		return nil
 	}
 	log.Printf("addVsanDisks: Starting initialization of vSAN disks on %s.", host.Name())
-	hvs, err := vsansystem.FromHost(host, defaultAPITimeout)
+	hvs, err := vsansystem.FromHost(ctx, host, defaultAPITimeout)
 	if err != nil {
 		return nil
 	}
-	diskMap, err := generateDiskMap(client, host, list)
+	diskMap, err := generateDiskMap(ctx, client, host, list)
 	if err != nil {
 		return err
 	}
 	if diskMap.Ssd.CanonicalName != "" {
 		log.Printf("addVsanDisks: Scheduled disks are being initialized.")
-		if err = vsansystem.InitializeDisks(client, host, hvs, diskMap, defaultAPITimeout); err != nil {
+		if err = vsansystem.InitializeDisks(ctx, client, host, hvs, diskMap, defaultAPITimeout); err != nil {
 			return err
 		}
 		log.Printf("addVsanDisks: vSAN disks successfully initialized.")
@@ -2175,19 +2202,19 @@ func addVsanDisks(host *object.HostSystem, list []interface{}, client *govmomi.C
 	return nil
 }
 
-func flattenVsanDisks(d *schema.ResourceData, cluster *object.ClusterComputeResource) error {
+func flattenVsanDisks(ctx context.Context, d *schema.ResourceData, cluster *object.ClusterComputeResource) error {
 	var diskMap []interface{}
 
-	hosts, err := clustercomputeresource.Hosts(cluster)
+	hosts, err := clustercomputeresource.Hosts(ctx, cluster)
 	if err != nil {
 		return err
 	}
 	for _, host := range hosts {
-		hvs, err := vsansystem.FromHost(host, defaultAPITimeout)
+		hvs, err := vsansystem.FromHost(ctx, host, defaultAPITimeout)
 		if err != nil {
 			return err
 		}
-		hvsProps, err := vsansystem.Properties(hvs, defaultAPITimeout)
+		hvsProps, err := vsansystem.Properties(ctx, hvs, defaultAPITimeout)
 		if err != nil {
 			return err
 		}
@@ -2208,8 +2235,8 @@ func flattenVsanDisks(d *schema.ResourceData, cluster *object.ClusterComputeReso
 	return d.Set("vsan_disk_group", diskMap)
 }
 
-func flattenVsanStretchedCluster(client *vsan.Client, d *schema.ResourceData, cluster *object.ClusterComputeResource, obj *types.ClusterConfigInfoEx) error {
-	res, err := vsanclient.GetWitnessHosts(client, cluster.Reference())
+func flattenVsanStretchedCluster(ctx context.Context, client *vsan.Client, d *schema.ResourceData, cluster *object.ClusterComputeResource, obj *types.ClusterConfigInfoEx) error {
+	res, err := vsanclient.GetWitnessHosts(ctx, client, cluster.Reference())
 	if err != nil {
 		return err
 	}
@@ -5,12 +5,15 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -24,12 +27,18 @@ const resourceVSphereComputeClusterHostGroupName = "vsphere_compute_cluster_host
 
 func resourceVSphereComputeClusterHostGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterHostGroupCreate,
-		Read:   resourceVSphereComputeClusterHostGroupRead,
-		Update: resourceVSphereComputeClusterHostGroupUpdate,
-		Delete: resourceVSphereComputeClusterHostGroupDelete,
+		CreateContext: resourceVSphereComputeClusterHostGroupCreate,
+		ReadContext:   resourceVSphereComputeClusterHostGroupRead,
+		UpdateContext: resourceVSphereComputeClusterHostGroupUpdate,
+		DeleteContext: resourceVSphereComputeClusterHostGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterHostGroupImport,
+			StateContext: resourceVSphereComputeClusterHostGroupImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -55,17 +64,17 @@ func resourceVSphereComputeClusterHostGroup() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterHostGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterHostGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterHostGroup(d, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		GroupSpec: []types.ClusterGroupSpec{
@@ -78,31 +87,31 @@ func resourceVSphereComputeClusterHostGroupCreate(d *schema.ResourceData, meta i
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterHostGroupFlattenID(cluster, name)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterHostGroupIDString(d))
-	return resourceVSphereComputeClusterHostGroupRead(d, meta)
+	return resourceVSphereComputeClusterHostGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterHostGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereComputeClusterHostGroupFindEntry(cluster, name)
+	info, err := resourceVSphereComputeClusterHostGroupFindEntry(ctx, cluster, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -115,34 +124,34 @@ func resourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta int
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
 	// This is the "correct" way to set name here, even if it's a bit
 	// superfluous.
 	if err = d.Set("name", info.Name); err != nil {
-		return fmt.Errorf("error setting attribute \"name\": %s", err)
+		return diag.Errorf("error setting attribute \"name\": %s", err)
 	}
 
 	if err = flattenClusterHostGroup(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterHostGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterHostGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterHostGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterHostGroup(d, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		GroupSpec: []types.ClusterGroupSpec{
@@ -155,20 +164,20 @@ func resourceVSphereComputeClusterHostGroupUpdate(d *schema.ResourceData, meta i
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterHostGroupIDString(d))
-	return resourceVSphereComputeClusterHostGroupRead(d, meta)
+	return resourceVSphereComputeClusterHostGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterHostGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterHostGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -182,15 +191,15 @@ func resourceVSphereComputeClusterHostGroupDelete(d *schema.ResourceData, meta i
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterHostGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterHostGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterHostGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -209,12 +218,12 @@ func resourceVSphereComputeClusterHostGroupImport(d *schema.ResourceData, meta i
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	info, err := resourceVSphereComputeClusterHostGroupFindEntry(cluster, name)
+	info, err := resourceVSphereComputeClusterHostGroupFindEntry(ctx, cluster, name)
 	if err != nil {
 		return nil, err
 	}
@@ -284,10 +293,11 @@ func resourceVSphereComputeClusterHostGroupParseID(id string) (string, string, e
 // resource's read functionality and tests. nil is returned if the entry cannot
 // be found.
 func resourceVSphereComputeClusterHostGroupFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterHostGroup, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -311,20 +321,22 @@ func resourceVSphereComputeClusterHostGroupFindEntry(
 // * If the resource ID is available, the data is derived from the ID.
 // * If not, it's derived from the compute_cluster_id and name attributes.
 func resourceVSphereComputeClusterHostGroupObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, string, error) {
 	if d.Id() != "" {
-		return resourceVSphereComputeClusterHostGroupObjectsFromID(d, meta)
+		return resourceVSphereComputeClusterHostGroupObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereComputeClusterHostGroupObjectsFromAttributes(d, meta)
+	return resourceVSphereComputeClusterHostGroupObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereComputeClusterHostGroupObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, string, error) {
-	return resourceVSphereComputeClusterHostGroupFetchObjects(
+	return resourceVSphereComputeClusterHostGroupFetchObjects(ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		d.Get("name").(string),
@@ -332,6 +344,7 @@ func resourceVSphereComputeClusterHostGroupObjectsFromAttributes(
 }
 
 func resourceVSphereComputeClusterHostGroupObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, string, error) {
@@ -343,7 +356,7 @@ func resourceVSphereComputeClusterHostGroupObjectsFromID(
 		return nil, "", err
 	}
 
-	return resourceVSphereComputeClusterHostGroupFetchObjects(meta, clusterID, name)
+	return resourceVSphereComputeClusterHostGroupFetchObjects(ctx, meta, clusterID, name)
 }
 
 // resourceVSphereComputeClusterHostGroupFetchObjects fetches the "objects" for
@@ -353,6 +366,7 @@ func resourceVSphereComputeClusterHostGroupObjectsFromID(
 // ArrayUpdateSpec that have managed object as keys, such as VM and host
 // overrides.
 func resourceVSphereComputeClusterHostGroupFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	name string,
@@ -362,7 +376,7 @@ func resourceVSphereComputeClusterHostGroupFetchObjects(
 		return nil, "", err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, "", fmt.Errorf("cannot locate cluster: %s", err)
 	}
@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -811,7 +812,7 @@ func testAccResourceVSphereComputeClusterCheckAdmissionControlFailoverHost(expec
 		}
 
 		client := testAccProvider.Meta().(*Client).vimClient
-		hs, err := hostsystem.FromID(client, failoverHostsPolicy.FailoverHosts[0].Value)
+		hs, err := hostsystem.FromID(context.Background(), client, failoverHostsPolicy.FailoverHosts[0].Value)
 		if err != nil {
 			return err
 		}
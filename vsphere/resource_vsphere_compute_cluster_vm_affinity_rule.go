@@ -5,13 +5,16 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -26,12 +29,18 @@ const resourceVSphereComputeClusterVMAffinityRuleName = "vsphere_compute_cluster
 
 func resourceVSphereComputeClusterVMAffinityRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMAffinityRuleCreate,
-		Read:   resourceVSphereComputeClusterVMAffinityRuleRead,
-		Update: resourceVSphereComputeClusterVMAffinityRuleUpdate,
-		Delete: resourceVSphereComputeClusterVMAffinityRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMAffinityRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMAffinityRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMAffinityRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMAffinityRuleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMAffinityRuleImport,
+			StateContext: resourceVSphereComputeClusterVMAffinityRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -67,17 +76,17 @@ func resourceVSphereComputeClusterVMAffinityRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
-	cluster, _, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
+	cluster, _, err := resourceVSphereComputeClusterVMAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := expandClusterAffinityRuleSpec(d, meta)
+	info, err := expandClusterAffinityRuleSpec(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -90,36 +99,36 @@ func resourceVSphereComputeClusterVMAffinityRuleCreate(d *schema.ResourceData, m
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	info, err = resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(cluster, info.Name)
+	info, err = resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(ctx, cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMAffinityRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMAffinityRuleFindEntry(cluster, key)
+	info, err := resourceVSphereComputeClusterVMAffinityRuleFindEntry(ctx, cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -132,28 +141,28 @@ func resourceVSphereComputeClusterVMAffinityRuleRead(d *schema.ResourceData, met
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
-	if err = flattenClusterAffinityRuleSpec(d, meta, info); err != nil {
-		return err
+	if err = flattenClusterAffinityRuleSpec(ctx, d, meta, info); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := expandClusterAffinityRuleSpec(d, meta)
+	info, err := expandClusterAffinityRuleSpec(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -168,20 +177,20 @@ func resourceVSphereComputeClusterVMAffinityRuleUpdate(d *schema.ResourceData, m
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -195,15 +204,15 @@ func resourceVSphereComputeClusterVMAffinityRuleDelete(d *schema.ResourceData, m
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMAffinityRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -222,12 +231,12 @@ func resourceVSphereComputeClusterVMAffinityRuleImport(d *schema.ResourceData, m
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(cluster, name)
+	info, err := resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(ctx, cluster, name)
 	if err != nil {
 		return nil, err
 	}
@@ -242,13 +251,14 @@ func resourceVSphereComputeClusterVMAffinityRuleImport(d *schema.ResourceData, m
 
 // expandClusterAffinityRuleSpec reads certain ResourceData keys and returns a
 // ClusterAffinityRuleSpec.
-func expandClusterAffinityRuleSpec(d *schema.ResourceData, meta interface{}) (*types.ClusterAffinityRuleSpec, error) {
+func expandClusterAffinityRuleSpec(ctx context.Context, d *schema.ResourceData, meta interface{}) (*types.ClusterAffinityRuleSpec, error) {
 	client, err := resourceVSphereComputeClusterVMGroupClient(meta)
 	if err != nil {
 		return nil, err
 	}
 
 	results, err := virtualmachine.MOIDsForUUIDs(
+		ctx,
 		client,
 		structure.SliceInterfacesToStrings(d.Get("virtual_machine_ids").(*schema.Set).List()),
 	)
@@ -269,13 +279,14 @@ func expandClusterAffinityRuleSpec(d *schema.ResourceData, meta interface{}) (*t
 }
 
 // flattenClusterAffinityRuleSpec saves a ClusterAffinityRuleSpec into the supplied ResourceData.
-func flattenClusterAffinityRuleSpec(d *schema.ResourceData, meta interface{}, obj *types.ClusterAffinityRuleSpec) error {
+func flattenClusterAffinityRuleSpec(ctx context.Context, d *schema.ResourceData, meta interface{}, obj *types.ClusterAffinityRuleSpec) error {
 	client, err := resourceVSphereComputeClusterVMGroupClient(meta)
 	if err != nil {
 		return err
 	}
 
 	results, err := virtualmachine.UUIDsForManagedObjectReferences(
+		ctx,
 		client,
 		obj.Vm,
 	)
@@ -325,10 +336,11 @@ func resourceVSphereComputeClusterVMAffinityRuleParseID(id string) (string, int3
 // resource's read functionality and tests. nil is returned if the entry cannot
 // be found.
 func resourceVSphereComputeClusterVMAffinityRuleFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -353,10 +365,11 @@ func resourceVSphereComputeClusterVMAffinityRuleFindEntry(
 // don't allow missing entries, as it's designed to be used in places where we
 // don't want to allow for missing entries, such as during creation and import.
 func resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -380,20 +393,22 @@ func resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(
 // * If not, only the cluster is retrieved from compute_cluster_id. -1 is
 // returned for the key.
 func resourceVSphereComputeClusterVMAffinityRuleObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
 	if d.Id() != "" {
-		return resourceVSphereComputeClusterVMAffinityRuleObjectsFromID(d, meta)
+		return resourceVSphereComputeClusterVMAffinityRuleObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereComputeClusterVMAffinityRuleObjectsFromAttributes(d, meta)
+	return resourceVSphereComputeClusterVMAffinityRuleObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereComputeClusterVMAffinityRuleObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
-	return resourceVSphereComputeClusterVMAffinityRuleFetchObjects(
+	return resourceVSphereComputeClusterVMAffinityRuleFetchObjects(ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		-1,
@@ -401,6 +416,7 @@ func resourceVSphereComputeClusterVMAffinityRuleObjectsFromAttributes(
 }
 
 func resourceVSphereComputeClusterVMAffinityRuleObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
@@ -412,7 +428,7 @@ func resourceVSphereComputeClusterVMAffinityRuleObjectsFromID(
 		return nil, 0, err
 	}
 
-	return resourceVSphereComputeClusterVMAffinityRuleFetchObjects(meta, clusterID, key)
+	return resourceVSphereComputeClusterVMAffinityRuleFetchObjects(ctx, meta, clusterID, key)
 }
 
 // resourceVSphereComputeClusterVMAffinityRuleFetchObjects fetches the "objects"
@@ -422,6 +438,7 @@ func resourceVSphereComputeClusterVMAffinityRuleObjectsFromID(
 // ArrayUpdateSpec that have managed object as keys, such as VM and host
 // overrides.
 func resourceVSphereComputeClusterVMAffinityRuleFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	key int32,
@@ -431,7 +448,7 @@ func resourceVSphereComputeClusterVMAffinityRuleFetchObjects(
 		return nil, 0, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("cannot locate cluster: %s", err)
 	}
@@ -5,13 +5,16 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -26,12 +29,18 @@ const resourceVSphereComputeClusterVMAntiAffinityRuleName = "vsphere_compute_clu
 
 func resourceVSphereComputeClusterVMAntiAffinityRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMAntiAffinityRuleCreate,
-		Read:   resourceVSphereComputeClusterVMAntiAffinityRuleRead,
-		Update: resourceVSphereComputeClusterVMAntiAffinityRuleUpdate,
-		Delete: resourceVSphereComputeClusterVMAntiAffinityRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMAntiAffinityRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMAntiAffinityRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMAntiAffinityRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMAntiAffinityRuleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMAntiAffinityRuleImport,
+			StateContext: resourceVSphereComputeClusterVMAntiAffinityRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -67,17 +76,17 @@ func resourceVSphereComputeClusterVMAntiAffinityRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
-	cluster, _, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
+	cluster, _, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
+	info, err := expandClusterAntiAffinityRuleSpec(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -90,36 +99,36 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleCreate(d *schema.ResourceDat
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	info, err = resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(cluster, info.Name)
+	info, err = resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(ctx, cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMAntiAffinityRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(cluster, key)
+	info, err := resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(ctx, cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -132,28 +141,28 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleRead(d *schema.ResourceData,
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
-	if err = flattenClusterAntiAffinityRuleSpec(d, meta, info); err != nil {
-		return err
+	if err = flattenClusterAntiAffinityRuleSpec(ctx, d, meta, info); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
+	info, err := expandClusterAntiAffinityRuleSpec(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -168,20 +177,20 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleUpdate(d *schema.ResourceDat
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -195,15 +204,15 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleDelete(d *schema.ResourceDat
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMAntiAffinityRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -222,12 +231,12 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleImport(d *schema.ResourceDat
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(cluster, name)
+	info, err := resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(ctx, cluster, name)
 	if err != nil {
 		return nil, err
 	}
@@ -242,13 +251,14 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleImport(d *schema.ResourceDat
 
 // expandClusterAntiAffinityRuleSpec reads certain ResourceData keys and returns a
 // ClusterAntiAffinityRuleSpec.
-func expandClusterAntiAffinityRuleSpec(d *schema.ResourceData, meta interface{}) (*types.ClusterAntiAffinityRuleSpec, error) {
+func expandClusterAntiAffinityRuleSpec(ctx context.Context, d *schema.ResourceData, meta interface{}) (*types.ClusterAntiAffinityRuleSpec, error) {
 	client, err := resourceVSphereComputeClusterVMGroupClient(meta)
 	if err != nil {
 		return nil, err
 	}
 
 	results, err := virtualmachine.MOIDsForUUIDs(
+		ctx,
 		client,
 		structure.SliceInterfacesToStrings(d.Get("virtual_machine_ids").(*schema.Set).List()),
 	)
@@ -269,13 +279,14 @@ func expandClusterAntiAffinityRuleSpec(d *schema.ResourceData, meta interface{})
 }
 
 // flattenClusterAntiAffinityRuleSpec saves a ClusterAntiAffinityRuleSpec into the supplied ResourceData.
-func flattenClusterAntiAffinityRuleSpec(d *schema.ResourceData, meta interface{}, obj *types.ClusterAntiAffinityRuleSpec) error {
+func flattenClusterAntiAffinityRuleSpec(ctx context.Context, d *schema.ResourceData, meta interface{}, obj *types.ClusterAntiAffinityRuleSpec) error {
 	client, err := resourceVSphereComputeClusterVMGroupClient(meta)
 	if err != nil {
 		return err
 	}
 
 	results, err := virtualmachine.UUIDsForManagedObjectReferences(
+		ctx,
 		client,
 		obj.Vm,
 	)
@@ -324,10 +335,11 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleParseID(id string) (string,
 // resource's read functionality and tests. nil is returned if the entry cannot
 // be found.
 func resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -352,10 +364,11 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(
 // don't allow missing entries, as it's designed to be used in places where we
 // don't want to allow for missing entries, such as during creation and import.
 func resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -379,20 +392,22 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(
 // * If not, only the cluster is retrieved from compute_cluster_id. -1 is
 // returned for the key.
 func resourceVSphereComputeClusterVMAntiAffinityRuleObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
 	if d.Id() != "" {
-		return resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromID(d, meta)
+		return resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromAttributes(d, meta)
+	return resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
-	return resourceVSphereComputeClusterVMAntiAffinityRuleFetchObjects(
+	return resourceVSphereComputeClusterVMAntiAffinityRuleFetchObjects(ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		-1,
@@ -400,6 +415,7 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromAttributes(
 }
 
 func resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
@@ -411,7 +427,7 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromID(
 		return nil, 0, err
 	}
 
-	return resourceVSphereComputeClusterVMAntiAffinityRuleFetchObjects(meta, clusterID, key)
+	return resourceVSphereComputeClusterVMAntiAffinityRuleFetchObjects(ctx, meta, clusterID, key)
 }
 
 // resourceVSphereComputeClusterVMAntiAffinityRuleFetchObjects fetches the "objects"
@@ -421,6 +437,7 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleObjectsFromID(
 // ArrayUpdateSpec that have managed object as keys, such as VM and host
 // overrides.
 func resourceVSphereComputeClusterVMAntiAffinityRuleFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	key int32,
@@ -430,7 +447,7 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleFetchObjects(
 		return nil, 0, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("cannot locate cluster: %s", err)
 	}
@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -268,7 +269,7 @@ func testAccResourceVSphereComputeClusterVMAntiAffinityRuleMatchMembershipVMIDs(
 		ids = testAccResourceVSphereComputeClusterVMAntiAffinityRuleGetMultiple(s)
 	}
 
-	results, err := virtualmachine.MOIDsForUUIDs(testAccProvider.Meta().(*Client).vimClient, ids)
+	results, err := virtualmachine.MOIDsForUUIDs(context.Background(), testAccProvider.Meta().(*Client).vimClient, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -5,13 +5,16 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -25,12 +28,18 @@ const resourceVSphereComputeClusterVMDependencyRuleName = "vsphere_compute_clust
 
 func resourceVSphereComputeClusterVMDependencyRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMDependencyRuleCreate,
-		Read:   resourceVSphereComputeClusterVMDependencyRuleRead,
-		Update: resourceVSphereComputeClusterVMDependencyRuleUpdate,
-		Delete: resourceVSphereComputeClusterVMDependencyRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMDependencyRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMDependencyRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMDependencyRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMDependencyRuleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMDependencyRuleImport,
+			StateContext: resourceVSphereComputeClusterVMDependencyRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -70,17 +79,17 @@ func resourceVSphereComputeClusterVMDependencyRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
-	cluster, _, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
+	cluster, _, err := resourceVSphereComputeClusterVMDependencyRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDependencyRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -93,36 +102,36 @@ func resourceVSphereComputeClusterVMDependencyRuleCreate(d *schema.ResourceData,
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	info, err = resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(cluster, info.Name)
+	info, err = resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(ctx, cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMDependencyRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
-	return resourceVSphereComputeClusterVMDependencyRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMDependencyRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMDependencyRuleFindEntry(cluster, key)
+	info, err := resourceVSphereComputeClusterVMDependencyRuleFindEntry(ctx, cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -135,28 +144,28 @@ func resourceVSphereComputeClusterVMDependencyRuleRead(d *schema.ResourceData, m
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
 	if err = flattenClusterDependencyRuleInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDependencyRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -171,20 +180,20 @@ func resourceVSphereComputeClusterVMDependencyRuleUpdate(d *schema.ResourceData,
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
-	return resourceVSphereComputeClusterVMDependencyRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMDependencyRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -198,15 +207,15 @@ func resourceVSphereComputeClusterVMDependencyRuleDelete(d *schema.ResourceData,
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMDependencyRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -225,12 +234,12 @@ func resourceVSphereComputeClusterVMDependencyRuleImport(d *schema.ResourceData,
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(cluster, name)
+	info, err := resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(ctx, cluster, name)
 	if err != nil {
 		return nil, err
 	}
@@ -304,10 +313,11 @@ func resourceVSphereComputeClusterVMDependencyRuleParseID(id string) (string, in
 // resource's read functionality and tests. nil is returned if the entry cannot
 // be found.
 func resourceVSphereComputeClusterVMDependencyRuleFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterDependencyRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -332,10 +342,11 @@ func resourceVSphereComputeClusterVMDependencyRuleFindEntry(
 // don't allow missing entries, as it's designed to be used in places where we
 // don't want to allow for missing entries, such as during creation and import.
 func resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterDependencyRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -359,20 +370,22 @@ func resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(
 // * If not, only the cluster is retrieved from compute_cluster_id. -1 is
 // returned for the key.
 func resourceVSphereComputeClusterVMDependencyRuleObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
 	if d.Id() != "" {
-		return resourceVSphereComputeClusterVMDependencyRuleObjectsFromID(d, meta)
+		return resourceVSphereComputeClusterVMDependencyRuleObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereComputeClusterVMDependencyRuleObjectsFromAttributes(d, meta)
+	return resourceVSphereComputeClusterVMDependencyRuleObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereComputeClusterVMDependencyRuleObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
-	return resourceVSphereComputeClusterVMDependencyRuleFetchObjects(
+	return resourceVSphereComputeClusterVMDependencyRuleFetchObjects(ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		-1,
@@ -380,6 +393,7 @@ func resourceVSphereComputeClusterVMDependencyRuleObjectsFromAttributes(
 }
 
 func resourceVSphereComputeClusterVMDependencyRuleObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
@@ -391,7 +405,7 @@ func resourceVSphereComputeClusterVMDependencyRuleObjectsFromID(
 		return nil, 0, err
 	}
 
-	return resourceVSphereComputeClusterVMDependencyRuleFetchObjects(meta, clusterID, key)
+	return resourceVSphereComputeClusterVMDependencyRuleFetchObjects(ctx, meta, clusterID, key)
 }
 
 // resourceVSphereComputeClusterVMDependencyRuleFetchObjects fetches the "objects"
@@ -401,6 +415,7 @@ func resourceVSphereComputeClusterVMDependencyRuleObjectsFromID(
 // ArrayUpdateSpec that have managed object as keys, such as VM and host
 // overrides.
 func resourceVSphereComputeClusterVMDependencyRuleFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	key int32,
@@ -410,7 +425,7 @@ func resourceVSphereComputeClusterVMDependencyRuleFetchObjects(
 		return nil, 0, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("cannot locate cluster: %s", err)
 	}
@@ -11,7 +11,9 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -26,12 +28,18 @@ const resourceVSphereComputeClusterVMGroupName = "vsphere_compute_cluster_vm_gro
 
 func resourceVSphereComputeClusterVMGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMGroupCreate,
-		Read:   resourceVSphereComputeClusterVMGroupRead,
-		Update: resourceVSphereComputeClusterVMGroupUpdate,
-		Delete: resourceVSphereComputeClusterVMGroupDelete,
+		CreateContext: resourceVSphereComputeClusterVMGroupCreate,
+		ReadContext:   resourceVSphereComputeClusterVMGroupRead,
+		UpdateContext: resourceVSphereComputeClusterVMGroupUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMGroupImport,
+			StateContext: resourceVSphereComputeClusterVMGroupImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -57,28 +65,28 @@ func resourceVSphereComputeClusterVMGroup() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Check if the VM group already exists
-	exists, err := resourceVSphereComputeClusterVMGroupFindEntry(cluster, name)
+	exists, err := resourceVSphereComputeClusterVMGroupFindEntry(ctx, cluster, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if exists != nil {
 		log.Printf("[DEBUG] %s: VM group already exists, calling update", exists.Name)
-		return resourceVSphereComputeClusterVMGroupUpdate(d, meta)
+		return resourceVSphereComputeClusterVMGroupUpdate(ctx, d, meta)
 	}
 
-	info, err := expandClusterVMGroup(d, meta, name)
+	info, err := expandClusterVMGroup(ctx, d, meta, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		GroupSpec: []types.ClusterGroupSpec{
@@ -91,31 +99,31 @@ func resourceVSphereComputeClusterVMGroupCreate(d *schema.ResourceData, meta int
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMGroupFlattenID(cluster, name)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMGroupIDString(d))
-	return resourceVSphereComputeClusterVMGroupRead(d, meta)
+	return resourceVSphereComputeClusterVMGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMGroupFindEntry(cluster, name)
+	info, err := resourceVSphereComputeClusterVMGroupFindEntry(ctx, cluster, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -128,46 +136,46 @@ func resourceVSphereComputeClusterVMGroupRead(d *schema.ResourceData, meta inter
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
 	// This is the "correct" way to set name here, even if it's a bit
 	// superfluous.
 	if err = d.Set("name", info.Name); err != nil {
-		return fmt.Errorf("error setting attribute \"name\": %s", err)
+		return diag.Errorf("error setting attribute \"name\": %s", err)
 	}
 
-	if err = flattenClusterVMGroup(d, meta, info); err != nil {
-		return err
+	if err = flattenClusterVMGroup(ctx, d, meta, info); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Retrieve the existing VM group information.
-	existingGroup, err := getCurrentVMsInGroup(cluster, name)
+	existingGroup, err := getCurrentVMsInGroup(ctx, cluster, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Check if existingGroup is nil.
 	if existingGroup == nil {
-		return fmt.Errorf("VM group %s not found", name)
+		return diag.Errorf("VM group %s not found", name)
 	}
 
 	// Expand the new VM group information.
-	newInfo, err := expandClusterVMGroup(d, meta, name)
+	newInfo, err := expandClusterVMGroup(ctx, d, meta, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Convert existing and new virtual machines to string slices for diffVMGroup.
@@ -216,7 +224,7 @@ func resourceVSphereComputeClusterVMGroupUpdate(d *schema.ResourceData, meta int
 	}
 
 	if len(uniqueVMs) == 0 {
-		return fmt.Errorf("the resultant set of virtual machines in the vm group cannot be empty")
+		return diag.Errorf("the resultant set of virtual machines in the vm group cannot be empty")
 	}
 
 	// Update the VM group information with the merged list.
@@ -233,20 +241,20 @@ func resourceVSphereComputeClusterVMGroupUpdate(d *schema.ResourceData, meta int
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMGroupIDString(d))
-	return resourceVSphereComputeClusterVMGroupRead(d, meta)
+	return resourceVSphereComputeClusterVMGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMGroupIDString(d))
 
-	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
+	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -260,15 +268,15 @@ func resourceVSphereComputeClusterVMGroupDelete(d *schema.ResourceData, meta int
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -287,12 +295,12 @@ func resourceVSphereComputeClusterVMGroupImport(d *schema.ResourceData, meta int
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMGroupFindEntry(cluster, name)
+	info, err := resourceVSphereComputeClusterVMGroupFindEntry(ctx, cluster, name)
 	if err != nil {
 		return nil, err
 	}
@@ -311,13 +319,14 @@ func resourceVSphereComputeClusterVMGroupImport(d *schema.ResourceData, meta int
 
 // expandClusterVMGroup reads certain ResourceData keys and returns a
 // ClusterVmGroup.
-func expandClusterVMGroup(d *schema.ResourceData, meta interface{}, name string) (*types.ClusterVmGroup, error) {
+func expandClusterVMGroup(ctx context.Context, d *schema.ResourceData, meta interface{}, name string) (*types.ClusterVmGroup, error) {
 	client, err := resourceVSphereComputeClusterVMGroupClient(meta)
 	if err != nil {
 		return nil, err
 	}
 
 	results, err := virtualmachine.MOIDsForUUIDs(
+		ctx,
 		client,
 		structure.SliceInterfacesToStrings(d.Get("virtual_machine_ids").(*schema.Set).List()),
 	)
@@ -336,13 +345,14 @@ func expandClusterVMGroup(d *schema.ResourceData, meta interface{}, name string)
 }
 
 // flattenClusterVmGroup saves a ClusterVmGroup into the supplied ResourceData.
-func flattenClusterVMGroup(d *schema.ResourceData, meta interface{}, obj *types.ClusterVmGroup) error {
+func flattenClusterVMGroup(ctx context.Context, d *schema.ResourceData, meta interface{}, obj *types.ClusterVmGroup) error {
 	client, err := resourceVSphereComputeClusterVMGroupClient(meta)
 	if err != nil {
 		return err
 	}
 
 	results, err := virtualmachine.UUIDsForManagedObjectReferences(
+		ctx,
 		client,
 		obj.Vm,
 	)
@@ -382,10 +392,11 @@ func resourceVSphereComputeClusterVMGroupParseID(id string) (string, string, err
 // VM group config in a cluster's configuration. It's used by the resource's
 // read functionality and tests. nil is returned if the entry cannot be found.
 func resourceVSphereComputeClusterVMGroupFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterVmGroup, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -409,20 +420,22 @@ func resourceVSphereComputeClusterVMGroupFindEntry(
 // * If the resource ID is available, the data is derived from the ID.
 // * If not, it's derived from the compute_cluster_id and name attributes.
 func resourceVSphereComputeClusterVMGroupObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, string, error) {
 	if d.Id() != "" {
-		return resourceVSphereComputeClusterVMGroupObjectsFromID(d, meta)
+		return resourceVSphereComputeClusterVMGroupObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereComputeClusterVMGroupObjectsFromAttributes(d, meta)
+	return resourceVSphereComputeClusterVMGroupObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereComputeClusterVMGroupObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, string, error) {
-	return resourceVSphereComputeClusterVMGroupFetchObjects(
+	return resourceVSphereComputeClusterVMGroupFetchObjects(ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		d.Get("name").(string),
@@ -430,6 +443,7 @@ func resourceVSphereComputeClusterVMGroupObjectsFromAttributes(
 }
 
 func resourceVSphereComputeClusterVMGroupObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, string, error) {
@@ -441,7 +455,7 @@ func resourceVSphereComputeClusterVMGroupObjectsFromID(
 		return nil, "", err
 	}
 
-	return resourceVSphereComputeClusterVMGroupFetchObjects(meta, clusterID, name)
+	return resourceVSphereComputeClusterVMGroupFetchObjects(ctx, meta, clusterID, name)
 }
 
 // resourceVSphereComputeClusterVMGroupFetchObjects fetches the "objects" for a
@@ -451,6 +465,7 @@ func resourceVSphereComputeClusterVMGroupObjectsFromID(
 // ArrayUpdateSpec that have managed object as keys, such as VM and host
 // overrides.
 func resourceVSphereComputeClusterVMGroupFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	name string,
@@ -460,7 +475,7 @@ func resourceVSphereComputeClusterVMGroupFetchObjects(
 		return nil, "", err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, "", fmt.Errorf("cannot locate cluster: %s", err)
 	}
@@ -498,8 +513,7 @@ func diffVMGroup(oldVMs, newVMs []string) ([]string, []string) {
 }
 
 // getCurrentVMsInGroup retrieves the current VMs in the specified VM group from the vSphere cluster.
-func getCurrentVMsInGroup(cluster *object.ClusterComputeResource, groupName string) (*types.ClusterVmGroup, error) {
-	ctx := context.TODO()
+func getCurrentVMsInGroup(ctx context.Context, cluster *object.ClusterComputeResource, groupName string) (*types.ClusterVmGroup, error) {
 	groups, err := cluster.Configuration(ctx)
 	if err != nil {
 		return nil, err
@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -182,7 +183,7 @@ func testAccResourceVSphereComputeClusterVMGroupMatchMembershipVMIDs(s *terrafor
 		ids = testAccResourceVSphereComputeClusterVMGroupGetMultiple(s)
 	}
 
-	results, err := virtualmachine.MOIDsForUUIDs(testAccProvider.Meta().(*Client).vimClient, ids)
+	results, err := virtualmachine.MOIDsForUUIDs(context.Background(), testAccProvider.Meta().(*Client).vimClient, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -12,7 +12,9 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -26,13 +28,19 @@ const resourceVSphereComputeClusterVMHostRuleName = "vsphere_compute_cluster_vm_
 
 func resourceVSphereComputeClusterVMHostRule() *schema.Resource {
 	return &schema.Resource{
-		Create:        resourceVSphereComputeClusterVMHostRuleCreate,
-		Read:          resourceVSphereComputeClusterVMHostRuleRead,
-		Update:        resourceVSphereComputeClusterVMHostRuleUpdate,
-		Delete:        resourceVSphereComputeClusterVMHostRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMHostRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMHostRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMHostRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMHostRuleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		CustomizeDiff: resourceVSphereComputeClusterVMHostRuleCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMHostRuleImport,
+			StateContext: resourceVSphereComputeClusterVMHostRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -79,17 +87,17 @@ func resourceVSphereComputeClusterVMHostRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMHostRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
-	cluster, _, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
+	cluster, _, err := resourceVSphereComputeClusterVMHostRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterVMHostRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -102,36 +110,36 @@ func resourceVSphereComputeClusterVMHostRuleCreate(d *schema.ResourceData, meta
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	info, err = resourceVSphereComputeClusterVMHostRuleFindEntryByName(cluster, info.Name)
+	info, err = resourceVSphereComputeClusterVMHostRuleFindEntryByName(ctx, cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMHostRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
-	return resourceVSphereComputeClusterVMHostRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMHostRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMHostRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMHostRuleFindEntry(cluster, key)
+	info, err := resourceVSphereComputeClusterVMHostRuleFindEntry(ctx, cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -144,28 +152,28 @@ func resourceVSphereComputeClusterVMHostRuleRead(d *schema.ResourceData, meta in
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
 	if err = flattenClusterVMHostRuleInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMHostRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterVMHostRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -180,20 +188,20 @@ func resourceVSphereComputeClusterVMHostRuleUpdate(d *schema.ResourceData, meta
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
-	return resourceVSphereComputeClusterVMHostRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMHostRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMHostRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
-	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
+	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -207,8 +215,8 @@ func resourceVSphereComputeClusterVMHostRuleDelete(d *schema.ResourceData, meta
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
@@ -243,7 +251,7 @@ func resourceVSphereComputeClusterVMHostRuleValidateHostRulesSpecified(d *schema
 	return nil
 }
 
-func resourceVSphereComputeClusterVMHostRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMHostRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -262,12 +270,12 @@ func resourceVSphereComputeClusterVMHostRuleImport(d *schema.ResourceData, meta
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	info, err := resourceVSphereComputeClusterVMHostRuleFindEntryByName(cluster, name)
+	info, err := resourceVSphereComputeClusterVMHostRuleFindEntryByName(ctx, cluster, name)
 	if err != nil {
 		return nil, err
 	}
@@ -343,10 +351,11 @@ func resourceVSphereComputeClusterVMHostRuleParseID(id string) (string, int32, e
 // resource's read functionality and tests. nil is returned if the entry cannot
 // be found.
 func resourceVSphereComputeClusterVMHostRuleFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterVmHostRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -371,10 +380,11 @@ func resourceVSphereComputeClusterVMHostRuleFindEntry(
 // don't allow missing entries, as it's designed to be used in places where we
 // don't want to allow for missing entries, such as during creation and import.
 func resourceVSphereComputeClusterVMHostRuleFindEntryByName(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterVmHostRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -398,20 +408,22 @@ func resourceVSphereComputeClusterVMHostRuleFindEntryByName(
 // * If not, only the cluster is retrieved from compute_cluster_id. -1 is
 // returned for the key.
 func resourceVSphereComputeClusterVMHostRuleObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
 	if d.Id() != "" {
-		return resourceVSphereComputeClusterVMHostRuleObjectsFromID(d, meta)
+		return resourceVSphereComputeClusterVMHostRuleObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereComputeClusterVMHostRuleObjectsFromAttributes(d, meta)
+	return resourceVSphereComputeClusterVMHostRuleObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereComputeClusterVMHostRuleObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
-	return resourceVSphereComputeClusterVMHostRuleFetchObjects(
+	return resourceVSphereComputeClusterVMHostRuleFetchObjects(ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		-1,
@@ -419,6 +431,7 @@ func resourceVSphereComputeClusterVMHostRuleObjectsFromAttributes(
 }
 
 func resourceVSphereComputeClusterVMHostRuleObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, int32, error) {
@@ -430,7 +443,7 @@ func resourceVSphereComputeClusterVMHostRuleObjectsFromID(
 		return nil, 0, err
 	}
 
-	return resourceVSphereComputeClusterVMHostRuleFetchObjects(meta, clusterID, key)
+	return resourceVSphereComputeClusterVMHostRuleFetchObjects(ctx, meta, clusterID, key)
 }
 
 // resourceVSphereComputeClusterVMHostRuleFetchObjects fetches the "objects"
@@ -440,6 +453,7 @@ func resourceVSphereComputeClusterVMHostRuleObjectsFromID(
 // ArrayUpdateSpec that have managed object as keys, such as VM and host
 // overrides.
 func resourceVSphereComputeClusterVMHostRuleFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	key int32,
@@ -449,7 +463,7 @@ func resourceVSphereComputeClusterVMHostRuleFetchObjects(
 		return nil, 0, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("cannot locate cluster: %s", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -25,6 +26,12 @@ func resourceVSphereConfigurationProfile() *schema.Resource {
 		ReadContext:   resourceVSphereConfigurationProfileRead,
 		UpdateContext: resourceVSphereConfigurationProfileUpdate,
 		DeleteContext: resourceVSphereConfigurationProfileDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"reference_host_id": {
 				Type:          schema.TypeString,
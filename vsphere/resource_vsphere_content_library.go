@@ -5,20 +5,29 @@
 package vsphere
 
 import (
+	"context"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
 )
 
 func resourceVSphereContentLibrary() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereContentLibraryCreate,
-		Delete: resourceVSphereContentLibraryDelete,
-		Read:   resourceVSphereContentLibraryRead,
+		CreateContext: resourceVSphereContentLibraryCreate,
+		DeleteContext: resourceVSphereContentLibraryDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		ReadContext: resourceVSphereContentLibraryRead,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereContentLibraryImport,
+			StateContext: resourceVSphereContentLibraryImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -132,26 +141,26 @@ func resourceVSphereContentLibrary() *schema.Resource {
 	}
 }
 
-func resourceVSphereContentLibraryRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryRead : Beginning Content Library (%s) read", d.Id())
 	c := meta.(*Client).restClient
-	lib, err := contentlibrary.FromID(c, d.Id())
+	lib, err := contentlibrary.FromID(ctx, c, d.Id())
 	if err != nil {
 		if strings.Contains(err.Error(), "404 Not Found") {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(lib.ID)
 	if err = contentlibrary.FlattenPublication(d, lib.Publication); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = contentlibrary.FlattenSubscription(d, lib.Subscription); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = contentlibrary.FlattenStorageBackings(d, lib.Storage); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	_ = d.Set("name", lib.Name)
 	_ = d.Set("description", lib.Description)
@@ -159,41 +168,41 @@ func resourceVSphereContentLibraryRead(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func resourceVSphereContentLibraryCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryCreate : Beginning Content Library (%s) creation", d.Get("name").(string))
 	vimClient := meta.(*Client).vimClient
 	restClient := meta.(*Client).restClient
-	backings, err := contentlibrary.ExpandStorageBackings(vimClient, d)
+	backings, err := contentlibrary.ExpandStorageBackings(ctx, vimClient, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	id, err := contentlibrary.CreateLibrary(d, restClient, backings)
+	id, err := contentlibrary.CreateLibrary(ctx, d, restClient, backings)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(id)
 	log.Printf("[DEBUG] resourceVSphereContentLibraryCreate : Content Library (%s) creation is complete", d.Get("name").(string))
-	return resourceVSphereContentLibraryRead(d, meta)
+	return resourceVSphereContentLibraryRead(ctx, d, meta)
 }
 
-func resourceVSphereContentLibraryDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryDelete : Deleting Content Library (%s)", d.Id())
 	c := meta.(*Client).restClient
-	lib, err := contentlibrary.FromID(c, d.Id())
+	lib, err := contentlibrary.FromID(ctx, c, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] resourceVSphereContentLibraryDelete : Content Library (%s) deleted", d.Id())
-	return contentlibrary.DeleteLibrary(c, lib)
+	return diag.FromErr(contentlibrary.DeleteLibrary(ctx, c, lib))
 }
 
-func resourceVSphereContentLibraryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereContentLibraryImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).restClient
-	_, err := contentlibrary.FromID(client, d.Id())
+	_, err := contentlibrary.FromID(ctx, client, d.Id())
 	if err != nil {
 		return nil, err
 	}
-	err = resourceVSphereContentLibraryRead(d, meta)
+	err = provider.DiagnosticsError(resourceVSphereContentLibraryRead(ctx, d, meta))
 	if err != nil {
 		return nil, err
 	}
@@ -5,22 +5,31 @@
 package vsphere
 
 import (
+	"context"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
 )
 
 func resourceVSphereContentLibraryItem() *schema.Resource {
 	return &schema.Resource{
-		Create:        resourceVSphereContentLibraryItemCreate,
-		Delete:        resourceVSphereContentLibraryItemDelete,
-		Read:          resourceVSphereContentLibraryItemRead,
+		CreateContext: resourceVSphereContentLibraryItemCreate,
+		DeleteContext: resourceVSphereContentLibraryItemDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		ReadContext:   resourceVSphereContentLibraryItemRead,
 		SchemaVersion: 1,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereContentLibraryItemImport,
+			StateContext: resourceVSphereContentLibraryItemImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -62,20 +71,32 @@ func resourceVSphereContentLibraryItem() *schema.Resource {
 				Description:   "The managed object ID of an existing VM to be cloned to the content library.",
 				ConflictsWith: []string{"file_url"},
 			},
+			"allow_unverified_ssl_cert": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_ALLOW_UNVERIFIED_SSL", false),
+				Description: "Allow unverified SSL certificates while downloading a remote file_url.",
+				// The setting only applies to the download when the item is created,
+				// so changing it does not replace existing items.
+				DiffSuppressFunc: func(_, _, _ string, d *schema.ResourceData) bool {
+					return d.Id() != ""
+				},
+			},
 		},
 	}
 }
 
-func resourceVSphereContentLibraryItemRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryItemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemRead : Reading Content Library item (%s)", d.Id())
 	rc := meta.(*Client).restClient
-	item, err := contentlibrary.ItemFromID(rc, d.Id())
+	item, err := contentlibrary.ItemFromID(ctx, rc, d.Id())
 	if err != nil {
 		if strings.Contains(err.Error(), "404 Not Found") {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	_ = d.Set("name", item.Name)
 	_ = d.Set("description", item.Description)
@@ -85,46 +106,46 @@ func resourceVSphereContentLibraryItemRead(d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func resourceVSphereContentLibraryItemCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryItemCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemCreate : Beginning Content Library item (%s) creation", d.Get("name").(string))
 	rc := meta.(*Client).restClient
-	lib, err := contentlibrary.FromID(rc, d.Get("library_id").(string))
+	lib, err := contentlibrary.FromID(ctx, rc, d.Get("library_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	var moid virtualmachine.MOIDForUUIDResult
 	if uuid, ok := d.GetOk("source_uuid"); ok {
-		moid, err = virtualmachine.MOIDForUUID(meta.(*Client).vimClient, uuid.(string))
+		moid, err = virtualmachine.MOIDForUUID(ctx, meta.(*Client).vimClient, uuid.(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
-	id, err := contentlibrary.CreateLibraryItem(rc, lib, d.Get("name").(string), d.Get("description").(string), d.Get("type").(string), d.Get("file_url").(string), moid.MOID)
+	id, err := contentlibrary.CreateLibraryItem(ctx, rc, meta.(*Client).TLSOptions(), d.Get("allow_unverified_ssl_cert").(bool), lib, d.Get("name").(string), d.Get("description").(string), d.Get("type").(string), d.Get("file_url").(string), moid.MOID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(*id)
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemCreate : Content Library item (%s) creation complete", d.Get("name").(string))
-	return resourceVSphereContentLibraryItemRead(d, meta)
+	return resourceVSphereContentLibraryItemRead(ctx, d, meta)
 }
 
-func resourceVSphereContentLibraryItemDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryItemDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemDelete : Deleting Content Library item (%s)", d.Id())
 	rc := meta.(*Client).restClient
-	item, err := contentlibrary.ItemFromID(rc, d.Id())
+	item, err := contentlibrary.ItemFromID(ctx, rc, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return contentlibrary.DeleteLibraryItem(rc, item)
+	return diag.FromErr(contentlibrary.DeleteLibraryItem(ctx, rc, item))
 }
 
-func resourceVSphereContentLibraryItemImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereContentLibraryItemImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).restClient
-	_, err := contentlibrary.ItemFromID(client, d.Id())
+	_, err := contentlibrary.ItemFromID(ctx, client, d.Id())
 	if err != nil {
 		return nil, err
 	}
-	err = resourceVSphereContentLibraryItemRead(d, meta)
+	err = provider.DiagnosticsError(resourceVSphereContentLibraryItemRead(ctx, d, meta))
 	if err != nil {
 		return nil, err
 	}
@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -16,12 +18,18 @@ import (
 
 func resourceVSphereCustomAttribute() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereCustomAttributeCreate,
-		Read:   resourceVSphereCustomAttributeRead,
-		Update: resourceVSphereCustomAttributeUpdate,
-		Delete: resourceVSphereCustomAttributeDelete,
+		CreateContext: resourceVSphereCustomAttributeCreate,
+		ReadContext:   resourceVSphereCustomAttributeRead,
+		UpdateContext: resourceVSphereCustomAttributeUpdate,
+		DeleteContext: resourceVSphereCustomAttributeDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereCustomAttributeImport,
+			StateContext: resourceVSphereCustomAttributeImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -40,21 +48,21 @@ func resourceVSphereCustomAttribute() *schema.Resource {
 	}
 }
 
-func resourceVSphereCustomAttributeCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereCustomAttributeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := customattribute.VerifySupport(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	fm, err := object.GetCustomFieldsManager(client.Client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	field, err := fm.Add(ctx, d.Get("name").(string), d.Get("managed_object_type").(string), nil, nil)
 	if err != nil {
-		return fmt.Errorf("could not create custom attribute: %s", err)
+		return diag.Errorf("could not create custom attribute: %s", err)
 	}
 
 	d.SetId(fmt.Sprint(field.Key))
@@ -63,74 +71,74 @@ func resourceVSphereCustomAttributeCreate(d *schema.ResourceData, meta interface
 	return nil
 }
 
-func resourceVSphereCustomAttributeRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereCustomAttributeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := customattribute.VerifySupport(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	fm, err := object.GetCustomFieldsManager(client.Client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	key, err := strconv.ParseInt(d.Id(), 10, 32)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	fields, err := fm.Field(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	field := fields.ByKey(int32(key))
 	if field == nil {
-		return fmt.Errorf("could not locate category with id '%d'", key)
+		return diag.Errorf("could not locate category with id '%d'", key)
 	}
 	_ = d.Set("name", field.Name)
 	_ = d.Set("managed_object_type", field.ManagedObjectType)
 	return nil
 }
 
-func resourceVSphereCustomAttributeUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereCustomAttributeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := customattribute.VerifySupport(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	fm, err := object.GetCustomFieldsManager(client.Client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	key, err := strconv.ParseInt(d.Id(), 10, 32)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
-	return fm.Rename(ctx, int32(key), d.Get("name").(string))
+	return diag.FromErr(fm.Rename(ctx, int32(key), d.Get("name").(string)))
 }
 
-func resourceVSphereCustomAttributeDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereCustomAttributeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := customattribute.VerifySupport(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	fm, err := object.GetCustomFieldsManager(client.Client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	key, err := strconv.ParseInt(d.Id(), 10, 32)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
-	return fm.Remove(ctx, int32(key))
+	return diag.FromErr(fm.Remove(ctx, int32(key)))
 }
 
-func resourceVSphereCustomAttributeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereCustomAttributeImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).vimClient
 	if err := customattribute.VerifySupport(client); err != nil {
 		return nil, err
@@ -141,7 +149,7 @@ func resourceVSphereCustomAttributeImport(d *schema.ResourceData, meta interface
 		return nil, err
 	}
 
-	field, err := customattribute.ByName(fm, d.Id())
+	field, err := customattribute.ByName(ctx, fm, d.Id())
 	if err != nil {
 		return nil, err
 	}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/vmware/govmomi/find"
@@ -25,12 +26,18 @@ import (
 
 func resourceVSphereDatacenter() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDatacenterCreate,
-		Read:   resourceVSphereDatacenterRead,
-		Update: resourceVSphereDatacenterUpdate,
-		Delete: resourceVSphereDatacenterDelete,
+		CreateContext: resourceVSphereDatacenterCreate,
+		ReadContext:   resourceVSphereDatacenterRead,
+		UpdateContext: resourceVSphereDatacenterUpdate,
+		DeleteContext: resourceVSphereDatacenterDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDatacenterImport,
+			StateContext: resourceVSphereDatacenterImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -60,19 +67,19 @@ func resourceVSphereDatacenter() *schema.Resource {
 	}
 }
 
-func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatacenterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	name := d.Get("name").(string)
@@ -80,21 +87,21 @@ func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) e
 	var f *object.Folder
 	if v, ok := d.GetOk("folder"); ok {
 		finder := find.NewFinder(client.Client, true)
-		f, err = finder.Folder(context.TODO(), v.(string))
+		f, err = finder.Folder(ctx, v.(string))
 		if err != nil {
-			return fmt.Errorf("failed to find folder that will contain the datacenter: %s", err)
+			return diag.Errorf("failed to find folder that will contain the datacenter: %s", err)
 		}
 	} else {
 		f = object.NewRootFolder(client.Client)
 	}
 
-	dc, err := f.CreateDatacenter(context.TODO(), name)
+	dc, err := f.CreateDatacenter(ctx, name)
 	if err != nil {
-		return fmt.Errorf("failed to create datacenter: %s", err)
+		return diag.Errorf("failed to create datacenter: %s", err)
 	}
 	// From govmomi code: "Response will be nil if this is an ESX host that does not belong to a vCenter"
 	if dc == nil {
-		return fmt.Errorf("ESX host does not belong to a vCenter")
+		return diag.Errorf("ESX host does not belong to a vCenter")
 	}
 
 	d.SetId(name)
@@ -103,7 +110,7 @@ func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) e
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"InProgress"},
 		Target:     []string{"Created"},
-		Refresh:    resourceVSphereDatacenterStateRefreshFunc(d, meta),
+		Refresh:    resourceVSphereDatacenterStateRefreshFunc(ctx, d, meta),
 		Timeout:    10 * time.Minute,
 		MinTimeout: 3 * time.Second,
 		Delay:      5 * time.Second,
@@ -111,30 +118,30 @@ func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) e
 
 	_, err = stateConf.WaitForState()
 	if err != nil {
-		return fmt.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err)
+		return diag.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err)
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, dc); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, dc); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(dc); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, dc); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceVSphereDatacenterRead(d, meta)
+	return resourceVSphereDatacenterRead(ctx, d, meta)
 }
 
-func resourceVSphereDatacenterStateRefreshFunc(d *schema.ResourceData, meta interface{}) resource.StateRefreshFunc {
+func resourceVSphereDatacenterStateRefreshFunc(ctx context.Context, d *schema.ResourceData, meta interface{}) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		log.Print("[DEBUG] Refreshing datacenter state")
-		dc, err := datacenterExists(d, meta)
+		dc, err := datacenterExists(ctx, d, meta)
 		if err != nil {
 			var notFoundError *find.NotFoundError
 			switch {
@@ -150,7 +157,7 @@ func resourceVSphereDatacenterStateRefreshFunc(d *schema.ResourceData, meta inte
 	}
 }
 
-func datacenterExists(d *schema.ResourceData, meta interface{}) (*object.Datacenter, error) {
+func datacenterExists(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.Datacenter, error) {
 	client := meta.(*Client).vimClient
 	name := d.Id()
 
@@ -160,12 +167,12 @@ func datacenterExists(d *schema.ResourceData, meta interface{}) (*object.Datacen
 	}
 
 	finder := find.NewFinder(client.Client, true)
-	dc, err := finder.Datacenter(context.TODO(), path)
+	dc, err := finder.Datacenter(ctx, path)
 	return dc, err
 }
 
-func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) error {
-	dc, err := datacenterExists(d, meta)
+func resourceVSphereDatacenterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	dc, err := datacenterExists(ctx, d, meta)
 	if err != nil {
 		log.Printf("couldn't find the specified datacenter: %s", err)
 		d.SetId("")
@@ -174,24 +181,24 @@ func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) err
 
 	// TODO: In v2.0, the ID should be the MOID and this can be removed.
 	if err = d.Set("moid", dc.Reference().Value); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("name", dc.Name()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, dc, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, dc, d); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Read set custom attributes
 	client := meta.(*Client).vimClient
 	if customattribute.IsSupported(client) {
-		moDc, err := datacenterCustomAttributes(dc)
+		moDc, err := datacenterCustomAttributes(ctx, dc)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		customattribute.ReadFromResource(moDc.Entity(), d)
 	}
@@ -199,43 +206,43 @@ func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func resourceVSphereDatacenterUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatacenterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	client := meta.(*Client).vimClient
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	dc, err := datacenterExists(d, meta)
+	dc, err := datacenterExists(ctx, d, meta)
 	if err != nil {
-		return fmt.Errorf("couldn't find the specified datacenter: %s", err)
+		return diag.Errorf("couldn't find the specified datacenter: %s", err)
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, dc); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, dc); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(dc); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, dc); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatacenterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	name := d.Get("name").(string)
 
@@ -245,7 +252,7 @@ func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) e
 	}
 
 	finder := find.NewFinder(client.Client, true)
-	dc, err := finder.Datacenter(context.TODO(), path)
+	dc, err := finder.Datacenter(ctx, path)
 	if err != nil {
 		log.Printf("couldn't find the specified datacenter: %s", err)
 		d.SetId("")
@@ -256,16 +263,16 @@ func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) e
 		This: dc.Reference(),
 	}
 
-	_, err = methods.Destroy_Task(context.TODO(), client, req)
+	_, err = methods.Destroy_Task(ctx, client, req)
 	if err != nil {
-		return fmt.Errorf("%s", err)
+		return diag.Errorf("%s", err)
 	}
 
 	// Wait for the datacenter resource to be destroyed
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"Created"},
 		Target:     []string{},
-		Refresh:    resourceVSphereDatacenterStateRefreshFunc(d, meta),
+		Refresh:    resourceVSphereDatacenterStateRefreshFunc(ctx, d, meta),
 		Timeout:    10 * time.Minute,
 		MinTimeout: 3 * time.Second,
 		Delay:      5 * time.Second,
@@ -273,26 +280,26 @@ func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) e
 
 	_, err = stateConf.WaitForState()
 	if err != nil {
-		return fmt.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err)
+		return diag.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err)
 	}
 
 	return nil
 }
 
-func resourceVSphereDatacenterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDatacenterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).vimClient
 	p := d.Id()
 	if !strings.HasPrefix(p, "/") {
 		return nil, errors.New("path must start with a trailing slash")
 	}
 
-	dc, err := datacenter.FromPath(client, p)
+	dc, err := datacenter.FromPath(ctx, client, p)
 	if err != nil {
 		return nil, err
 	}
 
 	// determine a folder if one is present
-	f, err := folder.ParentFromPath(client, p, folder.VSphereFolderTypeDatacenter, dc)
+	f, err := folder.ParentFromPath(ctx, client, p, folder.VSphereFolderTypeDatacenter, dc)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate folder: %s", err)
 	}
@@ -5,9 +5,12 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/object"
@@ -34,12 +37,18 @@ var storageDrsSpaceLoadBalanceConfigSpaceThresholdModeAllowedValues = []string{
 
 func resourceVSphereDatastoreCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDatastoreClusterCreate,
-		Read:   resourceVSphereDatastoreClusterRead,
-		Update: resourceVSphereDatastoreClusterUpdate,
-		Delete: resourceVSphereDatastoreClusterDelete,
+		CreateContext: resourceVSphereDatastoreClusterCreate,
+		ReadContext:   resourceVSphereDatastoreClusterRead,
+		UpdateContext: resourceVSphereDatastoreClusterUpdate,
+		DeleteContext: resourceVSphereDatastoreClusterDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDatastoreClusterImport,
+			StateContext: resourceVSphereDatastoreClusterImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -195,120 +204,120 @@ func resourceVSphereDatastoreCluster() *schema.Resource {
 	}
 }
 
-func resourceVSphereDatastoreClusterCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDatastoreClusterIDString(d))
 
-	pod, err := resourceVSphereDatastoreClusterApplyCreate(d, meta)
+	pod, err := resourceVSphereDatastoreClusterApplyCreate(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplyTags(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplyTags(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplyCustomAttributes(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplyCustomAttributes(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplySDRSConfig(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplySDRSConfig(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDatastoreClusterIDString(d))
-	return resourceVSphereDatastoreClusterRead(d, meta)
+	return resourceVSphereDatastoreClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDatastoreClusterIDString(d))
-	pod, err := resourceVSphereDatastoreClusterGetPod(d, meta)
+	pod, err := resourceVSphereDatastoreClusterGetPod(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := resourceVSphereDatastoreClusterSaveNameAndPath(d, pod); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterFlattenSDRSData(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterFlattenSDRSData(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterReadTags(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterReadTags(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterReadCustomAttributes(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterReadCustomAttributes(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDatastoreClusterIDString(d))
 	return nil
 }
 
-func resourceVSphereDatastoreClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDatastoreClusterIDString(d))
-	pod, err := resourceVSphereDatastoreClusterGetPod(d, meta)
+	pod, err := resourceVSphereDatastoreClusterGetPod(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	pod, err = resourceVSphereDatastoreClusterApplyNameChange(d, meta, pod)
+	pod, err = resourceVSphereDatastoreClusterApplyNameChange(ctx, d, meta, pod)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	pod, err = resourceVSphereDatastoreClusterApplyFolderChange(d, meta, pod)
+	pod, err = resourceVSphereDatastoreClusterApplyFolderChange(ctx, d, meta, pod)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplySDRSConfig(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplySDRSConfig(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplyTags(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplyTags(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplyCustomAttributes(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplyCustomAttributes(ctx, d, meta, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDatastoreClusterIDString(d))
-	return resourceVSphereDatastoreClusterRead(d, meta)
+	return resourceVSphereDatastoreClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	resourceIDString := resourceVSphereDatastoreClusterIDString(d)
 	log.Printf("[DEBUG] %s: Beginning delete", resourceIDString)
-	pod, err := resourceVSphereDatastoreClusterGetPod(d, meta)
+	pod, err := resourceVSphereDatastoreClusterGetPod(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Very similar to how we handle folders, we don't delete a storage pod if
 	// there is child items in it. If there is, we fail with an error that
 	// mentions this restriction.
-	if err := resourceVSphereDatastoreClusterValidateEmptyCluster(d, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterValidateEmptyCluster(ctx, d, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplyDelete(d, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplyDelete(ctx, d, pod); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceIDString)
 	return nil
 }
 
-func resourceVSphereDatastoreClusterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDatastoreClusterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	p := d.Id()
-	pod, err := resourceVSphereDatastoreClusterGetPodFromPath(meta, p, "")
+	pod, err := resourceVSphereDatastoreClusterGetPodFromPath(ctx, meta, p, "")
 	if err != nil {
 		return nil, fmt.Errorf("error loading datastore cluster: %s", err)
 	}
 	client := meta.(*Client).vimClient
-	dc, err := datacenter.FromInventoryPath(client, pod.InventoryPath)
+	dc, err := datacenter.FromInventoryPath(ctx, client, pod.InventoryPath)
 	if err != nil {
 		return nil, fmt.Errorf("error getting datacenter of datastore cluster: %s", err)
 	}
@@ -320,27 +329,27 @@ func resourceVSphereDatastoreClusterImport(d *schema.ResourceData, meta interfac
 
 // resourceVSphereDatastoreClusterApplyCreate processes the creation part of
 // resourceVSphereDatastoreClusterCreate.
-func resourceVSphereDatastoreClusterApplyCreate(d *schema.ResourceData, meta interface{}) (*object.StoragePod, error) {
+func resourceVSphereDatastoreClusterApplyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.StoragePod, error) {
 	log.Printf("[DEBUG] %s: Processing datastore cluster creation", resourceVSphereDatastoreClusterIDString(d))
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
 		return nil, err
 	}
 
-	dc, err := datacenterFromID(client, d.Get("datacenter_id").(string))
+	dc, err := datacenterFromID(ctx, client, d.Get("datacenter_id").(string))
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate datacenter: %s", err)
 	}
 
 	// Find the folder based off the path to the datacenter. This is where we
 	// create the datastore cluster.
-	f, err := folder.FromPath(client, d.Get("folder").(string), folder.VSphereFolderTypeDatastore, dc)
+	f, err := folder.FromPath(ctx, client, d.Get("folder").(string), folder.VSphereFolderTypeDatastore, dc)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate folder: %s", err)
 	}
 
 	// Create the storage pod (datastore cluster).
-	pod, err := storagepod.Create(f, d.Get("name").(string))
+	pod, err := storagepod.Create(ctx, f, d.Get("name").(string))
 	if err != nil {
 		return nil, fmt.Errorf("error creating datastore cluster: %s", err)
 	}
@@ -355,7 +364,7 @@ func resourceVSphereDatastoreClusterApplyCreate(d *schema.ResourceData, meta int
 
 // resourceVSphereDatastoreClusterApplyTags processes the tags step for both
 // create and update for vsphere_datastore_cluster.
-func resourceVSphereDatastoreClusterApplyTags(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterApplyTags(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
 		return err
@@ -368,15 +377,15 @@ func resourceVSphereDatastoreClusterApplyTags(d *schema.ResourceData, meta inter
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereDatastoreClusterIDString(d))
-	return processTagDiff(tagsClient, d, pod)
+	return processTagDiff(ctx, tagsClient, d, pod)
 }
 
 // resourceVSphereDatastoreClusterReadTags reads the tags for
 // vsphere_datastore_cluster.
-func resourceVSphereDatastoreClusterReadTags(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereDatastoreClusterIDString(d))
-		if err := readTagsForResource(tagsClient, pod, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, pod, d); err != nil {
 			return err
 		}
 	} else {
@@ -387,7 +396,7 @@ func resourceVSphereDatastoreClusterReadTags(d *schema.ResourceData, meta interf
 
 // resourceVSphereDatastoreClusterApplyCustomAttributes processes the custom
 // attributes step for both create and update for vsphere_datastore_cluster.
-func resourceVSphereDatastoreClusterApplyCustomAttributes(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterApplyCustomAttributes(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	client := meta.(*Client).vimClient
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
@@ -401,17 +410,17 @@ func resourceVSphereDatastoreClusterApplyCustomAttributes(d *schema.ResourceData
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending custom attributes", resourceVSphereDatastoreClusterIDString(d))
-	return attrsProcessor.ProcessDiff(pod)
+	return attrsProcessor.ProcessDiff(ctx, pod)
 }
 
 // resourceVSphereDatastoreClusterReadCustomAttributes reads the custom
 // attributes for vsphere_datastore_cluster.
-func resourceVSphereDatastoreClusterReadCustomAttributes(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterReadCustomAttributes(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	client := meta.(*Client).vimClient
 	// Read custom attributes
 	if customattribute.IsSupported(client) {
 		log.Printf("[DEBUG] %s: Reading custom attributes", resourceVSphereDatastoreClusterIDString(d))
-		props, err := storagepod.Properties(pod)
+		props, err := storagepod.Properties(ctx, pod)
 		if err != nil {
 			return err
 		}
@@ -425,7 +434,7 @@ func resourceVSphereDatastoreClusterReadCustomAttributes(d *schema.ResourceData,
 
 // resourceVSphereDatastoreClusterApplySDRSConfig applies the SDRS
 // configuration to a datastore cluster.
-func resourceVSphereDatastoreClusterApplySDRSConfig(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterApplySDRSConfig(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	// This is a no-op if there is no SDRS config changed
 	if !resourceVSphereDatastoreClusterHasSDRSConfigChange(d) {
 		log.Printf("[DEBUG] %s: No SDRS configuration attributes have changed", resourceVSphereDatastoreClusterIDString(d))
@@ -447,7 +456,7 @@ func resourceVSphereDatastoreClusterApplySDRSConfig(d *schema.ResourceData, meta
 		PodConfigSpec: expandStorageDrsPodConfigSpec(d, version),
 	}
 
-	return storagepod.ApplyDRSConfiguration(client, pod, spec)
+	return storagepod.ApplyDRSConfiguration(ctx, client, pod, spec)
 }
 
 // resourceVSphereDatastoreClusterHasSDRSConfigChange checks all resource keys
@@ -490,24 +499,24 @@ func resourceVSphereDatastoreClusterHasSDRSConfigChangeExcluded(k string) bool {
 
 // resourceVSphereDatastoreClusterGetPod gets the StoragePod from the ID
 // in the supplied ResourceData.
-func resourceVSphereDatastoreClusterGetPod(d structure.ResourceIDStringer, meta interface{}) (*object.StoragePod, error) {
+func resourceVSphereDatastoreClusterGetPod(ctx context.Context, d structure.ResourceIDStringer, meta interface{}) (*object.StoragePod, error) {
 	log.Printf("[DEBUG] %s: Fetching StoragePod object from resource ID", resourceVSphereDatastoreClusterIDString(d))
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
 		return nil, err
 	}
 
-	return storagepod.FromID(client, d.Id())
+	return storagepod.FromID(ctx, client, d.Id())
 }
 
 // resourceVSphereDatastoreClusterGetPodFromPath gets the StoragePod from a
 // supplied path. If no datacenter is supplied, the path must be a full path.
-func resourceVSphereDatastoreClusterGetPodFromPath(meta interface{}, path string, dcID string) (*object.StoragePod, error) {
+func resourceVSphereDatastoreClusterGetPodFromPath(ctx context.Context, meta interface{}, path string, dcID string) (*object.StoragePod, error) {
 	client := meta.(*Client).vimClient
 	var dc *object.Datacenter
 	if dcID != "" {
 		var err error
-		dc, err = datacenterFromID(client, dcID)
+		dc, err = datacenterFromID(ctx, client, dcID)
 		if err != nil {
 			return nil, fmt.Errorf("cannot locate datacenter: %s", err)
 		}
@@ -519,7 +528,7 @@ func resourceVSphereDatastoreClusterGetPodFromPath(meta interface{}, path string
 		return nil, err
 	}
 
-	return storagepod.FromPath(client, path, dc)
+	return storagepod.FromPath(ctx, client, path, dc)
 }
 
 // resourceVSphereDatastoreClusterSaveNameAndPath saves the name and path of a
@@ -548,6 +557,7 @@ func resourceVSphereDatastoreClusterSaveNameAndPath(d *schema.ResourceData, pod
 // resourceVSphereDatastoreClusterApplyNameChange applies any changes to a
 // StoragePod's name.
 func resourceVSphereDatastoreClusterApplyNameChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	pod *object.StoragePod,
@@ -562,7 +572,7 @@ func resourceVSphereDatastoreClusterApplyNameChange(
 	var err error
 
 	if d.HasChange("name") {
-		if err = storagepod.Rename(pod, d.Get("name").(string)); err != nil {
+		if err = storagepod.Rename(ctx, pod, d.Get("name").(string)); err != nil {
 			return nil, fmt.Errorf("error renaming datastore cluster: %s", err)
 		}
 		changed = true
@@ -571,7 +581,7 @@ func resourceVSphereDatastoreClusterApplyNameChange(
 	if changed {
 		// Update the pod so that we have the new inventory path for logging and
 		// other things
-		pod, err = resourceVSphereDatastoreClusterGetPod(d, meta)
+		pod, err = resourceVSphereDatastoreClusterGetPod(ctx, d, meta)
 		if err != nil {
 			return nil, fmt.Errorf("error refreshing datastore cluster after name change: %s", err)
 		}
@@ -588,6 +598,7 @@ func resourceVSphereDatastoreClusterApplyNameChange(
 // resourceVSphereDatastoreClusterApplyFolderChange applies any changes to a
 // StoragePod's folder location.
 func resourceVSphereDatastoreClusterApplyFolderChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	pod *object.StoragePod,
@@ -604,7 +615,7 @@ func resourceVSphereDatastoreClusterApplyFolderChange(
 	if d.HasChange("folder") {
 		f := d.Get("folder").(string)
 		client := meta.(*Client).vimClient
-		if err = storagepod.MoveToFolder(client, pod, f); err != nil {
+		if err = storagepod.MoveToFolder(ctx, client, pod, f); err != nil {
 			return nil, fmt.Errorf("could not move datastore cluster to folder %q: %s", f, err)
 		}
 		changed = true
@@ -613,7 +624,7 @@ func resourceVSphereDatastoreClusterApplyFolderChange(
 	if changed {
 		// Update the pod so that we have the new inventory path for logging and
 		// other things
-		pod, err = resourceVSphereDatastoreClusterGetPod(d, meta)
+		pod, err = resourceVSphereDatastoreClusterGetPod(ctx, d, meta)
 		if err != nil {
 			return nil, fmt.Errorf("error refreshing pod after folder change: %s", err)
 		}
@@ -631,9 +642,9 @@ func resourceVSphereDatastoreClusterApplyFolderChange(
 // datastore cluster is empty. This is used to ensure a safe deletion of the
 // cluster - we do not allow deletion of clusters that still have datastores in
 // them.
-func resourceVSphereDatastoreClusterValidateEmptyCluster(d structure.ResourceIDStringer, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterValidateEmptyCluster(ctx context.Context, d structure.ResourceIDStringer, pod *object.StoragePod) error {
 	log.Printf("[DEBUG] %s: Checking to ensure that datastore cluster is empty", resourceVSphereDatastoreClusterIDString(d))
-	ne, err := storagepod.HasChildren(pod)
+	ne, err := storagepod.HasChildren(ctx, pod)
 	if err != nil {
 		return fmt.Errorf("error checking for datastore cluster contents: %s", err)
 	}
@@ -648,9 +659,9 @@ func resourceVSphereDatastoreClusterValidateEmptyCluster(d structure.ResourceIDS
 
 // resourceVSphereDatastoreClusterApplyDelete process the removal of a
 // datastore cluster.
-func resourceVSphereDatastoreClusterApplyDelete(d *schema.ResourceData, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterApplyDelete(ctx context.Context, d *schema.ResourceData, pod *object.StoragePod) error {
 	log.Printf("[DEBUG] %s: Proceeding with datastore cluster deletion", resourceVSphereDatastoreClusterIDString(d))
-	if err := storagepod.Delete(pod); err != nil {
+	if err := storagepod.Delete(ctx, pod); err != nil {
 		return err
 	}
 	d.SetId("")
@@ -662,7 +673,7 @@ func resourceVSphereDatastoreClusterApplyDelete(d *schema.ResourceData, pod *obj
 //
 // Note that other functions handle non-SDRS related items, such as path, name,
 // tags, and custom attributes.
-func resourceVSphereDatastoreClusterFlattenSDRSData(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterFlattenSDRSData(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	log.Printf("[DEBUG] %s: Saving datastore cluster attributes", resourceVSphereDatastoreClusterIDString(d))
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
@@ -673,7 +684,7 @@ func resourceVSphereDatastoreClusterFlattenSDRSData(d *schema.ResourceData, meta
 	// attributes we need to set
 	version := viapi.ParseVersionFromClient(client)
 
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(ctx, pod)
 	if err != nil {
 		return err
 	}
@@ -5,13 +5,16 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
@@ -25,12 +28,18 @@ const resourceVSphereDatastoreClusterVMAntiAffinityRuleName = "vsphere_datastore
 
 func resourceVSphereDatastoreClusterVMAntiAffinityRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate,
-		Read:   resourceVSphereDatastoreClusterVMAntiAffinityRuleRead,
-		Update: resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate,
-		Delete: resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete,
+		CreateContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate,
+		ReadContext:   resourceVSphereDatastoreClusterVMAntiAffinityRuleRead,
+		UpdateContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate,
+		DeleteContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDatastoreClusterVMAntiAffinityRuleImport,
+			StateContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -66,21 +75,21 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleValidateRuleVMCount(d); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
-	pod, _, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
+	pod, _, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
+	info, err := expandClusterAntiAffinityRuleSpec(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := types.StorageDrsConfigSpec{
 		PodConfigSpec: &types.StorageDrsPodConfigSpec{
@@ -95,36 +104,36 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate(d *schema.ResourceD
 		},
 	}
 
-	if err = resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(pod, spec); err != nil {
-		return err
+	if err = resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(ctx, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	info, err = resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(pod, info.Name)
+	info, err = resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(ctx, pod, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleFlattenID(pod, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
-	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
+	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(pod, key)
+	info, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(ctx, pod, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -137,32 +146,32 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d *schema.ResourceDat
 	// completeness on import so that if the wrong pod/VM combo was used, it
 	// will be noted.
 	if err = d.Set("datastore_cluster_id", pod.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"datastore_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"datastore_cluster_id\": %s", err)
 	}
 
-	if err = flattenClusterAntiAffinityRuleSpec(d, meta, info); err != nil {
-		return err
+	if err = flattenClusterAntiAffinityRuleSpec(ctx, d, meta, info); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleValidateRuleVMCount(d); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
-	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
+	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
+	info, err := expandClusterAntiAffinityRuleSpec(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -179,20 +188,20 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate(d *schema.ResourceD
 		},
 	}
 
-	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(pod, spec); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(ctx, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
-	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
+	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := types.StorageDrsConfigSpec{
@@ -208,15 +217,15 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete(d *schema.ResourceD
 		},
 	}
 
-	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(pod, spec); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(ctx, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -235,12 +244,12 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleImport(d *schema.ResourceD
 		return nil, err
 	}
 
-	pod, err := storagepod.FromPath(client, podPath, nil)
+	pod, err := storagepod.FromPath(ctx, client, podPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate datastore cluster %q: %s", podPath, err)
 	}
 
-	info, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(pod, name)
+	info, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(ctx, pod, name)
 	if err != nil {
 		return nil, err
 	}
@@ -286,10 +295,11 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleParseID(id string) (string
 // configuration by key. It's used by the resource's read functionality and
 // tests. nil is returned if the entry cannot be found.
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(
+	ctx context.Context,
 	pod *object.StoragePod,
 	key int32,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(ctx, pod)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching datastore cluster properties: %s", err)
 	}
@@ -315,10 +325,11 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(
 // allow missing entries, as it's designed to be used in places where we don't
 // want to allow for missing entries, such as during creation and import.
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(
+	ctx context.Context,
 	pod *object.StoragePod,
 	name string,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(ctx, pod)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching datastore cluster properties: %s", err)
 	}
@@ -343,20 +354,22 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(
 // * If not, only the cluster is retrieved from datastore_cluster_id. -1 is
 // returned for the key.
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.StoragePod, int32, error) {
 	if d.Id() != "" {
-		return resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromID(d, meta)
+		return resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromAttributes(d, meta)
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.StoragePod, int32, error) {
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleFetchObjects(
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleFetchObjects(ctx,
 		meta,
 		d.Get("datastore_cluster_id").(string),
 		-1,
@@ -364,6 +377,7 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromAttributes(
 }
 
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.StoragePod, int32, error) {
@@ -375,7 +389,7 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromID(
 		return nil, 0, err
 	}
 
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleFetchObjects(meta, podID, key)
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleFetchObjects(ctx, meta, podID, key)
 }
 
 // resourceVSphereDatastoreClusterVMAntiAffinityRuleFetchObjects fetches the
@@ -385,6 +399,7 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleObjectsFromID(
 // ArrayUpdateSpec that have managed object as keys, such as VM and host
 // overrides.
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	podID string,
 	key int32,
@@ -394,7 +409,7 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleFetchObjects(
 		return nil, 0, err
 	}
 
-	pod, err := storagepod.FromID(client, podID)
+	pod, err := storagepod.FromID(ctx, client, podID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("cannot locate datastore cluster: %s", err)
 	}
@@ -422,10 +437,11 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleClient(meta interface{}) (
 // is mainly just vim25.Client with some additional session helper bits that is
 // not normally needed during normal operation.
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(
+	ctx context.Context,
 	pod *object.StoragePod,
 	spec types.StorageDrsConfigSpec,
 ) error {
-	return storagepod.ApplyDRSConfiguration(
+	return storagepod.ApplyDRSConfiguration(ctx,
 		&govmomi.Client{
 			Client: pod.Client(),
 		},
@@ -7,7 +7,9 @@ package vsphere
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
@@ -39,56 +41,62 @@ func resourceVSphereDistributedPortGroup() *schema.Resource {
 	structure.MergeSchema(s, schemaDVPortgroupConfigSpec())
 
 	return &schema.Resource{
-		Create: resourceVSphereDistributedPortGroupCreate,
-		Read:   resourceVSphereDistributedPortGroupRead,
-		Update: resourceVSphereDistributedPortGroupUpdate,
-		Delete: resourceVSphereDistributedPortGroupDelete,
+		CreateContext: resourceVSphereDistributedPortGroupCreate,
+		ReadContext:   resourceVSphereDistributedPortGroupRead,
+		UpdateContext: resourceVSphereDistributedPortGroupUpdate,
+		DeleteContext: resourceVSphereDistributedPortGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDistributedPortGroupImport,
+			StateContext: resourceVSphereDistributedPortGroupImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereDistributedPortGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	dvsID := d.Get("distributed_virtual_switch_uuid").(string)
-	dvs, err := dvsFromUUID(client, dvsID)
+	dvs, err := dvsFromUUID(ctx, client, dvsID)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", dvsID, err)
+		return diag.Errorf("could not find DVS %q: %s", dvsID, err)
 	}
 
 	spec := expandDVPortgroupConfigSpec(d)
-	task, err := dvportgroup.Create(client, dvs, spec)
+	task, err := dvportgroup.Create(ctx, client, dvs, spec)
 	if err != nil {
-		return fmt.Errorf("error creating portgroup: %s", err)
+		return diag.Errorf("error creating portgroup: %s", err)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	info, err := task.WaitForResultEx(tctx, nil)
+	info, err := viapi.WaitForTaskResult(tctx, task)
 	if err != nil {
-		return fmt.Errorf("error waiting for portgroup creation to complete: %s", err)
+		return diag.Errorf("error waiting for portgroup creation to complete: %s", err)
 	}
-	pg, err := dvportgroup.FromMOID(client, info.Result.(types.ManagedObjectReference).Value)
+	pg, err := dvportgroup.FromMOID(ctx, client, info.Result.(types.ManagedObjectReference).Value)
 	if err != nil {
-		return fmt.Errorf("error fetching portgroup after creation: %s", err)
+		return diag.Errorf("error fetching portgroup after creation: %s", err)
 	}
-	props, err := dvportgroup.Properties(pg)
+	props, err := dvportgroup.Properties(ctx, pg)
 	if err != nil {
-		return fmt.Errorf("error fetching portgroup properties after creation: %s", err)
+		return diag.Errorf("error fetching portgroup properties after creation: %s", err)
 	}
 
 	d.SetId(pg.Reference().Value)
@@ -96,45 +104,45 @@ func resourceVSphereDistributedPortGroupCreate(d *schema.ResourceData, meta inte
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, pg.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, object.NewReference(client.Client, pg.Reference())); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(object.NewReference(client.Client, pg.Reference())); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, object.NewReference(client.Client, pg.Reference())); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceVSphereDistributedPortGroupRead(d, meta)
+	return resourceVSphereDistributedPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedPortGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	pgID := d.Id()
-	pg, err := dvportgroup.FromMOID(client, pgID)
+	pg, err := dvportgroup.FromMOID(ctx, client, pgID)
 	if err != nil {
-		return fmt.Errorf("could not find portgroup %q: %s", pgID, err)
+		return diag.Errorf("could not find portgroup %q: %s", pgID, err)
 	}
-	props, err := dvportgroup.Properties(pg)
+	props, err := dvportgroup.Properties(ctx, pg)
 	if err != nil {
-		return fmt.Errorf("error fetching portgroup properties: %s", err)
+		return diag.Errorf("error fetching portgroup properties: %s", err)
 	}
 
 	_ = d.Set("key", props.Key)
 
 	if err := flattenDVPortgroupConfigInfo(d, props.Config); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, pg, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, pg, d); err != nil {
+			return diag.Errorf("error reading tags: %s", err)
 		}
 	}
 
@@ -145,82 +153,82 @@ func resourceVSphereDistributedPortGroupRead(d *schema.ResourceData, meta interf
 	return nil
 }
 
-func resourceVSphereDistributedPortGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	pgID := d.Id()
-	pg, err := dvportgroup.FromMOID(client, pgID)
+	pg, err := dvportgroup.FromMOID(ctx, client, pgID)
 	if err != nil {
-		return fmt.Errorf("could not find portgroup %q: %s", pgID, err)
+		return diag.Errorf("could not find portgroup %q: %s", pgID, err)
 	}
 	spec := expandDVPortgroupConfigSpec(d)
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := pg.Reconfigure(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("error reconfiguring portgroup: %s", err)
+		return diag.Errorf("error reconfiguring portgroup: %s", err)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	if err := task.WaitEx(tctx); err != nil {
-		return fmt.Errorf("error waiting for portgroup update to complete: %s", err)
+	if err := viapi.WaitForTask(tctx, task); err != nil {
+		return diag.Errorf("error waiting for portgroup update to complete: %s", err)
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, pg.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, object.NewReference(client.Client, pg.Reference())); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	// Update custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(object.NewReference(client.Client, pg.Reference())); err != nil {
-			return fmt.Errorf("error updating custom attributes: %s", err)
+		if err := attrsProcessor.ProcessDiff(ctx, object.NewReference(client.Client, pg.Reference())); err != nil {
+			return diag.Errorf("error updating custom attributes: %s", err)
 		}
 	}
 
-	return resourceVSphereDistributedPortGroupRead(d, meta)
+	return resourceVSphereDistributedPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedPortGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	pgID := d.Id()
-	pg, err := dvportgroup.FromMOID(client, pgID)
+	pg, err := dvportgroup.FromMOID(ctx, client, pgID)
 	if err != nil {
-		return fmt.Errorf("could not find portgroup %q: %s", pgID, err)
+		return diag.Errorf("could not find portgroup %q: %s", pgID, err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := pg.Destroy(ctx)
 	if err != nil {
-		return fmt.Errorf("error deleting portgroup: %s", err)
+		return diag.Errorf("error deleting portgroup: %s", err)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	if err := task.WaitEx(tctx); err != nil {
-		return fmt.Errorf("error waiting for portgroup deletion to complete: %s", err)
+	if err := viapi.WaitForTask(tctx, task); err != nil {
+		return diag.Errorf("error waiting for portgroup deletion to complete: %s", err)
 	}
 	return nil
 }
 
-func resourceVSphereDistributedPortGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDistributedPortGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// We use the inventory path to the portgroup to import. There is not
 	// checking to make sure that it belongs to the configured DVS, but on
 	// subsequent plans, if it is not, the resource will be in an unusable state
@@ -231,11 +239,11 @@ func resourceVSphereDistributedPortGroupImport(d *schema.ResourceData, meta inte
 		return nil, err
 	}
 	moID := d.Id()
-	pg, err := dvportgroup.FromPath(client, moID, nil)
+	pg, err := dvportgroup.FromPath(ctx, client, moID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error locating portgroup: %s", err)
 	}
-	props, err := dvportgroup.Properties(pg)
+	props, err := dvportgroup.Properties(ctx, pg)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching portgroup properties: %s", err)
 	}
@@ -244,11 +252,11 @@ func resourceVSphereDistributedPortGroupImport(d *schema.ResourceData, meta inte
 	// We need to populate the DVS UUID here as well or else our read calls will
 	// fail.
 	dvsID := props.Config.DistributedVirtualSwitch.Value
-	dvs, err := dvsFromMOID(client, dvsID)
+	dvs, err := dvsFromMOID(ctx, client, dvsID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting DVS with ID %q: %s", dvsID, err)
 	}
-	dvProps, err := dvsProperties(dvs)
+	dvProps, err := dvsProperties(ctx, dvs)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching DVS properties: %s", err)
 	}
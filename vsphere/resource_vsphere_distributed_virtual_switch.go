@@ -7,7 +7,9 @@ package vsphere
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
@@ -43,134 +45,140 @@ func resourceVSphereDistributedVirtualSwitch() *schema.Resource {
 	structure.MergeSchema(s, schemaDVSCreateSpec())
 
 	return &schema.Resource{
-		Create: resourceVSphereDistributedVirtualSwitchCreate,
-		Read:   resourceVSphereDistributedVirtualSwitchRead,
-		Update: resourceVSphereDistributedVirtualSwitchUpdate,
-		Delete: resourceVSphereDistributedVirtualSwitchDelete,
+		CreateContext: resourceVSphereDistributedVirtualSwitchCreate,
+		ReadContext:   resourceVSphereDistributedVirtualSwitchRead,
+		UpdateContext: resourceVSphereDistributedVirtualSwitchUpdate,
+		DeleteContext: resourceVSphereDistributedVirtualSwitchDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDistributedVirtualSwitchImport,
+			StateContext: resourceVSphereDistributedVirtualSwitchImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereDistributedVirtualSwitchCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	dc, err := datacenterFromID(client, d.Get("datacenter_id").(string))
+	dc, err := datacenterFromID(ctx, client, d.Get("datacenter_id").(string))
 	if err != nil {
-		return fmt.Errorf("cannot locate datacenter: %s", err)
+		return diag.Errorf("cannot locate datacenter: %s", err)
 	}
-	fo, err := folder.FromPath(client, d.Get("folder").(string), folder.VSphereFolderTypeNetwork, dc)
+	fo, err := folder.FromPath(ctx, client, d.Get("folder").(string), folder.VSphereFolderTypeNetwork, dc)
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return diag.Errorf("cannot locate folder: %s", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	spec := expandDVSCreateSpec(d)
 	task, err := fo.CreateDVS(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("error creating DVS: %s", err)
+		return diag.Errorf("error creating DVS: %s", err)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	info, err := task.WaitForResultEx(tctx, nil)
+	info, err := viapi.WaitForTaskResult(tctx, task)
 	if err != nil {
-		return fmt.Errorf("error waiting for DVS creation to complete: %s", err)
+		return diag.Errorf("error waiting for DVS creation to complete: %s", err)
 	}
 
-	dvs, err := dvsFromMOID(client, info.Result.(types.ManagedObjectReference).Value)
+	dvs, err := dvsFromMOID(ctx, client, info.Result.(types.ManagedObjectReference).Value)
 	if err != nil {
-		return fmt.Errorf("error fetching DVS after creation: %s", err)
+		return diag.Errorf("error fetching DVS after creation: %s", err)
 	}
-	props, err := dvsProperties(dvs)
+	props, err := dvsProperties(ctx, dvs)
 	if err != nil {
-		return fmt.Errorf("error fetching DVS properties after creation: %s", err)
+		return diag.Errorf("error fetching DVS properties after creation: %s", err)
 	}
 
 	d.SetId(props.Uuid)
 
 	// Enable network resource I/O control if it needs to be enabled
 	if d.Get("network_resource_control_enabled").(bool) {
-		err = enableDVSNetworkResourceManagement(client, dvs, true)
+		err = enableDVSNetworkResourceManagement(ctx, client, dvs, true)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, dvs.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, object.NewReference(client.Client, dvs.Reference())); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(dvs); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, dvs); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceVSphereDistributedVirtualSwitchRead(d, meta)
+	return resourceVSphereDistributedVirtualSwitchRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := d.Id()
-	dvs, err := dvsFromUUID(client, id)
+	dvs, err := dvsFromUUID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", id, err)
+		return diag.Errorf("could not find DVS %q: %s", id, err)
 	}
-	props, err := dvsProperties(dvs)
+	props, err := dvsProperties(ctx, dvs)
 	if err != nil {
-		return fmt.Errorf("error fetching DVS properties: %s", err)
+		return diag.Errorf("error fetching DVS properties: %s", err)
 	}
 
 	// Set the datacenter ID, for completion's sake when importing
 	dcp, err := folder.RootPathParticleNetwork.SplitDatacenter(dvs.InventoryPath)
 	if err != nil {
-		return fmt.Errorf("error parsing datacenter from inventory path: %s", err)
+		return diag.Errorf("error parsing datacenter from inventory path: %s", err)
 	}
-	dc, err := getDatacenter(client, dcp)
+	dc, err := getDatacenter(ctx, client, dcp)
 	if err != nil {
-		return fmt.Errorf("error locating datacenter: %s", err)
+		return diag.Errorf("error locating datacenter: %s", err)
 	}
 	_ = d.Set("datacenter_id", dc.Reference().Value)
 
 	// Set the folder
 	f, err := folder.RootPathParticleNetwork.SplitRelativeFolder(dvs.InventoryPath)
 	if err != nil {
-		return fmt.Errorf("error parsing DVS path %q: %s", dvs.InventoryPath, err)
+		return diag.Errorf("error parsing DVS path %q: %s", dvs.InventoryPath, err)
 	}
 	_ = d.Set("folder", folder.NormalizePath(f))
 
 	// Read in config info
 	if err := flattenVMwareDVSConfigInfo(d, props.Config.(*types.VMwareDVSConfigInfo)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, dvs, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, dvs, d); err != nil {
+			return diag.Errorf("error reading tags: %s", err)
 		}
 	}
 
@@ -182,27 +190,27 @@ func resourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta in
 	return nil
 }
 
-func resourceVSphereDistributedVirtualSwitchUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	vsphereDistributedVirtualSwitchModificationMutex.Lock()
 
 	id := d.Id()
-	dvs, err := dvsFromUUID(client, id)
+	dvs, err := dvsFromUUID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", id, err)
+		return diag.Errorf("could not find DVS %q: %s", id, err)
 	}
 
 	// If we have a pending version upgrade, do that first.
@@ -218,14 +226,14 @@ func resourceVSphereDistributedVirtualSwitchUpdate(d *schema.ResourceData, meta
 			}
 		}
 		if nvi < ovi {
-			return fmt.Errorf("downgrading dvSwitches are not allowed (old: %s new: %s)", old, newValue)
+			return diag.Errorf("downgrading dvSwitches are not allowed (old: %s new: %s)", old, newValue)
 		}
-		if err := upgradeDVS(client, dvs, newValue.(string)); err != nil {
-			return fmt.Errorf("could not upgrade DVS: %s", err)
+		if err := upgradeDVS(ctx, client, dvs, newValue.(string)); err != nil {
+			return diag.Errorf("could not upgrade DVS: %s", err)
 		}
-		props, err := dvsProperties(dvs)
+		props, err := dvsProperties(ctx, dvs)
 		if err != nil {
-			return fmt.Errorf("could not get DVS properties after upgrade: %s", err)
+			return diag.Errorf("could not get DVS properties after upgrade: %s", err)
 		}
 		// ConfigVersion increments after a DVS upgrade, which means this needs to
 		// be updated before the post-update read to ensure that we don't run into
@@ -234,64 +242,64 @@ func resourceVSphereDistributedVirtualSwitchUpdate(d *schema.ResourceData, meta
 	}
 
 	spec := expandVMwareDVSConfigSpec(d)
-	if err := updateDVSConfiguration(dvs, spec); err != nil {
-		return fmt.Errorf("could not update DVS: %s", err)
+	if err := updateDVSConfiguration(ctx, dvs, spec); err != nil {
+		return diag.Errorf("could not update DVS: %s", err)
 	}
 
 	// Modify network I/O control if necessary
 	if d.HasChange("network_resource_control_enabled") {
-		err = enableDVSNetworkResourceManagement(client, dvs, d.Get("network_resource_control_enabled").(bool))
+		err = enableDVSNetworkResourceManagement(ctx, client, dvs, d.Get("network_resource_control_enabled").(bool))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, dvs.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, object.NewReference(client.Client, dvs.Reference())); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	// Apply custom attribute updates
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(dvs); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, dvs); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	vsphereDistributedVirtualSwitchModificationMutex.Unlock()
 
-	return resourceVSphereDistributedVirtualSwitchRead(d, meta)
+	return resourceVSphereDistributedVirtualSwitchRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedVirtualSwitchDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	id := d.Id()
-	dvs, err := dvsFromUUID(client, id)
+	dvs, err := dvsFromUUID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", id, err)
+		return diag.Errorf("could not find DVS %q: %s", id, err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := dvs.Destroy(ctx)
 	if err != nil {
-		return fmt.Errorf("error deleting DVS: %s", err)
+		return diag.Errorf("error deleting DVS: %s", err)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	if err := task.WaitEx(tctx); err != nil {
-		return fmt.Errorf("error waiting for DVS deletion to complete: %s", err)
+	if err := viapi.WaitForTask(tctx, task); err != nil {
+		return diag.Errorf("error waiting for DVS deletion to complete: %s", err)
 	}
 
 	return nil
 }
 
-func resourceVSphereDistributedVirtualSwitchImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDistributedVirtualSwitchImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// Due to the relative difficulty in trying to fetch a DVS's UUID, we use the
 	// inventory path to the DVS instead, and just run it through finder. A full
 	// path is required unless the default datacenter can be utilized.
@@ -300,11 +308,11 @@ func resourceVSphereDistributedVirtualSwitchImport(d *schema.ResourceData, meta
 		return nil, err
 	}
 	p := d.Id()
-	dvs, err := dvsFromPath(client, p, nil)
+	dvs, err := dvsFromPath(ctx, client, p, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error locating DVS: %s", err)
 	}
-	props, err := dvsProperties(dvs)
+	props, err := dvsProperties(ctx, dvs)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching DVS properties: %s", err)
 	}
@@ -5,9 +5,12 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/vim25/types"
@@ -48,14 +51,19 @@ func resourceVSphereDistributedVirtualSwitchPvlanMapping() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourceVSphereDistributedVirtualSwitchPvlanMappingCreate,
-		Read:   resourceVSphereDistributedVirtualSwitchPvlanMappingRead,
-		Delete: resourceVSphereDistributedVirtualSwitchPvlanMappingDelete,
+		CreateContext: resourceVSphereDistributedVirtualSwitchPvlanMappingCreate,
+		ReadContext:   resourceVSphereDistributedVirtualSwitchPvlanMappingRead,
+		DeleteContext: resourceVSphereDistributedVirtualSwitchPvlanMappingDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(operation types.ConfigSpecOperation, d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(ctx context.Context, operation types.ConfigSpecOperation, d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
 		return err
@@ -66,7 +74,7 @@ func resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(operation type
 	vsphereDistributedVirtualSwitchModificationMutex.Lock()
 
 	// Fetch the target dvswitch
-	dvs, err := dvsFromUUID(client, d.Get("distributed_virtual_switch_id").(string))
+	dvs, err := dvsFromUUID(ctx, client, d.Get("distributed_virtual_switch_id").(string))
 	if err != nil {
 		return fmt.Errorf("cannot locate distributed_virtual_switch: %s", err)
 	}
@@ -85,7 +93,7 @@ func resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(operation type
 		},
 	}
 
-	err = updateDVSPvlanMappings(dvs, pvlanConfig)
+	err = updateDVSPvlanMappings(ctx, dvs, pvlanConfig)
 	if err != nil {
 		return fmt.Errorf("cannot reconfigure distributed virtual switch: %s", err)
 	}
@@ -94,36 +102,36 @@ func resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(operation type
 	return nil
 }
 
-func resourceVSphereDistributedVirtualSwitchPvlanMappingCreate(d *schema.ResourceData, meta interface{}) error {
-	err := resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(types.ConfigSpecOperationAdd, d, meta)
+func resourceVSphereDistributedVirtualSwitchPvlanMappingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	err := resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(ctx, types.ConfigSpecOperationAdd, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Try to read the mapping back, which will also generate the ID.
-	return resourceVSphereDistributedVirtualSwitchPvlanMappingRead(d, meta)
+	return resourceVSphereDistributedVirtualSwitchPvlanMappingRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedVirtualSwitchPvlanMappingDelete(d *schema.ResourceData, meta interface{}) error {
-	return resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(types.ConfigSpecOperationRemove, d, meta)
+func resourceVSphereDistributedVirtualSwitchPvlanMappingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(resourceVSphereDistributedVirtualSwitchPvlanMappingOperation(ctx, types.ConfigSpecOperationRemove, d, meta))
 }
 
-func resourceVSphereDistributedVirtualSwitchPvlanMappingRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchPvlanMappingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Ensure the DVSwitch still exists
-	dvs, err := dvsFromUUID(client, d.Get("distributed_virtual_switch_id").(string))
+	dvs, err := dvsFromUUID(ctx, client, d.Get("distributed_virtual_switch_id").(string))
 	if err != nil {
-		return fmt.Errorf("cannot locate distributed_virtual_switch: %s", err)
+		return diag.Errorf("cannot locate distributed_virtual_switch: %s", err)
 	}
 
 	// Get its properties
-	props, err := dvsProperties(dvs)
+	props, err := dvsProperties(ctx, dvs)
 	if err != nil {
-		return fmt.Errorf("cannot read properties of distributed_virtual_switch: %s", err)
+		return diag.Errorf("cannot read properties of distributed_virtual_switch: %s", err)
 	}
 	_ = d.Set("distributed_virtual_switch_id", props.Uuid)
 
@@ -5,12 +5,15 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -26,12 +29,18 @@ const resourceVSphereDPMHostOverrideName = "vsphere_dpm_host_override"
 
 func resourceVSphereDPMHostOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDPMHostOverrideCreate,
-		Read:   resourceVSphereDPMHostOverrideRead,
-		Update: resourceVSphereDPMHostOverrideUpdate,
-		Delete: resourceVSphereDPMHostOverrideDelete,
+		CreateContext: resourceVSphereDPMHostOverrideCreate,
+		ReadContext:   resourceVSphereDPMHostOverrideRead,
+		UpdateContext: resourceVSphereDPMHostOverrideUpdate,
+		DeleteContext: resourceVSphereDPMHostOverrideDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDPMHostOverrideImport,
+			StateContext: resourceVSphereDPMHostOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -64,17 +73,17 @@ func resourceVSphereDPMHostOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereDPMHostOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDPMHostOverrideIDString(d))
 
-	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
+	cluster, host, err := resourceVSphereDPMHostOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDpmHostConfigInfo(d, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DpmHostConfigSpec: []types.ClusterDpmHostConfigSpec{
@@ -87,31 +96,31 @@ func resourceVSphereDPMHostOverrideCreate(d *schema.ResourceData, meta interface
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereDPMHostOverrideFlattenID(cluster, host)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDPMHostOverrideIDString(d))
-	return resourceVSphereDPMHostOverrideRead(d, meta)
+	return resourceVSphereDPMHostOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDPMHostOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDPMHostOverrideIDString(d))
 
-	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
+	cluster, host, err := resourceVSphereDPMHostOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereDPMHostOverrideFindEntry(cluster, host)
+	info, err := resourceVSphereDPMHostOverrideFindEntry(ctx, cluster, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -124,32 +133,32 @@ func resourceVSphereDPMHostOverrideRead(d *schema.ResourceData, meta interface{}
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
 	if err = d.Set("host_system_id", host.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"host_system_id\": %s", err)
+		return diag.Errorf("error setting attribute \"host_system_id\": %s", err)
 	}
 
 	if err = flattenClusterDpmHostConfigInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDPMHostOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDPMHostOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDPMHostOverrideIDString(d))
 
-	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
+	cluster, host, err := resourceVSphereDPMHostOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDpmHostConfigInfo(d, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DpmHostConfigSpec: []types.ClusterDpmHostConfigSpec{
@@ -162,20 +171,20 @@ func resourceVSphereDPMHostOverrideUpdate(d *schema.ResourceData, meta interface
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDPMHostOverrideIDString(d))
-	return resourceVSphereDPMHostOverrideRead(d, meta)
+	return resourceVSphereDPMHostOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDPMHostOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereDPMHostOverrideIDString(d))
 
-	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
+	cluster, host, err := resourceVSphereDPMHostOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -189,15 +198,15 @@ func resourceVSphereDPMHostOverrideDelete(d *schema.ResourceData, meta interface
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereDPMHostOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDPMHostOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDPMHostOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -216,12 +225,12 @@ func resourceVSphereDPMHostOverrideImport(d *schema.ResourceData, meta interface
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	host, err := hostsystem.SystemOrDefault(client, hostPath, nil)
+	host, err := hostsystem.SystemOrDefault(ctx, client, hostPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate host %q: %s", hostPath, err)
 	}
@@ -281,10 +290,11 @@ func resourceVSphereDPMHostOverrideParseID(id string) (string, string, error) {
 // config in a cluster's configuration. It's used by the resource's read
 // functionality and tests. nil is returned if the entry cannot be found.
 func resourceVSphereDPMHostOverrideFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	host *object.HostSystem,
 ) (*types.ClusterDpmHostConfigInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -306,20 +316,23 @@ func resourceVSphereDPMHostOverrideFindEntry(
 // * If not, it's derived from the compute_cluster_id and host_system_id
 // attributes.
 func resourceVSphereDPMHostOverrideObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.HostSystem, error) {
 	if d.Id() != "" {
-		return resourceVSphereDPMHostOverrideObjectsFromID(d, meta)
+		return resourceVSphereDPMHostOverrideObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereDPMHostOverrideObjectsFromAttributes(d, meta)
+	return resourceVSphereDPMHostOverrideObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereDPMHostOverrideObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.HostSystem, error) {
 	return resourceVSphereDPMHostOverrideFetchObjects(
+		ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		d.Get("host_system_id").(string),
@@ -327,6 +340,7 @@ func resourceVSphereDPMHostOverrideObjectsFromAttributes(
 }
 
 func resourceVSphereDPMHostOverrideObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.HostSystem, error) {
@@ -338,10 +352,11 @@ func resourceVSphereDPMHostOverrideObjectsFromID(
 		return nil, nil, err
 	}
 
-	return resourceVSphereDPMHostOverrideFetchObjects(meta, clusterID, hostID)
+	return resourceVSphereDPMHostOverrideFetchObjects(ctx, meta, clusterID, hostID)
 }
 
 func resourceVSphereDPMHostOverrideFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	hostID string,
@@ -351,12 +366,12 @@ func resourceVSphereDPMHostOverrideFetchObjects(
 		return nil, nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate cluster: %s", err)
 	}
 
-	host, err := hostsystem.FromID(client, hostID)
+	host, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate virtual machine: %s", err)
 	}
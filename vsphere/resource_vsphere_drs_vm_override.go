@@ -5,12 +5,15 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -26,12 +29,18 @@ const resourceVSphereDRSVMOverrideName = "vsphere_drs_vm_override"
 
 func resourceVSphereDRSVMOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDRSVMOverrideCreate,
-		Read:   resourceVSphereDRSVMOverrideRead,
-		Update: resourceVSphereDRSVMOverrideUpdate,
-		Delete: resourceVSphereDRSVMOverrideDelete,
+		CreateContext: resourceVSphereDRSVMOverrideCreate,
+		ReadContext:   resourceVSphereDRSVMOverrideRead,
+		UpdateContext: resourceVSphereDRSVMOverrideUpdate,
+		DeleteContext: resourceVSphereDRSVMOverrideDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDRSVMOverrideImport,
+			StateContext: resourceVSphereDRSVMOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -64,17 +73,17 @@ func resourceVSphereDRSVMOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereDRSVMOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDRSVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DrsVmConfigSpec: []types.ClusterDrsVmConfigSpec{
@@ -87,31 +96,31 @@ func resourceVSphereDRSVMOverrideCreate(d *schema.ResourceData, meta interface{}
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	id, err := resourceVSphereDRSVMOverrideFlattenID(cluster, vm)
+	id, err := resourceVSphereDRSVMOverrideFlattenID(ctx, cluster, vm)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDRSVMOverrideIDString(d))
-	return resourceVSphereDRSVMOverrideRead(d, meta)
+	return resourceVSphereDRSVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDRSVMOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDRSVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereDRSVMOverrideFindEntry(cluster, vm)
+	info, err := resourceVSphereDRSVMOverrideFindEntry(ctx, cluster, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -124,36 +133,36 @@ func resourceVSphereDRSVMOverrideRead(d *schema.ResourceData, meta interface{})
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return fmt.Errorf("error getting properties of virtual machine: %s", err)
+		return diag.Errorf("error getting properties of virtual machine: %s", err)
 	}
 	if err = d.Set("virtual_machine_id", props.Config.Uuid); err != nil {
-		return fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
+		return diag.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
 	}
 
 	if err = flattenClusterDrsVMConfigInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDRSVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDRSVMOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDRSVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DrsVmConfigSpec: []types.ClusterDrsVmConfigSpec{
@@ -169,20 +178,20 @@ func resourceVSphereDRSVMOverrideUpdate(d *schema.ResourceData, meta interface{}
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDRSVMOverrideIDString(d))
-	return resourceVSphereDRSVMOverrideRead(d, meta)
+	return resourceVSphereDRSVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDRSVMOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereDRSVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -196,15 +205,15 @@ func resourceVSphereDRSVMOverrideDelete(d *schema.ResourceData, meta interface{}
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereDRSVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDRSVMOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDRSVMOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -223,17 +232,17 @@ func resourceVSphereDRSVMOverrideImport(d *schema.ResourceData, meta interface{}
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	vm, err := virtualmachine.FromPath(client, vmPath, nil)
+	vm, err := virtualmachine.FromPath(ctx, client, vmPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate virtual machine %q: %s", vmPath, err)
 	}
 
-	id, err := resourceVSphereDRSVMOverrideFlattenID(cluster, vm)
+	id, err := resourceVSphereDRSVMOverrideFlattenID(ctx, cluster, vm)
 	if err != nil {
 		return nil, fmt.Errorf("cannot compute ID of imported resource: %s", err)
 	}
@@ -270,9 +279,9 @@ func resourceVSphereDRSVMOverrideIDString(d structure.ResourceIDStringer) string
 
 // resourceVSphereDRSVMOverrideFlattenID makes an ID for the
 // vsphere_drs_vm_override resource.
-func resourceVSphereDRSVMOverrideFlattenID(cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
+func resourceVSphereDRSVMOverrideFlattenID(ctx context.Context, cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
 	clusterID := cluster.Reference().Value
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return "", fmt.Errorf("cannot compute ID off of properties of virtual machine: %s", err)
 	}
@@ -294,10 +303,11 @@ func resourceVSphereDRSVMOverrideParseID(id string) (string, string, error) {
 // config in a cluster's configuration. It's used by the resource's read
 // functionality and tests. nil is returned if the entry cannot be found.
 func resourceVSphereDRSVMOverrideFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	vm *object.VirtualMachine,
 ) (*types.ClusterDrsVmConfigInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -319,20 +329,23 @@ func resourceVSphereDRSVMOverrideFindEntry(
 // * If not, it's derived from the compute_cluster_id and virtual_machine_id
 // attributes.
 func resourceVSphereDRSVMOverrideObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
 	if d.Id() != "" {
-		return resourceVSphereDRSVMOverrideObjectsFromID(d, meta)
+		return resourceVSphereDRSVMOverrideObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereDRSVMOverrideObjectsFromAttributes(d, meta)
+	return resourceVSphereDRSVMOverrideObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereDRSVMOverrideObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
 	return resourceVSphereDRSVMOverrideFetchObjects(
+		ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		d.Get("virtual_machine_id").(string),
@@ -340,6 +353,7 @@ func resourceVSphereDRSVMOverrideObjectsFromAttributes(
 }
 
 func resourceVSphereDRSVMOverrideObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
@@ -351,10 +365,11 @@ func resourceVSphereDRSVMOverrideObjectsFromID(
 		return nil, nil, err
 	}
 
-	return resourceVSphereDRSVMOverrideFetchObjects(meta, clusterID, vmID)
+	return resourceVSphereDRSVMOverrideFetchObjects(ctx, meta, clusterID, vmID)
 }
 
 func resourceVSphereDRSVMOverrideFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	vmID string,
@@ -364,12 +379,12 @@ func resourceVSphereDRSVMOverrideFetchObjects(
 		return nil, nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate cluster: %s", err)
 	}
 
-	vm, err := virtualmachine.FromUUID(client, vmID)
+	vm, err := virtualmachine.FromUUID(ctx, client, vmID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate virtual machine: %s", err)
 	}
@@ -6,13 +6,14 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
@@ -45,15 +46,21 @@ func resourceVsphereEntityPermissions() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourceEntityPermissionsCreate,
-		Read:   resourceEntityPermissionsRead,
-		Update: resourceEntityPermissionsUpdate,
-		Delete: resourceEntityPermissionsDelete,
+		CreateContext: resourceEntityPermissionsCreate,
+		ReadContext:   resourceEntityPermissionsRead,
+		UpdateContext: resourceEntityPermissionsUpdate,
+		DeleteContext: resourceEntityPermissionsDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: sch,
 	}
 }
 
-func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning create permission for entity id %s", d.Get("entity_id").(string))
 	client := meta.(*Client).vimClient
 
@@ -62,9 +69,9 @@ func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) e
 
 	entityType := d.Get("entity_type").(string)
 	entityID := d.Get("entity_id").(string)
-	entityMoid, err := utils.GetMoid(client, entityType, entityID)
+	entityMoid, err := utils.GetMoid(ctx, client, entityType, entityID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	entityMor := types.ManagedObjectReference{
 		Type:  entityType,
@@ -76,12 +83,12 @@ func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) e
 	for _, permission := range permissions {
 		userOrGroup := permission.(map[string]interface{})["user_or_group"].(string)
 		if usersAndGroupsMap[userOrGroup] {
-			return fmt.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup)
+			return diag.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup)
 		}
 		usersAndGroupsMap[userOrGroup] = true
 		roleIDInt, err := strconv.ParseInt(permission.(map[string]interface{})["role_id"].(string), 10, 32)
 		if err != nil {
-			return fmt.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string))
+			return diag.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string))
 		}
 		roleID := int32(roleIDInt)
 		permissionObjs = append(permissionObjs, types.Permission{
@@ -91,15 +98,15 @@ func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) e
 			RoleId:    roleID,
 		})
 	}
-	err = authorizationManager.SetEntityPermissions(context.Background(), entityMor, permissionObjs)
+	err = authorizationManager.SetEntityPermissions(ctx, entityMor, permissionObjs)
 	if err != nil {
-		return fmt.Errorf("error while creating permission for entity id %s %s", entityID, err)
+		return diag.Errorf("error while creating permission for entity id %s %s", entityID, err)
 	}
 	d.SetId(entityMoid)
-	return resourceEntityPermissionsRead(d, meta)
+	return resourceEntityPermissionsRead(ctx, d, meta)
 }
 
-func resourceEntityPermissionsRead(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	entityType := d.Get("entity_type").(string)
 	log.Printf(" [DEBUG] : Reading vm entity permissions for entity id %s and type %s", d.Id(), entityType)
 	client := meta.(*Client).vimClient
@@ -109,9 +116,9 @@ func resourceEntityPermissionsRead(d *schema.ResourceData, meta interface{}) err
 		Type:  entityType,
 		Value: d.Id(),
 	}
-	permissionsArr, err := authorizationManager.RetrieveEntityPermissions(context.Background(), entityMor, false)
+	permissionsArr, err := authorizationManager.RetrieveEntityPermissions(ctx, entityMor, false)
 	if err != nil {
-		return fmt.Errorf("error while reading permissions for entity %s %s", d.Id(), err)
+		return diag.Errorf("error while reading permissions for entity %s %s", d.Id(), err)
 	}
 	if len(permissionsArr) == 0 {
 		log.Printf(" [DEBUG] :the permissions for entity with id %s and type %s is not found", d.Id(), entityType)
@@ -137,7 +144,7 @@ func resourceEntityPermissionsRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if d.HasChange("permissions") {
 		oldPermissions, newPermissions := d.GetChange("permissions")
 		log.Printf(" [DEBUG] : Beginning update Permission with entity id %s", d.Id())
@@ -156,12 +163,12 @@ func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) e
 		for _, permission := range newPermissions.([]interface{}) {
 			userOrGroup := permission.(map[string]interface{})["user_or_group"].(string)
 			if usersAndGroups[strings.ToLower(userOrGroup)] {
-				return fmt.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup)
+				return diag.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup)
 			}
 			usersAndGroups[strings.ToLower(userOrGroup)] = true
 			roleIDInt, err := strconv.ParseInt(permission.(map[string]interface{})["role_id"].(string), 10, 32)
 			if err != nil {
-				return fmt.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string))
+				return diag.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string))
 			}
 			roleID := int32(roleIDInt)
 			permissionObjs = append(permissionObjs, types.Permission{
@@ -171,9 +178,9 @@ func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) e
 				RoleId:    roleID,
 			})
 		}
-		err := authorizationManager.SetEntityPermissions(context.Background(), entityMor, permissionObjs)
+		err := authorizationManager.SetEntityPermissions(ctx, entityMor, permissionObjs)
 		if err != nil {
-			return fmt.Errorf("error while updating permissions for entity id %s %s", d.Id(), err)
+			return diag.Errorf("error while updating permissions for entity id %s %s", d.Id(), err)
 		}
 
 		// handle removed permissions
@@ -183,17 +190,17 @@ func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) e
 
 			if !usersAndGroups[strings.ToLower(userOrGroup)] {
 				log.Printf(" [DEBUG] Deleting permissions for user/group %s", userOrGroup)
-				err = authorizationManager.RemoveEntityPermission(context.Background(), entityMor, userOrGroup, isGroup)
+				err = authorizationManager.RemoveEntityPermission(ctx, entityMor, userOrGroup, isGroup)
 				if err != nil {
-					return fmt.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err)
+					return diag.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err)
 				}
 			}
 		}
 	}
-	return resourceEntityPermissionsRead(d, meta)
+	return resourceEntityPermissionsRead(ctx, d, meta)
 }
 
-func resourceEntityPermissionsDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf(" [DEBUG] Performing Delete of Entity permission %s", d.Id())
 	client := meta.(*Client).vimClient
 	authorizationManager := object.NewAuthorizationManager(client.Client)
@@ -208,9 +215,9 @@ func resourceEntityPermissionsDelete(d *schema.ResourceData, meta interface{}) e
 		userOrGroup := permission.(map[string]interface{})["user_or_group"].(string)
 		isGroup := permission.(map[string]interface{})["is_group"].(bool)
 		log.Printf(" [DEBUG] Deleting permissions for user/group %s", userOrGroup)
-		err := authorizationManager.RemoveEntityPermission(context.Background(), entityMor, userOrGroup, isGroup)
+		err := authorizationManager.RemoveEntityPermission(ctx, entityMor, userOrGroup, isGroup)
 		if err != nil {
-			return fmt.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err)
+			return diag.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err)
 		}
 	}
 	d.SetId("")
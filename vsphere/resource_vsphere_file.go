@@ -15,11 +15,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 // file represents a datastore file operation, including paths, datacenters, and configuration options.
@@ -37,10 +39,16 @@ type file struct {
 // resourceVSphereFile defines a resource for managing files or virtual disks on a datastore.
 func resourceVSphereFile() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereFileCreate,
-		Read:   resourceVSphereFileRead,
-		Update: resourceVSphereFileUpdate,
-		Delete: resourceVSphereFileDelete,
+		CreateContext: resourceVSphereFileCreate,
+		ReadContext:   resourceVSphereFileRead,
+		UpdateContext: resourceVSphereFileUpdate,
+		DeleteContext: resourceVSphereFileDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"datacenter": {
@@ -85,7 +93,7 @@ func resourceVSphereFile() *schema.Resource {
 	}
 }
 
-func resourceVSphereFileCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] creating file: %#v", d)
 	client := meta.(*Client).vimClient
 
@@ -108,38 +116,38 @@ func resourceVSphereFileCreate(d *schema.ResourceData, meta interface{}) error {
 	if v, ok := d.GetOk("datastore"); ok {
 		f.datastore = v.(string)
 	} else {
-		return fmt.Errorf("datastore argument is required")
+		return diag.Errorf("datastore argument is required")
 	}
 
 	if v, ok := d.GetOk("source_file"); ok {
 		f.sourceFile = v.(string)
 	} else {
-		return fmt.Errorf("source_file argument is required")
+		return diag.Errorf("source_file argument is required")
 	}
 
 	if v, ok := d.GetOk("destination_file"); ok {
 		f.destinationFile = v.(string)
 	} else {
-		return fmt.Errorf("destination_file argument is required")
+		return diag.Errorf("destination_file argument is required")
 	}
 
 	if v, ok := d.GetOk("create_directories"); ok {
 		f.createDirectories = v.(bool)
 	}
 
-	err := createFile(client, &f)
+	err := createFile(ctx, client, &f)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("[%v] %v/%v", f.datastore, f.datacenter, f.destinationFile))
 	log.Printf("[INFO] Created file: %s", f.destinationFile)
 
-	return resourceVSphereFileRead(d, meta)
+	return resourceVSphereFileRead(ctx, d, meta)
 }
 
 // resourceVSphereFileRead retrieves the state of the datastore file and updates the resource data.
-func resourceVSphereFileRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] reading file: %#v", d)
 	f := file{}
 
@@ -158,36 +166,36 @@ func resourceVSphereFileRead(d *schema.ResourceData, meta interface{}) error {
 	if v, ok := d.GetOk("datastore"); ok {
 		f.datastore = v.(string)
 	} else {
-		return fmt.Errorf("datastore argument is required")
+		return diag.Errorf("datastore argument is required")
 	}
 
 	if v, ok := d.GetOk("source_file"); ok {
 		f.sourceFile = v.(string)
 	} else {
-		return fmt.Errorf("source_file argument is required")
+		return diag.Errorf("source_file argument is required")
 	}
 
 	if v, ok := d.GetOk("destination_file"); ok {
 		f.destinationFile = v.(string)
 	} else {
-		return fmt.Errorf("destination_file argument is required")
+		return diag.Errorf("destination_file argument is required")
 	}
 
 	client := meta.(*Client).vimClient
 	finder := find.NewFinder(client.Client, true)
 
-	dc, err := finder.Datacenter(context.TODO(), f.datacenter)
+	dc, err := finder.Datacenter(ctx, f.datacenter)
 	if err != nil {
-		return fmt.Errorf("error finding datacenter %s", err)
+		return diag.Errorf("error finding datacenter %s", err)
 	}
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, f.datastore)
+	ds, err := getDatastore(ctx, finder, f.datastore)
 	if err != nil {
-		return fmt.Errorf("error finding datastore %s", err)
+		return diag.Errorf("error finding datastore %s", err)
 	}
 
-	_, err = ds.Stat(context.TODO(), f.destinationFile)
+	_, err = ds.Stat(ctx, f.destinationFile)
 	if err != nil {
 		log.Printf("[DEBUG] resourceVSphereFileRead - stat failed on: %v", f.destinationFile)
 		d.SetId("")
@@ -195,7 +203,7 @@ func resourceVSphereFileRead(d *schema.ResourceData, meta interface{}) error {
 		var notFoundError *object.DatastoreNoSuchFileError
 		ok := errors.As(err, &notFoundError)
 		if !ok {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -203,7 +211,7 @@ func resourceVSphereFileRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 // resourceVSphereFileUpdate handles updating a file resource when attributes are modified.
-func resourceVSphereFileUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] updating file: %#v", d)
 
 	if d.HasChange("destination_file") || d.HasChange("datacenter") || d.HasChange("datastore") {
@@ -236,35 +244,35 @@ func resourceVSphereFileUpdate(d *schema.ResourceData, meta interface{}) error {
 
 		// Get old and new datacenter and datastore.
 		client := meta.(*Client).vimClient
-		dcOld, err := getDatacenter(client, oldDataceneter)
+		dcOld, err := getDatacenter(ctx, client, oldDataceneter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		dcNew, err := getDatacenter(client, newDatacenter)
+		dcNew, err := getDatacenter(ctx, client, newDatacenter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		finder := find.NewFinder(client.Client, true)
 		finder = finder.SetDatacenter(dcOld)
-		dsOld, err := getDatastore(finder, oldDatastore)
+		dsOld, err := getDatastore(ctx, finder, oldDatastore)
 		if err != nil {
-			return fmt.Errorf("error finding old datastore %s", err)
+			return diag.Errorf("error finding old datastore %s", err)
 		}
 		finder = finder.SetDatacenter(dcNew)
-		dsNew, err := getDatastore(finder, newDatastore)
+		dsNew, err := getDatastore(ctx, finder, newDatastore)
 		if err != nil {
-			return fmt.Errorf("error finding new datastore %s", err)
+			return diag.Errorf("error finding new datastore %s", err)
 		}
 
 		// Move file between old/new datacenter, datastore, and path.
 		fm := object.NewFileManager(client.Client)
-		task, err := fm.MoveDatastoreFile(context.TODO(), dsOld.Path(oldDestinationFile), dcOld, dsNew.Path(newDestinationFile), dcNew, true)
+		task, err := fm.MoveDatastoreFile(ctx, dsOld.Path(oldDestinationFile), dcOld, dsNew.Path(newDestinationFile), dcNew, true)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		_, err = task.WaitForResultEx(context.TODO(), nil)
+		_, err = viapi.WaitForTaskResult(ctx, task)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -272,7 +280,7 @@ func resourceVSphereFileUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 // resourceVSphereFileDelete deletes a file or virtual disk from a datastore using the resource data.
-func resourceVSphereFileDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] deleting file: %#v", d)
 	f := file{}
 
@@ -283,26 +291,26 @@ func resourceVSphereFileDelete(d *schema.ResourceData, meta interface{}) error {
 	if v, ok := d.GetOk("datastore"); ok {
 		f.datastore = v.(string)
 	} else {
-		return fmt.Errorf("datastore argument is required")
+		return diag.Errorf("datastore argument is required")
 	}
 
 	if v, ok := d.GetOk("source_file"); ok {
 		f.sourceFile = v.(string)
 	} else {
-		return fmt.Errorf("source_file argument is required")
+		return diag.Errorf("source_file argument is required")
 	}
 
 	if v, ok := d.GetOk("destination_file"); ok {
 		f.destinationFile = v.(string)
 	} else {
-		return fmt.Errorf("destination_file argument is required")
+		return diag.Errorf("destination_file argument is required")
 	}
 
 	client := meta.(*Client).vimClient
 
-	err := deleteFile(client, &f)
+	err := deleteFile(ctx, client, &f)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
@@ -310,7 +318,7 @@ func resourceVSphereFileDelete(d *schema.ResourceData, meta interface{}) error {
 }
 
 // createDirectory ensures the parent directories of the destination file are created in the datastore, if required.
-func createDirectory(datastoreFileManager *object.DatastoreFileManager, f *file) error {
+func createDirectory(ctx context.Context, datastoreFileManager *object.DatastoreFileManager, f *file) error {
 	directoryPathIndex := strings.LastIndex(f.destinationFile, "/")
 	if directoryPathIndex < 0 {
 		// there are no parent directories in the file's name - nothing to create
@@ -318,7 +326,7 @@ func createDirectory(datastoreFileManager *object.DatastoreFileManager, f *file)
 	}
 
 	targetPath := f.destinationFile[0:directoryPathIndex]
-	err := datastoreFileManager.FileManager.MakeDirectory(context.TODO(),
+	err := datastoreFileManager.FileManager.MakeDirectory(ctx,
 		datastoreFileManager.Datastore.Path(targetPath), datastoreFileManager.Datacenter, true)
 	if err != nil {
 		return err
@@ -327,23 +335,23 @@ func createDirectory(datastoreFileManager *object.DatastoreFileManager, f *file)
 }
 
 // createFile uploads or copies a file to a datastore, with optional directory creation and VMDK handling.
-func createFile(client *govmomi.Client, f *file) error {
+func createFile(ctx context.Context, client *govmomi.Client, f *file) error {
 	finder := find.NewFinder(client.Client, true)
 
-	dstDatacenter, err := finder.Datacenter(context.TODO(), f.datacenter)
+	dstDatacenter, err := finder.Datacenter(ctx, f.datacenter)
 	if err != nil {
 		return fmt.Errorf("error finding destination datacenter %s", err)
 	}
 	finder = finder.SetDatacenter(dstDatacenter)
 
-	dstDatastore, err := getDatastore(finder, f.datastore)
+	dstDatastore, err := getDatastore(ctx, finder, f.datastore)
 	if err != nil {
 		return fmt.Errorf("error finding destination datastore %s", err)
 	}
 	dstDfm := dstDatastore.NewFileManager(dstDatacenter, false)
 
 	if f.createDirectories {
-		err = createDirectory(dstDfm, f)
+		err = createDirectory(ctx, dstDfm, f)
 		if err != nil {
 			return fmt.Errorf("error creating directory %s", err)
 		}
@@ -351,18 +359,18 @@ func createFile(client *govmomi.Client, f *file) error {
 
 	switch {
 	case f.copyFile:
-		srcDatacenter, err := finder.Datacenter(context.TODO(), f.sourceDatacenter)
+		srcDatacenter, err := finder.Datacenter(ctx, f.sourceDatacenter)
 		if err != nil {
 			return fmt.Errorf("error finding source datacenter %s", err)
 		}
-		srcDatastore, err := getDatastore(finder, f.sourceDatastore)
+		srcDatastore, err := getDatastore(ctx, finder, f.sourceDatastore)
 		if err != nil {
 			return fmt.Errorf("error finding source datastore %s", err)
 		}
 		srcDfm := srcDatastore.NewFileManager(srcDatacenter, false)
 		srcDfm.DatacenterTarget = dstDatacenter
 		dstFilePath := dstDfm.Path(f.destinationFile)
-		err = srcDfm.Copy(context.TODO(), f.sourceFile, dstFilePath.String())
+		err = srcDfm.Copy(ctx, f.sourceFile, dstFilePath.String())
 		if err != nil {
 			return fmt.Errorf("error copying file %s", err)
 		}
@@ -371,17 +379,17 @@ func createFile(client *govmomi.Client, f *file) error {
 		// Temporary directory path to upload VMDK file.
 		tempDstFile := fmt.Sprintf("tfm-temp-%d/%s", time.Now().Nanosecond(), fileName)
 
-		err = fileUpload(client, dstDatacenter, dstDatastore, f.sourceFile, tempDstFile)
+		err = fileUpload(ctx, client, dstDatacenter, dstDatastore, f.sourceFile, tempDstFile)
 		if err != nil {
 			return fmt.Errorf("error uploading vmdk file %s", err)
 		}
-		err = dstDfm.Move(context.TODO(), tempDstFile, f.destinationFile)
+		err = dstDfm.Move(ctx, tempDstFile, f.destinationFile)
 		if err != nil {
 			return fmt.Errorf("error moving vmdk file %s", err)
 		}
 
 	default:
-		err = fileUpload(client, dstDatacenter, dstDatastore, f.sourceFile, f.destinationFile)
+		err = fileUpload(ctx, client, dstDatacenter, dstDatastore, f.sourceFile, f.destinationFile)
 		if err != nil {
 			return fmt.Errorf("error uploading file %s", err)
 		}
@@ -391,8 +399,8 @@ func createFile(client *govmomi.Client, f *file) error {
 }
 
 // deleteFile deletes a file or virtual disk from a datastore.
-func deleteFile(client *govmomi.Client, f *file) error {
-	dc, err := getDatacenter(client, f.datacenter)
+func deleteFile(ctx context.Context, client *govmomi.Client, f *file) error {
+	dc, err := getDatacenter(ctx, client, f.datacenter)
 	if err != nil {
 		return fmt.Errorf("failed to get datacenter %q: %w", f.datacenter, err)
 	}
@@ -400,7 +408,7 @@ func deleteFile(client *govmomi.Client, f *file) error {
 	finder := find.NewFinder(client.Client, true)
 	finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, f.datastore)
+	ds, err := getDatastore(ctx, finder, f.datastore)
 	if err != nil {
 		return fmt.Errorf("failed to get datastore %q: %w", f.datastore, err)
 	}
@@ -408,7 +416,6 @@ func deleteFile(client *govmomi.Client, f *file) error {
 	var task *object.Task
 	var deleteErr error
 	datastorePath := ds.Path(f.destinationFile)
-	ctx := context.TODO()
 
 	if path.Ext(f.destinationFile) == ".vmdk" {
 		vdm := object.NewVirtualDiskManager(client.Client)
@@ -422,7 +429,7 @@ func deleteFile(client *govmomi.Client, f *file) error {
 		return fmt.Errorf("failed to initiate delete for %q: %w", datastorePath, deleteErr)
 	}
 
-	_, err = task.WaitForResultEx(ctx, nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		return fmt.Errorf("error waiting for delete task on %q: %w", datastorePath, err)
 	}
@@ -431,7 +438,7 @@ func deleteFile(client *govmomi.Client, f *file) error {
 }
 
 // fileUpload uploads a local file to a datastore.
-func fileUpload(client *govmomi.Client, dc *object.Datacenter, ds *object.Datastore, source, destination string) error {
+func fileUpload(ctx context.Context, client *govmomi.Client, dc *object.Datacenter, ds *object.Datastore, source, destination string) error {
 	// Define a slice for the special characters.
 	specialChars := []string{"+"}
 
@@ -460,7 +467,7 @@ func fileUpload(client *govmomi.Client, dc *object.Datacenter, ds *object.Datast
 	dsurl := ds.NewURL(destination)
 
 	p := soap.DefaultUpload
-	err = client.UploadFile(context.TODO(), source, dsurl, &p)
+	err = client.UploadFile(ctx, source, dsurl, &p)
 	if err != nil {
 		return fmt.Errorf("error uploading file %s", err)
 	}
@@ -470,7 +477,7 @@ func fileUpload(client *govmomi.Client, dc *object.Datacenter, ds *object.Datast
 		if strings.Contains(originalDestination, char) {
 			// If it does, rename the file to the original destination path.
 			fm := object.NewFileManager(client.Client)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+			ctx, cancel := context.WithTimeout(ctx, time.Minute*5)
 
 			task, err := fm.MoveDatastoreFile(ctx, ds.Path(destination), dc, ds.Path(originalDestination), dc, false)
 			if err != nil {
@@ -493,11 +500,11 @@ func fileUpload(client *govmomi.Client, dc *object.Datacenter, ds *object.Datast
 }
 
 // getDatastore returns a reference to a specified datastore or the default datastore if none is provided.
-func getDatastore(f *find.Finder, ds string) (*object.Datastore, error) {
+func getDatastore(ctx context.Context, f *find.Finder, ds string) (*object.Datastore, error) {
 	if ds != "" {
-		dso, err := f.Datastore(context.TODO(), ds)
+		dso, err := f.Datastore(ctx, ds)
 		return dso, err
 	}
-	dso, err := f.DefaultDatastore(context.TODO())
+	dso, err := f.DefaultDatastore(ctx)
 	return dso, err
 }
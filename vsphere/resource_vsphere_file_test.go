@@ -342,7 +342,7 @@ func testAccCheckVSphereFileDestroy(s *terraform.State) error {
 
 		finder = finder.SetDatacenter(dc)
 
-		ds, err := getDatastore(finder, rs.Primary.Attributes["datastore"])
+		ds, err := getDatastore(context.Background(), finder, rs.Primary.Attributes["datastore"])
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
@@ -385,7 +385,7 @@ func testAccCheckVSphereFileExists(n string, df string, exists bool) resource.Te
 		}
 		finder = finder.SetDatacenter(dc)
 
-		ds, err := getDatastore(finder, rs.Primary.Attributes["datastore"])
+		ds, err := getDatastore(context.Background(), finder, rs.Primary.Attributes["datastore"])
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
@@ -0,0 +1,392 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/datastore"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/firstclassdisk"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/spbm"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+const resourceVSphereFirstClassDiskName = "vsphere_first_class_disk"
+
+var firstClassDiskProvisioningTypeAllowedValues = []string{
+	string(types.BaseConfigInfoDiskFileBackingInfoProvisioningTypeThin),
+	string(types.BaseConfigInfoDiskFileBackingInfoProvisioningTypeEagerZeroedThick),
+	string(types.BaseConfigInfoDiskFileBackingInfoProvisioningTypeLazyZeroedThick),
+}
+
+func resourceVSphereFirstClassDisk() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSphereFirstClassDiskCreate,
+		ReadContext:   resourceVSphereFirstClassDiskRead,
+		UpdateContext: resourceVSphereFirstClassDiskUpdate,
+		DeleteContext: resourceVSphereFirstClassDiskDelete,
+		CustomizeDiff: resourceVSphereFirstClassDiskCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereFirstClassDiskImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the disk.",
+			},
+			"datastore_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The managed object ID of the datastore for the disk. Changing this relocates the disk.",
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "The size of the disk, in GB. The disk can be grown but not shrunk.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"provisioning_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      string(types.BaseConfigInfoDiskFileBackingInfoProvisioningTypeThin),
+				Description:  "The provisioning type of the disk.",
+				ValidateFunc: validation.StringInSlice(firstClassDiskProvisioningTypeAllowedValues, false),
+			},
+			"keep_after_delete_vm": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Keep the disk when a virtual machine it is attached to is deleted.",
+			},
+			"storage_policy_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the storage policy to apply to the disk.",
+			},
+			"file_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The datastore path of the disk's backing file.",
+			},
+			vSphereTagAttributeKey: tagsSchema(),
+		},
+	}
+}
+
+func resourceVSphereFirstClassDiskCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereFirstClassDiskIDString(d))
+	client := meta.(*Client).vimClient
+	ds, err := datastore.FromID(ctx, client, d.Get("datastore_id").(string))
+	if err != nil {
+		return diag.Errorf("cannot locate datastore: %s", err)
+	}
+
+	spec := types.VslmCreateSpec{
+		Name:              d.Get("name").(string),
+		KeepAfterDeleteVm: structure.GetBool(d, "keep_after_delete_vm"),
+		CapacityInMB:      int64(d.Get("size").(int)) * 1024,
+		BackingSpec: &types.VslmCreateSpecDiskFileBackingSpec{
+			VslmCreateSpecBackingSpec: types.VslmCreateSpecBackingSpec{
+				Datastore: ds.Reference(),
+			},
+			ProvisioningType: d.Get("provisioning_type").(string),
+		},
+	}
+	if id, ok := d.GetOk("storage_policy_id"); ok {
+		spec.Profile = spbm.PolicySpecByID(id.(string))
+	}
+
+	obj, err := firstclassdisk.Create(ctx, client, spec)
+	if err != nil {
+		return diag.Errorf("error creating first class disk: %s", err)
+	}
+	d.SetId(obj.Config.Id.Id)
+
+	if err := resourceVSphereFirstClassDiskApplyTags(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereFirstClassDiskIDString(d))
+	return resourceVSphereFirstClassDiskRead(ctx, d, meta)
+}
+
+func resourceVSphereFirstClassDiskRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereFirstClassDiskIDString(d))
+	client := meta.(*Client).vimClient
+	ds, err := datastore.FromID(ctx, client, d.Get("datastore_id").(string))
+	if err != nil {
+		if viapi.IsAnyNotFoundError(err) {
+			log.Printf("[DEBUG] %s: Datastore not found, marking resource as gone", resourceVSphereFirstClassDiskIDString(d))
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("cannot locate datastore: %s", err)
+	}
+	obj, err := firstclassdisk.FromID(ctx, client, ds, d.Id())
+	if err != nil {
+		if viapi.IsAnyNotFoundError(err) {
+			log.Printf("[DEBUG] %s: Disk not found, marking resource as gone", resourceVSphereFirstClassDiskIDString(d))
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error reading first class disk: %s", err)
+	}
+
+	_ = d.Set("name", obj.Config.Name)
+	_ = d.Set("size", structure.ByteToGiB(obj.Config.CapacityInMB*1024*1024))
+	_ = d.Set("file_path", firstclassdisk.FilePath(obj))
+	if t := firstclassdisk.ProvisioningType(obj); t != "" {
+		_ = d.Set("provisioning_type", t)
+	}
+	if obj.Config.KeepAfterDeleteVm != nil {
+		_ = d.Set("keep_after_delete_vm", *obj.Config.KeepAfterDeleteVm)
+	}
+
+	// Storage policies are only available through vCenter.
+	if spbm.IsSupported(client) {
+		polID, err := spbm.PolicyIDByFirstClassDisk(ctx, client, d.Id())
+		if err != nil {
+			return diag.Errorf("error reading storage policy of first class disk: %s", err)
+		}
+		_ = d.Set("storage_policy_id", polID)
+	}
+
+	if err := resourceVSphereFirstClassDiskReadTags(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereFirstClassDiskIDString(d))
+	return nil
+}
+
+func resourceVSphereFirstClassDiskUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereFirstClassDiskIDString(d))
+	client := meta.(*Client).vimClient
+	oldDsID, newDsID := d.GetChange("datastore_id")
+	ds, err := datastore.FromID(ctx, client, oldDsID.(string))
+	if err != nil {
+		return diag.Errorf("cannot locate datastore: %s", err)
+	}
+
+	if d.HasChange("name") {
+		if err := firstclassdisk.Rename(ctx, client, ds, d.Id(), d.Get("name").(string)); err != nil {
+			return diag.Errorf("error renaming first class disk: %s", err)
+		}
+	}
+
+	policyApplied := false
+	if d.HasChange("datastore_id") {
+		dst, err := datastore.FromID(ctx, client, newDsID.(string))
+		if err != nil {
+			return diag.Errorf("cannot locate destination datastore: %s", err)
+		}
+		var profile []types.BaseVirtualMachineProfileSpec
+		if id, ok := d.GetOk("storage_policy_id"); ok {
+			profile = spbm.PolicySpecByID(id.(string))
+			policyApplied = true
+		}
+		if err := firstclassdisk.Relocate(ctx, client, ds, d.Id(), dst, profile); err != nil {
+			return diag.Errorf("error relocating first class disk: %s", err)
+		}
+		ds = dst
+	}
+
+	if d.HasChange("storage_policy_id") && !policyApplied {
+		var profile []types.BaseVirtualMachineProfileSpec
+		if id, ok := d.GetOk("storage_policy_id"); ok {
+			profile = spbm.PolicySpecByID(id.(string))
+		} else {
+			profile = []types.BaseVirtualMachineProfileSpec{&types.VirtualMachineEmptyProfileSpec{}}
+		}
+		if err := firstclassdisk.UpdatePolicy(ctx, client, ds, d.Id(), profile); err != nil {
+			return diag.Errorf("error updating storage policy on first class disk: %s", err)
+		}
+	}
+
+	if d.HasChange("size") {
+		if err := firstclassdisk.Extend(ctx, client, ds, d.Id(), int64(d.Get("size").(int))*1024); err != nil {
+			return diag.Errorf("error extending first class disk: %s", err)
+		}
+	}
+
+	if err := resourceVSphereFirstClassDiskApplyTags(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereFirstClassDiskIDString(d))
+	return resourceVSphereFirstClassDiskRead(ctx, d, meta)
+}
+
+func resourceVSphereFirstClassDiskDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereFirstClassDiskIDString(d))
+	client := meta.(*Client).vimClient
+	ds, err := datastore.FromID(ctx, client, d.Get("datastore_id").(string))
+	if err != nil {
+		return diag.Errorf("cannot locate datastore: %s", err)
+	}
+	if err := firstclassdisk.Delete(ctx, client, ds, d.Id()); err != nil {
+		return diag.Errorf("error deleting first class disk: %s", err)
+	}
+	d.SetId("")
+	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereFirstClassDiskIDString(d))
+	return nil
+}
+
+func resourceVSphereFirstClassDiskCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("size") {
+		return nil
+	}
+	o, n := d.GetChange("size")
+	if n.(int) < o.(int) {
+		return fmt.Errorf("cannot shrink first class disk from %d GB to %d GB", o.(int), n.(int))
+	}
+	return nil
+}
+
+func resourceVSphereFirstClassDiskImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("ID must be of the form <datastore_id>:<disk_id>, got %q", d.Id())
+	}
+	client := meta.(*Client).vimClient
+	ds, err := datastore.FromID(ctx, client, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot locate datastore: %s", err)
+	}
+	if _, err := firstclassdisk.FromID(ctx, client, ds, parts[1]); err != nil {
+		return nil, fmt.Errorf("error loading first class disk: %s", err)
+	}
+	d.SetId(parts[1])
+	_ = d.Set("datastore_id", parts[0])
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceVSphereFirstClassDiskApplyTags processes the tags step for the
+// first class disk. First class disks are not managed objects, so tags are
+// attached by name through the VStorageObjectManager rather than through the
+// tagging API.
+func resourceVSphereFirstClassDiskApplyTags(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tm, err := tagsManagerIfDefined(d, meta)
+	if err != nil {
+		return err
+	}
+	if tm == nil {
+		log.Printf("[DEBUG] %s: Tags unsupported on this connection, skipping", resourceVSphereFirstClassDiskIDString(d))
+		return nil
+	}
+
+	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereFirstClassDiskIDString(d))
+	client := meta.(*Client).vimClient
+	o, n := d.GetChange(vSphereTagAttributeKey)
+	for _, id := range o.(*schema.Set).Difference(n.(*schema.Set)).List() {
+		entry, err := firstClassDiskTagEntry(ctx, tm, id.(string))
+		if err != nil {
+			return err
+		}
+		if err := firstclassdisk.DetachTag(ctx, client, d.Id(), entry); err != nil {
+			return fmt.Errorf("error detaching tag %q from first class disk: %s", id, err)
+		}
+	}
+	for _, id := range n.(*schema.Set).Difference(o.(*schema.Set)).List() {
+		entry, err := firstClassDiskTagEntry(ctx, tm, id.(string))
+		if err != nil {
+			return err
+		}
+		if err := firstclassdisk.AttachTag(ctx, client, d.Id(), entry); err != nil {
+			return fmt.Errorf("error attaching tag %q to first class disk: %s", id, err)
+		}
+	}
+	return nil
+}
+
+// resourceVSphereFirstClassDiskReadTags reads the tags attached to the first
+// class disk and saves their IDs.
+func resourceVSphereFirstClassDiskReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tm, _ := meta.(*Client).TagsManager()
+	if tm == nil {
+		log.Printf("[DEBUG] %s: Tags unsupported on this connection, skipping tag read", resourceVSphereFirstClassDiskIDString(d))
+		return nil
+	}
+
+	log.Printf("[DEBUG] %s: Reading tags", resourceVSphereFirstClassDiskIDString(d))
+	entries, err := firstclassdisk.Tags(ctx, meta.(*Client).vimClient, d.Id())
+	if err != nil {
+		return fmt.Errorf("error reading tags for first class disk: %s", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		catID, err := tagCategoryByName(ctx, tm, entry.ParentCategoryName)
+		if err != nil {
+			return err
+		}
+		id, err := tagByName(ctx, tm, entry.TagName, catID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if err := d.Set(vSphereTagAttributeKey, ids); err != nil {
+		return fmt.Errorf("error saving tag IDs to resource data: %s", err)
+	}
+	return nil
+}
+
+// firstClassDiskTagEntry translates a tag ID into the tag and category names
+// used by the VStorageObjectManager.
+func firstClassDiskTagEntry(ctx context.Context, tm *tags.Manager, id string) (types.VslmTagEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+	tag, err := tm.GetTag(ctx, id)
+	if err != nil {
+		return types.VslmTagEntry{}, fmt.Errorf("could not get tag %q: %s", id, err)
+	}
+	cat, err := tm.GetCategory(ctx, tag.CategoryID)
+	if err != nil {
+		return types.VslmTagEntry{}, fmt.Errorf("could not get category for tag %q: %s", id, err)
+	}
+	return types.VslmTagEntry{TagName: tag.Name, ParentCategoryName: cat.Name}, nil
+}
+
+// firstClassDiskFromID is a convenience method that locates a first class
+// disk by its ID and the managed object ID of its datastore. Errors are
+// returned unwrapped so that callers can check for not found faults.
+func firstClassDiskFromID(ctx context.Context, meta interface{}, dsID, id string) (*object.Datastore, *types.VStorageObject, error) {
+	client := meta.(*Client).vimClient
+	ds, err := datastore.FromID(ctx, client, dsID)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := firstclassdisk.FromID(ctx, client, ds, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ds, obj, nil
+}
+
+func resourceVSphereFirstClassDiskIDString(d structure.ResourceIDStringer) string {
+	return structure.ResourceIDString(d, resourceVSphereFirstClassDiskName)
+}
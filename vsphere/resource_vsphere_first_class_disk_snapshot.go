@@ -0,0 +1,108 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/firstclassdisk"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+func resourceVSphereFirstClassDiskSnapshot() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSphereFirstClassDiskSnapshotCreate,
+		ReadContext:   resourceVSphereFirstClassDiskSnapshotRead,
+		DeleteContext: resourceVSphereFirstClassDiskSnapshotDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"first_class_disk_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the first class disk to snapshot.",
+			},
+			"datastore_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The managed object ID of the datastore of the first class disk.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The description of the snapshot.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the snapshot was created.",
+			},
+		},
+	}
+}
+
+func resourceVSphereFirstClassDiskSnapshotCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ds, obj, err := firstClassDiskFromID(ctx, meta, d.Get("datastore_id").(string), d.Get("first_class_disk_id").(string))
+	if err != nil {
+		return diag.Errorf("error while getting the first class disk: %s", err)
+	}
+	sid, err := firstclassdisk.CreateSnapshot(ctx, meta.(*Client).vimClient, ds, obj.Config.Id.Id, d.Get("description").(string))
+	if err != nil {
+		log.Printf("[DEBUG] Error while creating the first class disk snapshot: %v", err)
+		return diag.Errorf("error while creating the first class disk snapshot: %s", err)
+	}
+	log.Printf("[DEBUG] Create snapshot %q of first class disk %q completed", sid, obj.Config.Id.Id)
+	d.SetId(sid)
+	return resourceVSphereFirstClassDiskSnapshotRead(ctx, d, meta)
+}
+
+func resourceVSphereFirstClassDiskSnapshotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ds, obj, err := firstClassDiskFromID(ctx, meta, d.Get("datastore_id").(string), d.Get("first_class_disk_id").(string))
+	if err != nil {
+		if viapi.IsAnyNotFoundError(err) {
+			log.Printf("[DEBUG] First class disk for snapshot %q not found, marking resource as gone", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error while getting the first class disk: %s", err)
+	}
+	snapshot, err := firstclassdisk.SnapshotFromID(ctx, meta.(*Client).vimClient, ds, obj.Config.Id.Id, d.Id())
+	if err != nil {
+		return diag.Errorf("error while finding the snapshot: %s", err)
+	}
+	if snapshot == nil {
+		log.Printf("[DEBUG] Snapshot %q not found, marking resource as gone", d.Id())
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("description", snapshot.Description)
+	_ = d.Set("create_time", snapshot.CreateTime.Format(time.RFC3339))
+	return nil
+}
+
+func resourceVSphereFirstClassDiskSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ds, obj, err := firstClassDiskFromID(ctx, meta, d.Get("datastore_id").(string), d.Get("first_class_disk_id").(string))
+	if err != nil {
+		return diag.Errorf("error while getting the first class disk: %s", err)
+	}
+	if err := firstclassdisk.DeleteSnapshot(ctx, meta.(*Client).vimClient, ds, obj.Config.Id.Id, d.Id()); err != nil {
+		log.Printf("[DEBUG] Error while deleting the first class disk snapshot: %v", err)
+		return diag.Errorf("error while deleting the first class disk snapshot: %s", err)
+	}
+	log.Printf("[DEBUG] Delete snapshot %q of first class disk %q completed", d.Id(), obj.Config.Id.Id)
+	d.SetId("")
+	return nil
+}
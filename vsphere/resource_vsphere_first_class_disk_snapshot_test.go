@@ -0,0 +1,107 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/firstclassdisk"
+)
+
+func TestAccResourceVSphereFirstClassDiskSnapshot_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereFirstClassDiskSnapshotConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFirstClassDiskSnapshotCheckExists("vsphere_first_class_disk_snapshot.snapshot", true),
+					resource.TestCheckResourceAttr("vsphere_first_class_disk_snapshot.snapshot", "description", "terraform-test-snapshot"),
+					resource.TestCheckResourceAttrSet("vsphere_first_class_disk_snapshot.snapshot", "create_time"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereFirstClassDiskSnapshotConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFirstClassDiskCheckSnapshotCount(0),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceVSphereFirstClassDiskSnapshotCheckExists(name string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+		ds, obj, err := firstClassDiskFromID(context.Background(), testAccProvider.Meta(), rs.Primary.Attributes["datastore_id"], rs.Primary.Attributes["first_class_disk_id"])
+		if err != nil {
+			return err
+		}
+		snapshot, err := firstclassdisk.SnapshotFromID(context.Background(), testAccProvider.Meta().(*Client).vimClient, ds, obj.Config.Id.Id, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if expected && snapshot == nil {
+			return errors.New("expected snapshot to exist")
+		}
+		if !expected && snapshot != nil {
+			return errors.New("expected snapshot to be missing")
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereFirstClassDiskCheckSnapshotCount(expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		vars, err := testClientVariablesForResource(s, fmt.Sprintf("%s.%s", resourceVSphereFirstClassDiskName, "disk"))
+		if err != nil {
+			return err
+		}
+		ds, obj, err := firstClassDiskFromID(context.Background(), testAccProvider.Meta(), vars.resourceAttributes["datastore_id"], vars.resourceID)
+		if err != nil {
+			return err
+		}
+		snapshots, err := firstclassdisk.Snapshots(context.Background(), vars.client, ds, obj.Config.Id.Id)
+		if err != nil {
+			return err
+		}
+		if len(snapshots) != expected {
+			return fmt.Errorf("expected %d snapshots, got %d", expected, len(snapshots))
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereFirstClassDiskSnapshotConfig(snapshot bool) string {
+	snapshotConfig := ""
+	if snapshot {
+		snapshotConfig = `
+resource "vsphere_first_class_disk_snapshot" "snapshot" {
+  first_class_disk_id = vsphere_first_class_disk.disk.id
+  datastore_id        = vsphere_first_class_disk.disk.datastore_id
+  description         = "terraform-test-snapshot"
+}
+`
+	}
+	return fmt.Sprintf(`
+%s
+%s
+`,
+		testAccResourceVSphereFirstClassDiskConfig("testacc-fcd", 1),
+		snapshotConfig,
+	)
+}
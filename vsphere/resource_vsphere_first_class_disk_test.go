@@ -0,0 +1,190 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+func TestAccResourceVSphereFirstClassDisk_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereFirstClassDiskCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereFirstClassDiskConfig("testacc-fcd", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFirstClassDiskCheckExists(true),
+					testAccResourceVSphereFirstClassDiskCheckSize(1),
+					resource.TestCheckResourceAttrSet("vsphere_first_class_disk.disk", "file_path"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereFirstClassDiskConfig("testacc-fcd-renamed", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFirstClassDiskCheckExists(true),
+					testAccResourceVSphereFirstClassDiskCheckSize(2),
+					resource.TestCheckResourceAttr("vsphere_first_class_disk.disk", "name", "testacc-fcd-renamed"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_first_class_disk.disk",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["vsphere_first_class_disk.disk"]
+					if !ok {
+						return "", errors.New("vsphere_first_class_disk.disk not found in state")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["datastore_id"], rs.Primary.ID), nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereFirstClassDisk_shrink(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereFirstClassDiskCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereFirstClassDiskConfig("testacc-fcd", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFirstClassDiskCheckExists(true),
+				),
+			},
+			{
+				Config:      testAccResourceVSphereFirstClassDiskConfig("testacc-fcd", 1),
+				ExpectError: regexp.MustCompile("cannot shrink first class disk"),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereFirstClassDisk_tags(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereFirstClassDiskCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereFirstClassDiskConfigTags(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFirstClassDiskCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_first_class_disk.disk", "tags.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("vsphere_first_class_disk.disk", "tags.*", "vsphere_tag.tag1", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceVSphereFirstClassDiskCheckExists(expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		_, err := testGetFirstClassDisk(s, "disk")
+		if err != nil {
+			if viapi.IsAnyNotFoundError(err) && !expected {
+				// Expected missing
+				return nil
+			}
+			return err
+		}
+		if !expected {
+			return errors.New("expected first class disk to be missing")
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereFirstClassDiskCheckSize(expected int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		obj, err := testGetFirstClassDisk(s, "disk")
+		if err != nil {
+			return err
+		}
+		if actual := obj.Config.CapacityInMB / 1024; actual != expected {
+			return fmt.Errorf("expected size to be %d GB, got %d GB", expected, actual)
+		}
+		return nil
+	}
+}
+
+// testGetFirstClassDisk is a convenience method to fetch a first class disk
+// by resource name.
+func testGetFirstClassDisk(s *terraform.State, resourceName string) (*types.VStorageObject, error) {
+	vars, err := testClientVariablesForResource(s, fmt.Sprintf("%s.%s", resourceVSphereFirstClassDiskName, resourceName))
+	if err != nil {
+		return nil, err
+	}
+	_, obj, err := firstClassDiskFromID(context.Background(), testAccProvider.Meta(), vars.resourceAttributes["datastore_id"], vars.resourceID)
+	return obj, err
+}
+
+func testAccResourceVSphereFirstClassDiskConfig(name string, size int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "vsphere_first_class_disk" "disk" {
+  name         = "%s"
+  datastore_id = data.vsphere_datastore.rootds1.id
+  size         = %d
+}
+`,
+		testhelper.CombineConfigs(testhelper.ConfigDataRootDC1(), testhelper.ConfigDataRootDS1()),
+		name,
+		size,
+	)
+}
+
+func testAccResourceVSphereFirstClassDiskConfigTags() string {
+	return fmt.Sprintf(`
+%s
+
+resource "vsphere_tag_category" "category1" {
+  name        = "testacc-fcd-cat1"
+  cardinality = "MULTIPLE"
+
+  associable_types = [
+    "VirtualMachine",
+  ]
+}
+
+resource "vsphere_tag" "tag1" {
+  name        = "testacc-fcd-tag1"
+  category_id = vsphere_tag_category.category1.id
+}
+
+resource "vsphere_first_class_disk" "disk" {
+  name         = "testacc-fcd"
+  datastore_id = data.vsphere_datastore.rootds1.id
+  size         = 1
+  tags         = [vsphere_tag.tag1.id]
+}
+`,
+		testhelper.CombineConfigs(testhelper.ConfigDataRootDC1(), testhelper.ConfigDataRootDS1()),
+	)
+}
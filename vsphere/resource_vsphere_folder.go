@@ -7,10 +7,11 @@ package vsphere
 import (
 	"context"
 	"errors"
-	"fmt"
 	"path"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/object"
@@ -22,12 +23,18 @@ import (
 
 func resourceVSphereFolder() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereFolderCreate,
-		Read:   resourceVSphereFolderRead,
-		Update: resourceVSphereFolderUpdate,
-		Delete: resourceVSphereFolderDelete,
+		CreateContext: resourceVSphereFolderCreate,
+		ReadContext:   resourceVSphereFolderRead,
+		UpdateContext: resourceVSphereFolderUpdate,
+		DeleteContext: resourceVSphereFolderDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereFolderImport,
+			StateContext: resourceVSphereFolderImport,
 		},
 		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
@@ -68,77 +75,77 @@ func resourceVSphereFolder() *schema.Resource {
 	}
 }
 
-func resourceVSphereFolderCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	ft := folder.VSphereFolderType(d.Get("type").(string))
 	var dc *object.Datacenter
 	if dcID, ok := d.GetOk("datacenter_id"); ok {
 		var err error
-		dc, err = datacenterFromID(client, dcID.(string))
+		dc, err = datacenterFromID(ctx, client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.Errorf("cannot locate datacenter: %s", err)
 		}
 	} else if ft != folder.VSphereFolderTypeDatacenter {
-		return fmt.Errorf("datacenter_id cannot be empty when creating a targetFolder of type %s", ft)
+		return diag.Errorf("datacenter_id cannot be empty when creating a targetFolder of type %s", ft)
 	}
 
 	p := d.Get("path").(string)
 
 	// Determine the parent targetFolder
-	parent, err := folder.ParentFromPath(client, p, ft, dc)
+	parent, err := folder.ParentFromPath(ctx, client, p, ft, dc)
 	if err != nil {
-		return fmt.Errorf("error trying to determine parent targetFolder: %s", err)
+		return diag.Errorf("error trying to determine parent targetFolder: %s", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
 	targetFolder, err := parent.CreateFolder(ctx, path.Base(p))
 	if err != nil {
-		return fmt.Errorf("error creating targetFolder: %s", err)
+		return diag.Errorf("error creating targetFolder: %s", err)
 	}
 
 	d.SetId(targetFolder.Reference().Value)
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, targetFolder); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, targetFolder); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(targetFolder); err != nil {
-			return fmt.Errorf("error setting custom attributes: %s", err)
+		if err := attrsProcessor.ProcessDiff(ctx, targetFolder); err != nil {
+			return diag.Errorf("error setting custom attributes: %s", err)
 		}
 	}
 
-	return resourceVSphereFolderRead(d, meta)
+	return resourceVSphereFolderRead(ctx, d, meta)
 }
 
-func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	fo, err := folder.FromID(client, d.Id())
+	fo, err := folder.FromID(ctx, client, d.Id())
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return diag.Errorf("cannot locate folder: %s", err)
 	}
 
 	// Determine the folder type first. We use the folder as the source of truth
 	// here versus the state so that we can support import.
-	ft, err := folder.FindType(fo)
+	ft, err := folder.FindType(ctx, fo)
 	if err != nil {
-		return fmt.Errorf("cannot determine folder type: %s", err)
+		return diag.Errorf("cannot determine folder type: %s", err)
 	}
 
 	// Again, to support a clean import (which is done off of absolute path to
@@ -149,15 +156,15 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 		particle := folder.RootPathParticle(ft)
 		dcp, err := particle.SplitDatacenter(p)
 		if err != nil {
-			return fmt.Errorf("cannot determine datacenter path: %s", err)
+			return diag.Errorf("cannot determine datacenter path: %s", err)
 		}
-		dc, err = getDatacenter(client, dcp)
+		dc, err = getDatacenter(ctx, client, dcp)
 		if err != nil {
-			return fmt.Errorf("cannot find datacenter from path %q: %s", dcp, err)
+			return diag.Errorf("cannot find datacenter from path %q: %s", dcp, err)
 		}
 		relative, err := particle.SplitRelative(p)
 		if err != nil {
-			return fmt.Errorf("cannot determine relative folder path: %s", err)
+			return diag.Errorf("cannot determine relative folder path: %s", err)
 		}
 		p = relative
 	}
@@ -170,16 +177,16 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, fo, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, fo, d); err != nil {
+			return diag.Errorf("error reading tags: %s", err)
 		}
 	}
 
 	// Read custom attributes
 	if customattribute.IsSupported(client) {
-		moFolder, err := folder.Properties(fo)
+		moFolder, err := folder.Properties(ctx, fo)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		customattribute.ReadFromResource(moFolder.Entity(), d)
 	}
@@ -187,43 +194,43 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceVSphereFolderUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	fo, err := folder.FromID(client, d.Id())
+	fo, err := folder.FromID(ctx, client, d.Id())
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return diag.Errorf("cannot locate folder: %s", err)
 	}
 
 	// Apply any pending tags first as it's the lesser expensive of the two
 	// operations
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, fo); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, fo); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(fo); err != nil {
-			return fmt.Errorf("error setting custom attributes: %s", err)
+		if err := attrsProcessor.ProcessDiff(ctx, fo); err != nil {
+			return diag.Errorf("error setting custom attributes: %s", err)
 		}
 	}
 
 	var dc *object.Datacenter
 	if dcID, ok := d.GetOk("datacenter_id"); ok {
 		var err error
-		dc, err = datacenterFromID(client, dcID.(string))
+		dc, err = datacenterFromID(ctx, client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.Errorf("cannot locate datacenter: %s", err)
 		}
 	}
 
@@ -232,77 +239,77 @@ func resourceVSphereFolderUpdate(d *schema.ResourceData, meta interface{}) error
 		// change in name, or both.
 		ft := folder.VSphereFolderType(d.Get("type").(string))
 		oldp, newp := d.GetChange("path")
-		oldpa, err := folder.ParentFromPath(client, oldp.(string), ft, dc)
+		oldpa, err := folder.ParentFromPath(ctx, client, oldp.(string), ft, dc)
 		if err != nil {
-			return fmt.Errorf("error parsing parent folder from path %q: %s", oldp.(string), err)
+			return diag.Errorf("error parsing parent folder from path %q: %s", oldp.(string), err)
 		}
-		newpa, err := folder.ParentFromPath(client, newp.(string), ft, dc)
+		newpa, err := folder.ParentFromPath(ctx, client, newp.(string), ft, dc)
 		if err != nil {
-			return fmt.Errorf("error parsing parent folder from path %q: %s", newp.(string), err)
+			return diag.Errorf("error parsing parent folder from path %q: %s", newp.(string), err)
 		}
 		oldn := path.Base(oldp.(string))
 		newn := path.Base(newp.(string))
 
 		if oldn != newn {
 			// Folder base name has changed and needs a rename
-			if err := viapi.RenameObject(client, fo.Reference(), newn); err != nil {
-				return fmt.Errorf("could not rename folder: %s", err)
+			if err := viapi.RenameObject(ctx, client, fo.Reference(), newn); err != nil {
+				return diag.Errorf("could not rename folder: %s", err)
 			}
 		}
 		if oldpa.Reference().Value != newpa.Reference().Value {
 			// The parent folder has changed - we need to move the folder into the
 			// new path
-			ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+			ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 			defer cancel()
 			task, err := newpa.MoveInto(ctx, []types.ManagedObjectReference{fo.Reference()})
 			if err != nil {
-				return fmt.Errorf("could not move folder: %s", err)
+				return diag.Errorf("could not move folder: %s", err)
 			}
-			tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+			tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 			defer tcancel()
-			if err := task.WaitEx(tctx); err != nil {
-				return fmt.Errorf("error on waiting for move task completion: %s", err)
+			if err := viapi.WaitForTask(tctx, task); err != nil {
+				return diag.Errorf("error on waiting for move task completion: %s", err)
 			}
 		}
 	}
 
-	return resourceVSphereFolderRead(d, meta)
+	return resourceVSphereFolderRead(ctx, d, meta)
 }
 
-func resourceVSphereFolderDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	fo, err := folder.FromID(client, d.Id())
+	fo, err := folder.FromID(ctx, client, d.Id())
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return diag.Errorf("cannot locate folder: %s", err)
 	}
 
 	// We don't destroy if the folder has children. This might be flaggable in
 	// the future, but I don't think it's necessary at this point in time -
 	// better to have hardcoded safe behavior than hardcoded unsafe behavior.
-	ne, err := folder.HasChildren(fo)
+	ne, err := folder.HasChildren(ctx, fo)
 	if err != nil {
-		return fmt.Errorf("error checking for folder contents: %s", err)
+		return diag.Errorf("error checking for folder contents: %s", err)
 	}
 	if ne {
-		return errors.New("folder is not empty, please remove all items before deleting")
+		return diag.FromErr(errors.New("folder is not empty, please remove all items before deleting"))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := fo.Destroy(ctx)
 	if err != nil {
-		return fmt.Errorf("cannot delete folder: %s", err)
+		return diag.Errorf("cannot delete folder: %s", err)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	if err := task.WaitEx(tctx); err != nil {
-		return fmt.Errorf("error on waiting for deletion task completion: %s", err)
+	if err := viapi.WaitForTask(tctx, task); err != nil {
+		return diag.Errorf("error on waiting for deletion task completion: %s", err)
 	}
 
 	return nil
 }
 
-func resourceVSphereFolderImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereFolderImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// Our subject is the full path to a specific targetFolder, for which we just get
 	// the MOID for and then pass off to Read. Easy peasy.
 	p := d.Id()
@@ -311,7 +318,7 @@ func resourceVSphereFolderImport(d *schema.ResourceData, meta interface{}) ([]*s
 	}
 	client := meta.(*Client).vimClient
 	p = folder.NormalizePath(p)
-	targetFolder, err := folder.FromAbsolutePath(client, p)
+	targetFolder, err := folder.FromAbsolutePath(ctx, client, p)
 	if err != nil {
 		return nil, err
 	}
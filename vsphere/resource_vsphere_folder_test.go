@@ -523,7 +523,7 @@ func testAccResourceVSphereFolderHasType(expected folder.VSphereFolderType) reso
 		if err != nil {
 			return err
 		}
-		actual, err := folder.FindType(f)
+		actual, err := folder.FindType(context.Background(), f)
 		if err != nil {
 			return err
 		}
@@ -544,11 +544,11 @@ func testAccResourceVSphereFolderHasParent(expectedRoot bool, expectedName strin
 			return fmt.Errorf("folder %q is a root folder", props.Name)
 		}
 		client := testAccProvider.Meta().(*Client).vimClient
-		pfolder, err := folder.FromID(client, props.Parent.Value)
+		pfolder, err := folder.FromID(context.Background(), client, props.Parent.Value)
 		if err != nil {
 			return err
 		}
-		pprops, err := folder.Properties(pfolder)
+		pprops, err := folder.Properties(context.Background(), pfolder)
 		if err != nil {
 			return err
 		}
@@ -7,7 +7,9 @@ package vsphere
 import (
 	"context"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/object"
@@ -17,28 +19,34 @@ import (
 
 func resourceVSphereGuestOsCustomization() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereGuestOsCustomizationCreate,
-		Read:   resourceVSphereGuestOsCustomizationRead,
-		Update: resourceVSphereGuestOsCustomizationUpdate,
-		Delete: resourceVSphereGuestOsCustomizationDelete,
+		CreateContext: resourceVSphereGuestOsCustomizationCreate,
+		ReadContext:   resourceVSphereGuestOsCustomizationRead,
+		UpdateContext: resourceVSphereGuestOsCustomizationUpdate,
+		DeleteContext: resourceVSphereGuestOsCustomizationDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: getSchema(),
 	}
 }
 
-func resourceVSphereGuestOsCustomizationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereGuestOsCustomizationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	specItem, err := guestoscustomizations.FromName(client, d.Id())
+	specItem, err := guestoscustomizations.FromName(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return guestoscustomizations.FlattenGuestOsCustomizationSpec(d, specItem, client)
+	return diag.FromErr(guestoscustomizations.FlattenGuestOsCustomizationSpec(d, specItem, client))
 }
 
-func resourceVSphereGuestOsCustomizationCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereGuestOsCustomizationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning creation of customization specification %s", d.Get("name"))
 	client := meta.(*Client).vimClient
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	csm := object.NewCustomizationSpecManager(client.Client)
@@ -46,7 +54,7 @@ func resourceVSphereGuestOsCustomizationCreate(d *schema.ResourceData, meta inte
 	spec, err := guestoscustomizations.ExpandGuestOsCustomizationSpec(d, client)
 	if err != nil {
 		log.Printf("[ERROR] Error creating customization specification %s expansion: %s", d.Get("name"), err.Error())
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] Successfully expanded customization specification %s", d.Get("name"))
 
@@ -54,18 +62,18 @@ func resourceVSphereGuestOsCustomizationCreate(d *schema.ResourceData, meta inte
 	if err == nil {
 		log.Printf("[DEBUG] Successfully created customization specification %s", d.Get("name"))
 		d.SetId(spec.Info.Name)
-		return resourceVSphereGuestOsCustomizationRead(d, meta)
+		return resourceVSphereGuestOsCustomizationRead(ctx, d, meta)
 	}
 
 	log.Printf("[ERROR] Error creating customization specification %s:  %s ", d.Get("name"), err.Error())
 
-	return err
+	return diag.FromErr(err)
 }
 
-func resourceVSphereGuestOsCustomizationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereGuestOsCustomizationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Updating customization specification %s", d.Get("name"))
 	client := meta.(*Client).vimClient
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	csm := object.NewCustomizationSpecManager(client.Client)
 
@@ -75,7 +83,7 @@ func resourceVSphereGuestOsCustomizationUpdate(d *schema.ResourceData, meta inte
 		err := csm.RenameCustomizationSpec(ctx, oldName.(string), newName.(string))
 		if err != nil {
 			log.Printf("[ERROR] Renaming customization specification %s to %s: %s", oldName, newName, err.Error())
-			return err
+			return diag.FromErr(err)
 		}
 
 		log.Printf("[DEBUG] Successfully renamed customization specification %s to %s. Reseting the ID", oldName, newName)
@@ -85,14 +93,14 @@ func resourceVSphereGuestOsCustomizationUpdate(d *schema.ResourceData, meta inte
 	spec, err := guestoscustomizations.ExpandGuestOsCustomizationSpec(d, client)
 	if err != nil {
 		log.Printf("[ERROR] Error expanding the customization specification %s: %s ", d.Get("name"), err.Error())
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] Updating customization specification %s", d.Get("name").(string))
 	err = csm.OverwriteCustomizationSpec(ctx, *spec)
 	if err != nil {
 		log.Printf("[ERROR] Error updating customization specification %s: %s", d.Get("name").(string), err.Error())
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] Successfully updated customization specification %s", d.Get("name").(string))
@@ -100,12 +108,12 @@ func resourceVSphereGuestOsCustomizationUpdate(d *schema.ResourceData, meta inte
 	return nil
 }
 
-func resourceVSphereGuestOsCustomizationDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereGuestOsCustomizationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	csm := object.NewCustomizationSpecManager(client.Client)
-	return csm.DeleteCustomizationSpec(ctx, d.Id())
+	return diag.FromErr(csm.DeleteCustomizationSpec(ctx, d.Id()))
 }
 
 func getSchema() map[string]*schema.Schema {
@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -71,6 +72,28 @@ func TestAccResourceVSphereGOSC_linux(t *testing.T) {
 	})
 }
 
+func TestAccResourceVSphereGOSC_cloudConfig(t *testing.T) {
+	goscName := acctest.RandomWithPrefix("lin")
+	goscResourceName := acctest.RandomWithPrefix("gosc")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccGOSCExists(goscResourceName, goscName, false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGOSCCloudConfig(goscResourceName, goscName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGOSCExists(goscResourceName, goscName, true),
+					resource.TestCheckResourceAttr(fmt.Sprintf("vsphere_guest_os_customization.%s", goscResourceName), "spec.0.cloud_config.0.metadata", "instance-id: cloud-vm\nlocal-hostname: cloud-vm\n"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceVSphereGOSC_sysprep(t *testing.T) {
 	goscName := acctest.RandomWithPrefix("lin")
 	goscResourceName := acctest.RandomWithPrefix("gosc")
@@ -97,7 +120,7 @@ func testAccGOSCExists(resourceName string, goscName string, expectToExist bool)
 		if err != nil {
 			return err
 		}
-		_, err = guestoscustomizations.FromName(vars.client, goscName)
+		_, err = guestoscustomizations.FromName(context.Background(), vars.client, goscName)
 		if err != nil && expectToExist {
 			return err
 		}
@@ -179,3 +202,21 @@ func testAccGOSCLinux(resourceName string, goscName string) string {
 		goscName,
 	)
 }
+
+func testAccGOSCCloudConfig(resourceName string, goscName string) string {
+	return fmt.Sprintf(`
+		resource "vsphere_guest_os_customization" %q {
+			name = %q
+			type = "Linux"
+			spec {
+				cloud_config {
+					metadata = "instance-id: cloud-vm\nlocal-hostname: cloud-vm\n"
+					userdata = "#cloud-config\npackages:\n  - nginx\n"
+				}
+			}
+		}
+	`,
+		resourceName,
+		goscName,
+	)
+}
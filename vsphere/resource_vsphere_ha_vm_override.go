@@ -5,12 +5,15 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -64,12 +67,18 @@ var vmOverrideClusterVMReactionOnAPDClearedAllowedValues = []string{
 
 func resourceVSphereHAVMOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereHAVMOverrideCreate,
-		Read:   resourceVSphereHAVMOverrideRead,
-		Update: resourceVSphereHAVMOverrideUpdate,
-		Delete: resourceVSphereHAVMOverrideDelete,
+		CreateContext: resourceVSphereHAVMOverrideCreate,
+		ReadContext:   resourceVSphereHAVMOverrideRead,
+		UpdateContext: resourceVSphereHAVMOverrideUpdate,
+		DeleteContext: resourceVSphereHAVMOverrideDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereHAVMOverrideImport,
+			StateContext: resourceVSphereHAVMOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -178,17 +187,17 @@ func resourceVSphereHAVMOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereHAVMOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereHAVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereHAVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDasVMConfigInfo(d, meta, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DasVmConfigSpec: []types.ClusterDasVmConfigSpec{
@@ -201,31 +210,31 @@ func resourceVSphereHAVMOverrideCreate(d *schema.ResourceData, meta interface{})
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	id, err := resourceVSphereHAVMOverrideFlattenID(cluster, vm)
+	id, err := resourceVSphereHAVMOverrideFlattenID(ctx, cluster, vm)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.Errorf("cannot compute ID of created resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereHAVMOverrideIDString(d))
-	return resourceVSphereHAVMOverrideRead(d, meta)
+	return resourceVSphereHAVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereHAVMOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereHAVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereHAVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereHAVMOverrideFindEntry(cluster, vm)
+	info, err := resourceVSphereHAVMOverrideFindEntry(ctx, cluster, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -238,36 +247,36 @@ func resourceVSphereHAVMOverrideRead(d *schema.ResourceData, meta interface{}) e
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
 	}
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return fmt.Errorf("error getting properties of virtual machine: %s", err)
+		return diag.Errorf("error getting properties of virtual machine: %s", err)
 	}
 	if err = d.Set("virtual_machine_id", props.Config.Uuid); err != nil {
-		return fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
+		return diag.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
 	}
 
 	if err = flattenClusterDasVMConfigInfo(d, meta, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereHAVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereHAVMOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereHAVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereHAVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDasVMConfigInfo(d, meta, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DasVmConfigSpec: []types.ClusterDasVmConfigSpec{
@@ -283,20 +292,20 @@ func resourceVSphereHAVMOverrideUpdate(d *schema.ResourceData, meta interface{})
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereHAVMOverrideIDString(d))
-	return resourceVSphereHAVMOverrideRead(d, meta)
+	return resourceVSphereHAVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereHAVMOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereHAVMOverrideIDString(d))
 
-	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
+	cluster, vm, err := resourceVSphereHAVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -310,15 +319,15 @@ func resourceVSphereHAVMOverrideDelete(d *schema.ResourceData, meta interface{})
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereHAVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereHAVMOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereHAVMOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -337,17 +346,17 @@ func resourceVSphereHAVMOverrideImport(d *schema.ResourceData, meta interface{})
 		return nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromPath(client, clusterPath, nil)
+	cluster, err := clustercomputeresource.FromPath(ctx, client, clusterPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate cluster %q: %s", clusterPath, err)
 	}
 
-	vm, err := virtualmachine.FromPath(client, vmPath, nil)
+	vm, err := virtualmachine.FromPath(ctx, client, vmPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate virtual machine %q: %s", vmPath, err)
 	}
 
-	id, err := resourceVSphereHAVMOverrideFlattenID(cluster, vm)
+	id, err := resourceVSphereHAVMOverrideFlattenID(ctx, cluster, vm)
 	if err != nil {
 		return nil, fmt.Errorf("cannot compute ID of imported resource: %s", err)
 	}
@@ -405,9 +414,9 @@ func resourceVSphereHAVMOverrideIDString(d structure.ResourceIDStringer) string
 
 // resourceVSphereHAVMOverrideFlattenID makes an ID for the
 // vsphere_ha_vm_override resource.
-func resourceVSphereHAVMOverrideFlattenID(cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
+func resourceVSphereHAVMOverrideFlattenID(ctx context.Context, cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
 	clusterID := cluster.Reference().Value
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return "", fmt.Errorf("cannot compute ID off of properties of virtual machine: %s", err)
 	}
@@ -430,10 +439,11 @@ func resourceVSphereHAVMOverrideParseID(id string) (string, string, error) {
 // resource's read functionality and tests. nil is returned if the entry cannot
 // be found.
 func resourceVSphereHAVMOverrideFindEntry(
+	ctx context.Context,
 	cluster *object.ClusterComputeResource,
 	vm *object.VirtualMachine,
 ) (*types.ClusterDasVmConfigInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -455,20 +465,23 @@ func resourceVSphereHAVMOverrideFindEntry(
 // * If not, it's derived from the compute_cluster_id and virtual_machine_id
 // attributes.
 func resourceVSphereHAVMOverrideObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
 	if d.Id() != "" {
-		return resourceVSphereHAVMOverrideObjectsFromID(d, meta)
+		return resourceVSphereHAVMOverrideObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereHAVMOverrideObjectsFromAttributes(d, meta)
+	return resourceVSphereHAVMOverrideObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereHAVMOverrideObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
 	return resourceVSphereHAVMOverrideFetchObjects(
+		ctx,
 		meta,
 		d.Get("compute_cluster_id").(string),
 		d.Get("virtual_machine_id").(string),
@@ -476,6 +489,7 @@ func resourceVSphereHAVMOverrideObjectsFromAttributes(
 }
 
 func resourceVSphereHAVMOverrideObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.ClusterComputeResource, *object.VirtualMachine, error) {
@@ -487,10 +501,11 @@ func resourceVSphereHAVMOverrideObjectsFromID(
 		return nil, nil, err
 	}
 
-	return resourceVSphereHAVMOverrideFetchObjects(meta, clusterID, vmID)
+	return resourceVSphereHAVMOverrideFetchObjects(ctx, meta, clusterID, vmID)
 }
 
 func resourceVSphereHAVMOverrideFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	clusterID string,
 	vmID string,
@@ -500,12 +515,12 @@ func resourceVSphereHAVMOverrideFetchObjects(
 		return nil, nil, err
 	}
 
-	cluster, err := clustercomputeresource.FromID(client, clusterID)
+	cluster, err := clustercomputeresource.FromID(ctx, client, clusterID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate cluster: %s", err)
 	}
 
-	vm, err := virtualmachine.FromUUID(client, vmID)
+	vm, err := virtualmachine.FromUUID(ctx, client, vmID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate virtual machine: %s", err)
 	}
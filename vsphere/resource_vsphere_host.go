@@ -7,6 +7,7 @@ package vsphere
 import (
 	"bytes"
 	"context"
+	"time"
 
 	// TODO: Transition to crypto/sha256 in next major release.
 	"crypto/sha1" //nolint
@@ -14,13 +15,12 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/license"
 	"github.com/vmware/govmomi/object"
-	"github.com/vmware/govmomi/property"
-	gtask "github.com/vmware/govmomi/task"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -42,12 +42,18 @@ var servicesPolicyAllowedValues = []string{
 
 func resourceVsphereHost() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVsphereHostCreate,
-		Read:   resourceVsphereHostRead,
-		Update: resourceVsphereHostUpdate,
-		Delete: resourceVsphereHostDelete,
+		CreateContext: resourceVsphereHostCreate,
+		ReadContext:   resourceVsphereHostRead,
+		UpdateContext: resourceVsphereHostUpdate,
+		DeleteContext: resourceVsphereHostDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"datacenter": {
@@ -162,29 +168,29 @@ func resourceVsphereHost() *schema.Resource {
 	}
 }
 
-func resourceVsphereHostCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	err := validateFields(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	client := meta.(*Client).vimClient
 
 	hcs, err := buildHostConnectSpec(d)
 	if err != nil {
-		return fmt.Errorf("failed to build host connect spec: %v", err)
+		return diag.Errorf("failed to build host connect spec: %v", err)
 	}
 
 	licenseKey := d.Get("license").(string)
 
 	if licenseKey != "" {
-		licFound, err := licenseExists(client.Client, licenseKey)
+		licFound, err := licenseExists(ctx, client.Client, licenseKey)
 		if err != nil {
-			return fmt.Errorf("error while looking for license key. Error: %s", err)
+			return diag.Errorf("error while looking for license key. Error: %s", err)
 		}
 
 		if !licFound {
-			return fmt.Errorf("license key supplied (%s) did not match against known license keys", licenseKey)
+			return diag.Errorf("license key supplied (%s) did not match against known license keys", licenseKey)
 		}
 	}
 
@@ -199,40 +205,39 @@ func resourceVsphereHostCreate(d *schema.ResourceData, meta interface{}) error {
 	var task *object.Task
 	clusterID := d.Get("cluster").(string)
 	if clusterID != "" {
-		ccr, err := clustercomputeresource.FromID(client, clusterID)
+		ccr, err := clustercomputeresource.FromID(ctx, client, clusterID)
 		if err != nil {
-			return fmt.Errorf("error while searching cluster %s. Error: %s", clusterID, err)
+			return diag.Errorf("error while searching cluster %s. Error: %s", clusterID, err)
 		}
 
-		task, err = ccr.AddHost(context.TODO(), hcs, connectedState, &licenseKey, nil)
+		task, err = ccr.AddHost(ctx, hcs, connectedState, &licenseKey, nil)
 		if err != nil {
-			return fmt.Errorf("error while adding host with hostname %s to cluster %s.  Error: %s", d.Get("hostname").(string), clusterID, err)
+			return diag.Errorf("error while adding host with hostname %s to cluster %s.  Error: %s", d.Get("hostname").(string), clusterID, err)
 		}
 	} else {
 		dcID := d.Get("datacenter").(string)
-		dc, err := datacenterFromID(client, dcID)
+		dc, err := datacenterFromID(ctx, client, dcID)
 		if err != nil {
-			return fmt.Errorf("error while retrieving datacenter object for datacenter: %s. Error: %s", dcID, err)
+			return diag.Errorf("error while retrieving datacenter object for datacenter: %s. Error: %s", dcID, err)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 		defer cancel()
 		var dcProps mo.Datacenter
 		if err := dc.Properties(ctx, dc.Reference(), nil, &dcProps); err != nil {
-			return fmt.Errorf("error while retrieving properties for datacenter %s. Error: %s", dcID, err)
+			return diag.Errorf("error while retrieving properties for datacenter %s. Error: %s", dcID, err)
 		}
 
 		hostFolder := object.NewFolder(client.Client, dcProps.HostFolder)
-		task, err = hostFolder.AddStandaloneHost(context.TODO(), hcs, connectedState, &licenseKey, nil)
+		task, err = hostFolder.AddStandaloneHost(ctx, hcs, connectedState, &licenseKey, nil)
 		if err != nil {
-			return fmt.Errorf("error while adding standalone host %s. Error: %s", hcs.HostName, err)
+			return diag.Errorf("error while adding standalone host %s. Error: %s", hcs.HostName, err)
 		}
 	}
 
-	p := property.DefaultCollector(client.Client)
-	res, err := gtask.WaitEx(context.TODO(), task.Reference(), p, nil)
+	res, err := viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
-		return fmt.Errorf("host addition failed. %s", err)
+		return diag.Errorf("host addition failed. %s", err)
 	}
 	taskResult := res.Result
 
@@ -241,84 +246,84 @@ func resourceVsphereHostCreate(d *schema.ResourceData, meta interface{}) error {
 	switch taskResultType {
 	case "ComputeResource":
 		computeResource := object.NewComputeResource(client.Client, taskResult.(types.ManagedObjectReference))
-		crHosts, err := computeResource.Hosts(context.TODO())
+		crHosts, err := computeResource.Hosts(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to retrieve created computeResource Hosts. Error: %s", err)
+			return diag.Errorf("failed to retrieve created computeResource Hosts. Error: %s", err)
 		}
 		hostID = crHosts[0].Reference().Value
 		log.Printf("[DEBUG] standalone hostID: %s", hostID)
 	case "HostSystem":
 		hostID = taskResult.(types.ManagedObjectReference).Value
 	default:
-		return fmt.Errorf("unexpected task result type encountered. Got %s while waiting ComputeResourceType or Hostsystem", taskResultType)
+		return diag.Errorf("unexpected task result type encountered. Got %s while waiting ComputeResourceType or Hostsystem", taskResultType)
 	}
 	log.Printf("[DEBUG] Host added with ID %s", hostID)
 	d.SetId(hostID)
 
-	host, err := hostsystem.FromID(client, hostID)
+	host, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
-		return fmt.Errorf("failed while retrieving host object for host %s. Error: %s", hostID, err)
+		return diag.Errorf("failed while retrieving host object for host %s. Error: %s", hostID, err)
 	}
 
 	// Load the tags client to validate the vCenter Sever connection before
 	// attempting to proceed if tags have been defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Verify the vCenter Server connection before
 	// attempting to proceed if custom attributes have been defined.
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Apply tags
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, host); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, host); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	// Apply custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(host); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, host); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	lockdownModeString := d.Get("lockdown").(string)
 	lockdownMode, err := hostLockdownType(lockdownModeString)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if connectedState {
-		hostProps, err := hostsystem.Properties(host)
+		hostProps, err := hostsystem.Properties(ctx, host)
 		if err != nil {
-			return fmt.Errorf("error while retrieving properties for host %s. Error: %s", hostID, err)
+			return diag.Errorf("error while retrieving properties for host %s. Error: %s", hostID, err)
 		}
 
 		hamRef := hostProps.ConfigManager.HostAccessManager.Reference()
 		ham := NewHostAccessManager(client.Client, hamRef)
-		err = ham.ChangeLockdownMode(context.TODO(), lockdownMode)
+		err = ham.ChangeLockdownMode(ctx, lockdownMode)
 		if err != nil {
-			return fmt.Errorf("error while changing lockdown mode for host %s. Error: %s", hostID, err)
+			return diag.Errorf("error while changing lockdown mode for host %s. Error: %s", hostID, err)
 		}
 	}
 
 	maintenanceMode := d.Get("maintenance").(bool)
 	if maintenanceMode {
-		err = hostsystem.EnterMaintenanceMode(host, provider.DefaultAPITimeout, true)
+		err = hostsystem.EnterMaintenanceMode(ctx, host, provider.DefaultAPITimeout, true)
 	} else {
-		err = hostsystem.ExitMaintenanceMode(host, provider.DefaultAPITimeout)
+		err = hostsystem.ExitMaintenanceMode(ctx, host, provider.DefaultAPITimeout)
 	}
 	if err != nil {
-		return fmt.Errorf("error while toggling maintenance mode for host %s. Error: %s", hostID, err)
+		return diag.Errorf("error while toggling maintenance mode for host %s. Error: %s", hostID, err)
 	}
 
-	mutableKeys := map[string]func(*schema.ResourceData, interface{}, interface{}, interface{}) error{
+	mutableKeys := map[string]func(context.Context, *schema.ResourceData, interface{}, interface{}, interface{}) error{
 		"services": resourceVSphereHostUpdateServices,
 	}
 	for k, v := range mutableKeys {
@@ -328,16 +333,16 @@ func resourceVsphereHostCreate(d *schema.ResourceData, meta interface{}) error {
 		}
 		log.Printf("[DEBUG] Key %s has change, processing", k)
 		old, newVal := d.GetChange(k)
-		err := v(d, meta, old, newVal)
+		err := v(ctx, d, meta, old, newVal)
 		if err != nil {
-			return fmt.Errorf("error while updating %s: %s", k, err)
+			return diag.Errorf("error while updating %s: %s", k, err)
 		}
 	}
 
-	return resourceVsphereHostRead(d, meta)
+	return resourceVsphereHostRead(ctx, d, meta)
 }
 
-func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// NOTE: Destroying the host without telling vsphere about it will result in us not
 	// knowing that the host does not exist any more.
 
@@ -346,30 +351,29 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 	hostID := d.Id()
 
 	// Find host and get reference to it.
-	hs, err := hostsystem.FromID(client, hostID)
+	hs, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		if viapi.IsManagedObjectNotFoundError(err) {
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("error while searching host %s. Error: %s ", hostID, err)
+		return diag.Errorf("error while searching host %s. Error: %s ", hostID, err)
 	}
 
-	ctx := context.TODO()
 	serviceKey := "ntpd"
 	ntpServers, err := readHostNtpServerConfig(ctx, client, hs)
 	if err != nil {
-		return fmt.Errorf("error while reading NTP configuration for host: %s", err)
+		return diag.Errorf("error while reading NTP configuration for host: %s", err)
 	}
 
 	policyConfig, err := readHostServicePolicy(ctx, client, hs, serviceKey)
 	if err != nil {
-		return fmt.Errorf("error while reading policy configuration for host: %s", err)
+		return diag.Errorf("error while reading policy configuration for host: %s", err)
 	}
 
 	serviceEnabled, err := readHostServiceStatus(ctx, client, hs, serviceKey)
 	if err != nil {
-		return fmt.Errorf("error while reading service status for host: %s", err)
+		return diag.Errorf("error while reading service status for host: %s", err)
 	}
 
 	ntpdService := map[string]interface{}{
@@ -384,20 +388,20 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	// Set this structure under the "services" key in the resource data
 	if err := d.Set("services", []interface{}{ntpdService}); err != nil {
-		return fmt.Errorf("error setting services: %s", err)
+		return diag.Errorf("error setting services: %s", err)
 	}
 
-	maintenanceState, err := hostsystem.HostInMaintenance(hs)
+	maintenanceState, err := hostsystem.HostInMaintenance(ctx, hs)
 	if err != nil {
-		return fmt.Errorf("error while checking maintenance status for host %s. Error: %s", hostID, err)
+		return diag.Errorf("error while checking maintenance status for host %s. Error: %s", hostID, err)
 	}
 	_ = d.Set("maintenance", maintenanceState)
 
 	// Retrieve host's properties.
 	log.Printf("[DEBUG] Got host %s", hs.String())
-	host, err := hostsystem.Properties(hs)
+	host, err := hostsystem.Properties(ctx, hs)
 	if err != nil {
-		return fmt.Errorf("error while retrieving properties for host %s. Error: %s", hostID, err)
+		return diag.Errorf("error while retrieving properties for host %s. Error: %s", hostID, err)
 	}
 
 	if host.Parent != nil && host.Parent.Type == "ClusterComputeResource" && !d.Get("cluster_managed").(bool) {
@@ -406,9 +410,9 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 		_ = d.Set("cluster", "")
 	}
 
-	connectionState, err := hostsystem.GetConnectionState(hs)
+	connectionState, err := hostsystem.GetConnectionState(ctx, hs)
 	if err != nil {
-		return fmt.Errorf("error while getting connection state for host %s. Error: %s", hostID, err)
+		return diag.Errorf("error while getting connection state for host %s. Error: %s", hostID, err)
 	}
 
 	if connectionState == types.HostSystemConnectionStateDisconnected {
@@ -421,7 +425,7 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	lockdownMode, err := hostLockdownString(host.Config.LockdownMode)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("Setting lockdown to %s", lockdownMode)
@@ -429,9 +433,9 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	licenseKey := d.Get("license").(string)
 	if licenseKey != "" {
-		licFound, err := isLicenseAssigned(client.Client, hostID, licenseKey)
+		licFound, err := isLicenseAssigned(ctx, client.Client, hostID, licenseKey)
 		if err != nil {
-			return fmt.Errorf("error while checking license assignment for host %s. Error: %s", hostID, err)
+			return diag.Errorf("error while checking license assignment for host %s. Error: %s", hostID, err)
 		}
 
 		if !licFound {
@@ -441,16 +445,16 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	// Read tags
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, host, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, host, d); err != nil {
+			return diag.Errorf("error reading tags: %s", err)
 		}
 	}
 
 	// Read custom attributes
 	if customattribute.IsSupported(client) {
-		moHost, err := hostsystem.Properties(hs)
+		moHost, err := hostsystem.Properties(ctx, hs)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		customattribute.ReadFromResource(moHost.Entity(), d)
 	}
@@ -458,22 +462,22 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	err := validateFields(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	client := meta.(*Client).vimClient
 
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// First let's establish where we are and where we want to go
@@ -486,14 +490,14 @@ func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	hostID := d.Id()
-	hostObject, err := hostsystem.FromID(client, hostID)
+	hostObject, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
-		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
+		return diag.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
 	}
 
-	actualConnectionState, err := hostsystem.GetConnectionState(hostObject)
+	actualConnectionState, err := hostsystem.GetConnectionState(ctx, hostObject)
 	if err != nil {
-		return fmt.Errorf("error while retrieving connection state for host %s. Error: %s", hostID, err)
+		return diag.Errorf("error while retrieving connection state for host %s. Error: %s", hostID, err)
 	}
 
 	// Have there been any changes that warrant a reconnect?
@@ -509,25 +513,25 @@ func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
 	// Decide if we're going to reconnect or not
 	reconnectNeeded, err := shouldReconnect(d, meta, actualConnectionState, desiredConnectionState, reconnect)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	switch reconnectNeeded {
 	case 1:
-		err := resourceVSphereHostReconnect(d, meta)
+		err := resourceVSphereHostReconnect(ctx, d, meta)
 		if err != nil {
-			return fmt.Errorf("error while reconnecting host %s. Error: %s", hostID, err)
+			return diag.Errorf("error while reconnecting host %s. Error: %s", hostID, err)
 		}
 	case -1:
-		err := resourceVSphereHostDisconnect(d, meta)
+		err := resourceVSphereHostDisconnect(ctx, d, meta)
 		if err != nil {
-			return fmt.Errorf("error while disconnecting host %s. Error: %s", hostID, err)
+			return diag.Errorf("error while disconnecting host %s. Error: %s", hostID, err)
 		}
 	case 0:
 		break
 	}
 
-	mutableKeys := map[string]func(*schema.ResourceData, interface{}, interface{}, interface{}) error{
+	mutableKeys := map[string]func(context.Context, *schema.ResourceData, interface{}, interface{}, interface{}) error{
 		"license":     resourceVSphereHostUpdateLicense,
 		"cluster":     resourceVSphereHostUpdateCluster,
 		"maintenance": resourceVSphereHostUpdateMaintenanceMode,
@@ -542,54 +546,54 @@ func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 		log.Printf("[DEBUG] Key %s has change, processing", k)
 		old, newVal := d.GetChange(k)
-		err := v(d, meta, old, newVal)
+		err := v(ctx, d, meta, old, newVal)
 		if err != nil {
-			return fmt.Errorf("error while updating %s: %s", k, err)
+			return diag.Errorf("error while updating %s: %s", k, err)
 		}
 	}
 
 	// Apply tags
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, hostObject); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(ctx, tagsClient, d, hostObject); err != nil {
+			return diag.Errorf("error updating tags: %s", err)
 		}
 	}
 
 	// Apply custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(hostObject); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, hostObject); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceVsphereHostRead(d, meta)
+	return resourceVsphereHostRead(ctx, d, meta)
 }
 
-func resourceVsphereHostDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
 
-	hs, err := hostsystem.FromID(client, hostID)
+	hs, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
-		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
+		return diag.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
 	}
 
-	connectionState, err := hostsystem.GetConnectionState(hs)
+	connectionState, err := hostsystem.GetConnectionState(ctx, hs)
 	if err != nil {
-		return fmt.Errorf("error while retrieving connection state for host %s. Error: %s", hostID, err)
+		return diag.Errorf("error while retrieving connection state for host %s. Error: %s", hostID, err)
 	}
 
 	if connectionState != types.HostSystemConnectionStateDisconnected {
 		// We cannot put a disconnected server in maintenance mode.
-		err = resourceVSphereHostDisconnect(d, meta)
+		err = resourceVSphereHostDisconnect(ctx, d, meta)
 		if err != nil {
-			return fmt.Errorf("error while disconnecting host: %s", err.Error())
+			return diag.Errorf("error while disconnecting host: %s", err.Error())
 		}
 	}
 
-	hostProps, err := hostsystem.Properties(hs)
+	hostProps, err := hostsystem.Properties(ctx, hs)
 	if err != nil {
-		return fmt.Errorf("error while retrieving properties fort host %s. Error: %s", hostID, err)
+		return diag.Errorf("error while retrieving properties fort host %s. Error: %s", hostID, err)
 	}
 
 	// If this is a standalone host we need to destroy the ComputeResource object
@@ -597,28 +601,27 @@ func resourceVsphereHostDelete(d *schema.ResourceData, meta interface{}) error {
 	var task *object.Task
 	if hostProps.Parent.Type == "ComputeResource" {
 		cr := object.NewComputeResource(client.Client, *hostProps.Parent)
-		task, err = cr.Destroy(context.TODO())
+		task, err = cr.Destroy(ctx)
 		if err != nil {
-			return fmt.Errorf("error while submitting destroy task for compute resource %s. Error: %s", hostProps.Parent.Value, err)
+			return diag.Errorf("error while submitting destroy task for compute resource %s. Error: %s", hostProps.Parent.Value, err)
 		}
 	} else {
-		task, err = hs.Destroy(context.TODO())
+		task, err = hs.Destroy(ctx)
 		if err != nil {
-			return fmt.Errorf("error while submitting destroy task for host system %s. Error: %s", hostProps.Parent.Value, err)
+			return diag.Errorf("error while submitting destroy task for host system %s. Error: %s", hostProps.Parent.Value, err)
 		}
 	}
-	p := property.DefaultCollector(client.Client)
-	_, err = gtask.WaitEx(context.TODO(), task.Reference(), p, nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
-		return fmt.Errorf("error while waiting for host (%s) to be removed: %s", hostID, err)
+		return diag.Errorf("error while waiting for host (%s) to be removed: %s", hostID, err)
 	}
 	return nil
 }
 
-func resourceVSphereHostUpdateLockdownMode(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateLockdownMode(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
-	host, err := hostsystem.FromID(client, hostID)
+	host, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
 	}
@@ -629,14 +632,14 @@ func resourceVSphereHostUpdateLockdownMode(d *schema.ResourceData, meta, _, newV
 	}
 
 	var hostProps mo.HostSystem
-	err = host.Properties(context.TODO(), host.ConfigManager().Reference(), []string{"configManager.hostAccessManager"}, &hostProps)
+	err = host.Properties(ctx, host.ConfigManager().Reference(), []string{"configManager.hostAccessManager"}, &hostProps)
 	if err != nil {
 		return fmt.Errorf("error while retrieving HostSystem properties for host ID %s. Error: %s", hostID, err)
 	}
 
 	hamRef := hostProps.ConfigManager.HostAccessManager.Reference()
 	ham := NewHostAccessManager(client.Client, hamRef)
-	err = ham.ChangeLockdownMode(context.TODO(), lockdownMode)
+	err = ham.ChangeLockdownMode(ctx, lockdownMode)
 	if err != nil {
 		return fmt.Errorf("error while changing lonckdown mode for host ID %s to %s. Error: %s", hostID, lockdownMode, err)
 	}
@@ -644,20 +647,20 @@ func resourceVSphereHostUpdateLockdownMode(d *schema.ResourceData, meta, _, newV
 	return nil
 }
 
-func resourceVSphereHostUpdateMaintenanceMode(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateMaintenanceMode(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
 
-	host, err := hostsystem.FromID(client, hostID)
+	host, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
 	}
 
 	maintenanceMode := newVal.(bool)
 	if maintenanceMode {
-		err = hostsystem.EnterMaintenanceMode(host, provider.DefaultAPITimeout, true)
+		err = hostsystem.EnterMaintenanceMode(ctx, host, provider.DefaultAPITimeout, true)
 	} else {
-		err = hostsystem.ExitMaintenanceMode(host, provider.DefaultAPITimeout)
+		err = hostsystem.ExitMaintenanceMode(ctx, host, provider.DefaultAPITimeout)
 	}
 	if err != nil {
 		return fmt.Errorf("error while toggling maintenance mode for host %s. Error: %s", host.Name(), err)
@@ -665,51 +668,50 @@ func resourceVSphereHostUpdateMaintenanceMode(d *schema.ResourceData, meta, _, n
 	return nil
 }
 
-func resourceVSphereHostUpdateLicense(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateLicense(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	lm := license.NewManager(client.Client)
-	lam, err := lm.AssignmentManager(context.TODO())
+	lam, err := lm.AssignmentManager(ctx)
 	if err != nil {
 		return fmt.Errorf("error while accessing License Assignment Manager endpoint. Error: %s", err)
 	}
-	_, err = lam.Update(context.TODO(), d.Id(), newVal.(string), "")
+	_, err = lam.Update(ctx, d.Id(), newVal.(string), "")
 	if err != nil {
 		return fmt.Errorf("error while updating license. error: %s", err)
 	}
 	return nil
 }
 
-func resourceVSphereHostUpdateCluster(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateCluster(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
 	newClusterID := newVal.(string)
 
-	newCluster, err := clustercomputeresource.FromID(client, newClusterID)
+	newCluster, err := clustercomputeresource.FromID(ctx, client, newClusterID)
 	if err != nil {
 		return fmt.Errorf("error while searching newVal cluster %s. Error: %s", newClusterID, err)
 	}
 
-	hs, err := hostsystem.FromID(client, hostID)
+	hs, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
 	}
 
-	err = hostsystem.EnterMaintenanceMode(hs, provider.DefaultAPITimeout, true)
+	err = hostsystem.EnterMaintenanceMode(ctx, hs, provider.DefaultAPITimeout, true)
 	if err != nil {
 		return fmt.Errorf("error while putting host to maintenance mode: %s", err.Error())
 	}
 
-	task, err := newCluster.MoveInto(context.TODO(), hs)
+	task, err := newCluster.MoveInto(ctx, hs)
 	if err != nil {
 		return fmt.Errorf("error while moving HostSystem with ID %s to new cluster. Error: %s", hostID, err)
 	}
-	p := property.DefaultCollector(client.Client)
-	_, err = gtask.WaitEx(context.TODO(), task.Reference(), p, nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		return fmt.Errorf("error while moving host to new cluster (%s): %s", newClusterID, err)
 	}
 
-	err = hostsystem.ExitMaintenanceMode(hs, provider.DefaultAPITimeout)
+	err = hostsystem.ExitMaintenanceMode(ctx, hs, provider.DefaultAPITimeout)
 	if err != nil {
 		return fmt.Errorf("error while taking host out of maintenance mode: %s", err.Error())
 	}
@@ -717,11 +719,11 @@ func resourceVSphereHostUpdateCluster(d *schema.ResourceData, meta, _, newVal in
 	return nil
 }
 
-func resourceVSphereHostUpdateThumbprint(d *schema.ResourceData, meta, _, _ interface{}) error {
-	return resourceVSphereHostReconnect(d, meta)
+func resourceVSphereHostUpdateThumbprint(ctx context.Context, d *schema.ResourceData, meta, _, _ interface{}) error {
+	return resourceVSphereHostReconnect(ctx, d, meta)
 }
 
-func resourceVSphereHostReconnect(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostReconnect(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	hostID := d.Id()
 	client := meta.(*Client).vimClient
 	host := object.NewHostSystem(client.Client, types.ManagedObjectReference{Type: "HostSystem", Value: d.Id()})
@@ -730,25 +732,24 @@ func resourceVSphereHostReconnect(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("failed to build host connect spec: %v", err)
 	}
 
-	task, err := host.Reconnect(context.TODO(), &hcs, nil)
+	task, err := host.Reconnect(ctx, &hcs, nil)
 	if err != nil {
 		return fmt.Errorf("error while reconnecting host with ID %s. Error: %s", hostID, err)
 	}
 
-	p := property.DefaultCollector(client.Client)
-	_, err = gtask.WaitEx(context.TODO(), task.Reference(), p, nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		return fmt.Errorf("error while reconnecting host(%s): %s", hostID, err)
 	}
 
-	maintenanceState, err := hostsystem.HostInMaintenance(host)
+	maintenanceState, err := hostsystem.HostInMaintenance(ctx, host)
 	if err != nil {
 		return fmt.Errorf("error while retrieving host maintenance status for host %s. Error: %s", host.Name(), err)
 	}
 
 	maintenanceConfig := d.Get("maintenance").(bool)
 	if maintenanceState && !maintenanceConfig {
-		err := hostsystem.ExitMaintenanceMode(host, provider.DefaultAPITimeout)
+		err := hostsystem.ExitMaintenanceMode(ctx, host, provider.DefaultAPITimeout)
 		if err != nil {
 			return fmt.Errorf("error while taking host %s out of maintenance mode. Error: %s", host.Name(), err)
 		}
@@ -756,17 +757,16 @@ func resourceVSphereHostReconnect(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
-func resourceVSphereHostDisconnect(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostDisconnect(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	hostID := d.Id()
 	client := meta.(*Client).vimClient
 	host := object.NewHostSystem(client.Client, types.ManagedObjectReference{Type: "HostSystem", Value: d.Id()})
-	task, err := host.Disconnect(context.TODO())
+	task, err := host.Disconnect(ctx)
 	if err != nil {
 		return fmt.Errorf("error while disconnecting host %s. Error: %s", host.Name(), err)
 	}
 
-	p := property.DefaultCollector(client.Client)
-	_, err = gtask.WaitEx(context.TODO(), task.Reference(), p, nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		return fmt.Errorf("error while disconnecting host(%s): %s", hostID, err)
 	}
@@ -928,8 +928,7 @@ func getHostThumbprint(d *schema.ResourceData) (string, error) {
 	return buf.String(), nil
 }
 
-func isLicenseAssigned(client *vim25.Client, hostID, licenseKey string) (bool, error) {
-	ctx := context.TODO()
+func isLicenseAssigned(ctx context.Context, client *vim25.Client, hostID, licenseKey string) (bool, error) {
 	lm := license.NewManager(client)
 	am, err := lm.AssignmentManager(ctx)
 	if err != nil {
@@ -951,8 +950,7 @@ func isLicenseAssigned(client *vim25.Client, hostID, licenseKey string) (bool, e
 	return licFound, nil
 }
 
-func licenseExists(client *vim25.Client, licenseKey string) (bool, error) {
-	ctx := context.TODO()
+func licenseExists(ctx context.Context, client *vim25.Client, licenseKey string) (bool, error) {
 	lm := license.NewManager(client)
 	ll, err := lm.List(ctx)
 	if err != nil {
@@ -998,10 +996,10 @@ func (h HostAccessManager) ChangeLockdownMode(ctx context.Context, mode types.Ho
 	return err
 }
 
-func resourceVSphereHostUpdateServices(d *schema.ResourceData, meta interface{}, _, _ interface{}) error {
+func resourceVSphereHostUpdateServices(ctx context.Context, d *schema.ResourceData, meta interface{}, _, _ interface{}) error {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
-	hostObject, err := hostsystem.FromID(client, hostID)
+	hostObject, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
 	}
@@ -1024,7 +1022,7 @@ func resourceVSphereHostUpdateServices(d *schema.ResourceData, meta interface{},
 
 			// Start the NTP service if enabled
 			if enabled, ok := ntpdConfig["enabled"].(bool); ok && enabled {
-				err := StartHostService(context.Background(), client, hostObject, "ntpd")
+				err := StartHostService(ctx, client, hostObject, "ntpd")
 				if err != nil {
 					return fmt.Errorf("failed to start NTP service on host %s: %v", hostID, err)
 				}
@@ -1037,7 +1035,7 @@ func resourceVSphereHostUpdateServices(d *schema.ResourceData, meta interface{},
 				newServers[i] = server.(string)
 			}
 
-			err := changeHostNtpServers(context.Background(), hostObject, newServers)
+			err := changeHostNtpServers(ctx, hostObject, newServers)
 			if err != nil {
 				return fmt.Errorf("error while updating NTP servers for host %s. Error: %s", hostID, err)
 			}
@@ -1049,7 +1047,7 @@ func resourceVSphereHostUpdateServices(d *schema.ResourceData, meta interface{},
 
 			// Update the NTP service policy if applicable
 			if policy, ok := ntpdConfig["policy"].(string); ok {
-				err := UpdateHostServicePolicy(context.Background(), client, hostObject, "ntpd", policy)
+				err := UpdateHostServicePolicy(ctx, client, hostObject, "ntpd", policy)
 				if err != nil {
 					return fmt.Errorf("failed to update NTP service policy on host %s: %v", hostID, err)
 				}
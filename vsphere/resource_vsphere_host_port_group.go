@@ -6,8 +6,9 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
 )
@@ -46,114 +47,120 @@ func resourceVSphereHostPortGroup() *schema.Resource {
 	s["standby_nics"].Optional = true
 
 	return &schema.Resource{
-		Create: resourceVSphereHostPortGroupCreate,
-		Read:   resourceVSphereHostPortGroupRead,
-		Update: resourceVSphereHostPortGroupUpdate,
-		Delete: resourceVSphereHostPortGroupDelete,
+		CreateContext: resourceVSphereHostPortGroupCreate,
+		ReadContext:   resourceVSphereHostPortGroupRead,
+		UpdateContext: resourceVSphereHostPortGroupUpdate,
+		DeleteContext: resourceVSphereHostPortGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereHostPortGroupImport,
+			StateContext: resourceVSphereHostPortGroupImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereHostPortGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	name := d.Get("name").(string)
 	hsID := d.Get("host_system_id").(string)
-	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
+	ns, err := hostNetworkSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading network system: %s", err)
+		return diag.Errorf("error loading network system: %s", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	spec := expandHostPortGroupSpec(d)
 	if err := ns.AddPortGroup(ctx, *spec); err != nil {
-		return fmt.Errorf("error adding port group: %s", err)
+		return diag.Errorf("error adding port group: %s", err)
 	}
 
 	saveHostPortGroupID(d, hsID, name)
-	return resourceVSphereHostPortGroupRead(d, meta)
+	return resourceVSphereHostPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereHostPortGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := portGroupIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
+	ns, err := hostNetworkSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.Errorf("error loading host network system: %s", err)
 	}
 
-	pg, err := hostPortGroupFromName(meta.(*Client).vimClient, ns, name)
+	pg, err := hostPortGroupFromName(ctx, meta.(*Client).vimClient, ns, name)
 	if err != nil {
-		return fmt.Errorf("error fetching port group data: %s", err)
+		return diag.Errorf("error fetching port group data: %s", err)
 	}
 
 	if err := flattenHostPortGroupSpec(d, &pg.Spec); err != nil {
-		return fmt.Errorf("error setting resource data: %s", err)
+		return diag.Errorf("error setting resource data: %s", err)
 	}
 
 	_ = d.Set("key", pg.Key)
 	cpm, err := calculateComputedPolicy(pg.ComputedPolicy)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("computed_policy", cpm); err != nil {
-		return fmt.Errorf("error saving effective policy to state: %s", err)
+		return diag.Errorf("error saving effective policy to state: %s", err)
 	}
 	if err := d.Set("ports", calculatePorts(pg.Port)); err != nil {
-		return fmt.Errorf("error setting port list: %s", err)
+		return diag.Errorf("error setting port list: %s", err)
 	}
 
 	return nil
 }
 
-func resourceVSphereHostPortGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := portGroupIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
+	ns, err := hostNetworkSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.Errorf("error loading host network system: %s", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	spec := expandHostPortGroupSpec(d)
 	if err := ns.UpdatePortGroup(ctx, name, *spec); err != nil {
-		return fmt.Errorf("error updating port group: %s", err)
+		return diag.Errorf("error updating port group: %s", err)
 	}
 
-	return resourceVSphereHostPortGroupRead(d, meta)
+	return resourceVSphereHostPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereHostPortGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := portGroupIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
+	ns, err := hostNetworkSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.Errorf("error loading host network system: %s", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	if err := ns.RemovePortGroup(ctx, name); err != nil {
-		return fmt.Errorf("error deleting port group: %s", err)
+		return diag.Errorf("error deleting port group: %s", err)
 	}
 
 	return nil
 }
 
-func resourceVSphereHostPortGroupImport(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereHostPortGroupImport(ctx context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
 	hsID, name, err := portGroupIDsFromResourceID(d)
 	if err != nil {
 		return []*schema.ResourceData{}, err
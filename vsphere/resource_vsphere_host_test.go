@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -324,7 +325,7 @@ func testAccVSphereHostDestroy(s *terraform.State) error {
 }
 
 func hostExists(client *govmomi.Client, hostID string) (bool, error) {
-	hs, err := hostsystem.FromID(client, hostID)
+	hs, err := hostsystem.FromID(context.Background(), client, hostID)
 	if err != nil {
 		if viapi.IsManagedObjectNotFoundError(err) {
 			return false, nil
@@ -339,12 +340,12 @@ func hostExists(client *govmomi.Client, hostID string) (bool, error) {
 }
 
 func hostConnected(client *govmomi.Client, hostID string) (bool, error) {
-	hs, err := hostsystem.FromID(client, hostID)
+	hs, err := hostsystem.FromID(context.Background(), client, hostID)
 	if err != nil {
 		return false, err
 	}
 
-	connectionState, err := hostsystem.GetConnectionState(hs)
+	connectionState, err := hostsystem.GetConnectionState(context.Background(), hs)
 	if err != nil {
 		return false, err
 	}
@@ -352,12 +353,12 @@ func hostConnected(client *govmomi.Client, hostID string) (bool, error) {
 }
 
 func hostInMaintenance(client *govmomi.Client, hostID string) (bool, error) {
-	hs, err := hostsystem.FromID(client, hostID)
+	hs, err := hostsystem.FromID(context.Background(), client, hostID)
 	if err != nil {
 		return false, err
 	}
 
-	maintenanceState, err := hostsystem.HostInMaintenance(hs)
+	maintenanceState, err := hostsystem.HostInMaintenance(context.Background(), hs)
 	if err != nil {
 		return false, err
 	}
@@ -365,11 +366,11 @@ func hostInMaintenance(client *govmomi.Client, hostID string) (bool, error) {
 }
 
 func checkHostLockdown(client *govmomi.Client, hostID, lockdownMode string) (bool, error) {
-	host, err := hostsystem.FromID(client, hostID)
+	host, err := hostsystem.FromID(context.Background(), client, hostID)
 	if err != nil {
 		return false, err
 	}
-	hostProps, err := hostsystem.Properties(host)
+	hostProps, err := hostsystem.Properties(context.Background(), host)
 	if err != nil {
 		return false, err
 	}
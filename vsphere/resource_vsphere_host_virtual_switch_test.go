@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -172,12 +173,12 @@ func testAccResourceVSphereHostVirtualSwitchExists(expected bool) resource.TestC
 		if err != nil {
 			return err
 		}
-		ns, err := hostNetworkSystemFromHostSystemID(vars.client, hsID)
+		ns, err := hostNetworkSystemFromHostSystemID(context.Background(), vars.client, hsID)
 		if err != nil {
 			return fmt.Errorf("error loading host network system: %s", err)
 		}
 
-		_, err = hostVSwitchFromName(vars.client, ns, name)
+		_, err = hostVSwitchFromName(context.Background(), vars.client, ns, name)
 		if err != nil {
 			if err.Error() == fmt.Sprintf("could not find virtual switch %s", name) && expected == false {
 				// Expected missing
@@ -203,12 +204,12 @@ func testAccResourceVSphereHostVirtualSwitchNoBridge() resource.TestCheckFunc {
 		if err != nil {
 			return err
 		}
-		ns, err := hostNetworkSystemFromHostSystemID(vars.client, hsID)
+		ns, err := hostNetworkSystemFromHostSystemID(context.Background(), vars.client, hsID)
 		if err != nil {
 			return fmt.Errorf("error loading host network system: %s", err)
 		}
 
-		sw, err := hostVSwitchFromName(vars.client, ns, name)
+		sw, err := hostVSwitchFromName(context.Background(), vars.client, ns, name)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,333 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/keyprovider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+const resourceVSphereKeyProviderName = "vsphere_key_provider"
+
+var keyProviderTypeAllowedValues = []string{
+	keyprovider.TypeStandard,
+	keyprovider.TypeNative,
+}
+
+func resourceVSphereKeyProvider() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSphereKeyProviderCreate,
+		ReadContext:   resourceVSphereKeyProviderRead,
+		UpdateContext: resourceVSphereKeyProviderUpdate,
+		DeleteContext: resourceVSphereKeyProviderDelete,
+		CustomizeDiff: resourceVSphereKeyProviderCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereKeyProviderImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the key provider.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      keyprovider.TypeStandard,
+				Description:  "The type of the key provider. One of standard or native.",
+				ValidateFunc: validation.StringInSlice(keyProviderTypeAllowedValues, false),
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use this key provider as the default key provider of vCenter Server.",
+			},
+			"server": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The KMIP servers of a standard key provider.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the KMIP server.",
+						},
+						"address": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The address of the KMIP server.",
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      5696,
+							Description:  "The port of the KMIP server.",
+							ValidateFunc: validation.IsPortNumber,
+						},
+						"proxy_address": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The address of the proxy used to reach the KMIP server.",
+						},
+						"proxy_port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "The port of the proxy used to reach the KMIP server.",
+							ValidateFunc: validation.IsPortNumberOrZero,
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The username used to authenticate with the KMIP server.",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The password used to authenticate with the KMIP server.",
+						},
+					},
+				},
+			},
+			"has_backup": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the key provider has been backed up. Native key providers must be backed up before they can be used.",
+			},
+			"tpm_required": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the key provider requires hosts to have a TPM.",
+			},
+		},
+	}
+}
+
+func resourceVSphereKeyProviderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereKeyProviderIDString(d))
+	client := meta.(*Client).vimClient
+	name := d.Get("name").(string)
+
+	if err := keyprovider.Register(ctx, client, name, d.Get("type").(string)); err != nil {
+		return diag.Errorf("error registering key provider: %s", err)
+	}
+	d.SetId(name)
+
+	for _, spec := range expandKmipServerSpecs(name, d.Get("server").([]interface{})) {
+		if err := keyprovider.AddServer(ctx, client, spec); err != nil {
+			return diag.Errorf("error adding KMIP server %q: %s", spec.Info.Name, err)
+		}
+	}
+	if d.Get("default").(bool) {
+		if err := keyprovider.SetDefault(ctx, client, name); err != nil {
+			return diag.Errorf("error setting default key provider: %s", err)
+		}
+	}
+
+	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereKeyProviderIDString(d))
+	return resourceVSphereKeyProviderRead(ctx, d, meta)
+}
+
+func resourceVSphereKeyProviderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereKeyProviderIDString(d))
+	client := meta.(*Client).vimClient
+	info, err := keyprovider.FromID(ctx, client, d.Id())
+	if err != nil {
+		return diag.Errorf("error loading key provider: %s", err)
+	}
+	if info == nil {
+		log.Printf("[DEBUG] %s: Key provider not found, marking resource as gone", resourceVSphereKeyProviderIDString(d))
+		d.SetId("")
+		return nil
+	}
+	defaultID, err := keyprovider.DefaultID(ctx, client)
+	if err != nil {
+		return diag.Errorf("error loading default key provider: %s", err)
+	}
+
+	_ = d.Set("name", info.ClusterId.Id)
+	_ = d.Set("type", keyprovider.TypeFromInfo(info))
+	_ = d.Set("default", defaultID == info.ClusterId.Id)
+	_ = d.Set("has_backup", structure.DeRef(info.HasBackup))
+	_ = d.Set("tpm_required", structure.DeRef(info.TpmRequired))
+	if err := d.Set("server", flattenKmipServerInfo(d, info.Servers)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] %s: Read finished successfully", resourceVSphereKeyProviderIDString(d))
+	return nil
+}
+
+func resourceVSphereKeyProviderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereKeyProviderIDString(d))
+	client := meta.(*Client).vimClient
+
+	if d.HasChange("server") {
+		o, n := d.GetChange("server")
+		oldSpecs := make(map[string]types.KmipServerSpec)
+		for _, spec := range expandKmipServerSpecs(d.Id(), o.([]interface{})) {
+			oldSpecs[spec.Info.Name] = spec
+		}
+		newSpecs := expandKmipServerSpecs(d.Id(), n.([]interface{}))
+		newNames := make(map[string]bool)
+		for _, spec := range newSpecs {
+			newNames[spec.Info.Name] = true
+		}
+		// Remove servers first so that a server can be replaced by another one
+		// with the same address.
+		for name := range oldSpecs {
+			if newNames[name] {
+				continue
+			}
+			if err := keyprovider.RemoveServer(ctx, client, d.Id(), name); err != nil {
+				return diag.Errorf("error removing KMIP server %q: %s", name, err)
+			}
+		}
+		for _, spec := range newSpecs {
+			old, ok := oldSpecs[spec.Info.Name]
+			switch {
+			case !ok:
+				if err := keyprovider.AddServer(ctx, client, spec); err != nil {
+					return diag.Errorf("error adding KMIP server %q: %s", spec.Info.Name, err)
+				}
+			case old.Info != spec.Info || old.Password != spec.Password:
+				if err := keyprovider.UpdateServer(ctx, client, spec); err != nil {
+					return diag.Errorf("error updating KMIP server %q: %s", spec.Info.Name, err)
+				}
+			}
+		}
+	}
+
+	if d.HasChange("default") {
+		id := ""
+		if d.Get("default").(bool) {
+			id = d.Id()
+		}
+		if err := keyprovider.SetDefault(ctx, client, id); err != nil {
+			return diag.Errorf("error setting default key provider: %s", err)
+		}
+	}
+
+	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereKeyProviderIDString(d))
+	return resourceVSphereKeyProviderRead(ctx, d, meta)
+}
+
+func resourceVSphereKeyProviderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereKeyProviderIDString(d))
+	client := meta.(*Client).vimClient
+	if err := keyprovider.Unregister(ctx, client, d.Id()); err != nil {
+		return diag.Errorf("error unregistering key provider: %s", err)
+	}
+	log.Printf("[DEBUG] %s: Delete finished successfully", resourceVSphereKeyProviderIDString(d))
+	d.SetId("")
+	return nil
+}
+
+func resourceVSphereKeyProviderCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Get("type").(string) == keyprovider.TypeNative && len(d.Get("server").([]interface{})) > 0 {
+		return fmt.Errorf("server cannot be specified for a native key provider")
+	}
+	names := make(map[string]bool)
+	for _, s := range d.Get("server").([]interface{}) {
+		if s == nil {
+			continue
+		}
+		name := s.(map[string]interface{})["name"].(string)
+		if names[name] {
+			return fmt.Errorf("duplicate KMIP server name %q", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+func resourceVSphereKeyProviderImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	info, err := keyprovider.FromID(ctx, meta.(*Client).vimClient, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("error loading key provider: %s", err)
+	}
+	if info == nil {
+		return nil, fmt.Errorf("key provider %q not found", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandKmipServerSpecs reads the server list of a key provider into a list
+// of KmipServerSpec.
+func expandKmipServerSpecs(id string, servers []interface{}) []types.KmipServerSpec {
+	var specs []types.KmipServerSpec
+	for _, s := range servers {
+		if s == nil {
+			continue
+		}
+		server := s.(map[string]interface{})
+		specs = append(specs, types.KmipServerSpec{
+			ClusterId: types.KeyProviderId{Id: id},
+			Info: types.KmipServerInfo{
+				Name:         server["name"].(string),
+				Address:      server["address"].(string),
+				Port:         int32(server["port"].(int)),
+				ProxyAddress: server["proxy_address"].(string),
+				ProxyPort:    int32(server["proxy_port"].(int)),
+				UserName:     server["username"].(string),
+			},
+			Password: server["password"].(string),
+		})
+	}
+	return specs
+}
+
+// flattenKmipServerInfo reads a list of KmipServerInfo into the server list
+// of a key provider. Passwords are never returned by vCenter Server, so they
+// are carried over from the current state.
+func flattenKmipServerInfo(d *schema.ResourceData, servers []types.KmipServerInfo) []interface{} {
+	passwords := make(map[string]string)
+	for _, s := range d.Get("server").([]interface{}) {
+		if s == nil {
+			continue
+		}
+		server := s.(map[string]interface{})
+		passwords[server["name"].(string)] = server["password"].(string)
+	}
+	var result []interface{}
+	for _, server := range servers {
+		result = append(result, map[string]interface{}{
+			"name":          server.Name,
+			"address":       server.Address,
+			"port":          int(server.Port),
+			"proxy_address": server.ProxyAddress,
+			"proxy_port":    int(server.ProxyPort),
+			"username":      server.UserName,
+			"password":      passwords[server.Name],
+		})
+	}
+	return result
+}
+
+// resourceVSphereKeyProviderIDString prints a friendly string for the
+// vsphere_key_provider resource.
+func resourceVSphereKeyProviderIDString(d structure.ResourceIDStringer) string {
+	return structure.ResourceIDString(d, resourceVSphereKeyProviderName)
+}
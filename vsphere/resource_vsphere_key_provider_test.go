@@ -0,0 +1,142 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/keyprovider"
+)
+
+func TestAccResourceVSphereKeyProvider_native(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereKeyProviderCheckExists("testacc-nkp", false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereKeyProviderConfigNative(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereKeyProviderCheckExists("testacc-nkp", true),
+					resource.TestCheckResourceAttr("vsphere_key_provider.provider", "type", "native"),
+					resource.TestCheckResourceAttr("vsphere_key_provider.provider", "default", "false"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereKeyProviderConfigNative(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereKeyProviderCheckExists("testacc-nkp", true),
+					resource.TestCheckResourceAttr("vsphere_key_provider.provider", "default", "true"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_key_provider.provider",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereKeyProvider_standard(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			testAccCheckEnvVariables(t, []string{"TF_VAR_VSPHERE_KMIP_SERVER"})
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereKeyProviderCheckExists("testacc-kms", false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereKeyProviderConfigStandard(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereKeyProviderCheckExists("testacc-kms", true),
+					resource.TestCheckResourceAttr("vsphere_key_provider.provider", "type", "standard"),
+					resource.TestCheckResourceAttr("vsphere_key_provider.provider", "server.#", "1"),
+					resource.TestCheckResourceAttr("vsphere_key_provider.provider", "server.0.address", os.Getenv("TF_VAR_VSPHERE_KMIP_SERVER")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereKeyProvider_nativeWithServer(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceVSphereKeyProviderConfigNativeWithServer(),
+				ExpectError: regexp.MustCompile("server cannot be specified for a native key provider"),
+			},
+		},
+	})
+}
+
+func testAccResourceVSphereKeyProviderCheckExists(name string, expected bool) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		info, err := keyprovider.FromID(context.Background(), testAccProvider.Meta().(*Client).vimClient, name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case info == nil && expected:
+			return fmt.Errorf("expected key provider %q to exist", name)
+		case info != nil && !expected:
+			return fmt.Errorf("expected key provider %q to be missing", name)
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereKeyProviderConfigNative(isDefault bool) string {
+	return fmt.Sprintf(`
+resource "vsphere_key_provider" "provider" {
+  name    = "testacc-nkp"
+  type    = "native"
+  default = %t
+}
+`, isDefault)
+}
+
+func testAccResourceVSphereKeyProviderConfigStandard() string {
+	return fmt.Sprintf(`
+resource "vsphere_key_provider" "provider" {
+  name = "testacc-kms"
+
+  server {
+    name    = "testacc-kmip"
+    address = "%s"
+  }
+}
+`, os.Getenv("TF_VAR_VSPHERE_KMIP_SERVER"))
+}
+
+func testAccResourceVSphereKeyProviderConfigNativeWithServer() string {
+	return `
+resource "vsphere_key_provider" "provider" {
+  name = "testacc-nkp"
+  type = "native"
+
+  server {
+    name    = "testacc-kmip"
+    address = "kmip.example.com"
+  }
+}
+`
+}
@@ -20,6 +20,12 @@ func resourceVSphereNamespace() *schema.Resource {
 		ReadContext:   resourceVSphereNamespaceRead,
 		UpdateContext: resourceVSphereNamespaceUpdate,
 		DeleteContext: resourceVSphereNamespaceDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -5,9 +5,12 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -54,36 +57,42 @@ func resourceVSphereNasDatastore() *schema.Resource {
 	s[customattribute.ConfigKey] = customattribute.ConfigSchema()
 
 	return &schema.Resource{
-		Create: resourceVSphereNasDatastoreCreate,
-		Read:   resourceVSphereNasDatastoreRead,
-		Update: resourceVSphereNasDatastoreUpdate,
-		Delete: resourceVSphereNasDatastoreDelete,
+		CreateContext: resourceVSphereNasDatastoreCreate,
+		ReadContext:   resourceVSphereNasDatastoreRead,
+		UpdateContext: resourceVSphereNasDatastoreUpdate,
+		DeleteContext: resourceVSphereNasDatastoreDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereNasDatastoreImport,
+			StateContext: resourceVSphereNasDatastoreImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereNasDatastoreCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hosts := structure.SliceInterfacesToStrings(d.Get("host_system_ids").(*schema.Set).List())
 	volSpec, err := expandHostNasVolumeSpec(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	p := &nasDatastoreMountProcessor{
 		client:   client,
@@ -91,67 +100,67 @@ func resourceVSphereNasDatastoreCreate(d *schema.ResourceData, meta interface{})
 		newHSIDs: hosts,
 		volSpec:  volSpec,
 	}
-	ds, err := p.processMountOperations()
+	ds, err := p.processMountOperations(ctx)
 	if ds != nil {
 		d.SetId(ds.Reference().Value)
 	}
 	if err != nil {
-		return fmt.Errorf("error mounting datastore: %s", err)
+		return diag.Errorf("error mounting datastore: %s", err)
 	}
 
 	// Move the datastore to the correct folder or datastore cluster first, if
 	// specified.
-	f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
+	f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if !folder.PathIsEmpty(f) {
-		if err := datastore.MoveToFolderRelativeHostSystemID(client, ds, hosts[0], f); err != nil {
-			return fmt.Errorf("error moving datastore to folder: %s", err)
+		if err := datastore.MoveToFolderRelativeHostSystemID(ctx, client, ds, hosts[0], f); err != nil {
+			return diag.Errorf("error moving datastore to folder: %s", err)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Done
-	return resourceVSphereNasDatastoreRead(d, meta)
+	return resourceVSphereNasDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereNasDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	id := d.Id()
-	ds, err := datastore.FromID(client, id)
+	ds, err := datastore.FromID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return diag.Errorf("cannot find datastore: %s", err)
 	}
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ctx, ds)
 	if err != nil {
-		return fmt.Errorf("could not get properties for datastore: %s", err)
+		return diag.Errorf("could not get properties for datastore: %s", err)
 	}
 	if err := flattenDatastoreSummary(d, &props.Summary); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Set the folder
-	if err := resourceVSphereDatastoreReadFolderOrStorageClusterPath(d, ds); err != nil {
-		return err
+	if err := resourceVSphereDatastoreReadFolderOrStorageClusterPath(ctx, d, ds); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Update NAS spec
 	if err := flattenHostNasVolume(d, props.Info.(*types.NasDatastoreInfo).Nas); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Update mounted hosts
@@ -160,13 +169,13 @@ func resourceVSphereNasDatastoreRead(d *schema.ResourceData, meta interface{}) e
 		mountedHosts = append(mountedHosts, mount.Key.Value)
 	}
 	if err := d.Set("host_system_ids", mountedHosts); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, ds, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, ds, d); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -178,56 +187,56 @@ func resourceVSphereNasDatastoreRead(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
-func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := d.Id()
-	ds, err := datastore.FromID(client, id)
+	ds, err := datastore.FromID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return diag.Errorf("cannot find datastore: %s", err)
 	}
 
 	// Rename this datastore if our name has drifted.
 	if d.HasChange("name") {
-		if err := viapi.RenameObject(client, ds.Reference(), d.Get("name").(string)); err != nil {
-			return err
+		if err := viapi.RenameObject(ctx, client, ds.Reference(), d.Get("name").(string)); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Update folder or datastore cluster if necessary
 	if d.HasChange("folder") || d.HasChange("datastore_cluster_id") {
-		f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
+		f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(ctx, d, meta)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		if err := datastore.MoveToFolder(client, ds, f); err != nil {
-			return fmt.Errorf("could not move datastore to folder %q: %s", f, err)
+		if err := datastore.MoveToFolder(ctx, client, ds, f); err != nil {
+			return diag.Errorf("could not move datastore to folder %q: %s", f, err)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Apply custom attribute updates
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -235,7 +244,7 @@ func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{})
 	o, n := d.GetChange("host_system_ids")
 	volSpec, err := expandHostNasVolumeSpec(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	p := &nasDatastoreMountProcessor{
 		client:   client,
@@ -245,24 +254,24 @@ func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{})
 		ds:       ds,
 	}
 	// Unmount first
-	if err := p.processUnmountOperations(); err != nil {
-		return fmt.Errorf("error unmounting hosts: %s", err)
+	if err := p.processUnmountOperations(ctx); err != nil {
+		return diag.Errorf("error unmounting hosts: %s", err)
 	}
 	// Now mount
-	if _, err := p.processMountOperations(); err != nil {
-		return fmt.Errorf("error mounting hosts: %s", err)
+	if _, err := p.processMountOperations(ctx); err != nil {
+		return diag.Errorf("error mounting hosts: %s", err)
 	}
 
 	// Should be done with the update here.
-	return resourceVSphereNasDatastoreRead(d, meta)
+	return resourceVSphereNasDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereNasDatastoreDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	dsID := d.Id()
-	ds, err := datastore.FromID(client, dsID)
+	ds, err := datastore.FromID(ctx, client, dsID)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return diag.Errorf("cannot find datastore: %s", err)
 	}
 
 	// Unmount the datastore from every host. Once the last host is unmounted we
@@ -270,7 +279,7 @@ func resourceVSphereNasDatastoreDelete(d *schema.ResourceData, meta interface{})
 	hosts := structure.SliceInterfacesToStrings(d.Get("host_system_ids").(*schema.Set).List())
 	volSpec, err := expandHostNasVolumeSpec(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	p := &nasDatastoreMountProcessor{
 		client:   client,
@@ -279,24 +288,24 @@ func resourceVSphereNasDatastoreDelete(d *schema.ResourceData, meta interface{})
 		volSpec:  volSpec,
 		ds:       ds,
 	}
-	if err := p.processUnmountOperations(); err != nil {
-		return fmt.Errorf("error unmounting hosts: %s", err)
+	if err := p.processUnmountOperations(ctx); err != nil {
+		return diag.Errorf("error unmounting hosts: %s", err)
 	}
 
 	return nil
 }
 
-func resourceVSphereNasDatastoreImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereNasDatastoreImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// We support importing a MoRef - so we need to load the datastore and check
 	// to make sure 1) it exists, and 2) it's a VMFS datastore. If it is, we are
 	// good to go (rest of the stuff will be handled by read on refresh).
 	client := meta.(*Client).vimClient
 	id := d.Id()
-	ds, err := datastore.FromID(client, id)
+	ds, err := datastore.FromID(ctx, client, id)
 	if err != nil {
 		return nil, fmt.Errorf("cannot find datastore: %s", err)
 	}
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ctx, ds)
 	if err != nil {
 		return nil, fmt.Errorf("could not get properties for datastore: %s", err)
 	}
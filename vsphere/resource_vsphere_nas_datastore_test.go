@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -394,7 +395,7 @@ func testAccResourceVSphereNasDatastoreHasName(expected string) resource.TestChe
 			return err
 		}
 
-		props, err := datastore.Properties(ds)
+		props, err := datastore.Properties(context.Background(), ds)
 		if err != nil {
 			return err
 		}
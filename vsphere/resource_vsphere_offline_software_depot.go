@@ -7,7 +7,9 @@ package vsphere
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/vapi/cis/tasks"
@@ -51,14 +53,19 @@ func resourceVsphereOfflineSoftwareDepot() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourceVsphereOfflineSoftwareDepotCreate,
-		Read:   resourceVsphereOfflineSoftwareDepotRead,
-		Delete: resourceVsphereOfflineSoftwareDepotDelete,
+		CreateContext: resourceVsphereOfflineSoftwareDepotCreate,
+		ReadContext:   resourceVsphereOfflineSoftwareDepotRead,
+		DeleteContext: resourceVsphereOfflineSoftwareDepotDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Schema: s,
 	}
 }
 
-func resourceVsphereOfflineSoftwareDepotCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereOfflineSoftwareDepotCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).restClient
 
 	location := d.Get("location").(string)
@@ -72,51 +79,51 @@ func resourceVsphereOfflineSoftwareDepotCreate(d *schema.ResourceData, meta inte
 
 	taskID, err := m.CreateOfflineDepot(spec)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	_, err = tasks.NewManager(client).WaitForCompletion(context.Background(), taskID)
+	_, err = tasks.NewManager(client).WaitForCompletion(ctx, taskID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	offlineDepots, err := m.GetOfflineDepots()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	for id, depot := range offlineDepots {
 		if depot.Location == location {
 			d.SetId(id)
-			return resourceVsphereOfflineSoftwareDepotRead(d, meta)
+			return resourceVsphereOfflineSoftwareDepotRead(ctx, d, meta)
 		}
 	}
 
-	return errors.New("failed to create offline software depot")
+	return diag.FromErr(errors.New("failed to create offline software depot"))
 
 }
 
-func resourceVsphereOfflineSoftwareDepotRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereOfflineSoftwareDepotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).restClient
 	m := depots.NewManager(client)
 
 	data, err := m.GetOfflineDepotContent(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(d.Id())
-	return d.Set("component", readComponents(data))
+	return diag.FromErr(d.Set("component", readComponents(data)))
 }
 
-func resourceVsphereOfflineSoftwareDepotDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereOfflineSoftwareDepotDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).restClient
 	m := depots.NewManager(client)
 
 	if taskID, err := m.DeleteOfflineDepot(d.Id()); err != nil {
-		return err
-	} else if _, err = tasks.NewManager(client).WaitForCompletion(context.Background(), taskID); err != nil {
-		return err
+		return diag.FromErr(err)
+	} else if _, err = tasks.NewManager(client).WaitForCompletion(ctx, taskID); err != nil {
+		return diag.FromErr(err)
 	}
 	return nil
 }
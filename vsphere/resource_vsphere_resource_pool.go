@@ -5,9 +5,12 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -124,23 +127,29 @@ func resourceVSphereResourcePool() *schema.Resource {
 		customattribute.ConfigKey: customattribute.ConfigSchema(),
 	}
 	return &schema.Resource{
-		Create: resourceVSphereResourcePoolCreate,
-		Read:   resourceVSphereResourcePoolRead,
-		Update: resourceVSphereResourcePoolUpdate,
-		Delete: resourceVSphereResourcePoolDelete,
+		CreateContext: resourceVSphereResourcePoolCreate,
+		ReadContext:   resourceVSphereResourcePoolRead,
+		UpdateContext: resourceVSphereResourcePoolUpdate,
+		DeleteContext: resourceVSphereResourcePoolDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereResourcePoolImport,
+			StateContext: resourceVSphereResourcePoolImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereResourcePoolImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereResourcePoolImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
 		return nil, err
 	}
-	rp, err := resourcepool.FromPathOrDefault(client, d.Id(), nil)
+	rp, err := resourcepool.FromPathOrDefault(ctx, client, d.Id(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -148,133 +157,133 @@ func resourceVSphereResourcePoolImport(d *schema.ResourceData, meta interface{})
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceVSphereResourcePoolCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	prp, err := resourcepool.FromID(client, d.Get("parent_resource_pool_id").(string))
+	prp, err := resourcepool.FromID(ctx, client, d.Get("parent_resource_pool_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	version := viapi.ParseVersionFromClient(client)
 	rpSpec := expandResourcePoolConfigSpec(d, version)
-	err = reconcileScaleDescendantsShares(version, rpSpec, prp)
+	err = reconcileScaleDescendantsShares(ctx, version, rpSpec, prp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	rp, err := resourcepool.Create(prp, d.Get("name").(string), rpSpec)
+	rp, err := resourcepool.Create(ctx, prp, d.Get("name").(string), rpSpec)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	if err = resourceVSphereResourcePoolApplyTags(d, meta, rp); err != nil {
-		return err
+	if err = resourceVSphereResourcePoolApplyTags(ctx, d, meta, rp); err != nil {
+		return diag.FromErr(err)
 	}
 	d.SetId(rp.Reference().Value)
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereResourcePoolIDString(d))
-	return resourceVSphereResourcePoolRead(d, meta)
+	return resourceVSphereResourcePoolRead(ctx, d, meta)
 }
 
-func resourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	version := viapi.ParseVersionFromClient(client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	rp, err := resourcepool.FromID(client, d.Id())
+	rp, err := resourcepool.FromID(ctx, client, d.Id())
 	if err != nil {
 		if viapi.IsManagedObjectNotFoundError(err) {
 			log.Printf("[DEBUG] %s: Resource has been deleted", resourceVSphereResourcePoolIDString(d))
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
-	if err = resourceVSphereResourcePoolReadTags(d, meta, rp); err != nil {
-		return err
+	if err = resourceVSphereResourcePoolReadTags(ctx, d, meta, rp); err != nil {
+		return diag.FromErr(err)
 	}
 	err = d.Set("name", rp.Name())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	rpProps, err := resourcepool.Properties(rp)
+	rpProps, err := resourcepool.Properties(ctx, rp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("parent_resource_pool_id", rpProps.Parent.Value); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	err = flattenResourcePoolConfigSpec(d, rpProps.Config, version)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Read finished successfully", resourceVSphereResourcePoolIDString(d))
 	return nil
 }
 
-func resourceVSphereResourcePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	rp, err := resourcepool.FromID(client, d.Id())
+	rp, err := resourcepool.FromID(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	if err = resourceVSphereResourcePoolApplyTags(d, meta, rp); err != nil {
-		return err
+	if err = resourceVSphereResourcePoolApplyTags(ctx, d, meta, rp); err != nil {
+		return diag.FromErr(err)
 	}
 	op, np := d.GetChange("parent_resource_pool_id")
 	if op != np {
 		log.Printf("[DEBUG] %s: Parent resource pool has changed. Moving from %s, to %s", resourceVSphereResourcePoolIDString(d), op, np)
-		p, err := resourcepool.FromID(client, np.(string))
+		p, err := resourcepool.FromID(ctx, client, np.(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		err = resourcepool.MoveIntoResourcePool(p, rp.Reference())
+		err = resourcepool.MoveIntoResourcePool(ctx, p, rp.Reference())
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		log.Printf("[DEBUG] %s: Move finished successfully", resourceVSphereResourcePoolIDString(d))
 	}
 	version := viapi.ParseVersionFromClient(client)
 	rpSpec := expandResourcePoolConfigSpec(d, version)
-	prp, err := resourcepool.FromID(client, d.Get("parent_resource_pool_id").(string))
+	prp, err := resourcepool.FromID(ctx, client, d.Get("parent_resource_pool_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	err = reconcileScaleDescendantsShares(version, rpSpec, prp)
+	err = reconcileScaleDescendantsShares(ctx, version, rpSpec, prp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	err = resourcepool.Update(rp, d.Get("name").(string), rpSpec)
+	err = resourcepool.Update(ctx, rp, d.Get("name").(string), rpSpec)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereResourcePoolIDString(d))
 	return nil
 }
 
-func resourceVSphereResourcePoolDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	rp, err := resourcepool.FromID(client, d.Id())
+	rp, err := resourcepool.FromID(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	err = resourceVSphereResourcePoolValidateEmpty(rp)
+	err = resourceVSphereResourcePoolValidateEmpty(ctx, rp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	err = resourcepool.Delete(rp)
+	err = resourcepool.Delete(ctx, rp)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereResourcePoolIDString(d))
 	return nil
@@ -366,8 +375,8 @@ func resourceVSphereResourcePoolClient(meta interface{}) (*govmomi.Client, error
 	return client, nil
 }
 
-func resourceVSphereResourcePoolValidateEmpty(rp *object.ResourcePool) error {
-	ne, err := resourcepool.HasChildren(rp)
+func resourceVSphereResourcePoolValidateEmpty(ctx context.Context, rp *object.ResourcePool) error {
+	ne, err := resourcepool.HasChildren(ctx, rp)
 	if err != nil {
 		return fmt.Errorf("error checking contents of resource pool: %s", err)
 	}
@@ -379,7 +388,7 @@ func resourceVSphereResourcePoolValidateEmpty(rp *object.ResourcePool) error {
 
 // resourceVSphereResourcePoolApplyTags processes the tags step for both create
 // and update for a resource pool.
-func resourceVSphereResourcePoolApplyTags(d *schema.ResourceData, meta interface{}, rp *object.ResourcePool) error {
+func resourceVSphereResourcePoolApplyTags(ctx context.Context, d *schema.ResourceData, meta interface{}, rp *object.ResourcePool) error {
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
 		return err
@@ -392,14 +401,14 @@ func resourceVSphereResourcePoolApplyTags(d *schema.ResourceData, meta interface
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereResourcePoolIDString(d))
-	return processTagDiff(tagsClient, d, rp)
+	return processTagDiff(ctx, tagsClient, d, rp)
 }
 
 // resourceVSphereResourcePoolReadTags reads the tags for a resource pool.
-func resourceVSphereResourcePoolReadTags(d *schema.ResourceData, meta interface{}, rp *object.ResourcePool) error {
+func resourceVSphereResourcePoolReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, rp *object.ResourcePool) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereResourcePoolIDString(d))
-		if err := readTagsForResource(tagsClient, rp, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, rp, d); err != nil {
 			return err
 		}
 	} else {
@@ -411,10 +420,10 @@ func resourceVSphereResourcePoolReadTags(d *schema.ResourceData, meta interface{
 // reconcileScaleDescendantsShares reconciles the scale descendants shares setting for a resource pool. If the parent
 // resource pool has scale descendants shares set to scale CPU and memory shares, the child resource pool shares will
 // inherit the parent resource pool setting.
-func reconcileScaleDescendantsShares(version viapi.VSphereVersion, obj *types.ResourceConfigSpec, prp *object.ResourcePool) error {
+func reconcileScaleDescendantsShares(ctx context.Context, version viapi.VSphereVersion, obj *types.ResourceConfigSpec, prp *object.ResourcePool) error {
 	// Minimum Supported Version: 7.0.0
 	if version.Newer(viapi.VSphereVersion{Product: version.Product, Major: 7, Minor: 0}) {
-		prpProp, err := resourcepool.Properties(prp)
+		prpProp, err := resourcepool.Properties(ctx, prp)
 		if err != nil {
 			return fmt.Errorf("error getting properties of resource pool: %s", err)
 		}
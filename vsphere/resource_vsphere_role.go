@@ -11,7 +11,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -41,18 +43,24 @@ func resourceVsphereRole() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourceRoleCreate,
-		Read:   resourceRoleRead,
-		Update: resourceRoleUpdate,
-		Delete: resourceRoleDelete,
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: sch,
 		Importer: &schema.ResourceImporter{
-			State: resourceRoleImport,
+			StateContext: resourceRoleImport,
 		},
 	}
 }
 
-func resourceRoleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning create role %s", d.Get("name").(string))
 	client := meta.(*Client).vimClient
 
@@ -61,21 +69,21 @@ func resourceRoleCreate(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	rolePrivileges := structure.SliceInterfacesToStrings(d.Get("role_privileges").([]interface{}))
 
-	roleID, err := authorizationManager.AddRole(context.Background(), name, rolePrivileges)
+	roleID, err := authorizationManager.AddRole(ctx, name, rolePrivileges)
 	if err != nil {
-		return fmt.Errorf("error while creating role with name %s %s", name, err)
+		return diag.Errorf("error while creating role with name %s %s", name, err)
 	}
 
 	d.SetId(strconv.Itoa(int(roleID)))
-	return resourceRoleRead(d, meta)
+	return resourceRoleRead(ctx, d, meta)
 }
 
-func resourceRoleRead(d *schema.ResourceData, meta interface{}) error {
-	return getRoleByID(d, false, meta)
+func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(getRoleByID(ctx, d, false, meta))
 }
 
-func resourceRoleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	err := getRoleByID(d, true, meta)
+func resourceRoleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	err := getRoleByID(ctx, d, true, meta)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +91,7 @@ func resourceRoleImport(d *schema.ResourceData, meta interface{}) ([]*schema.Res
 	return []*schema.ResourceData{d}, nil
 }
 
-func getRoleByID(d *schema.ResourceData, excludeSystem bool, meta interface{}) error {
+func getRoleByID(ctx context.Context, d *schema.ResourceData, excludeSystem bool, meta interface{}) error {
 	log.Printf("[DEBUG] Reading vm role with id %s", d.Id())
 	client := meta.(*Client).vimClient
 	authorizationManager := object.NewAuthorizationManager(client.Client)
@@ -92,7 +100,7 @@ func getRoleByID(d *schema.ResourceData, excludeSystem bool, meta interface{}) e
 		return fmt.Errorf("error while coverting role id %s from string to int %s", d.Id(), err)
 	}
 	roleID := int32(roleIDInt)
-	roleList, err := authorizationManager.RoleList(context.Background())
+	roleList, err := authorizationManager.RoleList(ctx)
 
 	if err != nil {
 		return fmt.Errorf("error while reading the role list %s", err)
@@ -123,7 +131,7 @@ func getRoleByID(d *schema.ResourceData, excludeSystem bool, meta interface{}) e
 	return nil
 }
 
-func resourceRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning update role %s", d.Get("name").(string))
 	client := meta.(*Client).vimClient
 
@@ -131,31 +139,31 @@ func resourceRoleUpdate(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	roleIDInt, err := strconv.ParseInt(d.Id(), 10, 32)
 	if err != nil {
-		return fmt.Errorf("error while coverting role id %s from string to int %s", d.Id(), err)
+		return diag.Errorf("error while coverting role id %s from string to int %s", d.Id(), err)
 	}
 	roleID := int32(roleIDInt)
 	rolePrivileges := structure.SliceInterfacesToStrings(d.Get("role_privileges").([]interface{}))
 
-	err = authorizationManager.UpdateRole(context.Background(), roleID, name, rolePrivileges)
+	err = authorizationManager.UpdateRole(ctx, roleID, name, rolePrivileges)
 	if err != nil {
-		return fmt.Errorf("error while updating role with is %d %s", roleIDInt, err)
+		return diag.Errorf("error while updating role with is %d %s", roleIDInt, err)
 	}
-	return resourceRoleRead(d, meta)
+	return resourceRoleRead(ctx, d, meta)
 }
 
-func resourceRoleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Performing Delete of Role with ID %s", d.Id())
 	client := meta.(*Client).vimClient
 	authorizationManager := object.NewAuthorizationManager(client.Client)
 
 	roleIDInt, err := strconv.ParseInt(d.Id(), 10, 32)
 	if err != nil {
-		return fmt.Errorf("error while coverting role id %s from string to int %s", d.Id(), err)
+		return diag.Errorf("error while coverting role id %s from string to int %s", d.Id(), err)
 	}
 	roleID := int32(roleIDInt)
-	err = authorizationManager.RemoveRole(context.Background(), roleID, true)
+	err = authorizationManager.RemoveRole(ctx, roleID, true)
 	if err != nil {
-		return fmt.Errorf("error while deleting role with id %d %s", roleIDInt, err)
+		return diag.Errorf("error while deleting role with id %d %s", roleIDInt, err)
 	}
 
 	d.SetId("")
@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/ssoadmin"
 	"github.com/vmware/govmomi/ssoadmin/types"
@@ -34,10 +36,16 @@ func ssoPrincipalMemberResource() *schema.Resource {
 
 func resourceVSphereSSOGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereSSOGroupCreate,
-		Read:   resourceVSphereSSOGroupRead,
-		Update: resourceVSphereSSOGroupUpdate,
-		Delete: resourceVSphereSSOGroupDelete,
+		CreateContext: resourceVSphereSSOGroupCreate,
+		ReadContext:   resourceVSphereSSOGroupRead,
+		UpdateContext: resourceVSphereSSOGroupUpdate,
+		DeleteContext: resourceVSphereSSOGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -74,19 +82,18 @@ func resourceVSphereSSOGroup() *schema.Resource {
 	}
 }
 
-func resourceVSphereSSOGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
 	log.Printf("[DEBUG] vsphere_sso_group: creating group %q", name)
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	details := types.AdminGroupDetails{Description: d.Get("description").(string)}
 	if err := client.CreateGroup(ctx, name, details); err != nil {
-		return fmt.Errorf("error creating SSO group %q: %s", name, err)
+		return diag.Errorf("error creating SSO group %q: %s", name, err)
 	}
 
 	// Groups are created in the local (system) domain. The ID is qualified with
@@ -95,30 +102,29 @@ func resourceVSphereSSOGroupCreate(d *schema.ResourceData, meta interface{}) err
 
 	if users := ssohelper.ExpandMembers(d.Get("member_user").(*schema.Set)); len(users) > 0 {
 		if err := client.AddUsersToGroup(ctx, name, users...); err != nil {
-			return fmt.Errorf("error adding user members to SSO group %q: %s", name, err)
+			return diag.Errorf("error adding user members to SSO group %q: %s", name, err)
 		}
 	}
 	if groups := ssohelper.ExpandMembers(d.Get("member_group").(*schema.Set)); len(groups) > 0 {
 		if err := client.AddGroupsToGroup(ctx, name, groups...); err != nil {
-			return fmt.Errorf("error adding group members to SSO group %q: %s", name, err)
+			return diag.Errorf("error adding group members to SSO group %q: %s", name, err)
 		}
 	}
 
-	return resourceVSphereSSOGroupRead(d, meta)
+	return resourceVSphereSSOGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereSSOGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] vsphere_sso_group: reading group %q", d.Id())
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	group, err := client.FindGroup(ctx, d.Id())
 	if err != nil {
-		return fmt.Errorf("error reading SSO group %q: %s", d.Id(), err)
+		return diag.Errorf("error reading SSO group %q: %s", d.Id(), err)
 	}
 	if group == nil {
 		log.Printf("[DEBUG] vsphere_sso_group: group %q no longer exists", d.Id())
@@ -132,7 +138,7 @@ func resourceVSphereSSOGroupRead(d *schema.ResourceData, meta interface{}) error
 
 	memberUsers, memberGroups, err := readSSOGroupMembers(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	_ = d.Set("member_user", memberUsers)
 	_ = d.Set("member_group", memberGroups)
@@ -140,23 +146,22 @@ func resourceVSphereSSOGroupRead(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func resourceVSphereSSOGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name, _, err := ssohelper.ParseID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] vsphere_sso_group: updating group %q", d.Id())
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if d.HasChange("description") {
 		details := types.AdminGroupDetails{Description: d.Get("description").(string)}
 		if err := client.UpdateGroup(ctx, name, details); err != nil {
-			return fmt.Errorf("error updating SSO group %q: %s", name, err)
+			return diag.Errorf("error updating SSO group %q: %s", name, err)
 		}
 	}
 
@@ -165,7 +170,7 @@ func resourceVSphereSSOGroupUpdate(d *schema.ResourceData, meta interface{}) err
 		oldUsers := ssohelper.ExpandMembers(oldRaw.(*schema.Set))
 		newUsers := ssohelper.ExpandMembers(newRaw.(*schema.Set))
 		if err := reconcileSSOGroupUsers(ctx, client, name, oldUsers, newUsers); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -174,28 +179,27 @@ func resourceVSphereSSOGroupUpdate(d *schema.ResourceData, meta interface{}) err
 		oldGroups := ssohelper.ExpandMembers(oldRaw.(*schema.Set))
 		newGroups := ssohelper.ExpandMembers(newRaw.(*schema.Set))
 		if err := reconcileSSOGroupGroups(ctx, client, name, oldGroups, newGroups); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceVSphereSSOGroupRead(d, meta)
+	return resourceVSphereSSOGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereSSOGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name, _, err := ssohelper.ParseID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] vsphere_sso_group: deleting group %q", d.Id())
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := client.DeletePrincipal(ctx, name); err != nil {
-		return fmt.Errorf("error deleting SSO group %q: %s", name, err)
+		return diag.Errorf("error deleting SSO group %q: %s", name, err)
 	}
 
 	d.SetId("")
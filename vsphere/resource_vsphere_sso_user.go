@@ -6,9 +6,10 @@ package vsphere
 
 import (
 	"context"
-	"fmt"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/ssoadmin/types"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/ssohelper"
@@ -16,10 +17,16 @@ import (
 
 func resourceVSphereSSOUser() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereSSOUserCreate,
-		Read:   resourceVSphereSSOUserRead,
-		Update: resourceVSphereSSOUserUpdate,
-		Delete: resourceVSphereSSOUserDelete,
+		CreateContext: resourceVSphereSSOUserCreate,
+		ReadContext:   resourceVSphereSSOUserRead,
+		UpdateContext: resourceVSphereSSOUserUpdate,
+		DeleteContext: resourceVSphereSSOUserDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -67,21 +74,20 @@ func resourceVSphereSSOUser() *schema.Resource {
 	}
 }
 
-func resourceVSphereSSOUserCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name := d.Get("name").(string)
 	log.Printf("[DEBUG] vsphere_sso_user: creating user %q", name)
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Users can only be created in the local (system) domain. A user in an
 	// external domain is managed by its identity provider and can only be
 	// imported.
 	if domain := d.Get("domain").(string); domain != "" && domain != client.Domain {
-		return fmt.Errorf("cannot create user in domain %q: users can only be created in the local domain %q (external users are managed by their identity provider and can only be imported)", domain, client.Domain)
+		return diag.Errorf("cannot create user in domain %q: users can only be created in the local domain %q (external users are managed by their identity provider and can only be imported)", domain, client.Domain)
 	}
 
 	details := types.AdminPersonDetails{
@@ -92,25 +98,24 @@ func resourceVSphereSSOUserCreate(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	if err := client.CreatePersonUser(ctx, name, details, d.Get("password").(string)); err != nil {
-		return fmt.Errorf("error creating SSO user %q: %s", name, err)
+		return diag.Errorf("error creating SSO user %q: %s", name, err)
 	}
 
 	d.SetId(ssohelper.ID(name, client.Domain))
-	return resourceVSphereSSOUserRead(d, meta)
+	return resourceVSphereSSOUserRead(ctx, d, meta)
 }
 
-func resourceVSphereSSOUserRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] vsphere_sso_user: reading user %q", d.Id())
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	user, err := client.FindPersonUser(ctx, d.Id())
 	if err != nil {
-		return fmt.Errorf("error reading SSO user %q: %s", d.Id(), err)
+		return diag.Errorf("error reading SSO user %q: %s", d.Id(), err)
 	}
 	if user == nil {
 		log.Printf("[DEBUG] vsphere_sso_user: user %q no longer exists", d.Id())
@@ -129,22 +134,21 @@ func resourceVSphereSSOUserRead(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func resourceVSphereSSOUserUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name, domain, err := ssohelper.ParseID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] vsphere_sso_user: updating user %q", d.Id())
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Only local-domain users can be modified.
 	if domain != client.Domain {
-		return fmt.Errorf("cannot modify user %q: only users in the local domain %q can be modified", d.Id(), client.Domain)
+		return diag.Errorf("cannot modify user %q: only users in the local domain %q can be modified", d.Id(), client.Domain)
 	}
 
 	if d.HasChanges("description", "email_address", "first_name", "last_name") {
@@ -155,39 +159,38 @@ func resourceVSphereSSOUserUpdate(d *schema.ResourceData, meta interface{}) erro
 			LastName:     d.Get("last_name").(string),
 		}
 		if err := client.UpdatePersonUser(ctx, name, details); err != nil {
-			return fmt.Errorf("error updating SSO user %q: %s", d.Id(), err)
+			return diag.Errorf("error updating SSO user %q: %s", d.Id(), err)
 		}
 	}
 
 	if d.HasChange("password") {
 		if err := client.ResetPersonPassword(ctx, name, d.Get("password").(string)); err != nil {
-			return fmt.Errorf("error resetting password for SSO user %q: %s", d.Id(), err)
+			return diag.Errorf("error resetting password for SSO user %q: %s", d.Id(), err)
 		}
 	}
 
-	return resourceVSphereSSOUserRead(d, meta)
+	return resourceVSphereSSOUserRead(ctx, d, meta)
 }
 
-func resourceVSphereSSOUserDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereSSOUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	name, domain, err := ssohelper.ParseID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] vsphere_sso_user: deleting user %q", d.Id())
 
-	ctx := context.Background()
 	client, err := meta.(*Client).SSOAdminClient(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Only local-domain users can be deleted.
 	if domain != client.Domain {
-		return fmt.Errorf("cannot delete user %q: only users in the local domain %q can be deleted", d.Id(), client.Domain)
+		return diag.Errorf("cannot delete user %q: only users in the local domain %q can be deleted", d.Id(), client.Domain)
 	}
 
 	if err := client.DeletePrincipal(ctx, name); err != nil {
-		return fmt.Errorf("error deleting SSO user %q: %s", d.Id(), err)
+		return diag.Errorf("error deleting SSO user %q: %s", d.Id(), err)
 	}
 
 	d.SetId("")
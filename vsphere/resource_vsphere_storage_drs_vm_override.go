@@ -5,12 +5,15 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -26,12 +29,18 @@ const resourceVSphereStorageDrsVMOverrideName = "vsphere_storage_drs_vm_override
 
 func resourceVSphereStorageDrsVMOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereStorageDrsVMOverrideCreate,
-		Read:   resourceVSphereStorageDrsVMOverrideRead,
-		Update: resourceVSphereStorageDrsVMOverrideUpdate,
-		Delete: resourceVSphereStorageDrsVMOverrideDelete,
+		CreateContext: resourceVSphereStorageDrsVMOverrideCreate,
+		ReadContext:   resourceVSphereStorageDrsVMOverrideRead,
+		UpdateContext: resourceVSphereStorageDrsVMOverrideUpdate,
+		DeleteContext: resourceVSphereStorageDrsVMOverrideDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereStorageDrsVMOverrideImport,
+			StateContext: resourceVSphereStorageDrsVMOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -71,22 +80,22 @@ func resourceVSphereStorageDrsVMOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereStorageDrsVMOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereStorageDrsVMOverrideIDString(d))
 
 	client, err := resourceVSphereStorageDrsVMOverrideClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
+	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandStorageDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := types.StorageDrsConfigSpec{
 		VmConfigSpec: []types.StorageDrsVmConfigSpec{
@@ -99,31 +108,31 @@ func resourceVSphereStorageDrsVMOverrideCreate(d *schema.ResourceData, meta inte
 		},
 	}
 
-	if err = storagepod.ApplyDRSConfiguration(client, pod, spec); err != nil {
-		return err
+	if err = storagepod.ApplyDRSConfiguration(ctx, client, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
-	id, err := resourceVSphereStorageDrsVMOverrideFlattenID(pod, vm)
+	id, err := resourceVSphereStorageDrsVMOverrideFlattenID(ctx, pod, vm)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of imported resource: %s", err)
+		return diag.Errorf("cannot compute ID of imported resource: %s", err)
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereStorageDrsVMOverrideIDString(d))
-	return resourceVSphereStorageDrsVMOverrideRead(d, meta)
+	return resourceVSphereStorageDrsVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereStorageDrsVMOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereStorageDrsVMOverrideIDString(d))
 
-	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
+	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := resourceVSphereStorageDrsVMOverrideFindEntry(pod, vm)
+	info, err := resourceVSphereStorageDrsVMOverrideFindEntry(ctx, pod, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -136,41 +145,41 @@ func resourceVSphereStorageDrsVMOverrideRead(d *schema.ResourceData, meta interf
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// datastore cluster/VM combo was used, it will be noted.
 	if err = d.Set("datastore_cluster_id", pod.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"datastore_cluster_id\": %s", err)
+		return diag.Errorf("error setting attribute \"datastore_cluster_id\": %s", err)
 	}
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return fmt.Errorf("error getting properties of virtual machine: %s", err)
+		return diag.Errorf("error getting properties of virtual machine: %s", err)
 	}
 	if err = d.Set("virtual_machine_id", props.Config.Uuid); err != nil {
-		return fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
+		return diag.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
 	}
 
 	if err = flattenStorageDrsVMConfigInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereStorageDrsVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereStorageDrsVMOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereStorageDrsVMOverrideIDString(d))
 
 	client, err := resourceVSphereStorageDrsVMOverrideClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
+	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandStorageDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := types.StorageDrsConfigSpec{
 		VmConfigSpec: []types.StorageDrsVmConfigSpec{
@@ -187,26 +196,26 @@ func resourceVSphereStorageDrsVMOverrideUpdate(d *schema.ResourceData, meta inte
 		},
 	}
 
-	if err := storagepod.ApplyDRSConfiguration(client, pod, spec); err != nil {
-		return err
+	if err := storagepod.ApplyDRSConfiguration(ctx, client, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereStorageDrsVMOverrideIDString(d))
-	return resourceVSphereStorageDrsVMOverrideRead(d, meta)
+	return resourceVSphereStorageDrsVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereStorageDrsVMOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	resourceIDString := resourceVSphereStorageDrsVMOverrideIDString(d)
 	log.Printf("[DEBUG] %s: Beginning delete", resourceIDString)
 
 	client, err := resourceVSphereStorageDrsVMOverrideClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
+	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := types.StorageDrsConfigSpec{
@@ -220,8 +229,8 @@ func resourceVSphereStorageDrsVMOverrideDelete(d *schema.ResourceData, meta inte
 		},
 	}
 
-	if err := storagepod.ApplyDRSConfiguration(client, pod, spec); err != nil {
-		return err
+	if err := storagepod.ApplyDRSConfiguration(ctx, client, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
@@ -230,7 +239,7 @@ func resourceVSphereStorageDrsVMOverrideDelete(d *schema.ResourceData, meta inte
 	return nil
 }
 
-func resourceVSphereStorageDrsVMOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereStorageDrsVMOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -249,17 +258,17 @@ func resourceVSphereStorageDrsVMOverrideImport(d *schema.ResourceData, meta inte
 		return nil, err
 	}
 
-	pod, err := storagepod.FromPath(client, podPath, nil)
+	pod, err := storagepod.FromPath(ctx, client, podPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate datastore cluster %q: %s", podPath, err)
 	}
 
-	vm, err := virtualmachine.FromPath(client, vmPath, nil)
+	vm, err := virtualmachine.FromPath(ctx, client, vmPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot locate virtual machine %q: %s", vmPath, err)
 	}
 
-	id, err := resourceVSphereStorageDrsVMOverrideFlattenID(pod, vm)
+	id, err := resourceVSphereStorageDrsVMOverrideFlattenID(ctx, pod, vm)
 	if err != nil {
 		return nil, fmt.Errorf("cannot compute ID of imported resource: %s", err)
 	}
@@ -313,9 +322,9 @@ func resourceVSphereStorageDrsVMOverrideIDString(d structure.ResourceIDStringer)
 
 // resourceVSphereStorageDrsVMOverrideFlattenID makes an ID for the
 // vsphere_storage_drs_vm_override resource.
-func resourceVSphereStorageDrsVMOverrideFlattenID(pod *object.StoragePod, vm *object.VirtualMachine) (string, error) {
+func resourceVSphereStorageDrsVMOverrideFlattenID(ctx context.Context, pod *object.StoragePod, vm *object.VirtualMachine) (string, error) {
 	podID := pod.Reference().Value
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return "", fmt.Errorf("cannot compute ID off of properties of virtual machine: %s", err)
 	}
@@ -337,10 +346,11 @@ func resourceVSphereStorageDrsVMOverrideParseID(id string) (string, string, erro
 // config in a Storage Pod's DRS configuration. It's used by the resource's
 // read functionality and tests. nil is returned if the entry cannot be found.
 func resourceVSphereStorageDrsVMOverrideFindEntry(
+	ctx context.Context,
 	pod *object.StoragePod,
 	vm *object.VirtualMachine,
 ) (*types.StorageDrsVmConfigInfo, error) {
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(ctx, pod)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching datastore cluster properties: %s", err)
 	}
@@ -363,20 +373,23 @@ func resourceVSphereStorageDrsVMOverrideFindEntry(
 // * If not, it's derived from the datastore_cluster_id and virtual_machine_id
 // attributes.
 func resourceVSphereStorageDrsVMOverrideObjects(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.StoragePod, *object.VirtualMachine, error) {
 	if d.Id() != "" {
-		return resourceVSphereStorageDrsVMOverrideObjectsFromID(d, meta)
+		return resourceVSphereStorageDrsVMOverrideObjectsFromID(ctx, d, meta)
 	}
-	return resourceVSphereStorageDrsVMOverrideObjectsFromAttributes(d, meta)
+	return resourceVSphereStorageDrsVMOverrideObjectsFromAttributes(ctx, d, meta)
 }
 
 func resourceVSphereStorageDrsVMOverrideObjectsFromAttributes(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 ) (*object.StoragePod, *object.VirtualMachine, error) {
 	return resourceVSphereStorageDrsVMOverrideFetchObjects(
+		ctx,
 		meta,
 		d.Get("datastore_cluster_id").(string),
 		d.Get("virtual_machine_id").(string),
@@ -384,6 +397,7 @@ func resourceVSphereStorageDrsVMOverrideObjectsFromAttributes(
 }
 
 func resourceVSphereStorageDrsVMOverrideObjectsFromID(
+	ctx context.Context,
 	d structure.ResourceIDStringer,
 	meta interface{},
 ) (*object.StoragePod, *object.VirtualMachine, error) {
@@ -395,10 +409,11 @@ func resourceVSphereStorageDrsVMOverrideObjectsFromID(
 		return nil, nil, err
 	}
 
-	return resourceVSphereStorageDrsVMOverrideFetchObjects(meta, podID, vmID)
+	return resourceVSphereStorageDrsVMOverrideFetchObjects(ctx, meta, podID, vmID)
 }
 
 func resourceVSphereStorageDrsVMOverrideFetchObjects(
+	ctx context.Context,
 	meta interface{},
 	podID string,
 	vmID string,
@@ -408,12 +423,12 @@ func resourceVSphereStorageDrsVMOverrideFetchObjects(
 		return nil, nil, err
 	}
 
-	pod, err := storagepod.FromID(client, podID)
+	pod, err := storagepod.FromID(ctx, client, podID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate datastore cluster: %s", err)
 	}
 
-	vm, err := virtualmachine.FromUUID(client, vmID)
+	vm, err := virtualmachine.FromUUID(ctx, client, vmID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot locate virtual machine: %s", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/vapi/namespace"
@@ -17,10 +18,16 @@ import (
 
 func resourceVsphereSupervisor() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVsphereSupervisorCreate,
-		Read:   resourceVsphereSupervisorRead,
-		Update: resourceVsphereSupervisorUpdate,
-		Delete: resourceVsphereSupervisorDelete,
+		CreateContext: resourceVsphereSupervisorCreate,
+		ReadContext:   resourceVsphereSupervisorRead,
+		UpdateContext: resourceVsphereSupervisorUpdate,
+		DeleteContext: resourceVsphereSupervisorDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"cluster": {
 				Type:         schema.TypeString,
@@ -228,7 +235,7 @@ func namespaceSchema() *schema.Resource {
 	}
 }
 
-func resourceVsphereSupervisorCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereSupervisorCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	m := namespace.NewManager(c)
 
@@ -236,14 +243,14 @@ func resourceVsphereSupervisorCreate(d *schema.ResourceData, meta interface{}) e
 
 	spec := buildClusterEnableSpec(d)
 
-	if err := m.EnableCluster(context.Background(), clusterID, spec); err != nil {
-		return err
+	if err := m.EnableCluster(ctx, clusterID, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(clusterID)
 
-	if err := waitForSupervisorEnable(m, d); err != nil {
-		return err
+	if err := waitForSupervisorEnable(ctx, m, d); err != nil {
+		return diag.FromErr(err)
 	}
 
 	namespaces := d.Get("namespace").(*schema.Set).List()
@@ -251,22 +258,22 @@ func resourceVsphereSupervisorCreate(d *schema.ResourceData, meta interface{}) e
 	for _, ns := range namespaces {
 		nsData := ns.(map[string]interface{})
 
-		if err := createNamespace(m, nsData, d.Id()); err != nil {
-			return err
+		if err := createNamespace(ctx, m, nsData, d.Id()); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceVsphereSupervisorRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereSupervisorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	m := namespace.NewManager(c)
 
-	cluster := getClusterByID(m, d.Id())
+	cluster := getClusterByID(ctx, m, d.Id())
 
 	if cluster == nil {
-		return fmt.Errorf("could not find cluster %s", d.Id())
+		return diag.Errorf("could not find cluster %s", d.Id())
 	}
 
 	// To fully implement "read" and allow this resource to be imported
@@ -275,7 +282,7 @@ func resourceVsphereSupervisorRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func resourceVsphereSupervisorUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereSupervisorUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if d.HasChange("namespace") {
 		c := meta.(*Client).restClient
 		m := namespace.NewManager(c)
@@ -292,13 +299,13 @@ func resourceVsphereSupervisorUpdate(d *schema.ResourceData, meta interface{}) e
 		for k, v := range oldNamespaces {
 			if _, found := newNamespaces[k]; found {
 				// Namespace still exists but has changed
-				if err := updateNamespace(m, v.(map[string]interface{})); err != nil {
-					return err
+				if err := updateNamespace(ctx, m, v.(map[string]interface{})); err != nil {
+					return diag.FromErr(err)
 				}
 			} else {
 				// Namespace no longer exists
-				if err := deleteNamespace(m, v.(map[string]interface{})); err != nil {
-					return err
+				if err := deleteNamespace(ctx, m, v.(map[string]interface{})); err != nil {
+					return diag.FromErr(err)
 				}
 			}
 		}
@@ -306,8 +313,8 @@ func resourceVsphereSupervisorUpdate(d *schema.ResourceData, meta interface{}) e
 		for k, v := range newNamespaces {
 			if _, found := oldNamespaces[k]; !found {
 				// This is a new namespace
-				if err := createNamespace(m, v.(map[string]interface{}), d.Id()); err != nil {
-					return err
+				if err := createNamespace(ctx, m, v.(map[string]interface{}), d.Id()); err != nil {
+					return diag.FromErr(err)
 				}
 			}
 		}
@@ -315,18 +322,18 @@ func resourceVsphereSupervisorUpdate(d *schema.ResourceData, meta interface{}) e
 		return nil
 	}
 
-	return fmt.Errorf("only updates to namespaces are supported")
+	return diag.Errorf("only updates to namespaces are supported")
 }
 
-func resourceVsphereSupervisorDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereSupervisorDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	m := namespace.NewManager(c)
 
-	if err := m.DisableCluster(context.Background(), d.Id()); err != nil {
-		return err
+	if err := m.DisableCluster(ctx, d.Id()); err != nil {
+		return diag.FromErr(err)
 	}
 
-	return waitForSupervisorDisable(m, d)
+	return diag.FromErr(waitForSupervisorDisable(ctx, m, d))
 }
 
 func buildClusterEnableSpec(d *schema.ResourceData) *namespace.EnableClusterSpec {
@@ -412,15 +419,16 @@ func getSizingHint(data interface{}) *namespace.SizingHint {
 	return &namespace.UndefinedSizingHint
 }
 
-func waitForSupervisorEnable(m *namespace.Manager, d *schema.ResourceData) error {
+func waitForSupervisorEnable(ctx context.Context, m *namespace.Manager, d *schema.ResourceData) error {
 	ticker := time.NewTicker(time.Minute * time.Duration(1))
 	failureCount := 0
 
 	for {
 		select {
-		case <-context.Background().Done():
+		case <-ctx.Done():
+			return ctx.Err()
 		case <-ticker.C:
-			cluster := getClusterByID(m, d.Id())
+			cluster := getClusterByID(ctx, m, d.Id())
 
 			if cluster == nil {
 				return fmt.Errorf("could not find cluster %s", cluster.ID)
@@ -445,14 +453,15 @@ func waitForSupervisorEnable(m *namespace.Manager, d *schema.ResourceData) error
 	}
 }
 
-func waitForSupervisorDisable(m *namespace.Manager, d *schema.ResourceData) error {
+func waitForSupervisorDisable(ctx context.Context, m *namespace.Manager, d *schema.ResourceData) error {
 	ticker := time.NewTicker(time.Minute * time.Duration(1))
 
 	for {
 		select {
-		case <-context.Background().Done():
+		case <-ctx.Done():
+			return ctx.Err()
 		case <-ticker.C:
-			cluster := getClusterByID(m, d.Id())
+			cluster := getClusterByID(ctx, m, d.Id())
 
 			if cluster == nil {
 				return nil
@@ -465,8 +474,8 @@ func waitForSupervisorDisable(m *namespace.Manager, d *schema.ResourceData) erro
 	}
 }
 
-func getClusterByID(m *namespace.Manager, id string) *namespace.ClusterSummary {
-	clusters, err := m.ListClusters(context.Background())
+func getClusterByID(ctx context.Context, m *namespace.Manager, id string) *namespace.ClusterSummary {
+	clusters, err := m.ListClusters(ctx)
 
 	if err != nil {
 		return nil
@@ -492,7 +501,7 @@ func getNamespacesMap(namespaces []interface{}) map[string]interface{} {
 	return result
 }
 
-func createNamespace(m *namespace.Manager, nsData map[string]interface{}, cluster string) error {
+func createNamespace(ctx context.Context, m *namespace.Manager, nsData map[string]interface{}, cluster string) error {
 	namespaceSpec := namespace.NamespacesInstanceCreateSpec{
 		Namespace:     nsData["name"].(string),
 		Cluster:       cluster,
@@ -507,10 +516,10 @@ func createNamespace(m *namespace.Manager, nsData map[string]interface{}, cluste
 		namespaceSpec.VmServiceSpec.VmClasses = structure.SliceInterfacesToStrings(vmClasses.([]interface{}))
 	}
 
-	return m.CreateNamespace(context.Background(), namespaceSpec)
+	return m.CreateNamespace(ctx, namespaceSpec)
 }
 
-func updateNamespace(m *namespace.Manager, nsData map[string]interface{}) error {
+func updateNamespace(ctx context.Context, m *namespace.Manager, nsData map[string]interface{}) error {
 	spec := namespace.NamespacesInstanceUpdateSpec{
 		VmServiceSpec: namespace.VmServiceSpec{},
 	}
@@ -523,9 +532,9 @@ func updateNamespace(m *namespace.Manager, nsData map[string]interface{}) error
 		spec.VmServiceSpec.VmClasses = structure.SliceInterfacesToStrings(vmClasses.([]interface{}))
 	}
 
-	return m.UpdateNamespace(context.Background(), nsData["name"].(string), spec)
+	return m.UpdateNamespace(ctx, nsData["name"].(string), spec)
 }
 
-func deleteNamespace(m *namespace.Manager, nsData map[string]interface{}) error {
-	return m.DeleteNamespace(context.Background(), nsData["name"].(string))
+func deleteNamespace(ctx context.Context, m *namespace.Manager, nsData map[string]interface{}) error {
+	return m.DeleteNamespace(ctx, nsData["name"].(string))
 }
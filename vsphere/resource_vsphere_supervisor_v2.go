@@ -24,6 +24,8 @@ func resourceVsphereSupervisorV2() *schema.Resource {
 		DeleteContext: resourceVsphereSupervisorV2Delete,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -5,10 +5,13 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -121,23 +124,29 @@ func resourceVSphereVAppContainer() *schema.Resource {
 		customattribute.ConfigKey: customattribute.ConfigSchema(),
 	}
 	return &schema.Resource{
-		Create: resourceVSphereVAppContainerCreate,
-		Read:   resourceVSphereVAppContainerRead,
-		Update: resourceVSphereVAppContainerUpdate,
-		Delete: resourceVSphereVAppContainerDelete,
+		CreateContext: resourceVSphereVAppContainerCreate,
+		ReadContext:   resourceVSphereVAppContainerRead,
+		UpdateContext: resourceVSphereVAppContainerUpdate,
+		DeleteContext: resourceVSphereVAppContainerDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVAppContainerImport,
+			StateContext: resourceVSphereVAppContainerImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereVAppContainerImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVAppContainerImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
 		return nil, err
 	}
-	vc, err := vappcontainer.FromPath(client, d.Id(), nil)
+	vc, err := vappcontainer.FromPath(ctx, client, d.Id(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -145,150 +154,150 @@ func resourceVSphereVAppContainerImport(d *schema.ResourceData, meta interface{}
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceVSphereVAppContainerCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	prp, err := resourcepool.FromID(client, d.Get("parent_resource_pool_id").(string))
+	prp, err := resourcepool.FromID(ctx, client, d.Get("parent_resource_pool_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	rpSpec := expandVAppContainerConfigSpec(d)
 	vcSpec := &types.VAppConfigSpec{}
 	var f *object.Folder
 
-	if vappcontainer.IsVApp(client, d.Get("parent_resource_pool_id").(string)) {
+	if vappcontainer.IsVApp(ctx, client, d.Get("parent_resource_pool_id").(string)) {
 		f = nil
 	} else {
 		if pf, ok := d.GetOk("parent_folder_id"); ok {
-			f, err = folder.FromID(client, pf.(string))
+			f, err = folder.FromID(ctx, client, pf.(string))
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		} else {
 			p := strings.Split(prp.InventoryPath, "/")
 			if len(p) < 2 {
-				return fmt.Errorf("unable to locate datacenter name from parent resource pool")
+				return diag.Errorf("unable to locate datacenter name from parent resource pool")
 			}
 			var dc *object.Datacenter
-			dc, err = getDatacenter(client, p[1])
+			dc, err = getDatacenter(ctx, client, p[1])
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
-			f, err = folder.FromPath(client, "", folder.VSphereFolderTypeVM, dc)
+			f, err = folder.FromPath(ctx, client, "", folder.VSphereFolderTypeVM, dc)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	vc, err := vappcontainer.Create(prp, d.Get("name").(string), rpSpec, vcSpec, f)
+	vc, err := vappcontainer.Create(ctx, prp, d.Get("name").(string), rpSpec, vcSpec, f)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	if err = resourceVSphereVAppContainerApplyTags(d, meta, vc); err != nil {
-		return err
+	if err = resourceVSphereVAppContainerApplyTags(ctx, d, meta, vc); err != nil {
+		return diag.FromErr(err)
 	}
 	d.SetId(vc.Reference().Value)
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereVAppContainerIDString(d))
-	return resourceVSphereVAppContainerRead(d, meta)
+	return resourceVSphereVAppContainerRead(ctx, d, meta)
 }
 
-func resourceVSphereVAppContainerRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	vc, err := vappcontainer.FromID(client, d.Id())
+	vc, err := vappcontainer.FromID(ctx, client, d.Id())
 	if err != nil {
 		if viapi.IsManagedObjectNotFoundError(err) {
 			log.Printf("[DEBUG] %s: Resource has been deleted", resourceVSphereVAppContainerIDString(d))
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
-	if err = resourceVSphereVAppContainerReadTags(d, meta, vc); err != nil {
-		return err
+	if err = resourceVSphereVAppContainerReadTags(ctx, d, meta, vc); err != nil {
+		return diag.FromErr(err)
 	}
 	if err = d.Set("name", vc.Name()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	vcProps, err := vappcontainer.Properties(vc)
+	vcProps, err := vappcontainer.Properties(ctx, vc)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if vcProps.Parent != nil {
 		if err = d.Set("parent_resource_pool_id", vcProps.Parent.Value); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if vcProps.ParentFolder != nil {
 		if err = d.Set("parent_folder_id", vcProps.ParentFolder.Value); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if err = flattenVAppContainerConfigSpec(d, vcProps.Config); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Read finished successfully", resourceVSphereVAppContainerIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppContainerUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	vc, err := vappcontainer.FromID(client, d.Id())
+	vc, err := vappcontainer.FromID(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	if err = resourceVSphereVAppContainerApplyTags(d, meta, vc); err != nil {
-		return err
+	if err = resourceVSphereVAppContainerApplyTags(ctx, d, meta, vc); err != nil {
+		return diag.FromErr(err)
 	}
 	op, np := d.GetChange("parent_resource_pool_id")
 	if op != np {
 		log.Printf("[DEBUG] %s: Parent resource pool has changed. Moving from %s, to %s", resourceVSphereVAppContainerIDString(d), op, np)
 		var p *object.ResourcePool
-		p, err = resourcepool.FromID(client, np.(string))
+		p, err = resourcepool.FromID(ctx, client, np.(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		if err = resourcepool.MoveIntoResourcePool(p, vc.Reference()); err != nil {
-			return err
+		if err = resourcepool.MoveIntoResourcePool(ctx, p, vc.Reference()); err != nil {
+			return diag.FromErr(err)
 		}
 		log.Printf("[DEBUG] %s: Move finished successfully", resourceVSphereVAppContainerIDString(d))
 	}
 
 	vcSpec := types.VAppConfigSpec{}
-	if err = vappcontainer.Update(vc, vcSpec); err != nil {
-		return err
+	if err = vappcontainer.Update(ctx, vc, vcSpec); err != nil {
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereVAppContainerIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppContainerDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	vc, err := vappcontainer.FromID(client, d.Id())
+	vc, err := vappcontainer.FromID(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	if err = resourceVSphereVAppContainerValidateEmpty(vc); err != nil {
-		return err
+	if err = resourceVSphereVAppContainerValidateEmpty(ctx, vc); err != nil {
+		return diag.FromErr(err)
 	}
-	if err = vappcontainer.Delete(vc); err != nil {
-		return err
+	if err = vappcontainer.Delete(ctx, vc); err != nil {
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereVAppContainerIDString(d))
 	return nil
@@ -342,8 +351,8 @@ func resourceVSphereVAppContainerClient(meta interface{}) (*govmomi.Client, erro
 	return client, nil
 }
 
-func resourceVSphereVAppContainerValidateEmpty(va *object.VirtualApp) error {
-	ne, err := vappcontainer.HasChildren(va)
+func resourceVSphereVAppContainerValidateEmpty(ctx context.Context, va *object.VirtualApp) error {
+	ne, err := vappcontainer.HasChildren(ctx, va)
 	if err != nil {
 		return fmt.Errorf("error checking contents of vApp container: %s", err)
 	}
@@ -355,7 +364,7 @@ func resourceVSphereVAppContainerValidateEmpty(va *object.VirtualApp) error {
 
 // resourceVSphereVAppContainerApplyTags processes the tags step for both create
 // and update for vsphere_vapp_container.
-func resourceVSphereVAppContainerApplyTags(d *schema.ResourceData, meta interface{}, va *object.VirtualApp) error {
+func resourceVSphereVAppContainerApplyTags(ctx context.Context, d *schema.ResourceData, meta interface{}, va *object.VirtualApp) error {
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
 		return err
@@ -368,15 +377,15 @@ func resourceVSphereVAppContainerApplyTags(d *schema.ResourceData, meta interfac
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereVAppContainerIDString(d))
-	return processTagDiff(tagsClient, d, va)
+	return processTagDiff(ctx, tagsClient, d, va)
 }
 
 // resourceVSphereVAppContainerReadTags reads the tags for
 // vsphere_vapp_container.
-func resourceVSphereVAppContainerReadTags(d *schema.ResourceData, meta interface{}, va *object.VirtualApp) error {
+func resourceVSphereVAppContainerReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, va *object.VirtualApp) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereVAppContainerIDString(d))
-		if err := readTagsForResource(tagsClient, va, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, va, d); err != nil {
 			return err
 		}
 	} else {
@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -280,7 +281,7 @@ func testAccResourceVSphereVAppContainerCheckFolder(expected string) resource.Te
 		if err != nil {
 			return err
 		}
-		vcp, err := vappcontainer.Properties(vc)
+		vcp, err := vappcontainer.Properties(context.Background(), vc)
 		if err != nil {
 			return err
 		}
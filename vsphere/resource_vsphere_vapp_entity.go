@@ -5,10 +5,13 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -76,18 +79,24 @@ func resourceVSphereVAppEntity() *schema.Resource {
 		customattribute.ConfigKey: customattribute.ConfigSchema(),
 	}
 	return &schema.Resource{
-		Create: resourceVSphereVAppEntityCreate,
-		Read:   resourceVSphereVAppEntityRead,
-		Update: resourceVSphereVAppEntityUpdate,
-		Delete: resourceVSphereVAppEntityDelete,
+		CreateContext: resourceVSphereVAppEntityCreate,
+		ReadContext:   resourceVSphereVAppEntityRead,
+		UpdateContext: resourceVSphereVAppEntityUpdate,
+		DeleteContext: resourceVSphereVAppEntityDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVAppEntityImport,
+			StateContext: resourceVSphereVAppEntityImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereVAppEntityImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVAppEntityImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client, err := resourceVSphereVAppEntityClient(meta)
 	if err != nil {
 		return nil, err
@@ -96,11 +105,11 @@ func resourceVSphereVAppEntityImport(d *schema.ResourceData, meta interface{}) (
 	if err != nil {
 		return nil, err
 	}
-	vc, err := vappcontainer.FromID(client, vcID)
+	vc, err := vappcontainer.FromID(ctx, client, vcID)
 	if err != nil {
 		return nil, err
 	}
-	vcProps, err := vappcontainer.Properties(vc)
+	vcProps, err := vappcontainer.Properties(ctx, vc)
 	if err != nil {
 		return nil, err
 	}
@@ -111,55 +120,55 @@ func resourceVSphereVAppEntityImport(d *schema.ResourceData, meta interface{}) (
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceVSphereVAppEntityCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	container, err := vappcontainer.FromID(client, d.Get("container_id").(string))
+	container, err := vappcontainer.FromID(ctx, client, d.Get("container_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	cProps, err := vappcontainer.Properties(container)
+	cProps, err := vappcontainer.Properties(ctx, container)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(resourceVSphereVAppEntityIDFromKeys(d.Get("container_id").(string), d.Get("target_id").(string)))
-	entityConfig, err := expandVAppEntityConfigSpec(client, d)
+	entityConfig, err := expandVAppEntityConfigSpec(ctx, client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cProps.VAppConfig.EntityConfig = append(cProps.VAppConfig.EntityConfig, *entityConfig)
 	updateSpec := types.VAppConfigSpec{
 		EntityConfig: cProps.VAppConfig.EntityConfig,
 	}
-	if err = vappcontainer.Update(container, updateSpec); err != nil {
-		return err
+	if err = vappcontainer.Update(ctx, container, updateSpec); err != nil {
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereVAppEntityIDString(d))
-	return resourceVSphereVAppEntityRead(d, meta)
+	return resourceVSphereVAppEntityRead(ctx, d, meta)
 }
 
-func resourceVSphereVAppEntityRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	vcID, vmID, err := resourceVSphereVAppEntitySplitID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("container_id", vcID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("target_id", vmID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	entity, err := resourceVSphereVAppEntityFind(client, d.Id())
+	entity, err := resourceVSphereVAppEntityFind(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if entity == nil {
 		log.Printf("[DEBUG] %s: Resource has been deleted", resourceVSphereVAppEntityIDString(d))
@@ -168,58 +177,58 @@ func resourceVSphereVAppEntityRead(d *schema.ResourceData, meta interface{}) err
 	}
 	err = flattenVAppEntityConfigSpec(client, d, entity)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Read finished successfully", resourceVSphereVAppEntityIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppEntityUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	container, err := vappcontainer.FromID(client, d.Get("container_id").(string))
+	container, err := vappcontainer.FromID(ctx, client, d.Get("container_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	cProps, err := vappcontainer.Properties(container)
+	cProps, err := vappcontainer.Properties(ctx, container)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	entityConfig, err := expandVAppEntityConfigSpec(client, d)
+	entityConfig, err := expandVAppEntityConfigSpec(ctx, client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cProps.VAppConfig.EntityConfig = []types.VAppEntityConfigInfo{*entityConfig}
 	updateSpec := types.VAppConfigSpec{
 		EntityConfig: cProps.VAppConfig.EntityConfig,
 	}
-	if err = vappcontainer.Update(container, updateSpec); err != nil {
-		return err
+	if err = vappcontainer.Update(ctx, container, updateSpec); err != nil {
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereVAppEntityIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppEntityDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	container, err := vappcontainer.FromID(client, d.Get("container_id").(string))
+	container, err := vappcontainer.FromID(ctx, client, d.Get("container_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	cProps, err := vappcontainer.Properties(container)
+	cProps, err := vappcontainer.Properties(ctx, container)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	vm, err := virtualmachine.FromMOID(client, d.Get("target_id").(string))
+	vm, err := virtualmachine.FromMOID(ctx, client, d.Get("target_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	var el []types.VAppEntityConfigInfo
 	for _, e := range cProps.VAppConfig.EntityConfig {
@@ -230,22 +239,22 @@ func resourceVSphereVAppEntityDelete(d *schema.ResourceData, meta interface{}) e
 	updateSpec := types.VAppConfigSpec{
 		EntityConfig: el,
 	}
-	if err = vappcontainer.Update(container, updateSpec); err != nil {
-		return err
+	if err = vappcontainer.Update(ctx, container, updateSpec); err != nil {
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Delete finished successfully", resourceVSphereVAppEntityIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppEntityFind(client *govmomi.Client, id string) (*types.VAppEntityConfigInfo, error) {
+func resourceVSphereVAppEntityFind(ctx context.Context, client *govmomi.Client, id string) (*types.VAppEntityConfigInfo, error) {
 	parts := strings.SplitN(id, ":", 2)
 	cid := parts[0]
 	eid := parts[1]
-	container, err := vappcontainer.FromID(client, cid)
+	container, err := vappcontainer.FromID(ctx, client, cid)
 	if err != nil {
 		return nil, err
 	}
-	props, err := vappcontainer.Properties(container)
+	props, err := vappcontainer.Properties(ctx, container)
 	if err != nil {
 		return nil, err
 	}
@@ -270,12 +279,12 @@ func flattenVAppEntityConfigSpec(_ *govmomi.Client, d *schema.ResourceData, obj
 	})
 }
 
-func expandVAppEntityConfigSpec(client *govmomi.Client, d *schema.ResourceData) (*types.VAppEntityConfigInfo, error) {
+func expandVAppEntityConfigSpec(ctx context.Context, client *govmomi.Client, d *schema.ResourceData) (*types.VAppEntityConfigInfo, error) {
 	_, vmID, err := resourceVSphereVAppEntitySplitID(d.Id())
 	if err != nil {
 		return nil, err
 	}
-	vm, err := virtualmachine.FromMOID(client, vmID)
+	vm, err := virtualmachine.FromMOID(ctx, client, vmID)
 	if err != nil {
 		return nil, err
 	}
@@ -14,12 +14,15 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualdisk"
 )
 
@@ -38,12 +41,18 @@ type virtualDisk struct {
 // Define VirtualDisk args
 func resourceVSphereVirtualDisk() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereVirtualDiskCreate,
-		Read:   resourceVSphereVirtualDiskRead,
-		Update: resourceVSphereVirtualDiskUpdate,
-		Delete: resourceVSphereVirtualDiskDelete,
+		CreateContext: resourceVSphereVirtualDiskCreate,
+		ReadContext:   resourceVSphereVirtualDiskRead,
+		UpdateContext: resourceVSphereVirtualDiskUpdate,
+		DeleteContext: resourceVSphereVirtualDiskDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVirtualDiskImport,
+			StateContext: resourceVSphereVirtualDiskImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -123,7 +132,7 @@ func resourceVSphereVirtualDisk() *schema.Resource {
 	}
 }
 
-func resourceVSphereVirtualDiskCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualDiskCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Creating Virtual Disk")
 	client := meta.(*Client).vimClient
 
@@ -157,15 +166,15 @@ func resourceVSphereVirtualDiskCreate(d *schema.ResourceData, meta interface{})
 
 	finder := find.NewFinder(client.Client, true)
 
-	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	dc, err := getDatacenter(ctx, client, d.Get("datacenter").(string))
 	if err != nil {
-		return fmt.Errorf("error finding datacenter: %s: %s", vDisk.datacenter, err)
+		return diag.Errorf("error finding datacenter: %s: %s", vDisk.datacenter, err)
 	}
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, vDisk.datastore)
+	ds, err := getDatastore(ctx, finder, vDisk.datastore)
 	if err != nil {
-		return fmt.Errorf("error finding datastore: %s: %s", vDisk.datastore, err)
+		return diag.Errorf("error finding datastore: %s: %s", vDisk.datastore, err)
 	}
 
 	fm := object.NewFileManager(client.Client)
@@ -179,33 +188,33 @@ func resourceVSphereVirtualDiskCreate(d *schema.ResourceData, meta interface{})
 			vsphereVirtualDiskMakeDirectoryMutex.Lock()
 			vmdkPath := vDisk.vmdkPath[0:directoryPathIndex]
 			log.Printf("[DEBUG] Creating parent directories: %v", ds.Path(vmdkPath))
-			err = fm.MakeDirectory(context.TODO(), ds.Path(vmdkPath), dc, true)
+			err = fm.MakeDirectory(ctx, ds.Path(vmdkPath), dc, true)
 			vsphereVirtualDiskMakeDirectoryMutex.Unlock()
 			if err != nil && !isAlreadyExists(err) {
 				log.Printf("[DEBUG] Failed to create parent directories:  %v", err)
-				return err
+				return diag.FromErr(err)
 			}
 
-			err = searchForDirectory(client, vDisk.datacenter, vDisk.datastore, vmdkPath)
+			err = searchForDirectory(ctx, client, vDisk.datacenter, vDisk.datastore, vmdkPath)
 			if err != nil {
 				log.Printf("[DEBUG] Failed to find newly created parent directories:  %v", err)
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	err = createHardDisk(client, vDisk.size, ds.Path(vDisk.vmdkPath), vDisk.initType, vDisk.adapterType, vDisk.datacenter)
+	err = createHardDisk(ctx, client, vDisk.size, ds.Path(vDisk.vmdkPath), vDisk.initType, vDisk.adapterType, vDisk.datacenter)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(ds.Path(vDisk.vmdkPath))
 	log.Printf("[DEBUG] Virtual Disk id: %v", ds.Path(vDisk.vmdkPath))
 
-	return resourceVSphereVirtualDiskRead(d, meta)
+	return resourceVSphereVirtualDiskRead(ctx, d, meta)
 }
 
-func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualDiskRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Reading virtual disk.")
 	client := meta.(*Client).vimClient
 
@@ -233,23 +242,22 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 		vDisk.datastore = v.(string)
 	}
 
-	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	dc, err := getDatacenter(ctx, client, d.Get("datacenter").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	finder := find.NewFinder(client.Client, true)
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := finder.Datastore(context.TODO(), d.Get("datastore").(string))
+	ds, err := finder.Datastore(ctx, d.Get("datastore").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	ctx := context.TODO()
 	b, err := ds.Browser(ctx)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// `Datastore.Stat` does not allow to query `VmDiskFileQuery`. Instead, we
@@ -269,14 +277,14 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	dsPath := ds.Path(path.Dir(vDisk.vmdkPath))
-	task, err := b.SearchDatastore(context.TODO(), dsPath, &spec)
+	task, err := b.SearchDatastore(ctx, dsPath, &spec)
 
 	if err != nil {
 		log.Printf("[DEBUG] resourceVSphereVirtualDiskRead - could not search datastore for: %v", vDisk.vmdkPath)
-		return err
+		return diag.FromErr(err)
 	}
 
-	info, err := task.WaitForResultEx(context.TODO(), nil)
+	info, err := viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		if info != nil && info.Error != nil {
 			_, ok := info.Error.Fault.(*types.FileNotFound)
@@ -288,7 +296,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 		}
 
 		log.Printf("[DEBUG] resourceVSphereVirtualDiskRead - could not search datastore for: %v", vDisk.vmdkPath)
-		return err
+		return diag.FromErr(err)
 	}
 
 	res := info.Result.(types.HostDatastoreBrowserSearchResults)
@@ -300,7 +308,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	if len(res.File) != 1 {
-		return errors.New("datastore search did not return exactly one result")
+		return diag.FromErr(errors.New("datastore search did not return exactly one result"))
 	}
 
 	fileInfo := res.File[0]
@@ -311,7 +319,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 		Datastore: vDisk.datastore,
 		Path:      vDisk.vmdkPath,
 	}
-	diskType, err := virtualdisk.QueryDiskType(client, dp.String(), dc)
+	diskType, err := virtualdisk.QueryDiskType(ctx, client, dp.String(), dc)
 	/**
 	Thick Provisioned Lazy Zeroed disk type a.k.a "lazy" in the provider context is actually
 	"preallocated" for the VC. Due to historical reasons i.e. the disk type is documented as "lazy".
@@ -323,7 +331,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	if err != nil {
-		return errors.New("failed to query disk type")
+		return diag.FromErr(errors.New("failed to query disk type"))
 	}
 
 	// adapter_type is deprecated, so just default.
@@ -341,13 +349,13 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	return nil
 }
 
-func resourceVSphereVirtualDiskUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualDiskUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Updating Virtual Disk")
 	client := meta.(*Client).vimClient
 
 	oldSize, newSize := d.GetChange("size")
 	if newSize.(int) < oldSize.(int) {
-		return fmt.Errorf("shrinking a virtual disk is not supported")
+		return diag.Errorf("shrinking a virtual disk is not supported")
 	}
 
 	vDisk := virtualDisk{
@@ -368,25 +376,25 @@ func resourceVSphereVirtualDiskUpdate(d *schema.ResourceData, meta interface{})
 
 	finder := find.NewFinder(client.Client, true)
 
-	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	dc, err := getDatacenter(ctx, client, d.Get("datacenter").(string))
 	if err != nil {
-		return fmt.Errorf("error finding Datacenter: %s: %s", vDisk.datacenter, err)
+		return diag.Errorf("error finding Datacenter: %s: %s", vDisk.datacenter, err)
 	}
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, vDisk.datastore)
+	ds, err := getDatastore(ctx, finder, vDisk.datastore)
 	if err != nil {
-		return fmt.Errorf("error finding Datastore: %s: %s", vDisk.datastore, err)
+		return diag.Errorf("error finding Datastore: %s: %s", vDisk.datastore, err)
 	}
 
-	if err := extendHardDisk(client, vDisk.size, ds.Path(vDisk.vmdkPath), vDisk.datacenter); err != nil {
-		return err
+	if err := extendHardDisk(ctx, client, vDisk.size, ds.Path(vDisk.vmdkPath), vDisk.datacenter); err != nil {
+		return diag.FromErr(err)
 	}
 
-	return resourceVSphereVirtualDiskRead(d, meta)
+	return resourceVSphereVirtualDiskRead(ctx, d, meta)
 }
 
-func resourceVSphereVirtualDiskDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualDiskDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	vDisk := virtualDisk{}
@@ -398,32 +406,32 @@ func resourceVSphereVirtualDiskDelete(d *schema.ResourceData, meta interface{})
 		vDisk.datastore = v.(string)
 	}
 
-	dc, err := getDatacenter(client, d.Get("datacenter").(string))
+	dc, err := getDatacenter(ctx, client, d.Get("datacenter").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	finder := find.NewFinder(client.Client, true)
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, vDisk.datastore)
+	ds, err := getDatastore(ctx, finder, vDisk.datastore)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	diskPath := ds.Path(vDisk.vmdkPath)
 
 	virtualDiskManager := object.NewVirtualDiskManager(client.Client)
 
-	task, err := virtualDiskManager.DeleteVirtualDisk(context.TODO(), diskPath, dc)
+	task, err := virtualDiskManager.DeleteVirtualDisk(ctx, diskPath, dc)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	_, err = task.WaitForResultEx(context.TODO(), nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		log.Printf("[INFO] Failed to delete disk:  %v", err)
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[INFO] Deleted disk: %v", diskPath)
@@ -437,7 +445,7 @@ func isAlreadyExists(err error) bool {
 }
 
 // createHardDisk creates a new Hard Disk.
-func createHardDisk(client *govmomi.Client, size int, diskPath string, diskType string, adapterType string, dc string) error {
+func createHardDisk(ctx context.Context, client *govmomi.Client, size int, diskPath string, diskType string, adapterType string, dc string) error {
 	var vDiskType string
 	switch diskType {
 	case "thin":
@@ -456,18 +464,18 @@ func createHardDisk(client *govmomi.Client, size int, diskPath string, diskType
 		},
 		CapacityKb: int64(1024 * 1024 * size),
 	}
-	datacenter, err := getDatacenter(client, dc)
+	datacenter, err := getDatacenter(ctx, client, dc)
 	if err != nil {
 		return err
 	}
 	log.Printf("[DEBUG] Disk spec: %v", spec)
 
-	task, err := virtualDiskManager.CreateVirtualDisk(context.TODO(), diskPath, datacenter, spec)
+	task, err := virtualDiskManager.CreateVirtualDisk(ctx, diskPath, datacenter, spec)
 	if err != nil {
 		return err
 	}
 
-	_, err = task.WaitForResultEx(context.TODO(), nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		log.Printf("[INFO] Failed to create disk:  %v", err)
 		return err
@@ -477,20 +485,20 @@ func createHardDisk(client *govmomi.Client, size int, diskPath string, diskType
 	return nil
 }
 
-func extendHardDisk(client *govmomi.Client, capacity int, diskPath string, dc string) error {
+func extendHardDisk(ctx context.Context, client *govmomi.Client, capacity int, diskPath string, dc string) error {
 	virtualDiskManager := object.NewVirtualDiskManager(client.Client)
-	datacenter, err := getDatacenter(client, dc)
+	datacenter, err := getDatacenter(ctx, client, dc)
 	if err != nil {
 		return err
 	}
 
 	capacityKb := int64(1024 * 1024 * capacity)
-	task, err := virtualDiskManager.ExtendVirtualDisk(context.TODO(), diskPath, datacenter, capacityKb, nil)
+	task, err := virtualDiskManager.ExtendVirtualDisk(ctx, diskPath, datacenter, capacityKb, nil)
 	if err != nil {
 		return err
 	}
 
-	_, err = task.WaitForResultEx(context.TODO(), nil)
+	_, err = viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		log.Printf("[INFO] Failed to extend disk:  %v", err)
 		return err
@@ -501,22 +509,21 @@ func extendHardDisk(client *govmomi.Client, capacity int, diskPath string, dc st
 }
 
 // Searches for the presence of a directory path.
-func searchForDirectory(client *govmomi.Client, datacenter string, datastore string, directoryPath string) error {
+func searchForDirectory(ctx context.Context, client *govmomi.Client, datacenter string, datastore string, directoryPath string) error {
 	log.Printf("[DEBUG] Searching for Directory")
 	finder := find.NewFinder(client.Client, true)
 
-	dc, err := getDatacenter(client, datacenter)
+	dc, err := getDatacenter(ctx, client, datacenter)
 	if err != nil {
 		return fmt.Errorf("error finding datacenter: %s: %s", datacenter, err)
 	}
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := finder.Datastore(context.TODO(), datastore)
+	ds, err := finder.Datastore(ctx, datastore)
 	if err != nil {
 		return fmt.Errorf("error finding datastore: %s: %s", datastore, err)
 	}
 
-	ctx := context.TODO()
 	b, err := ds.Browser(ctx)
 	if err != nil {
 		return err
@@ -534,14 +541,14 @@ func searchForDirectory(client *govmomi.Client, datacenter string, datastore str
 	}
 
 	dsPath := ds.Path(path.Dir(directoryPath))
-	task, err := b.SearchDatastore(context.TODO(), dsPath, &spec)
+	task, err := b.SearchDatastore(ctx, dsPath, &spec)
 
 	if err != nil {
 		log.Printf("[DEBUG] searchForDirectory - could not search datastore for: %v", directoryPath)
 		return err
 	}
 
-	info, err := task.WaitForResultEx(context.TODO(), nil)
+	info, err := viapi.WaitForTaskResult(ctx, task)
 	if err != nil {
 		if info != nil && info.Error != nil {
 			_, ok := info.Error.Fault.(*types.FileNotFound)
@@ -572,7 +579,7 @@ func searchForDirectory(client *govmomi.Client, datacenter string, datastore str
 	return nil
 }
 
-func resourceVSphereVirtualDiskImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVirtualDiskImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).vimClient
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
@@ -601,12 +608,12 @@ func resourceVSphereVirtualDiskImport(d *schema.ResourceData, meta interface{})
 		return nil, errors.New("ID must be of the form /<datacenter>/[<datastore>] path/to/vmdk")
 	}
 
-	dc, err := getDatacenter(client, addrParts[1])
+	dc, err := getDatacenter(ctx, client, addrParts[1])
 	if err != nil {
 		return nil, err
 	}
 
-	di, err := virtualdisk.FromPath(client, addrParts[2], dc)
+	di, err := virtualdisk.FromPath(ctx, client, addrParts[2], dc)
 	if err != nil {
 		return nil, err
 	}
@@ -12,9 +12,12 @@ import (
 	"net"
 	"os"
 	"path"
+	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -29,6 +32,7 @@ import (
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/guestoscustomizations"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/ovfdeploy"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/provider"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/spbm"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/storagepod"
@@ -120,6 +124,13 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 			Computed:    true,
 			Description: "The ID of an optional host system to pin the virtual machine to.",
 		},
+		"target_vcenter": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "The vCenter Server instance to manage the virtual machine on, when it differs from the one the provider is connected to. Changing this migrates the virtual machine across vCenter Server instances.",
+			MaxItems:    1,
+			Elem:        &schema.Resource{Schema: schemaVirtualMachineTargetVCenter()},
+		},
 		"wait_for_guest_ip_timeout": {
 			Type:        schema.TypeInt,
 			Optional:    true,
@@ -138,6 +149,53 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 			Default:     true,
 			Description: "Controls whether or not the guest network waiter waits for a routable address. When false, the waiter does not wait for a default gateway, nor are IP addresses checked against any discovered default gateways as part of its success criteria.",
 		},
+		"wait_for_guest": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Waits for a guest readiness condition after the virtual machine is created or powered on. Exactly one of guestinfo_key, tools_ready, or property must be set.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"timeout": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Default:     5,
+						Description: "The amount of time, in minutes, to wait for the condition. A value less than 1 disables the waiter.",
+					},
+					"guestinfo_key": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Description:  "The name of a guestinfo variable to wait for, with or without the guestinfo. prefix.",
+						ExactlyOneOf: []string{"wait_for_guest.0.guestinfo_key", "wait_for_guest.0.tools_ready", "wait_for_guest.0.property"},
+					},
+					"tools_ready": {
+						Type:         schema.TypeBool,
+						Optional:     true,
+						Description:  "Wait for VMware Tools to be running with a green heartbeat.",
+						ExactlyOneOf: []string{"wait_for_guest.0.guestinfo_key", "wait_for_guest.0.tools_ready", "wait_for_guest.0.property"},
+					},
+					"property": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Description:  "The path of a virtual machine property to wait for, for example summary.guest.toolsStatus. Without value or regex, waits for the property to change.",
+						ExactlyOneOf: []string{"wait_for_guest.0.guestinfo_key", "wait_for_guest.0.tools_ready", "wait_for_guest.0.property"},
+					},
+					"value": {
+						Type:          schema.TypeString,
+						Optional:      true,
+						Description:   "The value that guestinfo_key or property must reach.",
+						ConflictsWith: []string{"wait_for_guest.0.regex", "wait_for_guest.0.tools_ready"},
+					},
+					"regex": {
+						Type:          schema.TypeString,
+						Optional:      true,
+						Description:   "A regular expression that the value of guestinfo_key or property must match.",
+						ValidateFunc:  validation.StringIsValidRegExp,
+						ConflictsWith: []string{"wait_for_guest.0.value", "wait_for_guest.0.tools_ready"},
+					},
+				},
+			},
+		},
 		"ignored_guest_ips": {
 			Type:        schema.TypeList,
 			Optional:    true,
@@ -260,6 +318,46 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 			MaxItems:    1,
 			Elem:        &schema.Resource{Schema: virtualdevice.VideoCardSubresourceSchema()},
 		},
+		"serial_port": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "A specification for a serial port device on this virtual machine.",
+			MaxItems:    32,
+			Elem:        &schema.Resource{Schema: virtualdevice.SerialPortSubresourceSchema()},
+		},
+		"parallel_port": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "A specification for a parallel port device on this virtual machine.",
+			MaxItems:    3,
+			Elem:        &schema.Resource{Schema: virtualdevice.ParallelPortSubresourceSchema()},
+		},
+		"usb_controller": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "A specification for a USB controller on this virtual machine.",
+			MaxItems:    2,
+			Elem:        &schema.Resource{Schema: virtualdevice.UsbControllerSubresourceSchema()},
+		},
+		"usb_device": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "A specification for a USB passthrough device on this virtual machine.",
+			MaxItems:    20,
+			Elem:        &schema.Resource{Schema: virtualdevice.UsbDeviceSubresourceSchema()},
+		},
+		"dynamic_pci_device": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "A specification for a dynamic DirectPath I/O device on this virtual machine.",
+			Elem:        &schema.Resource{Schema: virtualdevice.DynamicPciDeviceSubresourceSchema()},
+		},
+		"vgpu_profile": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "A specification for a vGPU device on this virtual machine.",
+			Elem:        &schema.Resource{Schema: virtualdevice.VgpuProfileSubresourceSchema()},
+		},
 		"pci_device_id": {
 			Type:         schema.TypeSet,
 			Optional:     true,
@@ -274,6 +372,20 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 			MaxItems:    1,
 			Elem:        &schema.Resource{Schema: vmworkflow.VirtualMachineCloneSchema()},
 		},
+		"cloud_init": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "The cloud-init metadata, user data, and vendor data for the virtual machine, passed to the guest through guestinfo.",
+			MaxItems:    1,
+			Elem:        &schema.Resource{Schema: schemaVirtualMachineCloudInit()},
+		},
+		"encryption": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "The encryption settings for the virtual machine. Requires storage_policy_id to be set to an encryption storage policy.",
+			MaxItems:    1,
+			Elem:        &schema.Resource{Schema: schemaVirtualMachineEncryption()},
+		},
 		"ovf_deploy": {
 			Type:        schema.TypeList,
 			Optional:    true,
@@ -330,30 +442,40 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 	structure.MergeSchema(s, schemaVirtualMachineGuestInfo())
 
 	return &schema.Resource{
-		Create:        resourceVSphereVirtualMachineCreate,
-		Read:          resourceVSphereVirtualMachineRead,
-		Update:        resourceVSphereVirtualMachineUpdate,
-		Delete:        resourceVSphereVirtualMachineDelete,
+		CreateContext: resourceVSphereVirtualMachineCreate,
+		ReadContext:   resourceVSphereVirtualMachineRead,
+		UpdateContext: resourceVSphereVirtualMachineUpdate,
+		DeleteContext: resourceVSphereVirtualMachineDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		CustomizeDiff: resourceVSphereVirtualMachineCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVirtualMachineImport,
+			StateContext: resourceVSphereVirtualMachineImport,
 		},
 		SchemaVersion: 3,
 		Schema:        s,
 	}
 }
 
-func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereVirtualMachineIDString(d))
+	meta, err := resourceVSphereVirtualMachineClient(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	client := meta.(*Client).vimClient
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	var vm *object.VirtualMachine
@@ -363,74 +485,76 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 	// The VM should also be returned powered on.
 	switch {
 	case len(d.Get("clone").([]interface{})) > 0:
-		vm, err = resourceVSphereVirtualMachineCreateClone(d, meta)
+		vm, err = resourceVSphereVirtualMachineCreateClone(ctx, d, meta)
 	case len(d.Get("ovf_deploy").([]interface{})) > 0:
-		vm, err = resourceVsphereMachineDeployOvfAndOva(d, meta)
+		vm, err = resourceVsphereMachineDeployOvfAndOva(ctx, d, meta)
 	default:
-		vm, err = resourceVSphereVirtualMachineCreateBare(d, meta)
+		vm, err = resourceVSphereVirtualMachineCreateBare(ctx, d, meta)
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Tag the VM
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, vm); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, vm); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(vm); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, vm); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Verify that host_system_id is set if pci_device_id is used.
 	pciDev := d.Get("pci_device_id").(*schema.Set)
 	if pciDev.Len() > 0 && d.Get("host_system_id").(string) == "" {
-		return fmt.Errorf("host_system_id must be set when using pci_device_id")
+		return diag.Errorf("host_system_id must be set when using pci_device_id")
 	}
 
 	// The host attribute of CreateVM_Task seems to be ignored in vCenter 6.7.
 	// Ensure that VMs are on the correct host and relocate if necessary. Do this
 	// near the end of the VM creation since it involves updating the
 	// ResourceData.
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if hid, ok := d.GetOk("host_system_id"); hid.(string) != vprops.Runtime.Host.Reference().Value && ok {
-		err = resourceVSphereVirtualMachineRead(d, meta)
+		err = provider.DiagnosticsError(resourceVSphereVirtualMachineRead(ctx, d, meta))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		// Restore the old host_system_id so we can still tell if a relocation is
 		// necessary.
 		err = d.Set("host_system_id", hid.(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		if err = resourceVSphereVirtualMachineUpdateLocation(d, meta); err != nil {
-			return err
+		if err = resourceVSphereVirtualMachineUpdateLocation(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Wait for guest IP address if we have been set to wait for one
 	err = virtualmachine.WaitForGuestIP(
+		ctx,
 		client,
 		vm,
 		d.Get("wait_for_guest_ip_timeout").(int),
 		d.Get("ignored_guest_ips").([]interface{}),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Wait for a routable address if we have been set to wait for one
 	err = virtualmachine.WaitForGuestNet(
+		ctx,
 		client,
 		vm,
 		d.Get("wait_for_guest_net_routable").(bool),
@@ -438,19 +562,29 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 		d.Get("ignored_guest_ips").([]interface{}),
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+
+	// Wait for the guest to signal that it is ready, if we have been set to
+	// wait for it
+	if err = resourceVSphereVirtualMachineWaitForGuest(ctx, d, client, vm); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// All done!
 	log.Printf("[DEBUG] %s: Create complete", resourceVSphereVirtualMachineIDString(d))
-	return resourceVSphereVirtualMachineRead(d, meta)
+	return resourceVSphereVirtualMachineRead(ctx, d, meta)
 }
 
-func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Reading state of virtual machine", resourceVSphereVirtualMachineIDString(d))
+	meta, err := resourceVSphereVirtualMachineClient(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	client := meta.(*Client).vimClient
 	id := d.Id()
-	vm, err := virtualmachine.FromUUID(client, id)
+	vm, err := virtualmachine.FromUUID(ctx, client, id)
 	if err != nil {
 		var notFoundError *virtualmachine.UUIDNotFoundError
 		if errors.As(err, &notFoundError) {
@@ -458,12 +592,12 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("error searching for with UUID %q: %s", id, err)
+		return diag.Errorf("error searching for with UUID %q: %s", id, err)
 	}
 
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return fmt.Errorf("error fetching VM properties: %s", err)
+		return diag.Errorf("error fetching VM properties: %s", err)
 	}
 
 	// Set the managed object id.
@@ -493,10 +627,10 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 		vmContainer = vprops.ResourcePool.Value
 	}
 
-	if !vappcontainer.IsVApp(client, vmContainer) {
+	if !vappcontainer.IsVApp(ctx, client, vmContainer) {
 		f, err := folder.RootPathParticleVM.SplitRelativeFolder(vm.InventoryPath)
 		if err != nil {
-			return fmt.Errorf("error parsing virtual machine path %q: %s", vm.InventoryPath, err)
+			return diag.Errorf("error parsing virtual machine path %q: %s", vm.InventoryPath, err)
 		}
 		_ = d.Set("folder", folder.NormalizePath(f))
 	}
@@ -508,7 +642,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	// Set the VMX path and default datastore
 	dp := &object.DatastorePath{}
 	if ok := dp.FromString(vprops.Config.Files.VmPathName); !ok {
-		return fmt.Errorf("could not parse VMX file path: %s", vprops.Config.Files.VmPathName)
+		return diag.Errorf("could not parse VMX file path: %s", vprops.Config.Files.VmPathName)
 	}
 	// The easiest path for us to get an exact match on the datastore in use is
 	// to look for the datastore name in the list of used datastores. This is
@@ -517,38 +651,38 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	// the resource when it's not used by anything else.
 	var ds *object.Datastore
 	for _, dsRef := range vprops.Datastore {
-		dsx, err := datastore.FromID(client, dsRef.Value)
+		dsx, err := datastore.FromID(ctx, client, dsRef.Value)
 		if err != nil {
-			return fmt.Errorf("error locating VMX datastore: %s", err)
+			return diag.Errorf("error locating VMX datastore: %s", err)
 		}
-		dsxProps, err := datastore.Properties(dsx)
+		dsxProps, err := datastore.Properties(ctx, dsx)
 		if err != nil {
-			return fmt.Errorf("error fetching VMX datastore properties: %s", err)
+			return diag.Errorf("error fetching VMX datastore properties: %s", err)
 		}
 		if dsxProps.Summary.Name == dp.Datastore {
 			ds = dsx
 		}
 	}
 	if ds == nil {
-		return fmt.Errorf("VMX datastore %s not found", dp.Datastore)
+		return diag.Errorf("VMX datastore %s not found", dp.Datastore)
 	}
 	_ = d.Set("datastore_id", ds.Reference().Value)
 	_ = d.Set("vmx_path", dp.Path)
 
 	isImported := d.Get("imported").(bool)
 	if isImported {
-		dsProps, err := datastore.Properties(ds)
+		dsProps, err := datastore.Properties(ctx, ds)
 		if err != nil {
-			return fmt.Errorf("could not read properties for datastore: %s", ds.Name())
+			return diag.Errorf("could not read properties for datastore: %s", ds.Name())
 		}
 		if dsProps.Parent.Type == "StoragePod" {
-			cluster, err := storagepod.FromID(client, dsProps.Parent.Value)
+			cluster, err := storagepod.FromID(ctx, client, dsProps.Parent.Value)
 			if err != nil {
-				return fmt.Errorf("could not read managed object reference (datastore cluster): %s", err)
+				return diag.Errorf("could not read managed object reference (datastore cluster): %s", err)
 			}
 
 			if cluster == nil {
-				return fmt.Errorf("could not read managed object reference (datastore cluster): %s", dsProps.Parent.Value)
+				return diag.Errorf("could not read managed object reference (datastore cluster): %s", dsProps.Parent.Value)
 			}
 
 			_ = d.Set("datastore_cluster_id", cluster.Reference().Value)
@@ -557,15 +691,20 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	}
 	// Read general VM config info
 	if err := flattenVirtualMachineConfigInfo(d, vprops.Config, client); err != nil {
-		return fmt.Errorf("error reading virtual machine configuration: %s", err)
+		return diag.Errorf("error reading virtual machine configuration: %s", err)
+	}
+
+	// Read the encryption settings
+	if err := flattenVirtualMachineEncryption(d, vprops.Config); err != nil {
+		return diag.Errorf("error reading virtual machine encryption: %s", err)
 	}
 
 	// Check if running for ESXi or vCenter.
 	if spbm.IsSupported(client) {
 		// Read the VM Home storage policy if associated.
-		polID, err := spbm.PolicyIDByVirtualMachine(client, moid)
+		polID, err := spbm.PolicyIDByVirtualMachine(ctx, client, moid)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		_ = d.Set("storage_policy_id", polID)
 	}
@@ -584,7 +723,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	}
 	err = d.Set("pci_device_id", pciDevs)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Perform pending device read operations.
@@ -593,26 +732,47 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	_ = d.Set("scsi_type", virtualdevice.ReadSCSIBusType(devices, d.Get("scsi_controller_count").(int)))
 	_ = d.Set("scsi_bus_sharing", virtualdevice.ReadSCSIBusSharing(devices, d.Get("scsi_controller_count").(int)))
 	// Disks first
-	if err := virtualdevice.DiskRefreshOperation(d, client, devices); err != nil {
-		return err
+	if err := virtualdevice.DiskRefreshOperation(ctx, d, client, devices); err != nil {
+		return diag.FromErr(err)
 	}
 	// Network devices
-	if err := virtualdevice.NetworkInterfaceRefreshOperation(d, client, devices); err != nil {
-		return err
+	if err := virtualdevice.NetworkInterfaceRefreshOperation(ctx, d, client, devices); err != nil {
+		return diag.FromErr(err)
 	}
 	// CDROM
 	if err := virtualdevice.CdromRefreshOperation(d, client, devices); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Video Card
 	if err := virtualdevice.VideoCardRefreshOperation(d, client, devices); err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	// Serial and parallel ports
+	if err := virtualdevice.SerialPortRefreshOperation(d, client, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := virtualdevice.ParallelPortRefreshOperation(d, client, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	// USB controllers and devices
+	if err := virtualdevice.UsbControllerRefreshOperation(d, client, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := virtualdevice.UsbDeviceRefreshOperation(d, client, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	// Dynamic DirectPath I/O and vGPU devices
+	if err := virtualdevice.DynamicPciDeviceRefreshOperation(d, client, devices); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := virtualdevice.VgpuProfileRefreshOperation(d, client, devices); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, vm, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, vm, d); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -626,7 +786,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	// user.
 	if vprops.Guest != nil {
 		if err := buildAndSelectGuestIPs(d, *vprops.Guest); err != nil {
-			return fmt.Errorf("error reading virtual machine guest data: %s", err)
+			return diag.Errorf("error reading virtual machine guest data: %s", err)
 		}
 	}
 
@@ -652,68 +812,82 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	if isVTPMPresent {
 		firmware := vprops.Config.Firmware
 		if isVTPMPresent && firmware != string(types.GuestOsDescriptorFirmwareTypeEfi) {
-			return errors.New("vtpm requires firmware to be set to efi")
+			return diag.FromErr(errors.New("vtpm requires firmware to be set to efi"))
 		}
 	}
 
+	if err := flattenVirtualMachineTargetVCenter(d, meta.(*Client)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	log.Printf("[DEBUG] %s: Read complete", resourceVSphereVirtualMachineIDString(d))
 	return nil
 }
 
-func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Performing update", resourceVSphereVirtualMachineIDString(d))
+	// Migrate the virtual machine first if it moves to another vCenter Server
+	// instance, so that the rest of the update runs on the destination.
+	migrated, err := resourceVSphereVirtualMachineUpdateVCenter(ctx, d, meta)
+	if err != nil {
+		return diag.Errorf("error migrating virtual machine across vCenter Server instances: %s", err)
+	}
+	meta, err = resourceVSphereVirtualMachineClient(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	client := meta.(*Client).vimClient
 	timeout := meta.(*Client).timeout
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := d.Id()
-	vm, err := virtualmachine.FromUUID(client, id)
+	vm, err := virtualmachine.FromUUID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("cannot locate virtual machine with UUID %q: %s", id, err)
+		return diag.Errorf("cannot locate virtual machine with UUID %q: %s", id, err)
 	}
 
-	if d.HasChange("resource_pool_id") {
+	if d.HasChange("resource_pool_id") && !migrated {
 		var rp *object.ResourcePool
-		rp, err = resourcepool.FromID(client, d.Get("resource_pool_id").(string))
+		rp, err = resourcepool.FromID(ctx, client, d.Get("resource_pool_id").(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		// Before we move the VM to the new RP we need to make sure the new one is on the same
 		// cluster or host as the old one, otherwise vsphere will throw an error.
-		dstRPProps, err := resourcepool.Properties(rp)
+		dstRPProps, err := resourcepool.Properties(ctx, rp)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		vmProps, err := virtualmachine.Properties(vm)
+		vmProps, err := virtualmachine.Properties(ctx, vm)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		srcRPID := vmProps.ResourcePool.Value
-		srcResourcePool, err := resourcepool.FromID(client, srcRPID)
+		srcResourcePool, err := resourcepool.FromID(ctx, client, srcRPID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		srcRPProps, err := resourcepool.Properties(srcResourcePool)
+		srcRPProps, err := resourcepool.Properties(ctx, srcResourcePool)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		// If the source and destination RPs have different owners (i.e hosts or clusters)
 		// then it will be handled as a vMotion task
 		if dstRPProps.Owner == srcRPProps.Owner {
-			err = resourcepool.MoveIntoResourcePool(rp, vm.Reference())
+			err = resourcepool.MoveIntoResourcePool(ctx, rp, vm.Reference())
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		} else {
 			// If we're migrating away from the current host we're setting the host system ID
@@ -724,31 +898,31 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 		// will change. This can affect steps later in the update process such as
 		// moving folders. To make sure the VM has the correct InventoryPath,
 		// refresh the VM after moving into a new resource pool.
-		vm, err = virtualmachine.FromMOID(client, vm.Reference().Value)
+		vm, err = virtualmachine.FromMOID(ctx, client, vm.Reference().Value)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	// Update folder if necessary
-	if d.HasChange("folder") && !vappcontainer.IsVApp(client, d.Get("resource_pool_id").(string)) {
+	if d.HasChange("folder") && !vappcontainer.IsVApp(ctx, client, d.Get("resource_pool_id").(string)) {
 		vmFolder := d.Get("folder").(string)
-		if err := virtualmachine.MoveToFolder(client, vm, vmFolder); err != nil {
-			return fmt.Errorf("could not move virtual machine to folder %q: %s", vmFolder, err)
+		if err := virtualmachine.MoveToFolder(ctx, client, vm, vmFolder); err != nil {
+			return diag.Errorf("could not move virtual machine to folder %q: %s", vmFolder, err)
 		}
 	}
 
 	// Apply any pending tags
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, vm); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, vm); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Update custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(vm); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, vm); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -756,19 +930,19 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 	// operation finishes successfully, need to be done in partial mode.
 	d.Partial(true)
 
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return fmt.Errorf("error fetching VM properties: %s", err)
+		return diag.Errorf("error fetching VM properties: %s", err)
 	}
 
-	spec, configChanged, err := expandVirtualMachineConfigSpecChanged(d, client, vprops.Config)
+	spec, configChanged, err := expandVirtualMachineConfigSpecChanged(ctx, d, client, vprops.Config)
 	if err != nil {
-		return fmt.Errorf("error in virtual machine configuration: %s", err)
+		return diag.Errorf("error in virtual machine configuration: %s", err)
 	}
 
 	devices := object.VirtualDeviceList(vprops.Config.Hardware.Device)
-	if spec.DeviceChange, err = applyVirtualDevices(d, client, devices); err != nil {
-		return err
+	if spec.DeviceChange, err = applyVirtualDevices(ctx, d, client, devices); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Only carry out the reconfigure if we actually have a change to process.
@@ -784,14 +958,22 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 		_ = d.Set("reboot_required", true)
 	}
 
-	if configChanged || evcChanged || len(spec.DeviceChange) > 0 {
+	// Encryption changes are applied after the reconfigure below, but the
+	// virtual machine needs to be shut down before it if the change requires
+	// it, so that it is not power cycled twice.
+	encryptionChanged := d.HasChange("encryption")
+	if (encryptionChanged || len(spec.DeviceChange) > 0) && virtualMachineEncryptionRequiresPowerOff(d, vprops) {
+		_ = d.Set("reboot_required", true)
+	}
+
+	if configChanged || evcChanged || encryptionChanged || len(spec.DeviceChange) > 0 {
 		// Check to see if we need to shutdown the VM for this process.
 		if d.Get("reboot_required").(bool) && vprops.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
 			// Attempt a graceful shutdown of this process. We wrap this in a VM helper.
 			timeout := d.Get("shutdown_wait_timeout").(int)
 			force := d.Get("force_power_off").(bool)
-			if err := virtualmachine.GracefulPowerOff(client, vm, timeout, force); err != nil {
-				return fmt.Errorf("error shutting down virtual machine: %s", err)
+			if err := virtualmachine.GracefulPowerOff(ctx, client, vm, timeout, force); err != nil {
+				return diag.Errorf("error shutting down virtual machine: %s", err)
 			}
 		}
 
@@ -812,7 +994,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 					// We're done
 					break
 				default:
-					vprops, err := virtualmachine.Properties(vm)
+					vprops, err := virtualmachine.Properties(ctx, vm)
 					if err != nil {
 						log.Printf("[DEBUG] Error while retrieving VM properties. Error: %s", err)
 						continue
@@ -826,7 +1008,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 						}
 						qMsg := q.Message[0].Id
 						if response, ok := questions[qMsg]; ok {
-							if err = vm.Answer(context.TODO(), q.Id, response); err != nil {
+							if err = vm.Answer(ctx, q.Id, response); err != nil {
 								log.Printf("[DEBUG] Failed to answer question. Error: %s", err)
 								break
 							}
@@ -840,55 +1022,65 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 
 		// Perform updates.
 		if _, ok := d.GetOk("datastore_cluster_id"); ok {
-			err = resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(d, meta, vm, spec)
+			err = resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(ctx, d, meta, vm, spec)
 		} else {
-			err = virtualmachine.Reconfigure(vm, spec, timeout)
+			err = virtualmachine.Reconfigure(ctx, vm, spec, timeout)
 		}
 		if err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+
+		// Apply encryption changes after the reconfigure, so that any new disks
+		// are encrypted as well.
+		if encryptionChanged || len(spec.DeviceChange) > 0 {
+			if err := resourceVSphereVirtualMachineApplyEncryption(ctx, d, meta, vm); err != nil {
+				return diag.FromErr(err)
+			}
 		}
 
 		if evcChanged {
-			if err = applyEvcMode(meta.(*Client).vimClient, vm, evcMode); err != nil {
+			if err = applyEvcMode(ctx, meta.(*Client).vimClient, vm, evcMode); err != nil {
 				return nil
 			}
 		}
 
 		// Upgrade the VM's hardware version if needed.
-		err = virtualmachine.SetHardwareVersion(vm, d.Get("hardware_version").(int))
+		err = virtualmachine.SetHardwareVersion(ctx, vm, d.Get("hardware_version").(int))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		// Regardless of the result we no longer need to watch for pending questions.
 		gChan <- true
 
 		// Re-fetch properties
-		vprops, err = virtualmachine.Properties(vm)
+		vprops, err = virtualmachine.Properties(ctx, vm)
 		if err != nil {
-			return fmt.Errorf("error re-fetching VM properties after update: %s", err)
+			return diag.Errorf("error re-fetching VM properties after update: %s", err)
 		}
 		// Power back on the VM, and wait for network if necessary.
 		if vprops.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
 			pTimeoutStr := fmt.Sprintf("%ds", d.Get("poweron_timeout").(int))
 			pTimeout, err := time.ParseDuration(pTimeoutStr)
 			if err != nil {
-				return fmt.Errorf("failed to parse poweron_timeout as a valid duration: %s", err)
+				return diag.Errorf("failed to parse poweron_timeout as a valid duration: %s", err)
 			}
 			// Start the virtual machine
-			if err := virtualmachine.PowerOn(vm, pTimeout); err != nil {
-				return fmt.Errorf("error powering on virtual machine: %s", err)
+			if err := virtualmachine.PowerOn(ctx, vm, pTimeout); err != nil {
+				return diag.Errorf("error powering on virtual machine: %s", err)
 			}
 			err = virtualmachine.WaitForGuestIP(
+				ctx,
 				client,
 				vm,
 				d.Get("wait_for_guest_ip_timeout").(int),
 				d.Get("ignored_guest_ips").([]interface{}),
 			)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			err = virtualmachine.WaitForGuestNet(
+				ctx,
 				client,
 				vm,
 				d.Get("wait_for_guest_net_routable").(bool),
@@ -896,7 +1088,10 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 				d.Get("ignored_guest_ips").([]interface{}),
 			)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
+			}
+			if err = resourceVSphereVirtualMachineWaitForGuest(ctx, d, client, vm); err != nil {
+				return diag.FromErr(err)
 			}
 		}
 	}
@@ -907,14 +1102,17 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 
 	// Now that any pending changes have been done (namely, any disks that don't
 	// need to be migrated have been deleted), proceed with vMotion if we have
-	// one pending.
-	if err := resourceVSphereVirtualMachineUpdateLocation(d, meta); err != nil {
-		return fmt.Errorf("error running VM migration: %s", err)
+	// one pending. The location was already set when migrating across vCenter
+	// Server instances.
+	if !migrated {
+		if err := resourceVSphereVirtualMachineUpdateLocation(ctx, d, meta); err != nil {
+			return diag.Errorf("error running VM migration: %s", err)
+		}
 	}
 
 	// All done with updates.
 	log.Printf("[DEBUG] %s: Update complete", resourceVSphereVirtualMachineIDString(d))
-	return resourceVSphereVirtualMachineRead(d, meta)
+	return resourceVSphereVirtualMachineRead(ctx, d, meta)
 }
 
 // resourceVSphereVirtualMachineUpdateReconfigureWithSDRS runs the reconfigure
@@ -922,6 +1120,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 // designed to be run when a storage cluster is specified, versus simply
 // specifying datastores.
 func resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	vm *object.VirtualMachine,
@@ -932,7 +1131,7 @@ func resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(
 	timeout := meta.(*Client).timeout
 	if !storagepod.HasDiskCreationOperations(spec.DeviceChange) {
 		log.Printf("[DEBUG] No disk operations for reconfiguration of VM %q, deferring to standard API", vm.InventoryPath)
-		return virtualmachine.Reconfigure(vm, spec, timeout)
+		return virtualmachine.Reconfigure(ctx, vm, spec, timeout)
 	}
 
 	client := meta.(*Client).vimClient
@@ -941,30 +1140,34 @@ func resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(
 	}
 
 	log.Printf("[DEBUG] %s: Reconfiguring virtual machine through Storage DRS API", resourceVSphereVirtualMachineIDString(d))
-	pod, err := storagepod.FromID(client, d.Get("datastore_cluster_id").(string))
+	pod, err := storagepod.FromID(ctx, client, d.Get("datastore_cluster_id").(string))
 	if err != nil {
 		return fmt.Errorf("error getting datastore cluster: %s", err)
 	}
 
-	err = storagepod.ReconfigureVM(client, vm, spec, pod)
+	err = storagepod.ReconfigureVM(ctx, client, vm, spec, pod)
 	if err != nil {
 		return fmt.Errorf("error reconfiguring VM on datastore cluster %q: %s", pod.Name(), err)
 	}
 	return nil
 }
 
-func resourceVSphereVirtualMachineDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Performing delete", resourceVSphereVirtualMachineIDString(d))
+	meta, err := resourceVSphereVirtualMachineClient(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	client := meta.(*Client).vimClient
 	timeout := meta.(*Client).timeout
 	id := d.Id()
-	vm, err := virtualmachine.FromUUID(client, id)
+	vm, err := virtualmachine.FromUUID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("cannot locate virtual machine with UUID %q: %s", id, err)
+		return diag.Errorf("cannot locate virtual machine with UUID %q: %s", id, err)
 	}
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
-		return fmt.Errorf("error fetching VM properties: %s", err)
+		return diag.Errorf("error fetching VM properties: %s", err)
 	}
 	// Shutdown the VM first. We do attempt a graceful shutdown for the purpose
 	// of catching any edge data issues with associated virtual disks that we may
@@ -972,34 +1175,41 @@ func resourceVSphereVirtualMachineDelete(d *schema.ResourceData, meta interface{
 	// flag.
 	if vprops.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
 		timeout := d.Get("shutdown_wait_timeout").(int)
-		if err := virtualmachine.GracefulPowerOff(client, vm, timeout, true); err != nil {
-			return fmt.Errorf("error shutting down virtual machine: %s", err)
+		if err := virtualmachine.GracefulPowerOff(ctx, client, vm, timeout, true); err != nil {
+			return diag.Errorf("error shutting down virtual machine: %s", err)
 		}
 	}
 	// Now attempt to detach any virtual disks that may need to be preserved.
 	devices := object.VirtualDeviceList(vprops.Config.Hardware.Device)
 	spec := types.VirtualMachineConfigSpec{}
 	if spec.DeviceChange, err = virtualdevice.DiskDestroyOperation(d, client, devices); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Only run the reconfigure operation if there's actually disks in the spec.
 	if len(spec.DeviceChange) > 0 {
-		if err := virtualmachine.Reconfigure(vm, spec, timeout); err != nil {
-			return fmt.Errorf("error detaching virtual disks: %s", err)
+		if err := virtualmachine.Reconfigure(ctx, vm, spec, timeout); err != nil {
+			return diag.Errorf("error detaching virtual disks: %s", err)
 		}
 	}
 
 	// The final operation here is to destroy the VM.
-	if err := virtualmachine.Destroy(vm); err != nil {
-		return fmt.Errorf("error destroying virtual machine: %s", err)
+	if err := virtualmachine.Destroy(ctx, vm); err != nil {
+		return diag.Errorf("error destroying virtual machine: %s", err)
 	}
 	d.SetId("")
 	log.Printf("[DEBUG] %s: Delete complete", resourceVSphereVirtualMachineIDString(d))
 	return nil
 }
 
-func resourceVSphereVirtualMachineCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+func resourceVSphereVirtualMachineCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
 	log.Printf("[DEBUG] %s: Performing diff customization and validation", resourceVSphereVirtualMachineIDString(d))
+	if err := virtualMachineTargetVCenterDiffOperation(d, meta); err != nil {
+		return err
+	}
+	meta, err := resourceVSphereVirtualMachineClient(ctx, d, meta)
+	if err != nil {
+		return err
+	}
 	client := meta.(*Client).vimClient
 
 	if len(d.Get("ovf_deploy").([]interface{})) == 0 && len(d.Get("network_interface").([]interface{})) == 0 {
@@ -1007,7 +1217,28 @@ func resourceVSphereVirtualMachineCustomizeDiff(_ context.Context, d *schema.Res
 	}
 
 	// Validate network device sub-resources
-	if err := virtualdevice.NetworkInterfaceDiffOperation(d, client); err != nil {
+	if err := virtualdevice.NetworkInterfaceDiffOperation(ctx, d, client); err != nil {
+		return err
+	}
+
+	// Validate USB controller and device sub-resources
+	if err := virtualdevice.UsbControllerDiffOperation(d); err != nil {
+		return err
+	}
+
+	// Validate that the hosts of the virtual machine support its PCI devices
+	if err := virtualdevice.DynamicPciDeviceDiffOperation(ctx, d, client); err != nil {
+		return err
+	}
+	if err := virtualdevice.VgpuProfileDiffOperation(ctx, d, client); err != nil {
+		return err
+	}
+
+	// Validate serial and parallel port sub-resources
+	if err := virtualdevice.SerialPortDiffOperation(d, client); err != nil {
+		return err
+	}
+	if err := virtualdevice.ParallelPortDiffOperation(d, client); err != nil {
 		return err
 	}
 
@@ -1017,19 +1248,29 @@ func resourceVSphereVirtualMachineCustomizeDiff(_ context.Context, d *schema.Res
 	}
 
 	// Normalize datastore cluster vs datastore
-	if err := datastoreClusterDiffOperation(d, client); err != nil {
+	if err := datastoreClusterDiffOperation(ctx, d, client); err != nil {
+		return err
+	}
+
+	// Validate cloud-init data
+	if err := cloudInitDiffOperation(d); err != nil {
+		return err
+	}
+
+	// Validate encryption settings
+	if err := encryptionDiffOperation(ctx, d, client); err != nil {
 		return err
 	}
 
 	// Validate and normalize disk sub-resources when not deploying from ovf
 	if len(d.Get("ovf_deploy").([]interface{})) == 0 {
-		if err := virtualdevice.DiskDiffOperation(d, client); err != nil {
+		if err := virtualdevice.DiskDiffOperation(ctx, d, client); err != nil {
 			return err
 		}
 	}
 	// When a VM is a member of a vApp container, it is no longer part of the VM
 	// tree, and therefore cannot have its VM folder set.
-	if _, ok := d.GetOk("folder"); ok && vappcontainer.IsVApp(client, d.Get("resource_pool_id").(string)) {
+	if _, ok := d.GetOk("folder"); ok && vappcontainer.IsVApp(ctx, client, d.Get("resource_pool_id").(string)) {
 		return fmt.Errorf("cannot set folder while VM is in a vApp container")
 	}
 
@@ -1069,15 +1310,21 @@ func resourceVSphereVirtualMachineCustomizeDiff(_ context.Context, d *schema.Res
 			}
 		case d.Id() == "":
 			templateUUID := d.Get("clone.0.template_uuid").(string)
-			isContentLibraryItem := contentlibrary.IsContentLibraryItem(meta.(*Client).restClient, templateUUID)
+			isContentLibraryItem := contentlibrary.IsContentLibraryItem(ctx, meta.(*Client).restClient, templateUUID)
 			if isContentLibraryItem {
 				if dsClusterID, ok := d.GetOk("datastore_cluster_id"); ok {
 					if err := d.SetNew("datastore_id", dsClusterID.(string)); err != nil {
 						return fmt.Errorf("error setting datastore_id: %s", err)
 					}
 				}
-			} else if err := vmworkflow.ValidateVirtualMachineClone(d, client); err != nil {
-				return err
+			} else {
+				src, err := resourceVSphereVirtualMachineCloneSourceClient(ctx, meta.(*Client), templateUUID)
+				if err != nil {
+					return err
+				}
+				if err := vmworkflow.ValidateVirtualMachineClone(ctx, d, client, src.vimClient); err != nil {
+					return err
+				}
 			}
 			fallthrough
 		default:
@@ -1105,7 +1352,7 @@ func resourceVSphereVirtualMachineCustomizeDiff(_ context.Context, d *schema.Res
 
 	// Validate hardware version changes.
 	cv, tv := d.GetChange("hardware_version")
-	err := virtualmachine.ValidateHardwareVersion(cv.(int), tv.(int))
+	err = virtualmachine.ValidateHardwareVersion(cv.(int), tv.(int))
 	if err != nil {
 		return err
 	}
@@ -1134,7 +1381,7 @@ func resourceVSphereVirtualMachineCustomizeDiffResourcePoolOperation(d *schema.R
 	return nil
 }
 
-func datastoreClusterDiffOperation(d *schema.ResourceDiff, client *govmomi.Client) error {
+func datastoreClusterDiffOperation(ctx context.Context, d *schema.ResourceDiff, client *govmomi.Client) error {
 	if !structure.ValuesAvailable("", []string{"datastore_cluster_id", "datastore_id"}, d) {
 		log.Printf("[DEBUG] DatastoreClusterDiffOperation: datastore_id or datastore_cluster_id value depends on a computed value from another resource. Skipping validation.")
 		return nil
@@ -1162,25 +1409,25 @@ func datastoreClusterDiffOperation(d *schema.ResourceDiff, client *govmomi.Clien
 		return d.SetNewComputed("datastore_id")
 	}
 
-	return datastoreClusterDiffOperationCheckMembership(d, client, podID.(string), dsID.(string))
+	return datastoreClusterDiffOperationCheckMembership(ctx, d, client, podID.(string), dsID.(string))
 }
 
-func datastoreClusterDiffOperationCheckMembership(d *schema.ResourceDiff, client *govmomi.Client, podID, dsID string) error {
+func datastoreClusterDiffOperationCheckMembership(ctx context.Context, d *schema.ResourceDiff, client *govmomi.Client, podID, dsID string) error {
 	log.Printf("[DEBUG] %s: Checking VM datastore cluster membership", resourceVSphereVirtualMachineIDString(d))
 
 	// Determine if the current datastore from state is a member of the current
 	// datastore cluster.
-	pod, err := storagepod.FromID(client, podID)
+	pod, err := storagepod.FromID(ctx, client, podID)
 	if err != nil {
 		return fmt.Errorf("error fetching datastore cluster ID %q: %s", podID, err)
 	}
 
-	ds, err := datastore.FromID(client, dsID)
+	ds, err := datastore.FromID(ctx, client, dsID)
 	if err != nil {
 		return fmt.Errorf("error fetching datastore ID %q: %s", dsID, err)
 	}
 
-	isMember, err := storagepod.IsMember(pod, ds)
+	isMember, err := storagepod.IsMember(ctx, pod, ds)
 	if err != nil {
 		return fmt.Errorf("error checking storage pod membership: %s", err)
 	}
@@ -1200,7 +1447,7 @@ func datastoreClusterDiffOperationCheckMembership(d *schema.ResourceDiff, client
 	return nil
 }
 
-func resourceVSphereVirtualMachineImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVirtualMachineImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).vimClient
 
 	name := d.Id()
@@ -1209,11 +1456,11 @@ func resourceVSphereVirtualMachineImport(d *schema.ResourceData, meta interface{
 	}
 
 	log.Printf("[DEBUG] Looking for VM by name/path %q", name)
-	vm, err := virtualmachine.FromPath(client, name, nil)
+	vm, err := virtualmachine.FromPath(ctx, client, name, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching virtual machine: %s", err)
 	}
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching virtual machine properties: %s", err)
 	}
@@ -1288,11 +1535,11 @@ func controllerCount(bus []bool) int {
 
 // resourceVSphereVirtualMachineCreateBare contains the "bare metal" VM
 // deploy path. The VM is returned.
-func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
+func resourceVSphereVirtualMachineCreateBare(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] %s: VM being created from scratch", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 	poolID := d.Get("resource_pool_id").(string)
-	pool, err := resourcepool.FromID(client, poolID)
+	pool, err := resourcepool.FromID(ctx, client, poolID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
 	}
@@ -1301,7 +1548,7 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 	// are saying here is that the VM folder that we are placing this VM in needs
 	// to be in the same hierarchy as the resource pool - so in other words, the
 	// same datacenter.
-	fo, err := folder.VirtualMachineFolderFromObject(client, pool, d.Get("folder").(string))
+	fo, err := folder.VirtualMachineFolderFromObject(ctx, client, pool, d.Get("folder").(string))
 	if err != nil {
 		return nil, err
 	}
@@ -1309,18 +1556,18 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 	if v, ok := d.GetOk("host_system_id"); ok {
 		hsID := v.(string)
 		var err error
-		if hs, err = hostsystem.FromID(client, hsID); err != nil {
+		if hs, err = hostsystem.FromID(ctx, client, hsID); err != nil {
 			return nil, fmt.Errorf("error locating host system at ID %q: %s", hsID, err)
 		}
 	}
 
 	// Validate that the host is part of the resource pool before proceeding
-	if err := resourcepool.ValidateHost(client, pool, hs); err != nil {
+	if err := resourcepool.ValidateHost(ctx, client, pool, hs); err != nil {
 		return nil, err
 	}
 
 	// Ready to start making the VM here. First expand our main config spec.
-	spec, err := expandVirtualMachineConfigSpec(d, client)
+	spec, err := expandVirtualMachineConfigSpec(ctx, d, client)
 	if err != nil {
 		return nil, fmt.Errorf("error in virtual machine configuration: %s", err)
 	}
@@ -1333,13 +1580,13 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 	if guestInterface, ok := d.GetOk("guest_id"); ok {
 		guestID = guestInterface.(string)
 	}
-	devices, err := resourcepool.DefaultDevices(client, pool, guestID)
+	devices, err := resourcepool.DefaultDevices(ctx, client, pool, guestID)
 	if err != nil {
 		return nil, fmt.Errorf("error loading default device list: %s", err)
 	}
 	log.Printf("[DEBUG] Default devices: %s", virtualdevice.DeviceListString(devices))
 
-	if spec.DeviceChange, err = applyVirtualDevices(d, client, devices); err != nil {
+	if spec.DeviceChange, err = applyVirtualDevices(ctx, d, client, devices); err != nil {
 		return nil, err
 	}
 
@@ -1347,36 +1594,41 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 	// cluster, use the SDRS API, if not, use the standard API.
 	var vm *object.VirtualMachine
 	if _, ok := d.GetOk("datastore_cluster_id"); ok {
-		vm, err = resourceVSphereVirtualMachineCreateBareWithSDRS(d, meta, fo, spec, pool, hs)
+		vm, err = resourceVSphereVirtualMachineCreateBareWithSDRS(ctx, d, meta, fo, spec, pool, hs)
 	} else {
-		vm, err = resourceVSphereVirtualMachineCreateBareStandard(d, meta, fo, spec, pool, hs)
+		vm, err = resourceVSphereVirtualMachineCreateBareStandard(ctx, d, meta, fo, spec, pool, hs)
 	}
 	if err != nil {
 		return nil, err
 	}
 
 	if evcMode, evcChanged := getEvcMode(d); evcChanged {
-		if err = applyEvcMode(meta.(*Client).vimClient, vm, evcMode); err != nil {
+		if err = applyEvcMode(ctx, meta.(*Client).vimClient, vm, evcMode); err != nil {
 			return nil, err
 		}
 	}
 
 	// VM is created. Set the ID now before proceeding, in case the rest of the
 	// process here fails.
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return nil, fmt.Errorf("cannot fetch properties of created virtual machine: %s", err)
 	}
 	log.Printf("[DEBUG] VM %q - UUID is %q", vm.InventoryPath, vprops.Config.Uuid)
 	d.SetId(vprops.Config.Uuid)
 
+	// Encrypt the VM before powering it on, if necessary.
+	if err := resourceVSphereVirtualMachineApplyEncryption(ctx, d, meta, vm); err != nil {
+		return nil, err
+	}
+
 	pTimeoutStr := fmt.Sprintf("%ds", d.Get("poweron_timeout").(int))
 	pTimeout, err := time.ParseDuration(pTimeoutStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse poweron_timeout as a valid duration: %s", err)
 	}
 	// Start the virtual machine
-	if err := virtualmachine.PowerOn(vm, pTimeout); err != nil {
+	if err := virtualmachine.PowerOn(ctx, vm, pTimeout); err != nil {
 		return nil, fmt.Errorf("error powering on virtual machine: %s", err)
 	}
 	return vm, nil
@@ -1387,6 +1639,7 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 // to be run when a storage cluster is specified, versus simply specifying
 // datastores.
 func resourceVSphereVirtualMachineCreateBareWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	fo *object.Folder,
@@ -1400,13 +1653,13 @@ func resourceVSphereVirtualMachineCreateBareWithSDRS(
 	}
 
 	log.Printf("[DEBUG] %s: Creating virtual machine through Storage DRS API", resourceVSphereVirtualMachineIDString(d))
-	pod, err := storagepod.FromID(client, d.Get("datastore_cluster_id").(string))
+	pod, err := storagepod.FromID(ctx, client, d.Get("datastore_cluster_id").(string))
 	if err != nil {
 		return nil, fmt.Errorf("error getting datastore cluster: %s", err)
 	}
 
 	timeout := meta.(*Client).timeout
-	vm, err := storagepod.CreateVM(client, fo, spec, pool, hs, pod, d.Get("datastore_path").(string), timeout)
+	vm, err := storagepod.CreateVM(ctx, client, fo, spec, pool, hs, pod, d.Get("datastore_path").(string), timeout)
 	if err != nil {
 		return nil, fmt.Errorf("error creating virtual machine on datastore cluster %q: %s", pod.Name(), err)
 	}
@@ -1417,6 +1670,7 @@ func resourceVSphereVirtualMachineCreateBareWithSDRS(
 // during resourceVSphereVirtualMachineCreateBare to create a virtual machine
 // when a datastore cluster is not supplied.
 func resourceVSphereVirtualMachineCreateBareStandard(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	fo *object.Folder,
@@ -1427,7 +1681,7 @@ func resourceVSphereVirtualMachineCreateBareStandard(
 	client := meta.(*Client).vimClient
 
 	// Set the datastore for the VM.
-	ds, err := datastore.FromID(client, d.Get("datastore_id").(string))
+	ds, err := datastore.FromID(ctx, client, d.Get("datastore_id").(string))
 	if err != nil {
 		return nil, fmt.Errorf("error locating datastore for VM: %s", err)
 	}
@@ -1436,7 +1690,7 @@ func resourceVSphereVirtualMachineCreateBareStandard(
 	}
 
 	timeout := meta.(*Client).timeout
-	vm, err := virtualmachine.Create(client, fo, spec, pool, hs, timeout)
+	vm, err := virtualmachine.Create(ctx, client, fo, spec, pool, hs, timeout)
 	if err != nil {
 		return nil, fmt.Errorf("error creating virtual machine: %s", err)
 	}
@@ -1444,23 +1698,24 @@ func resourceVSphereVirtualMachineCreateBareStandard(
 }
 
 // Deploy vm from ovf/ova template
-func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
+func resourceVsphereMachineDeployOvfAndOva(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
 	client := meta.(*Client).vimClient
 	timeout := meta.(*Client).timeout
 
 	ovfParams := NewOvfHelperParamsFromVMResource(d)
-	ovfHelper, err := ovfdeploy.NewOvfHelper(client, ovfParams)
+	ovfParams.TLS = meta.(*Client).TLSOptions()
+	ovfHelper, err := ovfdeploy.NewOvfHelper(ctx, client, ovfParams)
 	if err != nil {
 		return nil, fmt.Errorf("while extracting OVF parameters: %s", err)
 	}
 
-	ovfImportspec, err := ovfHelper.GetImportSpec(client)
+	ovfImportspec, err := ovfHelper.GetImportSpec(ctx, client)
 	if err != nil {
 		return nil, fmt.Errorf("while retrieving ovf import spec from the API: %s", err)
 	}
 
 	log.Print(" [DEBUG] start deploying from ovf/ova Template")
-	err = ovfHelper.DeployOvf(client, ovfImportspec)
+	err = ovfHelper.DeployOvf(ctx, client, ovfImportspec)
 	if err != nil {
 		return nil, fmt.Errorf("error while importing ovf/ova template, %s", err)
 	}
@@ -1469,7 +1724,7 @@ func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interfac
 	if dataCenterID == "" {
 		return nil, fmt.Errorf("data center ID is required for ovf deployment")
 	}
-	datacenterObj, err := datacenterFromID(client, dataCenterID)
+	datacenterObj, err := datacenterFromID(ctx, client, dataCenterID)
 	if err != nil {
 		return nil, fmt.Errorf("error while getting datacenter with id %s %s", dataCenterID, err)
 	}
@@ -1477,13 +1732,13 @@ func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interfac
 	if ovfHelper.Folder != nil && len(ovfHelper.Folder.InventoryPath) > 0 {
 		searchPath = path.Join(ovfHelper.Folder.InventoryPath, searchPath)
 	}
-	vm, err := virtualmachine.FromPath(client, searchPath, datacenterObj)
+	vm, err := virtualmachine.FromPath(ctx, client, searchPath, datacenterObj)
 	if err != nil {
 		return nil, fmt.Errorf("error while fetching the created vm, %s", err)
 	}
 
 	// set ID for the vm
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return nil, fmt.Errorf("cannot fetch properties of created virtual machine: %s", err)
 	}
@@ -1491,7 +1746,7 @@ func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interfac
 	log.Printf("[DEBUG] VM %q - UUID is %q", vm.InventoryPath, vprops.Config.Uuid)
 	d.SetId(vprops.Config.Uuid)
 	// update vapp properties
-	vappConfig, err := expandVAppConfig(d, client)
+	vappConfig, err := expandVAppConfig(ctx, d, client)
 	if err != nil {
 		return nil, fmt.Errorf("error while creating vapp properties config %s", err)
 	}
@@ -1499,32 +1754,32 @@ func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interfac
 		vmConfigSpec := types.VirtualMachineConfigSpec{
 			VAppConfig: vappConfig,
 		}
-		err = virtualmachine.Reconfigure(vm, vmConfigSpec, timeout)
+		err = virtualmachine.Reconfigure(ctx, vm, vmConfigSpec, timeout)
 		if err != nil {
 			return nil, fmt.Errorf("error while applying vapp config %s", err)
 		}
 	}
 
-	return vm, resourceVSphereVirtualMachinePostDeployChanges(d, meta, vm, true)
+	return vm, resourceVSphereVirtualMachinePostDeployChanges(ctx, d, meta, vm, true)
 }
 
-func createVCenterDeploy(d *schema.ResourceData, meta interface{}) (*virtualmachine.VCenterDeploy, error) {
+func createVCenterDeploy(ctx context.Context, d *schema.ResourceData, meta interface{}) (*virtualmachine.VCenterDeploy, error) {
 	restClient := meta.(*Client).restClient
 	vimClient := meta.(*Client).vimClient
 	vCenterManager := vcenter.NewManager(restClient)
 
-	item, err := contentlibrary.ItemFromID(restClient, d.Get("clone.0.template_uuid").(string))
+	item, err := contentlibrary.ItemFromID(ctx, restClient, d.Get("clone.0.template_uuid").(string))
 	if err != nil {
 		return nil, err
 	}
 
 	poolID := d.Get("resource_pool_id").(string)
-	poolObj, err := resourcepool.FromID(vimClient, poolID)
+	poolObj, err := resourcepool.FromID(ctx, vimClient, poolID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
 	}
 
-	folderObj, err := folder.VirtualMachineFolderFromObject(vimClient, poolObj, d.Get("folder").(string))
+	folderObj, err := folder.VirtualMachineFolderFromObject(ctx, vimClient, poolObj, d.Get("folder").(string))
 	if err != nil {
 		return nil, err
 	}
@@ -1547,7 +1802,7 @@ func createVCenterDeploy(d *schema.ResourceData, meta interface{}) (*virtualmach
 
 // resourceVSphereVirtualMachineCreateClone contains the clone VM deploy
 // path. The VM is returned.
-func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
+func resourceVSphereVirtualMachineCreateClone(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] %s: VM being created from clone", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 
@@ -1556,11 +1811,11 @@ func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta inter
 	// to be in the same hierarchy as the resource pool - so in other words, the
 	// same datacenter.
 	poolID := d.Get("resource_pool_id").(string)
-	pool, err := resourcepool.FromID(client, poolID)
+	pool, err := resourcepool.FromID(ctx, client, poolID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
 	}
-	fo, err := folder.VirtualMachineFolderFromObject(client, pool, d.Get("folder").(string))
+	fo, err := folder.VirtualMachineFolderFromObject(ctx, client, pool, d.Get("folder").(string))
 	if err != nil {
 		return nil, err
 	}
@@ -1569,17 +1824,17 @@ func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta inter
 	name := d.Get("name").(string)
 	timeout := d.Get("clone.0.timeout").(int)
 	var vm *object.VirtualMachine
-	switch contentlibrary.IsContentLibraryItem(meta.(*Client).restClient, d.Get("clone.0.template_uuid").(string)) {
+	switch contentlibrary.IsContentLibraryItem(ctx, meta.(*Client).restClient, d.Get("clone.0.template_uuid").(string)) {
 	case true:
-		deploySpec, err := createVCenterDeploy(d, meta)
+		deploySpec, err := createVCenterDeploy(ctx, d, meta)
 		if err != nil {
 			return nil, err
 		}
-		vmoid, err := virtualmachine.Deploy(deploySpec)
+		vmoid, err := virtualmachine.Deploy(ctx, deploySpec)
 		if err != nil {
 			return nil, err
 		}
-		vm, err = virtualmachine.FromMOID(client, vmoid.Value)
+		vm, err = virtualmachine.FromMOID(ctx, client, vmoid.Value)
 		if err != nil {
 			return nil, err
 		}
@@ -1587,21 +1842,144 @@ func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta inter
 		// the defaults from the template will be used.
 		_ = d.Set("guest_id", "")
 	case false:
+		src, err := resourceVSphereVirtualMachineCloneSourceClient(ctx, meta.(*Client), d.Get("clone.0.template_uuid").(string))
+		if err != nil {
+			return nil, err
+		}
+		crossVCenter := src != meta.(*Client)
+		if d.Get("clone.0.instant_clone").(bool) {
+			if crossVCenter {
+				return nil, errors.New("instant clone is not supported across vCenter Server instances")
+			}
+			return resourceVSphereVirtualMachineCreateInstantClone(ctx, d, meta, fo, name, timeout)
+		}
 		// Expand the clone spec. We get the source VM here too.
-		cloneSpec, srcVM, err := vmworkflow.ExpandVirtualMachineCloneSpec(d, client)
+		cloneSpec, srcVM, err := vmworkflow.ExpandVirtualMachineCloneSpec(ctx, d, client, src.vimClient)
 		if err != nil {
 			return nil, err
 		}
+		if crossVCenter {
+			if _, ok := d.GetOk("datastore_cluster_id"); ok {
+				return nil, errors.New("datastore_cluster_id cannot be used when cloning across vCenter Server instances")
+			}
+			// The clone task runs on the source vCenter Server instance, and
+			// places the virtual machine on the target instance through its
+			// service locator. The folder on the target must then be in the
+			// relocate spec, and the folder of the task is one on the source.
+			cloneSpec.Location.Service, err = virtualMachineServiceLocator(meta.(*Client), d.Get("target_vcenter"))
+			if err != nil {
+				return nil, err
+			}
+			cloneSpec.Location.Folder = types.NewReference(fo.Reference())
+			fo, err = folder.VirtualMachineFolderFromObject(ctx, src.vimClient, srcVM, "")
+			if err != nil {
+				return nil, err
+			}
+		}
 		if _, ok := d.GetOk("datastore_cluster_id"); ok {
-			vm, err = resourceVSphereVirtualMachineCreateCloneWithSDRS(d, meta, srcVM, fo, name, cloneSpec, timeout)
+			vm, err = resourceVSphereVirtualMachineCreateCloneWithSDRS(ctx, d, meta, srcVM, fo, name, cloneSpec, timeout)
 		} else {
-			vm, err = virtualmachine.Clone(client, srcVM, fo, name, cloneSpec, timeout)
+			vm, err = virtualmachine.Clone(ctx, client, srcVM, fo, name, cloneSpec, timeout)
 		}
 		if err != nil {
 			return nil, fmt.Errorf("error cloning virtual machine: %s", err)
 		}
 	}
-	return vm, resourceVSphereVirtualMachinePostDeployChanges(d, meta, vm, false)
+	return vm, resourceVSphereVirtualMachinePostDeployChanges(ctx, d, meta, vm, false)
+}
+
+// resourceVSphereVirtualMachineCreateInstantClone contains the instant clone
+// path. The VM is returned running.
+func resourceVSphereVirtualMachineCreateInstantClone(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+	fo *object.Folder,
+	name string,
+	timeout int,
+) (*object.VirtualMachine, error) {
+	client := meta.(*Client).vimClient
+	spec, srcVM, err := vmworkflow.ExpandVirtualMachineInstantCloneSpec(ctx, d, client)
+	if err != nil {
+		return nil, err
+	}
+	vm, err := virtualmachine.InstantClone(ctx, client, srcVM, fo, name, spec, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("error instant cloning virtual machine: %s", err)
+	}
+	return vm, resourceVSphereVirtualMachinePostInstantCloneChanges(ctx, d, meta, vm)
+}
+
+// resourceVSphereVirtualMachinePostInstantCloneChanges does the post-clone
+// configuration of an instant clone. An instant clone shares its running state
+// with the source, so the virtual machine is reconfigured while it is running,
+// with only the settings that differ from the source, and is not powered on
+// afterwards. As with full clones, the new virtual machine is rolled back if
+// any of this fails.
+func resourceVSphereVirtualMachinePostInstantCloneChanges(ctx context.Context, d *schema.ResourceData, meta interface{}, vm *object.VirtualMachine) error {
+	client := meta.(*Client).vimClient
+	vprops, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("cannot fetch properties of created virtual machine: %s", err),
+		)
+	}
+	log.Printf("[DEBUG] VM %q - UUID is %q", vm.InventoryPath, vprops.Config.Uuid)
+	d.SetId(vprops.Config.Uuid)
+
+	cfgSpec, err := expandVirtualMachineConfigSpecDelta(ctx, d, client, vprops.Config)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error in virtual machine configuration: %s", err),
+		)
+	}
+	devices := object.VirtualDeviceList(vprops.Config.Hardware.Device)
+	devices, delta, err := virtualdevice.DiskPostCloneOperation(ctx, d, client, devices, false)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing disk changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	devices, delta, err = virtualdevice.NetworkInterfacePostCloneOperation(ctx, d, client, devices)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing network device changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	log.Printf("[DEBUG] %s: Final device list: %s", resourceVSphereVirtualMachineIDString(d), virtualdevice.DeviceListString(devices))
+	log.Printf("[DEBUG] %s: Final device change cfgSpec: %s", resourceVSphereVirtualMachineIDString(d), virtualdevice.DeviceChangeString(cfgSpec.DeviceChange))
+	if reflect.DeepEqual(cfgSpec, types.VirtualMachineConfigSpec{}) {
+		return nil
+	}
+
+	if err := virtualmachine.Reconfigure(ctx, vm, cfgSpec, meta.(*Client).timeout); err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error reconfiguring virtual machine: %s", err),
+		)
+	}
+	return nil
 }
 
 // resourceVSphereVirtualMachinePostDeployChanges will do post-clone
@@ -1611,12 +1989,13 @@ func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta inter
 //
 // It's generally safe to not rollback after the initial re-configuration is
 // fully complete and we move on to sending the customization spec.
-func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta interface{}, vm *object.VirtualMachine, postOvf bool) error {
+func resourceVSphereVirtualMachinePostDeployChanges(ctx context.Context, d *schema.ResourceData, meta interface{}, vm *object.VirtualMachine, postOvf bool) error {
 	client := meta.(*Client).vimClient
 	poolID := d.Get("resource_pool_id").(string)
-	pool, err := resourcepool.FromID(client, poolID)
+	pool, err := resourcepool.FromID(ctx, client, poolID)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1624,9 +2003,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 		)
 	}
 
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1646,9 +2026,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	// Reconfigure VM after normalizing the bus to avoid sending duplicate edit operations for
 	// devices attached to the controllers.
 	// Continue with a fresh cfgSpec since all the changes have been applied
-	storageControllercfgSpec, err := expandVirtualMachineConfigSpec(d, client)
+	storageControllercfgSpec, err := expandVirtualMachineConfigSpec(ctx, d, client)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1658,6 +2039,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.NormalizeBus(devices, d) //nolint
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1667,9 +2049,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	storageControllercfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(storageControllercfgSpec.DeviceChange, delta...)
 
 	timeout := meta.(*Client).timeout
-	err = virtualmachine.Reconfigure(vm, storageControllercfgSpec, timeout)
+	err = virtualmachine.Reconfigure(ctx, vm, storageControllercfgSpec, timeout)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1680,18 +2063,20 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	// The VM has been reconfigured, we need to refresh some objects holding
 	// The current state of the vm
 
-	vm, err = virtualmachine.FromUUID(client, vprops.Config.Uuid)
+	vm, err = virtualmachine.FromUUID(ctx, client, vprops.Config.Uuid)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
 			fmt.Errorf("cannot fetch properties of created virtual machine: %s", err),
 		)
 	}
-	vprops, err = virtualmachine.Properties(vm)
+	vprops, err = virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1705,9 +2090,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	// configuration of the newly cloned VM. This is basically a subset of update
 	// with the stipulation that there is currently no state to help move this
 	// along.
-	cfgSpec, err := expandVirtualMachineConfigSpec(d, client)
+	cfgSpec, err := expandVirtualMachineConfigSpec(ctx, d, client)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1717,9 +2103,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices = vprops.Config.Hardware.Device
 
 	// Disks
-	devices, delta, err = virtualdevice.DiskPostCloneOperation(d, client, devices, postOvf)
+	devices, delta, err = virtualdevice.DiskPostCloneOperation(ctx, d, client, devices, postOvf)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1732,9 +2119,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	// Apply SDRS if new disks are being added
 	if d.Get("datastore_cluster_id").(string) != "" && len(newDisks) > 0 {
 		log.Printf("[DEBUG] %s: Reconfiguring virtual machine through Storage DRS API", resourceVSphereVirtualMachineIDString(d))
-		pod, err := storagepod.FromID(client, d.Get("datastore_cluster_id").(string))
+		pod, err := storagepod.FromID(ctx, client, d.Get("datastore_cluster_id").(string))
 		if err != nil {
 			return resourceVSphereVirtualMachineRollbackCreate(
+				ctx,
 				d,
 				meta,
 				vm,
@@ -1742,9 +2130,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 			)
 		}
 
-		err = storagepod.ReconfigureVM(client, vm, cfgSpec, pod)
+		err = storagepod.ReconfigureVM(ctx, client, vm, cfgSpec, pod)
 		if err != nil {
 			return resourceVSphereVirtualMachineRollbackCreate(
+				ctx,
 				d,
 				meta,
 				vm,
@@ -1775,9 +2164,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	}
 
 	// Network devices
-	devices, delta, err = virtualdevice.NetworkInterfacePostCloneOperation(d, client, devices)
+	devices, delta, err = virtualdevice.NetworkInterfacePostCloneOperation(ctx, d, client, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1786,9 +2176,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	}
 	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
 	// CDROM
-	devices, delta, err = virtualdevice.CdromPostCloneOperation(d, client, devices)
+	devices, delta, err = virtualdevice.CdromPostCloneOperation(ctx, d, client, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1800,6 +2191,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.VideoCardPostDeployOperation(d, client, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1807,10 +2199,80 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 		)
 	}
 	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	// Serial and parallel ports
+	devices, delta, err = virtualdevice.SerialPortPostCloneOperation(ctx, d, client, devices)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing serial port device changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	devices, delta, err = virtualdevice.ParallelPortPostCloneOperation(ctx, d, client, devices)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing parallel port device changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	// USB controllers and devices
+	devices, delta, err = virtualdevice.UsbControllerPostCloneOperation(d, client, devices)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing USB controller changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	devices, delta, err = virtualdevice.UsbDevicePostCloneOperation(d, client, devices)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing USB device changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	// Dynamic DirectPath I/O and vGPU devices
+	devices, delta, err = virtualdevice.DynamicPciDevicePostCloneOperation(ctx, d, client, devices)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing dynamic PCI device changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
+	devices, delta, err = virtualdevice.VgpuProfilePostCloneOperation(ctx, d, client, devices)
+	if err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
+			d,
+			meta,
+			vm,
+			fmt.Errorf("error processing vGPU device changes post-clone: %s", err),
+		)
+	}
+	cfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(cfgSpec.DeviceChange, delta...)
 	// PCI passthrough devices
-	devices, delta, err = virtualdevice.PciPassthroughPostCloneOperation(d, client, devices)
+	devices, delta, err = virtualdevice.PciPassthroughPostCloneOperation(ctx, d, client, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1823,6 +2285,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.VtpmApplyOperation(d, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1834,9 +2297,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	log.Printf("[DEBUG] %s: Final device change cfgSpec: %s", resourceVSphereVirtualMachineIDString(d), virtualdevice.DeviceChangeString(cfgSpec.DeviceChange))
 
 	// Perform updates
-	err = virtualmachine.Reconfigure(vm, cfgSpec, timeout)
+	err = virtualmachine.Reconfigure(ctx, vm, cfgSpec, timeout)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1847,12 +2311,12 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	var evcMode string
 	var evcChanged bool
 	if evcMode, evcChanged = getEvcMode(d); evcChanged {
-		if err = applyEvcMode(meta.(*Client).vimClient, vm, evcMode); err != nil {
+		if err = applyEvcMode(ctx, meta.(*Client).vimClient, vm, evcMode); err != nil {
 			return err
 		}
 	}
 
-	vmprops, err := virtualmachine.Properties(vm)
+	vmprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return err
 	}
@@ -1861,11 +2325,17 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	_ = d.Set("guest_id", vmprops.Config.GuestId)
 
 	// Upgrade the VM's hardware version if needed.
-	err = virtualmachine.SetHardwareVersion(vm, d.Get("hardware_version").(int))
+	err = virtualmachine.SetHardwareVersion(ctx, vm, d.Get("hardware_version").(int))
 	if err != nil {
 		return err
 	}
 
+	// Encrypt the VM if necessary. This needs to happen before customization,
+	// as the VM is powered on for customization.
+	if err := resourceVSphereVirtualMachineApplyEncryption(ctx, d, meta, vm); err != nil {
+		return resourceVSphereVirtualMachineRollbackCreate(ctx, d, meta, vm, err)
+	}
+
 	var cw *virtualMachineCustomizationWaiter
 	// Send customization spec if any has been defined.
 	hasCustomizeInCloneConfig := len(d.Get("clone.0.customize").([]interface{})) > 0
@@ -1877,7 +2347,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 			vmHardwareVersion = vmSpecHardwareVersion
 		}
 
-		family, err := resourcepool.OSFamily(client, pool, d.Get("guest_id").(string), vmHardwareVersion)
+		family, err := resourcepool.OSFamily(ctx, client, pool, d.Get("guest_id").(string), vmHardwareVersion)
 		if err != nil {
 			return fmt.Errorf("cannot find OS family for guest ID %q: %s", d.Get("guest_id").(string), err)
 		}
@@ -1886,11 +2356,14 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 		if hasCustomizeInCloneConfig {
 			timeout = d.Get("clone.0.customize.0.timeout").(int)
 			version := viapi.ParseVersionFromClient(client)
+			if err := guestoscustomizations.ValidateCloudConfigVersion(d, true, version); err != nil {
+				return err
+			}
 			customizationSpec = guestoscustomizations.ExpandCustomizationSpec(d, family, true, version)
 		} else {
 			timeout = d.Get("clone.0.customization_spec.0.timeout").(int)
 			goscName := d.Get("clone.0.customization_spec.0.id").(string)
-			specItem, err := guestoscustomizations.FromName(client, goscName)
+			specItem, err := guestoscustomizations.FromName(ctx, client, goscName)
 			if err != nil {
 				return err
 			}
@@ -1902,10 +2375,10 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 			customizationSpec = specItem.Spec
 		}
 
-		cw = newVirtualMachineCustomizationWaiter(client, vm, timeout)
-		if err := virtualmachine.Customize(vm, customizationSpec); err != nil {
+		cw = newVirtualMachineCustomizationWaiter(ctx, client, vm, timeout)
+		if err := virtualmachine.Customize(ctx, vm, customizationSpec); err != nil {
 			// Roll back the VMs as per the error handling in reconfigure.
-			if derr := resourceVSphereVirtualMachineDelete(d, meta); derr != nil {
+			if derr := provider.DiagnosticsError(resourceVSphereVirtualMachineDelete(context.WithoutCancel(ctx), d, meta)); derr != nil {
 				return fmt.Errorf(formatVirtualMachinePostCloneRollbackError, vm.InventoryPath, err, derr)
 			}
 			d.SetId("")
@@ -1914,7 +2387,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	}
 	// Finally time to power on the virtual machine!
 	pTimeout := time.Duration(d.Get("poweron_timeout").(int)) * time.Second
-	if err := virtualmachine.PowerOn(vm, pTimeout); err != nil {
+	if err := virtualmachine.PowerOn(ctx, vm, pTimeout); err != nil {
 		return fmt.Errorf("error powering on virtual machine: %s", err)
 	}
 	// If we customized, wait on customization.
@@ -1933,6 +2406,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 // to be run when a storage cluster is specified, versus simply specifying
 // datastores.
 func resourceVSphereVirtualMachineCreateCloneWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	srcVM *object.VirtualMachine,
@@ -1947,12 +2421,12 @@ func resourceVSphereVirtualMachineCreateCloneWithSDRS(
 	}
 
 	log.Printf("[DEBUG] %s: Cloning virtual machine through Storage DRS API", resourceVSphereVirtualMachineIDString(d))
-	pod, err := storagepod.FromID(client, d.Get("datastore_cluster_id").(string))
+	pod, err := storagepod.FromID(ctx, client, d.Get("datastore_cluster_id").(string))
 	if err != nil {
 		return nil, fmt.Errorf("error getting datastore cluster: %s", err)
 	}
 
-	vm, err := storagepod.CloneVM(client, srcVM, fo, name, spec, timeout, pod)
+	vm, err := storagepod.CloneVM(ctx, client, srcVM, fo, name, spec, timeout, pod)
 	if err != nil {
 		return nil, fmt.Errorf("error cloning on datastore cluster %q: %s", pod.Name(), err)
 	}
@@ -1969,6 +2443,7 @@ func resourceVSphereVirtualMachineCreateCloneWithSDRS(
 // If the rollback fails, an error is displayed prompting the user to manually
 // delete the virtual machine before trying again.
 func resourceVSphereVirtualMachineRollbackCreate(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	vm *object.VirtualMachine,
@@ -1979,7 +2454,10 @@ func resourceVSphereVirtualMachineRollbackCreate(
 	// keep_on_remove were attached, but just in case, we run this through delete
 	// to make sure to safely remove any disk that may have been attached as part
 	// of this process if it was flagged as such.
-	if err := resourceVSphereVirtualMachineDelete(d, meta); err != nil {
+	//
+	// The rollback still runs if ctx was cancelled, as that is usually the
+	// reason for the error.
+	if err := provider.DiagnosticsError(resourceVSphereVirtualMachineDelete(context.WithoutCancel(ctx), d, meta)); err != nil {
 		return fmt.Errorf(formatVirtualMachinePostCloneRollbackError, vm.InventoryPath, origErr, err)
 	}
 	return fmt.Errorf("error reconfiguring virtual machine: %s", origErr)
@@ -1991,25 +2469,25 @@ func resourceVSphereVirtualMachineRollbackCreate(
 //
 // This function is responsible for building the top-level relocate spec. For
 // disks, we call out to relocate functionality in the disk sub-resource.
-func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineUpdateLocation(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] %s: Checking for pending migration operations", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 
 	// A little bit of duplication of VM object data is done here to keep the
 	// method signature lean.
 	id := d.Id()
-	vm, err := virtualmachine.FromUUID(client, id)
+	vm, err := virtualmachine.FromUUID(ctx, client, id)
 	if err != nil {
 		return fmt.Errorf("cannot locate virtual machine with UUID %q: %s", id, err)
 	}
 
 	// Determine if we are performing any storage vMotion tasks. This will generate the relocators if there are any.
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(ctx, vm)
 	if err != nil {
 		return fmt.Errorf("error fetching VM properties: %s", err)
 	}
 	devices := object.VirtualDeviceList(vprops.Config.Hardware.Device)
-	relocators, diskRelocateOK, err := virtualdevice.DiskMigrateRelocateOperation(d, client, devices)
+	relocators, diskRelocateOK, err := virtualdevice.DiskMigrateRelocateOperation(ctx, d, client, devices)
 	if err != nil {
 		return err
 	}
@@ -2022,7 +2500,7 @@ func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta in
 
 	// Fetch and validate pool and host
 	poolID := d.Get("resource_pool_id").(string)
-	pool, err := resourcepool.FromID(client, poolID)
+	pool, err := resourcepool.FromID(ctx, client, poolID)
 	if err != nil {
 		return fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
 	}
@@ -2030,10 +2508,10 @@ func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta in
 	if v, ok := d.GetOk("host_system_id"); ok {
 		hsID := v.(string)
 		var err error
-		if hs, err = hostsystem.FromID(client, hsID); err != nil {
+		if hs, err = hostsystem.FromID(ctx, client, hsID); err != nil {
 			return fmt.Errorf("error locating host system at ID %q: %s", hsID, err)
 		}
-		if err := resourcepool.ValidateHost(client, pool, hs); err != nil {
+		if err := resourcepool.ValidateHost(ctx, client, pool, hs); err != nil {
 			return err
 		}
 	}
@@ -2046,7 +2524,7 @@ func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta in
 	// Fetch the datastore only if a datastore_cluster is not set
 	if _, ok := d.GetOk("datastore_cluster_id"); !ok {
 		if dsID, ok := d.GetOk("datastore_id"); ok {
-			ds, err := datastore.FromID(client, dsID.(string))
+			ds, err := datastore.FromID(ctx, client, dsID.(string))
 			if err != nil {
 				return fmt.Errorf("error locating datastore for VM: %s", err)
 			}
@@ -2064,9 +2542,9 @@ func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta in
 	// Ready to perform migration
 	timeout := d.Get("migrate_wait_timeout").(int)
 	if _, ok := d.GetOk("datastore_cluster_id"); ok {
-		err = resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(d, meta, vm, spec, timeout)
+		err = resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(ctx, d, meta, vm, spec, timeout)
 	} else {
-		err = virtualmachine.Relocate(vm, spec, timeout)
+		err = virtualmachine.Relocate(ctx, vm, spec, timeout)
 	}
 	return err
 }
@@ -2076,6 +2554,7 @@ func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta in
 // It's designed to be run when a storage cluster is specified, versus simply
 // specifying datastores.
 func resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	vm *object.VirtualMachine,
@@ -2088,12 +2567,12 @@ func resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(
 	}
 
 	log.Printf("[DEBUG] %s: Running virtual machine relocate Storage DRS API", resourceVSphereVirtualMachineIDString(d))
-	pod, err := storagepod.FromID(client, d.Get("datastore_cluster_id").(string))
+	pod, err := storagepod.FromID(ctx, client, d.Get("datastore_cluster_id").(string))
 	if err != nil {
 		return fmt.Errorf("error getting datastore cluster: %s", err)
 	}
 
-	err = storagepod.RelocateVM(client, vm, spec, timeout, pod)
+	err = storagepod.RelocateVM(ctx, client, vm, spec, timeout, pod)
 	if err != nil {
 		return fmt.Errorf("error running vMotion on datastore cluster %q: %s", pod.Name(), err)
 	}
@@ -2102,7 +2581,7 @@ func resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(
 
 // applyVirtualDevices is used by Create and Update to build a list of virtual
 // device changes.
-func applyVirtualDevices(d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
+func applyVirtualDevices(ctx context.Context, d *schema.ResourceData, c *govmomi.Client, l object.VirtualDeviceList) ([]types.BaseVirtualDeviceConfigSpec, error) {
 	// We filter this device list through each major device class' apply
 	// operation. This will give us a final set of changes that will be our
 	// deviceChange attribute.
@@ -2119,19 +2598,19 @@ func applyVirtualDevices(d *schema.ResourceData, c *govmomi.Client, l object.Vir
 	}
 	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
 	// Disks
-	l, delta, err = virtualdevice.DiskApplyOperation(d, c, l)
+	l, delta, err = virtualdevice.DiskApplyOperation(ctx, d, c, l)
 	if err != nil {
 		return nil, err
 	}
 	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
 	// Network devices
-	l, delta, err = virtualdevice.NetworkInterfaceApplyOperation(d, c, l)
+	l, delta, err = virtualdevice.NetworkInterfaceApplyOperation(ctx, d, c, l)
 	if err != nil {
 		return nil, err
 	}
 	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
 	// CDROM
-	l, delta, err = virtualdevice.CdromApplyOperation(d, c, l)
+	l, delta, err = virtualdevice.CdromApplyOperation(ctx, d, c, l)
 	if err != nil {
 		return nil, err
 	}
@@ -2142,8 +2621,41 @@ func applyVirtualDevices(d *schema.ResourceData, c *govmomi.Client, l object.Vir
 		return nil, err
 	}
 	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
+	// Serial and parallel ports
+	l, delta, err = virtualdevice.SerialPortApplyOperation(ctx, d, c, l)
+	if err != nil {
+		return nil, err
+	}
+	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
+	l, delta, err = virtualdevice.ParallelPortApplyOperation(ctx, d, c, l)
+	if err != nil {
+		return nil, err
+	}
+	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
+	// USB controllers and devices
+	l, delta, err = virtualdevice.UsbControllerApplyOperation(d, c, l)
+	if err != nil {
+		return nil, err
+	}
+	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
+	l, delta, err = virtualdevice.UsbDeviceApplyOperation(d, c, l)
+	if err != nil {
+		return nil, err
+	}
+	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
+	// Dynamic DirectPath I/O and vGPU devices
+	l, delta, err = virtualdevice.DynamicPciDeviceApplyOperation(ctx, d, c, l)
+	if err != nil {
+		return nil, err
+	}
+	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
+	l, delta, err = virtualdevice.VgpuProfileApplyOperation(ctx, d, c, l)
+	if err != nil {
+		return nil, err
+	}
+	spec = virtualdevice.AppendDeviceChangeSpec(spec, delta...)
 	// PCI passthrough devices
-	l, delta, err = virtualdevice.PciPassthroughApplyOperation(d, c, l)
+	l, delta, err = virtualdevice.PciPassthroughApplyOperation(ctx, d, c, l)
 	if err != nil {
 		return nil, err
 	}
@@ -2168,20 +2680,20 @@ func getEvcMode(d *schema.ResourceData) (string, bool) {
 	return newEvcMode, oldEvcMode != newEvcMode
 }
 
-func applyEvcMode(c *govmomi.Client, vm *object.VirtualMachine, mode string) error {
-	mask, err := getFeatureMask(c, mode)
+func applyEvcMode(ctx context.Context, c *govmomi.Client, vm *object.VirtualMachine, mode string) error {
+	mask, err := getFeatureMask(ctx, c, mode)
 	if err != nil {
 		return err
 	}
 
-	return virtualmachine.ApplyEvc(vm, mask)
+	return virtualmachine.ApplyEvc(ctx, vm, mask)
 }
 
-func getFeatureMask(client *govmomi.Client, evcMode string) ([]types.HostFeatureMask, error) {
+func getFeatureMask(ctx context.Context, client *govmomi.Client, evcMode string) ([]types.HostFeatureMask, error) {
 	// The EVC mode-to-feature mapping is available in the Capability property on the ServiceInstance.
 	// We need to query it via the property collector.
 	pc := client.PropertyCollector()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*1)
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*1)
 	defer cancel()
 
 	si := types.ManagedObjectReference{Type: "ServiceInstance", Value: "ServiceInstance"}
@@ -2226,6 +2738,27 @@ func getNewDisks(delta []types.BaseVirtualDeviceConfigSpec) []types.BaseVirtualD
 	return result
 }
 
+// resourceVSphereVirtualMachineWaitForGuest waits for the condition in the
+// wait_for_guest block, if one is configured.
+func resourceVSphereVirtualMachineWaitForGuest(ctx context.Context, d *schema.ResourceData, client *govmomi.Client, vm *object.VirtualMachine) error {
+	if _, ok := d.GetOk("wait_for_guest"); !ok {
+		return nil
+	}
+	cond := &virtualmachine.GuestWaitCondition{
+		GuestInfoKey: d.Get("wait_for_guest.0.guestinfo_key").(string),
+		ToolsReady:   d.Get("wait_for_guest.0.tools_ready").(bool),
+		Property:     d.Get("wait_for_guest.0.property").(string),
+		Value:        d.Get("wait_for_guest.0.value").(string),
+	}
+	if re := d.Get("wait_for_guest.0.regex").(string); re != "" {
+		var err error
+		if cond.Regex, err = regexp.Compile(re); err != nil {
+			return fmt.Errorf("error parsing wait_for_guest regex: %s", err)
+		}
+	}
+	return virtualmachine.WaitForGuestCondition(ctx, client, vm, cond, d.Get("wait_for_guest.0.timeout").(int))
+}
+
 // resourceVSphereVirtualMachineIDString prints a friendly string for the
 // vsphere_virtual_machine resource.
 func resourceVSphereVirtualMachineIDString(d structure.ResourceIDStringer) string {
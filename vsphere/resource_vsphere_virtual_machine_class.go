@@ -6,17 +6,25 @@ package vsphere
 
 import (
 	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/vapi/namespace"
 )
 
 func resourceVsphereVMClass() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVsphereVMClassCreate,
-		Read:   resourceVsphereVMClassRead,
-		Update: resourceVsphereVMClassUpdate,
-		Delete: resourceVsphereVMClassDelete,
+		CreateContext: resourceVsphereVMClassCreate,
+		ReadContext:   resourceVsphereVMClassRead,
+		UpdateContext: resourceVsphereVMClassUpdate,
+		DeleteContext: resourceVsphereVMClassDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -55,7 +63,7 @@ func resourceVsphereVMClass() *schema.Resource {
 	}
 }
 
-func resourceVsphereVMClassCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereVMClassCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	m := namespace.NewManager(c)
 
@@ -76,8 +84,8 @@ func resourceVsphereVMClassCreate(d *schema.ResourceData, meta interface{}) erro
 		vmClassSpec.Devices.VgpuDevices[i] = namespace.VgpuDevice{ProfileName: g.(string)}
 	}
 
-	if err := m.CreateVmClass(context.Background(), vmClassSpec); err != nil {
-		return err
+	if err := m.CreateVmClass(ctx, vmClassSpec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(vmClassSpec.Id)
@@ -85,16 +93,16 @@ func resourceVsphereVMClassCreate(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
-func resourceVsphereVMClassRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereVMClassRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	m := namespace.NewManager(c)
 
-	_, err := m.GetVmClass(context.Background(), d.Id())
+	_, err := m.GetVmClass(ctx, d.Id())
 
-	return err
+	return diag.FromErr(err)
 }
 
-func resourceVsphereVMClassUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereVMClassUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	m := namespace.NewManager(c)
 
@@ -115,12 +123,12 @@ func resourceVsphereVMClassUpdate(d *schema.ResourceData, meta interface{}) erro
 		vmClassSpec.Devices.VgpuDevices[i] = namespace.VgpuDevice{ProfileName: g.(string)}
 	}
 
-	return m.UpdateVmClass(context.Background(), d.Id(), vmClassSpec)
+	return diag.FromErr(m.UpdateVmClass(ctx, d.Id(), vmClassSpec))
 }
 
-func resourceVsphereVMClassDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereVMClassDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	m := namespace.NewManager(c)
 
-	return m.DeleteVmClass(context.Background(), d.Id())
+	return diag.FromErr(m.DeleteVmClass(ctx, d.Id()))
 }
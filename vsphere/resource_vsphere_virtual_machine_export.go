@@ -0,0 +1,267 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/ovfexport"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+)
+
+// resourceVSphereVirtualMachineExportName is the resource name of the
+// vsphere_virtual_machine_export resource.
+const resourceVSphereVirtualMachineExportName = "vsphere_virtual_machine_export"
+
+func resourceVSphereVirtualMachineExport() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSphereVirtualMachineExportCreate,
+		ReadContext:   resourceVSphereVirtualMachineExportRead,
+		DeleteContext: resourceVSphereVirtualMachineExportDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_uuid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The UUID of the virtual machine or template to export.",
+			},
+			"snapshot_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The managed object ID of a snapshot to export from. Allows exporting a powered on virtual machine to a local directory.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The name of the exported package. Defaults to the name of the virtual machine.",
+			},
+			"output_directory": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The local directory to write the exported package to.",
+				AtLeastOneOf: []string{"output_directory", "content_library"},
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      ovfexport.FormatOVF,
+				Description:  "The format of the exported package written to output_directory. One of ovf or ova.",
+				ValidateFunc: validation.StringInSlice(ovfexport.Formats, false),
+			},
+			"include_image_files": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Include ISO and floppy images attached to the virtual machine in the exported package.",
+			},
+			"manifest_algorithm": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "sha256",
+				Description:  "The checksum algorithm used for the manifest. One of sha1, sha256, or sha512.",
+				ValidateFunc: validation.StringInSlice(ovfexport.ManifestAlgorithms, false),
+			},
+			"overwrite": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Overwrite an existing package of the same name in output_directory.",
+			},
+			"content_library": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				MaxItems:     1,
+				Description:  "Captures the virtual machine as a new OVF item in a content library.",
+				AtLeastOneOf: []string{"output_directory", "content_library"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"library_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "The ID of the content library to create the item in.",
+						},
+						"item_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "The name of the content library item. Defaults to the name of the exported package.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "The description of the content library item.",
+						},
+					},
+				},
+			},
+			"output_files": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The paths of the files written to output_directory.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"manifest": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The checksums of the files in the exported package, keyed by file name.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"content_library_item_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the content library item created from the virtual machine.",
+			},
+		},
+	}
+}
+
+func resourceVSphereVirtualMachineExportCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	uuid := d.Get("virtual_machine_uuid").(string)
+	vm, err := virtualmachine.FromUUID(ctx, client, uuid)
+	if err != nil {
+		return diag.Errorf("error fetching virtual machine: %s", err)
+	}
+	name := d.Get("name").(string)
+	if name == "" {
+		name = vm.Name()
+	}
+	d.SetId(fmt.Sprintf("%s:%s", uuid, name))
+	_ = d.Set("name", name)
+
+	if dir, ok := d.GetOk("output_directory"); ok {
+		log.Printf("[DEBUG] %s: Exporting virtual machine to %q", resourceVSphereVirtualMachineExportIDString(d), dir)
+		result, err := ovfexport.Export(ctx, vm, &ovfexport.ExportParams{
+			Name:              name,
+			Directory:         dir.(string),
+			Format:            d.Get("format").(string),
+			SnapshotID:        d.Get("snapshot_id").(string),
+			IncludeImageFiles: d.Get("include_image_files").(bool),
+			ManifestAlgorithm: d.Get("manifest_algorithm").(string),
+			Overwrite:         d.Get("overwrite").(bool),
+		})
+		if err != nil {
+			d.SetId("")
+			return diag.Errorf("error exporting virtual machine: %s", err)
+		}
+		_ = d.Set("output_files", result.Files)
+		_ = d.Set("manifest", result.Manifest)
+	}
+
+	if _, ok := d.GetOk("content_library"); ok {
+		id, err := resourceVSphereVirtualMachineExportToLibrary(ctx, d, meta, name)
+		if err != nil {
+			// Keep the local export in state, so that it is cleaned up.
+			if _, ok := d.GetOk("output_directory"); !ok {
+				d.SetId("")
+			}
+			return diag.Errorf("error capturing virtual machine to content library: %s", err)
+		}
+		_ = d.Set("content_library_item_id", id)
+	}
+	return resourceVSphereVirtualMachineExportRead(ctx, d, meta)
+}
+
+func resourceVSphereVirtualMachineExportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	for _, f := range structure.SliceInterfacesToStrings(d.Get("output_files").([]interface{})) {
+		if _, err := os.Stat(f); err != nil {
+			if os.IsNotExist(err) {
+				log.Printf("[DEBUG] %s: Exported file %q not found, marking resource as gone", resourceVSphereVirtualMachineExportIDString(d), f)
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(err)
+		}
+	}
+	if id := d.Get("content_library_item_id").(string); id != "" {
+		item, err := contentlibrary.ItemFromID(ctx, meta.(*Client).restClient, id)
+		if err != nil {
+			if strings.Contains(err.Error(), "404 Not Found") {
+				log.Printf("[DEBUG] %s: Content library item %q not found, marking resource as gone", resourceVSphereVirtualMachineExportIDString(d), id)
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(err)
+		}
+		log.Printf("[DEBUG] %s: Content library item %q found", resourceVSphereVirtualMachineExportIDString(d), item.Name)
+	}
+	return nil
+}
+
+func resourceVSphereVirtualMachineExportDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := ovfexport.Remove(structure.SliceInterfacesToStrings(d.Get("output_files").([]interface{}))); err != nil {
+		return diag.Errorf("error removing exported files: %s", err)
+	}
+	if id := d.Get("content_library_item_id").(string); id != "" {
+		rc := meta.(*Client).restClient
+		item, err := contentlibrary.ItemFromID(ctx, rc, id)
+		if err != nil {
+			if strings.Contains(err.Error(), "404 Not Found") {
+				return nil
+			}
+			return diag.FromErr(err)
+		}
+		if err := contentlibrary.DeleteLibraryItem(ctx, rc, item); err != nil {
+			return diag.Errorf("error deleting content library item: %s", err)
+		}
+	}
+	return nil
+}
+
+// resourceVSphereVirtualMachineExportToLibrary captures the virtual machine as
+// a new OVF item in the content library configured in the content_library
+// block, and returns the ID of the item.
+func resourceVSphereVirtualMachineExportToLibrary(ctx context.Context, d *schema.ResourceData, meta interface{}, name string) (string, error) {
+	rc := meta.(*Client).restClient
+	lib, err := contentlibrary.FromID(ctx, rc, d.Get("content_library.0.library_id").(string))
+	if err != nil {
+		return "", err
+	}
+	moid, err := virtualmachine.MOIDForUUID(ctx, meta.(*Client).vimClient, d.Get("virtual_machine_uuid").(string))
+	if err != nil {
+		return "", err
+	}
+	itemName := d.Get("content_library.0.item_name").(string)
+	if itemName == "" {
+		itemName = name
+	}
+	id, err := contentlibrary.CreateLibraryItem(ctx, rc, meta.(*Client).TLSOptions(), false, lib, itemName, d.Get("content_library.0.description").(string), "ovf", "", moid.MOID)
+	if err != nil {
+		return "", err
+	}
+	return *id, nil
+}
+
+// resourceVSphereVirtualMachineExportIDString prints a friendly string for the
+// vsphere_virtual_machine_export resource.
+func resourceVSphereVirtualMachineExportIDString(d structure.ResourceIDStringer) string {
+	return structure.ResourceIDString(d, resourceVSphereVirtualMachineExportName)
+}
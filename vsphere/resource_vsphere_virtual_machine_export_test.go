@@ -0,0 +1,180 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
+)
+
+func TestAccResourceVSphereVirtualMachineExport_ova(t *testing.T) {
+	dir := t.TempDir()
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineExportCheckFiles(filepath.Join(dir, "testacc-export.ova"), false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineExportConfigSnapshot(dir),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineExportCheckFiles(filepath.Join(dir, "testacc-export.ova"), true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_export.export", "output_files.#", "1"),
+					resource.TestCheckResourceAttrSet("vsphere_virtual_machine_export.export", "manifest.testacc-export.ovf"),
+					resource.TestCheckResourceAttrSet("vsphere_virtual_machine_export.export", "manifest.testacc-export.mf"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachineExport_contentLibrary(t *testing.T) {
+	dir := t.TempDir()
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			testAccCheckEnvVariables(t, []string{"TF_VAR_VSPHERE_TEMPLATE"})
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineExportCheckFiles(filepath.Join(dir, "testacc-export.ovf"), false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineExportConfigContentLibrary(dir),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineExportCheckFiles(filepath.Join(dir, "testacc-export.ovf"), true),
+					testAccResourceVSphereVirtualMachineExportCheckFiles(filepath.Join(dir, "testacc-export.mf"), true),
+					testAccResourceVSphereVirtualMachineExportCheckLibraryItem(),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceVSphereVirtualMachineExportCheckFiles(path string, expected bool) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		_, err := os.Stat(path)
+		switch {
+		case err != nil && !os.IsNotExist(err):
+			return err
+		case err == nil && !expected:
+			return fmt.Errorf("expected %s to be removed", path)
+		case err != nil && expected:
+			return fmt.Errorf("expected %s to exist", path)
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereVirtualMachineExportCheckLibraryItem() resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["vsphere_virtual_machine_export.export"]
+		if !ok {
+			return errors.New("vsphere_virtual_machine_export.export not found in state")
+		}
+		item, err := contentlibrary.ItemFromID(context.Background(), testAccProvider.Meta().(*Client).restClient, rs.Primary.Attributes["content_library_item_id"])
+		if err != nil {
+			return err
+		}
+		if item.Name != "testacc-export-item" {
+			return fmt.Errorf("expected content library item name to be testacc-export-item, got %s", item.Name)
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereVirtualMachineExportConfigSnapshot(dir string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 1024
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label = "disk0"
+    size  = 1
+  }
+}
+
+resource "vsphere_virtual_machine_snapshot" "snapshot" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm.uuid
+  snapshot_name        = "terraform-test-export"
+  description          = "Managed by Terraform"
+  memory               = false
+  quiesce              = false
+}
+
+resource "vsphere_virtual_machine_export" "export" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm.uuid
+  snapshot_id          = vsphere_virtual_machine_snapshot.snapshot.id
+  name                 = "testacc-export"
+  output_directory     = "%s"
+  format               = "ova"
+}
+`,
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		dir,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineExportConfigContentLibrary(dir string) string {
+	return fmt.Sprintf(`
+%s
+
+variable "template" {
+  default = "%s"
+}
+
+data "vsphere_virtual_machine" "template" {
+  name          = var.template
+  datacenter_id = data.vsphere_datacenter.rootdc1.id
+}
+
+resource "vsphere_content_library" "library" {
+  name            = "testacc_content_library"
+  storage_backing = [data.vsphere_datastore.rootds1.id]
+}
+
+resource "vsphere_virtual_machine_export" "export" {
+  virtual_machine_uuid = data.vsphere_virtual_machine.template.id
+  name                 = "testacc-export"
+  output_directory     = "%s"
+  manifest_algorithm   = "sha512"
+
+  content_library {
+    library_id  = vsphere_content_library.library.id
+    item_name   = "testacc-export-item"
+    description = "Managed by Terraform"
+  }
+}
+`,
+		testhelper.CombineConfigs(testhelper.ConfigDataRootDC1(), testhelper.ConfigDataRootDS1()),
+		os.Getenv("TF_VAR_VSPHERE_TEMPLATE"),
+		dir,
+	)
+}
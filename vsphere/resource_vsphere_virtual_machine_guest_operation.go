@@ -0,0 +1,275 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/guestoperation"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+)
+
+// resourceVSphereVirtualMachineGuestOperationName is the resource name of the
+// vsphere_virtual_machine_guest_operation resource.
+const resourceVSphereVirtualMachineGuestOperationName = "vsphere_virtual_machine_guest_operation"
+
+func resourceVSphereVirtualMachineGuestOperation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSphereVirtualMachineGuestOperationCreate,
+		ReadContext:   resourceVSphereVirtualMachineGuestOperationRead,
+		DeleteContext: resourceVSphereVirtualMachineGuestOperationDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"virtual_machine_uuid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The UUID of the virtual machine to run the guest operations in.",
+			},
+			"guest_username": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The user name to authenticate to the guest operating system with.",
+				ExactlyOneOf: []string{"guest_username", "sspi_token"},
+				RequiredWith: []string{"guest_password"},
+			},
+			"guest_password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				Description:  "The password to authenticate to the guest operating system with.",
+				RequiredWith: []string{"guest_username"},
+			},
+			"sspi_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "A base64 encoded SSPI token to authenticate to a Windows guest operating system with.",
+			},
+			"interactive_session": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Run the guest operations in the interactive session of the user.",
+			},
+			"file": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "A file to copy into the guest before the program runs.",
+				AtLeastOneOf: []string{"file", "program_path"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "The path of a local file to copy. Conflicts with content.",
+						},
+						"content": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Sensitive:   true,
+							Description: "The content of the file to copy. Conflicts with source.",
+						},
+						"destination": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "The absolute path of the file in the guest.",
+						},
+						"overwrite": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     true,
+							Description: "Overwrite an existing file in the guest.",
+						},
+					},
+				},
+			},
+			"program_path": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The absolute path of the program to run in the guest.",
+				AtLeastOneOf: []string{"file", "program_path"},
+			},
+			"arguments": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The arguments passed to the program.",
+				RequiredWith: []string{"program_path"},
+			},
+			"working_directory": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The working directory of the program.",
+				RequiredWith: []string{"program_path"},
+			},
+			"environment": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The environment variables of the program.",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				RequiredWith: []string{"program_path"},
+			},
+			"capture_output": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Capture the standard output and standard error of the program through temporary files in the guest.",
+			},
+			"ignore_exit_code": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Do not fail when the program exits with a non-zero exit code.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that, when changed, run the guest operations again.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"exit_code": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The exit code of the program.",
+			},
+			"stdout": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The standard output of the program.",
+			},
+			"stderr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The standard error of the program.",
+			},
+		},
+	}
+}
+
+func resourceVSphereVirtualMachineGuestOperationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	vm, err := virtualmachine.FromUUID(ctx, client, d.Get("virtual_machine_uuid").(string))
+	if err != nil {
+		return diag.Errorf("error fetching virtual machine: %s", err)
+	}
+
+	var files []*guestoperation.File
+	for _, v := range d.Get("file").([]interface{}) {
+		f := v.(map[string]interface{})
+		file := &guestoperation.File{
+			Source:      f["source"].(string),
+			Content:     f["content"].(string),
+			Destination: f["destination"].(string),
+			Overwrite:   f["overwrite"].(bool),
+		}
+		if file.Source != "" && file.Content != "" {
+			return diag.Errorf("file %q: only one of source or content can be set", file.Destination)
+		}
+		files = append(files, file)
+	}
+
+	c, err := guestoperation.NewClient(ctx, client, vm, resourceVSphereVirtualMachineGuestOperationAuth(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, f := range files {
+		if err := guestoperation.Upload(ctx, c, f); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if path, ok := d.GetOk("program_path"); ok {
+		var env []string
+		for k, v := range d.Get("environment").(map[string]interface{}) {
+			env = append(env, fmt.Sprintf("%s=%s", k, v.(string)))
+		}
+		result, err := guestoperation.Run(ctx, c, &guestoperation.Program{
+			Path:             path.(string),
+			Arguments:        d.Get("arguments").(string),
+			WorkingDirectory: d.Get("working_directory").(string),
+			Env:              env,
+			CaptureOutput:    d.Get("capture_output").(bool),
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if result.ExitCode != 0 && !d.Get("ignore_exit_code").(bool) {
+			return diag.Errorf("%s exited with code %d: %s", path, result.ExitCode, result.Stderr)
+		}
+		_ = d.Set("exit_code", result.ExitCode)
+		_ = d.Set("stdout", result.Stdout)
+		_ = d.Set("stderr", result.Stderr)
+	}
+
+	d.SetId(id.UniqueId())
+	log.Printf("[DEBUG] %s: Guest operations complete", resourceVSphereVirtualMachineGuestOperationIDString(d))
+	return resourceVSphereVirtualMachineGuestOperationRead(ctx, d, meta)
+}
+
+func resourceVSphereVirtualMachineGuestOperationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	if _, err := virtualmachine.FromUUID(ctx, client, d.Get("virtual_machine_uuid").(string)); err != nil {
+		var notFoundError *virtualmachine.UUIDNotFoundError
+		if errors.As(err, &notFoundError) {
+			log.Printf("[DEBUG] %s: Virtual machine not found, marking resource as gone", resourceVSphereVirtualMachineGuestOperationIDString(d))
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceVSphereVirtualMachineGuestOperationDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	// Guest operations cannot be undone. Files copied into the guest are left
+	// in place.
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereVirtualMachineGuestOperationAuth returns the guest
+// authentication configured on the resource.
+func resourceVSphereVirtualMachineGuestOperationAuth(d *schema.ResourceData) types.BaseGuestAuthentication {
+	interactive := d.Get("interactive_session").(bool)
+	if token, ok := d.GetOk("sspi_token"); ok {
+		return guestoperation.SSPIAuth(token.(string), interactive)
+	}
+	return guestoperation.NamePasswordAuth(d.Get("guest_username").(string), d.Get("guest_password").(string), interactive)
+}
+
+// resourceVSphereVirtualMachineGuestOperationIDString prints a friendly
+// string for the vsphere_virtual_machine_guest_operation resource.
+func resourceVSphereVirtualMachineGuestOperationIDString(d structure.ResourceIDStringer) string {
+	return structure.ResourceIDString(d, resourceVSphereVirtualMachineGuestOperationName)
+}
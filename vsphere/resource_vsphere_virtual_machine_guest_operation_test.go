@@ -0,0 +1,108 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccResourceVSphereVirtualMachineGuestOperation_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			testAccCheckEnvVariables(t, []string{"TF_VAR_VSPHERE_TEMPLATE", "TF_VAR_VSPHERE_GUEST_USERNAME", "TF_VAR_VSPHERE_GUEST_PASSWORD"})
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineGuestOperationConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_guest_operation.op", "exit_code", "0"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_guest_operation.op", "stdout", "hello\n"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereVirtualMachineGuestOperationConfig("2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_guest_operation.op", "triggers.run", "2"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_guest_operation.op", "stdout", "hello\n"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceVSphereVirtualMachineGuestOperationConfig(run string) string {
+	return fmt.Sprintf(`
+%s
+
+data "vsphere_virtual_machine" "template" {
+  name          = "%s"
+  datacenter_id = data.vsphere_datacenter.rootdc1.id
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = data.vsphere_virtual_machine.template.guest_id
+
+  network_interface {
+    network_id   = data.vsphere_network.network1.id
+    adapter_type = data.vsphere_virtual_machine.template.network_interface_types[0]
+  }
+
+  disk {
+    label            = "disk0"
+    size             = data.vsphere_virtual_machine.template.disks.0.size
+    thin_provisioned = data.vsphere_virtual_machine.template.disks.0.thin_provisioned
+  }
+
+  clone {
+    template_uuid = data.vsphere_virtual_machine.template.id
+  }
+
+  wait_for_guest {
+    tools_ready = true
+  }
+}
+
+resource "vsphere_virtual_machine_guest_operation" "op" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm.id
+  guest_username       = "%s"
+  guest_password       = "%s"
+
+  file {
+    content     = "echo $GREETING"
+    destination = "/tmp/testacc-guest-operation.sh"
+  }
+
+  program_path = "/bin/sh"
+  arguments    = "/tmp/testacc-guest-operation.sh"
+  environment = {
+    GREETING = "hello"
+  }
+
+  triggers = {
+    run = "%s"
+  }
+}
+`,
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		os.Getenv("TF_VAR_VSPHERE_TEMPLATE"),
+		os.Getenv("TF_VAR_VSPHERE_GUEST_USERNAME"),
+		os.Getenv("TF_VAR_VSPHERE_GUEST_PASSWORD"),
+		run,
+	)
+}
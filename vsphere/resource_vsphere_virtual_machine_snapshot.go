@@ -6,20 +6,35 @@ package vsphere
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
 )
 
 func resourceVSphereVirtualMachineSnapshot() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereVirtualMachineSnapshotCreate,
-		Read:   resourceVSphereVirtualMachineSnapshotRead,
-		Delete: resourceVSphereVirtualMachineSnapshotDelete,
+		CreateContext: resourceVSphereVirtualMachineSnapshotCreate,
+		ReadContext:   resourceVSphereVirtualMachineSnapshotRead,
+		UpdateContext: resourceVSphereVirtualMachineSnapshotUpdate,
+		DeleteContext: resourceVSphereVirtualMachineSnapshotDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereVirtualMachineSnapshotImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"virtual_machine_uuid": {
@@ -28,14 +43,14 @@ func resourceVSphereVirtualMachineSnapshot() *schema.Resource {
 				ForceNew: true,
 			},
 			"snapshot_name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the snapshot. Changing this renames the snapshot.",
 			},
 			"description": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The description of the snapshot.",
 			},
 			"memory": {
 				Type:     schema.TypeBool,
@@ -50,50 +65,85 @@ func resourceVSphereVirtualMachineSnapshot() *schema.Resource {
 			"remove_children": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
 			},
 			"consolidate": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
+			},
+			"revert_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An arbitrary value that, when changed, reverts the virtual machine to this snapshot. Not used when the snapshot is created.",
+			},
+			"suppress_power_on": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Do not power on the virtual machine after reverting to this snapshot, even if it was powered on when the snapshot was taken.",
+			},
+			"parent_snapshot_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The managed object ID of the parent snapshot, if any.",
+			},
+			"child_snapshot_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The managed object IDs of the child snapshots.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the snapshot was created.",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the files that make up the snapshot, in bytes.",
+			},
+			"current": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether this snapshot is the current snapshot of the virtual machine.",
 			},
 		},
 	}
 }
 
-func resourceVSphereVirtualMachineSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineSnapshotCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	vm, err := virtualmachine.FromUUID(client, d.Get("virtual_machine_uuid").(string))
+	vm, err := virtualmachine.FromUUID(ctx, client, d.Get("virtual_machine_uuid").(string))
 	if err != nil {
-		return fmt.Errorf("error while getting the virtual machine :%s", err)
+		return diag.Errorf("error while getting the virtual machine :%s", err)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout) // This is 5 mins
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout) // This is 5 mins
 	defer cancel()
 	task, err := vm.CreateSnapshot(ctx, d.Get("snapshot_name").(string), d.Get("description").(string), d.Get("memory").(bool), d.Get("quiesce").(bool))
 	if err != nil {
 		log.Printf("[DEBUG] Error while creating for the create snapshot task: %v", err)
-		return fmt.Errorf("error while creating for the create snapshot task: %s", err)
+		return diag.Errorf("error while creating for the create snapshot task: %s", err)
 	}
 	log.Printf("[DEBUG] Task created for create snapshot: %v", task)
 
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	taskInfo, err := task.WaitForResultEx(tctx, nil)
+	taskInfo, err := viapi.WaitForTaskResult(tctx, task)
 	if err != nil {
 		log.Printf("[DEBUG] Error while waiting for the create snapshot task: %v", err)
-		return fmt.Errorf(" error while waiting for the create snapshot task: %s", err)
+		return diag.Errorf(" error while waiting for the create snapshot task: %s", err)
 	}
 	log.Printf("[DEBUG] Create snapshot completed %v", d.Get("snapshot_name").(string))
 	log.Println("[DEBUG] Managed Object Reference: " + taskInfo.Result.(types.ManagedObjectReference).Value)
 	d.SetId(taskInfo.Result.(types.ManagedObjectReference).Value)
-	return nil
+	return resourceVSphereVirtualMachineSnapshotRead(ctx, d, meta)
 }
 
-func resourceVSphereVirtualMachineSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	vm, err := virtualmachine.FromUUID(client, d.Get("virtual_machine_uuid").(string))
+	vm, err := virtualmachine.FromUUID(ctx, client, d.Get("virtual_machine_uuid").(string))
 	if err != nil {
-		return fmt.Errorf("error while getting the virtual machine :%s", err)
+		return diag.Errorf("error while getting the virtual machine :%s", err)
 	}
 
 	if d.Id() == "" {
@@ -116,43 +166,126 @@ func resourceVSphereVirtualMachineSnapshotDelete(d *schema.ResourceData, meta in
 	} else {
 		removeChildren = false
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout) // This is 5 mins
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout) // This is 5 mins
 	defer cancel()
 	task, err := vm.RemoveSnapshot(ctx, d.Id(), removeChildren, consolidatePtr)
 	if err != nil {
 		log.Printf("[DEBUG] Error while creating the delete snapshot task: %v", err)
-		return fmt.Errorf("error while creating the delete snapshot task: %s", err)
+		return diag.Errorf("error while creating the delete snapshot task: %s", err)
 	}
 	log.Printf("[DEBUG] Task created for delete snapshot: %v", task)
 
-	err = task.WaitEx(ctx)
+	err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		log.Printf("[DEBUG] Error while waiting for the delete snapshot task: %v", err)
-		return fmt.Errorf("error while waiting for the delete snapshot task: %s", err)
+		return diag.Errorf("error while waiting for the delete snapshot task: %s", err)
 	}
 	log.Printf("[DEBUG] Delete snapshot completed %v", d.Get("snapshot_name").(string))
 
 	return nil
 }
 
-func resourceVSphereVirtualMachineSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineSnapshotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	vm, err := virtualmachine.FromUUID(client, d.Get("virtual_machine_uuid").(string))
+	vm, err := virtualmachine.FromUUID(ctx, client, d.Get("virtual_machine_uuid").(string))
 	if err != nil {
-		return fmt.Errorf("error while getting the virtual machine :%s", err)
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout) // This is 5 mins
-	defer cancel()
-	snapshot, err := vm.FindSnapshot(ctx, d.Id())
-	if err != nil {
-		if strings.Contains(err.Error(), "no snapshots for this VM") || strings.Contains(err.Error(), "snapshot \""+d.Get("snapshot_name").(string)+"\" not found") {
-			log.Printf("[DEBUG] Error while finding the snapshot: %v", err)
+		var notFoundError *virtualmachine.UUIDNotFoundError
+		if errors.As(err, &notFoundError) {
+			log.Printf("[DEBUG] Virtual machine for snapshot %q not found, marking resource as gone: %s", d.Id(), err)
 			d.SetId("")
 			return nil
 		}
-		log.Printf("[DEBUG] Error while finding the snapshot: %v", err)
-		return fmt.Errorf("error while finding the snapshot :%s", err)
+		return diag.Errorf("error while getting the virtual machine :%s", err)
+	}
+	props, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return diag.Errorf("error fetching VM properties: %s", err)
+	}
+	node, err := virtualmachine.FindSnapshotNode(props, d.Id())
+	if err != nil {
+		return diag.Errorf("error while finding the snapshot :%s", err)
+	}
+	if node == nil {
+		log.Printf("[DEBUG] Snapshot %q not found, marking resource as gone", d.Id())
+		d.SetId("")
+		return nil
+	}
+	log.Printf("[DEBUG] Snapshot found: %s", node.Tree.Snapshot.Value)
+	if err := flattenVirtualMachineSnapshotNode(d, props, *node); err != nil {
+		return diag.FromErr(err)
 	}
-	log.Printf("[DEBUG] Snapshot found: %v", snapshot)
 	return nil
 }
+
+func resourceVSphereVirtualMachineSnapshotUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	if d.HasChanges("snapshot_name", "description") {
+		ref := types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: d.Id()}
+		if err := virtualmachine.RenameSnapshot(ctx, client, ref, d.Get("snapshot_name").(string), d.Get("description").(string)); err != nil {
+			return diag.Errorf("error while renaming the snapshot: %s", err)
+		}
+	}
+	if d.HasChange("revert_trigger") {
+		vm, err := virtualmachine.FromUUID(ctx, client, d.Get("virtual_machine_uuid").(string))
+		if err != nil {
+			return diag.Errorf("error while getting the virtual machine :%s", err)
+		}
+		if err := virtualmachine.RevertToSnapshot(ctx, vm, d.Id(), d.Get("suppress_power_on").(bool)); err != nil {
+			return diag.Errorf("error while reverting to the snapshot: %s", err)
+		}
+		log.Printf("[DEBUG] Revert to snapshot %q completed", d.Id())
+	}
+	return resourceVSphereVirtualMachineSnapshotRead(ctx, d, meta)
+}
+
+func resourceVSphereVirtualMachineSnapshotImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("ID must be of the form <virtual_machine_uuid>:<snapshot_name_or_id>, got %q", d.Id())
+	}
+	client := meta.(*Client).vimClient
+	vm, err := virtualmachine.FromUUID(ctx, client, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("error while getting the virtual machine: %s", err)
+	}
+	props, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching VM properties: %s", err)
+	}
+	node, err := virtualmachine.FindSnapshotNode(props, parts[1])
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("snapshot %q not found on virtual machine %q", parts[1], parts[0])
+	}
+	d.SetId(node.Tree.Snapshot.Value)
+	_ = d.Set("virtual_machine_uuid", parts[0])
+	// The memory and quiesce settings are only read on import, as they depend
+	// on the state of the virtual machine when the snapshot was taken and
+	// would otherwise cause snapshots to be replaced.
+	_ = d.Set("memory", node.Tree.State == types.VirtualMachinePowerStatePoweredOn)
+	_ = d.Set("quiesce", node.Tree.Quiesced)
+	_ = d.Set("suppress_power_on", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// flattenVirtualMachineSnapshotNode reads the attributes of a snapshot into
+// the supplied ResourceData.
+func flattenVirtualMachineSnapshotNode(d *schema.ResourceData, props *mo.VirtualMachine, node virtualmachine.SnapshotNode) error {
+	var parentID string
+	if node.Parent != nil {
+		parentID = node.Parent.Snapshot.Value
+	}
+	var childIDs []string
+	for _, child := range node.Tree.ChildSnapshotList {
+		childIDs = append(childIDs, child.Snapshot.Value)
+	}
+	_ = d.Set("snapshot_name", node.Tree.Name)
+	_ = d.Set("description", node.Tree.Description)
+	_ = d.Set("parent_snapshot_id", parentID)
+	_ = d.Set("create_time", node.Tree.CreateTime.Format(time.RFC3339))
+	_ = d.Set("size", virtualmachine.SnapshotSize(props, node))
+	_ = d.Set("current", virtualmachine.IsCurrentSnapshot(props, node))
+	return d.Set("child_snapshot_ids", childIDs)
+}
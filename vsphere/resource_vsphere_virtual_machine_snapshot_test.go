@@ -6,6 +6,7 @@ package vsphere
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -45,6 +46,54 @@ func TestAccResourceVSphereVirtualMachineSnapshot_basic(t *testing.T) {
 	})
 }
 
+func TestAccResourceVSphereVirtualMachineSnapshot_updateAndImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVirtualMachineSnapshotExists("vsphere_virtual_machine_snapshot.snapshot", false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineSnapshotConfigUpdate("terraform-test-snapshot", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVirtualMachineSnapshotExists("vsphere_virtual_machine_snapshot.snapshot", true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "current", "true"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "parent_snapshot_id", ""),
+					resource.TestCheckResourceAttrSet("vsphere_virtual_machine_snapshot.snapshot", "create_time"),
+					resource.TestCheckResourceAttrSet("vsphere_virtual_machine_snapshot.snapshot", "size"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereVirtualMachineSnapshotConfigUpdate("terraform-test-snapshot-renamed", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVirtualMachineSnapshotExists("vsphere_virtual_machine_snapshot.snapshot", true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "snapshot_name", "terraform-test-snapshot-renamed"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine_snapshot.snapshot", "current", "true"),
+				),
+			},
+			{
+				ResourceName:      "vsphere_virtual_machine_snapshot.snapshot",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"consolidate",
+					"remove_children",
+					"revert_trigger",
+				},
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["vsphere_virtual_machine_snapshot.snapshot"]
+					if !ok {
+						return "", errors.New("vsphere_virtual_machine_snapshot.snapshot not found in state")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["virtual_machine_uuid"], rs.Primary.Attributes["snapshot_name"]), nil
+				},
+			},
+		},
+	})
+}
+
 func testAccResourceVSphereVirtualMachineSnapshotPreCheck(t *testing.T) {
 	if os.Getenv("TF_VAR_VSPHERE_DATACENTER") == "" {
 		t.Skip("set TF_VAR_VSPHERE_DATACENTER to run vsphere_virtual_machine_snapshot acceptance tests")
@@ -84,7 +133,7 @@ func snapshotExists(n string, s *terraform.State) (bool, error) {
 	}
 	client := testAccProvider.Meta().(*Client).vimClient
 
-	vm, err := virtualmachine.FromUUID(client, rs.Primary.Attributes["virtual_machine_uuid"])
+	vm, err := virtualmachine.FromUUID(context.Background(), client, rs.Primary.Attributes["virtual_machine_uuid"])
 	if err != nil {
 		return false, fmt.Errorf("error %s", err)
 	}
@@ -125,11 +174,11 @@ func testAccCheckVirtualMachineHasNoSnapshots(n string) resource.TestCheckFunc {
 		}
 		client := testAccProvider.Meta().(*Client).vimClient
 
-		vm, err := virtualmachine.FromUUID(client, rs.Primary.Attributes["uuid"])
+		vm, err := virtualmachine.FromUUID(context.Background(), client, rs.Primary.Attributes["uuid"])
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
-		props, err := virtualmachine.Properties(vm)
+		props, err := virtualmachine.Properties(context.Background(), vm)
 		if err != nil {
 			return fmt.Errorf("cannot get properties for virtual machine: %s", err)
 		}
@@ -225,3 +274,43 @@ resource "vsphere_virtual_machine_snapshot" "snapshot" {
 		enabled,
 	)
 }
+
+func testAccResourceVSphereVirtualMachineSnapshotConfigUpdate(name, revertTrigger string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 1024
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label = "disk0"
+    size  = 1
+  }
+}
+
+resource "vsphere_virtual_machine_snapshot" "snapshot" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm.uuid
+  snapshot_name        = "%s"
+  description          = "Managed by Terraform"
+  memory               = false
+  quiesce              = false
+  revert_trigger       = "%s"
+}
+`,
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		name,
+		revertTrigger,
+	)
+}
@@ -5,6 +5,8 @@
 package vsphere
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net"
@@ -1254,6 +1256,26 @@ func TestAccResourceVSphereVirtualMachine_extraConfig(t *testing.T) {
 	})
 }
 
+func TestAccResourceVSphereVirtualMachine_waitForGuestInfo(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigWaitForGuestInfo("do.*"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "wait_for_guest.0.guestinfo_key", "bootstrap"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceVSphereVirtualMachine_extraConfigSwapKeys(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -1282,6 +1304,181 @@ func TestAccResourceVSphereVirtualMachine_extraConfigSwapKeys(t *testing.T) {
 	})
 }
 
+func TestAccResourceVSphereVirtualMachine_serialPort(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigSerialPort("telnet://:5000"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "serial_port.#", "1"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "serial_port.0.service_uri", "telnet://:5000"),
+					resource.TestMatchResourceAttr("vsphere_virtual_machine.vm", "serial_port.0.device_address", regexp.MustCompile("^sio:0:")),
+				),
+			},
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigSerialPort("telnet://:5001"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "serial_port.0.service_uri", "telnet://:5001"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachine_usbController(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigUsbController(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "usb_controller.#", "1"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "usb_controller.0.type", "xhci"),
+					resource.TestCheckResourceAttrSet("vsphere_virtual_machine.vm", "usb_controller.0.key"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigUsbController(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "usb_controller.0.auto_connect_devices", "true"),
+				),
+			},
+			{
+				Config:      testAccResourceVSphereVirtualMachineConfigUsbDeviceNoController(),
+				ExpectError: regexp.MustCompile("a usb_controller is required to attach a USB device"),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachine_dynamicPciDeviceNotOnHost(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceVSphereVirtualMachineConfigDynamicPciDevice("ffff", "ffff"),
+				ExpectError: regexp.MustCompile("no host available to the virtual machine has a PCI device"),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachine_rdmDiskLunNotFound(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceVSphereVirtualMachineConfigRdmDisk("naa.00000000000000000000000000000000"),
+				ExpectError: regexp.MustCompile("was not found on any host available to the virtual machine"),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachine_firstClassDisk(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigFirstClassDisk(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttrPair("vsphere_virtual_machine.vm", "disk.1.first_class_disk_id", "vsphere_first_class_disk.disk", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachine_encryption(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigEncryption(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttrPair("vsphere_virtual_machine.vm", "encryption.0.key_provider_id", "vsphere_key_provider.provider", "id"),
+					resource.TestCheckResourceAttrSet("vsphere_virtual_machine.vm", "encryption.0.key_id"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "encryption.0.encrypt_disks", "true"),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "encryption.0.migrate_encryption", "required"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigEncryption(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "encryption.0.encrypt_disks", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachine_cloudInit(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigCloudInit("testacc-1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					testAccResourceVSphereVirtualMachineCheckExtraConfig("guestinfo.metadata", base64.StdEncoding.EncodeToString([]byte("local-hostname: testacc-1\n"))),
+					testAccResourceVSphereVirtualMachineCheckExtraConfig("guestinfo.metadata.encoding", "base64"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigCloudInit("testacc-2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "reboot_required", "false"),
+					testAccResourceVSphereVirtualMachineCheckExtraConfig("guestinfo.metadata", base64.StdEncoding.EncodeToString([]byte("local-hostname: testacc-2\n"))),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceVSphereVirtualMachine_attachExistingVmdk(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -1745,6 +1942,51 @@ func TestAccResourceVSphereVirtualMachine_clonePoweredOn(t *testing.T) {
 	})
 }
 
+func TestAccResourceVSphereVirtualMachine_cloneInstant(t *testing.T) {
+	testAccSkipUnstable(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigCloneInstant(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					testAccResourceVSphereVirtualMachineCheckPowerState(types.VirtualMachinePowerStatePoweredOn),
+					testAccResourceVSphereVirtualMachineCheckAnnotation(),
+					testAccResourceVSphereVirtualMachineCheckExtraConfig("guestinfo.hostname", "terraform-test2"),
+					testAccResourceVSphereVirtualMachineCheckExtraConfig("foo", "bar"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereVirtualMachine_cloneLinkedFromSnapshotName(t *testing.T) {
+	testAccSkipUnstable(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigCloneLinkedFromSnapshotName(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestCheckResourceAttr("vsphere_virtual_machine.vm", "clone.0.snapshot_name", "terraform-test-first"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceVSphereVirtualMachine_cloneCustomizeWithNewResourcePool(t *testing.T) {
 	testAccSkipUnstable(t)
 	resource.Test(t, resource.TestCase{
@@ -3033,6 +3275,19 @@ func testAccResourceVSphereVirtualMachineCheckEagerlyScrub(diskIndex int, eagerl
 
 // testAccResourceVSphereVirtualMachineCheckPowerState is a check to check for
 // a VirtualMachine's power state.
+func testAccResourceVSphereVirtualMachineCheckPowerState(expected types.VirtualMachinePowerState) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		props, err := testGetVirtualMachineProperties(s, "vm")
+		if err != nil {
+			return err
+		}
+		actual := props.Runtime.PowerState
+		if expected != actual {
+			return fmt.Errorf("expected power state to be %s, got %s", expected, actual)
+		}
+		return nil
+	}
+}
 
 // testAccResourceVSphereVirtualMachineCheckHostname is a check to check for a
 // VirtualMachine's hostname. The check uses guest info, so VMware Tools needs
@@ -3459,7 +3714,7 @@ func testAccResourceVSphereVirtualMachineDeletePersistentDisks(disks *[]map[stri
 		var vmFolder string
 		var dsID string
 		for _, disk := range *disks {
-			ds, err := datastore.FromID(client, disk["datastore_id"])
+			ds, err := datastore.FromID(context.Background(), client, disk["datastore_id"])
 			if err != nil {
 				return err
 			}
@@ -3493,7 +3748,7 @@ func testAccResourceVSphereVirtualMachineCheckPowerOffEvent(expected bool) resou
 			return err
 		}
 		client := testAccProvider.Meta().(*Client).vimClient
-		actual, err := selectEventsForReference(client, vm.Reference(), []string{eventTypeVMPoweredOffEvent})
+		actual, err := selectEventsForReference(context.Background(), client, vm.Reference(), []string{eventTypeVMPoweredOffEvent})
 		if err != nil {
 			return err
 		}
@@ -3589,15 +3844,15 @@ func testAccResourceVSphereVirtualMachineCheckResourcePool(expected string) reso
 		actual := pool.Name()
 		if actual == "Resources" && path.Base(expected) == "Resources" {
 			client := testAccProvider.Meta().(*Client).vimClient
-			expectedCluster, err := computeresource.BaseFromPath(client, path.Dir(expected))
+			expectedCluster, err := computeresource.BaseFromPath(context.Background(), client, path.Dir(expected))
 			if err != nil {
 				return err
 			}
-			pprops, err := resourcepool.Properties(pool)
+			pprops, err := resourcepool.Properties(context.Background(), pool)
 			if err != nil {
 				return err
 			}
-			actualCluster, err := computeresource.BaseFromReference(client, *pprops.Parent)
+			actualCluster, err := computeresource.BaseFromReference(context.Background(), client, *pprops.Parent)
 			if err != nil {
 				return err
 			}
@@ -3788,16 +4043,16 @@ func testCheckVMDiskFileExists(name string) resource.TestCheckFunc {
 		if err != nil {
 			return err
 		}
-		dc, err := getDatacenter(tVars.client, dcp)
+		dc, err := getDatacenter(context.Background(), tVars.client, dcp)
 		if err != nil {
 			return err
 		}
-		ds, err := datastore.FromPath(tVars.client, vmxPath.Datastore, dc)
+		ds, err := datastore.FromPath(context.Background(), tVars.client, vmxPath.Datastore, dc)
 		if err != nil {
 			return err
 		}
 		p := path.Join(path.Dir(vmxPath.Path), name)
-		exists, err := datastore.FileExists(ds, p)
+		exists, err := datastore.FileExists(context.Background(), ds, p)
 		if err != nil {
 			return err
 		}
@@ -5712,25 +5967,12 @@ resource "vsphere_virtual_machine" "vm" {
 	)
 }
 
-func testAccResourceVSphereVirtualMachineConfigExistingVmdk() string {
+func testAccResourceVSphereVirtualMachineConfigWaitForGuestInfo(regex string) string {
 	return fmt.Sprintf(`
 
 
 %s  // Mix and match config
 
-variable "extra_vmdk_name" {
-  default = "%s"
-}
-
-resource "vsphere_virtual_disk" "disk" {
-  size         = 1
-  vmdk_path    = var.extra_vmdk_name
-  datacenter   = data.vsphere_datacenter.rootdc1.name
-  datastore    = data.vsphere_datastore.rootds1.name
-  type         = "thin"
-  adapter_type = "lsiLogic"
-}
-
 resource "vsphere_virtual_machine" "vm" {
   name             = "testacc-test"
   resource_pool_id = vsphere_resource_pool.pool1.id
@@ -5739,54 +5981,45 @@ resource "vsphere_virtual_machine" "vm" {
   num_cpus = 2
   memory   = 2048
   guest_id = "other3xLinuxGuest"
-  firmware = "efi"
 
   wait_for_guest_net_timeout = 0
 
-  network_interface {
-    network_id = data.vsphere_network.network1.id
+  extra_config = {
+    "guestinfo.bootstrap" = "done"
   }
 
-  disk {
-    label          = "disk0"
-    size           = 1
-    io_reservation = 1
+  wait_for_guest {
+    timeout       = 1
+    guestinfo_key = "bootstrap"
+    regex         = "%s"
+  }
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
   }
 
   disk {
-    label          = "disk1"
-    datastore_id   = data.vsphere_datastore.rootds1.id
-    path           = vsphere_virtual_disk.disk.vmdk_path
-    disk_mode      = "independent_persistent"
-    attach         = true
-    unit_number    = 1
-    io_reservation = 1
+    label = "disk0"
+    size  = 1
   }
 }
 `,
 
 		testAccResourceVSphereVirtualMachineConfigBase(),
-		testAccResourceVSphereVirtualMachineDiskNameExtraVmdk,
+		regex,
 	)
 }
 
-func testAccResourceVSphereVirtualMachineConfigInFolder() string {
+func testAccResourceVSphereVirtualMachineConfigSerialPort(uri string) string {
 	return fmt.Sprintf(`
 
 
 %s  // Mix and match config
 
-resource "vsphere_folder" "folder" {
-  path          = "terraform-test-vms"
-  type          = "vm"
-  datacenter_id = data.vsphere_datacenter.rootdc1.id
-}
-
 resource "vsphere_virtual_machine" "vm" {
   name             = "testacc-test"
   resource_pool_id = vsphere_resource_pool.pool1.id
   datastore_id     = data.vsphere_datastore.rootds1.id
-  folder           = vsphere_folder.folder.path
 
   num_cpus = 2
   memory   = 2048
@@ -5795,32 +6028,435 @@ resource "vsphere_virtual_machine" "vm" {
 
   wait_for_guest_net_timeout = 0
 
+  serial_port {
+    service_uri = "%s"
+    direction   = "server"
+  }
+
   network_interface {
     network_id = data.vsphere_network.network1.id
   }
 
   disk {
-    label = "disk0"
-    size  = 1
+    label          = "disk0"
+    size           = 1
     io_reservation = 1
   }
 }
 `,
 
 		testAccResourceVSphereVirtualMachineConfigBase(),
+		uri,
 	)
 }
 
-func testAccResourceVSphereVirtualMachineConfigInVAppContainer() string {
+func testAccResourceVSphereVirtualMachineConfigUsbController(autoConnect bool) string {
 	return fmt.Sprintf(`
 
 
 %s  // Mix and match config
 
-resource "vsphere_folder" "folder" {
-  path          = "terraform-test-vms"
-  type          = "vm"
-  datacenter_id = data.vsphere_datacenter.rootdc1.id
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+  firmware = "efi"
+
+  wait_for_guest_net_timeout = 0
+
+  usb_controller {
+    type                 = "xhci"
+    auto_connect_devices = %t
+  }
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label          = "disk0"
+    size           = 1
+    io_reservation = 1
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		autoConnect,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigUsbDeviceNoController() string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+  firmware = "efi"
+
+  wait_for_guest_net_timeout = 0
+
+  usb_device {
+    device_name = "path:1/0/1 version:2"
+  }
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label          = "disk0"
+    size           = 1
+    io_reservation = 1
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigDynamicPciDevice(vendorID, deviceID string) string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus                         = 2
+  memory                           = 2048
+  memory_reservation_locked_to_max = true
+  guest_id                         = "other3xLinuxGuest"
+  firmware                         = "efi"
+
+  wait_for_guest_net_timeout = 0
+
+  dynamic_pci_device {
+    vendor_id = "%s"
+    device_id = "%s"
+  }
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label          = "disk0"
+    size           = 1
+    io_reservation = 1
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		vendorID,
+		deviceID,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigRdmDisk(lun string) string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label          = "disk0"
+    size           = 1
+    io_reservation = 1
+  }
+
+  disk {
+    label       = "disk1"
+    unit_number = 1
+    rdm_lun     = "%s"
+    disk_mode   = "independent_persistent"
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		lun,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigFirstClassDisk() string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_first_class_disk" "disk" {
+  name         = "testacc-fcd"
+  datastore_id = data.vsphere_datastore.rootds1.id
+  size         = 1
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label          = "disk0"
+    size           = 1
+    io_reservation = 1
+  }
+
+  disk {
+    label               = "disk1"
+    unit_number         = 1
+    attach              = true
+    datastore_id        = vsphere_first_class_disk.disk.datastore_id
+    first_class_disk_id = vsphere_first_class_disk.disk.id
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigEncryption(encryptDisks bool) string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_key_provider" "provider" {
+  name = "testacc-nkp"
+  type = "native"
+}
+
+data "vsphere_storage_policy" "encryption" {
+  name = "VM Encryption Policy"
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name              = "testacc-test"
+  resource_pool_id  = vsphere_resource_pool.pool1.id
+  datastore_id      = data.vsphere_datastore.rootds1.id
+  storage_policy_id = data.vsphere_storage_policy.encryption.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label = "disk0"
+    size  = 1
+  }
+
+  encryption {
+    key_provider_id    = vsphere_key_provider.provider.id
+    encrypt_disks      = %t
+    migrate_encryption = "required"
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		encryptDisks,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigCloudInit(hostname string) string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+  firmware = "efi"
+
+  wait_for_guest_net_timeout = 0
+
+  cloud_init {
+    metadata         = "local-hostname: %s\n"
+    encoding         = "base64"
+    reboot_on_change = false
+  }
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label          = "disk0"
+    size           = 1
+    io_reservation = 1
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		hostname,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigExistingVmdk() string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+variable "extra_vmdk_name" {
+  default = "%s"
+}
+
+resource "vsphere_virtual_disk" "disk" {
+  size         = 1
+  vmdk_path    = var.extra_vmdk_name
+  datacenter   = data.vsphere_datacenter.rootdc1.name
+  datastore    = data.vsphere_datastore.rootds1.name
+  type         = "thin"
+  adapter_type = "lsiLogic"
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+  firmware = "efi"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label          = "disk0"
+    size           = 1
+    io_reservation = 1
+  }
+
+  disk {
+    label          = "disk1"
+    datastore_id   = data.vsphere_datastore.rootds1.id
+    path           = vsphere_virtual_disk.disk.vmdk_path
+    disk_mode      = "independent_persistent"
+    attach         = true
+    unit_number    = 1
+    io_reservation = 1
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		testAccResourceVSphereVirtualMachineDiskNameExtraVmdk,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigInFolder() string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_folder" "folder" {
+  path          = "terraform-test-vms"
+  type          = "vm"
+  datacenter_id = data.vsphere_datacenter.rootdc1.id
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "testacc-test"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+  folder           = vsphere_folder.folder.path
+
+  num_cpus = 2
+  memory   = 2048
+  guest_id = "other3xLinuxGuest"
+  firmware = "efi"
+
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id = data.vsphere_network.network1.id
+  }
+
+  disk {
+    label = "disk0"
+    size  = 1
+    io_reservation = 1
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigInVAppContainer() string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+resource "vsphere_folder" "folder" {
+  path          = "terraform-test-vms"
+  type          = "vm"
+  datacenter_id = data.vsphere_datacenter.rootdc1.id
 }
 
 resource "vsphere_vapp_container" "vapp-container" {
@@ -6544,6 +7180,189 @@ resource "vsphere_virtual_machine" "vm" {
 	)
 }
 
+func testAccResourceVSphereVirtualMachineConfigCloneInstant() string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+data "vsphere_virtual_machine" "template" {
+  name          = "%s"
+  datacenter_id = data.vsphere_datacenter.rootdc1.id
+}
+
+resource "vsphere_virtual_machine" "vm_source" {
+  name             = "terraform-test1"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus                   = 2
+  memory                     = 2048
+  guest_id                   = data.vsphere_virtual_machine.template.guest_id
+  wait_for_guest_net_timeout = -1
+
+  network_interface {
+    network_id   = data.vsphere_network.network1.id
+    adapter_type = data.vsphere_virtual_machine.template.network_interface_types[0]
+  }
+
+  disk {
+    label            = "disk0"
+    size             = data.vsphere_virtual_machine.template.disks.0.size
+    eagerly_scrub    = data.vsphere_virtual_machine.template.disks.0.eagerly_scrub
+    thin_provisioned = data.vsphere_virtual_machine.template.disks.0.thin_provisioned
+  }
+
+  clone {
+    template_uuid = data.vsphere_virtual_machine.template.id
+  }
+
+  cdrom {
+    client_device = true
+  }
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "terraform-test2"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+  annotation       = "%s"
+
+  num_cpus                   = 2
+  memory                     = 2048
+  guest_id                   = data.vsphere_virtual_machine.template.guest_id
+  wait_for_guest_net_timeout = -1
+
+  extra_config = {
+    foo = "bar"
+  }
+
+  network_interface {
+    network_id   = data.vsphere_network.network1.id
+    adapter_type = data.vsphere_virtual_machine.template.network_interface_types[0]
+  }
+
+  disk {
+    label            = "disk0"
+    size             = data.vsphere_virtual_machine.template.disks.0.size
+    eagerly_scrub    = data.vsphere_virtual_machine.template.disks.0.eagerly_scrub
+    thin_provisioned = data.vsphere_virtual_machine.template.disks.0.thin_provisioned
+  }
+
+  clone {
+    template_uuid = vsphere_virtual_machine.vm_source.id
+    instant_clone = true
+
+    instant_clone_guestinfo = {
+      hostname = "terraform-test2"
+    }
+  }
+
+  cdrom {
+    client_device = true
+  }
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		os.Getenv("TF_VAR_VSPHERE_TEMPLATE"),
+		testAccResourceVSphereVirtualMachineAnnotation,
+	)
+}
+
+func testAccResourceVSphereVirtualMachineConfigCloneLinkedFromSnapshotName() string {
+	return fmt.Sprintf(`
+
+
+%s  // Mix and match config
+
+data "vsphere_virtual_machine" "template" {
+  name          = "%s"
+  datacenter_id = data.vsphere_datacenter.rootdc1.id
+}
+
+resource "vsphere_virtual_machine" "vm_source" {
+  name             = "terraform-test1"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus                   = 2
+  memory                     = 2048
+  guest_id                   = data.vsphere_virtual_machine.template.guest_id
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id   = data.vsphere_network.network1.id
+    adapter_type = data.vsphere_virtual_machine.template.network_interface_types[0]
+  }
+
+  disk {
+    label            = "disk0"
+    size             = data.vsphere_virtual_machine.template.disks.0.size
+    eagerly_scrub    = data.vsphere_virtual_machine.template.disks.0.eagerly_scrub
+    thin_provisioned = data.vsphere_virtual_machine.template.disks.0.thin_provisioned
+  }
+
+  clone {
+    template_uuid = data.vsphere_virtual_machine.template.id
+  }
+}
+
+resource "vsphere_virtual_machine_snapshot" "first" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm_source.uuid
+  snapshot_name        = "terraform-test-first"
+  description          = "Managed by Terraform"
+  memory               = false
+  quiesce              = false
+}
+
+resource "vsphere_virtual_machine_snapshot" "second" {
+  virtual_machine_uuid = vsphere_virtual_machine.vm_source.uuid
+  snapshot_name        = "terraform-test-second"
+  description          = "Managed by Terraform"
+  memory               = false
+  quiesce              = false
+
+  depends_on = [vsphere_virtual_machine_snapshot.first]
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name             = "terraform-test2"
+  resource_pool_id = vsphere_resource_pool.pool1.id
+  datastore_id     = data.vsphere_datastore.rootds1.id
+
+  num_cpus                   = 2
+  memory                     = 2048
+  guest_id                   = data.vsphere_virtual_machine.template.guest_id
+  wait_for_guest_net_timeout = 0
+
+  network_interface {
+    network_id   = data.vsphere_network.network1.id
+    adapter_type = data.vsphere_virtual_machine.template.network_interface_types[0]
+  }
+
+  disk {
+    label            = "disk0"
+    size             = data.vsphere_virtual_machine.template.disks.0.size
+    eagerly_scrub    = data.vsphere_virtual_machine.template.disks.0.eagerly_scrub
+    thin_provisioned = data.vsphere_virtual_machine.template.disks.0.thin_provisioned
+  }
+
+  clone {
+    template_uuid = vsphere_virtual_machine.vm_source.id
+    linked_clone  = true
+    snapshot_name = vsphere_virtual_machine_snapshot.first.snapshot_name
+  }
+
+  depends_on = [vsphere_virtual_machine_snapshot.second]
+}
+`,
+
+		testAccResourceVSphereVirtualMachineConfigBase(),
+		os.Getenv("TF_VAR_VSPHERE_TEMPLATE"),
+	)
+}
+
 func testAccResourceVSphereVirtualMachineConfigBadSizeLinked() string {
 	return fmt.Sprintf(`
 
@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/pbm"
 	types2 "github.com/vmware/govmomi/pbm/types"
@@ -41,22 +43,28 @@ func resourceVMStoragePolicy() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourceVMStoragePolicyCreate,
-		Read:   resourceVMStoragePolicyRead,
-		Update: resourceVMStoragePolicyUpdate,
-		Delete: resourceVMStoragePolicyDelete,
+		CreateContext: resourceVMStoragePolicyCreate,
+		ReadContext:   resourceVMStoragePolicyRead,
+		UpdateContext: resourceVMStoragePolicyUpdate,
+		DeleteContext: resourceVMStoragePolicyDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Schema: sch,
 	}
 }
 
-func resourceVMStoragePolicyCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVMStoragePolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning create storage policy profile %s", d.Get("name").(string))
 	client := meta.(*Client).vimClient
 	rc := meta.(*Client).restClient
 
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.Errorf("error while creating pbm client %s", err)
 	}
 
 	tagsManager := tags.NewManager(rc)
@@ -66,9 +74,9 @@ func resourceVMStoragePolicyCreate(d *schema.ResourceData, meta interface{}) err
 
 	for _, tagRule := range tagRules {
 		tagCategory := tagRule.(map[string]interface{})["tag_category"].(string)
-		_, err := tagCategoryByName(tagsManager, tagCategory)
+		_, err := tagCategoryByName(ctx, tagsManager, tagCategory)
 		if err != nil {
-			return fmt.Errorf("error while getting the tag %s %s", tagCategory, err)
+			return diag.Errorf("error while getting the tag %s %s", tagCategory, err)
 		}
 
 		tagValues := tagRule.(map[string]interface{})["tags"].([]interface{})
@@ -109,40 +117,40 @@ func resourceVMStoragePolicyCreate(d *schema.ResourceData, meta interface{}) err
 	}
 	pbmCapabilityProfileCreateSpec, err := pbm.CreateCapabilityProfileSpec(capabilityProfileCreateSpec)
 	if err != nil {
-		return fmt.Errorf("error while creating storage policy spec %s", err)
+		return diag.Errorf("error while creating storage policy spec %s", err)
 	}
 
-	profileID, err := pbmClient.CreateProfile(context.Background(), *pbmCapabilityProfileCreateSpec)
+	profileID, err := pbmClient.CreateProfile(ctx, *pbmCapabilityProfileCreateSpec)
 	if err != nil {
-		return fmt.Errorf("error while creating storage policy %s", err)
+		return diag.Errorf("error while creating storage policy %s", err)
 	}
 
 	d.SetId(profileID.UniqueId)
 	log.Printf("[DEBUG] Storage policy create complete with Id %s", profileID.UniqueId)
 
-	return resourceVMStoragePolicyRead(d, meta)
+	return resourceVMStoragePolicyRead(ctx, d, meta)
 }
 
-func resourceVMStoragePolicyRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVMStoragePolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Reading vm storage policy profile", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.Errorf("error while creating pbm client %s", err)
 	}
 	profileID := types2.PbmProfileId{
 		UniqueId: d.Id(),
 	}
 	pbmProfileIDs := []types2.PbmProfileId{profileID}
 
-	vmStoragePolicies, err := pbmClient.RetrieveContent(context.Background(), pbmProfileIDs)
+	vmStoragePolicies, err := pbmClient.RetrieveContent(ctx, pbmProfileIDs)
 	if err != nil {
 		if strings.Contains(err.Error(), "Profile not found") {
 			log.Printf("[DEBUG] storage policy profile %s: Resource has been deleted", d.Id())
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("error while reading vm storage policy profile with Id %s %s", d.Id(), err.Error())
+		return diag.Errorf("error while reading vm storage policy profile with Id %s %s", d.Id(), err.Error())
 	}
 	if len(vmStoragePolicies) == 0 {
 		d.SetId("")
@@ -199,13 +207,13 @@ func resourceVMStoragePolicyRead(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVMStoragePolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Print("[DEBUG] :  Performing update")
 	client := meta.(*Client).vimClient
 	rc := meta.(*Client).restClient
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.Errorf("error while creating pbm client %s", err)
 	}
 
 	updateSpec := types2.PbmCapabilityProfileUpdateSpec{}
@@ -221,9 +229,9 @@ func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) err
 
 		for _, tagRule := range tagRules {
 			tagCategory := tagRule.(map[string]interface{})["tag_category"].(string)
-			_, err := tagCategoryByName(tagsManager, tagCategory)
+			_, err := tagCategoryByName(ctx, tagsManager, tagCategory)
 			if err != nil {
-				return fmt.Errorf("error while getting the tag %s %s", tagCategory, err)
+				return diag.Errorf("error while getting the tag %s %s", tagCategory, err)
 			}
 
 			tagValues := tagRule.(map[string]interface{})["tags"].([]interface{})
@@ -259,7 +267,7 @@ func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 		pbmCapabilityProfileCreateSpec, err := pbm.CreateCapabilityProfileSpec(capabilityProfileCreateSpec)
 		if err != nil {
-			return fmt.Errorf("error while creating profile updatespec %s", err)
+			return diag.Errorf("error while creating profile updatespec %s", err)
 		}
 		updateSpec.Constraints = pbmCapabilityProfileCreateSpec.Constraints
 	}
@@ -267,20 +275,20 @@ func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) err
 	policyIDToUpdate := types2.PbmProfileId{
 		UniqueId: d.Id(),
 	}
-	err = pbmClient.UpdateProfile(context.Background(), policyIDToUpdate, updateSpec)
+	err = pbmClient.UpdateProfile(ctx, policyIDToUpdate, updateSpec)
 	if err != nil {
-		return fmt.Errorf("error while performing profile update for Id %s %s", d.Id(), err)
+		return diag.Errorf("error while performing profile update for Id %s %s", d.Id(), err)
 	}
 	log.Print("[DEBUG] : update complete")
-	return resourceVMStoragePolicyRead(d, meta)
+	return resourceVMStoragePolicyRead(ctx, d, meta)
 }
 
-func resourceVMStoragePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVMStoragePolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Performing delete of VM storage policy with ID %s", d.Id())
 	client := meta.(*Client).vimClient
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.Errorf("error while creating pbm client %s", err)
 	}
 	var policyIDsToDelete []types2.PbmProfileId
 	policyIDsToDelete = append(policyIDsToDelete, types2.PbmProfileId{
@@ -288,12 +296,12 @@ func resourceVMStoragePolicyDelete(d *schema.ResourceData, meta interface{}) err
 	})
 
 	var deleteProfileOutcome []types2.PbmProfileOperationOutcome
-	deleteProfileOutcome, err = pbmClient.DeleteProfile(context.Background(), policyIDsToDelete)
+	deleteProfileOutcome, err = pbmClient.DeleteProfile(ctx, policyIDsToDelete)
 	if err != nil {
-		return fmt.Errorf("error while deleting policy with ID %s %s", d.Id(), err)
+		return diag.Errorf("error while deleting policy with ID %s %s", d.Id(), err)
 	}
 	if len(deleteProfileOutcome) > 0 && deleteProfileOutcome[0].Fault != nil {
-		return fmt.Errorf("error while deleting policy with ID %s %s",
+		return diag.Errorf("error while deleting policy with ID %s %s",
 			d.Id(), deleteProfileOutcome[0].Fault.LocalizedMessage)
 	}
 	d.SetId("")
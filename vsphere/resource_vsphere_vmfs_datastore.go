@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/vmware/govmomi/vim25/types"
@@ -86,37 +87,43 @@ func resourceVSphereVmfsDatastore() *schema.Resource {
 	s[customattribute.ConfigKey] = customattribute.ConfigSchema()
 
 	return &schema.Resource{
-		Create:        resourceVSphereVmfsDatastoreCreate,
-		Read:          resourceVSphereVmfsDatastoreRead,
-		Update:        resourceVSphereVmfsDatastoreUpdate,
-		Delete:        resourceVSphereVmfsDatastoreDelete,
+		CreateContext: resourceVSphereVmfsDatastoreCreate,
+		ReadContext:   resourceVSphereVmfsDatastoreRead,
+		UpdateContext: resourceVSphereVmfsDatastoreUpdate,
+		DeleteContext: resourceVSphereVmfsDatastoreDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		CustomizeDiff: resourceVSphereVmfsDatastoreCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVmfsDatastoreImport,
+			StateContext: resourceVSphereVmfsDatastoreImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereVmfsDatastoreCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hsID := d.Get("host_system_id").(string)
-	dss, err := hostDatastoreSystemFromHostSystemID(client, hsID)
+	dss, err := hostDatastoreSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host datastore system: %s", err)
+		return diag.Errorf("error loading host datastore system: %s", err)
 	}
 
 	// To ensure the datastore is fully created with all the disks that we want
@@ -124,43 +131,43 @@ func resourceVSphereVmfsDatastoreCreate(d *schema.ResourceData, meta interface{}
 	// the rest of the extents.
 	disks := d.Get("disks").([]interface{})
 	disk := disks[0].(string)
-	spec, err := diskSpecForCreate(dss, disk)
+	spec, err := diskSpecForCreate(ctx, dss, disk)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec.Vmfs.VolumeName = d.Get("name").(string)
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	ds, err := dss.CreateVmfsDatastore(ctx, *spec)
 	if err != nil {
-		return fmt.Errorf("error creating datastore with disk %s: %s", disk, err)
+		return diag.Errorf("error creating datastore with disk %s: %s", disk, err)
 	}
 
 	// Add any remaining disks.
 	for _, disk := range disks[1:] {
 		var extendSpec *types.VmfsDatastoreExtendSpec
-		extendSpec, err = diskSpecForExtend(dss, ds, disk.(string))
+		extendSpec, err = diskSpecForExtend(ctx, dss, ds, disk.(string))
 		if err != nil {
 			// We have to destroy the created datastore here.
-			if remErr := removeDatastore(dss, ds); remErr != nil {
+			if remErr := removeDatastore(ctx, dss, ds); remErr != nil {
 				// We could not destroy the created datastore and there is now a dangling
 				// resource. We need to instruct the user to remove the datastore
 				// manually.
-				return fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, err, remErr)
+				return diag.FromErr(fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, err, remErr))
 			}
-			return fmt.Errorf("error fetching datastore extend spec for disk %q: %s", disk, err)
+			return diag.Errorf("error fetching datastore extend spec for disk %q: %s", disk, err)
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 		_, extendErr := extendVmfsDatastore(ctx, dss, ds, *extendSpec)
 		cancel()
 		if extendErr != nil {
-			if remErr := removeDatastore(dss, ds); remErr != nil {
+			if remErr := removeDatastore(ctx, dss, ds); remErr != nil {
 				// We could not destroy the created datastore and there is now a dangling
 				// resource. We need to instruct the user to remove the datastore
 				// manually.
-				return fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, extendErr, remErr)
+				return diag.FromErr(fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, extendErr, remErr))
 			}
-			return fmt.Errorf("error extending datastore with disk %q: %s", disk, extendErr)
+			return diag.Errorf("error extending datastore with disk %q: %s", disk, extendErr)
 		}
 	}
 
@@ -169,52 +176,52 @@ func resourceVSphereVmfsDatastoreCreate(d *schema.ResourceData, meta interface{}
 	d.SetId(ds.Reference().Value)
 
 	// Move the datastore to the correct folder first, if specified.
-	f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
+	f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if !folder.PathIsEmpty(f) {
-		if err := datastore.MoveToFolderRelativeHostSystemID(client, ds, hsID, f); err != nil {
-			return fmt.Errorf("could not move datastore to folder %q: %s", f, err)
+		if err := datastore.MoveToFolderRelativeHostSystemID(ctx, client, ds, hsID, f); err != nil {
+			return diag.Errorf("could not move datastore to folder %q: %s", f, err)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Done
-	return resourceVSphereVmfsDatastoreRead(d, meta)
+	return resourceVSphereVmfsDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereVmfsDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	id := d.Id()
-	ds, err := datastore.FromID(client, id)
+	ds, err := datastore.FromID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return diag.Errorf("cannot find datastore: %s", err)
 	}
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ctx, ds)
 	if err != nil {
-		return fmt.Errorf("could not get properties for datastore: %s", err)
+		return diag.Errorf("could not get properties for datastore: %s", err)
 	}
 	if err := flattenDatastoreSummary(d, &props.Summary); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Set the folder
-	if err := resourceVSphereDatastoreReadFolderOrStorageClusterPath(d, ds); err != nil {
-		return err
+	if err := resourceVSphereDatastoreReadFolderOrStorageClusterPath(ctx, d, ds); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// We also need to update the disk list from the summary.
@@ -223,13 +230,13 @@ func resourceVSphereVmfsDatastoreRead(d *schema.ResourceData, meta interface{})
 		disks = append(disks, disk.DiskName)
 	}
 	if err := d.Set("disks", disks); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, ds, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, ds, d); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -241,62 +248,62 @@ func resourceVSphereVmfsDatastoreRead(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hsID := d.Get("host_system_id").(string)
-	dss, err := hostDatastoreSystemFromHostSystemID(client, hsID)
+	dss, err := hostDatastoreSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host datastore system: %s", err)
+		return diag.Errorf("error loading host datastore system: %s", err)
 	}
 
 	id := d.Id()
-	ds, err := datastore.FromID(client, id)
+	ds, err := datastore.FromID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return diag.Errorf("cannot find datastore: %s", err)
 	}
 
 	// Rename this datastore if our name has drifted.
 	if d.HasChange("name") {
-		if err := viapi.RenameObject(client, ds.Reference(), d.Get("name").(string)); err != nil {
-			return err
+		if err := viapi.RenameObject(ctx, client, ds.Reference(), d.Get("name").(string)); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Update folder or datastore cluster if necessary
 	if d.HasChange("folder") || d.HasChange("datastore_cluster_id") {
-		f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
+		f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(ctx, d, meta)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		if err := datastore.MoveToFolder(client, ds, f); err != nil {
-			return fmt.Errorf("could not move datastore to folder %q: %s", f, err)
+		if err := datastore.MoveToFolder(ctx, client, ds, f); err != nil {
+			return diag.Errorf("could not move datastore to folder %q: %s", f, err)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(ctx, tagsClient, d, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Apply custom attribute updates
 	if attrsProcessor != nil {
-		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+		if err := attrsProcessor.ProcessDiff(ctx, ds); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -311,7 +318,7 @@ func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}
 			}
 		}
 		if !found {
-			return fmt.Errorf("disk %s found in state but not config (removal of disks is not supported)", v1)
+			return diag.Errorf("disk %s found in state but not config (removal of disks is not supported)", v1)
 		}
 	}
 
@@ -326,35 +333,35 @@ func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}
 		}
 		if !found {
 			// Add the disk
-			spec, err := diskSpecForExtend(dss, ds, v1.(string))
+			spec, err := diskSpecForExtend(ctx, dss, ds, v1.(string))
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
-			ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+			ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 			_, extendErr := extendVmfsDatastore(ctx, dss, ds, *spec)
 			cancel()
 			if extendErr != nil {
-				return extendErr
+				return diag.FromErr(extendErr)
 			}
 		}
 	}
 
 	// Should be done with the update here.
-	return resourceVSphereVmfsDatastoreRead(d, meta)
+	return resourceVSphereVmfsDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID := d.Get("host_system_id").(string)
-	dss, err := hostDatastoreSystemFromHostSystemID(client, hsID)
+	dss, err := hostDatastoreSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host datastore system: %s", err)
+		return diag.Errorf("error loading host datastore system: %s", err)
 	}
 
 	id := d.Id()
-	ds, err := datastore.FromID(client, id)
+	ds, err := datastore.FromID(ctx, client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return diag.Errorf("cannot find datastore: %s", err)
 	}
 
 	// This is a race that more than likely will only come up during tests, but
@@ -366,7 +373,7 @@ func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}
 	// minimum delay to make an honest first attempt at trying to delete the
 	// datastore without spamming the task log with errors.
 	deleteRetryFunc := func() (interface{}, string, error) {
-		err := removeDatastore(dss, ds)
+		err := removeDatastore(ctx, dss, ds)
 		if err != nil {
 			if viapi.IsResourceInUseError(err) {
 				// Pending
@@ -388,16 +395,16 @@ func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}
 		Delay:      2 * time.Second,
 	}
 
-	_, err = deleteRetry.WaitForStateContext(context.Background())
+	_, err = deleteRetry.WaitForStateContext(ctx)
 	if err != nil {
-		return fmt.Errorf("could not delete datastore: %s", err)
+		return diag.Errorf("could not delete datastore: %s", err)
 	}
 
 	// We need to make sure the datastore is completely removed. There appears to
 	// be a bit of a delay sometimes on vCenter, and it causes issues in tests,
 	// which means it could cause issues somewhere else too.
 	waitForDeleteFunc := func() (interface{}, string, error) {
-		_, err := datastore.FromID(client, id)
+		_, err := datastore.FromID(ctx, client, id)
 		if err != nil {
 			if viapi.IsManagedObjectNotFoundError(err) {
 				// Done
@@ -421,7 +428,7 @@ func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}
 
 	_, err = waitForDelete.WaitForState()
 	if err != nil {
-		return fmt.Errorf("error waiting for datastore to delete: %s", err.Error())
+		return diag.Errorf("error waiting for datastore to delete: %s", err.Error())
 	}
 
 	return nil
@@ -442,7 +449,7 @@ func resourceVSphereVmfsDatastoreCustomizeDiff(_ context.Context, d *schema.Reso
 	return nil
 }
 
-func resourceVSphereVmfsDatastoreImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVmfsDatastoreImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// We support importing a MoRef - so we need to load the datastore and check
 	// to make sure 1) it exists, and 2) it's a VMFS datastore. If it is, we are
 	// good to go (rest of the stuff will be handled by read on refresh).
@@ -454,11 +461,11 @@ func resourceVSphereVmfsDatastoreImport(d *schema.ResourceData, meta interface{}
 	id := ids[0]
 	hsID := ids[1]
 	client := meta.(*Client).vimClient
-	ds, err := datastore.FromID(client, id)
+	ds, err := datastore.FromID(ctx, client, id)
 	if err != nil {
 		return nil, fmt.Errorf("cannot find datastore: %s", err)
 	}
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ctx, ds)
 	if err != nil {
 		return nil, fmt.Errorf("could not get properties for datastore: %s", err)
 	}
@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -443,7 +444,7 @@ func testAccResourceVSphereVmfsDatastoreHasName(expected string) resource.TestCh
 			return err
 		}
 
-		props, err := datastore.Properties(ds)
+		props, err := datastore.Properties(context.Background(), ds)
 		if err != nil {
 			return err
 		}
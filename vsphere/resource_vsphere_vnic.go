@@ -10,7 +10,9 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi"
@@ -37,12 +39,18 @@ var vnicServiceTypeAllowedValues = []string{
 
 func resourceVsphereNic() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVsphereNicCreate,
-		Read:   resourceVsphereNicRead,
-		Update: resourceVsphereNicUpdate,
-		Delete: resourceVsphereNicDelete,
+		CreateContext: resourceVsphereNicCreate,
+		ReadContext:   resourceVsphereNicRead,
+		UpdateContext: resourceVsphereNicUpdate,
+		DeleteContext: resourceVsphereNicDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereNicImport,
+			StateContext: resourceVSphereNicImport,
 		},
 		Schema: vNicSchema(),
 	}
@@ -60,9 +68,8 @@ func vNicSchema() map[string]*schema.Schema {
 	return base
 }
 
-func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereNicRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] starting resource_vnic")
-	ctx := context.TODO()
 	client := meta.(*Client).vimClient
 
 	hostID, nicID := splitHostIDNicID(d)
@@ -98,7 +105,7 @@ func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		err = d.Set("ipv4", []map[string]interface{}{ipv4dict})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -131,15 +138,15 @@ func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
 			}
 			err = d.Set("ipv6", []map[string]interface{}{ipv6dict})
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
 
 	// get enabled services
-	hostSystem, err := hostsystem.FromID(client, hostID)
+	hostSystem, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hostVnicMgr, err := hostSystem.ConfigManager().VirtualNicManager(ctx)
@@ -161,51 +168,51 @@ func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 	if err := d.Set("services", schema.NewSet(schema.HashString, structure.SliceStringsToInterfaces(services))); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceVsphereNicCreate(d *schema.ResourceData, meta interface{}) error {
-	nicID, err := createVNic(d, meta)
+func resourceVsphereNicCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	nicID, err := createVNic(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] Created NIC with ID: %s", nicID)
 	hostID := d.Get("host")
 	tfNicID := fmt.Sprintf("%s_%s", hostID, nicID)
 	d.SetId(tfNicID)
-	return resourceVsphereNicRead(d, meta)
+	return resourceVsphereNicRead(ctx, d, meta)
 }
 
-func resourceVsphereNicUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereNicUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	for _, k := range []string{
 		"portgroup", "distributed_switch_port", "distributed_port_group",
 		"mac", "mtu", "ipv4", "ipv6", "netstack", "services"} {
 		if d.HasChange(k) {
-			_, err := updateVNic(d, meta)
+			_, err := updateVNic(ctx, d, meta)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			break
 		}
 	}
-	return resourceVsphereNicRead(d, meta)
+	return resourceVsphereNicRead(ctx, d, meta)
 }
 
-func resourceVsphereNicDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereNicDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID, nicID := splitHostIDNicID(d)
 
-	err := removeVnic(client, hostID, nicID)
+	err := removeVnic(ctx, client, hostID, nicID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceVsphereNicRead(d, meta)
+	return resourceVsphereNicRead(ctx, d, meta)
 }
 
-func resourceVSphereNicImport(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereNicImport(ctx context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
 	hostID, _ := splitHostIDNicID(d)
 
 	err := d.Set("host", hostID)
@@ -331,7 +338,7 @@ func BaseVMKernelSchema() map[string]*schema.Schema {
 	return sch
 }
 
-func updateVNic(d *schema.ResourceData, meta interface{}) (string, error) {
+func updateVNic(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, error) {
 	err := precheckEnableServices(d)
 	if err != nil {
 		return "", err
@@ -339,14 +346,13 @@ func updateVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 
 	client := meta.(*Client).vimClient
 	hostID, nicID := splitHostIDNicID(d)
-	ctx := context.TODO()
 
 	nic, err := getNicSpecFromSchema(d)
 	if err != nil {
 		return "", err
 	}
 
-	hns, err := getHostNetworkSystem(client, hostID)
+	hns, err := getHostNetworkSystem(ctx, client, hostID)
 	if err != nil {
 		return "", err
 	}
@@ -356,7 +362,7 @@ func updateVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 		return "", err
 	}
 
-	err = updateVnicService(d, hostID, nicID, meta)
+	err = updateVnicService(ctx, d, hostID, nicID, meta)
 	if err != nil {
 		return "", err
 	}
@@ -364,14 +370,13 @@ func updateVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 	return nicID, nil
 }
 
-func updateVnicService(d *schema.ResourceData, hostID string, nicID string, meta interface{}) error {
+func updateVnicService(ctx context.Context, d *schema.ResourceData, hostID string, nicID string, meta interface{}) error {
 	serviceOld, serviceNew := d.GetChange("services")
 	deleteList := serviceOld.(*schema.Set).List()
 	addList := serviceNew.(*schema.Set).List()
 
 	client := meta.(*Client).vimClient
-	ctx := context.TODO()
-	hostSystem, err := hostsystem.FromID(client, hostID)
+	hostSystem, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return err
 	}
@@ -404,14 +409,13 @@ func precheckEnableServices(d *schema.ResourceData) error {
 	return nil
 }
 
-func createVNic(d *schema.ResourceData, meta interface{}) (string, error) {
+func createVNic(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, error) {
 	err := precheckEnableServices(d)
 	if err != nil {
 		return "", err
 	}
 
 	client := meta.(*Client).vimClient
-	ctx := context.TODO()
 
 	nic, err := getNicSpecFromSchema(d)
 	if err != nil {
@@ -419,7 +423,7 @@ func createVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 	}
 
 	hostID := d.Get("host").(string)
-	hns, err := getHostNetworkSystem(client, hostID)
+	hns, err := getHostNetworkSystem(ctx, client, hostID)
 	if err != nil {
 		return "", err
 	}
@@ -431,7 +435,7 @@ func createVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 	}
 	d.SetId(fmt.Sprintf("%s_%s", hostID, nicID))
 
-	err = updateVnicService(d, hostID, nicID, meta)
+	err = updateVnicService(ctx, d, hostID, nicID, meta)
 	if err != nil {
 		return "", err
 	}
@@ -439,19 +443,18 @@ func createVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 	return nicID, nil
 }
 
-func removeVnic(client *govmomi.Client, hostID, nicID string) error {
-	hns, err := getHostNetworkSystem(client, hostID)
+func removeVnic(ctx context.Context, client *govmomi.Client, hostID, nicID string) error {
+	hns, err := getHostNetworkSystem(ctx, client, hostID)
 	if err != nil {
 		return err
 	}
 
-	return hns.RemoveVirtualNic(context.TODO(), nicID)
+	return hns.RemoveVirtualNic(ctx, nicID)
 }
 
-func getHostNetworkSystem(client *govmomi.Client, hostID string) (*object.HostNetworkSystem, error) {
-	ctx := context.TODO()
+func getHostNetworkSystem(ctx context.Context, client *govmomi.Client, hostID string) (*object.HostNetworkSystem, error) {
 
-	host, err := hostsystem.FromID(client, hostID)
+	host, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return nil, err
 	}
@@ -608,7 +611,7 @@ func getNicSpecFromSchema(d *schema.ResourceData) (*types.HostVirtualNicSpec, er
 }
 
 func getVnicFromHost(ctx context.Context, client *govmomi.Client, hostID, nicID string) (*types.HostVirtualNic, error) {
-	host, err := hostsystem.FromID(client, hostID)
+	host, err := hostsystem.FromID(ctx, client, hostID)
 	if err != nil {
 		return nil, err
 	}
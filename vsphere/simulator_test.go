@@ -0,0 +1,495 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/cassette"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/folder"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/sessioncache"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
+)
+
+// simulatorTestMain wraps the test runner so that the whole test binary runs
+// against a single in-process vcsim instance when offline mode is enabled.
+type simulatorTestMain struct {
+	m *testing.M
+}
+
+func (s *simulatorTestMain) Run() int {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		log.Printf("[ERROR] Could not start vSphere simulator: %s", err)
+		return 1
+	}
+	defer sim.Close()
+	if err := sim.SetEnv(); err != nil {
+		log.Printf("[ERROR] Could not configure vSphere simulator environment: %s", err)
+		return 1
+	}
+	return s.m.Run()
+}
+
+func testAccSimulatorPreCheck(t *testing.T) {
+	if !testhelper.SimulatorEnabled() {
+		t.Skipf("set %s to run tests against the vSphere simulator", testhelper.SimulatorEnvVar)
+	}
+	testAccPreCheck(t)
+}
+
+// testSimulatorConfig returns a provider configuration for the supplied
+// simulator.
+func testSimulatorConfig(sim *testhelper.Simulator) *Config {
+	password, _ := sim.Server.URL.User.Password()
+	return &Config{
+		InsecureFlag:  true,
+		User:          sim.Server.URL.User.Username(),
+		Password:      password,
+		VSphereServer: sim.Server.URL.Host,
+		KeepAlive:     10,
+		APITimeout:    defaultAPITimeout,
+	}
+}
+
+func testSimulatorCheckClient(t *testing.T, client *Client) {
+	if client.restClient == nil {
+		t.Fatal("expected REST client to be configured")
+	}
+	if client.pbmClient == nil {
+		t.Fatal("expected PBM client to be configured")
+	}
+	if client.vsanClient == nil {
+		t.Fatal("expected vSAN client to be configured")
+	}
+	if _, err := client.TagsManager(); err != nil {
+		t.Fatalf("expected tags manager to be available: %s", err)
+	}
+}
+
+func TestSimulatorClient(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer sim.Close()
+
+	client, err := testSimulatorConfig(sim).Client()
+	if err != nil {
+		t.Fatalf("error connecting to simulator: %s", err)
+	}
+	testSimulatorCheckClient(t, client)
+}
+
+func TestSimulatorClient_cassette(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	dir := t.TempDir()
+
+	c := testSimulatorConfig(sim)
+	c.CassettePath = dir
+	c.CassetteMode = string(cassette.ModeRecord)
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("error recording client session: %s", err)
+	}
+	testSimulatorCheckClient(t, client)
+	dcs, err := testSimulatorListDatacenters(client)
+	if err != nil {
+		t.Fatalf("error listing datacenters: %s", err)
+	}
+	_ = c.recorder.Close()
+
+	// Replay must not need the simulator.
+	sim.Close()
+
+	c = testSimulatorConfig(sim)
+	c.CassettePath = dir
+	c.CassetteMode = string(cassette.ModeReplay)
+	client, err = c.Client()
+	if err != nil {
+		t.Fatalf("error replaying client session: %s", err)
+	}
+	testSimulatorCheckClient(t, client)
+	replayed, err := testSimulatorListDatacenters(client)
+	if err != nil {
+		t.Fatalf("error listing datacenters from cassette: %s", err)
+	}
+	if !reflect.DeepEqual(dcs, replayed) {
+		t.Fatalf("expected %v, got %v", dcs, replayed)
+	}
+}
+
+func TestSimulatorClient_ssoToken(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer sim.Close()
+
+	certPEM, keyPEM := testSimulatorSolutionUserCertificate(t)
+	token := `<saml2:Assertion xmlns:saml2="urn:oasis:names:tc:SAML:2.0:assertion"><saml2:Subject><saml2:NameID>ci@vsphere.local</saml2:NameID></saml2:Subject></saml2:Assertion>`
+
+	cases := []struct {
+		name      string
+		configure func(c *Config)
+	}{
+		{"holder-of-key token for a solution user", func(c *Config) {
+			c.SSOCertificate = certPEM
+			c.SSOPrivateKey = keyPEM
+		}},
+		{"external token", func(c *Config) {
+			c.SSOToken = token
+		}},
+		{"external base64 encoded token", func(c *Config) {
+			c.SSOToken = base64.StdEncoding.EncodeToString([]byte(token))
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := testSimulatorConfig(sim)
+			c.User = ""
+			c.Password = ""
+			tc.configure(c)
+			if err := c.validateAuth(); err != nil {
+				t.Fatalf("error validating configuration: %s", err)
+			}
+			client, err := c.Client()
+			if err != nil {
+				t.Fatalf("error connecting to simulator: %s", err)
+			}
+			testSimulatorCheckClient(t, client)
+		})
+	}
+}
+
+func TestSimulatorClient_tls(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer sim.Close()
+
+	cert := sim.Server.Certificate()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+
+	cases := []struct {
+		name      string
+		configure func(c *Config)
+		ok        bool
+	}{
+		{"unverified certificate", func(_ *Config) {}, false},
+		{"ca_pem", func(c *Config) {
+			c.CAPEM = caPEM
+		}, true},
+		{"vcenter_thumbprint", func(c *Config) {
+			c.Thumbprint = soap.ThumbprintSHA256(cert)
+		}, true},
+		{"wrong vcenter_thumbprint with a trusted certificate", func(c *Config) {
+			c.CAPEM = caPEM
+			c.Thumbprint = "AA:BB:CC"
+		}, false},
+		{"wrong host_thumbprints with allow_unverified_ssl", func(c *Config) {
+			c.InsecureFlag = true
+			c.HostThumbprints = map[string]string{sim.Server.URL.Hostname(): "AA:BB:CC"}
+		}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := testSimulatorConfig(sim)
+			c.InsecureFlag = false
+			tc.configure(c)
+			client, err := c.Client()
+			if !tc.ok {
+				if err == nil {
+					t.Fatal("expected connection to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error connecting to simulator: %s", err)
+			}
+			testSimulatorCheckClient(t, client)
+		})
+	}
+}
+
+func TestSimulatorClient_proxy(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer sim.Close()
+
+	p := testhelper.StartProxy(sim.Server.URL.Host)
+	defer p.Close()
+
+	// The host name does not resolve, and is only reachable through the
+	// proxy.
+	server := net.JoinHostPort("vcenter.example.com", sim.Server.URL.Port())
+	c := testSimulatorConfig(sim)
+	c.VSphereServer = server
+	c.InsecureFlag = false
+	c.Thumbprint = soap.ThumbprintSHA256(sim.Server.Certificate())
+	c.ProxyURL = p.URL.String()
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("error connecting to simulator through proxy: %s", err)
+	}
+	testSimulatorCheckClient(t, client)
+	if len(p.Requests()) == 0 || p.Requests()[0] != server {
+		t.Fatalf("expected connections to %s through the proxy, got %v", server, p.Requests())
+	}
+
+	c.NoProxy = "example.com"
+	if _, err := c.Client(); err == nil {
+		t.Fatal("expected direct connection to fail")
+	}
+}
+
+func TestSimulatorClient_relogin(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer sim.Close()
+
+	client, err := testSimulatorConfig(sim).Client()
+	if err != nil {
+		t.Fatalf("error connecting to simulator: %s", err)
+	}
+	ctx := context.Background()
+
+	// Expired sessions are renewed with the provider credentials.
+	if err := client.vimClient.SessionManager.Logout(ctx); err != nil {
+		t.Fatalf("error logging out of the SOAP session: %s", err)
+	}
+	if _, err := methods.GetCurrentTime(ctx, client.vimClient); err != nil {
+		t.Fatalf("expected SOAP session to be renewed: %s", err)
+	}
+	if err := client.restClient.Logout(ctx); err != nil {
+		t.Fatalf("error logging out of the REST session: %s", err)
+	}
+	tm, err := client.TagsManager()
+	if err != nil {
+		t.Fatalf("error getting tags manager: %s", err)
+	}
+	if _, err := tm.GetCategories(ctx); err != nil {
+		t.Fatalf("expected REST session to be renewed: %s", err)
+	}
+}
+
+func TestSimulatorClient_persistSession(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer sim.Close()
+
+	dir := t.TempDir()
+	newConfig := func(key []byte) *Config {
+		c := testSimulatorConfig(sim)
+		c.Persist = true
+		c.VimSessionPath = filepath.Join(dir, "sessions")
+		c.RestSessionPath = filepath.Join(dir, "rest_sessions")
+		c.SessionEncryptionKey = base64.StdEncoding.EncodeToString(key)
+		return c
+	}
+	key := bytes.Repeat([]byte{1}, sessioncache.KeySize)
+
+	c := newConfig(key)
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("error connecting to simulator: %s", err)
+	}
+	vimFile, _ := c.vimSessionFile()
+	restFile, _ := c.restSessionFile()
+	for _, p := range []string{vimFile, restFile} {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("error reading session file: %s", err)
+		}
+		if bytes.Contains(b, []byte("SessionID")) || bytes.Contains(b, []byte("vmware_soap_session")) {
+			t.Fatalf("expected session file %q to be encrypted", p)
+		}
+	}
+
+	// The saved sessions are reused.
+	again, err := newConfig(key).Client()
+	if err != nil {
+		t.Fatalf("error connecting to simulator with saved sessions: %s", err)
+	}
+	if again.vimClient.Client.Client.SessionCookie() == nil ||
+		again.vimClient.Client.Client.SessionCookie().Value != client.vimClient.Client.Client.SessionCookie().Value {
+		t.Fatal("expected saved SOAP session to be reused")
+	}
+	if again.restClient.SessionID() != client.restClient.SessionID() {
+		t.Fatal("expected saved REST session to be reused")
+	}
+
+	// Sessions saved with another key are replaced.
+	other, err := newConfig(bytes.Repeat([]byte{2}, sessioncache.KeySize)).Client()
+	if err != nil {
+		t.Fatalf("error connecting to simulator with another key: %s", err)
+	}
+	if other.restClient.SessionID() == client.restClient.SessionID() {
+		t.Fatal("expected new REST session with another key")
+	}
+	testSimulatorCheckClient(t, other)
+}
+
+// testSimulatorSolutionUserCertificate returns a PEM encoded self-signed
+// certificate and private key.
+func testSimulatorSolutionUserCertificate(t *testing.T) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %s", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating certificate: %s", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(certPEM), string(keyPEM)
+}
+
+func testSimulatorListDatacenters(client *Client) ([]string, error) {
+	finder := find.NewFinder(client.vimClient.Client, false)
+	dcs, err := finder.DatacenterList(context.Background(), "*")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, dc := range dcs {
+		names = append(names, dc.Name())
+	}
+	return names, nil
+}
+
+func TestAccSimulator_folder(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccSimulatorPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereFolderExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereFolderConfigBasic(
+					testAccResourceVSphereFolderConfigExpectedName,
+					folder.VSphereFolderTypeVM,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFolderExists(true),
+					testAccResourceVSphereFolderHasName(testAccResourceVSphereFolderConfigExpectedName),
+					testAccResourceVSphereFolderHasType(folder.VSphereFolderTypeVM),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSimulator_resourcePool(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccSimulatorPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereResourcePoolCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereResourcePoolConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereResourcePoolCheckExists(true),
+					testAccResourceVSphereResourcePoolHasParent("terraform-resource-pool-test-parent"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSimulator_computeCluster(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccSimulatorPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereComputeClusterCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereComputeClusterConfigEmpty(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereComputeClusterCheckExists(true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSimulator_datastoreCluster(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccSimulatorPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereDatastoreClusterCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereDatastoreClusterConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereDatastoreClusterCheckExists(true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSimulator_virtualMachine(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccSimulatorPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereVirtualMachineCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVirtualMachineConfigBasic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereVirtualMachineCheckExists(true),
+					resource.TestMatchResourceAttr("vsphere_virtual_machine.vm", "moid", regexp.MustCompile("^vm-")),
+				),
+			},
+		},
+	})
+}
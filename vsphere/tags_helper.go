@@ -99,8 +99,8 @@ func isEligibleVSANEndpoint(client *govmomi.Client) bool {
 
 // tagCategoryByName locates a tag category by name. It's used by the
 // vsphere_tag_category data source, and the resource importer.
-func tagCategoryByName(tm *tags.Manager, name string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func tagCategoryByName(ctx context.Context, tm *tags.Manager, name string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	allCats, err := tm.GetCategories(ctx)
 	if err != nil {
@@ -132,8 +132,8 @@ func tagCategoryByName(tm *tags.Manager, name string) (string, error) {
 // tagByName locates a tag by it supplied name and category ID. Use
 // tagCategoryByName to get the tag category ID if require the category ID as
 // well.
-func tagByName(tm *tags.Manager, name, categoryID string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func tagByName(ctx context.Context, tm *tags.Manager, name, categoryID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	allTags, err := tm.GetTagsForCategory(ctx, categoryID)
 	var tagList []*tags.Tag
@@ -176,9 +176,9 @@ func tagsSchema() *schema.Schema {
 // readTagsForResource reads the tags for a given reference and saves the list
 // in the supplied ResourceData. It returns an error if there was an issue
 // reading the tags.
-func readTagsForResource(tm *tags.Manager, obj object.Reference, d *schema.ResourceData) error {
+func readTagsForResource(ctx context.Context, tm *tags.Manager, obj object.Reference, d *schema.ResourceData) error {
 	log.Printf("[DEBUG] Reading tags for object %q", obj.Reference().Value)
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
 	ids, err := tm.ListAttachedTags(ctx, obj)
@@ -238,13 +238,13 @@ func (p *tagDiffProcessor) diff(a, b []string) []string {
 
 // processAttachOperations processes all pending attach operations by diffing old
 // and new and adding any IDs that were not found in old.
-func (p *tagDiffProcessor) processAttachOperations() error {
+func (p *tagDiffProcessor) processAttachOperations(ctx context.Context) error {
 	tagIDs := p.diffNewOld()
 	if len(tagIDs) < 1 {
 		return nil
 	}
 	for _, tagID := range tagIDs {
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 		log.Printf("[DEBUG] Attaching tag %q for object %q", tagID, p.subject.Reference().Value)
 		err := p.manager.AttachTag(ctx, tagID, p.subject)
 		cancel()
@@ -257,13 +257,13 @@ func (p *tagDiffProcessor) processAttachOperations() error {
 
 // processDetachOperations processes all pending detach operations by diffing
 // new and old, and removing any IDs that were not found in new.
-func (p *tagDiffProcessor) processDetachOperations() error {
+func (p *tagDiffProcessor) processDetachOperations(ctx context.Context) error {
 	tagIDs := p.diffOldNew()
 	if len(tagIDs) < 1 {
 		return nil
 	}
 	for _, tagID := range tagIDs {
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 		log.Printf("[DEBUG] Detaching tag %q for object %q", tagID, p.subject.Reference().Value)
 		err := p.manager.DetachTag(ctx, tagID, p.subject)
 		cancel()
@@ -297,7 +297,7 @@ func tagsManagerIfDefined(d *schema.ResourceData, meta interface{}) (*tags.Manag
 
 // processTagDiff wraps the whole tag diffing operation into a nice clean
 // function that resources can use.
-func processTagDiff(tm *tags.Manager, d *schema.ResourceData, obj object.Reference) error {
+func processTagDiff(ctx context.Context, tm *tags.Manager, d *schema.ResourceData, obj object.Reference) error {
 	log.Printf("[DEBUG] Processing tags for object %q", obj.Reference().Value)
 	old, newValue := d.GetChange(vSphereTagAttributeKey)
 	tdp := &tagDiffProcessor{
@@ -306,10 +306,10 @@ func processTagDiff(tm *tags.Manager, d *schema.ResourceData, obj object.Referen
 		oldTagIDs: structure.SliceInterfacesToStrings(old.(*schema.Set).List()),
 		newTagIDs: structure.SliceInterfacesToStrings(newValue.(*schema.Set).List()),
 	}
-	if err := tdp.processDetachOperations(); err != nil {
+	if err := tdp.processDetachOperations(ctx); err != nil {
 		return fmt.Errorf("error detaching tags to object ID %q: %s", obj.Reference().Value, err)
 	}
-	if err := tdp.processAttachOperations(); err != nil {
+	if err := tdp.processAttachOperations(ctx); err != nil {
 		return fmt.Errorf("error attaching tags to object ID %q: %s", obj.Reference().Value, err)
 	}
 	return nil
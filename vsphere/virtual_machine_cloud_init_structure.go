@@ -0,0 +1,287 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi/vim25/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	cloudInitEncodingBase64     = "base64"
+	cloudInitEncodingGzipBase64 = "gzip+base64"
+)
+
+var cloudInitEncodingAllowedValues = []string{
+	cloudInitEncodingBase64,
+	cloudInitEncodingGzipBase64,
+}
+
+// cloudInitGuestInfoKeys maps the cloud_init attributes to the guestinfo keys
+// read by the VMware datasource for cloud-init. The encoding of each key is
+// set in a matching "<key>.encoding" key.
+var cloudInitGuestInfoKeys = map[string]string{
+	"metadata":   "guestinfo.metadata",
+	"userdata":   "guestinfo.userdata",
+	"vendordata": "guestinfo.vendordata",
+}
+
+// schemaVirtualMachineCloudInit returns the schema for the cloud_init block
+// of vsphere_virtual_machine.
+func schemaVirtualMachineCloudInit() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"metadata": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The cloud-init metadata for the virtual machine, as YAML or JSON.",
+			ValidateFunc: validateCloudInitYAML,
+		},
+		"userdata": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The cloud-init user data for the virtual machine. Cloud config documents starting with #cloud-config are validated as YAML.",
+			ValidateFunc: validateCloudInitUserData,
+		},
+		"vendordata": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The cloud-init vendor data for the virtual machine. Cloud config documents starting with #cloud-config are validated as YAML.",
+			ValidateFunc: validateCloudInitUserData,
+		},
+		"encoding": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      cloudInitEncodingGzipBase64,
+			Description:  "The encoding of the data sent to the guest. One of base64 or gzip+base64.",
+			ValidateFunc: validation.StringInSlice(cloudInitEncodingAllowedValues, false),
+		},
+		"apply_on_change": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Apply changes to the cloud-init data to an existing virtual machine. When false, the data is only applied when the virtual machine is created.",
+		},
+		"reboot_on_change": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Allow the virtual machine to be rebooted when a change to the cloud-init data is applied.",
+		},
+	}
+}
+
+// validateCloudInitYAML checks that a value is a valid YAML or JSON document.
+func validateCloudInitYAML(v interface{}, k string) ([]string, []error) {
+	var out interface{}
+	if err := yaml.Unmarshal([]byte(v.(string)), &out); err != nil {
+		return nil, []error{fmt.Errorf("%s is not valid YAML or JSON: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// validateCloudInitUserData checks that user or vendor data is valid YAML
+// when it is a cloud config document. Other formats, such as scripts or MIME
+// multi-part archives, are passed to the guest as they are.
+func validateCloudInitUserData(v interface{}, k string) ([]string, []error) {
+	if !strings.HasPrefix(strings.TrimSpace(v.(string)), "#cloud-config") {
+		return nil, nil
+	}
+	return validateCloudInitYAML(v, k)
+}
+
+// expandCloudInit reads the cloud_init block and returns the guestinfo
+// OptionValues for it.
+//
+// As with extra_config, values are only returned when the data has changed,
+// and keys for data that has been removed are set to an empty value to
+// remove them from extraConfig. Changes after creation are skipped when
+// apply_on_change is false, except for the removal of the cloud_init block,
+// which always removes the keys.
+func expandCloudInit(d *schema.ResourceData) ([]types.BaseOptionValue, error) {
+	if !d.HasChanges("cloud_init.0.metadata", "cloud_init.0.userdata", "cloud_init.0.vendordata", "cloud_init.0.encoding") {
+		return nil, nil
+	}
+	removed := len(d.Get("cloud_init").([]interface{})) < 1
+	if !d.IsNewResource() && !removed {
+		if !d.Get("cloud_init.0.apply_on_change").(bool) {
+			log.Printf("[DEBUG] %s: Skipping cloud-init changes, apply_on_change is false", resourceVSphereVirtualMachineIDString(d))
+			return nil, nil
+		}
+		if d.Get("cloud_init.0.reboot_on_change").(bool) {
+			_ = d.Set("reboot_required", true)
+		}
+	}
+
+	old, newValue := d.GetChange("cloud_init")
+	oldData := cloudInitData(old.([]interface{}))
+	newData := cloudInitData(newValue.([]interface{}))
+	encoding := d.Get("cloud_init.0.encoding").(string)
+
+	var opts []types.BaseOptionValue
+	for _, attr := range []string{"metadata", "userdata", "vendordata"} {
+		key := cloudInitGuestInfoKeys[attr]
+		data := newData[attr]
+		if data == "" {
+			if oldData[attr] != "" {
+				opts = append(opts, cloudInitOptionValues(key, "", "")...)
+			}
+			continue
+		}
+		value, err := encodeCloudInitData(data, encoding)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding cloud-init %s: %s", attr, err)
+		}
+		opts = append(opts, cloudInitOptionValues(key, value, encoding)...)
+	}
+	return opts, nil
+}
+
+// flattenCloudInit reads the guestinfo keys of the cloud_init block from the
+// extraConfig of a virtual machine, so that changes made outside of Terraform
+// are detected. The keys are only read when the cloud_init block is set and
+// apply_on_change is true, as changes are not applied otherwise. Keys that
+// cannot be decoded are logged and keep their value in state.
+func flattenCloudInit(d *schema.ResourceData, opts []types.BaseOptionValue) error {
+	l := d.Get("cloud_init").([]interface{})
+	if len(l) < 1 || l[0] == nil {
+		return nil
+	}
+	m := l[0].(map[string]interface{})
+	if !m["apply_on_change"].(bool) {
+		return nil
+	}
+
+	ec := make(map[string]string)
+	for _, v := range opts {
+		ov := v.GetOptionValue()
+		if value, ok := ov.Value.(string); ok {
+			ec[ov.Key] = value
+		}
+	}
+	for attr, key := range cloudInitGuestInfoKeys {
+		value := ec[key]
+		if value == "" {
+			m[attr] = ""
+			continue
+		}
+		encoding := ec[key+".encoding"]
+		data, err := decodeCloudInitData(value, encoding)
+		if err != nil {
+			// Keep the value in state rather than reading an empty value, which
+			// would show the data as removed in the next plan.
+			log.Printf("[WARN] %s: Error decoding %s, keeping the value in state: %s", resourceVSphereVirtualMachineIDString(d), key, err)
+			continue
+		}
+		m[attr] = data
+		if cloudInitEncodingValid(encoding) {
+			m["encoding"] = encoding
+		}
+	}
+	return d.Set("cloud_init", []interface{}{m})
+}
+
+// cloudInitEncodingValid returns true if encoding is one of the encodings
+// supported by cloud_init.
+func cloudInitEncodingValid(encoding string) bool {
+	for _, e := range cloudInitEncodingAllowedValues {
+		if encoding == e {
+			return true
+		}
+	}
+	return false
+}
+
+// cloudInitData returns the data attributes of a cloud_init block, or an
+// empty map if the block is not set.
+func cloudInitData(l []interface{}) map[string]string {
+	data := make(map[string]string)
+	if len(l) < 1 || l[0] == nil {
+		return data
+	}
+	m := l[0].(map[string]interface{})
+	for attr := range cloudInitGuestInfoKeys {
+		data[attr] = m[attr].(string)
+	}
+	return data
+}
+
+// cloudInitOptionValues returns the OptionValues for a guestinfo key and its
+// encoding.
+func cloudInitOptionValues(key, value, encoding string) []types.BaseOptionValue {
+	return []types.BaseOptionValue{
+		&types.OptionValue{
+			Key:   key,
+			Value: value,
+		},
+		&types.OptionValue{
+			Key:   key + ".encoding",
+			Value: encoding,
+		},
+	}
+}
+
+// encodeCloudInitData encodes cloud-init data with the supplied encoding.
+func encodeCloudInitData(data, encoding string) (string, error) {
+	if encoding == cloudInitEncodingBase64 {
+		return base64.StdEncoding.EncodeToString([]byte(data)), nil
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeCloudInitData decodes cloud-init data read from a guestinfo key with
+// the encoding of the key.
+func decodeCloudInitData(value, encoding string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	if encoding != cloudInitEncodingGzipBase64 {
+		return string(b), nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// cloudInitDiffOperation validates that extra_config does not set the
+// guestinfo keys managed by cloud_init.
+func cloudInitDiffOperation(d *schema.ResourceDiff) error {
+	if len(d.Get("cloud_init").([]interface{})) < 1 {
+		return nil
+	}
+	for k := range d.Get("extra_config").(map[string]interface{}) {
+		for _, key := range cloudInitGuestInfoKeys {
+			if k == key || k == key+".encoding" {
+				return fmt.Errorf("extra_config key %q cannot be used together with cloud_init", k)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestEncodeCloudInitData(t *testing.T) {
+	data := "#cloud-config\nhostname: test\n"
+
+	actual, err := encodeCloudInitData(data, cloudInitEncodingBase64)
+	if err != nil {
+		t.Fatalf("error encoding data: %s", err)
+	}
+	if expected := base64.StdEncoding.EncodeToString([]byte(data)); actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+
+	actual, err = encodeCloudInitData(data, cloudInitEncodingGzipBase64)
+	if err != nil {
+		t.Fatalf("error encoding data: %s", err)
+	}
+	b, err := base64.StdEncoding.DecodeString(actual)
+	if err != nil {
+		t.Fatalf("error decoding base64: %s", err)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("error opening gzip stream: %s", err)
+	}
+	b, err = io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("error reading gzip stream: %s", err)
+	}
+	if string(b) != data {
+		t.Fatalf("expected %q, got %q", data, string(b))
+	}
+}
+
+func TestDecodeCloudInitData(t *testing.T) {
+	data := "#cloud-config\nhostname: test\n"
+	for _, encoding := range cloudInitEncodingAllowedValues {
+		t.Run(encoding, func(t *testing.T) {
+			value, err := encodeCloudInitData(data, encoding)
+			if err != nil {
+				t.Fatalf("error encoding data: %s", err)
+			}
+			actual, err := decodeCloudInitData(value, encoding)
+			if err != nil {
+				t.Fatalf("error decoding data: %s", err)
+			}
+			if actual != data {
+				t.Fatalf("expected %q, got %q", data, actual)
+			}
+		})
+	}
+}
+
+func TestFlattenCloudInit(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"cloud_init": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     &schema.Resource{Schema: schemaVirtualMachineCloudInit()},
+		},
+	}
+	userdata, err := encodeCloudInitData("#cloud-config\nhostname: changed\n", cloudInitEncodingBase64)
+	if err != nil {
+		t.Fatalf("error encoding data: %s", err)
+	}
+	opts := []types.BaseOptionValue{
+		&types.OptionValue{Key: "guestinfo.userdata", Value: userdata},
+		&types.OptionValue{Key: "guestinfo.userdata.encoding", Value: cloudInitEncodingBase64},
+	}
+
+	cases := []struct {
+		name          string
+		applyOnChange bool
+		expected      string
+	}{
+		{"apply on change", true, "#cloud-config\nhostname: changed\n"},
+		{"apply on create only", false, "#cloud-config\nhostname: test\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, s, map[string]interface{}{
+				"cloud_init": []interface{}{
+					map[string]interface{}{
+						"metadata":        "local-hostname: test\n",
+						"userdata":        "#cloud-config\nhostname: test\n",
+						"apply_on_change": tc.applyOnChange,
+					},
+				},
+			})
+			if err := flattenCloudInit(d, opts); err != nil {
+				t.Fatalf("error reading cloud-init data: %s", err)
+			}
+			if actual := d.Get("cloud_init.0.userdata").(string); actual != tc.expected {
+				t.Fatalf("expected userdata %q, got %q", tc.expected, actual)
+			}
+			if tc.applyOnChange {
+				if actual := d.Get("cloud_init.0.metadata").(string); actual != "" {
+					t.Fatalf("expected removed metadata to be read as empty, got %q", actual)
+				}
+				if actual := d.Get("cloud_init.0.encoding").(string); actual != cloudInitEncodingBase64 {
+					t.Fatalf("expected encoding %q, got %q", cloudInitEncodingBase64, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestFlattenCloudInit_decodeError(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"cloud_init": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     &schema.Resource{Schema: schemaVirtualMachineCloudInit()},
+		},
+	}
+	opts := []types.BaseOptionValue{
+		&types.OptionValue{Key: "guestinfo.userdata", Value: "not base64!"},
+		&types.OptionValue{Key: "guestinfo.userdata.encoding", Value: cloudInitEncodingGzipBase64},
+	}
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		"cloud_init": []interface{}{
+			map[string]interface{}{
+				"userdata":        "#cloud-config\nhostname: test\n",
+				"apply_on_change": true,
+			},
+		},
+	})
+	if err := flattenCloudInit(d, opts); err != nil {
+		t.Fatalf("error reading cloud-init data: %s", err)
+	}
+	expected := "#cloud-config\nhostname: test\n"
+	if actual := d.Get("cloud_init.0.userdata").(string); actual != expected {
+		t.Fatalf("expected userdata %q, got %q", expected, actual)
+	}
+}
+
+func TestValidateCloudInitUserData(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected bool
+	}{
+		{"cloud config", "#cloud-config\nhostname: test\n", true},
+		{"invalid cloud config", "#cloud-config\nhostname: [test\n", false},
+		{"script", "#!/bin/sh\necho [\n", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, errs := validateCloudInitUserData(tc.value, "userdata")
+			if (len(errs) == 0) != tc.expected {
+				t.Fatalf("expected valid to be %t, got errors: %v", tc.expected, errs)
+			}
+		})
+	}
+}
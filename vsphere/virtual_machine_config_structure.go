@@ -5,6 +5,7 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -779,7 +780,7 @@ func flattenExtraConfig(d *schema.ResourceData, opts []types.BaseOptionValue) er
 // We track changes to keys to determine if any have been removed from
 // configuration - if they have, we add them with an empty value to ensure
 // they are removed from vAppConfig on the update.
-func expandVAppConfig(d *schema.ResourceData, client *govmomi.Client) (*types.VmConfigSpec, error) {
+func expandVAppConfig(ctx context.Context, d *schema.ResourceData, client *govmomi.Client) (*types.VmConfigSpec, error) {
 	if !d.HasChange("vapp") {
 		return nil, nil
 	}
@@ -813,8 +814,8 @@ func expandVAppConfig(d *schema.ResourceData, client *govmomi.Client) (*types.Vm
 		}
 		return nil, nil
 	}
-	vm, _ := virtualmachine.FromUUID(client, d.Id())
-	vmProps, _ := virtualmachine.Properties(vm)
+	vm, _ := virtualmachine.FromUUID(ctx, client, d.Id())
+	vmProps, _ := virtualmachine.Properties(ctx, vm)
 	if vmProps.Config.VAppConfig == nil {
 		return nil, fmt.Errorf("this VM lacks a vApp configuration and cannot have vApp properties set on it")
 	}
@@ -994,9 +995,13 @@ func expandVirtualMachineProfileSpec(d *schema.ResourceData) []types.BaseVirtual
 
 // expandVirtualMachineConfigSpec reads certain ResourceData keys and
 // returns a VirtualMachineConfigSpec.
-func expandVirtualMachineConfigSpec(d *schema.ResourceData, client *govmomi.Client) (types.VirtualMachineConfigSpec, error) {
+func expandVirtualMachineConfigSpec(ctx context.Context, d *schema.ResourceData, client *govmomi.Client) (types.VirtualMachineConfigSpec, error) {
 	log.Printf("[DEBUG] %s: Building config spec", resourceVSphereVirtualMachineIDString(d))
-	vappConfig, err := expandVAppConfig(d, client)
+	vappConfig, err := expandVAppConfig(ctx, d, client)
+	if err != nil {
+		return types.VirtualMachineConfigSpec{}, err
+	}
+	cloudInitConfig, err := expandCloudInit(d)
 	if err != nil {
 		return types.VirtualMachineConfigSpec{}, err
 	}
@@ -1018,7 +1023,7 @@ func expandVirtualMachineConfigSpec(d *schema.ResourceData, client *govmomi.Clie
 		CpuAllocation:                expandVirtualMachineResourceAllocation(d, "cpu"),
 		MemoryAllocation:             expandVirtualMachineResourceAllocation(d, "memory"),
 		MemoryReservationLockedToMax: getMemoryReservationLockedToMax(d),
-		ExtraConfig:                  expandExtraConfig(d),
+		ExtraConfig:                  append(expandExtraConfig(d), cloudInitConfig...),
 		SwapPlacement:                getWithRestart(d, "swap_placement_policy").(string),
 		BootOptions:                  expandVirtualMachineBootOptions(d, client),
 		VAppConfig:                   vappConfig,
@@ -1079,6 +1084,9 @@ func flattenVirtualMachineConfigInfo(d *schema.ResourceData, obj *types.VirtualM
 	if err := flattenExtraConfig(d, obj.ExtraConfig); err != nil {
 		return err
 	}
+	if err := flattenCloudInit(d, obj.ExtraConfig); err != nil {
+		return err
+	}
 	if err := flattenVAppConfig(d, obj.VAppConfig); err != nil {
 		return err
 	}
@@ -1099,36 +1107,75 @@ func flattenVirtualMachineConfigInfo(d *schema.ResourceData, obj *types.VirtualM
 // It does this be creating a fake ResourceData off of the VM resource schema,
 // flattening the config info into that, and then expanding both ResourceData
 // instances and comparing the resultant ConfigSpecs.
-func expandVirtualMachineConfigSpecChanged(d *schema.ResourceData, client *govmomi.Client, info *types.VirtualMachineConfigInfo) (types.VirtualMachineConfigSpec, bool, error) {
+func expandVirtualMachineConfigSpecChanged(ctx context.Context, d *schema.ResourceData, client *govmomi.Client, info *types.VirtualMachineConfigInfo) (types.VirtualMachineConfigSpec, bool, error) {
+	oldSpec, err := expandVirtualMachineConfigSpecFromInfo(ctx, d, client, info)
+	if err != nil {
+		return types.VirtualMachineConfigSpec{}, false, err
+	}
+
+	newSpec, err := expandVirtualMachineConfigSpec(ctx, d, client)
+	if err != nil {
+		return types.VirtualMachineConfigSpec{}, false, err
+	}
+
+	isVMConfigSpecChanged := !reflect.DeepEqual(oldSpec, newSpec)
+	sanitizeConfigSpec(&newSpec, info)
+
+	// Return the new spec and compare
+	return newSpec, isVMConfigSpecChanged, nil
+}
+
+// expandVirtualMachineConfigSpecFromInfo expands a VirtualMachineConfigSpec
+// from an existing VirtualMachineConfigInfo, by flattening the config info into
+// a fake ResourceData off of the VM resource schema and expanding that.
+func expandVirtualMachineConfigSpecFromInfo(ctx context.Context, d *schema.ResourceData, client *govmomi.Client, info *types.VirtualMachineConfigInfo) (types.VirtualMachineConfigSpec, error) {
 	// Create the fake ResourceData from the VM resource
 	oldData := resourceVSphereVirtualMachine().Data(nil)
 	oldData.SetId(d.Id())
 	// Flatten the old config info into it
 	err := flattenVirtualMachineConfigInfo(oldData, info, client)
 	if err != nil {
-		return types.VirtualMachineConfigSpec{}, false, err
+		return types.VirtualMachineConfigSpec{}, err
 	}
 	// Read state back in. This is necessary to ensure GetChange calls work
 	// correctly.
 	oldData = resourceVSphereVirtualMachine().Data(oldData.State())
-	// Get both specs.
 	log.Printf("[DEBUG] %s: Expanding old config. Ignore reboot_required messages", resourceVSphereVirtualMachineIDString(d))
-	oldSpec, err := expandVirtualMachineConfigSpec(oldData, client)
+	oldSpec, err := expandVirtualMachineConfigSpec(ctx, oldData, client)
 	if err != nil {
-		return types.VirtualMachineConfigSpec{}, false, err
+		return types.VirtualMachineConfigSpec{}, err
 	}
 	log.Printf("[DEBUG] %s: Expanding of old config complete", resourceVSphereVirtualMachineIDString(d))
+	return oldSpec, nil
+}
 
-	newSpec, err := expandVirtualMachineConfigSpec(d, client)
+// expandVirtualMachineConfigSpecDelta expands a VirtualMachineConfigSpec that
+// only contains the settings in the resource data that differ from an existing
+// VirtualMachineConfigInfo.
+//
+// This is used on instant clones, which are running from the time that they
+// are created. Settings that can only be changed while the virtual machine is
+// powered off, such as the firmware, are then only sent if they actually
+// change, in which case the reconfiguration fails.
+func expandVirtualMachineConfigSpecDelta(ctx context.Context, d *schema.ResourceData, client *govmomi.Client, info *types.VirtualMachineConfigInfo) (types.VirtualMachineConfigSpec, error) {
+	oldSpec, err := expandVirtualMachineConfigSpecFromInfo(ctx, d, client, info)
 	if err != nil {
-		return types.VirtualMachineConfigSpec{}, false, err
+		return types.VirtualMachineConfigSpec{}, err
+	}
+	newSpec, err := expandVirtualMachineConfigSpec(ctx, d, client)
+	if err != nil {
+		return types.VirtualMachineConfigSpec{}, err
 	}
-
-	isVMConfigSpecChanged := !reflect.DeepEqual(oldSpec, newSpec)
 	sanitizeConfigSpec(&newSpec, info)
 
-	// Return the new spec and compare
-	return newSpec, isVMConfigSpecChanged, nil
+	ov := reflect.ValueOf(oldSpec)
+	nv := reflect.ValueOf(&newSpec).Elem()
+	for i := 0; i < nv.NumField(); i++ {
+		if reflect.DeepEqual(nv.Field(i).Interface(), ov.Field(i).Interface()) {
+			nv.Field(i).Set(reflect.Zero(nv.Field(i).Type()))
+		}
+	}
+	return newSpec, nil
 }
 
 func sanitizeConfigSpec(spec *types.VirtualMachineConfigSpec, info *types.VirtualMachineConfigInfo) {
@@ -0,0 +1,421 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/keyprovider"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/spbm"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/virtualdevice"
+)
+
+var virtualMachineMigrateEncryptionAllowedValues = []string{
+	string(types.VirtualMachineConfigSpecEncryptedVMotionModesDisabled),
+	string(types.VirtualMachineConfigSpecEncryptedVMotionModesOpportunistic),
+	string(types.VirtualMachineConfigSpecEncryptedVMotionModesRequired),
+}
+
+var virtualMachineFtEncryptionModeAllowedValues = []string{
+	string(types.VirtualMachineConfigSpecEncryptedFtModesFtEncryptionDisabled),
+	string(types.VirtualMachineConfigSpecEncryptedFtModesFtEncryptionOpportunistic),
+	string(types.VirtualMachineConfigSpecEncryptedFtModesFtEncryptionRequired),
+}
+
+// schemaVirtualMachineEncryption returns the schema for the encryption block
+// of vsphere_virtual_machine.
+func schemaVirtualMachineEncryption() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"key_provider_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "The ID of the key provider used to encrypt the virtual machine. If not specified, the default key provider is used. Changing this rekeys the virtual machine.",
+		},
+		"encrypt_disks": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Encrypt the virtual disks of the virtual machine in addition to the virtual machine home.",
+		},
+		"deep_rekey": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Perform a deep rekey, which re-encrypts all data with a new key, when the key provider is changed. A deep rekey requires the virtual machine to be powered off. By default, a shallow rekey is performed, which only replaces the key encryption key.",
+		},
+		"migrate_encryption": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			Description:  "The encrypted vMotion policy of the virtual machine. Can be one of disabled, opportunistic, or required.",
+			ValidateFunc: validation.StringInSlice(virtualMachineMigrateEncryptionAllowedValues, false),
+		},
+		"ft_encryption_mode": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			Description:  "The encrypted Fault Tolerance policy of the virtual machine. Can be one of ftEncryptionDisabled, ftEncryptionOpportunistic, or ftEncryptionRequired.",
+			ValidateFunc: validation.StringInSlice(virtualMachineFtEncryptionModeAllowedValues, false),
+		},
+		"key_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The ID of the key used to encrypt the virtual machine.",
+		},
+	}
+}
+
+// encryptionDiffOperation validates the encryption block. Encryption is
+// applied and removed through storage policies, so storage_policy_id must be
+// set to an encryption storage policy when encryption is enabled, and moved
+// away from one when the block is removed. reboot_required is flagged for
+// existing virtual machines if the change requires them to be powered off.
+func encryptionDiffOperation(ctx context.Context, d *schema.ResourceDiff, client *govmomi.Client) error {
+	if d.Id() != "" && d.HasChange("encryption") && encryptionDiffRequiresPowerOff(d) {
+		log.Printf("[DEBUG] %s: Encryption change requires the virtual machine to be powered off", resourceVSphereVirtualMachineIDString(d))
+		if err := d.SetNew("reboot_required", true); err != nil {
+			return fmt.Errorf("error setting reboot_required: %s", err)
+		}
+	}
+	o, n := d.GetChange("encryption")
+	oldEnc, newEnc := o.([]interface{}), n.([]interface{})
+	if len(newEnc) == 0 || newEnc[0] == nil {
+		if d.Id() == "" || len(oldEnc) == 0 || oldEnc[0] == nil || !spbm.IsSupported(client) {
+			return nil
+		}
+		// The virtual machine is decrypted.
+		encryptDisks := oldEnc[0].(map[string]interface{})["encrypt_disks"].(bool)
+		return encryptionDiffCheckPolicies(ctx, d, client, false, encryptDisks)
+	}
+	if d.NewValueKnown("storage_policy_id") && d.Get("storage_policy_id").(string) == "" {
+		return fmt.Errorf("storage_policy_id must be set to an encryption storage policy when encryption is specified")
+	}
+	if !d.HasChanges("encryption", "storage_policy_id", "disk") || !spbm.IsSupported(client) {
+		return nil
+	}
+	encryptDisks := newEnc[0].(map[string]interface{})["encrypt_disks"].(bool)
+	return encryptionDiffCheckPolicies(ctx, d, client, true, encryptDisks)
+}
+
+// encryptionDiffCheckPolicies checks that storage_policy_id, and the
+// storage_policy_id of each disk if checkDisks is set, support encryption if
+// encrypt is set, or do not support it otherwise. Policies that are not set
+// or not yet known are skipped.
+func encryptionDiffCheckPolicies(ctx context.Context, d *schema.ResourceDiff, client *govmomi.Client, encrypt, checkDisks bool) error {
+	supported := make(map[string]bool)
+	check := func(key string) error {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+		id := d.Get(key).(string)
+		if id == "" {
+			return nil
+		}
+		if _, ok := supported[id]; !ok {
+			enc, err := spbm.PolicySupportsEncryption(ctx, client, id)
+			if err != nil {
+				return fmt.Errorf("error checking storage policy %q: %s", id, err)
+			}
+			supported[id] = enc
+		}
+		switch {
+		case encrypt && !supported[id]:
+			return fmt.Errorf("%s %q must be an encryption storage policy when encryption is specified", key, id)
+		case !encrypt && supported[id]:
+			return fmt.Errorf("%s %q is an encryption storage policy and must be changed when encryption is removed", key, id)
+		}
+		return nil
+	}
+	if err := check("storage_policy_id"); err != nil {
+		return err
+	}
+	if !checkDisks {
+		return nil
+	}
+	for i := range d.Get("disk").([]interface{}) {
+		if err := check(fmt.Sprintf("disk.%d.storage_policy_id", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encryptionDiffRequiresPowerOff returns true if a change to the encryption
+// block requires the virtual machine to be powered off. This is the case when
+// the virtual machine or its disks are encrypted or decrypted, or when a deep
+// rekey is performed.
+func encryptionDiffRequiresPowerOff(d *schema.ResourceDiff) bool {
+	o, n := d.GetChange("encryption")
+	oldEnc, newEnc := o.([]interface{}), n.([]interface{})
+	if len(oldEnc) == 0 || oldEnc[0] == nil || len(newEnc) == 0 || newEnc[0] == nil {
+		// The block is added or removed.
+		return true
+	}
+	oldSettings := oldEnc[0].(map[string]interface{})
+	newSettings := newEnc[0].(map[string]interface{})
+	if oldSettings["encrypt_disks"] != newSettings["encrypt_disks"] {
+		return true
+	}
+	if !d.NewValueKnown("encryption.0.key_provider_id") {
+		return newSettings["deep_rekey"].(bool)
+	}
+	providerID := newSettings["key_provider_id"].(string)
+	return providerID != "" && providerID != oldSettings["key_provider_id"] && newSettings["deep_rekey"].(bool)
+}
+
+// virtualMachineDiskKeyID returns the key used to encrypt a virtual disk, or
+// nil if the disk is not encrypted.
+func virtualMachineDiskKeyID(disk *types.VirtualDisk) *types.CryptoKeyId {
+	switch backing := disk.Backing.(type) {
+	case *types.VirtualDiskFlatVer2BackingInfo:
+		return backing.KeyId
+	case *types.VirtualDiskSeSparseBackingInfo:
+		return backing.KeyId
+	case *types.VirtualDiskSparseVer2BackingInfo:
+		return backing.KeyId
+	}
+	return nil
+}
+
+// virtualMachineEncryptionRequiresPowerOff returns true if reconciling the
+// encryption block with the current encryption state of a virtual machine
+// requires the virtual machine to be powered off. It follows the same rules
+// as expandVirtualMachineEncryptionSpec without generating any keys, so that
+// the virtual machine can be shut down before it is reconfigured.
+func virtualMachineEncryptionRequiresPowerOff(d *schema.ResourceData, vprops *mo.VirtualMachine) bool {
+	current := vprops.Config.KeyId
+	enc := d.Get("encryption").([]interface{})
+	if len(enc) == 0 || enc[0] == nil {
+		old, _ := d.GetChange("encryption")
+		return current != nil && len(old.([]interface{})) > 0
+	}
+	settings := enc[0].(map[string]interface{})
+	providerID := settings["key_provider_id"].(string)
+	encryptDisks := settings["encrypt_disks"].(bool)
+	switch {
+	case current == nil:
+		return true
+	case providerID != "" && (current.ProviderId == nil || current.ProviderId.Id != providerID) && settings["deep_rekey"].(bool):
+		return true
+	}
+	for _, device := range vprops.Config.Hardware.Device {
+		disk, ok := device.(*types.VirtualDisk)
+		if !ok {
+			continue
+		}
+		if encrypted := virtualMachineDiskKeyID(disk) != nil; encrypted != encryptDisks {
+			return true
+		}
+	}
+	return false
+}
+
+// expandVirtualMachineEncryptionSpec compares the encryption block with the
+// current encryption state of a virtual machine and returns the
+// VirtualMachineConfigSpec needed to reconcile them. nil is returned if no
+// change is needed. The second return value is true if the change requires
+// the virtual machine to be powered off.
+func expandVirtualMachineEncryptionSpec(ctx context.Context, d *schema.ResourceData, client *govmomi.Client, vprops *mo.VirtualMachine) (*types.VirtualMachineConfigSpec, bool, error) {
+	current := vprops.Config.KeyId
+	enc := d.Get("encryption").([]interface{})
+	if len(enc) == 0 || enc[0] == nil {
+		// Only decrypt virtual machines that were encrypted through the
+		// encryption block. Virtual machines encrypted by other means, such as
+		// through storage_policy_id alone, are left alone.
+		old, _ := d.GetChange("encryption")
+		if current == nil || len(old.([]interface{})) == 0 {
+			return nil, false, nil
+		}
+		log.Printf("[DEBUG] %s: Decrypting virtual machine", resourceVSphereVirtualMachineIDString(d))
+		spec := &types.VirtualMachineConfigSpec{
+			Crypto:    &types.CryptoSpecDecrypt{},
+			VmProfile: expandVirtualMachineProfileSpec(d),
+		}
+		spec.DeviceChange = expandVirtualMachineEncryptionDiskChanges(d, client, vprops, func(keyID *types.CryptoKeyId) types.BaseCryptoSpec {
+			if keyID == nil {
+				return nil
+			}
+			return &types.CryptoSpecDecrypt{}
+		})
+		return spec, true, nil
+	}
+
+	settings := enc[0].(map[string]interface{})
+	providerID := settings["key_provider_id"].(string)
+	encryptDisks := settings["encrypt_disks"].(bool)
+	spec := &types.VirtualMachineConfigSpec{}
+	powerOff := false
+
+	var key *types.CryptoKeyId
+	var homeCrypto types.BaseCryptoSpec
+	switch {
+	case current == nil:
+		log.Printf("[DEBUG] %s: Encrypting virtual machine", resourceVSphereVirtualMachineIDString(d))
+		newKey, err := keyprovider.GenerateKey(ctx, client, providerID)
+		if err != nil {
+			return nil, false, fmt.Errorf("error generating encryption key: %s", err)
+		}
+		key = newKey
+		homeCrypto = &types.CryptoSpecEncrypt{CryptoKeyId: *key}
+		powerOff = true
+	case providerID != "" && (current.ProviderId == nil || current.ProviderId.Id != providerID):
+		newKey, err := keyprovider.GenerateKey(ctx, client, providerID)
+		if err != nil {
+			return nil, false, fmt.Errorf("error generating encryption key: %s", err)
+		}
+		key = newKey
+		if settings["deep_rekey"].(bool) {
+			log.Printf("[DEBUG] %s: Performing deep rekey of virtual machine", resourceVSphereVirtualMachineIDString(d))
+			homeCrypto = &types.CryptoSpecDeepRecrypt{NewKeyId: *key}
+			powerOff = true
+		} else {
+			log.Printf("[DEBUG] %s: Performing shallow rekey of virtual machine", resourceVSphereVirtualMachineIDString(d))
+			homeCrypto = &types.CryptoSpecShallowRecrypt{NewKeyId: *key}
+		}
+	default:
+		key = current
+	}
+	if homeCrypto != nil {
+		spec.Crypto = homeCrypto
+		spec.VmProfile = expandVirtualMachineProfileSpec(d)
+	}
+
+	spec.DeviceChange = expandVirtualMachineEncryptionDiskChanges(d, client, vprops, func(keyID *types.CryptoKeyId) types.BaseCryptoSpec {
+		switch {
+		case encryptDisks && keyID == nil:
+			powerOff = true
+			return &types.CryptoSpecEncrypt{CryptoKeyId: *key}
+		case !encryptDisks && keyID != nil:
+			powerOff = true
+			return &types.CryptoSpecDecrypt{}
+		case keyID != nil && homeCrypto != nil && current != nil:
+			// Rekey disks along with the virtual machine home.
+			return homeCrypto
+		}
+		return nil
+	})
+
+	if mode := settings["migrate_encryption"].(string); mode != "" && mode != vprops.Config.MigrateEncryption {
+		spec.MigrateEncryption = mode
+	}
+	if mode := settings["ft_encryption_mode"].(string); mode != "" && mode != vprops.Config.FtEncryptionMode {
+		spec.FtEncryptionMode = mode
+	}
+
+	if spec.Crypto == nil && len(spec.DeviceChange) == 0 && spec.MigrateEncryption == "" && spec.FtEncryptionMode == "" {
+		return nil, false, nil
+	}
+	return spec, powerOff, nil
+}
+
+// expandVirtualMachineEncryptionDiskChanges returns a device change for each
+// virtual disk for which cryptoFn returns a crypto spec. cryptoFn is passed
+// the current key of the disk, or nil if the disk is not encrypted. Each disk
+// keeps its own storage_policy_id, and falls back to the storage policy of the
+// virtual machine if it has none.
+func expandVirtualMachineEncryptionDiskChanges(d *schema.ResourceData, client *govmomi.Client, vprops *mo.VirtualMachine, cryptoFn func(*types.CryptoKeyId) types.BaseCryptoSpec) []types.BaseVirtualDeviceConfigSpec {
+	policies := virtualdevice.DiskStoragePolicyIDs(d, client, object.VirtualDeviceList(vprops.Config.Hardware.Device))
+	var changes []types.BaseVirtualDeviceConfigSpec
+	for _, device := range vprops.Config.Hardware.Device {
+		disk, ok := device.(*types.VirtualDisk)
+		if !ok {
+			continue
+		}
+		crypto := cryptoFn(virtualMachineDiskKeyID(disk))
+		if crypto == nil {
+			continue
+		}
+		profile := expandVirtualMachineProfileSpec(d)
+		if policyID, ok := policies[disk.Key]; ok {
+			profile = spbm.PolicySpecByID(policyID)
+		}
+		changes = append(changes, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationEdit,
+			Device:    disk,
+			Backing: &types.VirtualDeviceConfigSpecBackingSpec{
+				Crypto: crypto,
+			},
+			Profile: profile,
+		})
+	}
+	return changes
+}
+
+// resourceVSphereVirtualMachineApplyEncryption reconciles the encryption
+// state of a virtual machine with the encryption block. On update, the
+// virtual machine is expected to have been shut down already through
+// reboot_required; it is only powered off here as a fallback. Callers are
+// responsible for powering the virtual machine back on.
+func resourceVSphereVirtualMachineApplyEncryption(ctx context.Context, d *schema.ResourceData, meta interface{}, vm *object.VirtualMachine) error {
+	client := meta.(*Client).vimClient
+	vprops, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return fmt.Errorf("error fetching VM properties: %s", err)
+	}
+	spec, powerOff, err := expandVirtualMachineEncryptionSpec(ctx, d, client, vprops)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		return nil
+	}
+	if powerOff && vprops.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
+		timeout := d.Get("shutdown_wait_timeout").(int)
+		force := d.Get("force_power_off").(bool)
+		if err := virtualmachine.GracefulPowerOff(ctx, client, vm, timeout, force); err != nil {
+			return fmt.Errorf("error shutting down virtual machine: %s", err)
+		}
+	}
+	timeout := meta.(*Client).timeout
+	if err := virtualmachine.Reconfigure(ctx, vm, *spec, timeout); err != nil {
+		return fmt.Errorf("error changing virtual machine encryption: %s", err)
+	}
+	return nil
+}
+
+// flattenVirtualMachineEncryption reads the encryption state of a virtual
+// machine into the encryption block. The block is only read if it is already
+// present in state, so that virtual machines encrypted by other means do not
+// get decrypted.
+func flattenVirtualMachineEncryption(d *schema.ResourceData, obj *types.VirtualMachineConfigInfo) error {
+	enc := d.Get("encryption").([]interface{})
+	if len(enc) == 0 || enc[0] == nil {
+		return nil
+	}
+	if obj.KeyId == nil {
+		return d.Set("encryption", nil)
+	}
+	settings := enc[0].(map[string]interface{})
+	encryptDisks := true
+	for _, device := range obj.Hardware.Device {
+		if disk, ok := device.(*types.VirtualDisk); ok && virtualMachineDiskKeyID(disk) == nil {
+			encryptDisks = false
+		}
+	}
+	var providerID string
+	if obj.KeyId.ProviderId != nil {
+		providerID = obj.KeyId.ProviderId.Id
+	}
+	return d.Set("encryption", []interface{}{
+		map[string]interface{}{
+			"key_provider_id":    providerID,
+			"encrypt_disks":      encryptDisks,
+			"deep_rekey":         settings["deep_rekey"],
+			"migrate_encryption": obj.MigrateEncryption,
+			"ft_encryption_mode": obj.FtEncryptionMode,
+			"key_id":             obj.KeyId.KeyId,
+		},
+	})
+}
@@ -0,0 +1,149 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func testVirtualMachineEncryptionProperties(keyID *types.CryptoKeyId, diskKeyIDs ...*types.CryptoKeyId) *mo.VirtualMachine {
+	var devices []types.BaseVirtualDevice
+	for _, diskKeyID := range diskKeyIDs {
+		devices = append(devices, &types.VirtualDisk{
+			VirtualDevice: types.VirtualDevice{
+				Backing: &types.VirtualDiskFlatVer2BackingInfo{KeyId: diskKeyID},
+			},
+		})
+	}
+	return &mo.VirtualMachine{
+		Config: &types.VirtualMachineConfigInfo{
+			KeyId:    keyID,
+			Hardware: types.VirtualHardware{Device: devices},
+		},
+	}
+}
+
+func TestExpandVirtualMachineEncryptionSpec(t *testing.T) {
+	key := &types.CryptoKeyId{KeyId: "key1", ProviderId: &types.KeyProviderId{Id: "kp1"}}
+
+	cases := []struct {
+		name              string
+		encryptDisks      bool
+		migrateEncryption string
+		vprops            *mo.VirtualMachine
+		expectedChanges   int
+		expectedPowerOff  bool
+	}{
+		{"no change", true, "", testVirtualMachineEncryptionProperties(key, key), 0, false},
+		{"encrypt disk", true, "", testVirtualMachineEncryptionProperties(key, key, nil), 1, true},
+		{"decrypt disks", false, "", testVirtualMachineEncryptionProperties(key, key, key), 2, true},
+		{"encrypted vMotion", true, "required", testVirtualMachineEncryptionProperties(key, key), 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceVSphereVirtualMachine().Schema, map[string]interface{}{
+				"storage_policy_id": "policy1",
+				"encryption": []interface{}{
+					map[string]interface{}{
+						"key_provider_id":    "kp1",
+						"encrypt_disks":      tc.encryptDisks,
+						"migrate_encryption": tc.migrateEncryption,
+					},
+				},
+			})
+			spec, powerOff, err := expandVirtualMachineEncryptionSpec(context.Background(), d, nil, tc.vprops)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual := virtualMachineEncryptionRequiresPowerOff(d, tc.vprops); actual != tc.expectedPowerOff {
+				t.Fatalf("expected the power off check to return %t, got %t", tc.expectedPowerOff, actual)
+			}
+			if tc.migrateEncryption != "" {
+				if spec == nil || spec.MigrateEncryption != tc.migrateEncryption {
+					t.Fatalf("expected migrate encryption to be set to %q, got %#v", tc.migrateEncryption, spec)
+				}
+				return
+			}
+			if tc.expectedChanges == 0 {
+				if spec != nil {
+					t.Fatalf("expected no spec, got %#v", spec)
+				}
+				return
+			}
+			if spec == nil {
+				t.Fatal("expected spec, got nil")
+			}
+			if spec.Crypto != nil {
+				t.Fatalf("expected no change to the virtual machine home, got %#v", spec.Crypto)
+			}
+			if len(spec.DeviceChange) != tc.expectedChanges {
+				t.Fatalf("expected %d device changes, got %d", tc.expectedChanges, len(spec.DeviceChange))
+			}
+			if powerOff != tc.expectedPowerOff {
+				t.Fatalf("expected power off to be %t, got %t", tc.expectedPowerOff, powerOff)
+			}
+		})
+	}
+}
+
+func TestExpandVirtualMachineEncryptionDiskChanges_diskPolicy(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVirtualMachine().Schema, map[string]interface{}{
+		"storage_policy_id": "vm-policy",
+		"disk": []interface{}{
+			map[string]interface{}{
+				"label":             "disk0",
+				"uuid":              "uuid0",
+				"storage_policy_id": "disk-policy",
+			},
+			map[string]interface{}{
+				"label": "disk1",
+				"uuid":  "uuid1",
+			},
+		},
+	})
+	vprops := &mo.VirtualMachine{
+		Config: &types.VirtualMachineConfigInfo{
+			Hardware: types.VirtualHardware{
+				Device: []types.BaseVirtualDevice{
+					&types.VirtualDisk{
+						VirtualDevice: types.VirtualDevice{
+							Key:     2000,
+							Backing: &types.VirtualDiskFlatVer2BackingInfo{Uuid: "uuid0"},
+						},
+					},
+					&types.VirtualDisk{
+						VirtualDevice: types.VirtualDevice{
+							Key:     2001,
+							Backing: &types.VirtualDiskFlatVer2BackingInfo{Uuid: "uuid1"},
+						},
+					},
+				},
+			},
+		},
+	}
+	changes := expandVirtualMachineEncryptionDiskChanges(d, nil, vprops, func(_ *types.CryptoKeyId) types.BaseCryptoSpec {
+		return &types.CryptoSpecDecrypt{}
+	})
+	expected := map[int32]string{2000: "disk-policy", 2001: "vm-policy"}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d device changes, got %d", len(expected), len(changes))
+	}
+	for _, change := range changes {
+		spec := change.GetVirtualDeviceConfigSpec()
+		key := spec.Device.GetVirtualDevice().Key
+		if len(spec.Profile) != 1 {
+			t.Fatalf("expected one profile for disk %d, got %d", key, len(spec.Profile))
+		}
+		actual := spec.Profile[0].(*types.VirtualMachineDefinedProfileSpec).ProfileId
+		if actual != expected[key] {
+			t.Fatalf("expected disk %d to use storage policy %q, got %q", key, expected[key], actual)
+		}
+	}
+}
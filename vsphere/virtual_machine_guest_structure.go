@@ -40,6 +40,32 @@ func schemaVirtualMachineGuestInfo() map[string]*schema.Schema {
 // guest_ip_addresses.
 func buildAndSelectGuestIPs(d *schema.ResourceData, guest types.GuestInfo) error {
 	log.Printf("[DEBUG] %s: Checking guest networking state", resourceVSphereVirtualMachineIDString(d))
+	primary, addrs := selectGuestIPs(guest)
+	if len(addrs) < 1 {
+		// No IP addresses were discovered. This more than likely means that the VM
+		// is powered off, or VMware Tools is not installed. We can return here,
+		// setting the empty set of addresses to avoid spurious diffs.
+		log.Printf("[DEBUG] %s: No IP addresses found in guest state", resourceVSphereVirtualMachineIDString(d))
+		return d.Set("guest_ip_addresses", addrs)
+	}
+	log.Printf("[DEBUG] %s: Primary IP address: %s", resourceVSphereVirtualMachineIDString(d), primary)
+	_ = d.Set("default_ip_address", primary)
+	log.Printf("[DEBUG] %s: All IP addresses: %s", resourceVSphereVirtualMachineIDString(d), strings.Join(addrs, ","))
+	if err := d.Set("guest_ip_addresses", addrs); err != nil {
+		return err
+	}
+	d.SetConnInfo(map[string]string{
+		"type": "ssh",
+		"host": primary,
+	})
+
+	return nil
+}
+
+// selectGuestIPs returns the primary IP address and the list of all IP
+// addresses known to VMware Tools, as described in buildAndSelectGuestIPs. The
+// primary address is empty when no addresses are known.
+func selectGuestIPs(guest types.GuestInfo) (string, []string) {
 	var v4primary, v6primary, v4gw, v6gw net.IP
 	var v4net2addrs, v6net2addrs map[string][]string
 	var deviceMacAddresses []string
@@ -105,32 +131,13 @@ func buildAndSelectGuestIPs(d *schema.ResourceData, guest types.GuestInfo) error
 		addrs = append(addrs, guest.IpAddress)
 	}
 
-	if len(addrs) < 1 {
-		// No IP addresses were discovered. This more than likely means that the VM
-		// is powered off, or VMware Tools is not installed. We can return here,
-		// setting the empty set of addresses to avoid spurious diffs.
-		log.Printf("[DEBUG] %s: No IP addresses found in guest state", resourceVSphereVirtualMachineIDString(d))
-		return d.Set("guest_ip_addresses", addrs)
-	}
-	var primary string
 	switch {
+	case len(addrs) < 1:
+		return "", addrs
 	case v4primary != nil:
-		primary = v4primary.String()
+		return v4primary.String(), addrs
 	case v6primary != nil:
-		primary = v6primary.String()
-	default:
-		primary = addrs[0]
-	}
-	log.Printf("[DEBUG] %s: Primary IP address: %s", resourceVSphereVirtualMachineIDString(d), primary)
-	_ = d.Set("default_ip_address", primary)
-	log.Printf("[DEBUG] %s: All IP addresses: %s", resourceVSphereVirtualMachineIDString(d), strings.Join(addrs, ","))
-	if err := d.Set("guest_ip_addresses", addrs); err != nil {
-		return err
+		return v6primary.String(), addrs
 	}
-	d.SetConnInfo(map[string]string{
-		"type": "ssh",
-		"host": primary,
-	})
-
-	return nil
+	return addrs[0], addrs
 }
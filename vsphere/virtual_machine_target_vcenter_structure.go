@@ -0,0 +1,265 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/datastore"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/folder"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/virtualdevice"
+)
+
+// schemaVirtualMachineTargetVCenter returns the schema for the target_vcenter
+// block of vsphere_virtual_machine.
+func schemaVirtualMachineTargetVCenter() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"server": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The fully qualified domain name or IP address of the vCenter Server instance.",
+		},
+		"username": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The user name for the vCenter Server instance.",
+		},
+		"password": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Sensitive:   true,
+			Description: "The password for the vCenter Server instance.",
+		},
+		"thumbprint": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The SHA-1 or SHA-256 thumbprint of the certificate of the vCenter Server instance. When set, the certificate is verified against it.",
+		},
+		"instance_uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The instance UUID of the vCenter Server instance.",
+		},
+	}
+}
+
+// virtualMachineResourceGetter is implemented by both *schema.ResourceData and
+// *schema.ResourceDiff.
+type virtualMachineResourceGetter interface {
+	Get(string) interface{}
+}
+
+// virtualMachineTargetVCenterServer returns the server of a target_vcenter
+// value, or an empty string if the block is not set.
+func virtualMachineTargetVCenterServer(v interface{}) string {
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return ""
+	}
+	return l[0].(map[string]interface{})["server"].(string)
+}
+
+// virtualMachineVCenterClient returns the client for a target_vcenter value.
+// The client of the provider connection is returned when the block is not
+// set.
+func virtualMachineVCenterClient(ctx context.Context, meta interface{}, v interface{}) (*Client, error) {
+	client := meta.(*Client).originClient()
+	if virtualMachineTargetVCenterServer(v) == "" {
+		return client, nil
+	}
+	tv := v.([]interface{})[0].(map[string]interface{})
+	return client.VCenterClient(
+		ctx,
+		tv["server"].(string),
+		tv["username"].(string),
+		tv["password"].(string),
+		tv["thumbprint"].(string),
+	)
+}
+
+// resourceVSphereVirtualMachineClient returns the client for the vCenter
+// Server instance that the virtual machine is managed on. This is the instance
+// in target_vcenter when set, and the instance that the provider is connected
+// to otherwise.
+func resourceVSphereVirtualMachineClient(ctx context.Context, d virtualMachineResourceGetter, meta interface{}) (*Client, error) {
+	client, err := virtualMachineVCenterClient(ctx, meta, d.Get("target_vcenter"))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to target_vcenter: %s", err)
+	}
+	return client, nil
+}
+
+// resourceVSphereVirtualMachineCloneSourceClient returns the client for the
+// vCenter Server instance that the source of a clone is on. When the virtual
+// machine is managed on another vCenter Server instance through
+// target_vcenter, and the source is not found on that instance, the source is
+// looked up on the instance that the provider is connected to, and the clone
+// is performed across vCenter Server instances.
+func resourceVSphereVirtualMachineCloneSourceClient(ctx context.Context, client *Client, uuid string) (*Client, error) {
+	origin := client.originClient()
+	if origin == client {
+		return client, nil
+	}
+	_, err := virtualmachine.FromUUID(ctx, client.vimClient, uuid)
+	if err == nil {
+		return client, nil
+	}
+	var notFoundError *virtualmachine.UUIDNotFoundError
+	if !errors.As(err, &notFoundError) {
+		return nil, err
+	}
+	log.Printf("[DEBUG] Clone source %q not found on %s, using %s", uuid, client.vimClient.URL().Host, origin.vimClient.URL().Host)
+	return origin, nil
+}
+
+// virtualMachineServiceLocator returns the service locator of the vCenter
+// Server instance that client is connected to, using the credentials in the
+// target_vcenter value v. When v is not set, such as when the virtual machine
+// is migrated back to the vCenter Server instance of the provider, the
+// credentials of the provider are used.
+func virtualMachineServiceLocator(client *Client, v interface{}) (*types.ServiceLocator, error) {
+	if virtualMachineTargetVCenterServer(v) == "" {
+		cfg := client.originClient().config
+		if err := virtualMachineValidateOriginCredentials(cfg); err != nil {
+			return nil, err
+		}
+		return virtualmachine.ServiceLocator(client.vimClient, cfg.User, cfg.Password, cfg.Thumbprint)
+	}
+	tv := v.([]interface{})[0].(map[string]interface{})
+	return virtualmachine.ServiceLocator(
+		client.vimClient,
+		tv["username"].(string),
+		tv["password"].(string),
+		tv["thumbprint"].(string),
+	)
+}
+
+// virtualMachineValidateOriginCredentials checks that the virtual machine can
+// be migrated back to the vCenter Server instance of the provider, which
+// requires the user name and password of the provider for the service
+// locator.
+func virtualMachineValidateOriginCredentials(cfg *Config) error {
+	if cfg == nil || cfg.User == "" || cfg.Password == "" {
+		return errors.New("removing target_vcenter migrates the virtual machine back to the vCenter Server instance of the provider, which requires the provider to log in with a user and password")
+	}
+	return nil
+}
+
+// virtualMachineTargetVCenterDiffOperation validates the removal of the
+// target_vcenter block of an existing virtual machine.
+func virtualMachineTargetVCenterDiffOperation(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	o, n := d.GetChange("target_vcenter")
+	if virtualMachineTargetVCenterServer(o) == "" || virtualMachineTargetVCenterServer(n) != "" {
+		return nil
+	}
+	return virtualMachineValidateOriginCredentials(meta.(*Client).originClient().config)
+}
+
+// flattenVirtualMachineTargetVCenter sets the computed attributes of the
+// target_vcenter block.
+func flattenVirtualMachineTargetVCenter(d *schema.ResourceData, client *Client) error {
+	l := d.Get("target_vcenter").([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	tv := l[0].(map[string]interface{})
+	tv["instance_uuid"] = client.vimClient.ServiceContent.About.InstanceUuid
+	return d.Set("target_vcenter", []interface{}{tv})
+}
+
+// resourceVSphereVirtualMachineUpdateVCenter migrates the virtual machine
+// across vCenter Server instances when the server in target_vcenter changes.
+// The resource pool, host, datastore, folder, and networks in configuration
+// are looked up on the destination instance. All disks are moved to the
+// datastore of the virtual machine. It returns true if the virtual machine
+// was migrated.
+func resourceVSphereVirtualMachineUpdateVCenter(ctx context.Context, d *schema.ResourceData, meta interface{}) (bool, error) {
+	o, n := d.GetChange("target_vcenter")
+	if virtualMachineTargetVCenterServer(o) == virtualMachineTargetVCenterServer(n) {
+		return false, nil
+	}
+	if _, ok := d.GetOk("datastore_cluster_id"); ok {
+		return false, errors.New("datastore_cluster_id cannot be used when migrating across vCenter Server instances")
+	}
+	src, err := virtualMachineVCenterClient(ctx, meta, o)
+	if err != nil {
+		return false, fmt.Errorf("error connecting to source vCenter Server: %s", err)
+	}
+	dst, err := virtualMachineVCenterClient(ctx, meta, n)
+	if err != nil {
+		return false, fmt.Errorf("error connecting to destination vCenter Server: %s", err)
+	}
+	log.Printf(
+		"[DEBUG] %s: Migrating virtual machine from %s to %s",
+		resourceVSphereVirtualMachineIDString(d),
+		src.vimClient.URL().Host,
+		dst.vimClient.URL().Host,
+	)
+
+	vm, err := virtualmachine.FromUUID(ctx, src.vimClient, d.Id())
+	if err != nil {
+		return false, fmt.Errorf("cannot locate virtual machine with UUID %q: %s", d.Id(), err)
+	}
+	vprops, err := virtualmachine.Properties(ctx, vm)
+	if err != nil {
+		return false, fmt.Errorf("error fetching VM properties: %s", err)
+	}
+
+	poolID := d.Get("resource_pool_id").(string)
+	pool, err := resourcepool.FromID(ctx, dst.vimClient, poolID)
+	if err != nil {
+		return false, fmt.Errorf("could not find resource pool ID %q: %s", poolID, err)
+	}
+	fo, err := folder.VirtualMachineFolderFromObject(ctx, dst.vimClient, pool, d.Get("folder").(string))
+	if err != nil {
+		return false, err
+	}
+	spec := types.VirtualMachineRelocateSpec{
+		Pool:   types.NewReference(pool.Reference()),
+		Folder: types.NewReference(fo.Reference()),
+	}
+	if v, ok := d.GetOk("host_system_id"); ok {
+		hs, err := hostsystem.FromID(ctx, dst.vimClient, v.(string))
+		if err != nil {
+			return false, fmt.Errorf("error locating host system at ID %q: %s", v, err)
+		}
+		if err := resourcepool.ValidateHost(ctx, dst.vimClient, pool, hs); err != nil {
+			return false, err
+		}
+		spec.Host = types.NewReference(hs.Reference())
+	}
+	if v, ok := d.GetOk("datastore_id"); ok {
+		ds, err := datastore.FromID(ctx, dst.vimClient, v.(string))
+		if err != nil {
+			return false, fmt.Errorf("error locating datastore for VM: %s", err)
+		}
+		spec.Datastore = types.NewReference(ds.Reference())
+	}
+	spec.DeviceChange, err = virtualdevice.NetworkInterfaceMigrateRelocateOperation(ctx, d, dst.vimClient, object.VirtualDeviceList(vprops.Config.Hardware.Device))
+	if err != nil {
+		return false, err
+	}
+	spec.Service, err = virtualMachineServiceLocator(dst, n)
+	if err != nil {
+		return false, err
+	}
+
+	if err := virtualmachine.Relocate(ctx, vm, spec, d.Get("migrate_wait_timeout").(int)); err != nil {
+		return false, fmt.Errorf("error migrating virtual machine to %s: %s", dst.vimClient.URL().Host, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,160 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+)
+
+func TestVirtualMachineTargetVCenterServer(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"unset", []interface{}{}, ""},
+		{"empty block", []interface{}{nil}, ""},
+		{"set", []interface{}{map[string]interface{}{"server": "vc2.example.com"}}, "vc2.example.com"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := virtualMachineTargetVCenterServer(tc.value); actual != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestVirtualMachineVCenterClient(t *testing.T) {
+	ctx := context.Background()
+	source, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer source.Close()
+	target, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer target.Close()
+
+	origin, err := testSimulatorConfig(source).Client()
+	if err != nil {
+		t.Fatalf("error creating client: %s", err)
+	}
+	password, _ := target.Server.URL.User.Password()
+	tv := []interface{}{
+		map[string]interface{}{
+			"server":     target.Server.URL.Host,
+			"username":   target.Server.URL.User.Username(),
+			"password":   password,
+			"thumbprint": "",
+		},
+	}
+
+	client, err := virtualMachineVCenterClient(ctx, origin, tv)
+	if err != nil {
+		t.Fatalf("error connecting to target vCenter Server: %s", err)
+	}
+	if client == origin || client.originClient() != origin {
+		t.Fatal("expected a client for the target vCenter Server")
+	}
+	if client.restClient == nil || client.pbmClient == nil {
+		t.Fatal("expected REST and PBM clients to be configured")
+	}
+	cached, err := virtualMachineVCenterClient(ctx, client, tv)
+	if err != nil {
+		t.Fatalf("error connecting to target vCenter Server: %s", err)
+	}
+	if cached != client {
+		t.Fatal("expected the target vCenter Server client to be cached")
+	}
+	if c, _ := virtualMachineVCenterClient(ctx, client, []interface{}{}); c != origin {
+		t.Fatal("expected the provider client when target_vcenter is not set")
+	}
+
+	locator, err := virtualMachineServiceLocator(client, tv)
+	if err != nil {
+		t.Fatalf("error building service locator: %s", err)
+	}
+	if locator.InstanceUuid != client.vimClient.ServiceContent.About.InstanceUuid {
+		t.Fatalf("expected instance UUID %q, got %q", client.vimClient.ServiceContent.About.InstanceUuid, locator.InstanceUuid)
+	}
+	if locator.SslThumbprint == "" {
+		t.Fatal("expected the thumbprint of the target vCenter Server to be fetched")
+	}
+
+	// A clone source is used from the target vCenter Server when it exists
+	// there, and from the provider vCenter Server otherwise.
+	vms, err := virtualmachine.List(ctx, client.vimClient)
+	if err != nil || len(vms) == 0 {
+		t.Fatalf("error listing virtual machines: %v", err)
+	}
+	src, err := resourceVSphereVirtualMachineCloneSourceClient(ctx, client, vms[0].UUID(ctx))
+	if err != nil {
+		t.Fatalf("error finding clone source: %s", err)
+	}
+	if src != client {
+		t.Fatal("expected the clone source on the target vCenter Server")
+	}
+	src, err = resourceVSphereVirtualMachineCloneSourceClient(ctx, client, "00000000-0000-0000-0000-000000000000")
+	if err != nil {
+		t.Fatalf("error finding clone source: %s", err)
+	}
+	if src != origin {
+		t.Fatal("expected the clone source on the provider vCenter Server")
+	}
+}
+
+func TestVirtualMachineServiceLocator_origin(t *testing.T) {
+	sim, err := testhelper.StartSimulator()
+	if err != nil {
+		t.Fatalf("error starting simulator: %s", err)
+	}
+	defer sim.Close()
+
+	c := testSimulatorConfig(sim)
+	origin, err := c.Client()
+	if err != nil {
+		t.Fatalf("error creating client: %s", err)
+	}
+
+	// Removing target_vcenter migrates the virtual machine back with the
+	// credentials of the provider.
+	locator, err := virtualMachineServiceLocator(origin, []interface{}{})
+	if err != nil {
+		t.Fatalf("error building service locator: %s", err)
+	}
+	credential := locator.Credential.(*types.ServiceLocatorNamePassword)
+	if credential.Username != c.User || credential.Password != c.Password {
+		t.Fatalf("expected the credentials of the provider, got %q", credential.Username)
+	}
+	if locator.InstanceUuid != origin.vimClient.ServiceContent.About.InstanceUuid {
+		t.Fatalf("expected instance UUID %q, got %q", origin.vimClient.ServiceContent.About.InstanceUuid, locator.InstanceUuid)
+	}
+
+	// A SHA-256 thumbprint is verified and converted to SHA-1.
+	c.Thumbprint = soap.ThumbprintSHA256(sim.Server.Certificate())
+	locator, err = virtualMachineServiceLocator(origin, []interface{}{})
+	if err != nil {
+		t.Fatalf("error building service locator: %s", err)
+	}
+	if locator.SslThumbprint != soap.ThumbprintSHA1(sim.Server.Certificate()) {
+		t.Fatalf("expected SHA-1 thumbprint, got %q", locator.SslThumbprint)
+	}
+
+	// Without a password, such as with SSO token authentication, the block
+	// cannot be removed.
+	c.Password = ""
+	if _, err := virtualMachineServiceLocator(origin, []interface{}{}); err == nil {
+		t.Fatal("expected error without provider credentials")
+	}
+}
@@ -9,9 +9,14 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/vmware/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
 )
 
 func TestMain(m *testing.M) {
+	if testhelper.SimulatorEnabled() {
+		resource.TestMain(&simulatorTestMain{m: m})
+		return
+	}
 	resource.TestMain(m)
 }
 